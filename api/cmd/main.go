@@ -2,27 +2,48 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"os"
+	"time"
 	"via/internal/auth"
+	biz_guide_assign "via/internal/biz/guide/assign"
+	biz_guide_expiry "via/internal/biz/guide/expiry"
+	biz_guide_reconcile "via/internal/biz/guide/reconcile"
+	"via/internal/cache"
+	"via/internal/cli"
 	ent_client "via/internal/client/ent"
 	"via/internal/config"
+	db_migrate "via/internal/db/migrate"
 	db_pool "via/internal/db/pool"
 	"via/internal/ds"
 	redis_ds "via/internal/ds/redis"
+	"via/internal/idp"
 	jwt_key "via/internal/jwt"
 	"via/internal/log"
 	app_log "via/internal/log/app"
+	"via/internal/presence"
+	audit_provider "via/internal/provider/audit"
+	audit_ent_provider "via/internal/provider/audit/ent"
+	device_provider "via/internal/provider/device"
+	device_ent_provider "via/internal/provider/device/ent"
 	guide_provider "via/internal/provider/guide"
 	guide_ent_provider "via/internal/provider/guide/ent"
 	operator_provider "via/internal/provider/operator"
 	operator_ent_provider "via/internal/provider/operator/ent"
 	via_guide_provider "via/internal/provider/via/guide"
+	via_guide_api_provider "via/internal/provider/via/guide/api"
+	via_guide_file_provider "via/internal/provider/via/guide/file"
+	via_guide_resilient_provider "via/internal/provider/via/guide/resilient"
 	via_guide_web_provider "via/internal/provider/via/guide/web"
 	"via/internal/pubsub"
 	redis_pubsub "via/internal/pubsub/redis"
+	"via/internal/response"
 	"via/internal/router"
+	"via/internal/scheduler"
 	"via/internal/secret"
 	"via/internal/server"
+	"via/internal/session"
 )
 
 func main() {
@@ -35,32 +56,118 @@ func main() {
 	logger.Debug(context.Background(), "config", cfg)
 
 	secret.Set(new(secret.FileSecretReader))
+	response.SetDocsURL(cfg.Application.ErrorDocsURL)
 
-	ds.Set(redis_ds.New(cfg.DS))
+	// admin commands, the API servers are started when no command or serve is given
+	if len(os.Args) > 1 && os.Args[1] != "serve" {
+		os.Exit(cli.Run(context.Background(), cli.Env{
+			Config:  cfg,
+			Stdin:   os.Stdin,
+			Stdout:  os.Stdout,
+			Stderr:  os.Stderr,
+			Connect: func(ctx context.Context) (func(), error) { return connect(cfg) },
+		}, os.Args[1:]))
+	}
+	serve(cfg)
+}
 
+// connect sets up the database, datastore and pubsub backed providers, the returned function closes the database
+func connect(cfg config.Config) (func(), error) {
 	pubsub.Set(redis_pubsub.New(cfg.PubSub))
+	ds.Set(redis_ds.New(cfg.DS))
+	session.Set(session.New(ds.Get(), cfg.OAuth.JWTRefreshExpirationInSeconds))
+
+	// Initialize the database connection pool
+	dbPool, err := db_pool.New(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	// Initialize the Ent client with the database connection pool
+	entClient := ent_client.New(dbPool)
 
-	auth.Set(auth.New(cfg.OAuth, ds.Get()))
+	guide_provider.Set(guide_ent_provider.New())
+	operator_provider.Set(operator_ent_provider.New())
+	device_provider.Set(device_ent_provider.New())
+	audit_provider.Set(audit_ent_provider.New())
+	return func() {
+		entClient.Close()
+		dbPool.Close()
+	}, nil
+}
+
+func serve(cfg config.Config) {
+	logger := log.Get()
 
 	// JWT key initialization
 	if err := jwt_key.Init(cfg.JWT); err != nil {
 		logger.Fatal(context.Background(), err, "msg", "Error in jwt key initialization")
 	}
 
-	// Initialize the database connection pool
-	dbPool, err := db_pool.New(cfg.Database)
+	// IdP signing keys initialization, the id tokens are verified offline with them. A provider whose
+	// issuer can not be discovered is disabled until the next start, the others keep working.
+	verifiers := idp.Verifiers{}
+	disabled := []string{}
+	for name, provider := range cfg.OAuth.GetProviders() {
+		verifier, err := idp.New(context.Background(), cfg.IDP, provider.Issuer, provider.ClientID)
+		if err != nil {
+			logger.Error(context.Background(), err, "msg", "Error in IdP verifier initialization, provider disabled",
+				"provider", name)
+			disabled = append(disabled, name)
+			continue
+		}
+		verifiers[name] = verifier
+	}
+	idp.Set(verifiers)
+
+	release, err := connect(cfg)
 	if err != nil {
 		logger.Fatal(context.Background(), err, "msg", "Error in db connection pool initialization")
 	}
-	// Initialize the Ent client with the database connection pool
-	entClient := ent_client.New(dbPool)
-	defer entClient.Close()
-	defer dbPool.Close()
+	defer release()
+
+	a := auth.New(cfg.OAuth, ds.Get())
+	for _, name := range disabled {
+		a.DisableProvider(name)
+	}
+	auth.Set(a)
+
+	if err := migrateDB(context.Background(), cfg.Migrate); err != nil {
+		logger.Fatal(context.Background(), err, "msg", "Error in database migration")
+	}
 
 	// Set up dependencies
-	via_guide_provider.Set(via_guide_web_provider.New(cfg.GuideWebClient, via_guide_web_provider.HistoricalQueryResponseParser{}))
-	guide_provider.Set(guide_ent_provider.New())
-	operator_provider.Set(operator_ent_provider.New())
+	via_guide_provider.Set(newViaGuideProvider(cfg))
+	presence.Set(presence.New(cfg.Presence))
+
+	// background jobs, a single instance runs each of them per interval
+	jobs := scheduler.New(ds.Get())
+	if cfg.Reconcile.Enabled {
+		jobs.Add(scheduler.Job{
+			Name:     "reconcile",
+			Interval: time.Duration(cfg.Reconcile.Interval) * time.Second,
+			Run:      biz_guide_reconcile.New(cfg.Reconcile, cfg.Bussiness).Run,
+		})
+	}
+	if cfg.Expiry.Enabled {
+		jobs.Add(scheduler.Job{
+			Name:     "expiry",
+			Interval: time.Duration(cfg.Expiry.Interval) * time.Second,
+			Run:      biz_guide_expiry.New(cfg.Expiry).Run,
+		})
+	}
+	// new guides are given to the available operators as they arrive, by a single instance
+	// so the operators cap holds
+	if cfg.Assign.Enabled {
+		jobs.Add(scheduler.Job{
+			Name:     "assign",
+			Interval: time.Duration(cfg.Assign.Lease) * time.Second,
+			Run:      biz_guide_assign.New(cfg.Assign).RunLocked,
+		})
+	}
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobs.Start(jobsCtx)
+	defer jobs.Wait()
+	defer stopJobs()
 
 	servers := []*http.Server{}
 	//start servers
@@ -72,3 +179,46 @@ func main() {
 	}
 	server.Start(servers)
 }
+
+// migrateDB applies, or only verifies, the embedded migrations and refuses a database
+// whose schema differs from the ent one
+func migrateDB(ctx context.Context, cfg db_migrate.MigrateCfg) error {
+	migrator, err := db_migrate.New(db_pool.Get())
+	if err != nil {
+		return err
+	}
+	if cfg.Auto {
+		_, err = migrator.Up(ctx)
+	} else {
+		err = migrator.Verify(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	if cfg.DriftCheck {
+		_, err = db_migrate.CheckDrift(ctx, ent_client.Get().Schema.Create)
+	}
+	return err
+}
+
+// newViaGuideProvider builds the via guide provider for the configured source
+func newViaGuideProvider(cfg config.Config) via_guide_provider.ViaGuideProvider {
+	switch cfg.Application.ViaGuideSource {
+	case via_guide_provider.SOURCE_FIXTURE:
+		provider, err := via_guide_file_provider.New(cfg.GuideFixture)
+		if err != nil {
+			log.Get().Fatal(context.Background(), err, "msg", "Error loading via guide fixtures")
+		}
+		return provider
+	case via_guide_provider.SOURCE_API:
+		return via_guide_resilient_provider.New(cfg.GuideProvider,
+			via_guide_api_provider.New(cfg.GuideAPIClient), cache.New(ds.Get()))
+	case via_guide_provider.SOURCE_WEB:
+		return via_guide_resilient_provider.New(cfg.GuideProvider,
+			via_guide_web_provider.New(cfg.GuideWebClient, via_guide_web_provider.HistoricalQueryResponseParser{}),
+			cache.New(ds.Get()))
+	}
+	log.Get().Fatal(context.Background(), fmt.Errorf("unknown via guide source %q", cfg.Application.ViaGuideSource),
+		"msg", "Error in via guide provider initialization")
+	return nil
+}
@@ -2,12 +2,14 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"sync"
 	"time"
 	"via/internal/ds"
 	"via/internal/model"
 	"via/internal/secret"
+	"via/internal/session"
 
 	"crypto/rand"
 	"crypto/sha256"
@@ -17,7 +19,6 @@ import (
 	jwt_key "via/internal/jwt"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
 	"golang.org/x/oauth2"
 )
 
@@ -30,16 +31,31 @@ var (
 )
 
 type Auth struct {
-	OAuth2Config OAuth2Cfg
+	Providers    map[string]*Provider
 	cache        *cache.Cache // Use a suitable cache implementation, e.g., Redis
+	ds           ds.DS
+	refreshTTL   int // in seconds
+	refreshGrace int // in seconds
 }
 
 type Claims struct {
 	OperatorID int    `json:"operatorId"`
+	Role       string `json:"role"`
 	IDPIDToken string `json:"idpIdToken"`
+	SessionID  string `json:"sid"`
+	Provider   string `json:"idp,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// GetProvider returns the provider the operator logged in with, the tokens signed before there
+// were several providers are from the default one
+func (c Claims) GetProvider() string {
+	if c.Provider == "" {
+		return DefaultProvider
+	}
+	return c.Provider
+}
+
 func Get() *Auth {
 	mutex.RLock()
 	defer mutex.RUnlock()
@@ -53,11 +69,29 @@ func Set(auth *Auth) {
 }
 
 func New(cfg OAuthConfig, ds ds.DS) *Auth {
+	providers := map[string]*Provider{}
+	for name, provider := range cfg.GetProviders() {
+		providers[name] = newProvider(name, provider)
+	}
+	return &Auth{
+		Providers:    providers,
+		cache:        cache.New(ds),
+		ds:           ds,
+		refreshTTL:   cfg.JWTRefreshExpirationInSeconds,
+		refreshGrace: cfg.JWTRefreshGraceInSeconds,
+	}
+}
+
+func newProvider(name string, cfg ProviderConfig) *Provider {
 	if cfg.ClientSecret == "" {
 		cfg.ClientSecret = secret.Get().Read(cfg.ClientSecretFile)
 	}
-	return &Auth{
-		cache: cache.New(ds),
+	return &Provider{
+		Name:           name,
+		Issuer:         cfg.Issuer,
+		UserInfoURL:    cfg.UserInfoURL,
+		RevokeTokenURL: cfg.RevokeTokenURL,
+		TrustEmail:     cfg.TrustEmail,
 		OAuth2Config: &oauth2.Config{
 			ClientID:     cfg.ClientID,
 			ClientSecret: cfg.ClientSecret,
@@ -71,10 +105,25 @@ func New(cfg OAuthConfig, ds ds.DS) *Auth {
 	}
 }
 
+// DisableProvider removes the identity provider, its logins are refused and the login page no longer offers it
+func (a *Auth) DisableProvider(name string) {
+	delete(a.Providers, name)
+}
+
+// GetProvider returns the named identity provider, the default one when the name is empty
+func (a *Auth) GetProvider(name string) (*Provider, bool) {
+	if name == "" {
+		name = DefaultProvider
+	}
+	provider, ok := a.Providers[name]
+	return provider, ok
+}
+
 // AuthState holds the state for OAuth2 authentication, including the code verifier and redirect URI.
 type AuthState struct {
 	CodeVerifier string
 	RedirectURI  string
+	Provider     string
 }
 
 type OAuthConfig struct {
@@ -94,7 +143,14 @@ type OAuthConfig struct {
 	JWTClaimsAudience             string   `env:"JWT_CLAIMS_AUDIENCE" json:"jwtClaimsAudience"`
 	JWTExpirationInSeconds        int      `env:"JWT_EXPIRATION_IN_SECONDS" json:"jwtExpirationInSeconds"`
 	JWTRefreshExpirationInSeconds int      `env:"JWT_REFRESH_EXPIRATION_IN_SECONDS" json:"jwtRefreshExpirationInSeconds"`
-	IDPIssuer                     string   `env:"IDP_ISSUER" json:"idpIssuer"`
+	// a refresh token used again within this window after its rotation gets the same new token
+	JWTRefreshGraceInSeconds int    `env:"JWT_REFRESH_GRACE_IN_SECONDS" envDefault:"10" json:"jwtRefreshGraceInSeconds"`
+	IDPIssuer                string `env:"IDP_ISSUER" json:"idpIssuer"`
+	TrustEmail               bool   `env:"TRUST_EMAIL" json:"trustEmail"` // see ProviderConfig
+	// named identity providers besides the default one above, each configured with OAUTH_<NAME>_ variables
+	Providers       []string                  `env:"PROVIDERS" envSeparator:"," json:"providers"`
+	ProviderConfigs map[string]ProviderConfig `json:"providerConfigs"`
+	LocalLogin      bool                      `env:"LOCAL_LOGIN" json:"localLogin"` // emergency login with password and TOTP code
 }
 
 var randReadPKCE = rand.Read
@@ -127,7 +183,7 @@ func generateState() (string, error) {
 	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
-func (a *Auth) GenerateState(ctx context.Context, redirectURI string) (string, string, string, error) {
+func (a *Auth) GenerateState(ctx context.Context, provider string, redirectURI string) (string, string, string, error) {
 	state, err := generateState()
 	if err != nil {
 		return "", "", "", err
@@ -138,7 +194,8 @@ func (a *Auth) GenerateState(ctx context.Context, redirectURI string) (string, s
 	}
 	authState := AuthState{
 		CodeVerifier: codeVerifier,
-		RedirectURI:  redirectURI}
+		RedirectURI:  redirectURI,
+		Provider:     provider}
 
 	err = a.cache.Set(ctx, state, authState, cacheTTL)
 	if err != nil {
@@ -173,13 +230,13 @@ func GetAuthToken(r *http.Request) (token string, err error) {
 	return
 }
 
-func writeAuthCookie(w http.ResponseWriter, token string, cfg OAuthConfig, expires time.Time) {
+func writeAuthCookie(w http.ResponseWriter, name string, token string, cfg OAuthConfig, expires time.Time) {
 	sameSite := http.SameSiteLaxMode
 	if cfg.CookieSameSiteNone {
 		sameSite = http.SameSiteNoneMode
 	}
 	http.SetCookie(w, &http.Cookie{
-		Name:     AuthTokenKey,
+		Name:     name,
 		Value:    token,
 		Expires:  expires,
 		HttpOnly: true,
@@ -191,34 +248,56 @@ func writeAuthCookie(w http.ResponseWriter, token string, cfg OAuthConfig, expir
 }
 
 func SetAuthToken(w http.ResponseWriter, token string, cfg OAuthConfig) {
-	writeAuthCookie(w, token, cfg, time.Now().Add(time.Duration(cfg.JWTExpirationInSeconds)*time.Second))
+	writeAuthCookie(w, AuthTokenKey, token, cfg, time.Now().Add(time.Duration(cfg.JWTExpirationInSeconds)*time.Second))
 }
 
 func DelAuthToken(w http.ResponseWriter, cfg OAuthConfig) {
-	writeAuthCookie(w, "", cfg, time.Now().Add(-time.Hour))
+	writeAuthCookie(w, AuthTokenKey, "", cfg, time.Now().Add(-time.Hour))
 }
 
+// ParseTokenWithClaims verifies the token with the key of its kid header, a token signed before
+// the keys had ids is verified with the signing key. Only the algorithms of the keys are accepted.
 func ParseTokenWithClaims(token string) (claims Claims, tkn *jwt.Token, err error) {
+	methods := []string{}
+	for _, key := range jwt_key.GetKeys() {
+		methods = append(methods, key.Method.Alg())
+	}
 	tkn, err = jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (any, error) {
-		return jwt_key.GetPublicKey(), nil
-	})
+		id, _ := token.Header["kid"].(string)
+		if id == "" {
+			return jwt_key.GetPublicKey(), nil
+		}
+		key, ok := jwt_key.GetKey(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %s", id)
+		}
+		if key.Method.Alg() != token.Method.Alg() {
+			return nil, fmt.Errorf("signing key %s does not use %s", id, token.Method.Alg())
+		}
+		return key.Public, nil
+	}, jwt.WithValidMethods(methods))
 	return
 }
 
-func GenerateAuthToken(operator model.Operator, idToken string, cfg OAuthConfig) (string, error) {
+// GenerateAuthToken signs the access token of the session, its jti is the session one
+func GenerateAuthToken(operator model.Operator, s session.Session, cfg OAuthConfig) (string, error) {
 	now := time.Now()
 	claims := Claims{
 		OperatorID: operator.ID,
-		IDPIDToken: idToken,
+		Role:       operator.Role,
+		IDPIDToken: s.IDPIDToken,
+		SessionID:  s.ID,
+		Provider:   s.Provider,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    cfg.JWTClaimsIssuer,
 			Audience:  jwt.ClaimStrings{cfg.JWTClaimsAudience},
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(cfg.JWTExpirationInSeconds) * time.Second)),
-			ID:        uuid.New().String(),
+			ID:        s.JTI,
 			Subject:   operator.Account,
 		},
 	}
 	tokenJWT := jwt.NewWithClaims(jwt_key.GetSigningMethod(), claims)
+	tokenJWT.Header["kid"] = jwt_key.GetKeyID()
 	return tokenJWT.SignedString(jwt_key.GetPrivateKey())
 }
@@ -4,9 +4,15 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 	"via/internal/cache"
 	mock_ds "via/internal/ds/mock"
+	jwt_key "via/internal/jwt"
+	"via/internal/model"
+	"via/internal/session"
+	"via/internal/testutil"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/oauth2"
@@ -45,7 +51,57 @@ func TestNew(t *testing.T) {
 	a := New(cfg, mockDS)
 
 	assert.NotNil(t, a)
-	assert.Equal(t, expected, a.OAuth2Config)
+	provider, ok := a.GetProvider("")
+	assert.True(t, ok)
+	assert.Equal(t, DefaultProvider, provider.Name)
+	assert.Equal(t, expected, provider.OAuth2Config)
+}
+
+func TestNew_Providers(t *testing.T) {
+	entra := ProviderConfig{ClientID: "entra", ClientSecret: "sec", Issuer: "https://login.microsoftonline.com/t/v2.0",
+		UserInfoURL: "https://graph.microsoft.com/oidc/userinfo", RevokeTokenURL: "https://revoke.entra", TrustEmail: true}
+	tests := []struct {
+		name     string
+		cfg      OAuthConfig
+		expected []string
+	}{
+		{name: "default provider only", cfg: OAuthConfig{ClientID: "google", ClientSecret: "sec"},
+			expected: []string{DefaultProvider}},
+		{name: "named providers only", cfg: OAuthConfig{ProviderConfigs: map[string]ProviderConfig{"entra": entra}},
+			expected: []string{"entra"}},
+		{name: "default and named providers", cfg: OAuthConfig{ClientID: "google", ClientSecret: "sec",
+			ProviderConfigs: map[string]ProviderConfig{"entra": entra}}, expected: []string{DefaultProvider, "entra"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(tt.cfg, new(mock_ds.MockDS))
+			names := []string{}
+			for name := range a.Providers {
+				names = append(names, name)
+			}
+			assert.ElementsMatch(t, tt.expected, names)
+		})
+	}
+
+	a := New(OAuthConfig{ProviderConfigs: map[string]ProviderConfig{"entra": entra}}, new(mock_ds.MockDS))
+	provider, ok := a.GetProvider("entra")
+	assert.True(t, ok)
+	assert.Equal(t, &Provider{Name: "entra", Issuer: entra.Issuer, UserInfoURL: entra.UserInfoURL,
+		RevokeTokenURL: entra.RevokeTokenURL, TrustEmail: true,
+		OAuth2Config: &oauth2.Config{ClientID: "entra", ClientSecret: "sec"}}, provider)
+	_, ok = a.GetProvider("")
+	assert.False(t, ok, "the default provider is not configured")
+
+	a.DisableProvider("entra")
+	_, ok = a.GetProvider("entra")
+	assert.False(t, ok, "the disabled provider is not offered")
+	provider, _ = New(OAuthConfig{ClientID: "google", ClientSecret: "sec", TrustEmail: true}, new(mock_ds.MockDS)).GetProvider("")
+	assert.True(t, provider.TrustEmail)
+}
+
+func TestClaimsGetProvider(t *testing.T) {
+	assert.Equal(t, DefaultProvider, Claims{}.GetProvider())
+	assert.Equal(t, LocalProvider, Claims{Provider: LocalProvider}.GetProvider())
 }
 
 func TestGenerateStateAndGetState(t *testing.T) {
@@ -56,7 +112,7 @@ func TestGenerateStateAndGetState(t *testing.T) {
 	// Mock Set (simulate OK)
 	mockDS.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	state, codeChallenge, method, err := a.GenerateState(ctx, "http://redirect")
+	state, codeChallenge, method, err := a.GenerateState(ctx, "entra", "http://redirect")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, state)
 	assert.NotEmpty(t, codeChallenge)
@@ -66,13 +122,14 @@ func TestGenerateStateAndGetState(t *testing.T) {
 
 	// Simulate fetching state successfully
 	mockDS.On("Get", mock.Anything, state).
-		Return(true, `{"CodeVerifier":"abc","RedirectURI":"http://redirect"}`, nil)
+		Return(true, `{"CodeVerifier":"abc","RedirectURI":"http://redirect","Provider":"entra"}`, nil)
 
 	authState, found, err := a.GetState(ctx, state)
 	assert.NoError(t, err)
 	assert.True(t, found)
 	assert.Equal(t, "abc", authState.CodeVerifier)
 	assert.Equal(t, "http://redirect", authState.RedirectURI)
+	assert.Equal(t, "entra", authState.Provider)
 
 	// Simulate cache miss
 	mockDS.On("Get", mock.Anything, "missing").Return(false, "", nil)
@@ -95,7 +152,7 @@ func TestGenerateStateErrors(t *testing.T) {
 	mockDS.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(errors.New("cache error"))
 
-	_, _, _, err := a.GenerateState(ctx, "http://redirect")
+	_, _, _, err := a.GenerateState(ctx, DefaultProvider, "http://redirect")
 	assert.Error(t, err)
 }
 
@@ -116,7 +173,7 @@ func TestGenerateState_Errors(t *testing.T) {
 	randReadState = func(b []byte) (int, error) {
 		return 0, errors.New("state generation generateState() failed")
 	}
-	_, _, _, err := a.GenerateState(ctx, "https://example.com/callback")
+	_, _, _, err := a.GenerateState(ctx, DefaultProvider, "https://example.com/callback")
 	assert.EqualError(t, err, "state generation generateState() failed")
 
 	randReadState = originalRandRead
@@ -126,8 +183,61 @@ func TestGenerateState_Errors(t *testing.T) {
 	randReadPKCE = func(b []byte) (int, error) {
 		return 0, errors.New("state generation generatePKCE() failed")
 	}
-	_, _, _, err = a.GenerateState(ctx, "https://example.com/callback")
+	_, _, _, err = a.GenerateState(ctx, DefaultProvider, "https://example.com/callback")
 	assert.EqualError(t, err, "state generation generatePKCE() failed")
 
 	randReadPKCE = originalRandRead
 }
+
+func TestParseTokenWithClaims(t *testing.T) {
+	testutil.InjectMockJWTKey()
+	t.Cleanup(jwt_key.Reset)
+	operator := model.Operator{ID: 5, Role: "supervisor"}
+	s := session.Session{Session: model.Session{ID: "s1"}, JTI: "j1"}
+	cfg := OAuthConfig{JWTExpirationInSeconds: 60}
+	signed, err := GenerateAuthToken(operator, s, cfg)
+	assert.NoError(t, err)
+
+	withHeader := func(kid any, method jwt.SigningMethod, key any) string {
+		token := jwt.NewWithClaims(method, Claims{OperatorID: 5, RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}})
+		if kid != nil {
+			token.Header["kid"] = kid
+		}
+		signedString, err := token.SignedString(key)
+		assert.NoError(t, err)
+		return signedString
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		expectErr bool
+	}{
+		{name: "signed with the current key", token: signed},
+		{name: "signed before the keys had ids", token: withHeader(nil, jwt_key.GetSigningMethod(), jwt_key.GetPrivateKey())},
+		{name: "unknown key", token: withHeader("retired", jwt_key.GetSigningMethod(), jwt_key.GetPrivateKey()), expectErr: true},
+		{name: "algorithm of another key", token: withHeader(jwt_key.GetKeyID(), jwt.SigningMethodRS512, jwt_key.GetPrivateKey()),
+			expectErr: true},
+		{name: "signed before the keys had ids with another algorithm",
+			token: withHeader(nil, jwt.SigningMethodRS512, jwt_key.GetPrivateKey()), expectErr: true},
+		{name: "symmetric algorithm", token: withHeader(nil, jwt.SigningMethodHS256, []byte("public key")), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, _, err := ParseTokenWithClaims(tt.token)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 5, claims.OperatorID)
+		})
+	}
+
+	token, _, _ := jwt.NewParser().ParseUnverified(signed, &Claims{})
+	assert.Equal(t, jwt_key.GetKeyID(), token.Header["kid"])
+}
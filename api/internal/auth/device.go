@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"net/http"
+	"time"
+)
+
+const DeviceTokenKey string = "device_token"
+
+// GetDeviceToken returns the token the kiosk or monitor got when paired, kept in a cookie so the
+// event streams send it too
+func GetDeviceToken(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(DeviceTokenKey)
+	if err != nil {
+		return "", err
+	}
+	if err := cookie.Valid(); err != nil {
+		return "", err
+	}
+	return cookie.Value, nil
+}
+
+func SetDeviceToken(w http.ResponseWriter, token string, cfg OAuthConfig, maxAgeSeconds int) {
+	writeAuthCookie(w, DeviceTokenKey, token, cfg, time.Now().Add(time.Duration(maxAgeSeconds)*time.Second))
+}
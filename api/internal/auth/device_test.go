@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceTokenCookie(t *testing.T) {
+	cfg := OAuthConfig{CookieSecure: true, CookieDomain: "via.test"}
+
+	w := httptest.NewRecorder()
+	SetDeviceToken(w, "7.secret", cfg, 86400)
+	cookie := w.Result().Cookies()[0]
+	assert.Equal(t, DeviceTokenKey, cookie.Name)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, "via.test", cookie.Domain)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), cookie.Expires, time.Minute)
+
+	req := httptest.NewRequest(http.MethodGet, "/monitor/events", nil)
+	req.AddCookie(cookie)
+	token, err := GetDeviceToken(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "7.secret", token)
+
+	_, err = GetDeviceToken(httptest.NewRequest(http.MethodGet, "/monitor/events", nil))
+	assert.Error(t, err)
+
+	req = httptest.NewRequest(http.MethodGet, "/monitor/events", nil)
+	req.Header.Set("Cookie", DeviceTokenKey+"=bad\"value")
+	_, err = GetDeviceToken(req)
+	assert.Error(t, err)
+}
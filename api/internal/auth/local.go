@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"via/internal/log"
+	"via/internal/model"
+	operator_provider "via/internal/provider/operator"
+	"via/internal/totp"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrLocalLoginInvalid is returned for a wrong account, password or code alike, the reason is only logged
+var ErrLocalLoginInvalid = errors.New("local login invalid")
+
+// compared when the account has no password, a missing account takes as long to reject as a wrong password
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+var now = time.Now
+
+// HashPassword returns the bcrypt hash of the local login password
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hashing password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func totpUsedKey(operatorId int, step uint64) string {
+	return "totp:used:" + strconv.Itoa(operatorId) + ":" + strconv.FormatUint(step, 10)
+}
+
+// LocalLogin checks the password and the TOTP code of the operator, for when the IdPs are down.
+// A code is accepted once, a code seen over the shoulder can not be replayed.
+func (a *Auth) LocalLogin(ctx context.Context, account, password, code string) (model.Operator, error) {
+	operator, credentials, err := operator_provider.Get().GetOperatorCredentials(ctx, account)
+	if err != nil {
+		return model.Operator{}, err
+	}
+	hash := []byte(credentials.PasswordHash)
+	if credentials.PasswordHash == "" {
+		hash = dummyHash
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil || credentials.PasswordHash == "" {
+		log.Get().Warn(ctx, "msg", "local login password rejected", "account", account)
+		return model.Operator{}, ErrLocalLoginInvalid
+	}
+	step, ok := totp.Validate(credentials.TOTPSecret, code, now())
+	if !ok {
+		log.Get().Warn(ctx, "msg", "local login code rejected", "account", account)
+		return model.Operator{}, ErrLocalLoginInvalid
+	}
+	first, err := a.ds.SetNX(ctx, totpUsedKey(operator.ID, step), account, totp.Window)
+	if err != nil {
+		return model.Operator{}, fmt.Errorf("marking code used: %w", err)
+	}
+	if !first {
+		log.Get().Warn(ctx, "msg", "local login code reused", "account", account)
+		return model.Operator{}, ErrLocalLoginInvalid
+	}
+	if !operator.Enabled {
+		log.Get().Warn(ctx, "msg", "local login of a disabled operator", "account", account)
+		return model.Operator{}, ErrLocalLoginInvalid
+	}
+	return operator, nil
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"via/internal/model"
+	operator_provider "via/internal/provider/operator"
+	mock_operator_provider "via/internal/provider/operator/mock"
+	"via/internal/testutil"
+	"via/internal/totp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse battery staple")
+	assert.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("correct horse battery staple")))
+
+	_, err = HashPassword(string(make([]byte, 100)))
+	assert.ErrorContains(t, err, "hashing password")
+}
+
+func TestLocalLogin(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	clock := time.Unix(1111111109, 0)
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now })
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret password"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+	code, err := totp.Code(secret, totp.Step(clock))
+	assert.NoError(t, err)
+	credentials := model.OperatorCredentials{PasswordHash: string(hash), TOTPSecret: secret}
+	operator := model.Operator{ID: 4, Account: "ana@example.com", Enabled: true}
+	usedKey := totpUsedKey(4, totp.Step(clock))
+
+	tests := []struct {
+		name        string
+		password    string
+		code        string
+		operator    model.Operator
+		credentials model.OperatorCredentials
+		providerErr error
+		markCode    bool
+		firstUse    bool
+		markErr     error
+		expectErr   error
+	}{
+		{name: "success", password: "secret password", code: code, operator: operator, credentials: credentials,
+			markCode: true, firstUse: true},
+		{name: "provider error", providerErr: errors.New("db error"), expectErr: errors.New("db error")},
+		{name: "unknown account", password: "secret password", code: code, expectErr: ErrLocalLoginInvalid},
+		{name: "local login not enabled for the operator", password: "dummy password", code: code, operator: operator,
+			expectErr: ErrLocalLoginInvalid},
+		{name: "wrong password", password: "guess", code: code, operator: operator, credentials: credentials,
+			expectErr: ErrLocalLoginInvalid},
+		{name: "wrong code", password: "secret password", code: "000000", operator: operator, credentials: credentials,
+			expectErr: ErrLocalLoginInvalid},
+		{name: "code reused", password: "secret password", code: code, operator: operator, credentials: credentials,
+			markCode: true, expectErr: ErrLocalLoginInvalid},
+		{name: "error marking the code used", password: "secret password", code: code, operator: operator,
+			credentials: credentials, markCode: true, markErr: errors.New("ds error"),
+			expectErr: errors.New("marking code used: ds error")},
+		{name: "disabled operator", password: "secret password", code: code,
+			operator: model.Operator{ID: 4, Account: "ana@example.com"}, credentials: credentials, markCode: true,
+			firstUse: true, expectErr: ErrLocalLoginInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, mockDS := newRefreshAuth()
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockOperator.On("GetOperatorCredentials", mock.Anything, "ana@example.com").
+				Return(tt.operator, tt.credentials, tt.providerErr).Once()
+			if tt.markCode {
+				mockDS.On("SetNX", mock.Anything, usedKey, "ana@example.com", totp.Window).Return(tt.firstUse, tt.markErr).Once()
+			}
+
+			result, err := a.LocalLogin(context.Background(), "ana@example.com", tt.password, tt.code)
+
+			if tt.expectErr != nil {
+				assert.EqualError(t, err, tt.expectErr.Error())
+				assert.Empty(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, operator, result)
+			}
+			mockDS.AssertExpectations(t)
+			mockOperator.AssertExpectations(t)
+		})
+	}
+}
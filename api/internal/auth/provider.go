@@ -0,0 +1,59 @@
+package auth
+
+// DefaultProvider is the identity provider configured with the OAUTH_ variables
+const DefaultProvider = "default"
+
+// LocalProvider marks the sessions of the emergency local login, no IdP token backs them
+const LocalProvider = "local"
+
+// ProviderConfig is an OAuth2/OIDC identity provider the operators log in with
+type ProviderConfig struct {
+	ClientID         string   `env:"CLIENT_ID" json:"-"`
+	ClientSecret     string   `env:"CLIENT_SECRET" json:"-"`
+	ClientSecretFile string   `env:"CLIENT_SECRET_FILE" json:"clientSecretFile"`
+	RedirectURL      string   `env:"REDIRECT_URL" json:"redirectUrl"`
+	Scopes           []string `env:"SCOPES" json:"scopes"`
+	AuthURL          string   `env:"AUTH_URL" json:"authUrl"`
+	TokenURL         string   `env:"TOKEN_URL" json:"tokenUrl"`
+	UserInfoURL      string   `env:"USER_INFO_URL" json:"userInfoUrl"`
+	RevokeTokenURL   string   `env:"REVOKE_TOKEN_URL" json:"revokeTokenUrl"`
+	Issuer           string   `env:"ISSUER" json:"issuer"`
+	// the verified email of the user info links an account to the operator on its first login,
+	// otherwise an admin links it with via operator link
+	TrustEmail bool `env:"TRUST_EMAIL" json:"trustEmail"`
+}
+
+// Provider is a configured identity provider
+type Provider struct {
+	Name           string
+	Issuer         string
+	UserInfoURL    string
+	RevokeTokenURL string
+	TrustEmail     bool
+	OAuth2Config   OAuth2Cfg
+}
+
+// GetProviders returns the identity providers by name. The default one is left out when only
+// named providers are configured.
+func (cfg OAuthConfig) GetProviders() map[string]ProviderConfig {
+	providers := map[string]ProviderConfig{}
+	if cfg.ClientID != "" || len(cfg.ProviderConfigs) == 0 {
+		providers[DefaultProvider] = ProviderConfig{
+			ClientID:         cfg.ClientID,
+			ClientSecret:     cfg.ClientSecret,
+			ClientSecretFile: cfg.ClientSecretFile,
+			RedirectURL:      cfg.RedirectURL,
+			Scopes:           cfg.Scopes,
+			AuthURL:          cfg.AuthURL,
+			TokenURL:         cfg.TokenURL,
+			UserInfoURL:      cfg.UserInfoURL,
+			RevokeTokenURL:   cfg.RevokeTokenURL,
+			Issuer:           cfg.IDPIssuer,
+			TrustEmail:       cfg.TrustEmail,
+		}
+	}
+	for name, provider := range cfg.ProviderConfigs {
+		providers[name] = provider
+	}
+	return providers
+}
@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+	"via/internal/model"
+	operator_provider "via/internal/provider/operator"
+	"via/internal/session"
+
+	"github.com/google/uuid"
+)
+
+const RefreshTokenKey string = "refresh_token"
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token invalid")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+)
+
+// the token is stored hashed, a leaked datastore does not leak usable tokens
+func refreshTokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "refresh:token:" + hex.EncodeToString(sum[:])
+}
+
+func refreshUsedKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "refresh:used:" + hex.EncodeToString(sum[:])
+}
+
+// the token the rotation gave, kept in clear for the grace window only
+func refreshSuccessorKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "refresh:successor:" + hex.EncodeToString(sum[:])
+}
+
+var randReadRefresh = rand.Read
+
+// StartSession opens a session for the operator login with the provider, returning its access and refresh tokens
+func (a *Auth) StartSession(ctx context.Context, r *http.Request, operator model.Operator, provider string, idToken string,
+	cfg OAuthConfig) (string, string, error) {
+	now := time.Now()
+	s := session.Session{
+		Session: model.Session{
+			ID:         uuid.New().String(),
+			OperatorID: operator.ID,
+			CreatedAt:  now,
+			RenewedAt:  now,
+			UserAgent:  r.UserAgent(),
+			IP:         clientIP(r),
+			Provider:   provider,
+		},
+		JTI:        uuid.New().String(),
+		IDPIDToken: idToken,
+	}
+	accessToken, err := GenerateAuthToken(operator, s, cfg)
+	if err != nil {
+		return "", "", fmt.Errorf("signing access token: %w", err)
+	}
+	if err := session.Get().Save(ctx, s); err != nil {
+		return "", "", err
+	}
+	refreshToken, err := a.IssueRefreshToken(ctx, s.ID)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := randReadRefresh(b); err != nil {
+		return "", fmt.Errorf("generating refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// IssueRefreshToken creates a new refresh token for the session
+func (a *Auth) IssueRefreshToken(ctx context.Context, sessionId string) (string, error) {
+	token, err := newRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	if err := a.ds.Set(ctx, refreshTokenKey(token), sessionId, a.refreshTTL); err != nil {
+		return "", fmt.Errorf("storing refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// RotateRefreshToken exchanges the refresh token for a new one of the same session. The requests
+// racing with the first use, two tabs renewing at once, get the same new token within the grace
+// window. A token used after it was stolen or leaked, so the session is revoked and every holder
+// must log in again.
+func (a *Auth) RotateRefreshToken(ctx context.Context, token string) (session.Session, string, error) {
+	found, sessionId, err := a.ds.Get(ctx, refreshTokenKey(token))
+	if err != nil {
+		return session.Session{}, "", fmt.Errorf("getting refresh token: %w", err)
+	}
+	if !found {
+		return session.Session{}, "", ErrRefreshTokenInvalid
+	}
+	successor, first, err := a.claimRefreshToken(ctx, token, sessionId)
+	if err != nil {
+		return session.Session{}, "", err
+	}
+	if successor == "" {
+		if err := session.Get().Revoke(ctx, sessionId); err != nil {
+			return session.Session{}, "", err
+		}
+		return session.Session{}, "", ErrRefreshTokenReused
+	}
+	s, active, err := session.Get().Get(ctx, sessionId)
+	if err != nil {
+		return s, "", err
+	}
+	if !active {
+		return s, "", ErrRefreshTokenInvalid
+	}
+	if first {
+		if err := a.ds.Set(ctx, refreshTokenKey(successor), sessionId, a.refreshTTL); err != nil {
+			return s, "", fmt.Errorf("storing refresh token: %w", err)
+		}
+	}
+	return s, successor, nil
+}
+
+// claimRefreshToken marks the token used and returns the token replacing it, reporting whether this
+// is the first use. The uses within the grace window get the same replacement, the later ones none.
+func (a *Auth) claimRefreshToken(ctx context.Context, token string, sessionId string) (string, bool, error) {
+	successor, err := newRefreshToken()
+	if err != nil {
+		return "", false, err
+	}
+	if a.refreshGrace > 0 {
+		claimed, err := a.ds.SetNX(ctx, refreshSuccessorKey(token), successor, a.refreshGrace)
+		if err != nil {
+			return "", false, fmt.Errorf("marking refresh token used: %w", err)
+		}
+		if !claimed {
+			_, successor, err := a.ds.Get(ctx, refreshSuccessorKey(token))
+			if err != nil {
+				return "", false, fmt.Errorf("getting rotated refresh token: %w", err)
+			}
+			return successor, false, nil
+		}
+	}
+	first, err := a.ds.SetNX(ctx, refreshUsedKey(token), sessionId, a.refreshTTL)
+	if err != nil {
+		return "", false, fmt.Errorf("marking refresh token used: %w", err)
+	}
+	if !first {
+		return "", false, nil
+	}
+	return successor, true, nil
+}
+
+// RevokeRefreshToken ends the session the refresh token belongs to
+func (a *Auth) RevokeRefreshToken(ctx context.Context, token string) error {
+	found, sessionId, err := a.ds.Get(ctx, refreshTokenKey(token))
+	if err != nil {
+		return fmt.Errorf("getting refresh token: %w", err)
+	}
+	if !found {
+		return nil
+	}
+	return session.Get().Revoke(ctx, sessionId)
+}
+
+// Renew rotates the refresh token and signs a new access token for the operator, both are written
+// as cookies and the previous access token is rejected from then on. A disabled or removed operator
+// ends the session.
+func (a *Auth) Renew(ctx context.Context, w http.ResponseWriter, refreshToken string, cfg OAuthConfig) (string, error) {
+	s, newRefreshToken, err := a.RotateRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return "", err
+	}
+	operator, err := operator_provider.Get().GetOperatorById(ctx, s.OperatorID)
+	if err != nil {
+		return "", fmt.Errorf("getting operator %d: %w", s.OperatorID, err)
+	}
+	if operator.ID == 0 || !operator.Enabled {
+		if err := session.Get().Revoke(ctx, s.ID); err != nil {
+			return "", err
+		}
+		return "", ErrRefreshTokenInvalid
+	}
+	s.JTI = uuid.New().String()
+	s.RenewedAt = time.Now()
+	accessToken, err := GenerateAuthToken(operator, s, cfg)
+	if err != nil {
+		return "", fmt.Errorf("signing access token: %w", err)
+	}
+	if err := session.Get().Save(ctx, s); err != nil {
+		return "", err
+	}
+	SetAuthToken(w, accessToken, cfg)
+	SetRefreshToken(w, newRefreshToken, cfg)
+	return accessToken, nil
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func GetRefreshToken(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(RefreshTokenKey)
+	if err != nil {
+		return "", err
+	}
+	if err := cookie.Valid(); err != nil {
+		return "", err
+	}
+	return cookie.Value, nil
+}
+
+func SetRefreshToken(w http.ResponseWriter, token string, cfg OAuthConfig) {
+	writeAuthCookie(w, RefreshTokenKey, token, cfg, time.Now().Add(time.Duration(cfg.JWTRefreshExpirationInSeconds)*time.Second))
+}
+
+func DelRefreshToken(w http.ResponseWriter, cfg OAuthConfig) {
+	writeAuthCookie(w, RefreshTokenKey, "", cfg, time.Now().Add(-time.Hour))
+}
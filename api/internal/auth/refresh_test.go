@@ -0,0 +1,480 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	mock_ds "via/internal/ds/mock"
+	jwt_key "via/internal/jwt"
+	"via/internal/model"
+	operator_provider "via/internal/provider/operator"
+	mock_operator_provider "via/internal/provider/operator/mock"
+	"via/internal/session"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRefreshAuth() (*Auth, *mock_ds.MockDS) {
+	mockDS := new(mock_ds.MockDS)
+	session.Set(session.New(mockDS, 7200))
+	return New(OAuthConfig{ClientSecret: "sec", JWTRefreshExpirationInSeconds: 7200}, mockDS), mockDS
+}
+
+var storedSession = session.Session{
+	Session:    model.Session{ID: "s1", OperatorID: 1, CreatedAt: time.Unix(100, 0).UTC(), RenewedAt: time.Unix(100, 0).UTC()},
+	JTI:        "j1",
+	IDPIDToken: "idp-token",
+}
+
+func sessionJSON(t *testing.T, s session.Session) string {
+	bytes, err := json.Marshal(s)
+	assert.NoError(t, err)
+	return string(bytes)
+}
+
+func TestStartSession(t *testing.T) {
+	testutil.InjectMockJWTKey()
+	t.Cleanup(jwt_key.Reset)
+	cfg := OAuthConfig{JWTExpirationInSeconds: 3600, JWTRefreshExpirationInSeconds: 7200}
+	operator := model.Operator{ID: 1, Role: "supervisor", Enabled: true}
+
+	tests := []struct {
+		name       string
+		saveErr    error
+		refreshErr error
+		expectErr  bool
+	}{
+		{name: "success"},
+		{name: "error saving session", saveErr: errors.New("ds error"), expectErr: true},
+		{name: "error issuing refresh token", refreshErr: errors.New("ds error"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, mockDS := newRefreshAuth()
+			var saved session.Session
+			mockDS.On("Set", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "session:") }),
+				mock.Anything, 7200).Run(func(args mock.Arguments) {
+				assert.NoError(t, json.Unmarshal([]byte(args.String(2)), &saved))
+			}).Return(tt.saveErr).Once()
+			mockDS.On("SAdd", mock.Anything, "sessions:operator:1:ids", mock.Anything, 7200).Return(nil).Once()
+			mockDS.On("Set", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "refresh:") }),
+				mock.Anything, 7200).Return(tt.refreshErr).Once()
+			req := httptest.NewRequest(http.MethodGet, "/auth/callback", nil)
+			req.RemoteAddr = "10.0.0.1:5000"
+			req.Header.Set("User-Agent", "firefox")
+
+			accessToken, refreshToken, err := a.StartSession(context.Background(), req, operator, "google", "idp-token", cfg)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, refreshToken)
+			claims, _, err := ParseTokenWithClaims(accessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, saved.ID, claims.SessionID)
+			assert.Equal(t, saved.JTI, claims.ID)
+			assert.Equal(t, "idp-token", saved.IDPIDToken)
+			assert.Equal(t, "google", saved.Provider)
+			assert.Equal(t, "google", claims.GetProvider())
+			assert.Equal(t, "10.0.0.1", saved.IP)
+			assert.Equal(t, "firefox", saved.UserAgent)
+			mockDS.AssertCalled(t, "Set", mock.Anything, refreshTokenKey(refreshToken), saved.ID, 7200)
+		})
+	}
+}
+
+func TestIssueRefreshToken(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		a, mockDS := newRefreshAuth()
+		mockDS.On("Set", mock.Anything, mock.Anything, "s1", 7200).Return(nil).Once()
+
+		token, err := a.IssueRefreshToken(context.Background(), "s1")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		mockDS.AssertCalled(t, "Set", mock.Anything, refreshTokenKey(token), "s1", 7200)
+		assert.NotContains(t, refreshTokenKey(token), token, "tokens are stored hashed")
+	})
+	t.Run("error storing token", func(t *testing.T) {
+		a, mockDS := newRefreshAuth()
+		mockDS.On("Set", mock.Anything, mock.Anything, "s1", 7200).Return(errors.New("ds error")).Once()
+		_, err := a.IssueRefreshToken(context.Background(), "s1")
+		assert.EqualError(t, err, "storing refresh token: ds error")
+	})
+	t.Run("error generating token", func(t *testing.T) {
+		original := randReadRefresh
+		randReadRefresh = func(b []byte) (int, error) { return 0, errors.New("rand error") }
+		t.Cleanup(func() { randReadRefresh = original })
+		a, _ := newRefreshAuth()
+		_, err := a.IssueRefreshToken(context.Background(), "s1")
+		assert.EqualError(t, err, "generating refresh token: rand error")
+	})
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	tokenKey := refreshTokenKey("old")
+	usedKey := refreshUsedKey("old")
+	stored := sessionJSON(t, storedSession)
+
+	tests := []struct {
+		name       string
+		setupMocks func(mockDS *mock_ds.MockDS)
+		expectErr  string
+		expectIs   error
+	}{
+		{
+			name: "success",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, usedKey, "s1", 7200).Return(true, nil).Once()
+				mockDS.On("Get", mock.Anything, "session:s1").Return(true, stored, nil).Once()
+				mockDS.On("Set", mock.Anything, mock.Anything, "s1", 7200).Return(nil).Once()
+			},
+		},
+		{
+			name: "unknown token",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(false, "", nil).Once()
+			},
+			expectIs: ErrRefreshTokenInvalid,
+		},
+		{
+			name: "reused token revokes the session",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, usedKey, "s1", 7200).Return(false, nil).Once()
+				mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
+			},
+			expectIs: ErrRefreshTokenReused,
+		},
+		{
+			name: "revoked session",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, usedKey, "s1", 7200).Return(true, nil).Once()
+				mockDS.On("Get", mock.Anything, "session:s1").Return(false, "", nil).Once()
+			},
+			expectIs: ErrRefreshTokenInvalid,
+		},
+		{
+			name: "error getting token",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(false, "", errors.New("ds error")).Once()
+			},
+			expectErr: "getting refresh token: ds error",
+		},
+		{
+			name: "error marking token used",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, usedKey, "s1", 7200).Return(false, errors.New("ds error")).Once()
+			},
+			expectErr: "marking refresh token used: ds error",
+		},
+		{
+			name: "error revoking session",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, usedKey, "s1", 7200).Return(false, nil).Once()
+				mockDS.On("Del", mock.Anything, "session:s1").Return(errors.New("ds error")).Once()
+			},
+			expectErr: "revoking session s1: ds error",
+		},
+		{
+			name: "error getting session",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, usedKey, "s1", 7200).Return(true, nil).Once()
+				mockDS.On("Get", mock.Anything, "session:s1").Return(false, "", errors.New("ds error")).Once()
+			},
+			expectErr: "getting session: ds error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, mockDS := newRefreshAuth()
+			tt.setupMocks(mockDS)
+
+			s, token, err := a.RotateRefreshToken(context.Background(), "old")
+
+			switch {
+			case tt.expectIs != nil:
+				assert.ErrorIs(t, err, tt.expectIs)
+			case tt.expectErr != "":
+				assert.EqualError(t, err, tt.expectErr)
+			default:
+				assert.NoError(t, err)
+				assert.NotEmpty(t, token)
+				assert.NotEqual(t, "old", token)
+				assert.Equal(t, storedSession, s)
+			}
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRotateRefreshToken_Grace(t *testing.T) {
+	tokenKey := refreshTokenKey("old")
+	usedKey := refreshUsedKey("old")
+	successorKey := refreshSuccessorKey("old")
+	stored := sessionJSON(t, storedSession)
+
+	tests := []struct {
+		name        string
+		setupMocks  func(mockDS *mock_ds.MockDS)
+		expectToken string
+		expectErr   string
+		expectIs    error
+	}{
+		{
+			name: "first use keeps the new token for the window",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, successorKey, mock.Anything, 10).Return(true, nil).Once()
+				mockDS.On("SetNX", mock.Anything, usedKey, "s1", 7200).Return(true, nil).Once()
+				mockDS.On("Get", mock.Anything, "session:s1").Return(true, stored, nil).Once()
+				mockDS.On("Set", mock.Anything, mock.Anything, "s1", 7200).Return(nil).Once()
+			},
+		},
+		{
+			name: "used again within the window gets the same token",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, successorKey, mock.Anything, 10).Return(false, nil).Once()
+				mockDS.On("Get", mock.Anything, successorKey).Return(true, "new", nil).Once()
+				mockDS.On("Get", mock.Anything, "session:s1").Return(true, stored, nil).Once()
+			},
+			expectToken: "new",
+		},
+		{
+			name: "window expired while using it again",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, successorKey, mock.Anything, 10).Return(false, nil).Once()
+				mockDS.On("Get", mock.Anything, successorKey).Return(false, "", nil).Once()
+				mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
+			},
+			expectIs: ErrRefreshTokenReused,
+		},
+		{
+			name: "used again after the window revokes the session",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, successorKey, mock.Anything, 10).Return(true, nil).Once()
+				mockDS.On("SetNX", mock.Anything, usedKey, "s1", 7200).Return(false, nil).Once()
+				mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
+			},
+			expectIs: ErrRefreshTokenReused,
+		},
+		{
+			name: "error marking token used",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, successorKey, mock.Anything, 10).Return(false, errors.New("ds error")).Once()
+			},
+			expectErr: "marking refresh token used: ds error",
+		},
+		{
+			name: "error getting the rotated token",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, successorKey, mock.Anything, 10).Return(false, nil).Once()
+				mockDS.On("Get", mock.Anything, successorKey).Return(false, "", errors.New("ds error")).Once()
+			},
+			expectErr: "getting rotated refresh token: ds error",
+		},
+		{
+			name: "error storing the new token",
+			setupMocks: func(mockDS *mock_ds.MockDS) {
+				mockDS.On("Get", mock.Anything, tokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, successorKey, mock.Anything, 10).Return(true, nil).Once()
+				mockDS.On("SetNX", mock.Anything, usedKey, "s1", 7200).Return(true, nil).Once()
+				mockDS.On("Get", mock.Anything, "session:s1").Return(true, stored, nil).Once()
+				mockDS.On("Set", mock.Anything, mock.Anything, "s1", 7200).Return(errors.New("ds error")).Once()
+			},
+			expectErr: "storing refresh token: ds error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			session.Set(session.New(mockDS, 7200))
+			a := New(OAuthConfig{ClientSecret: "sec", JWTRefreshExpirationInSeconds: 7200, JWTRefreshGraceInSeconds: 10}, mockDS)
+			tt.setupMocks(mockDS)
+			// the first use returns the token kept for the window
+			successor := ""
+			for _, call := range mockDS.ExpectedCalls {
+				if call.Method == "SetNX" && call.Arguments[1] == successorKey {
+					call.Run(func(args mock.Arguments) { successor = args.String(2) })
+				}
+			}
+
+			s, token, err := a.RotateRefreshToken(context.Background(), "old")
+
+			switch {
+			case tt.expectIs != nil:
+				assert.ErrorIs(t, err, tt.expectIs)
+			case tt.expectErr != "":
+				assert.EqualError(t, err, tt.expectErr)
+			default:
+				assert.NoError(t, err)
+				assert.NotEmpty(t, token)
+				assert.Equal(t, storedSession, s)
+				if tt.expectToken != "" {
+					assert.Equal(t, tt.expectToken, token)
+				} else {
+					assert.Equal(t, successor, token)
+				}
+			}
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	t.Run("revokes the session", func(t *testing.T) {
+		a, mockDS := newRefreshAuth()
+		mockDS.On("Get", mock.Anything, refreshTokenKey("old")).Return(true, "s1", nil).Once()
+		mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
+		assert.NoError(t, a.RevokeRefreshToken(context.Background(), "old"))
+		mockDS.AssertExpectations(t)
+	})
+	t.Run("unknown token", func(t *testing.T) {
+		a, mockDS := newRefreshAuth()
+		mockDS.On("Get", mock.Anything, refreshTokenKey("old")).Return(false, "", nil).Once()
+		assert.NoError(t, a.RevokeRefreshToken(context.Background(), "old"))
+	})
+	t.Run("error getting token", func(t *testing.T) {
+		a, mockDS := newRefreshAuth()
+		mockDS.On("Get", mock.Anything, refreshTokenKey("old")).Return(false, "", errors.New("ds error")).Once()
+		assert.EqualError(t, a.RevokeRefreshToken(context.Background(), "old"), "getting refresh token: ds error")
+	})
+}
+
+func TestRenew(t *testing.T) {
+	testutil.InjectMockJWTKey()
+	t.Cleanup(jwt_key.Reset)
+	cfg := OAuthConfig{JWTExpirationInSeconds: 3600, JWTRefreshExpirationInSeconds: 7200}
+	stored := sessionJSON(t, storedSession)
+
+	rotation := func(mockDS *mock_ds.MockDS) {
+		mockDS.On("Get", mock.Anything, refreshTokenKey("old")).Return(true, "s1", nil).Once()
+		mockDS.On("SetNX", mock.Anything, refreshUsedKey("old"), "s1", 7200).Return(true, nil).Once()
+		mockDS.On("Get", mock.Anything, "session:s1").Return(true, stored, nil).Once()
+		mockDS.On("Set", mock.Anything, mock.Anything, "s1", 7200).Return(nil).Once()
+	}
+
+	tests := []struct {
+		name        string
+		rotate      bool
+		operator    model.Operator
+		operatorErr error
+		saveErr     error
+		expectDel   bool
+		expectSave  bool
+		expectErr   string
+		expectIs    error
+	}{
+		{name: "success", rotate: true, operator: model.Operator{ID: 1, Enabled: true, Role: "supervisor"}, expectSave: true},
+		{name: "invalid refresh token", expectIs: ErrRefreshTokenInvalid},
+		{name: "disabled operator ends the session", rotate: true, operator: model.Operator{ID: 1}, expectDel: true,
+			expectIs: ErrRefreshTokenInvalid},
+		{name: "removed operator ends the session", rotate: true, expectDel: true, expectIs: ErrRefreshTokenInvalid},
+		{name: "error getting operator", rotate: true, operatorErr: errors.New("db error"),
+			expectErr: "getting operator 1: db error"},
+		{name: "error saving session", rotate: true, operator: model.Operator{ID: 1, Enabled: true}, expectSave: true,
+			saveErr: errors.New("ds error"), expectErr: "storing session: ds error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, mockDS := newRefreshAuth()
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			if tt.rotate {
+				rotation(mockDS)
+				mockOperator.On("GetOperatorById", mock.Anything, 1).Return(tt.operator, tt.operatorErr).Once()
+			} else {
+				mockDS.On("Get", mock.Anything, refreshTokenKey("old")).Return(false, "", nil).Once()
+			}
+			if tt.expectDel {
+				mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
+			}
+			if tt.expectSave {
+				mockDS.On("Set", mock.Anything, "session:s1", mock.Anything, 7200).Return(tt.saveErr).Once()
+				mockDS.On("SAdd", mock.Anything, "sessions:operator:1:ids", "s1", 7200).Return(nil).Maybe()
+			}
+			w := httptest.NewRecorder()
+
+			accessToken, err := a.Renew(context.Background(), w, "old", cfg)
+
+			switch {
+			case tt.expectIs != nil:
+				assert.ErrorIs(t, err, tt.expectIs)
+			case tt.expectErr != "":
+				assert.EqualError(t, err, tt.expectErr)
+			default:
+				assert.NoError(t, err)
+				claims, _, err := ParseTokenWithClaims(accessToken)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, claims.OperatorID)
+				assert.Equal(t, "supervisor", claims.Role)
+				assert.Equal(t, "idp-token", claims.IDPIDToken)
+				assert.Equal(t, "s1", claims.SessionID)
+				assert.NotEqual(t, "j1", claims.ID, "the renewed token has a new jti")
+				assert.Len(t, w.Result().Cookies(), 2)
+			}
+			mockDS.AssertExpectations(t)
+			mockOperator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:5000"
+	assert.Equal(t, "10.0.0.1", clientIP(req))
+	req.RemoteAddr = "10.0.0.1"
+	assert.Equal(t, "10.0.0.1", clientIP(req))
+}
+
+func TestRefreshTokenCookie(t *testing.T) {
+	cfg := OAuthConfig{JWTRefreshExpirationInSeconds: 7200, CookieSameSiteNone: true}
+
+	w := httptest.NewRecorder()
+	SetRefreshToken(w, "refresh", cfg)
+	cookie := w.Result().Cookies()[0]
+	assert.Equal(t, RefreshTokenKey, cookie.Name)
+	assert.True(t, cookie.HttpOnly)
+	assert.Equal(t, http.SameSiteNoneMode, cookie.SameSite)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", nil)
+	req.AddCookie(cookie)
+	token, err := GetRefreshToken(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh", token)
+
+	_, err = GetRefreshToken(httptest.NewRequest(http.MethodPost, "/auth/refresh", nil))
+	assert.Error(t, err)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/refresh", nil)
+	req.Header.Set("Cookie", RefreshTokenKey+"=bad\"value")
+	_, err = GetRefreshToken(req)
+	assert.Error(t, err)
+
+	w = httptest.NewRecorder()
+	DelRefreshToken(w, cfg)
+	assert.Empty(t, w.Result().Cookies()[0].Value)
+}
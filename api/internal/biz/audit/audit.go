@@ -0,0 +1,157 @@
+package biz_audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+	"via/internal/log"
+	"via/internal/model"
+	audit_provider "via/internal/provider/audit"
+)
+
+// Audited actions
+const (
+	AUTH_LOGIN            = "auth.login"
+	AUTH_LOCAL_LOGIN      = "auth.local_login"
+	AUTH_LOGOUT           = "auth.logout"
+	AUTH_FORBIDDEN        = "auth.forbidden"
+	SESSION_REVOKE        = "session.revoke"
+	GUIDE_ASSIGN          = "guide.assign"
+	GUIDE_RELEASE         = "guide.release"
+	GUIDE_TRANSFER        = "guide.transfer"
+	GUIDE_REASSIGN        = "guide.reassign"
+	GUIDE_STATUS          = "guide.status"
+	CLOSE_DAY             = "admin.close_day"
+	DEVICE_CREATE         = "device.create"
+	DEVICE_PAIRING_CODE   = "device.pairing_code"
+	DEVICE_PRIVACY        = "device.privacy"
+	DEVICE_REVOKE         = "device.revoke"
+	DEVICE_PAIR           = "device.pair"
+	OPERATOR_ADD          = "operator.add"
+	OPERATOR_DISABLE      = "operator.disable"
+	OPERATOR_LOCAL_LOGIN  = "operator.local_login"
+	OPERATOR_LINK         = "operator.link"
+	OPERATOR_AVAILABILITY = "operator.availability"
+)
+
+// Types of the audited targets
+const (
+	TARGET_GUIDE    = "guide"
+	TARGET_OPERATOR = "operator"
+	TARGET_SESSION  = "session"
+	TARGET_DEVICE   = "device"
+)
+
+// ACTOR_CLI is the actor of the admin commands
+const ACTOR_CLI = "cli"
+
+const (
+	DEFAULT_LIMIT = 50
+	MAX_LIMIT     = 500
+	verifyBatch   = 500
+)
+
+// Event is an action to audit, before and after are the changed values and are stored as json
+type Event struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	IP         string
+	Before     any
+	After      any
+}
+
+var now = time.Now
+
+// Record appends the event to the audit log. A failure is logged, the action it records is already done
+func Record(ctx context.Context, event Event) {
+	entry := model.AuditEntry{
+		Actor:      event.Actor,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		RequestID:  event.RequestID,
+		IP:         event.IP,
+		Before:     toJSON(ctx, event.Before),
+		After:      toJSON(ctx, event.After),
+		// stored with microseconds, the hash is computed on the value read back
+		CreatedAt: now().UTC().Truncate(time.Microsecond),
+	}
+	if _, err := audit_provider.Get().AppendAuditEntry(ctx, entry); err != nil {
+		log.Get().Error(ctx, err, "msg", "failed to record audit entry", "action", event.Action)
+	}
+}
+
+func toJSON(ctx context.Context, value any) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed to encode audit value")
+		return nil
+	}
+	return data
+}
+
+// Hash seals the entry along with the hash of the previous one. The id is left out, it is given on insert
+func Hash(entry model.AuditEntry) string {
+	data, _ := json.Marshal(struct {
+		PrevHash   string `json:"prevHash"`
+		CreatedAt  string `json:"createdAt"`
+		Actor      string `json:"actor"`
+		Action     string `json:"action"`
+		TargetType string `json:"targetType"`
+		TargetID   string `json:"targetId"`
+		RequestID  string `json:"requestId"`
+		IP         string `json:"ip"`
+		Before     string `json:"before"`
+		After      string `json:"after"`
+	}{
+		PrevHash:   entry.PrevHash,
+		CreatedAt:  entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Actor:      entry.Actor,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		RequestID:  entry.RequestID,
+		IP:         entry.IP,
+		Before:     string(entry.Before),
+		After:      string(entry.After),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func Search(ctx context.Context, search model.AuditSearch) ([]model.AuditEntry, error) {
+	return audit_provider.Get().SearchAuditEntries(ctx, search)
+}
+
+// Verify walks the audit log from its first entry checking every hash and its link to the previous entry
+func Verify(ctx context.Context) (model.AuditVerification, error) {
+	verification := model.AuditVerification{Valid: true}
+	afterId := 0
+	for {
+		entries, err := audit_provider.Get().GetAuditEntriesAfter(ctx, afterId, verifyBatch)
+		if err != nil {
+			return model.AuditVerification{}, err
+		}
+		for _, entry := range entries {
+			if entry.PrevHash != verification.LastHash || entry.Hash != Hash(entry) {
+				verification.Valid = false
+				verification.BrokenAt = entry.ID
+				return verification, nil
+			}
+			verification.Entries++
+			verification.LastHash = entry.Hash
+			afterId = entry.ID
+		}
+		if len(entries) < verifyBatch {
+			return verification, nil
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package biz_audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"via/internal/model"
+	audit_provider "via/internal/provider/audit"
+	mock_audit_provider "via/internal/provider/audit/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// chain links the entries as the provider stores them, numbering them from 1
+func chain(entries ...model.AuditEntry) []model.AuditEntry {
+	prevHash := ""
+	for i := range entries {
+		entries[i].ID = i + 1
+		entries[i].PrevHash = prevHash
+		entries[i].Hash = Hash(entries[i])
+		prevHash = entries[i].Hash
+	}
+	return entries
+}
+
+func TestRecord(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	createdAt := time.Date(2026, 3, 2, 10, 0, 0, 123456789, time.FixedZone("ART", -3*60*60))
+	now = func() time.Time { return createdAt }
+	t.Cleanup(func() { now = time.Now })
+
+	tests := []struct {
+		name        string
+		event       Event
+		appendErr   error
+		expectEntry model.AuditEntry
+	}{
+		{
+			name: "entry recorded",
+			event: Event{Actor: "operator:42", Action: GUIDE_STATUS, TargetType: TARGET_GUIDE, TargetID: "123",
+				RequestID: "req-1", IP: "10.0.0.1", Before: map[string]string{"status": "IN_PROCESS"},
+				After: map[string]string{"status": "DELIVERED"}},
+			expectEntry: model.AuditEntry{Actor: "operator:42", Action: GUIDE_STATUS, TargetType: TARGET_GUIDE,
+				TargetID: "123", RequestID: "req-1", IP: "10.0.0.1", Before: json.RawMessage(`{"status":"IN_PROCESS"}`),
+				After: json.RawMessage(`{"status":"DELIVERED"}`)},
+		},
+		{
+			name:        "entry without values",
+			event:       Event{Actor: ACTOR_CLI, Action: CLOSE_DAY},
+			expectEntry: model.AuditEntry{Actor: ACTOR_CLI, Action: CLOSE_DAY},
+		},
+		{
+			name:        "value not encodable",
+			event:       Event{Actor: ACTOR_CLI, Action: CLOSE_DAY, After: func() {}},
+			expectEntry: model.AuditEntry{Actor: ACTOR_CLI, Action: CLOSE_DAY},
+		},
+		{
+			name:        "append error is only logged",
+			event:       Event{Actor: ACTOR_CLI, Action: CLOSE_DAY},
+			appendErr:   errors.New("db error"),
+			expectEntry: model.AuditEntry{Actor: ACTOR_CLI, Action: CLOSE_DAY},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_audit_provider.MockAuditProvider)
+			audit_provider.Set(mockProvider)
+			expected := tt.expectEntry
+			expected.CreatedAt = time.Date(2026, 3, 2, 13, 0, 0, 123456000, time.UTC)
+			mockProvider.On("AppendAuditEntry", mock.Anything, expected).Return(expected, tt.appendErr).Once()
+
+			Record(context.Background(), tt.event)
+
+			mockProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	entry := model.AuditEntry{ID: 1, Actor: "operator:42", Action: GUIDE_ASSIGN, TargetType: TARGET_GUIDE,
+		TargetID: "123", After: json.RawMessage(`{"operatorId":42}`), CreatedAt: time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC)}
+	hash := Hash(entry)
+	assert.Len(t, hash, 64)
+
+	sameInstant := entry
+	sameInstant.ID = 7
+	sameInstant.CreatedAt = entry.CreatedAt.In(time.FixedZone("ART", -3*60*60))
+	assert.Equal(t, hash, Hash(sameInstant), "the id and the time zone are not sealed")
+
+	for name, change := range map[string]func(*model.AuditEntry){
+		"prev hash": func(e *model.AuditEntry) { e.PrevHash = "abc" },
+		"actor":     func(e *model.AuditEntry) { e.Actor = "operator:7" },
+		"after":     func(e *model.AuditEntry) { e.After = json.RawMessage(`{"operatorId":7}`) },
+		"created":   func(e *model.AuditEntry) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) },
+	} {
+		changed := entry
+		change(&changed)
+		assert.NotEqual(t, hash, Hash(changed), name)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	entries := func() []model.AuditEntry {
+		return chain(
+			model.AuditEntry{Actor: "operator:42", Action: AUTH_LOGIN, CreatedAt: time.Unix(1, 0)},
+			model.AuditEntry{Actor: "operator:42", Action: GUIDE_ASSIGN, TargetType: TARGET_GUIDE, TargetID: "123",
+				CreatedAt: time.Unix(2, 0)},
+			model.AuditEntry{Actor: ACTOR_CLI, Action: OPERATOR_DISABLE, CreatedAt: time.Unix(3, 0)},
+		)
+	}
+
+	tests := []struct {
+		name      string
+		entries   func() []model.AuditEntry
+		getErr    error
+		expect    model.AuditVerification
+		expectErr bool
+	}{
+		{
+			name:    "empty log",
+			entries: func() []model.AuditEntry { return []model.AuditEntry{} },
+			expect:  model.AuditVerification{Valid: true},
+		},
+		{
+			name:    "valid chain",
+			entries: entries,
+			expect:  model.AuditVerification{Valid: true, Entries: 3, LastHash: entries()[2].Hash},
+		},
+		{
+			name: "tampered entry",
+			entries: func() []model.AuditEntry {
+				tampered := entries()
+				tampered[1].TargetID = "456"
+				return tampered
+			},
+			expect: model.AuditVerification{Entries: 1, BrokenAt: 2, LastHash: entries()[0].Hash},
+		},
+		{
+			name: "deleted entry",
+			entries: func() []model.AuditEntry {
+				all := entries()
+				return []model.AuditEntry{all[0], all[2]}
+			},
+			expect: model.AuditVerification{Entries: 1, BrokenAt: 3, LastHash: entries()[0].Hash},
+		},
+		{
+			name:      "provider error",
+			entries:   func() []model.AuditEntry { return nil },
+			getErr:    errors.New("db error"),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_audit_provider.MockAuditProvider)
+			audit_provider.Set(mockProvider)
+			mockProvider.On("GetAuditEntriesAfter", mock.Anything, 0, verifyBatch).Return(tt.entries(), tt.getErr)
+
+			verification, err := Verify(context.Background())
+
+			assert.Equal(t, tt.expectErr, err != nil)
+			assert.Equal(t, tt.expect, verification)
+		})
+	}
+}
+
+func TestVerifyBatches(t *testing.T) {
+	all := make([]model.AuditEntry, verifyBatch+1)
+	for i := range all {
+		all[i] = model.AuditEntry{Actor: ACTOR_CLI, Action: CLOSE_DAY, CreatedAt: time.Unix(int64(i), 0)}
+	}
+	all = chain(all...)
+	mockProvider := new(mock_audit_provider.MockAuditProvider)
+	audit_provider.Set(mockProvider)
+	mockProvider.On("GetAuditEntriesAfter", mock.Anything, 0, verifyBatch).Return(all[:verifyBatch], nil).Once()
+	mockProvider.On("GetAuditEntriesAfter", mock.Anything, verifyBatch, verifyBatch).Return(all[verifyBatch:], nil).Once()
+
+	verification, err := Verify(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AuditVerification{Valid: true, Entries: verifyBatch + 1, LastHash: all[verifyBatch].Hash},
+		verification)
+	mockProvider.AssertExpectations(t)
+}
+
+func TestSearch(t *testing.T) {
+	mockProvider := new(mock_audit_provider.MockAuditProvider)
+	audit_provider.Set(mockProvider)
+	search := model.AuditSearch{Actor: "operator:42", Limit: DEFAULT_LIMIT}
+	mockProvider.On("SearchAuditEntries", mock.Anything, search).Return([]model.AuditEntry{{ID: 4}}, nil)
+
+	entries, err := Search(context.Background(), search)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.AuditEntry{{ID: 4}}, entries)
+}
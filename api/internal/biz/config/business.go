@@ -11,6 +11,7 @@ type BussinessCfg struct {
 	DeliveredStatus string `env:"DELIVERED_STATUS" envDefault:"ENT" json:"deliveredStatus"`
 	PendingStatus   string `env:"PENDING_STATUS" envDefault:"ASP,CPO,ORI,PTE" json:"pendingStatus"`
 	HomeDelivery    string `env:"HOME_DELIVERY" envDefault:"CD06" json:"homeDelivery"`
+	MonitorPrivacy  string `env:"MONITOR_PRIVACY" envDefault:"initials" json:"monitorPrivacy"` // default of the branch monitors, initials, first_name, ticket or guide_last4
 }
 
 const (
@@ -0,0 +1,169 @@
+package biz_device
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"via/internal/global"
+	"via/internal/log"
+	"via/internal/model"
+	device_provider "via/internal/provider/device"
+	"via/internal/pubsub"
+)
+
+type DeviceCfg struct {
+	Required       bool `env:"REQUIRED" envDefault:"true" json:"required"`               // the kiosk and monitor endpoints reject requests without a device token
+	PairingCodeTTL int  `env:"PAIRING_CODE_TTL" envDefault:"900" json:"pairingCodeTtl"`  // in seconds
+	CookieMaxAge   int  `env:"COOKIE_MAX_AGE" envDefault:"34560000" json:"cookieMaxAge"` // in seconds, browsers cap it to 400 days
+}
+
+// Device kinds, each one is only accepted by its own endpoints
+const (
+	KIND_KIOSK   = "kiosk"
+	KIND_MONITOR = "monitor"
+)
+
+// ErrPairingCodeInvalid is returned when the pairing code is unknown, expired or already used
+var ErrPairingCodeInvalid = errors.New("invalid pairing code")
+
+// the pairing code alphabet leaves out the characters easily mistaken for others, its 32 symbols
+// keep the random bytes uniform
+const pairingCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+const pairingCodeLength = 8
+
+var randRead = rand.Read
+
+func IsValidKind(kind string) bool {
+	return kind == KIND_KIOSK || kind == KIND_MONITOR
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func newPairingCode() (string, error) {
+	b := make([]byte, pairingCodeLength)
+	if _, err := randRead(b); err != nil {
+		return "", fmt.Errorf("generating pairing code: %w", err)
+	}
+	code := make([]byte, pairingCodeLength)
+	for i, v := range b {
+		code[i] = pairingCodeAlphabet[int(v)%len(pairingCodeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// normalizePairingCode accepts the code as typed, in lower case or split by dashes or spaces
+func normalizePairingCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func newPairing(cfg DeviceCfg) (string, model.DevicePairing, error) {
+	code, err := newPairingCode()
+	if err != nil {
+		return "", model.DevicePairing{}, err
+	}
+	return code, model.DevicePairing{
+		CodeHash:  hash(code),
+		ExpiresAt: time.Now().Add(time.Duration(cfg.PairingCodeTTL) * time.Second),
+	}, nil
+}
+
+// Create registers the device and returns it with the code it is paired with
+func Create(ctx context.Context, device model.Device, cfg DeviceCfg) (model.Device, string, error) {
+	code, pairing, err := newPairing(cfg)
+	if err != nil {
+		return model.Device{}, "", err
+	}
+	device, err = device_provider.Get().CreateDevice(ctx, device, pairing)
+	if err != nil {
+		return model.Device{}, "", err
+	}
+	return device, code, nil
+}
+
+func GetDevices(ctx context.Context) ([]model.Device, error) {
+	return device_provider.Get().GetDevices(ctx)
+}
+
+// NewPairingCode replaces the pending code of the device, pairing again gives it a new token.
+// It reports false when the device does not exist or was revoked
+func NewPairingCode(ctx context.Context, id int, cfg DeviceCfg) (string, bool, error) {
+	code, pairing, err := newPairing(cfg)
+	if err != nil {
+		return "", false, err
+	}
+	found, err := device_provider.Get().SetDevicePairing(ctx, id, pairing)
+	if err != nil || !found {
+		return "", found, err
+	}
+	return code, true, nil
+}
+
+// Pair uses the pairing code and returns the device with its new token, formatted as <id>.<secret>
+func Pair(ctx context.Context, code string) (model.Device, string, error) {
+	b := make([]byte, 32)
+	if _, err := randRead(b); err != nil {
+		return model.Device{}, "", fmt.Errorf("generating device token: %w", err)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+	device, err := device_provider.Get().PairDevice(ctx, hash(normalizePairingCode(code)),
+		model.DeviceCredentials{TokenHash: hash(secret)})
+	if err != nil {
+		return model.Device{}, "", err
+	}
+	if device.ID == 0 {
+		return model.Device{}, "", ErrPairingCodeInvalid
+	}
+	return device, strconv.Itoa(device.ID) + "." + secret, nil
+}
+
+// Authenticate returns the device the token belongs to, it reports false for malformed, replaced
+// or revoked tokens
+func Authenticate(ctx context.Context, token string) (model.Device, bool, error) {
+	idPart, secret, found := strings.Cut(token, ".")
+	id, err := strconv.Atoi(idPart)
+	if !found || err != nil || secret == "" {
+		return model.Device{}, false, nil
+	}
+	device, credentials, err := device_provider.Get().GetDeviceCredentials(ctx, id)
+	if err != nil {
+		return model.Device{}, false, err
+	}
+	if device.ID == 0 || device.RevokedAt != nil || credentials.TokenHash == "" {
+		return model.Device{}, false, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(credentials.TokenHash), []byte(hash(secret))) != 1 {
+		return model.Device{}, false, nil
+	}
+	return device, true, nil
+}
+
+// Revoke rejects the device token from then on and ends its open event streams. It reports false
+// when the device does not exist or was already revoked
+func Revoke(ctx context.Context, id int) (bool, error) {
+	found, err := device_provider.Get().RevokeDevice(ctx, id)
+	if err != nil || !found {
+		return found, err
+	}
+	// when the notice is lost the streams keep going until the device reconnects
+	if err := pubsub.Get().Publish(ctx, global.DeviceRevokedChannel, strconv.Itoa(id)); err != nil {
+		log.Get().Error(ctx, err, "msg", "failed to publish device revocation", "device_id", id)
+	}
+	return true, nil
+}
+
+// SetPrivacy changes how the monitor identifies the guides, an empty mode uses the branch default. The
+// open event streams of the monitor keep the previous mode until it reconnects
+func SetPrivacy(ctx context.Context, id int, privacy string) (bool, error) {
+	return device_provider.Get().SetDevicePrivacy(ctx, id, privacy)
+}
@@ -0,0 +1,242 @@
+package biz_device
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"via/internal/global"
+	"via/internal/model"
+	device_provider "via/internal/provider/device"
+	mock_device_provider "via/internal/provider/device/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var cfg = DeviceCfg{PairingCodeTTL: 600}
+
+func TestIsValidKind(t *testing.T) {
+	assert.True(t, IsValidKind(KIND_KIOSK))
+	assert.True(t, IsValidKind(KIND_MONITOR))
+	assert.False(t, IsValidKind("tablet"))
+}
+
+func TestNewPairingCode(t *testing.T) {
+	code, err := newPairingCode()
+	assert.NoError(t, err)
+	assert.Len(t, code, pairingCodeLength)
+	for _, c := range code {
+		assert.Contains(t, pairingCodeAlphabet, string(c))
+	}
+	assert.Equal(t, code, normalizePairingCode(strings.ToLower(code[:4])+"-"+code[4:]))
+}
+
+func TestCreate(t *testing.T) {
+	mockProvider := new(mock_device_provider.MockDeviceProvider)
+	device_provider.Set(mockProvider)
+	device := model.Device{Name: "Entrance", Kind: KIND_KIOSK, Branch: "123"}
+	var pairing model.DevicePairing
+	mockProvider.On("CreateDevice", mock.Anything, device, mock.Anything).
+		Run(func(args mock.Arguments) { pairing = args.Get(2).(model.DevicePairing) }).
+		Return(model.Device{ID: 4, Name: "Entrance"}, nil).Once()
+
+	created, code, err := Create(context.Background(), device, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, created.ID)
+	assert.Equal(t, hash(code), pairing.CodeHash)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), pairing.ExpiresAt, time.Minute)
+
+	mockProvider.On("CreateDevice", mock.Anything, device, mock.Anything).
+		Return(model.Device{}, errors.New("db error")).Once()
+	_, _, err = Create(context.Background(), device, cfg)
+	assert.EqualError(t, err, "db error")
+
+	randRead = func([]byte) (int, error) { return 0, errors.New("no entropy") }
+	t.Cleanup(func() { randRead = rand.Read })
+	_, _, err = Create(context.Background(), device, cfg)
+	assert.ErrorContains(t, err, "generating pairing code")
+}
+
+func TestNewPairingCodeForDevice(t *testing.T) {
+	mockProvider := new(mock_device_provider.MockDeviceProvider)
+	device_provider.Set(mockProvider)
+	mockProvider.On("SetDevicePairing", mock.Anything, 4, mock.Anything).Return(true, nil).Once()
+	mockProvider.On("SetDevicePairing", mock.Anything, 5, mock.Anything).Return(false, nil).Once()
+	mockProvider.On("SetDevicePairing", mock.Anything, 6, mock.Anything).Return(false, errors.New("db error")).Once()
+
+	code, found, err := NewPairingCode(context.Background(), 4, cfg)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Len(t, code, pairingCodeLength)
+
+	code, found, err = NewPairingCode(context.Background(), 5, cfg)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Empty(t, code)
+
+	_, _, err = NewPairingCode(context.Background(), 6, cfg)
+	assert.EqualError(t, err, "db error")
+
+	randRead = func([]byte) (int, error) { return 0, errors.New("no entropy") }
+	t.Cleanup(func() { randRead = rand.Read })
+	_, _, err = NewPairingCode(context.Background(), 4, cfg)
+	assert.ErrorContains(t, err, "generating pairing code")
+}
+
+func TestPair(t *testing.T) {
+	tests := []struct {
+		name      string
+		device    model.Device
+		pairErr   error
+		randErr   error
+		expectErr string
+	}{
+		{name: "device paired", device: model.Device{ID: 4, Kind: KIND_MONITOR}},
+		{name: "unknown code", expectErr: ErrPairingCodeInvalid.Error()},
+		{name: "provider error", pairErr: errors.New("db error"), expectErr: "db error"},
+		{name: "random error", randErr: errors.New("no entropy"), expectErr: "generating device token: no entropy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_device_provider.MockDeviceProvider)
+			device_provider.Set(mockProvider)
+			var credentials model.DeviceCredentials
+			mockProvider.On("PairDevice", mock.Anything, hash("ABCD2345"), mock.Anything).
+				Run(func(args mock.Arguments) { credentials = args.Get(2).(model.DeviceCredentials) }).
+				Return(tt.device, tt.pairErr)
+			if tt.randErr != nil {
+				randRead = func([]byte) (int, error) { return 0, tt.randErr }
+				t.Cleanup(func() { randRead = rand.Read })
+			}
+
+			device, token, err := Pair(context.Background(), "abcd-2345")
+
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				assert.Empty(t, token)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.device, device)
+			id, secret, _ := strings.Cut(token, ".")
+			assert.Equal(t, "4", id)
+			assert.Equal(t, hash(secret), credentials.TokenHash)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	revokedAt := time.Now()
+	tests := []struct {
+		name         string
+		token        string
+		device       model.Device
+		tokenHash    string
+		getErr       error
+		expectOk     bool
+		expectErr    bool
+		expectLookup bool
+	}{
+		{name: "valid token", token: "4.secret", device: model.Device{ID: 4}, tokenHash: hash("secret"),
+			expectOk: true, expectLookup: true},
+		{name: "replaced token", token: "4.old", device: model.Device{ID: 4}, tokenHash: hash("secret"),
+			expectLookup: true},
+		{name: "revoked device", token: "4.secret", device: model.Device{ID: 4, RevokedAt: &revokedAt},
+			tokenHash: hash("secret"), expectLookup: true},
+		{name: "device not paired", token: "4.secret", device: model.Device{ID: 4}, expectLookup: true},
+		{name: "unknown device", token: "4.secret", expectLookup: true},
+		{name: "lookup error", token: "4.secret", getErr: errors.New("db error"), expectErr: true, expectLookup: true},
+		{name: "without id", token: "secret"},
+		{name: "id not a number", token: "four.secret"},
+		{name: "without secret", token: "4."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_device_provider.MockDeviceProvider)
+			device_provider.Set(mockProvider)
+			mockProvider.On("GetDeviceCredentials", mock.Anything, 4).
+				Return(tt.device, model.DeviceCredentials{TokenHash: tt.tokenHash}, tt.getErr)
+
+			device, ok, err := Authenticate(context.Background(), tt.token)
+
+			assert.Equal(t, tt.expectErr, err != nil)
+			assert.Equal(t, tt.expectOk, ok)
+			if tt.expectOk {
+				assert.Equal(t, tt.device, device)
+			} else {
+				assert.Empty(t, device)
+			}
+			if !tt.expectLookup {
+				mockProvider.AssertNotCalled(t, "GetDeviceCredentials")
+			}
+		})
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	tests := []struct {
+		name          string
+		found         bool
+		revokeErr     error
+		publishErr    error
+		expectFound   bool
+		expectErr     bool
+		expectPublish bool
+	}{
+		{name: "device revoked", found: true, expectFound: true, expectPublish: true},
+		{name: "revocation notice lost", found: true, publishErr: errors.New("pubsub down"), expectFound: true,
+			expectPublish: true},
+		{name: "device not found"},
+		{name: "provider error", revokeErr: errors.New("db error"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_device_provider.MockDeviceProvider)
+			device_provider.Set(mockProvider)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			mockProvider.On("RevokeDevice", mock.Anything, 4).Return(tt.found, tt.revokeErr)
+			mockPubSub.On("Publish", mock.Anything, global.DeviceRevokedChannel, "4").Return(tt.publishErr)
+
+			found, err := Revoke(context.Background(), 4)
+
+			assert.Equal(t, tt.expectErr, err != nil)
+			assert.Equal(t, tt.expectFound, found)
+			if tt.expectPublish {
+				mockPubSub.AssertCalled(t, "Publish", mock.Anything, global.DeviceRevokedChannel, "4")
+			} else {
+				mockPubSub.AssertNotCalled(t, "Publish")
+			}
+		})
+	}
+}
+
+func TestGetDevices(t *testing.T) {
+	mockProvider := new(mock_device_provider.MockDeviceProvider)
+	device_provider.Set(mockProvider)
+	mockProvider.On("GetDevices", mock.Anything).Return([]model.Device{{ID: 4}}, nil)
+
+	devices, err := GetDevices(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Device{{ID: 4}}, devices)
+}
+
+func TestSetPrivacy(t *testing.T) {
+	mockProvider := new(mock_device_provider.MockDeviceProvider)
+	device_provider.Set(mockProvider)
+	mockProvider.On("SetDevicePrivacy", mock.Anything, 4, "ticket").Return(true, nil)
+
+	found, err := SetPrivacy(context.Background(), 4, "ticket")
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
@@ -0,0 +1,251 @@
+package biz_guide_assign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+	biz_guide_status "via/internal/biz/guide/status"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/ds"
+	"via/internal/global"
+	"via/internal/log"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	operator_provider "via/internal/provider/operator"
+	"via/internal/pubsub"
+)
+
+type AssignCfg struct {
+	Enabled   bool   `env:"ENABLED" envDefault:"false" json:"enabled"`
+	Strategy  string `env:"STRATEGY" envDefault:"leastLoaded" json:"strategy"` // leastLoaded or roundRobin
+	MaxGuides int    `env:"MAX_GUIDES" envDefault:"3" json:"maxGuides"`        // in process guides per operator, 0 is unlimited
+	Lease     int    `env:"LEASE" envDefault:"15" json:"lease"`                // seconds, the assigner lock lasts without renewal
+	Sweep     int    `env:"SWEEP" envDefault:"60" json:"sweep"`                // seconds between the sweeps of the unassigned guides, 0 for none
+}
+
+// Assignment strategies
+const (
+	STRATEGY_LEAST_LOADED = "leastLoaded"
+	STRATEGY_ROUND_ROBIN  = "roundRobin"
+)
+
+const roundRobinKey = "assign:round_robin"
+
+// retryDelay is the wait before subscribing again after losing the subscription
+var retryDelay = 5 * time.Second
+
+var ErrSubscriptionClosed = errors.New("new guide subscription closed")
+
+// Assigner gives the new guides to the available operators
+type Assigner struct {
+	cfg AssignCfg
+}
+
+func New(cfg AssignCfg) *Assigner {
+	if cfg.Strategy != STRATEGY_ROUND_ROBIN {
+		cfg.Strategy = STRATEGY_LEAST_LOADED
+	}
+	return &Assigner{cfg: cfg}
+}
+
+// Assign gives the guide, while nobody is attending it, to an available and online operator below the guides cap.
+// It returns the operator id, 0 when no operator can take it or somebody took it first.
+func (a *Assigner) Assign(ctx context.Context, guideId int) (int, error) {
+	operatorId, _, err := a.assign(ctx, guideId)
+	return operatorId, err
+}
+
+// assign is Assign also reporting whether any operator could take the guide
+func (a *Assigner) assign(ctx context.Context, guideId int) (int, bool, error) {
+	operators, err := operator_provider.Get().GetOperatorsByAvailability(ctx, biz_operator.AVAILABILITY_AVAILABLE)
+	if err != nil {
+		return 0, false, fmt.Errorf("getting available operators: %w", err)
+	}
+	workload, err := guide_provider.Get().GetOperatorWorkload(ctx, biz_guide_status.GetInProcessStatus())
+	if err != nil {
+		return 0, false, fmt.Errorf("getting operator workload: %w", err)
+	}
+	online, err := onlineOperators(ctx, operators)
+	if err != nil {
+		return 0, false, err
+	}
+	candidates := []model.Operator{}
+	for _, operator := range operators {
+		if online[operator.ID] && (a.cfg.MaxGuides <= 0 || workload[operator.ID] < a.cfg.MaxGuides) {
+			candidates = append(candidates, operator)
+		}
+	}
+	if len(candidates) == 0 {
+		log.Get().Info(ctx, "msg", "no operator available for the guide", "guide_id", guideId)
+		return 0, false, nil
+	}
+
+	operator, err := a.pick(ctx, candidates, workload)
+	if err != nil {
+		return 0, true, err
+	}
+	assigned, err := guide_provider.Get().AssignGuide(ctx, guideId, biz_operator.OPERATOR_SYSTEM, operator.ID)
+	if err != nil {
+		return 0, true, fmt.Errorf("assigning guide %d: %w", guideId, err)
+	}
+	if !assigned {
+		log.Get().Info(ctx, "msg", "guide already assigned", "guide_id", guideId)
+		return 0, true, nil
+	}
+	log.Get().Info(ctx, "msg", "guide auto assigned", "guide_id", guideId, "operator_id", operator.ID, "strategy", a.cfg.Strategy)
+	err = pubsub.Get().Publish(ctx, global.GuideAssignmentChannel, fmt.Sprintf("{\"guide_id\":\"%d\"}", guideId))
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "unable to publish event", "channel", global.GuideAssignmentChannel)
+	}
+	return operator.ID, true, nil
+}
+
+// Sweep assigns, oldest first, the new guides still owned by the system: the ones announced while no
+// operator could take them or no instance was listening. It stops once no operator can take more
+// and returns how many were assigned.
+func (a *Assigner) Sweep(ctx context.Context) (int, error) {
+	guides, err := guide_provider.Get().GetGuidesByStatus(ctx, []string{biz_guide_status.INITIAL})
+	if err != nil {
+		return 0, fmt.Errorf("getting new guides: %w", err)
+	}
+	slices.SortFunc(guides, func(x, y model.Guide) int {
+		if c := x.CreatedAt.Compare(y.CreatedAt); c != 0 {
+			return c
+		}
+		return x.ID - y.ID
+	})
+	count := 0
+	for _, guide := range guides {
+		if guide.Operator.ID != biz_operator.OPERATOR_SYSTEM {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		operatorId, available, err := a.assign(ctx, guide.ID)
+		if err != nil {
+			return count, err
+		}
+		if !available {
+			break
+		}
+		if operatorId != 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (a *Assigner) sweep(ctx context.Context) {
+	count, err := a.Sweep(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Get().Error(ctx, err, "msg", "failed sweeping the unassigned guides")
+		}
+		return
+	}
+	if count > 0 {
+		log.Get().Info(ctx, "msg", "unassigned guides swept", "assigned", count)
+	}
+}
+
+// pick chooses among the candidates, sorted by id: the one with fewer guides, the first one on ties,
+// or the next one in turn, the turn is shared by every API instance
+func (a *Assigner) pick(ctx context.Context, candidates []model.Operator, workload map[int]int) (model.Operator, error) {
+	if a.cfg.Strategy == STRATEGY_ROUND_ROBIN {
+		turn, err := ds.Get().Incr(ctx, roundRobinKey)
+		if err != nil {
+			return model.Operator{}, fmt.Errorf("getting round robin turn: %w", err)
+		}
+		return candidates[int((turn-1)%int64(len(candidates)))], nil
+	}
+	chosen := candidates[0]
+	for _, operator := range candidates[1:] {
+		if workload[operator.ID] < workload[chosen.ID] {
+			chosen = operator
+		}
+	}
+	return chosen, nil
+}
+
+// Run assigns the guides announced on the new guide channel until ctx is done, subscribing again
+// when the subscription is lost. The unassigned guides are swept on every subscription, every
+// cfg.Sweep seconds and when an operator becomes available.
+func (a *Assigner) Run(ctx context.Context) {
+	for {
+		err := a.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Get().Error(ctx, err, "msg", "guide assignment stopped, retrying", "retry_in", retryDelay)
+		select {
+		case <-time.After(retryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunLocked is Run as a scheduler job: the instance holding the job lock assigns the guides,
+// the others take over when its lock is lost
+func (a *Assigner) RunLocked(ctx context.Context) error {
+	a.Run(ctx)
+	return nil
+}
+
+func (a *Assigner) listen(ctx context.Context) error {
+	sub, err := pubsub.Get().Subscribe(ctx, global.NewGuideChannel, global.OperatorAvailableChannel)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+	// the guides announced before subscribing were missed
+	a.sweep(ctx)
+	var tick <-chan time.Time
+	if a.cfg.Sweep > 0 {
+		ticker := time.NewTicker(time.Duration(a.cfg.Sweep) * time.Second)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return ErrSubscriptionClosed
+			}
+			if msg.Channel == global.OperatorAvailableChannel {
+				a.sweep(ctx)
+				continue
+			}
+			guideId, ok := guideIdFromPayload(msg.Payload)
+			if !ok {
+				log.Get().Warn(ctx, "msg", "invalid new guide event", "payload", msg.Payload)
+				continue
+			}
+			if _, err := a.Assign(ctx, guideId); err != nil {
+				log.Get().Error(ctx, err, "msg", "failed auto assigning guide", "guide_id", guideId)
+			}
+		case <-tick:
+			a.sweep(ctx)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// guideIdFromPayload reads the guide id of a {"guide_id":"<id>"} event
+func guideIdFromPayload(payload any) (int, bool) {
+	event, ok := payload.(map[string]any)
+	if !ok {
+		return 0, false
+	}
+	value, ok := event["guide_id"].(string)
+	if !ok {
+		return 0, false
+	}
+	guideId, err := strconv.Atoi(value)
+	return guideId, err == nil && guideId > 0
+}
@@ -0,0 +1,404 @@
+package biz_guide_assign
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	biz_guide_status "via/internal/biz/guide/status"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/ds"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/global"
+	"via/internal/model"
+	"via/internal/presence"
+	guide_provider "via/internal/provider/guide"
+	mock_guide_provider "via/internal/provider/guide/mock"
+	operator_provider "via/internal/provider/operator"
+	mock_operator_provider "via/internal/provider/operator/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAssign(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	operators := []model.Operator{{ID: 2}, {ID: 3}, {ID: 4}}
+	workload := map[int]int{biz_operator.OPERATOR_SYSTEM: 5, 2: 1, 4: 3}
+
+	tests := []struct {
+		name         string
+		cfg          AssignCfg
+		operators    []model.Operator
+		operatorsErr error
+		workloadErr  error
+		turn         int
+		turnErr      error
+		assigned     bool
+		assignErr    error
+		expectedId   int
+		expectAssign int
+		expectErr    string
+	}{
+		{name: "least loaded", cfg: AssignCfg{MaxGuides: 3}, operators: operators, assigned: true, expectedId: 3, expectAssign: 3},
+		{name: "least loaded without cap", cfg: AssignCfg{}, operators: []model.Operator{{ID: 2}, {ID: 4}}, assigned: true,
+			expectedId: 2, expectAssign: 2},
+		{name: "round robin skips capped operators", cfg: AssignCfg{Strategy: STRATEGY_ROUND_ROBIN, MaxGuides: 3},
+			operators: operators, turn: 4, assigned: true, expectedId: 3, expectAssign: 3},
+		{name: "taken by somebody else", cfg: AssignCfg{MaxGuides: 3}, operators: operators, expectAssign: 3},
+		{name: "nobody available", cfg: AssignCfg{MaxGuides: 3}, operators: []model.Operator{}},
+		{name: "everybody at the cap", cfg: AssignCfg{MaxGuides: 1}, operators: []model.Operator{{ID: 2}, {ID: 4}}},
+		{name: "error getting operators", operatorsErr: errors.New("db error"), operators: []model.Operator{},
+			expectErr: "getting available operators: db error"},
+		{name: "error getting workload", operators: operators, workloadErr: errors.New("db error"),
+			expectErr: "getting operator workload: db error"},
+		{name: "error getting turn", cfg: AssignCfg{Strategy: STRATEGY_ROUND_ROBIN}, operators: operators,
+			turnErr: errors.New("ds error"), expectErr: "getting round robin turn: ds error"},
+		{name: "error assigning", operators: operators, assignErr: errors.New("db error"), expectAssign: 3,
+			expectErr: "assigning guide 10: db error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockDS := new(mock_ds.MockDS)
+			ds.Set(mockDS)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+
+			mockOperator.On("GetOperatorsByAvailability", mock.Anything, biz_operator.AVAILABILITY_AVAILABLE).
+				Return(tt.operators, tt.operatorsErr).Once()
+			if tt.operatorsErr == nil {
+				mockGuide.On("GetOperatorWorkload", mock.Anything, biz_guide_status.GetInProcessStatus()).
+					Return(workload, tt.workloadErr).Once()
+			}
+			if tt.turn != 0 || tt.turnErr != nil {
+				mockDS.On("Incr", mock.Anything, roundRobinKey).Return(tt.turn, tt.turnErr).Once()
+			}
+			if tt.expectAssign != 0 {
+				mockGuide.On("AssignGuide", mock.Anything, 10, biz_operator.OPERATOR_SYSTEM, tt.expectAssign).
+					Return(tt.assigned, tt.assignErr).Once()
+			}
+			if tt.assigned {
+				mockPubSub.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"10"}`).
+					Return(errors.New("pubsub error")).Once()
+			}
+
+			operatorId, err := New(tt.cfg).Assign(context.Background(), 10)
+
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedId, operatorId)
+			mockOperator.AssertExpectations(t)
+			mockGuide.AssertExpectations(t)
+			mockDS.AssertExpectations(t)
+			mockPubSub.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssignSkipsDisconnectedOperators(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	t.Cleanup(func() { presence.Set(nil) })
+
+	tests := []struct {
+		name        string
+		presenceErr error
+		expectedId  int
+		expectErr   string
+	}{
+		{name: "online operator", expectedId: 3},
+		{name: "error getting presence", presenceErr: errors.New("ds error"),
+			expectErr: "getting operators presence: getting operator 2 presence: ds error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockDS := new(mock_ds.MockDS)
+			ds.Set(mockDS)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			presence.Set(presence.New(presence.PresenceCfg{}))
+
+			mockOperator.On("GetOperatorsByAvailability", mock.Anything, biz_operator.AVAILABILITY_AVAILABLE).
+				Return([]model.Operator{{ID: 2}, {ID: 3}}, nil).Once()
+			mockGuide.On("GetOperatorWorkload", mock.Anything, biz_guide_status.GetInProcessStatus()).
+				Return(map[int]int{3: 2}, nil).Once()
+			// the least loaded operator is disconnected
+			mockDS.On("SMembers", mock.Anything, "presence:operator:2:connections").Return([]string{}, tt.presenceErr).Once()
+			if tt.presenceErr == nil {
+				mockDS.On("SMembers", mock.Anything, "presence:operator:3:connections").Return([]string{"a"}, nil).Once()
+				mockGuide.On("AssignGuide", mock.Anything, 10, biz_operator.OPERATOR_SYSTEM, 3).Return(true, nil).Once()
+				mockPubSub.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"10"}`).Return(nil).Once()
+			}
+
+			operatorId, err := New(AssignCfg{MaxGuides: 3}).Assign(context.Background(), 10)
+
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedId, operatorId)
+			mockOperator.AssertExpectations(t)
+			mockGuide.AssertExpectations(t)
+			mockDS.AssertExpectations(t)
+			mockPubSub.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSweep(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	created := time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC)
+	system := model.Operator{ID: biz_operator.OPERATOR_SYSTEM}
+	guides := []model.Guide{
+		{ID: 12, Operator: system, CreatedAt: created.Add(time.Minute)},
+		{ID: 11, Operator: system, CreatedAt: created},
+		{ID: 13, Operator: model.Operator{ID: 5}, CreatedAt: created},
+		{ID: 14, Operator: system, CreatedAt: created.Add(2 * time.Minute)},
+	}
+
+	tests := []struct {
+		name        string
+		cfg         AssignCfg
+		guidesErr   error
+		setup       func(mockGuide *mock_guide_provider.MockGuideProvider)
+		expectCount int
+		expectErr   string
+	}{
+		{name: "oldest first until nobody can take more", cfg: AssignCfg{MaxGuides: 1},
+			setup: func(mockGuide *mock_guide_provider.MockGuideProvider) {
+				mockGuide.On("GetOperatorWorkload", mock.Anything, mock.Anything).Return(map[int]int{}, nil).Once()
+				mockGuide.On("AssignGuide", mock.Anything, 11, biz_operator.OPERATOR_SYSTEM, 2).Return(true, nil).Once()
+				mockGuide.On("GetOperatorWorkload", mock.Anything, mock.Anything).Return(map[int]int{2: 1}, nil).Once()
+			}, expectCount: 1},
+		{name: "guides taken meanwhile are skipped",
+			setup: func(mockGuide *mock_guide_provider.MockGuideProvider) {
+				mockGuide.On("GetOperatorWorkload", mock.Anything, mock.Anything).Return(map[int]int{}, nil).Times(3)
+				mockGuide.On("AssignGuide", mock.Anything, 11, biz_operator.OPERATOR_SYSTEM, 2).Return(false, nil).Once()
+				mockGuide.On("AssignGuide", mock.Anything, 12, biz_operator.OPERATOR_SYSTEM, 2).Return(true, nil).Once()
+				mockGuide.On("AssignGuide", mock.Anything, 14, biz_operator.OPERATOR_SYSTEM, 2).Return(true, nil).Once()
+			}, expectCount: 2},
+		{name: "error getting the guides", guidesErr: errors.New("db error"), setup: func(*mock_guide_provider.MockGuideProvider) {},
+			expectErr: "getting new guides: db error"},
+		{name: "error assigning stops the sweep",
+			setup: func(mockGuide *mock_guide_provider.MockGuideProvider) {
+				mockGuide.On("GetOperatorWorkload", mock.Anything, mock.Anything).Return(map[int]int{}, nil).Once()
+				mockGuide.On("AssignGuide", mock.Anything, 11, biz_operator.OPERATOR_SYSTEM, 2).Return(false, errors.New("db error")).Once()
+			}, expectErr: "assigning guide 11: db error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+
+			mockGuide.On("GetGuidesByStatus", mock.Anything, []string{biz_guide_status.INITIAL}).
+				Return(append([]model.Guide{}, guides...), tt.guidesErr).Once()
+			mockOperator.On("GetOperatorsByAvailability", mock.Anything, biz_operator.AVAILABILITY_AVAILABLE).
+				Return([]model.Operator{{ID: 2}}, nil).Maybe()
+			mockPubSub.On("Publish", mock.Anything, global.GuideAssignmentChannel, mock.Anything).Return(nil).Maybe()
+			tt.setup(mockGuide)
+
+			count, err := New(tt.cfg).Sweep(context.Background())
+
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectCount, count)
+			mockGuide.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRunSweeps(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mockOperator := new(mock_operator_provider.MockOperatorProvider)
+	operator_provider.Set(mockOperator)
+	mockGuide := new(mock_guide_provider.MockGuideProvider)
+	guide_provider.Set(mockGuide)
+	mockPubSub := new(mock_pubsub.MockPubSub)
+	pubsub.Set(mockPubSub)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan pubsub.Message, 1)
+	sub := new(mock_pubsub.MockSubscription)
+	sub.On("Channel").Return((<-chan pubsub.Message)(events))
+	sub.On("Close").Return(nil)
+	mockPubSub.On("Subscribe", mock.Anything, global.NewGuideChannel, global.OperatorAvailableChannel).Return(sub, nil).Once()
+	unassigned := []model.Guide{{ID: 10, Operator: model.Operator{ID: biz_operator.OPERATOR_SYSTEM}}}
+	swept := make(chan struct{}, 3)
+	// on subscribing nobody is available
+	mockGuide.On("GetGuidesByStatus", mock.Anything, []string{biz_guide_status.INITIAL}).Return(unassigned, nil).Once().
+		Run(func(mock.Arguments) { swept <- struct{}{} })
+	mockOperator.On("GetOperatorsByAvailability", mock.Anything, biz_operator.AVAILABILITY_AVAILABLE).
+		Return([]model.Operator{}, nil).Once()
+	mockGuide.On("GetOperatorWorkload", mock.Anything, mock.Anything).Return(map[int]int{}, nil)
+	// an operator becomes available
+	mockGuide.On("GetGuidesByStatus", mock.Anything, []string{biz_guide_status.INITIAL}).Return(unassigned, nil).Once().
+		Run(func(mock.Arguments) { swept <- struct{}{} })
+	mockOperator.On("GetOperatorsByAvailability", mock.Anything, biz_operator.AVAILABILITY_AVAILABLE).
+		Return([]model.Operator{{ID: 2}}, nil)
+	mockGuide.On("AssignGuide", mock.Anything, 10, biz_operator.OPERATOR_SYSTEM, 2).Return(true, nil).Once()
+	mockPubSub.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"10"}`).Return(nil).Once()
+	// and the ticker finds nothing left
+	mockGuide.On("GetGuidesByStatus", mock.Anything, []string{biz_guide_status.INITIAL}).Return([]model.Guide{}, nil).
+		Run(func(mock.Arguments) { swept <- struct{}{} })
+
+	assigner := New(AssignCfg{Sweep: 1})
+	done := make(chan struct{})
+	go func() {
+		assigner.Run(ctx)
+		close(done)
+	}()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-swept:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("sweep %d did not run", i+1)
+		}
+		if i == 0 {
+			events <- pubsub.Message{Channel: global.OperatorAvailableChannel, Payload: map[string]any{"operator_id": "2"}}
+		}
+	}
+	cancel()
+	<-done
+	mockGuide.AssertExpectations(t)
+	mockPubSub.AssertExpectations(t)
+}
+
+func TestRun(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	retryDelay = time.Millisecond
+	t.Cleanup(func() { retryDelay = 5 * time.Second })
+
+	mockOperator := new(mock_operator_provider.MockOperatorProvider)
+	operator_provider.Set(mockOperator)
+	mockGuide := new(mock_guide_provider.MockGuideProvider)
+	guide_provider.Set(mockGuide)
+	mockPubSub := new(mock_pubsub.MockPubSub)
+	pubsub.Set(mockPubSub)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	closed := make(chan pubsub.Message)
+	close(closed)
+	events := make(chan pubsub.Message, 3)
+	events <- pubsub.Message{Channel: global.NewGuideChannel, Payload: "invalid"}
+	events <- pubsub.Message{Channel: global.NewGuideChannel, Payload: map[string]any{"guide_id": "10"}}
+	events <- pubsub.Message{Channel: global.NewGuideChannel, Payload: map[string]any{"guide_id": "11"}}
+
+	lost := new(mock_pubsub.MockSubscription)
+	lost.On("Channel").Return((<-chan pubsub.Message)(closed))
+	lost.On("Close").Return(nil)
+	sub := new(mock_pubsub.MockSubscription)
+	sub.On("Channel").Return((<-chan pubsub.Message)(events))
+	sub.On("Close").Return(nil)
+
+	// the subscription fails, is lost and finally delivers the events
+	mockPubSub.On("Subscribe", mock.Anything, global.NewGuideChannel, global.OperatorAvailableChannel).
+		Return(nil, errors.New("redis down")).Once()
+	mockPubSub.On("Subscribe", mock.Anything, global.NewGuideChannel, global.OperatorAvailableChannel).Return(lost, nil).Once()
+	mockPubSub.On("Subscribe", mock.Anything, global.NewGuideChannel, global.OperatorAvailableChannel).Return(sub, nil).Once()
+	// every subscription sweeps the guides announced meanwhile
+	mockGuide.On("GetGuidesByStatus", mock.Anything, []string{biz_guide_status.INITIAL}).Return([]model.Guide{}, nil).Twice()
+	mockOperator.On("GetOperatorsByAvailability", mock.Anything, biz_operator.AVAILABILITY_AVAILABLE).
+		Return([]model.Operator{{ID: 2}}, nil).Once()
+	mockGuide.On("GetOperatorWorkload", mock.Anything, mock.Anything).Return(map[int]int{}, nil).Once()
+	mockGuide.On("AssignGuide", mock.Anything, 10, biz_operator.OPERATOR_SYSTEM, 2).Return(true, nil).Once()
+	mockPubSub.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"10"}`).Return(nil).Once()
+	mockOperator.On("GetOperatorsByAvailability", mock.Anything, biz_operator.AVAILABILITY_AVAILABLE).
+		Return([]model.Operator{}, errors.New("db error")).Once().
+		Run(func(mock.Arguments) { cancel() })
+
+	done := make(chan struct{})
+	go func() {
+		New(AssignCfg{}).Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("assigner did not stop")
+	}
+	mockPubSub.AssertExpectations(t)
+	mockOperator.AssertExpectations(t)
+	mockGuide.AssertExpectations(t)
+	lost.AssertExpectations(t)
+	sub.AssertExpectations(t)
+}
+
+func TestRunStopsWhileWaiting(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mockPubSub := new(mock_pubsub.MockPubSub)
+	pubsub.Set(mockPubSub)
+	ctx, cancel := context.WithCancel(context.Background())
+	mockPubSub.On("Subscribe", mock.Anything, global.NewGuideChannel, global.OperatorAvailableChannel).
+		Return(nil, errors.New("redis down")).Once().
+		Run(func(mock.Arguments) { go cancel() })
+
+	New(AssignCfg{}).Run(ctx)
+
+	mockPubSub.AssertExpectations(t)
+}
+
+func TestRunLocked(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mockPubSub := new(mock_pubsub.MockPubSub)
+	pubsub.Set(mockPubSub)
+	mockGuide := new(mock_guide_provider.MockGuideProvider)
+	guide_provider.Set(mockGuide)
+	mockGuide.On("GetGuidesByStatus", mock.Anything, []string{biz_guide_status.INITIAL}).Return([]model.Guide{}, nil).Once()
+	events := make(chan pubsub.Message)
+	sub := new(mock_pubsub.MockSubscription)
+	sub.On("Channel").Return((<-chan pubsub.Message)(events))
+	sub.On("Close").Return(nil).Once()
+	// the job context is cancelled when the scheduler lock is lost
+	ctx, cancel := context.WithCancel(context.Background())
+	mockPubSub.On("Subscribe", mock.Anything, global.NewGuideChannel, global.OperatorAvailableChannel).Return(sub, nil).Once().
+		Run(func(mock.Arguments) { go cancel() })
+
+	assert.NoError(t, New(AssignCfg{}).RunLocked(ctx))
+
+	mockPubSub.AssertExpectations(t)
+	sub.AssertExpectations(t)
+}
+
+func TestGuideIdFromPayload(t *testing.T) {
+	tests := []struct {
+		payload    any
+		expectedId int
+		expectedOk bool
+	}{
+		{map[string]any{"guide_id": "12"}, 12, true},
+		{map[string]any{"guide_id": "x"}, 0, false},
+		{map[string]any{"guide_id": "0"}, 0, false},
+		{map[string]any{"guide_id": 12}, 0, false},
+		{"12", 0, false},
+	}
+	for _, tt := range tests {
+		guideId, ok := guideIdFromPayload(tt.payload)
+		assert.Equal(t, tt.expectedId, guideId)
+		assert.Equal(t, tt.expectedOk, ok)
+	}
+}
@@ -0,0 +1,175 @@
+package biz_guide_assign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/global"
+	"via/internal/log"
+	"via/internal/model"
+	"via/internal/presence"
+	guide_provider "via/internal/provider/guide"
+	operator_provider "via/internal/provider/operator"
+	"via/internal/pubsub"
+)
+
+var (
+	ErrGuideNotFound   = errors.New("guide not found")
+	ErrOperatorInvalid = errors.New("operator can not attend guides")
+	ErrAssignedToOther = errors.New("guide assigned to another active operator")
+	ErrNotOwner        = errors.New("guide not assigned to the operator")
+)
+
+// isActive reports whether the operator is attending guides: enabled, not offline and online
+func isActive(ctx context.Context, operator model.Operator) (bool, error) {
+	if operator.ID == 0 || !operator.Enabled || operator.Availability == biz_operator.AVAILABILITY_OFFLINE {
+		return false, nil
+	}
+	online, err := onlineOperators(ctx, []model.Operator{operator})
+	return online[operator.ID], err
+}
+
+// onlineOperators reports which operators keep a feed connection open, every operator
+// is online when presence is not tracked
+func onlineOperators(ctx context.Context, operators []model.Operator) (map[int]bool, error) {
+	ids := make([]int, 0, len(operators))
+	for _, operator := range operators {
+		ids = append(ids, operator.ID)
+	}
+	if presence.Get() == nil {
+		online := map[int]bool{}
+		for _, id := range ids {
+			online[id] = true
+		}
+		return online, nil
+	}
+	online, err := presence.Get().Online(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("getting operators presence: %w", err)
+	}
+	return online, nil
+}
+
+// Take assigns the guide to the operator. A guide owned by another active operator
+// is only taken when forced.
+func Take(ctx context.Context, guideId int, operatorId int, force bool) error {
+	guide, err := getGuide(ctx, guideId)
+	if err != nil {
+		return err
+	}
+	owner := guide.Operator.ID
+	if owner == operatorId {
+		return nil
+	}
+	if owner != biz_operator.OPERATOR_SYSTEM && !force {
+		operator, err := operator_provider.Get().GetOperatorById(ctx, owner)
+		if err != nil {
+			return fmt.Errorf("getting operator %d: %w", owner, err)
+		}
+		active, err := isActive(ctx, operator)
+		if err != nil {
+			return err
+		}
+		if active {
+			return ErrAssignedToOther
+		}
+	}
+	return move(ctx, guide, operatorId)
+}
+
+// Release gives the guide back to SYSTEM. Only its owner releases it, unless forced.
+func Release(ctx context.Context, guideId int, operatorId int, force bool) error {
+	guide, err := getGuide(ctx, guideId)
+	if err != nil {
+		return err
+	}
+	if guide.Operator.ID == biz_operator.OPERATOR_SYSTEM {
+		return nil
+	}
+	if guide.Operator.ID != operatorId && !force {
+		return ErrNotOwner
+	}
+	return move(ctx, guide, biz_operator.OPERATOR_SYSTEM)
+}
+
+// Transfer hands the guide to another active operator. Only its owner transfers it, unless forced.
+func Transfer(ctx context.Context, guideId int, operatorId int, to int, force bool) error {
+	if to == biz_operator.OPERATOR_SYSTEM {
+		return ErrOperatorInvalid
+	}
+	guide, err := getGuide(ctx, guideId)
+	if err != nil {
+		return err
+	}
+	if guide.Operator.ID == to {
+		return nil
+	}
+	if guide.Operator.ID != operatorId && !force {
+		return ErrNotOwner
+	}
+	operator, err := operator_provider.Get().GetOperatorById(ctx, to)
+	if err != nil {
+		return fmt.Errorf("getting operator %d: %w", to, err)
+	}
+	active, err := isActive(ctx, operator)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return ErrOperatorInvalid
+	}
+	return move(ctx, guide, to)
+}
+
+// Override gives the guide to any enabled operator, whatever its availability and workload,
+// or back to SYSTEM. It returns the operator the guide is taken from.
+func Override(ctx context.Context, guideId int, to int) (int, error) {
+	guide, err := getGuide(ctx, guideId)
+	if err != nil {
+		return 0, err
+	}
+	if guide.Operator.ID == to {
+		return to, nil
+	}
+	if to != biz_operator.OPERATOR_SYSTEM {
+		operator, err := operator_provider.Get().GetOperatorById(ctx, to)
+		if err != nil {
+			return 0, fmt.Errorf("getting operator %d: %w", to, err)
+		}
+		if operator.ID == 0 || !operator.Enabled {
+			return 0, ErrOperatorInvalid
+		}
+	}
+	return guide.Operator.ID, move(ctx, guide, to)
+}
+
+func getGuide(ctx context.Context, guideId int) (model.Guide, error) {
+	guide, err := guide_provider.Get().GetGuideById(ctx, guideId)
+	if err != nil {
+		return guide, fmt.Errorf("getting guide %d: %w", guideId, err)
+	}
+	if guide.ID == 0 {
+		return guide, ErrGuideNotFound
+	}
+	return guide, nil
+}
+
+// move changes the owner of the guide, the history trigger records it. A guide whose owner
+// changed since it was read is left alone.
+func move(ctx context.Context, guide model.Guide, to int) error {
+	moved, err := guide_provider.Get().AssignGuide(ctx, guide.ID, guide.Operator.ID, to)
+	if err != nil {
+		return fmt.Errorf("assigning guide %d: %w", guide.ID, err)
+	}
+	if !moved {
+		return ErrAssignedToOther
+	}
+	log.Get().Info(ctx, "msg", "guide operator changed", "guide_id", guide.ID, "from_operator_id", guide.Operator.ID,
+		"operator_id", to)
+	err = pubsub.Get().Publish(ctx, global.GuideAssignmentChannel, fmt.Sprintf("{\"guide_id\":\"%d\"}", guide.ID))
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "unable to publish event", "channel", global.GuideAssignmentChannel)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package biz_guide_assign
+
+import (
+	"context"
+	"errors"
+	"testing"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/ds"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/global"
+	"via/internal/model"
+	"via/internal/presence"
+	guide_provider "via/internal/provider/guide"
+	mock_guide_provider "via/internal/provider/guide/mock"
+	operator_provider "via/internal/provider/operator"
+	mock_operator_provider "via/internal/provider/operator/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type ownershipMocks struct {
+	guide    *mock_guide_provider.MockGuideProvider
+	operator *mock_operator_provider.MockOperatorProvider
+	pubsub   *mock_pubsub.MockPubSub
+}
+
+func setupOwnershipMocks(t *testing.T, owner int) ownershipMocks {
+	testutil.InjectNoOpLogger()
+	m := ownershipMocks{
+		guide:    new(mock_guide_provider.MockGuideProvider),
+		operator: new(mock_operator_provider.MockOperatorProvider),
+		pubsub:   new(mock_pubsub.MockPubSub),
+	}
+	guide_provider.Set(m.guide)
+	operator_provider.Set(m.operator)
+	pubsub.Set(m.pubsub)
+	if owner != 0 {
+		m.guide.On("GetGuideById", mock.Anything, 10).
+			Return(model.Guide{ID: 10, Operator: model.Operator{ID: owner}}, nil).Once()
+	} else {
+		m.guide.On("GetGuideById", mock.Anything, 10).Return(model.Guide{}, nil).Once()
+	}
+	t.Cleanup(func() {
+		m.guide.AssertExpectations(t)
+		m.operator.AssertExpectations(t)
+		m.pubsub.AssertExpectations(t)
+	})
+	return m
+}
+
+func (m ownershipMocks) expectMove(from, to int, moved bool, err error) {
+	m.guide.On("AssignGuide", mock.Anything, 10, from, to).Return(moved, err).Once()
+	if moved {
+		m.pubsub.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"10"}`).Return(nil).Once()
+	}
+}
+
+// trackPresence reports the operator 3 presence, presence is not tracked by default
+func trackPresence(t *testing.T, online bool, err error) {
+	mockDS := new(mock_ds.MockDS)
+	ds.Set(mockDS)
+	presence.Set(presence.New(presence.PresenceCfg{}))
+	connections := []string{}
+	if online {
+		connections = append(connections, "a")
+	}
+	mockDS.On("SMembers", mock.Anything, "presence:operator:3:connections").Return(connections, err).Once()
+	t.Cleanup(func() {
+		presence.Set(nil)
+		mockDS.AssertExpectations(t)
+	})
+}
+
+var (
+	activeOperator  = model.Operator{ID: 3, Enabled: true, Availability: biz_operator.AVAILABILITY_ON_BREAK}
+	offlineOperator = model.Operator{ID: 3, Enabled: true, Availability: biz_operator.AVAILABILITY_OFFLINE}
+)
+
+func TestTake(t *testing.T) {
+	t.Run("unattended guide", func(t *testing.T) {
+		m := setupOwnershipMocks(t, biz_operator.OPERATOR_SYSTEM)
+		m.expectMove(biz_operator.OPERATOR_SYSTEM, 2, true, nil)
+		assert.NoError(t, Take(context.Background(), 10, 2, false))
+	})
+	t.Run("already owned", func(t *testing.T) {
+		setupOwnershipMocks(t, 2)
+		assert.NoError(t, Take(context.Background(), 10, 2, false))
+	})
+	t.Run("owned by an active operator", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 3)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		assert.ErrorIs(t, Take(context.Background(), 10, 2, false), ErrAssignedToOther)
+	})
+	t.Run("owned by an offline operator", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 3)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(offlineOperator, nil).Once()
+		m.expectMove(3, 2, true, nil)
+		assert.NoError(t, Take(context.Background(), 10, 2, false))
+	})
+	t.Run("owned by an online operator", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 3)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		trackPresence(t, true, nil)
+		assert.ErrorIs(t, Take(context.Background(), 10, 2, false), ErrAssignedToOther)
+	})
+	t.Run("owned by a disconnected operator", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 3)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		trackPresence(t, false, nil)
+		m.expectMove(3, 2, true, nil)
+		assert.NoError(t, Take(context.Background(), 10, 2, false))
+	})
+	t.Run("error getting owner presence", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 3)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		trackPresence(t, false, errors.New("ds error"))
+		assert.EqualError(t, Take(context.Background(), 10, 2, false),
+			"getting operators presence: getting operator 3 presence: ds error")
+	})
+	t.Run("forced", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 3)
+		m.expectMove(3, 2, true, nil)
+		assert.NoError(t, Take(context.Background(), 10, 2, true))
+	})
+	t.Run("taken in the meantime", func(t *testing.T) {
+		m := setupOwnershipMocks(t, biz_operator.OPERATOR_SYSTEM)
+		m.expectMove(biz_operator.OPERATOR_SYSTEM, 2, false, nil)
+		assert.ErrorIs(t, Take(context.Background(), 10, 2, false), ErrAssignedToOther)
+	})
+	t.Run("error getting owner", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 3)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{}, errors.New("db error")).Once()
+		assert.EqualError(t, Take(context.Background(), 10, 2, false), "getting operator 3: db error")
+	})
+	t.Run("error assigning", func(t *testing.T) {
+		m := setupOwnershipMocks(t, biz_operator.OPERATOR_SYSTEM)
+		m.expectMove(biz_operator.OPERATOR_SYSTEM, 2, false, errors.New("db error"))
+		assert.EqualError(t, Take(context.Background(), 10, 2, false), "assigning guide 10: db error")
+	})
+	t.Run("guide not found", func(t *testing.T) {
+		setupOwnershipMocks(t, 0)
+		assert.ErrorIs(t, Take(context.Background(), 10, 2, false), ErrGuideNotFound)
+	})
+	t.Run("error getting guide", func(t *testing.T) {
+		testutil.InjectNoOpLogger()
+		mockGuide := new(mock_guide_provider.MockGuideProvider)
+		guide_provider.Set(mockGuide)
+		mockGuide.On("GetGuideById", mock.Anything, 10).Return(model.Guide{}, errors.New("db error")).Once()
+		assert.EqualError(t, Take(context.Background(), 10, 2, false), "getting guide 10: db error")
+	})
+}
+
+func TestRelease(t *testing.T) {
+	t.Run("owner releases", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.expectMove(2, biz_operator.OPERATOR_SYSTEM, true, nil)
+		assert.NoError(t, Release(context.Background(), 10, 2, false))
+	})
+	t.Run("already released", func(t *testing.T) {
+		setupOwnershipMocks(t, biz_operator.OPERATOR_SYSTEM)
+		assert.NoError(t, Release(context.Background(), 10, 2, false))
+	})
+	t.Run("not the owner", func(t *testing.T) {
+		setupOwnershipMocks(t, 3)
+		assert.ErrorIs(t, Release(context.Background(), 10, 2, false), ErrNotOwner)
+	})
+	t.Run("forced", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 3)
+		m.expectMove(3, biz_operator.OPERATOR_SYSTEM, true, nil)
+		assert.NoError(t, Release(context.Background(), 10, 2, true))
+	})
+	t.Run("guide not found", func(t *testing.T) {
+		setupOwnershipMocks(t, 0)
+		assert.ErrorIs(t, Release(context.Background(), 10, 2, false), ErrGuideNotFound)
+	})
+}
+
+func TestTransfer(t *testing.T) {
+	t.Run("owner transfers", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		m.expectMove(2, 3, true, nil)
+		assert.NoError(t, Transfer(context.Background(), 10, 2, 3, false))
+	})
+	t.Run("to SYSTEM", func(t *testing.T) {
+		assert.ErrorIs(t, Transfer(context.Background(), 10, 2, biz_operator.OPERATOR_SYSTEM, false), ErrOperatorInvalid)
+	})
+	t.Run("already owned by the target", func(t *testing.T) {
+		setupOwnershipMocks(t, 3)
+		assert.NoError(t, Transfer(context.Background(), 10, 2, 3, false))
+	})
+	t.Run("not the owner", func(t *testing.T) {
+		setupOwnershipMocks(t, 4)
+		assert.ErrorIs(t, Transfer(context.Background(), 10, 2, 3, false), ErrNotOwner)
+	})
+	t.Run("forced", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 4)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		m.expectMove(4, 3, true, nil)
+		assert.NoError(t, Transfer(context.Background(), 10, 2, 3, true))
+	})
+	t.Run("offline target", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(offlineOperator, nil).Once()
+		assert.ErrorIs(t, Transfer(context.Background(), 10, 2, 3, false), ErrOperatorInvalid)
+	})
+	t.Run("disconnected target", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		trackPresence(t, false, nil)
+		assert.ErrorIs(t, Transfer(context.Background(), 10, 2, 3, false), ErrOperatorInvalid)
+	})
+	t.Run("error getting target presence", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		trackPresence(t, false, errors.New("ds error"))
+		assert.EqualError(t, Transfer(context.Background(), 10, 2, 3, false),
+			"getting operators presence: getting operator 3 presence: ds error")
+	})
+	t.Run("unknown target", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{}, nil).Once()
+		assert.ErrorIs(t, Transfer(context.Background(), 10, 2, 3, false), ErrOperatorInvalid)
+	})
+	t.Run("error getting target", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{}, errors.New("db error")).Once()
+		assert.EqualError(t, Transfer(context.Background(), 10, 2, 3, false), "getting operator 3: db error")
+	})
+	t.Run("guide not found", func(t *testing.T) {
+		setupOwnershipMocks(t, 0)
+		assert.ErrorIs(t, Transfer(context.Background(), 10, 2, 3, false), ErrGuideNotFound)
+	})
+}
+
+func TestOverride(t *testing.T) {
+	t.Run("to an operator on break", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		m.expectMove(2, 3, true, nil)
+		previous, err := Override(context.Background(), 10, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, previous)
+	})
+	t.Run("to an offline operator", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(offlineOperator, nil).Once()
+		m.expectMove(2, 3, true, nil)
+		_, err := Override(context.Background(), 10, 3)
+		assert.NoError(t, err)
+	})
+	t.Run("to SYSTEM", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.expectMove(2, biz_operator.OPERATOR_SYSTEM, true, nil)
+		_, err := Override(context.Background(), 10, biz_operator.OPERATOR_SYSTEM)
+		assert.NoError(t, err)
+	})
+	t.Run("already owned by the target", func(t *testing.T) {
+		setupOwnershipMocks(t, 3)
+		previous, err := Override(context.Background(), 10, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, previous)
+	})
+	t.Run("owner changed meanwhile", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(activeOperator, nil).Once()
+		m.expectMove(2, 3, false, nil)
+		_, err := Override(context.Background(), 10, 3)
+		assert.ErrorIs(t, err, ErrAssignedToOther)
+	})
+	t.Run("disabled target", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{ID: 3}, nil).Once()
+		_, err := Override(context.Background(), 10, 3)
+		assert.ErrorIs(t, err, ErrOperatorInvalid)
+	})
+	t.Run("error getting target", func(t *testing.T) {
+		m := setupOwnershipMocks(t, 2)
+		m.operator.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{}, errors.New("db error")).Once()
+		_, err := Override(context.Background(), 10, 3)
+		assert.EqualError(t, err, "getting operator 3: db error")
+	})
+	t.Run("guide not found", func(t *testing.T) {
+		setupOwnershipMocks(t, 0)
+		_, err := Override(context.Background(), 10, 3)
+		assert.ErrorIs(t, err, ErrGuideNotFound)
+	})
+}
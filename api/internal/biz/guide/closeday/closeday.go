@@ -0,0 +1,92 @@
+package biz_guide_closeday
+
+import (
+	"context"
+	"fmt"
+	"time"
+	biz_guide_status "via/internal/biz/guide/status"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/global"
+	"via/internal/log"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	"via/internal/pubsub"
+)
+
+type CloseDayCfg struct {
+	Status      string `env:"STATUS" envDefault:"onHold" json:"status"`        // onHold or suspended
+	ArchiveDays int    `env:"ARCHIVE_DAYS" envDefault:"90" json:"archiveDays"` // 0 disables the archival
+}
+
+var now = time.Now
+
+// Closer closes the business day: the guides still in process are left on hold, or suspended,
+// and the guides closed for longer than the archive window leave the hot tables
+type Closer struct {
+	cfg CloseDayCfg
+}
+
+func New(cfg CloseDayCfg) *Closer {
+	if cfg.Status != biz_guide_status.SUSPENDED {
+		cfg.Status = biz_guide_status.ON_HOLD
+	}
+	return &Closer{cfg: cfg}
+}
+
+// Close returns the summary of the open guides at close time. A guide failing to close is counted
+// and the others go on, an archival failure is returned along with the summary.
+func (c *Closer) Close(ctx context.Context) (model.DaySummary, error) {
+	logger := log.Get()
+	summary := model.DaySummary{
+		ClosedAt:    now(),
+		PerStatus:   map[string]int{},
+		PerOperator: map[string]int{},
+		Unresolved:  []model.UnresolvedGuide{},
+	}
+	guides, err := guide_provider.Get().GetGuidesByStatus(ctx, biz_guide_status.GetOperatorStatus())
+	if err != nil {
+		return summary, fmt.Errorf("getting open guides: %w", err)
+	}
+
+	for _, guide := range guides {
+		summary.PerStatus[guide.Status]++
+		summary.PerOperator[guide.Operator.Account]++
+		if !biz_guide_status.IsInProcess(guide.Status) {
+			continue
+		}
+		summary.Unresolved = append(summary.Unresolved, model.UnresolvedGuide{
+			GuideId:    guide.ID,
+			ViaGuideId: guide.ViaGuideID,
+			Recipient:  guide.Recipient,
+			Status:     guide.Status,
+			Operator:   guide.Operator,
+			LastChange: guide.UpdatedAt,
+		})
+		err := guide_provider.Get().UpdateGuide(ctx, model.Guide{
+			ID:           guide.ID,
+			Status:       c.cfg.Status,
+			StatusReason: biz_guide_status.REASON_DAY_CLOSED,
+			Operator:     model.Operator{ID: biz_operator.OPERATOR_SYSTEM},
+		})
+		if err != nil {
+			logger.Error(ctx, err, "msg", "unable to close guide", "guide_id", guide.ID)
+			summary.Failed++
+			continue
+		}
+		summary.Closed++
+		if err := pubsub.Get().Publish(ctx, global.GuideStatusChangeChannel, fmt.Sprintf("{\"guide_id\":\"%d\"}", guide.ID)); err != nil {
+			logger.Error(ctx, err, "msg", "unable to publish guide close", "guide_id", guide.ID)
+		}
+	}
+	logger.Info(ctx, "msg", "day closed", "open", len(guides), "closed", summary.Closed, "failed", summary.Failed)
+
+	if c.cfg.ArchiveDays <= 0 {
+		return summary, nil
+	}
+	before := summary.ClosedAt.AddDate(0, 0, -c.cfg.ArchiveDays)
+	summary.Archived, err = guide_provider.Get().ArchiveGuides(ctx, biz_guide_status.GetArchivableStatus(), before)
+	if err != nil {
+		return summary, fmt.Errorf("archiving guides: %w", err)
+	}
+	return summary, nil
+}
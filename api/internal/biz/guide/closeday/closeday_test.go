@@ -0,0 +1,129 @@
+package biz_guide_closeday
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	biz_guide_status "via/internal/biz/guide/status"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/global"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	mock_guide_provider "via/internal/provider/guide/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew(t *testing.T) {
+	assert.Equal(t, biz_guide_status.SUSPENDED, New(CloseDayCfg{Status: biz_guide_status.SUSPENDED}).cfg.Status)
+	assert.Equal(t, biz_guide_status.ON_HOLD, New(CloseDayCfg{Status: biz_guide_status.DELIVERED}).cfg.Status)
+}
+
+func TestClose(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	fixedNow := time.Date(2025, 3, 13, 20, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixedNow }
+	t.Cleanup(func() { now = time.Now })
+
+	ana := model.Operator{ID: 2, Account: "ana"}
+	system := model.Operator{ID: biz_operator.OPERATOR_SYSTEM, Account: "SYSTEM"}
+	open := []model.Guide{
+		{ID: 1, ViaGuideID: "100000000001", Status: biz_guide_status.INITIAL, Operator: system, UpdatedAt: fixedNow},
+		{ID: 2, ViaGuideID: "100000000002", Status: biz_guide_status.PENDING_PAYMENT, Operator: ana, UpdatedAt: fixedNow},
+		{ID: 3, ViaGuideID: "100000000003", Status: biz_guide_status.ON_HOLD, Operator: ana, UpdatedAt: fixedNow},
+	}
+	unresolved := []model.UnresolvedGuide{
+		{GuideId: 1, ViaGuideId: "100000000001", Status: biz_guide_status.INITIAL, Operator: system, LastChange: fixedNow},
+		{GuideId: 2, ViaGuideId: "100000000002", Status: biz_guide_status.PENDING_PAYMENT, Operator: ana, LastChange: fixedNow},
+	}
+	perStatus := map[string]int{biz_guide_status.INITIAL: 1, biz_guide_status.PENDING_PAYMENT: 1, biz_guide_status.ON_HOLD: 1}
+	perOperator := map[string]int{"SYSTEM": 1, "ana": 2}
+	archiveBefore := fixedNow.AddDate(0, 0, -30)
+
+	tests := []struct {
+		name          string
+		archiveDays   int
+		openErr       error
+		updateErr     error
+		publishErr    error
+		archiveErr    error
+		expectErr     string
+		expectSummary model.DaySummary
+	}{
+		{
+			name:        "day closed and guides archived",
+			archiveDays: 30,
+			expectSummary: model.DaySummary{ClosedAt: fixedNow, PerStatus: perStatus, PerOperator: perOperator,
+				Unresolved: unresolved, Closed: 2, Archived: 4},
+		},
+		{
+			name: "archival disabled",
+			expectSummary: model.DaySummary{ClosedAt: fixedNow, PerStatus: perStatus, PerOperator: perOperator,
+				Unresolved: unresolved, Closed: 2},
+		},
+		{
+			name:       "publish error is only logged",
+			publishErr: errors.New("pubsub error"),
+			expectSummary: model.DaySummary{ClosedAt: fixedNow, PerStatus: perStatus, PerOperator: perOperator,
+				Unresolved: unresolved, Closed: 2},
+		},
+		{
+			name:      "failing guides are counted",
+			updateErr: errors.New("db error"),
+			expectSummary: model.DaySummary{ClosedAt: fixedNow, PerStatus: perStatus, PerOperator: perOperator,
+				Unresolved: unresolved, Failed: 2},
+		},
+		{
+			name:        "archival error",
+			archiveDays: 30,
+			archiveErr:  errors.New("db error"),
+			expectErr:   "archiving guides: db error",
+			expectSummary: model.DaySummary{ClosedAt: fixedNow, PerStatus: perStatus, PerOperator: perOperator,
+				Unresolved: unresolved, Closed: 2},
+		},
+		{
+			name:      "error getting open guides",
+			openErr:   errors.New("db error"),
+			expectErr: "getting open guides: db error",
+			expectSummary: model.DaySummary{ClosedAt: fixedNow, PerStatus: map[string]int{}, PerOperator: map[string]int{},
+				Unresolved: []model.UnresolvedGuide{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+
+			mockGuide.On("GetGuidesByStatus", mock.Anything, biz_guide_status.GetOperatorStatus()).Return(open, tt.openErr)
+			for _, id := range []int{1, 2} {
+				mockGuide.On("UpdateGuide", mock.Anything, model.Guide{ID: id, Status: biz_guide_status.ON_HOLD,
+					StatusReason: biz_guide_status.REASON_DAY_CLOSED, Operator: model.Operator{ID: biz_operator.OPERATOR_SYSTEM}}).
+					Return(tt.updateErr)
+			}
+			mockGuide.On("ArchiveGuides", mock.Anything, biz_guide_status.GetArchivableStatus(), archiveBefore).
+				Return(tt.expectSummary.Archived, tt.archiveErr)
+			mockPubSub.On("Publish", mock.Anything, global.GuideStatusChangeChannel, mock.Anything).Return(tt.publishErr)
+
+			summary, err := New(CloseDayCfg{ArchiveDays: tt.archiveDays}).Close(context.Background())
+
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectSummary, summary)
+			mockGuide.AssertNotCalled(t, "UpdateGuide", mock.Anything, mock.MatchedBy(func(g model.Guide) bool { return g.ID == 3 }))
+			if tt.archiveDays == 0 {
+				mockGuide.AssertNotCalled(t, "ArchiveGuides", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
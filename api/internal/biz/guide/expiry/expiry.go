@@ -0,0 +1,76 @@
+package biz_guide_expiry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	biz_guide_status "via/internal/biz/guide/status"
+	"via/internal/global"
+	"via/internal/log"
+	guide_provider "via/internal/provider/guide"
+	"via/internal/pubsub"
+)
+
+type ExpiryCfg struct {
+	Enabled  bool           `env:"ENABLED" envDefault:"true" json:"enabled"`
+	Interval int            `env:"INTERVAL" envDefault:"60" json:"interval"`                                         // in seconds
+	Timeouts map[string]int `env:"TIMEOUTS" envDefault:"initial:1800,pendingRecipientIdentify:1800" json:"timeouts"` // status:seconds
+}
+
+var now = time.Now
+
+// Expirer puts on hold the guides left untouched in a status longer than its timeout
+type Expirer struct {
+	cfg ExpiryCfg
+}
+
+func New(cfg ExpiryCfg) *Expirer {
+	return &Expirer{cfg: cfg}
+}
+
+// Run expires the guides of every configured status, a failing guide does not stop the others
+func (e *Expirer) Run(ctx context.Context) error {
+	logger := log.Get()
+	statuses := make([]string, 0, len(e.cfg.Timeouts))
+	for status := range e.cfg.Timeouts {
+		statuses = append(statuses, status)
+	}
+	slices.Sort(statuses)
+
+	var errs []error
+	for _, status := range statuses {
+		timeout := e.cfg.Timeouts[status]
+		// only guides in process can be put on hold
+		if timeout <= 0 || !biz_guide_status.IsInProcess(status) {
+			logger.Warn(ctx, "msg", "ignoring expiry timeout", "status", status, "timeout", timeout)
+			continue
+		}
+		before := now().Add(-time.Duration(timeout) * time.Second)
+		guides, err := guide_provider.Get().GetGuidesByStatusUpdatedBefore(ctx, []string{status}, before)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("getting %s guides: %w", status, err))
+			continue
+		}
+		for _, guide := range guides {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			expired, err := guide_provider.Get().ExpireGuide(ctx, guide.ID, status, before, biz_guide_status.REASON_EXPIRED)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("expiring guide %d: %w", guide.ID, err))
+				continue
+			}
+			if !expired {
+				logger.Debug(ctx, "msg", "guide changed before expiring", "guide_id", guide.ID)
+				continue
+			}
+			logger.Info(ctx, "msg", "guide expired", "guide_id", guide.ID, "status", status, "updated_at", guide.UpdatedAt)
+			if err := pubsub.Get().Publish(ctx, global.GuideStatusChangeChannel, fmt.Sprintf("{\"guide_id\":\"%d\"}", guide.ID)); err != nil {
+				logger.Error(ctx, err, "msg", "unable to publish guide expiry", "guide_id", guide.ID)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
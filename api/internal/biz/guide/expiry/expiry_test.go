@@ -0,0 +1,100 @@
+package biz_guide_expiry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	biz_guide_status "via/internal/biz/guide/status"
+	"via/internal/global"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	mock_guide_provider "via/internal/provider/guide/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRun(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	fixedNow := time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixedNow }
+	t.Cleanup(func() { now = time.Now })
+
+	cfg := ExpiryCfg{Timeouts: map[string]int{
+		biz_guide_status.INITIAL:                    600,
+		biz_guide_status.PENDING_RECIPIENT_IDENTIFY: 1800,
+		biz_guide_status.DELIVERED:                  600, // ignored, can not be put on hold
+		biz_guide_status.PAID:                       0,   // ignored, disabled
+	}}
+	initialBefore := fixedNow.Add(-10 * time.Minute)
+	pendingBefore := fixedNow.Add(-30 * time.Minute)
+
+	tests := []struct {
+		name          string
+		initialErr    error
+		expireErr     error
+		changed       bool
+		publishErr    error
+		expectErr     string
+		expectPublish bool
+	}{
+		{name: "guides expired", expectPublish: true},
+		{name: "publish error is only logged", publishErr: errors.New("pubsub error"), expectPublish: true},
+		{name: "guide changed before expiring", changed: true},
+		{name: "error expiring guide", expireErr: errors.New("db error"), expectErr: "expiring guide 2: db error"},
+		{name: "error getting guides", initialErr: errors.New("db error"), expectErr: "getting initial guides: db error", expectPublish: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+
+			mockGuide.On("GetGuidesByStatusUpdatedBefore", mock.Anything, []string{biz_guide_status.INITIAL}, initialBefore).
+				Return([]model.Guide{{ID: 1, Status: biz_guide_status.INITIAL}}, tt.initialErr)
+			mockGuide.On("GetGuidesByStatusUpdatedBefore", mock.Anything, []string{biz_guide_status.PENDING_RECIPIENT_IDENTIFY}, pendingBefore).
+				Return([]model.Guide{{ID: 2, Status: biz_guide_status.PENDING_RECIPIENT_IDENTIFY}}, nil)
+			mockGuide.On("ExpireGuide", mock.Anything, 1, biz_guide_status.INITIAL, initialBefore, biz_guide_status.REASON_EXPIRED).
+				Return(!tt.changed, nil)
+			mockGuide.On("ExpireGuide", mock.Anything, 2, biz_guide_status.PENDING_RECIPIENT_IDENTIFY, pendingBefore, biz_guide_status.REASON_EXPIRED).
+				Return(!tt.changed && tt.expireErr == nil, tt.expireErr)
+			mockPubSub.On("Publish", mock.Anything, global.GuideStatusChangeChannel, mock.Anything).Return(tt.publishErr)
+
+			err := New(cfg).Run(context.Background())
+
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectPublish {
+				mockPubSub.AssertCalled(t, "Publish", mock.Anything, global.GuideStatusChangeChannel, `{"guide_id":"2"}`)
+			} else {
+				mockPubSub.AssertNotCalled(t, "Publish", mock.Anything, global.GuideStatusChangeChannel, `{"guide_id":"2"}`)
+			}
+			mockGuide.AssertNotCalled(t, "GetGuidesByStatusUpdatedBefore", mock.Anything, []string{biz_guide_status.DELIVERED}, mock.Anything)
+			mockGuide.AssertNotCalled(t, "GetGuidesByStatusUpdatedBefore", mock.Anything, []string{biz_guide_status.PAID}, mock.Anything)
+		})
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mockGuide := new(mock_guide_provider.MockGuideProvider)
+	guide_provider.Set(mockGuide)
+	mockGuide.On("GetGuidesByStatusUpdatedBefore", mock.Anything, mock.Anything, mock.Anything).
+		Return([]model.Guide{{ID: 1}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := New(ExpiryCfg{Timeouts: map[string]int{biz_guide_status.INITIAL: 60}}).Run(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	mockGuide.AssertNotCalled(t, "ExpireGuide", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,134 @@
+package biz_guide_reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	biz_config "via/internal/biz/config"
+	biz_guide_status "via/internal/biz/guide/status"
+	"via/internal/cache"
+	"via/internal/ds"
+	"via/internal/log"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	via_guide_provider "via/internal/provider/via/guide"
+)
+
+type ReconcileCfg struct {
+	Enabled           bool `env:"ENABLED" envDefault:"true" json:"enabled"`
+	Interval          int  `env:"INTERVAL" envDefault:"300" json:"interval"`                      // in seconds
+	DeliveredLookback int  `env:"DELIVERED_LOOKBACK" envDefault:"86400" json:"deliveredLookback"` // in seconds
+	ReportTTL         int  `env:"REPORT_TTL" envDefault:"86400" json:"reportTtl"`                 // in seconds
+}
+
+// Discrepancy reasons, described by biz_guide_status.GetReasonDescription
+const (
+	DELIVERED_AT_CARRIER     = biz_guide_status.REASON_DELIVERED_AT_CARRIER
+	NOT_DELIVERED_AT_CARRIER = biz_guide_status.REASON_NOT_DELIVERED_AT_CARRIER
+	NOT_FOUND_AT_CARRIER     = biz_guide_status.REASON_NOT_FOUND_AT_CARRIER
+	NOT_AT_BRANCH            = biz_guide_status.REASON_NOT_AT_BRANCH
+	WRONG_BRANCH             = biz_guide_status.REASON_WRONG_BRANCH
+)
+
+const reportKey = "reconcile:report"
+
+// Compare returns the reason why the guide and its carrier state disagree, empty when they agree
+func Compare(guide model.Guide, viaGuide model.ViaGuide, biz biz_config.BussinessCfg) string {
+	if viaGuide.ID == "" {
+		return NOT_FOUND_AT_CARRIER
+	}
+	deliveredAtCarrier := viaGuide.Status == biz.DeliveredStatus
+	switch {
+	case biz_guide_status.IsDelivered(guide.Status):
+		if !deliveredAtCarrier {
+			return NOT_DELIVERED_AT_CARRIER
+		}
+	case guide.Status == biz_guide_status.PARTIAL_DELIVERED:
+		// the carrier keeps the guide open until every package is withdrawn
+	case deliveredAtCarrier:
+		return DELIVERED_AT_CARRIER
+	case viaGuide.Destination.ID != biz.ViaBranch:
+		return WRONG_BRANCH
+	case slices.Contains(strings.Split(biz.PendingStatus, ","), viaGuide.Status):
+		return NOT_AT_BRANCH
+	}
+	return ""
+}
+
+var now = time.Now
+
+// Reconciler checks open and recently delivered guides against the carrier
+type Reconciler struct {
+	cfg ReconcileCfg
+	biz biz_config.BussinessCfg
+}
+
+func New(cfg ReconcileCfg, biz biz_config.BussinessCfg) *Reconciler {
+	return &Reconciler{cfg: cfg, biz: biz}
+}
+
+// Run builds a new report, when the carrier becomes unavailable the run is aborted
+// and the previous report is kept
+func (r *Reconciler) Run(ctx context.Context) error {
+	logger := log.Get()
+	open, err := guide_provider.Get().GetGuidesByStatus(ctx, biz_guide_status.GetOperatorStatus())
+	if err != nil {
+		return fmt.Errorf("getting open guides: %w", err)
+	}
+	delivered, err := guide_provider.Get().GetGuidesByStatusUpdatedAfter(ctx, []string{biz_guide_status.DELIVERED},
+		now().Add(-time.Duration(r.cfg.DeliveredLookback)*time.Second))
+	if err != nil {
+		return fmt.Errorf("getting delivered guides: %w", err)
+	}
+
+	report := model.ReconciliationReport{RunAt: now(), Discrepancies: []model.Discrepancy{}}
+	for _, guide := range append(open, delivered...) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// the cached and stale guides would report the carrier state of an hour ago
+		viaGuide, err := via_guide_provider.Get().GetGuide(via_guide_provider.WithoutCache(ctx), guide.ViaGuideID)
+		if errors.Is(err, via_guide_provider.ErrUnavailable) {
+			return fmt.Errorf("reconciliation aborted after %d guides: %w", report.Checked, err)
+		}
+		if err != nil {
+			logger.Error(ctx, err, "msg", "unable to reconcile guide", "guide_id", guide.ID)
+			report.Failed++
+			continue
+		}
+		report.Checked++
+		if reason := Compare(guide, viaGuide, r.biz); reason != "" {
+			logger.Warn(ctx, "msg", "guide discrepancy", "guide_id", guide.ID, "status", guide.Status,
+				"via_status", viaGuide.Status, "reason", reason)
+			report.Discrepancies = append(report.Discrepancies, model.Discrepancy{
+				GuideId:    guide.ID,
+				ViaGuideId: guide.ViaGuideID,
+				Status:     guide.Status,
+				ViaStatus:  viaGuide.Status,
+				Reason:     reason,
+				Operator:   guide.Operator,
+				DetectedAt: report.RunAt,
+			})
+		}
+	}
+
+	if err := cache.New(ds.Get()).Set(ctx, reportKey, report, r.cfg.ReportTTL); err != nil {
+		return fmt.Errorf("storing reconciliation report: %w", err)
+	}
+	logger.Info(ctx, "msg", "reconciliation finished", "checked", report.Checked, "failed", report.Failed,
+		"discrepancies", len(report.Discrepancies))
+	return nil
+}
+
+// GetReport returns the last reconciliation report
+func GetReport(ctx context.Context) (model.ReconciliationReport, bool, error) {
+	var report model.ReconciliationReport
+	found, err := cache.New(ds.Get()).Get(ctx, reportKey, &report)
+	if err != nil || !found {
+		return model.ReconciliationReport{}, false, err
+	}
+	return report, true, nil
+}
@@ -0,0 +1,198 @@
+package biz_guide_reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	biz_config "via/internal/biz/config"
+	biz_guide_status "via/internal/biz/guide/status"
+	"via/internal/cache"
+	"via/internal/ds"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	mock_guide_provider "via/internal/provider/guide/mock"
+	via_guide_provider "via/internal/provider/via/guide"
+	mock_via_guide_provider "via/internal/provider/via/guide/mock"
+	via_guide_resilient_provider "via/internal/provider/via/guide/resilient"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var biz = biz_config.BussinessCfg{ViaBranch: "1757", WithdrawStatus: "CRR", DeliveredStatus: "ENT", PendingStatus: "ASP,PTE"}
+
+func TestCompare(t *testing.T) {
+	atBranch := model.ViaDestination{ID: "1757"}
+	tests := []struct {
+		name     string
+		status   string
+		viaGuide model.ViaGuide
+		expected string
+	}{
+		{"open and ready at carrier", biz_guide_status.PENDING_COUNTER_DELIVERY, model.ViaGuide{ID: "1", Status: "CRR", Destination: atBranch}, ""},
+		{"not found at carrier", biz_guide_status.INITIAL, model.ViaGuide{}, NOT_FOUND_AT_CARRIER},
+		{"open but delivered at carrier", biz_guide_status.PENDING_COUNTER_DELIVERY, model.ViaGuide{ID: "1", Status: "ENT", Destination: atBranch}, DELIVERED_AT_CARRIER},
+		{"delivered but open at carrier", biz_guide_status.DELIVERED, model.ViaGuide{ID: "1", Status: "CRR", Destination: atBranch}, NOT_DELIVERED_AT_CARRIER},
+		{"delivered at both", biz_guide_status.DELIVERED, model.ViaGuide{ID: "1", Status: "ENT", Destination: atBranch}, ""},
+		{"partial delivered open at carrier", biz_guide_status.PARTIAL_DELIVERED, model.ViaGuide{ID: "1", Status: "CRR", Destination: atBranch}, ""},
+		{"open with other destination", biz_guide_status.ON_HOLD, model.ViaGuide{ID: "1", Status: "CRR", Destination: model.ViaDestination{ID: "8000"}}, WRONG_BRANCH},
+		{"open but pending at carrier", biz_guide_status.INITIAL, model.ViaGuide{ID: "1", Status: "PTE", Destination: atBranch}, NOT_AT_BRANCH},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Compare(model.Guide{Status: tt.status}, tt.viaGuide, biz))
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	fixedNow := time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixedNow }
+	t.Cleanup(func() { now = time.Now })
+
+	cfg := ReconcileCfg{DeliveredLookback: 3600, ReportTTL: 600}
+	open := []model.Guide{
+		{ID: 1, ViaGuideID: "100000000001", Status: biz_guide_status.PENDING_COUNTER_DELIVERY},
+		{ID: 2, ViaGuideID: "100000000002", Status: biz_guide_status.INITIAL},
+	}
+	delivered := []model.Guide{{ID: 3, ViaGuideID: "100000000003", Status: biz_guide_status.DELIVERED}}
+	viaGuides := map[string]model.ViaGuide{
+		"100000000001": {ID: "100000000001", Status: "ENT", Destination: model.ViaDestination{ID: "1757"}},
+		"100000000002": {ID: "100000000002", Status: "CRR", Destination: model.ViaDestination{ID: "1757"}},
+		"100000000003": {ID: "100000000003", Status: "CRR", Destination: model.ViaDestination{ID: "1757"}},
+	}
+
+	tests := []struct {
+		name         string
+		openErr      error
+		deliveredErr error
+		viaErr       map[string]error
+		expectErr    error
+		expectReport *model.ReconciliationReport
+	}{
+		{
+			name:      "error getting open guides",
+			openErr:   errors.New("db error"),
+			expectErr: errors.New("getting open guides"),
+		},
+		{
+			name:         "error getting delivered guides",
+			deliveredErr: errors.New("db error"),
+			expectErr:    errors.New("getting delivered guides"),
+		},
+		{
+			name:      "carrier unavailable aborts the run",
+			viaErr:    map[string]error{"100000000002": fmt.Errorf("%w: breaker open", via_guide_provider.ErrUnavailable)},
+			expectErr: via_guide_provider.ErrUnavailable,
+		},
+		{
+			name:   "discrepancies reported",
+			viaErr: map[string]error{"100000000002": errors.New("parse error")},
+			expectReport: &model.ReconciliationReport{
+				RunAt:   fixedNow,
+				Checked: 2,
+				Failed:  1,
+				Discrepancies: []model.Discrepancy{
+					{GuideId: 1, ViaGuideId: "100000000001", Status: biz_guide_status.PENDING_COUNTER_DELIVERY, ViaStatus: "ENT",
+						Reason: DELIVERED_AT_CARRIER, DetectedAt: fixedNow},
+					{GuideId: 3, ViaGuideId: "100000000003", Status: biz_guide_status.DELIVERED, ViaStatus: "CRR",
+						Reason: NOT_DELIVERED_AT_CARRIER, DetectedAt: fixedNow},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockVia := new(mock_via_guide_provider.MockViaGuideProvider)
+			via_guide_provider.Set(mockVia)
+			mockDS := new(mock_ds.MockDS)
+			ds.Set(mockDS)
+
+			mockGuide.On("GetGuidesByStatus", mock.Anything, biz_guide_status.GetOperatorStatus()).Return(open, tt.openErr)
+			mockGuide.On("GetGuidesByStatusUpdatedAfter", mock.Anything, []string{biz_guide_status.DELIVERED},
+				fixedNow.Add(-time.Hour)).Return(delivered, tt.deliveredErr)
+			for id, viaGuide := range viaGuides {
+				mockVia.On("GetGuide", mock.MatchedBy(via_guide_provider.IsWithoutCache), id).Return(viaGuide, tt.viaErr[id])
+			}
+			var stored string
+			mockDS.On("Set", mock.Anything, reportKey, mock.Anything, cfg.ReportTTL).
+				Run(func(args mock.Arguments) { stored = args.String(2) }).Return(nil)
+
+			err := New(cfg, biz).Run(context.Background())
+
+			if tt.expectErr != nil {
+				assert.ErrorContains(t, err, tt.expectErr.Error())
+				mockDS.AssertNotCalled(t, "Set", mock.Anything, reportKey, mock.Anything, cfg.ReportTTL)
+				return
+			}
+			assert.NoError(t, err)
+			var report model.ReconciliationReport
+			assert.NoError(t, json.Unmarshal([]byte(stored), &report))
+			assert.Equal(t, *tt.expectReport, report)
+		})
+	}
+}
+
+func TestRun_BreakerOpenWithCachedGuide(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	cfg := ReconcileCfg{DeliveredLookback: 3600, ReportTTL: 600}
+	guide := model.Guide{ID: 1, ViaGuideID: "100000000001", Status: biz_guide_status.PENDING_COUNTER_DELIVERY}
+	cached, err := json.Marshal(map[string]any{
+		"guide":     model.ViaGuide{ID: guide.ViaGuideID, Status: "CRR", Destination: model.ViaDestination{ID: "1757"}},
+		"fetchedAt": time.Now(),
+	})
+	assert.NoError(t, err)
+
+	mockGuide := new(mock_guide_provider.MockGuideProvider)
+	guide_provider.Set(mockGuide)
+	mockVia := new(mock_via_guide_provider.MockViaGuideProvider)
+	mockDS := new(mock_ds.MockDS)
+	ds.Set(mockDS)
+	resilient := via_guide_resilient_provider.New(via_guide_resilient_provider.ResilienceCfg{
+		BreakerFailures: 1, BreakerOpen: 60, CacheTTL: 60, StaleTTL: 3600,
+	}, mockVia, cache.New(mockDS))
+	via_guide_provider.Set(resilient)
+
+	mockGuide.On("GetGuidesByStatus", mock.Anything, biz_guide_status.GetOperatorStatus()).Return([]model.Guide{guide}, nil)
+	mockGuide.On("GetGuidesByStatusUpdatedAfter", mock.Anything, []string{biz_guide_status.DELIVERED}, mock.Anything).
+		Return([]model.Guide{}, nil)
+	mockDS.On("Get", mock.Anything, "via_guide:"+guide.ViaGuideID).Return(true, string(cached), nil).Maybe()
+	mockVia.On("GetGuide", mock.Anything, guide.ViaGuideID).
+		Return(model.ViaGuide{}, fmt.Errorf("%w: timeout", via_guide_provider.ErrTransient)).Once()
+	// the failure opens the breaker
+	_, err = resilient.GetGuide(via_guide_provider.WithoutCache(context.Background()), guide.ViaGuideID)
+	assert.ErrorIs(t, err, via_guide_provider.ErrUnavailable)
+
+	err = New(cfg, biz).Run(context.Background())
+
+	assert.ErrorIs(t, err, via_guide_provider.ErrUnavailable)
+	mockVia.AssertNumberOfCalls(t, "GetGuide", 1)
+	mockDS.AssertNotCalled(t, "Set", mock.Anything, reportKey, mock.Anything, cfg.ReportTTL)
+}
+
+func TestGetReport(t *testing.T) {
+	mockDS := new(mock_ds.MockDS)
+	ds.Set(mockDS)
+	report := model.ReconciliationReport{Checked: 3, Discrepancies: []model.Discrepancy{{GuideId: 1, Reason: WRONG_BRANCH}}}
+	data, _ := json.Marshal(report)
+
+	mockDS.On("Get", mock.Anything, reportKey).Return(true, string(data), nil).Once()
+	result, found, err := GetReport(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, report, result)
+
+	mockDS.On("Get", mock.Anything, reportKey).Return(false, "", nil).Once()
+	_, found, err = GetReport(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
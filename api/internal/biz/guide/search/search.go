@@ -0,0 +1,92 @@
+package biz_guide_search
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"via/internal/model"
+)
+
+// Sort fields
+const (
+	SORT_CREATED_AT = "createdAt"
+	SORT_UPDATED_AT = "updatedAt"
+)
+
+// Page sizes
+const (
+	DEFAULT_LIMIT = 20
+	MAX_LIMIT     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrCursorMismatch is returned for a cursor of a search with another sort, order or filters,
+	// its position means nothing in the new one
+	ErrCursorMismatch = errors.New("cursor of another search")
+)
+
+// accents are folded the same way the recipient_normalized column was backfilled
+var accents = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u",
+	"à", "a", "è", "e", "ì", "i", "ò", "o", "ù", "u",
+	"ä", "a", "ë", "e", "ï", "i", "ö", "o", "ü", "u",
+	"ñ", "n", "ç", "c",
+)
+
+// NormalizeName lower cases a person name, removes its accents and collapses the spaces,
+// so "José  Peña" and "JOSE PENA" are the same name
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(accents.Replace(strings.ToLower(name))), " ")
+}
+
+// IsValidSort reports whether guides can be sorted by field
+func IsValidSort(field string) bool {
+	return field == SORT_CREATED_AT || field == SORT_UPDATED_AT
+}
+
+// EncodeCursor returns the opaque representation of a cursor handed to the clients, bound to the
+// sort, order and filters of search
+func EncodeCursor(search model.GuideSearch, cursor model.GuideCursor) string {
+	cursor.Sort, cursor.Desc, cursor.Filters = search.Sort, search.Desc, filters(search)
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor returned by EncodeCursor, it must have been given for search
+func DecodeCursor(s string, search model.GuideSearch) (model.GuideCursor, error) {
+	cursor := model.GuideCursor{}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &cursor); err != nil || cursor.ID <= 0 {
+		return model.GuideCursor{}, ErrInvalidCursor
+	}
+	if cursor.Sort != search.Sort || cursor.Desc != search.Desc || cursor.Filters != filters(search) {
+		return model.GuideCursor{}, ErrCursorMismatch
+	}
+	return cursor, nil
+}
+
+// filters fingerprints the filters of search, the page size is not one of them
+func filters(search model.GuideSearch) string {
+	status := slices.Clone(search.Status)
+	slices.Sort(status)
+	b, _ := json.Marshal(struct {
+		ViaGuideIDPrefix string
+		Recipient        string
+		OperatorID       int
+		Status           []string
+		Payment          string
+		From             *time.Time
+		To               *time.Time
+	}{search.ViaGuideIDPrefix, search.Recipient, search.OperatorID, status, search.Payment, search.From, search.To})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,75 @@
+package biz_guide_search
+
+import (
+	"testing"
+	"time"
+	"via/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"José  Peña", "jose pena"},
+		{"  MARÍA ÁNGELES Nuñez ", "maria angeles nunez"},
+		{"Güemes", "guemes"},
+		{"lopez", "lopez"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeName(tt.name))
+		})
+	}
+}
+
+func TestIsValidSort(t *testing.T) {
+	assert.True(t, IsValidSort(SORT_CREATED_AT))
+	assert.True(t, IsValidSort(SORT_UPDATED_AT))
+	assert.False(t, IsValidSort("recipient"))
+}
+
+func TestCursor(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	search := model.GuideSearch{Recipient: "jose pena", Status: []string{"onHold", "delivered"}, From: &from,
+		Sort: SORT_UPDATED_AT, Desc: true, Limit: 20}
+	cursor := model.GuideCursor{Value: time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC), ID: 42}
+	decoded, err := DecodeCursor(EncodeCursor(search, cursor), search)
+	assert.NoError(t, err)
+	assert.True(t, cursor.Value.Equal(decoded.Value))
+	assert.Equal(t, cursor.ID, decoded.ID)
+
+	// the page size and the order of the statuses do not change the search
+	other := search
+	other.Limit, other.Status = 50, []string{"delivered", "onHold"}
+	_, err = DecodeCursor(EncodeCursor(search, cursor), other)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		cursor      string
+		change      func(s *model.GuideSearch)
+		expectedErr error
+	}{
+		{"not base64", "%%%", nil, ErrInvalidCursor},
+		{"not json", "bm90IGpzb24", nil, ErrInvalidCursor},
+		{"missing id", EncodeCursor(search, model.GuideCursor{Value: cursor.Value}), nil, ErrInvalidCursor},
+		{"other sort", EncodeCursor(search, cursor), func(s *model.GuideSearch) { s.Sort = SORT_CREATED_AT }, ErrCursorMismatch},
+		{"other order", EncodeCursor(search, cursor), func(s *model.GuideSearch) { s.Desc = false }, ErrCursorMismatch},
+		{"other filters", EncodeCursor(search, cursor), func(s *model.GuideSearch) { s.Recipient = "ana" }, ErrCursorMismatch},
+		{"other dates", EncodeCursor(search, cursor), func(s *model.GuideSearch) { s.From = nil }, ErrCursorMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := search
+			if tt.change != nil {
+				tt.change(&other)
+			}
+			_, err := DecodeCursor(tt.cursor, other)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
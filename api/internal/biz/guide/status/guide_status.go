@@ -22,6 +22,21 @@ const (
 	PREVIOUS                   = "previous"
 )
 
+// Status reason constants, why the SYSTEM operator changed the status
+const (
+	REASON_EXPIRED    = "expired"
+	REASON_DAY_CLOSED = "dayClosed"
+)
+
+// Discrepancy reason constants, why a guide and its carrier state disagree
+const (
+	REASON_DELIVERED_AT_CARRIER     = "deliveredAtCarrier"
+	REASON_NOT_DELIVERED_AT_CARRIER = "notDeliveredAtCarrier"
+	REASON_NOT_FOUND_AT_CARRIER     = "notFoundAtCarrier"
+	REASON_NOT_AT_BRANCH            = "notAtBranch"
+	REASON_WRONG_BRANCH             = "wrongBranch"
+)
+
 // messages holds the localized descriptions
 var messages = map[string]map[string]string{
 	biz_language.ES: {
@@ -39,6 +54,18 @@ var messages = map[string]map[string]string{
 	},
 }
 
+var reasonMessages = map[string]map[string]string{
+	biz_language.ES: {
+		REASON_EXPIRED:                  "Vencida por inactividad",
+		REASON_DAY_CLOSED:               "Cierre del día",
+		REASON_DELIVERED_AT_CARRIER:     "Entregada en Via pero abierta en el sistema",
+		REASON_NOT_DELIVERED_AT_CARRIER: "Entregada en el sistema pero no en Via",
+		REASON_NOT_FOUND_AT_CARRIER:     "No se encuentra en Via",
+		REASON_NOT_AT_BRANCH:            "Abierta en el sistema pero Via indica que no llegó a la sucursal",
+		REASON_WRONG_BRANCH:             "Abierta en el sistema pero Via indica otra sucursal de destino",
+	},
+}
+
 // Description returns the localized description of a status for a given language.
 // If lang or status is unknown, it returns the status key itself.
 func GetStatusDescription(lang, status string) string {
@@ -54,6 +81,24 @@ func GetStatusDescription(lang, status string) string {
 	return fmt.Sprintf("Unknown status: %s", status)
 }
 
+// IsValid reports whether status is a known guide status
+func IsValid(status string) bool {
+	_, ok := messages[biz_language.DEFAULT][status]
+	return ok
+}
+
+// GetReasonDescription returns the localized description of a status or discrepancy reason,
+// the reason itself when unknown
+func GetReasonDescription(lang, reason string) string {
+	if desc, ok := reasonMessages[lang][reason]; ok {
+		return desc
+	}
+	if desc, ok := reasonMessages[biz_language.DEFAULT][reason]; ok {
+		return desc
+	}
+	return reason
+}
+
 var nextStatus = map[string][]string{
 	INITIAL:                    {ON_HOLD, SUSPENDED, PENDING_RECIPIENT_IDENTIFY},
 	PENDING_RECIPIENT_IDENTIFY: {ON_HOLD, SUSPENDED, RECIPIENT_IDENTIFIED},
@@ -135,6 +180,17 @@ func GetMonitorStatus() []string {
 		PENDING_COUNTER_DELIVERY, PENDING_WAREHOUSE_DELIVERY}
 }
 
+// GetArchivableStatus returns the status of the guides that can leave the hot tables
+func GetArchivableStatus() []string {
+	return []string{PARTIAL_DELIVERED, DELIVERED, ON_HOLD, SUSPENDED}
+}
+
+// GetInProcessStatus returns the statuses of the guides an operator is attending
+func GetInProcessStatus() []string {
+	return []string{INITIAL, PENDING_RECIPIENT_IDENTIFY, RECIPIENT_IDENTIFIED, PENDING_PAYMENT, PAID,
+		PENDING_COUNTER_DELIVERY, PENDING_WAREHOUSE_DELIVERY}
+}
+
 func GetOperatorStatus() []string {
 	return []string{INITIAL, PENDING_RECIPIENT_IDENTIFY, RECIPIENT_IDENTIFIED, PENDING_PAYMENT, PAID,
 		PENDING_COUNTER_DELIVERY, PENDING_WAREHOUSE_DELIVERY, ON_HOLD, SUSPENDED}
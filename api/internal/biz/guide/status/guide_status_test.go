@@ -29,6 +29,20 @@ func TestGetStatusDescription(t *testing.T) {
 	}
 }
 
+func TestIsValid(t *testing.T) {
+	assert.True(t, IsValid(ON_HOLD))
+	assert.False(t, IsValid(PREVIOUS))
+	assert.False(t, IsValid("unknown"))
+}
+
+func TestGetReasonDescription(t *testing.T) {
+	assert.Equal(t, "Vencida por inactividad", GetReasonDescription(biz_language.ES, REASON_EXPIRED))
+	assert.Equal(t, "Cierre del día", GetReasonDescription("fr", REASON_DAY_CLOSED))
+	assert.Equal(t, "No se encuentra en Via", GetReasonDescription(biz_language.ES, REASON_NOT_FOUND_AT_CARRIER))
+	assert.Equal(t, "", GetReasonDescription(biz_language.ES, ""))
+	assert.Equal(t, "unknown", GetReasonDescription(biz_language.ES, "unknown"))
+}
+
 func TestGetNextStatus(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -134,7 +148,21 @@ func TestGetMonitorStatus(t *testing.T) {
 	assert.NotEmpty(t, result)
 }
 
+func TestGetArchivableStatus(t *testing.T) {
+	result := GetArchivableStatus()
+	for _, status := range result {
+		assert.False(t, IsInProcess(status))
+	}
+}
+
 func TestGetOperatorStatus(t *testing.T) {
 	result := GetOperatorStatus()
 	assert.NotEmpty(t, result)
 }
+
+func TestGetInProcessStatus(t *testing.T) {
+	for _, status := range GetInProcessStatus() {
+		assert.True(t, IsInProcess(status), status)
+	}
+	assert.NotContains(t, GetInProcessStatus(), ON_HOLD)
+}
@@ -0,0 +1,74 @@
+package biz_monitor
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"via/internal/model"
+)
+
+// Privacy modes of a monitor, they choose how the guides are identified on its public screen.
+// The full recipient name is never shown
+const (
+	PRIVACY_INITIALS    = "initials"    // recipient initials and the via guide id
+	PRIVACY_FIRST_NAME  = "first_name"  // recipient first name, last name initial and the via guide id
+	PRIVACY_TICKET      = "ticket"      // ticket number given by the kiosk only
+	PRIVACY_GUIDE_LAST4 = "guide_last4" // last 4 digits of the via guide id only
+)
+
+func IsValidPrivacy(mode string) bool {
+	switch mode {
+	case PRIVACY_INITIALS, PRIVACY_FIRST_NAME, PRIVACY_TICKET, PRIVACY_GUIDE_LAST4:
+		return true
+	}
+	return false
+}
+
+// NewEvent identifies the guide as the privacy mode allows, unknown modes show the ticket number only
+func NewEvent(guide model.Guide, mode string) model.MonitorEvent {
+	event := model.MonitorEvent{Ticket: guide.ID}
+	switch mode {
+	case PRIVACY_INITIALS:
+		event.GuideId = guide.ViaGuideID
+		event.Recipient = initials(strings.Fields(guide.Recipient))
+	case PRIVACY_FIRST_NAME:
+		event.GuideId = guide.ViaGuideID
+		event.Recipient = firstName(strings.Fields(guide.Recipient))
+	case PRIVACY_GUIDE_LAST4:
+		event.GuideId = lastDigits(guide.ViaGuideID, 4)
+	}
+	return event
+}
+
+func initial(word string) string {
+	r, _ := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + "."
+}
+
+// initials turns "John Doe" into "J. D."
+func initials(words []string) string {
+	masked := make([]string, len(words))
+	for i, word := range words {
+		masked[i] = initial(word)
+	}
+	return strings.Join(masked, " ")
+}
+
+// firstName turns "John Michael Doe" into "John D.", a single word may be the whole name
+// and only its initial is shown
+func firstName(words []string) string {
+	switch len(words) {
+	case 0:
+		return ""
+	case 1:
+		return initial(words[0])
+	}
+	return words[0] + " " + initial(words[len(words)-1])
+}
+
+func lastDigits(value string, n int) string {
+	if len(value) <= n {
+		return value
+	}
+	return value[len(value)-n:]
+}
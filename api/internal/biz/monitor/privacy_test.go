@@ -0,0 +1,55 @@
+package biz_monitor
+
+import (
+	"testing"
+	"via/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidPrivacy(t *testing.T) {
+	for _, mode := range []string{PRIVACY_INITIALS, PRIVACY_FIRST_NAME, PRIVACY_TICKET, PRIVACY_GUIDE_LAST4} {
+		assert.True(t, IsValidPrivacy(mode), mode)
+	}
+	assert.False(t, IsValidPrivacy("full_name"))
+	assert.False(t, IsValidPrivacy(""))
+}
+
+func TestNewEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		recipient string
+		mode      string
+		expected  model.MonitorEvent
+	}{
+		{name: "initials", recipient: "John Michael Doe", mode: PRIVACY_INITIALS,
+			expected: model.MonitorEvent{Ticket: 7, GuideId: "123456789012", Recipient: "J. M. D."}},
+		{name: "initials of accented lower case names", recipient: "  Ángel   pérez ", mode: PRIVACY_INITIALS,
+			expected: model.MonitorEvent{Ticket: 7, GuideId: "123456789012", Recipient: "Á. P."}},
+		{name: "first name", recipient: "John Michael Doe", mode: PRIVACY_FIRST_NAME,
+			expected: model.MonitorEvent{Ticket: 7, GuideId: "123456789012", Recipient: "John D."}},
+		{name: "first name of a single word", recipient: "Johndoe", mode: PRIVACY_FIRST_NAME,
+			expected: model.MonitorEvent{Ticket: 7, GuideId: "123456789012", Recipient: "J."}},
+		{name: "first name of a single padded word", recipient: "  johndoe ", mode: PRIVACY_FIRST_NAME,
+			expected: model.MonitorEvent{Ticket: 7, GuideId: "123456789012", Recipient: "J."}},
+		{name: "first name without recipient", mode: PRIVACY_FIRST_NAME,
+			expected: model.MonitorEvent{Ticket: 7, GuideId: "123456789012"}},
+		{name: "ticket", recipient: "John Doe", mode: PRIVACY_TICKET,
+			expected: model.MonitorEvent{Ticket: 7}},
+		{name: "guide last 4 digits", recipient: "John Doe", mode: PRIVACY_GUIDE_LAST4,
+			expected: model.MonitorEvent{Ticket: 7, GuideId: "9012"}},
+		{name: "unknown mode shows the ticket only", recipient: "John Doe", mode: "full_name",
+			expected: model.MonitorEvent{Ticket: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guide := model.Guide{ID: 7, ViaGuideID: "123456789012", Recipient: tt.recipient}
+			assert.Equal(t, tt.expected, NewEvent(guide, tt.mode))
+		})
+	}
+}
+
+func TestLastDigits(t *testing.T) {
+	assert.Equal(t, "12", lastDigits("12", 4))
+}
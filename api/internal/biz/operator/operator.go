@@ -13,6 +13,41 @@ var operatorCache = cache.New(5*time.Minute, 10*time.Minute)
 
 const OPERATOR_SYSTEM int = 1
 
+// Operator roles, each role is granted everything the previous ones can do
+const (
+	ROLE_OPERATOR   = "operator"
+	ROLE_SUPERVISOR = "supervisor"
+	ROLE_ADMIN      = "admin"
+)
+
+var roleLevel = map[string]int{
+	ROLE_OPERATOR:   1,
+	ROLE_SUPERVISOR: 2,
+	ROLE_ADMIN:      3,
+}
+
+// Operator availability, only available operators are given new guides
+const (
+	AVAILABILITY_AVAILABLE = "available"
+	AVAILABILITY_ON_BREAK  = "onBreak"
+	AVAILABILITY_OFFLINE   = "offline"
+)
+
+func IsValidAvailability(availability string) bool {
+	return availability == AVAILABILITY_AVAILABLE || availability == AVAILABILITY_ON_BREAK || availability == AVAILABILITY_OFFLINE
+}
+
+func IsValidRole(role string) bool {
+	_, ok := roleLevel[role]
+	return ok
+}
+
+// HasRole reports whether an operator with the given role is granted the required one
+func HasRole(role, required string) bool {
+	level, ok := roleLevel[role]
+	return ok && level >= roleLevel[required]
+}
+
 func GetOperatorByAccount(ctx context.Context, account string) (model.Operator, error) {
 	if data, found := operatorCache.Get(account); found {
 		return data.(model.Operator), nil
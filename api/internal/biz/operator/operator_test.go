@@ -88,3 +88,34 @@ func TestGetOperatorByAccount(t *testing.T) {
 		mockProvider.AssertExpectations(t)
 	})
 }
+
+func TestIsValidRole(t *testing.T) {
+	assert.True(t, IsValidRole(ROLE_SUPERVISOR))
+	assert.False(t, IsValidRole("root"))
+}
+
+func TestIsValidAvailability(t *testing.T) {
+	assert.True(t, IsValidAvailability(AVAILABILITY_ON_BREAK))
+	assert.False(t, IsValidAvailability("busy"))
+}
+
+func TestHasRole(t *testing.T) {
+	tests := []struct {
+		role     string
+		required string
+		expected bool
+	}{
+		{ROLE_OPERATOR, ROLE_OPERATOR, true},
+		{ROLE_OPERATOR, ROLE_SUPERVISOR, false},
+		{ROLE_SUPERVISOR, ROLE_OPERATOR, true},
+		{ROLE_SUPERVISOR, ROLE_ADMIN, false},
+		{ROLE_ADMIN, ROLE_SUPERVISOR, true},
+		{"", ROLE_OPERATOR, false},
+		{"unknown", ROLE_OPERATOR, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role+"_"+tt.required, func(t *testing.T) {
+			assert.Equal(t, tt.expected, HasRole(tt.role, tt.required))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	CLOSED    = "closed"
+	OPEN      = "open"
+	HALF_OPEN = "halfOpen"
+)
+
+// Breaker is a circuit breaker that opens after a number of consecutive failures
+// and lets a probe request through once the open timeout elapses.
+type Breaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	failures         int
+	state            string
+	openedAt         time.Time
+	mu               sync.Mutex
+	now              func() time.Time
+}
+
+func New(failureThreshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            CLOSED,
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a request can go through.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case OPEN:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = HALF_OPEN
+		return true
+	case HALF_OPEN:
+		// a probe is already in flight
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.state = CLOSED
+}
+
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == HALF_OPEN || b.failures >= b.failureThreshold {
+		b.state = OPEN
+		b.openedAt = b.now()
+	}
+}
+
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
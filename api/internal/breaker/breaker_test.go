@@ -0,0 +1,43 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := New(2, 10*time.Second)
+	b.now = func() time.Time { return now }
+
+	assert.True(t, b.Allow())
+	assert.Equal(t, CLOSED, b.State())
+
+	b.Failure()
+	assert.True(t, b.Allow(), "under threshold keeps the breaker closed")
+	b.Failure()
+	assert.Equal(t, OPEN, b.State())
+	assert.False(t, b.Allow(), "open breaker fails fast")
+
+	now = now.Add(11 * time.Second)
+	assert.True(t, b.Allow(), "probe allowed after open timeout")
+	assert.Equal(t, HALF_OPEN, b.State())
+	assert.False(t, b.Allow(), "only one probe while half open")
+
+	b.Failure()
+	assert.Equal(t, OPEN, b.State(), "failed probe opens the breaker again")
+	assert.False(t, b.Allow())
+
+	now = now.Add(11 * time.Second)
+	assert.True(t, b.Allow())
+	b.Success()
+	assert.Equal(t, CLOSED, b.State())
+	assert.True(t, b.Allow())
+
+	b.Failure()
+	b.Success()
+	b.Failure()
+	assert.Equal(t, CLOSED, b.State(), "success resets the failure count")
+}
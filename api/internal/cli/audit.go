@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	biz_audit "via/internal/biz/audit"
+)
+
+var auditCommand = Command{
+	Name:  "audit",
+	Usage: "verify the audit log",
+	Subcommands: []Command{
+		{Name: "verify", Usage: "check the hash chain of the audit log", Run: auditVerify},
+	},
+}
+
+// recordAudit appends the action of an admin command on the target to the audit log
+func recordAudit(ctx context.Context, action, targetType, targetId string, before, after any) {
+	biz_audit.Record(ctx, biz_audit.Event{Actor: biz_audit.ACTOR_CLI, Action: action, TargetType: targetType,
+		TargetID: targetId, Before: before, After: after})
+}
+
+func auditVerify(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "audit verify", "via audit verify")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	return connected(ctx, env, func() error {
+		verification, err := biz_audit.Verify(ctx)
+		if err != nil {
+			return err
+		}
+		if !verification.Valid {
+			return fmt.Errorf("audit log chain broken at entry %d, %d entries verified before it",
+				verification.BrokenAt, verification.Entries)
+		}
+		fmt.Fprintf(env.Stdout, "audit log valid, %d entries\nlast hash: %s\n", verification.Entries, verification.LastHash)
+		return nil
+	})
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	biz_audit "via/internal/biz/audit"
+	"via/internal/model"
+	audit_provider "via/internal/provider/audit"
+	mock_audit_provider "via/internal/provider/audit/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditVerify(t *testing.T) {
+	entry := model.AuditEntry{ID: 1, Actor: biz_audit.ACTOR_CLI, Action: biz_audit.OPERATOR_ADD, CreatedAt: time.Unix(1, 0)}
+	entry.Hash = biz_audit.Hash(entry)
+	tampered := entry
+	tampered.Actor = "operator:42"
+
+	tests := []struct {
+		name         string
+		entries      []model.AuditEntry
+		getErr       error
+		expectCode   int
+		expectStdout string
+		expectStderr string
+	}{
+		{name: "valid log", entries: []model.AuditEntry{entry},
+			expectStdout: "audit log valid, 1 entries\nlast hash: " + entry.Hash + "\n"},
+		{name: "broken chain", entries: []model.AuditEntry{tampered}, expectCode: 1,
+			expectStderr: "audit log chain broken at entry 1, 0 entries verified before it"},
+		{name: "provider error", getErr: errors.New("db error"), expectCode: 1, expectStderr: "db error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, stdout, stderr, released := newTestEnv(nil)
+			mockAudit := new(mock_audit_provider.MockAuditProvider)
+			audit_provider.Set(mockAudit)
+			mockAudit.On("GetAuditEntriesAfter", context.Background(), 0, 500).Return(tt.entries, tt.getErr)
+
+			assert.Equal(t, tt.expectCode, Run(context.Background(), env, []string{"audit", "verify"}))
+			assert.Equal(t, tt.expectStdout, stdout.String())
+			assert.Contains(t, stderr.String(), tt.expectStderr)
+			assert.Equal(t, 1, *released)
+		})
+	}
+}
+
+func TestAuditVerifyArgs(t *testing.T) {
+	env, _, stderr, _ := newTestEnv(nil)
+	assert.Equal(t, 2, Run(context.Background(), env, []string{"audit", "verify", "extra"}))
+	assert.NotEmpty(t, stderr.String())
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"via/internal/config"
+)
+
+// ErrUsage is returned when the command line is wrong, the usage is printed along with it
+var ErrUsage = errors.New("invalid usage")
+
+// Env is what the commands run with
+type Env struct {
+	Config config.Config
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Connect sets up the providers used by the commands reading or writing data,
+	// the returned function releases them
+	Connect func(ctx context.Context) (func(), error)
+}
+
+// Command is a CLI command, either runnable or a group of subcommands
+type Command struct {
+	Name        string
+	Usage       string
+	Run         func(ctx context.Context, env Env, args []string) error
+	Subcommands []Command
+}
+
+// Commands are the admin commands of the via binary, serve is handled by main
+var Commands = []Command{
+	migrateCommand,
+	operatorCommand,
+	guideCommand,
+	configCommand,
+	jwtCommand,
+	auditCommand,
+}
+
+// Run runs the command named by args and returns the process exit code
+func Run(ctx context.Context, env Env, args []string) int {
+	err := run(ctx, env, Command{Name: "via", Subcommands: Commands}, args)
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintln(env.Stderr, "error:", err)
+	if errors.Is(err, ErrUsage) {
+		return 2
+	}
+	return 1
+}
+
+func run(ctx context.Context, env Env, cmd Command, args []string) error {
+	if cmd.Run != nil {
+		return cmd.Run(ctx, env, args)
+	}
+	if len(args) > 0 {
+		for _, sub := range cmd.Subcommands {
+			if sub.Name == args[0] {
+				sub.Name = cmd.Name + " " + sub.Name
+				return run(ctx, env, sub, args[1:])
+			}
+		}
+	}
+	printUsage(env.Stderr, cmd)
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing %s command", ErrUsage, cmd.Name)
+	}
+	return fmt.Errorf("%w: unknown %s command %q", ErrUsage, cmd.Name, args[0])
+}
+
+func printUsage(w io.Writer, cmd Command) {
+	fmt.Fprintf(w, "usage: %s <command>\n\ncommands:\n", cmd.Name)
+	for _, sub := range cmd.Subcommands {
+		fmt.Fprintf(w, "  %-12s %s\n", sub.Name, sub.Usage)
+	}
+}
+
+// newFlagSet returns a flag set writing its errors and usage to the command stderr
+func newFlagSet(env Env, name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.Stderr, "usage: %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args, which must leave exactly nArgs positional arguments
+func parseFlags(fs *flag.FlagSet, args []string, nArgs int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	if fs.NArg() != nArgs {
+		fs.Usage()
+		return nil, fmt.Errorf("%w: expected %d arguments, got %d (%s)", ErrUsage, nArgs, fs.NArg(),
+			strings.Join(fs.Args(), " "))
+	}
+	return fs.Args(), nil
+}
+
+// connected runs fn with the data providers set up
+func connected(ctx context.Context, env Env, fn func() error) error {
+	release, err := env.Connect(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting: %w", err)
+	}
+	defer release()
+	return fn()
+}
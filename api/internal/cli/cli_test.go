@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestEnv returns an env writing to buffers whose Connect counts the releases
+func newTestEnv(connectErr error) (Env, *bytes.Buffer, *bytes.Buffer, *int) {
+	testutil.InjectNoOpAuditProvider()
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	released := 0
+	env := Env{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: stderr,
+		Connect: func(ctx context.Context) (func(), error) {
+			if connectErr != nil {
+				return nil, connectErr
+			}
+			return func() { released++ }, nil
+		},
+	}
+	return env, stdout, stderr, &released
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		connectErr   error
+		expectCode   int
+		expectStderr string
+	}{
+		{name: "no command", args: []string{}, expectCode: 2, expectStderr: "missing via command"},
+		{name: "unknown command", args: []string{"foo"}, expectCode: 2, expectStderr: `unknown via command "foo"`},
+		{name: "missing subcommand", args: []string{"operator"}, expectCode: 2, expectStderr: "usage: via operator <command>"},
+		{name: "unknown subcommand", args: []string{"guide", "delete"}, expectCode: 2, expectStderr: `unknown via guide command "delete"`},
+		{name: "bad flag", args: []string{"operator", "list", "-x"}, expectCode: 2, expectStderr: "flag provided but not defined"},
+		{name: "connect error", args: []string{"operator", "list"}, connectErr: errors.New("db down"), expectCode: 1,
+			expectStderr: "error: connecting: db down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, _, stderr, _ := newTestEnv(tt.connectErr)
+			code := Run(context.Background(), env, tt.args)
+			assert.Equal(t, tt.expectCode, code)
+			assert.Contains(t, stderr.String(), tt.expectStderr)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+)
+
+var configCommand = Command{
+	Name:  "config",
+	Usage: "print the configuration",
+	Subcommands: []Command{
+		{Name: "print", Usage: "print the configuration, secrets are left out", Run: configPrint},
+	},
+}
+
+// configPrint prints the configuration as JSON, the secrets are tagged json:"-" in their config structs
+func configPrint(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "config print", "via config print")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(env.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(env.Config)
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"via/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigPrint(t *testing.T) {
+	env, stdout, _, _ := newTestEnv(nil)
+	env.Config = config.Config{}
+	env.Config.Application.Name = "via"
+	env.Config.Database.Password = "db-secret"
+	env.Config.OAuth.ClientSecret = "oauth-secret"
+	env.Config.JWT.PrivateKey = "jwt-secret"
+
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"config", "print"}))
+
+	var printed map[string]any
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &printed))
+	assert.Equal(t, "via", printed["application"].(map[string]any)["name"])
+	assert.NotContains(t, stdout.String(), "db-secret")
+	assert.NotContains(t, stdout.String(), "oauth-secret")
+	assert.NotContains(t, stdout.String(), "jwt-secret")
+
+	assert.Equal(t, 2, Run(context.Background(), env, []string{"config", "print", "extra"}))
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	biz_audit "via/internal/biz/audit"
+	biz_guide_closeday "via/internal/biz/guide/closeday"
+	biz_guide_status "via/internal/biz/guide/status"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/global"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	"via/internal/pubsub"
+)
+
+var guideCommand = Command{
+	Name:  "guide",
+	Usage: "show a guide, force its status or close the day",
+	Subcommands: []Command{
+		{Name: "show", Usage: "show a guide and its history", Run: guideShow},
+		{Name: "set-status", Usage: "set the status of a guide as the SYSTEM operator", Run: guideSetStatus},
+		{Name: "close-day", Usage: "hold or suspend the guides in process, archive the old ones and print the day summary", Run: guideCloseDay},
+	},
+}
+
+type guideShowOutput struct {
+	Guide   model.Guide          `json:"guide"`
+	History []model.GuideHistory `json:"history"`
+}
+
+func parseGuideId(value string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid guide id %q", ErrUsage, value)
+	}
+	return id, nil
+}
+
+// getGuide returns the guide, failing when it does not exist
+func getGuide(ctx context.Context, id int) (model.Guide, error) {
+	guide, err := guide_provider.Get().GetGuideById(ctx, id)
+	if err != nil {
+		return guide, err
+	}
+	if guide.ID == 0 {
+		return guide, fmt.Errorf("guide %d not found", id)
+	}
+	return guide, nil
+}
+
+func guideShow(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "guide show", "via guide show <guide id>")
+	args, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseGuideId(args[0])
+	if err != nil {
+		return err
+	}
+
+	return connected(ctx, env, func() error {
+		guide, err := getGuide(ctx, id)
+		if err != nil {
+			return err
+		}
+		history, err := guide_provider.Get().GetGuideHistory(ctx, id)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(guideShowOutput{Guide: guide, History: history})
+	})
+}
+
+func guideSetStatus(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "guide set-status", "via guide set-status [-reason <reason>] <guide id> <status>")
+	reason := fs.String("reason", "", "reason recorded in the guide history")
+	args, err := parseFlags(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseGuideId(args[0])
+	if err != nil {
+		return err
+	}
+	status := args[1]
+	if !biz_guide_status.IsValid(status) {
+		return fmt.Errorf("%w: unknown status %q", ErrUsage, status)
+	}
+
+	return connected(ctx, env, func() error {
+		guide, err := getGuide(ctx, id)
+		if err != nil {
+			return err
+		}
+		err = guide_provider.Get().UpdateGuide(ctx, model.Guide{
+			ID:           id,
+			Status:       status,
+			StatusReason: *reason,
+			Operator:     model.Operator{ID: biz_operator.OPERATOR_SYSTEM},
+		})
+		if err != nil {
+			return err
+		}
+		if err := pubsub.Get().Publish(ctx, global.GuideStatusChangeChannel, fmt.Sprintf("{\"guide_id\":\"%d\"}", id)); err != nil {
+			fmt.Fprintf(env.Stderr, "warning: operators were not notified: %v\n", err)
+		}
+		recordAudit(ctx, biz_audit.GUIDE_STATUS, biz_audit.TARGET_GUIDE, strconv.Itoa(id),
+			map[string]string{"status": guide.Status}, map[string]string{"status": status, "reason": *reason})
+		fmt.Fprintf(env.Stdout, "guide %d status changed from %s to %s\n", id, guide.Status, status)
+		return nil
+	})
+}
+
+// guideCloseDay prints the summary even when the archival fails, the guides were closed anyway
+func guideCloseDay(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "guide close-day", "via guide close-day")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	return connected(ctx, env, func() error {
+		summary, err := biz_guide_closeday.New(env.Config.CloseDay).Close(ctx)
+		recordAudit(ctx, biz_audit.CLOSE_DAY, "", "", nil,
+			map[string]any{"closed": summary.Closed, "archived": summary.Archived, "failed": err != nil})
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(summary); encErr != nil && err == nil {
+			return encErr
+		}
+		return err
+	})
+}
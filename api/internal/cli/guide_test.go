@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	biz_guide_status "via/internal/biz/guide/status"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/global"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	mock_guide_provider "via/internal/provider/guide/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGuideShow(t *testing.T) {
+	guide := model.Guide{ID: 5, ViaGuideID: "100000000005", Status: biz_guide_status.ON_HOLD}
+	history := []model.GuideHistory{{ID: 1, Status: biz_guide_status.INITIAL}, {ID: 2, Status: biz_guide_status.ON_HOLD}}
+
+	tests := []struct {
+		name       string
+		args       []string
+		guide      model.Guide
+		guideErr   error
+		historyErr error
+		expectCode int
+	}{
+		{name: "guide shown", args: []string{"5"}, guide: guide},
+		{name: "invalid id", args: []string{"abc"}, expectCode: 2},
+		{name: "guide not found", args: []string{"5"}, expectCode: 1},
+		{name: "guide error", args: []string{"5"}, guideErr: errors.New("db error"), expectCode: 1},
+		{name: "history error", args: []string{"5"}, guide: guide, historyErr: errors.New("db error"), expectCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockGuide.On("GetGuideById", context.Background(), 5).Return(tt.guide, tt.guideErr)
+			mockGuide.On("GetGuideHistory", context.Background(), 5).Return(history, tt.historyErr)
+			env, stdout, _, _ := newTestEnv(nil)
+
+			code := Run(context.Background(), env, append([]string{"guide", "show"}, tt.args...))
+
+			assert.Equal(t, tt.expectCode, code)
+			if tt.expectCode != 0 {
+				return
+			}
+			var output guideShowOutput
+			assert.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+			assert.Equal(t, guideShowOutput{Guide: guide, History: history}, output)
+		})
+	}
+}
+
+func TestGuideSetStatus(t *testing.T) {
+	guide := model.Guide{ID: 5, ViaGuideID: "100000000005", Status: biz_guide_status.SUSPENDED}
+	update := model.Guide{ID: 5, Status: biz_guide_status.ON_HOLD, StatusReason: "customer called",
+		Operator: model.Operator{ID: biz_operator.OPERATOR_SYSTEM}}
+
+	tests := []struct {
+		name         string
+		args         []string
+		updateErr    error
+		publishErr   error
+		expectCode   int
+		expectStdout string
+		expectStderr string
+	}{
+		{name: "status set", args: []string{"-reason", "customer called", "5", biz_guide_status.ON_HOLD},
+			expectStdout: "guide 5 status changed from suspended to onHold\n"},
+		{name: "publish error is a warning", args: []string{"-reason", "customer called", "5", biz_guide_status.ON_HOLD},
+			publishErr: errors.New("pubsub error"), expectStdout: "guide 5 status changed from suspended to onHold\n",
+			expectStderr: "warning: operators were not notified: pubsub error"},
+		{name: "unknown status", args: []string{"5", "closed"}, expectCode: 2, expectStderr: `unknown status "closed"`},
+		{name: "invalid id", args: []string{"0", biz_guide_status.ON_HOLD}, expectCode: 2},
+		{name: "update error", args: []string{"-reason", "customer called", "5", biz_guide_status.ON_HOLD},
+			updateErr: errors.New("db error"), expectCode: 1, expectStderr: "error: db error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			mockGuide.On("GetGuideById", context.Background(), 5).Return(guide, nil)
+			mockGuide.On("UpdateGuide", context.Background(), update).Return(tt.updateErr)
+			mockPubSub.On("Publish", mock.Anything, global.GuideStatusChangeChannel, `{"guide_id":"5"}`).Return(tt.publishErr)
+			env, stdout, stderr, _ := newTestEnv(nil)
+
+			code := Run(context.Background(), env, append([]string{"guide", "set-status"}, tt.args...))
+
+			assert.Equal(t, tt.expectCode, code)
+			assert.Equal(t, tt.expectStdout, stdout.String())
+			assert.Contains(t, stderr.String(), tt.expectStderr)
+		})
+	}
+}
+
+func TestGuideCloseDay(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	open := []model.Guide{
+		{ID: 5, Status: biz_guide_status.PENDING_PAYMENT, Operator: model.Operator{Account: "jdoe"}},
+		{ID: 6, Status: biz_guide_status.ON_HOLD, Operator: model.Operator{Account: "jdoe"}},
+	}
+	update := model.Guide{ID: 5, Status: biz_guide_status.ON_HOLD, StatusReason: biz_guide_status.REASON_DAY_CLOSED,
+		Operator: model.Operator{ID: biz_operator.OPERATOR_SYSTEM}}
+
+	tests := []struct {
+		name           string
+		args           []string
+		guidesErr      error
+		archiveErr     error
+		expectCode     int
+		expectClosed   int
+		expectArchived int
+		expectStderr   string
+	}{
+		{name: "day closed", expectClosed: 1, expectArchived: 3},
+		{name: "archive error prints the summary", archiveErr: errors.New("db error"), expectClosed: 1,
+			expectCode: 1, expectStderr: "archiving guides: db error"},
+		{name: "open guides error", guidesErr: errors.New("db error"), expectCode: 1,
+			expectStderr: "getting open guides: db error"},
+		{name: "unexpected argument", args: []string{"today"}, expectCode: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			mockGuide.On("GetGuidesByStatus", mock.Anything, biz_guide_status.GetOperatorStatus()).Return(open, tt.guidesErr)
+			mockGuide.On("UpdateGuide", mock.Anything, update).Return(nil)
+			mockGuide.On("ArchiveGuides", mock.Anything, biz_guide_status.GetArchivableStatus(), mock.Anything).
+				Return(tt.expectArchived, tt.archiveErr)
+			mockPubSub.On("Publish", mock.Anything, global.GuideStatusChangeChannel, `{"guide_id":"5"}`).Return(nil)
+			env, stdout, stderr, _ := newTestEnv(nil)
+			env.Config.CloseDay.ArchiveDays = 90
+
+			code := Run(context.Background(), env, append([]string{"guide", "close-day"}, tt.args...))
+
+			assert.Equal(t, tt.expectCode, code)
+			assert.Contains(t, stderr.String(), tt.expectStderr)
+			if tt.expectCode == 2 {
+				return
+			}
+			var summary model.DaySummary
+			assert.NoError(t, json.Unmarshal(stdout.Bytes(), &summary))
+			assert.Equal(t, tt.expectClosed, summary.Closed)
+			assert.Equal(t, tt.expectArchived, summary.Archived)
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	jwt_key "via/internal/jwt"
+)
+
+var jwtCommand = Command{
+	Name:  "jwt",
+	Usage: "manage the token signing key",
+	Subcommands: []Command{
+		{Name: "prepare", Usage: "write the upcoming signing key pair to new files", Run: jwtPrepare},
+		{Name: "rotate", Usage: "sign with the upcoming or a new key pair", Run: jwtRotate},
+	},
+}
+
+var generateKey = rsa.GenerateKey
+
+// jwtPrepare writes the upcoming key pair to new files, its public key is listed as a verify key
+// and published before jwt rotate -next promotes it
+func jwtPrepare(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "jwt prepare",
+		"via jwt prepare -private <file> -public <file> [-type rsa|ecdsa|ed25519] [-bits <bits>]")
+	privateFile := fs.String("private", "", "new private key file")
+	publicFile := fs.String("public", "", "new public key file")
+	keyType := fs.String("type", "rsa", "key type, rsa, ecdsa or ed25519")
+	bits := fs.Int("bits", 2048, "rsa key size")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if *privateFile == "" || *publicFile == "" {
+		return fmt.Errorf("%w: the private and public key files are required", ErrUsage)
+	}
+
+	privatePEM, publicPEM, err := newKeyPairPEM(*keyType, *bits)
+	if err != nil {
+		return err
+	}
+	if err := writeFileNew(*publicFile, publicPEM, 0o644); err != nil {
+		return err
+	}
+	if err := writeFileNew(*privateFile, privatePEM, 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(env.Stdout, "upcoming key pair written to %s and %s\n", *privateFile, *publicFile)
+	fmt.Fprintf(env.Stdout, "list %s in JWT_VERIFY_KEY_FILES and restart the API instances, then promote it with via jwt rotate -next %s\n",
+		*publicFile, *privateFile)
+	return nil
+}
+
+// jwtRotate signs with the upcoming key pair, or a new one without -next, writing it to the
+// configured key files. The replaced public key is kept in a new file, listed as a verify key
+// the tokens it signed stay valid until they expire. The configured verify key files are never
+// written. The API instances pick the new pair up on restart.
+func jwtRotate(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "jwt rotate",
+		"via jwt rotate [-private <file>] [-public <file>] [-next <file>] [-previous <file>] [-type rsa|ecdsa|ed25519] [-bits <bits>]")
+	privateFile := fs.String("private", env.Config.JWT.PrivateKeySecretFile, "private key file")
+	publicFile := fs.String("public", env.Config.JWT.PublicKeySecretFile, "public key file")
+	nextFile := fs.String("next", "", "private key file of the upcoming pair, a new pair is generated without it")
+	previousFile := fs.String("previous", "", "new file the replaced public key is kept in, next to the public key file by default")
+	keyType := fs.String("type", "rsa", "key type of a new pair, rsa, ecdsa or ed25519")
+	bits := fs.Int("bits", 2048, "rsa key size of a new pair")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if *privateFile == "" || *publicFile == "" {
+		return fmt.Errorf("%w: the private and public key files are required", ErrUsage)
+	}
+	if *previousFile != "" && slices.Contains(env.Config.JWT.VerifyKeySecretFiles, *previousFile) {
+		return fmt.Errorf("%w: %s is a configured verify key file", ErrUsage, *previousFile)
+	}
+
+	var privatePEM, publicPEM []byte
+	var err error
+	if *nextFile != "" {
+		privatePEM, publicPEM, err = readKeyPairPEM(*nextFile)
+	} else {
+		privatePEM, publicPEM, err = newKeyPairPEM(*keyType, *bits)
+	}
+	if err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(*publicFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading %s: %w", *publicFile, err)
+	}
+	existed := err == nil
+	kept := existed && !bytes.Equal(current, publicPEM)
+	if kept {
+		if *previousFile == "" {
+			*previousFile = retiredFile(*publicFile, current)
+		}
+		if err := keepRetired(*previousFile, current); err != nil {
+			return err
+		}
+	}
+	// the public key goes first, a failure then leaves the previous pair usable
+	if err := writeFileAtomic(*publicFile, publicPEM, 0o644); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(*privateFile, privatePEM, 0o600); err != nil {
+		return err
+	}
+	if *nextFile != "" {
+		fmt.Fprintf(env.Stdout, "upcoming key pair %s written to %s and %s\n", *nextFile, *privateFile, *publicFile)
+	} else {
+		fmt.Fprintf(env.Stdout, "new key pair written to %s and %s\n", *privateFile, *publicFile)
+	}
+	if kept {
+		fmt.Fprintf(env.Stdout, "the previous public key was kept in %s, list it in JWT_VERIFY_KEY_FILES until the tokens it signed expire\n",
+			*previousFile)
+	}
+	if existed {
+		fmt.Fprintln(env.Stdout, "restart the API instances")
+		return nil
+	}
+	fmt.Fprintln(env.Stdout, "restart the API instances, the tokens issued until now will be rejected")
+	return nil
+}
+
+// newKeyPairPEM generates a key pair, returning its private and public PEM
+func newKeyPairPEM(keyType string, bits int) ([]byte, []byte, error) {
+	privateBlock, public, err := newKeyPair(keyType, bits)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicPEM, err := publicKeyPEM(public)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(privateBlock), publicPEM, nil
+}
+
+// readKeyPairPEM reads the private key of a prepared pair, returning it with its public PEM
+func readKeyPairPEM(privateFile string) ([]byte, []byte, error) {
+	privatePEM, err := os.ReadFile(privateFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", privateFile, err)
+	}
+	private, err := jwt_key.ParsePrivateKeyFromPEM(string(privatePEM))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", privateFile, err)
+	}
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("reading %s: unsupported key type %T", privateFile, private)
+	}
+	publicPEM, err := publicKeyPEM(signer.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+	return privatePEM, publicPEM, nil
+}
+
+func publicKeyPEM(public crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("encoding public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// retiredFile names the file a replaced public key is kept in after its content, public.pem
+// becomes public.retired-<hash>.pem
+func retiredFile(publicFile string, public []byte) string {
+	sum := sha256.Sum256(public)
+	ext := filepath.Ext(publicFile)
+	return strings.TrimSuffix(publicFile, ext) + ".retired-" + hex.EncodeToString(sum[:6]) + ext
+}
+
+// keepRetired writes the replaced public key to a new file, a file already keeping it is left as is
+func keepRetired(name string, public []byte) error {
+	err := writeFileNew(name, public, 0o644)
+	if !errors.Is(err, os.ErrExist) {
+		return err
+	}
+	if kept, readErr := os.ReadFile(name); readErr == nil && bytes.Equal(kept, public) {
+		return nil
+	}
+	return err
+}
+
+// newKeyPair generates a signing key, returning its private PEM block and its public key
+func newKeyPair(keyType string, bits int) (*pem.Block, crypto.PublicKey, error) {
+	switch keyType {
+	case "rsa":
+		key, err := generateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generating key: %w", err)
+		}
+		return &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}, &key.PublicKey, nil
+	case "ecdsa":
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generating key: %w", err)
+		}
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encoding private key: %w", err)
+		}
+		return &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}, &key.PublicKey, nil
+	case "ed25519":
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generating key: %w", err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(private)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encoding private key: %w", err)
+		}
+		return &pem.Block{Type: "PRIVATE KEY", Bytes: der}, public, nil
+	}
+	return nil, nil, fmt.Errorf("%w: unknown key type %s", ErrUsage, keyType)
+}
+
+// writeFileNew writes a file that must not exist yet
+func writeFileNew(name string, data []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(name)
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(name)
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeFileAtomic(name string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
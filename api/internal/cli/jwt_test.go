@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJwtRotate(t *testing.T) {
+	dir := t.TempDir()
+	privateFile := filepath.Join(dir, "private.pem")
+	publicFile := filepath.Join(dir, "public.pem")
+	env, stdout, _, _ := newTestEnv(nil)
+	env.Config.JWT.PrivateKeySecretFile = privateFile
+	env.Config.JWT.PublicKeySecretFile = publicFile
+
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"jwt", "rotate", "-bits", "1024"}))
+	assert.Contains(t, stdout.String(), "new key pair written to "+privateFile)
+
+	privatePEM, err := os.ReadFile(privateFile)
+	assert.NoError(t, err)
+	publicPEM, err := os.ReadFile(publicFile)
+	assert.NoError(t, err)
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+	assert.NoError(t, err)
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+	assert.NoError(t, err)
+	assert.True(t, privateKey.PublicKey.Equal(publicKey))
+	info, err := os.Stat(privateFile)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestJwtRotateErrors(t *testing.T) {
+	env, _, stderr, _ := newTestEnv(nil)
+	assert.Equal(t, 2, Run(context.Background(), env, []string{"jwt", "rotate"}))
+	assert.Contains(t, stderr.String(), "the private and public key files are required")
+
+	missingDir := filepath.Join(t.TempDir(), "missing")
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"jwt", "rotate", "-bits", "1024",
+		"-private", filepath.Join(missingDir, "private.pem"), "-public", filepath.Join(missingDir, "public.pem")}))
+
+	generateKey = func(io.Reader, int) (*rsa.PrivateKey, error) { return nil, errors.New("no entropy") }
+	t.Cleanup(func() { generateKey = rsa.GenerateKey })
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"jwt", "rotate", "-private", "a", "-public", "b"}))
+	assert.Contains(t, stderr.String(), "generating key: no entropy")
+}
+
+func TestJwtRotateKeepsPrevious(t *testing.T) {
+	dir := t.TempDir()
+	privateFile := filepath.Join(dir, "private.pem")
+	publicFile := filepath.Join(dir, "public.pem")
+	upcomingFile := filepath.Join(dir, "upcoming.pem")
+	assert.NoError(t, os.WriteFile(publicFile, []byte("current"), 0o644))
+	assert.NoError(t, os.WriteFile(upcomingFile, []byte("upcoming"), 0o644))
+	env, stdout, stderr, _ := newTestEnv(nil)
+	env.Config.JWT.PrivateKeySecretFile = privateFile
+	env.Config.JWT.PublicKeySecretFile = publicFile
+	env.Config.JWT.VerifyKeySecretFiles = []string{upcomingFile}
+
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"jwt", "rotate", "-type", "ecdsa"}))
+
+	retired := retiredFile(publicFile, []byte("current"))
+	assert.Regexp(t, `/public\.retired-[0-9a-f]{12}\.pem$`, retired)
+	previous, err := os.ReadFile(retired)
+	assert.NoError(t, err)
+	assert.Equal(t, "current", string(previous))
+	upcoming, err := os.ReadFile(upcomingFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "upcoming", string(upcoming))
+	assert.Contains(t, stdout.String(), "the previous public key was kept in "+retired)
+	privatePEM, err := os.ReadFile(privateFile)
+	assert.NoError(t, err)
+	_, err = jwt.ParseECPrivateKeyFromPEM(privatePEM)
+	assert.NoError(t, err)
+
+	// a configured verify key file is never written
+	assert.Equal(t, 2, Run(context.Background(), env, []string{"jwt", "rotate", "-previous", upcomingFile}))
+	assert.Contains(t, stderr.String(), upcomingFile+" is a configured verify key file")
+
+	// an existing file is not overwritten
+	other := filepath.Join(dir, "other.pem")
+	assert.NoError(t, os.WriteFile(other, []byte("other"), 0o644))
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"jwt", "rotate", "-bits", "1024", "-previous", other}))
+	kept, err := os.ReadFile(other)
+	assert.NoError(t, err)
+	assert.Equal(t, "other", string(kept))
+}
+
+func TestJwtPrepareAndPromote(t *testing.T) {
+	dir := t.TempDir()
+	privateFile := filepath.Join(dir, "private.pem")
+	publicFile := filepath.Join(dir, "public.pem")
+	nextPrivate := filepath.Join(dir, "next-private.pem")
+	nextPublic := filepath.Join(dir, "next-public.pem")
+	env, stdout, stderr, _ := newTestEnv(nil)
+	env.Config.JWT.PrivateKeySecretFile = privateFile
+	env.Config.JWT.PublicKeySecretFile = publicFile
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"jwt", "rotate", "-bits", "1024"}))
+	current, err := os.ReadFile(publicFile)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"jwt", "prepare", "-type", "ed25519",
+		"-private", nextPrivate, "-public", nextPublic}))
+	assert.Contains(t, stdout.String(), "via jwt rotate -next "+nextPrivate)
+	upcoming, err := os.ReadFile(nextPublic)
+	assert.NoError(t, err)
+	info, err := os.Stat(nextPrivate)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// the prepared files are never overwritten
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"jwt", "prepare",
+		"-private", nextPrivate, "-public", nextPublic}))
+	assert.Equal(t, 2, Run(context.Background(), env, []string{"jwt", "prepare"}))
+	assert.Contains(t, stderr.String(), "the private and public key files are required")
+
+	env.Config.JWT.VerifyKeySecretFiles = []string{nextPublic}
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"jwt", "rotate", "-next", nextPrivate}))
+
+	promoted, err := os.ReadFile(publicFile)
+	assert.NoError(t, err)
+	assert.Equal(t, string(upcoming), string(promoted))
+	nextPEM, err := os.ReadFile(nextPrivate)
+	assert.NoError(t, err)
+	privatePEM, err := os.ReadFile(privateFile)
+	assert.NoError(t, err)
+	assert.Equal(t, string(nextPEM), string(privatePEM))
+	retired, err := os.ReadFile(retiredFile(publicFile, current))
+	assert.NoError(t, err)
+	assert.Equal(t, string(current), string(retired))
+
+	// promoted again, nothing is replaced
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"jwt", "rotate", "-next", nextPrivate}))
+
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"jwt", "rotate", "-next", filepath.Join(dir, "missing.pem")}))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.pem"), []byte("invalid"), 0o600))
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"jwt", "rotate", "-next", filepath.Join(dir, "invalid.pem")}))
+	assert.Contains(t, stderr.String(), "private key must be a PEM encoded")
+}
+
+func TestJwtRotateKeyTypes(t *testing.T) {
+	dir := t.TempDir()
+	privateFile := filepath.Join(dir, "private.pem")
+	publicFile := filepath.Join(dir, "public.pem")
+	env, stdout, stderr, _ := newTestEnv(nil)
+
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"jwt", "rotate", "-type", "ed25519",
+		"-private", privateFile, "-public", publicFile}))
+	assert.Contains(t, stdout.String(), "the tokens issued until now will be rejected")
+	privatePEM, err := os.ReadFile(privateFile)
+	assert.NoError(t, err)
+	_, err = jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+	assert.NoError(t, err)
+	publicPEM, err := os.ReadFile(publicFile)
+	assert.NoError(t, err)
+	_, err = jwt.ParseEdPublicKeyFromPEM(publicPEM)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, Run(context.Background(), env, []string{"jwt", "rotate", "-type", "dsa",
+		"-private", privateFile, "-public", publicFile}))
+	assert.Contains(t, stderr.String(), "unknown key type dsa")
+
+	// the public key path is a directory, it can not be kept
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"jwt", "rotate", "-bits", "1024",
+		"-private", privateFile, "-public", dir, "-previous", filepath.Join(dir, "previous.pem")}))
+	assert.Contains(t, stderr.String(), "reading "+dir)
+
+	// the previous key directory is missing
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"jwt", "rotate", "-bits", "1024",
+		"-private", privateFile, "-public", publicFile, "-previous", filepath.Join(dir, "missing", "previous.pem")}))
+}
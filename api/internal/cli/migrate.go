@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	ent_client "via/internal/client/ent"
+	db_migrate "via/internal/db/migrate"
+	db_pool "via/internal/db/pool"
+)
+
+var migrateCommand = Command{
+	Name:  "migrate",
+	Usage: "apply or inspect the database migrations",
+	Subcommands: []Command{
+		{Name: "up", Usage: "apply the pending migrations", Run: migrateUp},
+		{Name: "status", Usage: "list the migrations and when they were applied", Run: migrateStatus},
+		{Name: "check", Usage: "fail on pending migrations or a schema differing from ent", Run: migrateCheck},
+	},
+}
+
+var (
+	newMigrator = func() (*db_migrate.Migrator, error) { return db_migrate.New(db_pool.Get()) }
+	entSchema   = func() db_migrate.SchemaCreator { return ent_client.Get().Schema.Create }
+)
+
+func migrateUp(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "migrate up", "via migrate up")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	return connected(ctx, env, func() error {
+		migrator, err := newMigrator()
+		if err != nil {
+			return err
+		}
+		done, err := migrator.Up(ctx)
+		for _, migration := range done {
+			fmt.Fprintf(env.Stdout, "applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Fprintln(env.Stdout, "database is up to date")
+		}
+		return nil
+	})
+}
+
+func migrateStatus(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "migrate status", "via migrate status")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	return connected(ctx, env, func() error {
+		migrator, err := newMigrator()
+		if err != nil {
+			return err
+		}
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return tw.Flush()
+	})
+}
+
+func migrateCheck(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "migrate check", "via migrate check")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	return connected(ctx, env, func() error {
+		migrator, err := newMigrator()
+		if err != nil {
+			return err
+		}
+		if err := migrator.Verify(ctx); err != nil {
+			return err
+		}
+		changes, err := db_migrate.CheckDrift(ctx, entSchema())
+		for _, change := range changes {
+			fmt.Fprintln(env.Stdout, change)
+		}
+		if len(changes) > 0 {
+			return fmt.Errorf("%w, ent would run the %d statements above", db_migrate.ErrSchemaDrift, len(changes))
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(env.Stdout, "database matches the migrations and the ent schema")
+		return nil
+	})
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	db_migrate "via/internal/db/migrate"
+	"via/internal/testutil"
+
+	"entgo.io/ent/dialect/sql/schema"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// withMockMigrator points the migrate commands to a sqlmock database with no migration applied
+func withMockMigrator(t *testing.T) sqlmock.Sqlmock {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	original := newMigrator
+	newMigrator = func() (*db_migrate.Migrator, error) { return db_migrate.New(db) }
+	t.Cleanup(func() { newMigrator = original })
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	return mock
+}
+
+func noAppliedMigrations() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"})
+}
+
+func TestMigrateStatus(t *testing.T) {
+	mock := withMockMigrator(t)
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").WillReturnRows(noAppliedMigrations())
+	env, stdout, _, _ := newTestEnv(nil)
+
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"migrate", "status"}))
+	assert.Contains(t, stdout.String(), "0001     baseline")
+	assert.Contains(t, stdout.String(), "pending")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrateUp(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mock := withMockMigrator(t)
+	mock.ExpectExec("SELECT pg_advisory_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").WillReturnRows(noAppliedMigrations())
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS operators").WillReturnError(errors.New("permission denied"))
+	mock.ExpectRollback()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WillReturnResult(sqlmock.NewResult(0, 0))
+	env, _, stderr, _ := newTestEnv(nil)
+
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"migrate", "up"}))
+	assert.Contains(t, stderr.String(), "migration 1_baseline: permission denied")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrateCheck(t *testing.T) {
+	t.Run("pending migrations", func(t *testing.T) {
+		mock := withMockMigrator(t)
+		mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").WillReturnRows(noAppliedMigrations())
+		env, _, stderr, _ := newTestEnv(nil)
+
+		assert.Equal(t, 1, Run(context.Background(), env, []string{"migrate", "check"}))
+		assert.Contains(t, stderr.String(), db_migrate.ErrPendingMigrations.Error())
+	})
+
+	t.Run("migrator error", func(t *testing.T) {
+		original := newMigrator
+		newMigrator = func() (*db_migrate.Migrator, error) { return nil, errors.New("no database") }
+		t.Cleanup(func() { newMigrator = original })
+		env, _, stderr, _ := newTestEnv(nil)
+
+		assert.Equal(t, 1, Run(context.Background(), env, []string{"migrate", "check"}))
+		assert.Contains(t, stderr.String(), "no database")
+	})
+}
+
+func TestMigrateCheckDrift(t *testing.T) {
+	tests := []struct {
+		name         string
+		planErr      error
+		expectCode   int
+		expectStdout string
+		expectStderr string
+	}{
+		{name: "schema matches", expectStdout: "database matches the migrations and the ent schema\n"},
+		{name: "planning error", planErr: errors.New("inspect error"), expectCode: 1,
+			expectStderr: "planning ent schema: inspect error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := withMockMigrator(t)
+			migrator, _ := newMigrator()
+			applied := noAppliedMigrations()
+			for _, m := range migrator.Migrations() {
+				applied.AddRow(m.Version, m.Name, m.Checksum, time.Now())
+			}
+			mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").WillReturnRows(applied)
+			original := entSchema
+			entSchema = func() db_migrate.SchemaCreator {
+				return func(ctx context.Context, opts ...schema.MigrateOption) error { return tt.planErr }
+			}
+			t.Cleanup(func() { entSchema = original })
+			env, stdout, stderr, _ := newTestEnv(nil)
+
+			assert.Equal(t, tt.expectCode, Run(context.Background(), env, []string{"migrate", "check"}))
+			assert.Equal(t, tt.expectStdout, stdout.String())
+			assert.Contains(t, stderr.String(), tt.expectStderr)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"via/internal/auth"
+	biz_audit "via/internal/biz/audit"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/model"
+	operator_provider "via/internal/provider/operator"
+	"via/internal/session"
+	"via/internal/totp"
+)
+
+const (
+	minPasswordLength = 12
+	maxPasswordLength = 72 // bcrypt ignores the bytes past it
+	totpIssuer        = "via"
+)
+
+var operatorCommand = Command{
+	Name:  "operator",
+	Usage: "add, disable or list operators, link their IdP accounts and set their local login",
+	Subcommands: []Command{
+		{Name: "add", Usage: "add an operator", Run: operatorAdd},
+		{Name: "disable", Usage: "disable an operator", Run: operatorDisable},
+		{Name: "list", Usage: "list the operators", Run: operatorList},
+		{Name: "link", Usage: "link an IdP account to an operator", Run: operatorLink},
+		{Name: "local-login", Usage: "set or remove the emergency local login", Run: operatorLocalLogin},
+	},
+}
+
+func operatorAdd(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "operator add", "via operator add [-role operator|supervisor|admin] [-disabled] <account> <name>")
+	role := fs.String("role", biz_operator.ROLE_OPERATOR, "operator role")
+	disabled := fs.Bool("disabled", false, "create the operator disabled")
+	args, err := parseFlags(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	if !biz_operator.IsValidRole(*role) {
+		return fmt.Errorf("%w: unknown role %q", ErrUsage, *role)
+	}
+
+	return connected(ctx, env, func() error {
+		id, err := operator_provider.Get().CreateOperator(ctx, model.Operator{
+			Account: args[0], Name: args[1], Role: *role, Enabled: !*disabled})
+		if err != nil {
+			return err
+		}
+		recordAudit(ctx, biz_audit.OPERATOR_ADD, biz_audit.TARGET_OPERATOR, strconv.Itoa(id), nil,
+			map[string]any{"account": args[0], "role": *role, "enabled": !*disabled})
+		fmt.Fprintf(env.Stdout, "operator %s added with id %d\n", args[0], id)
+		return nil
+	})
+}
+
+func operatorDisable(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "operator disable", "via operator disable <account>")
+	args, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	return connected(ctx, env, func() error {
+		found, err := operator_provider.Get().SetOperatorEnabled(ctx, args[0], false)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("operator %s not found", args[0])
+		}
+		// the sessions already open would otherwise last until their refresh token expires
+		operator, err := operator_provider.Get().GetOperatorByAccount(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		revoked, err := session.Get().RevokeAll(ctx, operator.ID)
+		if err != nil {
+			return err
+		}
+		recordAudit(ctx, biz_audit.OPERATOR_DISABLE, biz_audit.TARGET_OPERATOR, strconv.Itoa(operator.ID), nil,
+			map[string]any{"enabled": false, "revokedSessions": revoked})
+		fmt.Fprintf(env.Stdout, "operator %s disabled, %d sessions revoked\n", args[0], revoked)
+		return nil
+	})
+}
+
+func operatorList(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "operator list", "via operator list")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	return connected(ctx, env, func() error {
+		operators, err := operator_provider.Get().GetOperators(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tACCOUNT\tNAME\tROLE\tENABLED")
+		for _, op := range operators {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%t\n", op.ID, op.Account, op.Name, op.Role, op.Enabled)
+		}
+		return tw.Flush()
+	})
+}
+
+// operatorLink links the IdP account to the operator, the subject is logged by its refused login
+func operatorLink(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "operator link", "via operator link [-provider <name>] <account> <subject>")
+	providerName := fs.String("provider", auth.DefaultProvider, "identity provider of the account")
+	args, err := parseFlags(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	provider, ok := env.Config.OAuth.GetProviders()[*providerName]
+	if !ok {
+		return fmt.Errorf("%w: unknown provider %q", ErrUsage, *providerName)
+	}
+
+	return connected(ctx, env, func() error {
+		operator, err := operator_provider.Get().GetOperatorByAccount(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if operator.ID == 0 {
+			return fmt.Errorf("operator %s not found", args[0])
+		}
+		if err := operator_provider.Get().LinkOperatorIdentity(ctx, operator.ID, provider.Issuer, args[1]); err != nil {
+			return err
+		}
+		recordAudit(ctx, biz_audit.OPERATOR_LINK, biz_audit.TARGET_OPERATOR, strconv.Itoa(operator.ID), nil,
+			map[string]string{"provider": *providerName, "issuer": provider.Issuer, "subject": args[1]})
+		fmt.Fprintf(env.Stdout, "%s account %s linked to operator %s\n", *providerName, args[1], args[0])
+		return nil
+	})
+}
+
+// operatorLocalLogin sets the password, read from stdin, and a new TOTP secret of the operator, the
+// secret is printed once to be added to an authenticator app
+func operatorLocalLogin(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "operator local-login", "via operator local-login [-remove] <account> < password")
+	remove := fs.Bool("remove", false, "remove the local login of the operator")
+	args, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var credentials model.OperatorCredentials
+	if !*remove {
+		password, err := readPassword(env)
+		if err != nil {
+			return err
+		}
+		credentials.PasswordHash, err = auth.HashPassword(password)
+		if err != nil {
+			return err
+		}
+		credentials.TOTPSecret, err = totp.GenerateSecret()
+		if err != nil {
+			return err
+		}
+	}
+
+	return connected(ctx, env, func() error {
+		found, err := operator_provider.Get().SetOperatorCredentials(ctx, args[0], credentials)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("operator %s not found", args[0])
+		}
+		// the credentials are never audited, only whether the operator has them
+		recordAudit(ctx, biz_audit.OPERATOR_LOCAL_LOGIN, biz_audit.TARGET_OPERATOR, args[0], nil,
+			map[string]bool{"localLogin": !*remove})
+		if *remove {
+			fmt.Fprintf(env.Stdout, "local login of operator %s removed\n", args[0])
+			return nil
+		}
+		fmt.Fprintf(env.Stdout, "local login of operator %s set\nTOTP secret: %s\nTOTP uri: %s\n", args[0],
+			credentials.TOTPSecret, totp.URI(totpIssuer, args[0], credentials.TOTPSecret))
+		return nil
+	})
+}
+
+// readPassword reads the password from the first line of stdin
+func readPassword(env Env) (string, error) {
+	line, err := bufio.NewReader(env.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("%w: the password is read from stdin", ErrUsage)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return "", fmt.Errorf("%w: the password must have between %d and %d characters", ErrUsage,
+			minPasswordLength, maxPasswordLength)
+	}
+	return password, nil
+}
@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"via/internal/auth"
+	biz_operator "via/internal/biz/operator"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/model"
+	operator_provider "via/internal/provider/operator"
+	mock_operator_provider "via/internal/provider/operator/mock"
+	"via/internal/session"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestOperatorAdd(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expected     model.Operator
+		createErr    error
+		expectCode   int
+		expectStdout string
+	}{
+		{name: "operator added", args: []string{"ana", "Ana Perez"},
+			expected:   model.Operator{Account: "ana", Name: "Ana Perez", Role: biz_operator.ROLE_OPERATOR, Enabled: true},
+			expectCode: 0, expectStdout: "operator ana added with id 7\n"},
+		{name: "disabled supervisor added", args: []string{"-role", "supervisor", "-disabled", "ana", "Ana Perez"},
+			expected:   model.Operator{Account: "ana", Name: "Ana Perez", Role: biz_operator.ROLE_SUPERVISOR},
+			expectCode: 0, expectStdout: "operator ana added with id 7\n"},
+		{name: "unknown role", args: []string{"-role", "root", "ana", "Ana Perez"}, expectCode: 2},
+		{name: "missing name", args: []string{"ana"}, expectCode: 2},
+		{name: "create error", args: []string{"ana", "Ana Perez"}, createErr: errors.New("duplicated account"),
+			expected:   model.Operator{Account: "ana", Name: "Ana Perez", Role: biz_operator.ROLE_OPERATOR, Enabled: true},
+			expectCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockOperator.On("CreateOperator", context.Background(), tt.expected).Return(7, tt.createErr)
+			env, stdout, _, released := newTestEnv(nil)
+
+			code := Run(context.Background(), env, append([]string{"operator", "add"}, tt.args...))
+
+			assert.Equal(t, tt.expectCode, code)
+			assert.Equal(t, tt.expectStdout, stdout.String())
+			if tt.expectCode == 2 {
+				mockOperator.AssertNotCalled(t, "CreateOperator")
+				assert.Zero(t, *released)
+				return
+			}
+			assert.Equal(t, 1, *released)
+		})
+	}
+}
+
+func TestOperatorDisable(t *testing.T) {
+	tests := []struct {
+		name         string
+		found        bool
+		err          error
+		getErr       error
+		sessions     []string
+		delErr       error
+		expectCode   int
+		expectStdout string
+	}{
+		{name: "operator disabled", found: true, sessions: []string{"s1", "s2"},
+			expectStdout: "operator ana disabled, 2 sessions revoked\n"},
+		{name: "operator disabled without sessions", found: true,
+			expectStdout: "operator ana disabled, 0 sessions revoked\n"},
+		{name: "operator not found", expectCode: 1},
+		{name: "update error", err: errors.New("db error"), expectCode: 1},
+		{name: "get operator error", found: true, getErr: errors.New("db error"), expectCode: 1},
+		{name: "revoke error", found: true, sessions: []string{"s1"}, delErr: errors.New("ds error"), expectCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockDS := new(mock_ds.MockDS)
+			session.Set(session.New(mockDS, 60))
+			mockOperator.On("SetOperatorEnabled", context.Background(), "ana", false).Return(tt.found, tt.err)
+			mockOperator.On("GetOperatorByAccount", context.Background(), "ana").
+				Return(model.Operator{ID: 3, Account: "ana"}, tt.getErr)
+			mockDS.On("SMembers", context.Background(), "sessions:operator:3:ids").Return(tt.sessions, nil)
+			mockDS.On("Del", context.Background(), mock.Anything).Return(tt.delErr)
+			mockDS.On("SRem", context.Background(), "sessions:operator:3:ids", mock.Anything).Return(nil)
+			env, stdout, _, _ := newTestEnv(nil)
+
+			code := Run(context.Background(), env, []string{"operator", "disable", "ana"})
+
+			assert.Equal(t, tt.expectCode, code)
+			assert.Equal(t, tt.expectStdout, stdout.String())
+			if tt.expectCode == 0 {
+				for _, id := range tt.sessions {
+					mockDS.AssertCalled(t, "SRem", context.Background(), "sessions:operator:3:ids", id)
+				}
+			}
+		})
+	}
+}
+
+func TestOperatorLink(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		operator     model.Operator
+		getErr       error
+		linkErr      error
+		expectIssuer string
+		expectCode   int
+		expectStdout string
+	}{
+		{name: "account linked", args: []string{"ana", "subject-1"}, operator: model.Operator{ID: 3, Account: "ana"},
+			expectIssuer: "https://accounts.google.com", expectStdout: "default account subject-1 linked to operator ana\n"},
+		{name: "account of a named provider linked", args: []string{"-provider", "entra", "ana", "subject-1"},
+			operator: model.Operator{ID: 3, Account: "ana"}, expectIssuer: "https://login.microsoftonline.com/t/v2.0",
+			expectStdout: "entra account subject-1 linked to operator ana\n"},
+		{name: "unknown provider", args: []string{"-provider", "okta", "ana", "subject-1"}, expectCode: 2},
+		{name: "missing subject", args: []string{"ana"}, expectCode: 2},
+		{name: "operator not found", args: []string{"ana", "subject-1"}, expectCode: 1},
+		{name: "get operator error", args: []string{"ana", "subject-1"}, getErr: errors.New("db error"), expectCode: 1},
+		{name: "link error", args: []string{"ana", "subject-1"}, operator: model.Operator{ID: 3, Account: "ana"},
+			linkErr: errors.New("duplicate key"), expectIssuer: "https://accounts.google.com", expectCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockOperator.On("GetOperatorByAccount", context.Background(), "ana").Return(tt.operator, tt.getErr)
+			if tt.expectIssuer != "" {
+				mockOperator.On("LinkOperatorIdentity", context.Background(), 3, tt.expectIssuer, "subject-1").
+					Return(tt.linkErr).Once()
+			}
+			env, stdout, _, _ := newTestEnv(nil)
+			env.Config.OAuth.ClientID = "google"
+			env.Config.OAuth.IDPIssuer = "https://accounts.google.com"
+			env.Config.OAuth.ProviderConfigs = map[string]auth.ProviderConfig{
+				"entra": {ClientID: "entra", Issuer: "https://login.microsoftonline.com/t/v2.0"}}
+
+			code := Run(context.Background(), env, append([]string{"operator", "link"}, tt.args...))
+
+			assert.Equal(t, tt.expectCode, code)
+			assert.Equal(t, tt.expectStdout, stdout.String())
+			if tt.expectCode == 2 {
+				mockOperator.AssertNotCalled(t, "GetOperatorByAccount")
+				return
+			}
+			mockOperator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOperatorList(t *testing.T) {
+	mockOperator := new(mock_operator_provider.MockOperatorProvider)
+	operator_provider.Set(mockOperator)
+	mockOperator.On("GetOperators", context.Background()).Return([]model.Operator{
+		{ID: 2, Account: "ana", Name: "Ana", Role: biz_operator.ROLE_ADMIN, Enabled: true},
+	}, nil).Once()
+	env, stdout, _, _ := newTestEnv(nil)
+
+	assert.Equal(t, 0, Run(context.Background(), env, []string{"operator", "list"}))
+	assert.Equal(t, "ID  ACCOUNT  NAME  ROLE   ENABLED\n2   ana      Ana   admin  true\n", stdout.String())
+
+	mockOperator.On("GetOperators", context.Background()).Return([]model.Operator{}, errors.New("db error")).Once()
+	assert.Equal(t, 1, Run(context.Background(), env, []string{"operator", "list"}))
+}
+
+func TestOperatorLocalLogin(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		stdin        string
+		found        bool
+		setErr       error
+		expectCode   int
+		expectStdout string
+		expectStderr string
+	}{
+		{name: "local login set", args: []string{"ana"}, stdin: "correct horse battery\n", found: true,
+			expectStdout: "local login of operator ana set\nTOTP secret: "},
+		{name: "password without newline", args: []string{"ana"}, stdin: "correct horse battery", found: true,
+			expectStdout: "local login of operator ana set\nTOTP secret: "},
+		{name: "local login removed", args: []string{"-remove", "ana"}, found: true,
+			expectStdout: "local login of operator ana removed\n"},
+		{name: "missing password", args: []string{"ana"}, expectCode: 2,
+			expectStderr: "the password is read from stdin"},
+		{name: "short password", args: []string{"ana"}, stdin: "secret\n", expectCode: 2,
+			expectStderr: "the password must have between 12 and 72 characters"},
+		{name: "missing account", args: []string{}, expectCode: 2},
+		{name: "operator not found", args: []string{"ana"}, stdin: "correct horse battery\n", expectCode: 1,
+			expectStderr: "operator ana not found"},
+		{name: "update error", args: []string{"-remove", "ana"}, setErr: errors.New("db error"), expectCode: 1,
+			expectStderr: "db error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			var credentials model.OperatorCredentials
+			mockOperator.On("SetOperatorCredentials", context.Background(), "ana", mock.Anything).
+				Run(func(args mock.Arguments) { credentials = args.Get(2).(model.OperatorCredentials) }).
+				Return(tt.found, tt.setErr)
+			env, stdout, stderr, _ := newTestEnv(nil)
+			env.Stdin = strings.NewReader(tt.stdin)
+
+			code := Run(context.Background(), env, append([]string{"operator", "local-login"}, tt.args...))
+
+			assert.Equal(t, tt.expectCode, code)
+			assert.True(t, strings.HasPrefix(stdout.String(), tt.expectStdout), stdout.String())
+			assert.Contains(t, stderr.String(), tt.expectStderr)
+			switch {
+			case tt.expectCode == 2:
+				mockOperator.AssertNotCalled(t, "SetOperatorCredentials")
+			case strings.Contains(tt.expectStdout, "set"):
+				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(credentials.PasswordHash), []byte("correct horse battery")))
+				assert.Len(t, credentials.TOTPSecret, 32)
+				assert.Contains(t, stdout.String(), "TOTP uri: otpauth://totp/via:ana?")
+				assert.Contains(t, stdout.String(), credentials.TOTPSecret)
+			case tt.args[0] == "-remove":
+				assert.Empty(t, credentials)
+			}
+		})
+	}
+}
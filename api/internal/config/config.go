@@ -1,18 +1,31 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strings"
 	"sync"
 	"via/internal/auth"
 	biz_config "via/internal/biz/config"
+	biz_device "via/internal/biz/device"
+	biz_guide_assign "via/internal/biz/guide/assign"
+	biz_guide_closeday "via/internal/biz/guide/closeday"
+	biz_guide_expiry "via/internal/biz/guide/expiry"
+	biz_guide_reconcile "via/internal/biz/guide/reconcile"
 	http_client "via/internal/client/http"
+	db_migrate "via/internal/db/migrate"
 	db_pool "via/internal/db/pool"
 	"via/internal/ds"
+	"via/internal/idp"
 	jwt_key "via/internal/jwt"
 	app_log "via/internal/log/app"
 	"via/internal/middleware"
+	"via/internal/presence"
+	via_guide_file_provider "via/internal/provider/via/guide/file"
+	via_guide_resilient_provider "via/internal/provider/via/guide/resilient"
 	"via/internal/pubsub"
+	"via/internal/ratelimit"
 	"via/internal/server"
 
 	"github.com/caarlos0/env/v10"
@@ -22,22 +35,42 @@ type Application struct {
 	Env  string `env:"ENV"  envDefault:"production"    json:"env"`
 	Name string `env:"NAME" envDefault:"via"           json:"name"`
 	//Port           int    `env:"PORT" envDefault:"8080"          json:"port"`
-	RequestTimeout int `env:"REQUEST_TIMEOUT" envDefault:"30" json:"requestTimeout"`
+	RequestTimeout int    `env:"REQUEST_TIMEOUT" envDefault:"30" json:"requestTimeout"`
+	MaxBodyBytes   int64  `env:"MAX_BODY_BYTES" envDefault:"1048576" json:"maxBodyBytes"` // bodies read by the request validation
+	ViaGuideSource string `env:"VIA_GUIDE_SOURCE" envDefault:"web" json:"viaGuideSource"` // web, api or fixture
+	ErrorDocsURL   string `env:"ERROR_DOCS_URL" json:"errorDocsUrl"`                      // page documenting the error codes
 }
 
 type Config struct {
-	Log            app_log.LogCfg            `envPrefix:"LOG_" json:"log"`
-	Application    Application               `envPrefix:"APP_" json:"application"`
-	Database       db_pool.DatabaseCfg       `envPrefix:"DB_" json:"db"`
-	CORS           middleware.CORSCfg        `envPrefix:"CORS_" json:"cors"`
-	GuideWebClient http_client.HttpClientCfg `envPrefix:"GUIDE_WEB_CLIENT_" json:"guideWebClient"`
-	Bussiness      biz_config.BussinessCfg   `envPrefix:"BUSSINESS_" json:"bussiness"`
-	OAuth          auth.OAuthConfig          `envPrefix:"OAUTH_" json:"oauth"`
-	JWT            jwt_key.JWTConfig         `envPrefix:"JWT_" json:"jwt"`
-	DS             ds.DSConfig               `envPrefix:"DS_" json:"ds"`
-	RestServer     server.ServerConfig       `envPrefix:"REST_" json:"rest"`
-	SSEServer      server.ServerConfig       `envPrefix:"SSE_" json:"sse"`
-	PubSub         pubsub.PubSubConfig       `envPrefix:"PUBSUB_" json:"pubsub"`
+	Log                  app_log.LogCfg                             `envPrefix:"LOG_" json:"log"`
+	Application          Application                                `envPrefix:"APP_" json:"application"`
+	Database             db_pool.DatabaseCfg                        `envPrefix:"DB_" json:"db"`
+	Migrate              db_migrate.MigrateCfg                      `envPrefix:"MIGRATE_" json:"migrate"`
+	CORS                 middleware.CORSCfg                         `envPrefix:"CORS_" json:"cors"`
+	GuideWebClient       http_client.HttpClientCfg                  `envPrefix:"GUIDE_WEB_CLIENT_" json:"guideWebClient"`
+	GuideAPIClient       http_client.HttpClientCfg                  `envPrefix:"GUIDE_API_CLIENT_" json:"guideApiClient"`
+	GuideFixture         via_guide_file_provider.FileProviderCfg    `envPrefix:"GUIDE_FIXTURE_" json:"guideFixture"`
+	Bussiness            biz_config.BussinessCfg                    `envPrefix:"BUSSINESS_" json:"bussiness"`
+	OAuth                auth.OAuthConfig                           `envPrefix:"OAUTH_" json:"oauth"`
+	JWT                  jwt_key.JWTConfig                          `envPrefix:"JWT_" json:"jwt"`
+	DS                   ds.DSConfig                                `envPrefix:"DS_" json:"ds"`
+	RestServer           server.ServerConfig                        `envPrefix:"REST_" json:"rest"`
+	SSEServer            server.ServerConfig                        `envPrefix:"SSE_" json:"sse"`
+	PubSub               pubsub.PubSubConfig                        `envPrefix:"PUBSUB_" json:"pubsub"`
+	Device               biz_device.DeviceCfg                       `envPrefix:"DEVICE_" json:"device"`
+	DevicePairingLockout ratelimit.LockoutCfg                       `envPrefix:"DEVICE_PAIRING_LOCKOUT_" json:"devicePairingLockout"`
+	DeviceAuthLimit      ratelimit.LimitCfg                         `envPrefix:"DEVICE_AUTH_LIMIT_" json:"deviceAuthLimit"`
+	DeviceAuthLockout    ratelimit.LockoutCfg                       `envPrefix:"DEVICE_AUTH_LOCKOUT_" json:"deviceAuthLockout"`
+	GuideLookupLimit     ratelimit.LimitCfg                         `envPrefix:"GUIDE_LOOKUP_LIMIT_" json:"guideLookupLimit"`
+	GuideLookupLockout   ratelimit.LockoutCfg                       `envPrefix:"GUIDE_LOOKUP_LOCKOUT_" json:"guideLookupLockout"`
+	LocalLoginLockout    ratelimit.LockoutCfg                       `envPrefix:"LOCAL_LOGIN_LOCKOUT_" json:"localLoginLockout"`
+	GuideProvider        via_guide_resilient_provider.ResilienceCfg `envPrefix:"GUIDE_PROVIDER_" json:"guideProvider"`
+	Reconcile            biz_guide_reconcile.ReconcileCfg           `envPrefix:"RECONCILE_" json:"reconcile"`
+	Expiry               biz_guide_expiry.ExpiryCfg                 `envPrefix:"EXPIRY_" json:"expiry"`
+	CloseDay             biz_guide_closeday.CloseDayCfg             `envPrefix:"CLOSE_DAY_" json:"closeDay"`
+	Assign               biz_guide_assign.AssignCfg                 `envPrefix:"ASSIGN_" json:"assign"`
+	Presence             presence.PresenceCfg                       `envPrefix:"PRESENCE_" json:"presence"`
+	IDP                  idp.IDPCfg                                 `envPrefix:"IDP_" json:"idp"`
 }
 
 var (
@@ -58,6 +91,9 @@ func Get() Config {
 		if err := env.ParseWithOptions(&cfg, opts); err != nil {
 			log.Fatalf("❌ Error loading config: %v", err)
 		}
+		if err := loadProviders(&cfg.OAuth); err != nil {
+			log.Fatalf("❌ Error loading config: %v", err)
+		}
 
 		if cfg.Log.DefaultWriter.Output == nil {
 			cfg.Log.DefaultWriter.Output = os.Stdout
@@ -67,6 +103,23 @@ func Get() Config {
 	return *instance
 }
 
+// loadProviders reads the OAUTH_<NAME>_ variables of each named identity provider
+func loadProviders(cfg *auth.OAuthConfig) error {
+	cfg.ProviderConfigs = map[string]auth.ProviderConfig{}
+	for _, name := range cfg.Providers {
+		if name == auth.DefaultProvider || name == auth.LocalProvider {
+			return fmt.Errorf("identity provider name %s is reserved", name)
+		}
+		var provider auth.ProviderConfig
+		prefix := "OAUTH_" + strings.ToUpper(name) + "_"
+		if err := env.ParseWithOptions(&provider, env.Options{Prefix: prefix}); err != nil {
+			return fmt.Errorf("identity provider %s: %w", name, err)
+		}
+		cfg.ProviderConfigs[name] = provider
+	}
+	return nil
+}
+
 func reset() {
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"via/internal/auth"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -23,6 +24,8 @@ func TestGetConfig(t *testing.T) {
 	assert.Equal(t, "myapp", cfg.Application.Name)
 	//assert.Equal(t, 9090, cfg.Application.Port)
 	assert.Equal(t, 45, cfg.Application.RequestTimeout)
+	assert.Equal(t, int64(1048576), cfg.Application.MaxBodyBytes)
+	assert.Equal(t, map[string]int{"initial": 1800, "pendingRecipientIdentify": 1800}, cfg.Expiry.Timeouts)
 
 	// Check singleton behavior
 	cfg2 := Get()
@@ -62,3 +65,35 @@ func TestGet_ErrorCase(t *testing.T) {
 		t.Errorf("expected fatal error log, got: %s", string(output))
 	}
 }
+
+func TestLoadProviders(t *testing.T) {
+	t.Setenv("OAUTH_ENTRA_CLIENT_ID", "entra-client")
+	t.Setenv("OAUTH_ENTRA_ISSUER", "https://login.microsoftonline.com/tenant/v2.0")
+	t.Setenv("OAUTH_ENTRA_SCOPES", "openid,email")
+
+	tests := []struct {
+		name      string
+		providers []string
+		expected  map[string]auth.ProviderConfig
+		expectErr string
+	}{
+		{name: "no named providers", expected: map[string]auth.ProviderConfig{}},
+		{name: "named provider", providers: []string{"entra"}, expected: map[string]auth.ProviderConfig{
+			"entra": {ClientID: "entra-client", Issuer: "https://login.microsoftonline.com/tenant/v2.0",
+				Scopes: []string{"openid", "email"}},
+		}},
+		{name: "reserved name", providers: []string{"local"}, expectErr: "identity provider name local is reserved"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := auth.OAuthConfig{Providers: tt.providers}
+			err := loadProviders(&cfg)
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.ProviderConfigs)
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package db_migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"via/internal/log"
+
+	"ariga.io/atlas/sql/migrate"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql/schema"
+)
+
+//go:embed migrations/*.sql
+var embedded embed.FS
+
+var (
+	ErrInvalidMigration  = errors.New("invalid migration file")
+	ErrChecksumMismatch  = errors.New("applied migration was modified")
+	ErrUnknownMigration  = errors.New("database has a migration unknown to this binary")
+	ErrPendingMigrations = errors.New("database has pending migrations")
+	ErrSchemaDrift       = errors.New("database schema differs from the ent schema")
+)
+
+type MigrateCfg struct {
+	Auto       bool `env:"AUTO" envDefault:"true" json:"auto"`              // apply the pending migrations on startup
+	DriftCheck bool `env:"DRIFT_CHECK" envDefault:"true" json:"driftCheck"` // refuse to start when the schema differs from ent
+}
+
+// Migration is a versioned SQL script, named <version>_<description>.sql
+type Migration struct {
+	Version  int    `json:"version"`
+	Name     string `json:"name"`
+	SQL      string `json:"-"`
+	Checksum string `json:"checksum"`
+}
+
+type Status struct {
+	Migration
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+const (
+	// lockKey is the advisory lock held while migrating, so only one instance migrates
+	lockKey = 4200311
+
+	createTableQuery = `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(200) NOT NULL,
+		checksum CHAR(64) NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP)`
+	lockQuery    = `SELECT pg_advisory_lock($1)`
+	unlockQuery  = `SELECT pg_advisory_unlock($1)`
+	appliedQuery = `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`
+	insertQuery  = `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`
+)
+
+var fileNameRe = regexp.MustCompile(`^(\d+)_(\w+)\.sql$`)
+
+// Load reads the migrations of dir sorted by version
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+	var migrations []Migration
+	versions := map[int]string{}
+	for _, entry := range entries {
+		match := fileNameRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMigration, entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if other, found := versions[version]; found {
+			return nil, fmt.Errorf("%w: %s and %s share version %d", ErrInvalidMigration, other, entry.Name(), version)
+		}
+		versions[version] = entry.Name()
+		data, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", entry.Name(), err)
+		}
+		sum := sha256.Sum256(data)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     match[2],
+			SQL:      string(data),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+	return migrations, nil
+}
+
+// Migrator applies the migrations embedded in the binary, recording them in schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load(embedded, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrations returns the migrations known to the binary
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+type appliedMigration struct {
+	version   int
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func readApplied(ctx context.Context, q queryer) ([]appliedMigration, error) {
+	rows, err := q.QueryContext(ctx, appliedQuery)
+	if err != nil {
+		return nil, fmt.Errorf("reading applied migrations: %w", err)
+	}
+	defer rows.Close()
+	var applied []appliedMigration
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, fmt.Errorf("reading applied migrations: %w", err)
+		}
+		applied = append(applied, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// status matches the applied migrations against the embedded ones. An applied migration
+// that was modified afterwards, or that this binary does not know about, is an error.
+func (m *Migrator) status(applied []appliedMigration) ([]Status, error) {
+	byVersion := map[int]appliedMigration{}
+	for _, a := range applied {
+		byVersion[a.version] = a
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if a, found := byVersion[migration.Version]; found {
+			if strings.TrimSpace(a.checksum) != migration.Checksum {
+				return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+			}
+			status.AppliedAt = &a.appliedAt
+			delete(byVersion, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, a := range applied {
+		if _, unknown := byVersion[a.version]; unknown {
+			return nil, fmt.Errorf("%w: %d_%s", ErrUnknownMigration, a.version, a.name)
+		}
+	}
+	return statuses, nil
+}
+
+// Status returns every migration along with when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if _, err := m.db.ExecContext(ctx, createTableQuery); err != nil {
+		return nil, fmt.Errorf("creating schema_migrations: %w", err)
+	}
+	applied, err := readApplied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	return m.status(applied)
+}
+
+// Verify fails when a migration is pending or an applied one does not match the binary
+func (m *Migrator) Verify(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			return fmt.Errorf("%w: %d_%s", ErrPendingMigrations, status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+// Up applies the pending migrations, each one in its own transaction, and returns them.
+// Concurrent instances wait on an advisory lock, the later ones find nothing pending.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting connection: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, createTableQuery); err != nil {
+		return nil, fmt.Errorf("creating schema_migrations: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, lockQuery, lockKey); err != nil {
+		return nil, fmt.Errorf("locking migrations: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), unlockQuery, lockKey)
+
+	applied, err := readApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := m.status(applied)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.Get()
+	done := []Migration{}
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			continue
+		}
+		if err := apply(ctx, conn, status.Migration); err != nil {
+			return done, err
+		}
+		logger.Info(ctx, "msg", "migration applied", "version", status.Version, "name", status.Name)
+		done = append(done, status.Migration)
+	}
+	return done, nil
+}
+
+func apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, insertQuery, migration.Version, migration.Name, migration.Checksum); err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// SchemaCreator is the ent schema migration, usually client.Schema.Create
+type SchemaCreator func(ctx context.Context, opts ...schema.MigrateOption) error
+
+// CheckDrift plans the ent schema migration against the database without applying it,
+// returning the statements ent would run. None means the database matches the ent schema.
+func CheckDrift(ctx context.Context, create SchemaCreator) ([]string, error) {
+	changes := []string{}
+	if err := create(ctx, schema.WithApplyHook(recordChanges(&changes))); err != nil {
+		return nil, fmt.Errorf("planning ent schema: %w", err)
+	}
+	if len(changes) > 0 {
+		return changes, fmt.Errorf("%w: %s", ErrSchemaDrift, strings.Join(changes, "; "))
+	}
+	return changes, nil
+}
+
+// recordChanges is an apply hook keeping the planned statements instead of running them
+func recordChanges(changes *[]string) schema.ApplyHook {
+	return func(next schema.Applier) schema.Applier {
+		return schema.ApplyFunc(func(ctx context.Context, conn dialect.ExecQuerier, plan *migrate.Plan) error {
+			for _, change := range plan.Changes {
+				*changes = append(*changes, change.Cmd)
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package db_migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+	"via/internal/testutil"
+
+	"ariga.io/atlas/sql/migrate"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql/schema"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name           string
+		fsys           fstest.MapFS
+		expectVersions []int
+		expectErr      error
+	}{
+		{
+			name: "sorted by version",
+			fsys: fstest.MapFS{
+				"m/0010_later.sql": {Data: []byte("SELECT 10;")},
+				"m/0002_first.sql": {Data: []byte("SELECT 2;")},
+			},
+			expectVersions: []int{2, 10},
+		},
+		{
+			name:      "invalid file name",
+			fsys:      fstest.MapFS{"m/first.sql": {Data: []byte("SELECT 1;")}},
+			expectErr: ErrInvalidMigration,
+		},
+		{
+			name: "duplicated version",
+			fsys: fstest.MapFS{
+				"m/0001_a.sql": {Data: []byte("SELECT 1;")},
+				"m/001_b.sql":  {Data: []byte("SELECT 1;")},
+			},
+			expectErr: ErrInvalidMigration,
+		},
+		{
+			name:      "missing dir",
+			fsys:      fstest.MapFS{},
+			expectErr: errors.New("reading migrations"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Load(tt.fsys, "m")
+			if tt.expectErr != nil {
+				if errors.Is(tt.expectErr, ErrInvalidMigration) {
+					assert.ErrorIs(t, err, tt.expectErr)
+				} else {
+					assert.ErrorContains(t, err, tt.expectErr.Error())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			versions := []int{}
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+				assert.Len(t, m.Checksum, 64)
+			}
+			assert.Equal(t, tt.expectVersions, versions)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	m, err := New(nil)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m.Migrations())
+	for i, migration := range m.Migrations() {
+		assert.Equal(t, i+1, migration.Version, "migration versions must be consecutive")
+	}
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "baseline", SQL: "CREATE TABLE a (id int);", Checksum: "c1"},
+	{Version: 2, Name: "seed", SQL: "INSERT INTO a VALUES (1);", Checksum: "c2"},
+}
+
+func newMockMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &Migrator{db: db, migrations: testMigrations}, mock
+}
+
+func appliedRows(versions ...int) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"})
+	for _, v := range versions {
+		m := testMigrations[v-1]
+		rows.AddRow(m.Version, m.Name, m.Checksum, time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC))
+	}
+	return rows
+}
+
+func TestUp(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	t.Run("applies the pending migrations", func(t *testing.T) {
+		m, mock := newMockMigrator(t)
+		mock.ExpectExec(createTableQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(lockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(appliedQuery).WillReturnRows(appliedRows(1))
+		mock.ExpectBegin()
+		mock.ExpectExec(testMigrations[1].SQL).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insertQuery).WithArgs(2, "seed", "c2").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectExec(unlockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		done, err := m.Up(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []Migration{testMigrations[1]}, done)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failing migration is rolled back", func(t *testing.T) {
+		m, mock := newMockMigrator(t)
+		mock.ExpectExec(createTableQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(lockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(appliedQuery).WillReturnRows(appliedRows())
+		mock.ExpectBegin()
+		mock.ExpectExec(testMigrations[0].SQL).WillReturnError(errors.New("syntax error"))
+		mock.ExpectRollback()
+		mock.ExpectExec(unlockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		done, err := m.Up(context.Background())
+		assert.EqualError(t, err, "migration 1_baseline: syntax error")
+		assert.Empty(t, done)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("modified migration", func(t *testing.T) {
+		m, mock := newMockMigrator(t)
+		mock.ExpectExec(createTableQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(lockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(appliedQuery).WillReturnRows(sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"}).
+			AddRow(1, "baseline", "changed", time.Now()))
+		mock.ExpectExec(unlockQuery).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := m.Up(context.Background())
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("lock error", func(t *testing.T) {
+		m, mock := newMockMigrator(t)
+		mock.ExpectExec(createTableQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(lockQuery).WithArgs(lockKey).WillReturnError(errors.New("timeout"))
+
+		_, err := m.Up(context.Background())
+		assert.EqualError(t, err, "locking migrations: timeout")
+	})
+}
+
+func TestStatusAndVerify(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      *sqlmock.Rows
+		queryErr  error
+		expectErr error
+	}{
+		{name: "all applied", rows: appliedRows(1, 2)},
+		{name: "pending", rows: appliedRows(1), expectErr: ErrPendingMigrations},
+		{name: "unknown migration", rows: appliedRows(1, 2).AddRow(3, "newer", "c3", time.Now()), expectErr: ErrUnknownMigration},
+		{name: "query error", queryErr: errors.New("db error"), expectErr: errors.New("reading applied migrations: db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mock := newMockMigrator(t)
+			mock.ExpectExec(createTableQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+			query := mock.ExpectQuery(appliedQuery)
+			if tt.queryErr != nil {
+				query.WillReturnError(tt.queryErr)
+			} else {
+				query.WillReturnRows(tt.rows)
+			}
+
+			err := m.Verify(context.Background())
+
+			switch {
+			case tt.expectErr == nil:
+				assert.NoError(t, err)
+			case tt.queryErr != nil:
+				assert.EqualError(t, err, tt.expectErr.Error())
+			default:
+				assert.ErrorIs(t, err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	changes, err := CheckDrift(context.Background(), func(ctx context.Context, opts ...schema.MigrateOption) error {
+		assert.Len(t, opts, 1)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = CheckDrift(context.Background(), func(ctx context.Context, opts ...schema.MigrateOption) error {
+		return errors.New("inspect error")
+	})
+	assert.EqualError(t, err, "planning ent schema: inspect error")
+}
+
+func TestRecordChanges(t *testing.T) {
+	var changes []string
+	plan := &migrate.Plan{Changes: []*migrate.Change{{Cmd: `ALTER TABLE "guides" ADD COLUMN "x" bigint`}}}
+	applier := recordChanges(&changes)(schema.ApplyFunc(func(context.Context, dialect.ExecQuerier, *migrate.Plan) error {
+		t.Fatal("the planned changes must not be applied")
+		return nil
+	}))
+
+	assert.NoError(t, applier.Apply(context.Background(), nil, plan))
+	assert.Equal(t, []string{`ALTER TABLE "guides" ADD COLUMN "x" bigint`}, changes)
+}
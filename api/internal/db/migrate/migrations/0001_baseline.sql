@@ -0,0 +1,72 @@
+-- Schema previously created by db/script/init.sql on the first Postgres boot.
+-- Every statement is idempotent so databases created by that script adopt the migrations.
+CREATE TABLE IF NOT EXISTS operators (
+    id SERIAL PRIMARY KEY,
+    account VARCHAR(200) UNIQUE NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    enabled boolean NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE operators ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'operator';
+
+CREATE TABLE IF NOT EXISTS guides (
+    id SERIAL PRIMARY KEY,
+    via_guide_id CHAR(12) NOT NULL,
+    recipient VARCHAR(100) NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    payment CHAR(1) NOT NULL DEFAULT 'P',
+    operator_id INTEGER NOT NULL REFERENCES operators(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE guides ADD COLUMN IF NOT EXISTS status_reason VARCHAR(100);
+ALTER TABLE guides ADD COLUMN IF NOT EXISTS via_snapshot JSONB;
+ALTER TABLE guides ADD COLUMN IF NOT EXISTS via_synced_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS guide_histories (
+    id SERIAL PRIMARY KEY,
+    guide_id INTEGER NOT NULL REFERENCES guides(id),
+    status VARCHAR(30) NOT NULL,
+    operator_id INTEGER NOT NULL REFERENCES operators(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE guide_histories ADD COLUMN IF NOT EXISTS reason VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS guide_status_updated_at ON guides (status, updated_at);
+
+CREATE TABLE IF NOT EXISTS guides_archive (
+    id INTEGER PRIMARY KEY,
+    via_guide_id CHAR(12) NOT NULL,
+    recipient VARCHAR(100) NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    status_reason VARCHAR(100),
+    payment CHAR(1) NOT NULL,
+    operator_id INTEGER NOT NULL REFERENCES operators(id),
+    via_snapshot JSONB,
+    via_synced_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS guides_archive_via_guide_id ON guides_archive (via_guide_id);
+
+CREATE TABLE IF NOT EXISTS guide_histories_archive (
+    id INTEGER PRIMARY KEY,
+    guide_id INTEGER NOT NULL REFERENCES guides_archive(id),
+    status VARCHAR(30) NOT NULL,
+    reason VARCHAR(100),
+    operator_id INTEGER NOT NULL REFERENCES operators(id),
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+-- SYSTEM operator, owner of the guides nobody is attending
+INSERT INTO operators (id, account, name, enabled, role)
+SELECT 1, 'SYSTEM', 'SYSTEM', true, 'admin'
+WHERE NOT EXISTS (SELECT 1 FROM operators WHERE id = 1);
+
+SELECT setval(pg_get_serial_sequence('operators', 'id'), GREATEST(MAX(id), 1)) FROM operators;
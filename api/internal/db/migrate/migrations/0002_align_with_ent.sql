@@ -0,0 +1,45 @@
+-- Align types, defaults and constraint names with the ent schema, which the drift check compares against.
+-- The history trigger is recreated by the next migration, Postgres does not alter columns a trigger lists.
+DROP TRIGGER IF EXISTS trg_insert_guide_histories ON guides;
+
+ALTER TABLE operators ALTER COLUMN id TYPE bigint;
+
+ALTER TABLE guides
+    ALTER COLUMN id TYPE bigint,
+    ALTER COLUMN operator_id TYPE bigint,
+    ALTER COLUMN via_guide_id TYPE VARCHAR(12),
+    ALTER COLUMN payment TYPE VARCHAR(1),
+    ALTER COLUMN payment DROP DEFAULT;
+
+ALTER TABLE guide_histories
+    ALTER COLUMN id TYPE bigint,
+    ALTER COLUMN guide_id TYPE bigint,
+    ALTER COLUMN operator_id TYPE bigint;
+
+ALTER TABLE guides_archive
+    ALTER COLUMN id TYPE bigint,
+    ALTER COLUMN operator_id TYPE bigint,
+    ALTER COLUMN via_guide_id TYPE VARCHAR(12),
+    ALTER COLUMN payment TYPE VARCHAR(1);
+
+ALTER TABLE guide_histories_archive
+    ALTER COLUMN id TYPE bigint,
+    ALTER COLUMN guide_id TYPE bigint,
+    ALTER COLUMN operator_id TYPE bigint;
+
+-- ent ids are identity columns instead of serial ones
+DO $$
+DECLARE
+  t text;
+BEGIN
+  FOREACH t IN ARRAY ARRAY['operators', 'guides', 'guide_histories'] LOOP
+    EXECUTE format('ALTER TABLE %I ALTER COLUMN id DROP DEFAULT', t);
+    EXECUTE format('DROP SEQUENCE IF EXISTS %s', pg_get_serial_sequence(t, 'id'));
+    EXECUTE format('ALTER TABLE %I ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY', t);
+    EXECUTE format('SELECT setval(pg_get_serial_sequence(%L, ''id''), GREATEST((SELECT MAX(id) FROM %I), 1))', t, t);
+  END LOOP;
+END $$;
+
+ALTER TABLE guides RENAME CONSTRAINT guides_operator_id_fkey TO guides_operators_guides;
+ALTER TABLE guide_histories RENAME CONSTRAINT guide_histories_guide_id_fkey TO guide_histories_guides_history;
+ALTER TABLE guide_histories RENAME CONSTRAINT guide_histories_operator_id_fkey TO guide_histories_operators_guide_history;
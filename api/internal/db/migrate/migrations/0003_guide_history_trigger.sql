@@ -0,0 +1,16 @@
+-- Every status or operator change of a guide is recorded in its history
+CREATE OR REPLACE FUNCTION insert_guide_histories()
+RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO guide_histories (guide_id, status, reason, operator_id)
+  VALUES (NEW.id, NEW.status, NEW.status_reason, NEW.operator_id);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Only status and operator changes are history, a carrier snapshot refresh is not
+DROP TRIGGER IF EXISTS trg_insert_guide_histories ON guides;
+CREATE TRIGGER trg_insert_guide_histories
+AFTER INSERT OR UPDATE OF status, operator_id ON guides
+FOR EACH ROW
+EXECUTE FUNCTION insert_guide_histories();
@@ -0,0 +1,14 @@
+-- Operator search over every guide: accent-insensitive recipient substrings, guide id prefixes and date ranges.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE guides ADD COLUMN recipient_normalized VARCHAR(100);
+
+-- Same folding as biz_guide_search.NormalizeName
+UPDATE guides SET recipient_normalized = btrim(regexp_replace(
+    translate(lower(recipient), 'áéíóúàèìòùäëïöüñç', 'aeiouaeiouaeiounc'), '\s+', ' ', 'g'));
+
+ALTER TABLE guides ALTER COLUMN recipient_normalized SET NOT NULL;
+
+CREATE INDEX guide_recipient_normalized ON guides USING GIN (recipient_normalized gin_trgm_ops);
+CREATE INDEX guide_via_guide_id ON guides (via_guide_id varchar_pattern_ops);
+CREATE INDEX guide_created_at ON guides (created_at);
@@ -0,0 +1,2 @@
+-- Only available operators are given new guides by the assignment engine
+ALTER TABLE operators ADD COLUMN availability VARCHAR(20) NOT NULL DEFAULT 'offline';
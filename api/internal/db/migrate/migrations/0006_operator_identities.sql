@@ -0,0 +1,16 @@
+-- Operators are matched by the (issuer, subject) of their IdP accounts, several IdPs may log in the same operator
+CREATE TABLE operator_identities (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    issuer VARCHAR(500) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    operator_id bigint NOT NULL,
+    CONSTRAINT operator_identities_operators_identities FOREIGN KEY (operator_id) REFERENCES operators (id)
+);
+
+CREATE UNIQUE INDEX operatoridentity_issuer_subject ON operator_identities (issuer, subject);
+
+-- Emergency local login while the IdPs are down, both unset unless an admin enables it for the operator
+ALTER TABLE operators
+    ADD COLUMN password_hash VARCHAR(100),
+    ADD COLUMN totp_secret VARCHAR(100);
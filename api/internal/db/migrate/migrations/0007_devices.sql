@@ -0,0 +1,15 @@
+-- Kiosks and monitors paired by an admin, they call the public endpoints with the token they got when paired
+CREATE TABLE devices (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    branch VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64),
+    pairing_code_hash VARCHAR(64),
+    pairing_expires_at TIMESTAMPTZ,
+    paired_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX device_pairing_code_hash ON devices (pairing_code_hash);
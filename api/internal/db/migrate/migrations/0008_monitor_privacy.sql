@@ -0,0 +1,2 @@
+-- How a monitor identifies the guides on its public screen, empty uses the branch default
+ALTER TABLE devices ADD COLUMN privacy VARCHAR(20);
@@ -0,0 +1,41 @@
+-- Operator, admin and device actions. The entries are append only, each one holds the hash of the
+-- previous one so a changed or removed entry breaks the chain
+CREATE TABLE audit_entries (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    actor VARCHAR(100) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(50),
+    target_id VARCHAR(100),
+    request_id VARCHAR(100),
+    ip VARCHAR(64),
+    before TEXT,
+    after TEXT,
+    prev_hash VARCHAR(64),
+    hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX auditentry_actor ON audit_entries (actor);
+CREATE INDEX auditentry_action ON audit_entries (action);
+CREATE INDEX auditentry_target_type_target_id ON audit_entries (target_type, target_id);
+CREATE INDEX auditentry_created_at ON audit_entries (created_at);
+CREATE UNIQUE INDEX auditentry_hash ON audit_entries (hash);
+
+CREATE OR REPLACE FUNCTION reject_audit_entries_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit entries are append only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_reject_audit_entries_change ON audit_entries;
+CREATE TRIGGER trg_reject_audit_entries_change
+BEFORE UPDATE OR DELETE ON audit_entries
+FOR EACH ROW
+EXECUTE FUNCTION reject_audit_entries_change();
+
+DROP TRIGGER IF EXISTS trg_reject_audit_entries_truncate ON audit_entries;
+CREATE TRIGGER trg_reject_audit_entries_truncate
+BEFORE TRUNCATE ON audit_entries
+FOR EACH STATEMENT
+EXECUTE FUNCTION reject_audit_entries_change();
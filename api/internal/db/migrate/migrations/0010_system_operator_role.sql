@@ -0,0 +1,2 @@
+-- The SYSTEM operator only owns the guides nobody is attending, it holds no admin rights
+UPDATE operators SET role = 'operator' WHERE id = 1 AND account = 'SYSTEM';
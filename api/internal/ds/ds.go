@@ -19,6 +19,21 @@ type DS interface {
 	Get(ctx context.Context, key string) (bool, string, error)
 	Del(ctx context.Context, key string) error
 	Incr(ctx context.Context, key string) (int64, error)
+	// IncrExpire increments the key and sets its expiration on the first increment, in one atomic step
+	IncrExpire(ctx context.Context, key string, ttlSeconds int) (int64, error)
+	// Expire sets the expiration of the key, keeping its value
+	Expire(ctx context.Context, key string, ttlSeconds int) error
+	// SetNX sets the key only if it does not exist, reporting whether it was set
+	SetNX(ctx context.Context, key string, value string, ttlSeconds int) (bool, error)
+	// Renew sets the expiration of the key only while it holds value, reporting whether it did;
+	// a ttl of 0 deletes it. It keeps a lock taken with SetNX from being renewed by another owner.
+	Renew(ctx context.Context, key string, value string, ttlSeconds int) (bool, error)
+	// SAdd adds the member to the set and sets the set expiration, in one atomic step
+	SAdd(ctx context.Context, key string, member string, ttlSeconds int) error
+	// SRem removes the member from the set, the set is deleted with its last member
+	SRem(ctx context.Context, key string, member string) error
+	// SMembers returns the members of the set, none when it does not exist
+	SMembers(ctx context.Context, key string) ([]string, error)
 }
 
 var (
@@ -29,3 +29,39 @@ func (m *MockDS) Incr(ctx context.Context, key string) (int64, error) {
 	args := m.Called(ctx, key)
 	return int64(args.Int(0)), args.Error(1)
 }
+
+func (m *MockDS) IncrExpire(ctx context.Context, key string, ttlSeconds int) (int64, error) {
+	args := m.Called(ctx, key, ttlSeconds)
+	return int64(args.Int(0)), args.Error(1)
+}
+
+func (m *MockDS) Expire(ctx context.Context, key string, ttlSeconds int) error {
+	args := m.Called(ctx, key, ttlSeconds)
+	return args.Error(0)
+}
+
+func (m *MockDS) SetNX(ctx context.Context, key, value string, ttlSeconds int) (bool, error) {
+	args := m.Called(ctx, key, value, ttlSeconds)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDS) Renew(ctx context.Context, key, value string, ttlSeconds int) (bool, error) {
+	args := m.Called(ctx, key, value, ttlSeconds)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDS) SAdd(ctx context.Context, key, member string, ttlSeconds int) error {
+	args := m.Called(ctx, key, member, ttlSeconds)
+	return args.Error(0)
+}
+
+func (m *MockDS) SRem(ctx context.Context, key, member string) error {
+	args := m.Called(ctx, key, member)
+	return args.Error(0)
+}
+
+func (m *MockDS) SMembers(ctx context.Context, key string) ([]string, error) {
+	args := m.Called(ctx, key)
+	members, _ := args.Get(0).([]string)
+	return members, args.Error(1)
+}
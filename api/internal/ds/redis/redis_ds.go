@@ -10,6 +10,30 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// incrExpire sets the expiration only when the key is created by the increment, so the window
+// is not extended by the following hits
+var incrExpire = redis.NewScript(`
+local counter = redis.call("INCR", KEYS[1])
+if counter == 1 then
+	redis.call("EXPIRE", KEYS[1], ARGV[1])
+end
+return counter
+`)
+
+// renew expires the key only while it holds the value, EXPIRE deletes it on a ttl of 0
+var renew = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("EXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// sAddExpire refreshes the expiration of the set with every member added
+var sAddExpire = redis.NewScript(`
+redis.call("SADD", KEYS[1], ARGV[1])
+return redis.call("EXPIRE", KEYS[1], ARGV[2])
+`)
+
 type RedisDS struct {
 	client *redis.Client
 }
@@ -46,3 +70,32 @@ func (r *RedisDS) Del(ctx context.Context, key string) error {
 func (r *RedisDS) Incr(ctx context.Context, key string) (int64, error) {
 	return r.client.Incr(ctx, key).Result()
 }
+
+func (r *RedisDS) IncrExpire(ctx context.Context, key string, ttlSeconds int) (int64, error) {
+	return incrExpire.Run(ctx, r.client, []string{key}, ttlSeconds).Int64()
+}
+
+func (r *RedisDS) Expire(ctx context.Context, key string, ttlSeconds int) error {
+	return r.client.Expire(ctx, key, time.Duration(ttlSeconds)*time.Second).Err()
+}
+
+func (r *RedisDS) SetNX(ctx context.Context, key string, value string, ttlSeconds int) (bool, error) {
+	return r.client.SetNX(ctx, key, value, time.Duration(ttlSeconds)*time.Second).Result()
+}
+
+func (r *RedisDS) Renew(ctx context.Context, key string, value string, ttlSeconds int) (bool, error) {
+	renewed, err := renew.Run(ctx, r.client, []string{key}, value, ttlSeconds).Int64()
+	return renewed == 1, err
+}
+
+func (r *RedisDS) SAdd(ctx context.Context, key string, member string, ttlSeconds int) error {
+	return sAddExpire.Run(ctx, r.client, []string{key}, member, ttlSeconds).Err()
+}
+
+func (r *RedisDS) SRem(ctx context.Context, key string, member string) error {
+	return r.client.SRem(ctx, key, member).Err()
+}
+
+func (r *RedisDS) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, key).Result()
+}
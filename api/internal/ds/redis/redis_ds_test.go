@@ -74,5 +74,72 @@ func TestRedisDS(t *testing.T) {
 		assert.Equal(t, int64(42), val)
 	})
 
+	t.Run("IncrExpire success", func(t *testing.T) {
+		mock.ExpectEvalSha(incrExpire.Hash(), []string{"key"}, 60).SetVal(int64(1))
+		val, err := r.IncrExpire(ctx, "key", 60)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), val)
+	})
+
+	t.Run("Expire success", func(t *testing.T) {
+		mock.ExpectExpire("key", 60*time.Second).SetVal(true)
+		err := r.Expire(ctx, "key", 60)
+		assert.NoError(t, err)
+	})
+
+	t.Run("SetNX acquired", func(t *testing.T) {
+		mock.ExpectSetNX("lock", "owner", 5*time.Second).SetVal(true)
+		ok, err := r.SetNX(ctx, "lock", "owner", 5)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("SetNX already set", func(t *testing.T) {
+		mock.ExpectSetNX("lock", "owner", 5*time.Second).SetVal(false)
+		ok, err := r.SetNX(ctx, "lock", "owner", 5)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Renew held", func(t *testing.T) {
+		mock.ExpectEvalSha(renew.Hash(), []string{"lock"}, "owner", 5).SetVal(int64(1))
+		ok, err := r.Renew(ctx, "lock", "owner", 5)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Renew held by another owner", func(t *testing.T) {
+		mock.ExpectEvalSha(renew.Hash(), []string{"lock"}, "owner", 5).SetVal(int64(0))
+		ok, err := r.Renew(ctx, "lock", "owner", 5)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("SAdd success", func(t *testing.T) {
+		mock.ExpectEvalSha(sAddExpire.Hash(), []string{"set"}, "member", 45).SetVal(int64(1))
+		err := r.SAdd(ctx, "set", "member", 45)
+		assert.NoError(t, err)
+	})
+
+	t.Run("SRem success", func(t *testing.T) {
+		mock.ExpectSRem("set", "member").SetVal(1)
+		err := r.SRem(ctx, "set", "member")
+		assert.NoError(t, err)
+	})
+
+	t.Run("SMembers success", func(t *testing.T) {
+		mock.ExpectSMembers("set").SetVal([]string{"a", "b"})
+		members, err := r.SMembers(ctx, "set")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, members)
+	})
+
+	t.Run("SMembers missing set", func(t *testing.T) {
+		mock.ExpectSMembers("set").SetVal([]string{})
+		members, err := r.SMembers(ctx, "set")
+		assert.NoError(t, err)
+		assert.Empty(t, members)
+	})
+
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,404 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"via/internal/ent/auditentry"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// AuditEntryCreate is the builder for creating a AuditEntry entity.
+type AuditEntryCreate struct {
+	config
+	mutation *AuditEntryMutation
+	hooks    []Hook
+}
+
+// SetActor sets the "actor" field.
+func (aec *AuditEntryCreate) SetActor(s string) *AuditEntryCreate {
+	aec.mutation.SetActor(s)
+	return aec
+}
+
+// SetAction sets the "action" field.
+func (aec *AuditEntryCreate) SetAction(s string) *AuditEntryCreate {
+	aec.mutation.SetAction(s)
+	return aec
+}
+
+// SetTargetType sets the "target_type" field.
+func (aec *AuditEntryCreate) SetTargetType(s string) *AuditEntryCreate {
+	aec.mutation.SetTargetType(s)
+	return aec
+}
+
+// SetNillableTargetType sets the "target_type" field if the given value is not nil.
+func (aec *AuditEntryCreate) SetNillableTargetType(s *string) *AuditEntryCreate {
+	if s != nil {
+		aec.SetTargetType(*s)
+	}
+	return aec
+}
+
+// SetTargetID sets the "target_id" field.
+func (aec *AuditEntryCreate) SetTargetID(s string) *AuditEntryCreate {
+	aec.mutation.SetTargetID(s)
+	return aec
+}
+
+// SetNillableTargetID sets the "target_id" field if the given value is not nil.
+func (aec *AuditEntryCreate) SetNillableTargetID(s *string) *AuditEntryCreate {
+	if s != nil {
+		aec.SetTargetID(*s)
+	}
+	return aec
+}
+
+// SetRequestID sets the "request_id" field.
+func (aec *AuditEntryCreate) SetRequestID(s string) *AuditEntryCreate {
+	aec.mutation.SetRequestID(s)
+	return aec
+}
+
+// SetNillableRequestID sets the "request_id" field if the given value is not nil.
+func (aec *AuditEntryCreate) SetNillableRequestID(s *string) *AuditEntryCreate {
+	if s != nil {
+		aec.SetRequestID(*s)
+	}
+	return aec
+}
+
+// SetIP sets the "ip" field.
+func (aec *AuditEntryCreate) SetIP(s string) *AuditEntryCreate {
+	aec.mutation.SetIP(s)
+	return aec
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (aec *AuditEntryCreate) SetNillableIP(s *string) *AuditEntryCreate {
+	if s != nil {
+		aec.SetIP(*s)
+	}
+	return aec
+}
+
+// SetBefore sets the "before" field.
+func (aec *AuditEntryCreate) SetBefore(s string) *AuditEntryCreate {
+	aec.mutation.SetBefore(s)
+	return aec
+}
+
+// SetNillableBefore sets the "before" field if the given value is not nil.
+func (aec *AuditEntryCreate) SetNillableBefore(s *string) *AuditEntryCreate {
+	if s != nil {
+		aec.SetBefore(*s)
+	}
+	return aec
+}
+
+// SetAfter sets the "after" field.
+func (aec *AuditEntryCreate) SetAfter(s string) *AuditEntryCreate {
+	aec.mutation.SetAfter(s)
+	return aec
+}
+
+// SetNillableAfter sets the "after" field if the given value is not nil.
+func (aec *AuditEntryCreate) SetNillableAfter(s *string) *AuditEntryCreate {
+	if s != nil {
+		aec.SetAfter(*s)
+	}
+	return aec
+}
+
+// SetPrevHash sets the "prev_hash" field.
+func (aec *AuditEntryCreate) SetPrevHash(s string) *AuditEntryCreate {
+	aec.mutation.SetPrevHash(s)
+	return aec
+}
+
+// SetNillablePrevHash sets the "prev_hash" field if the given value is not nil.
+func (aec *AuditEntryCreate) SetNillablePrevHash(s *string) *AuditEntryCreate {
+	if s != nil {
+		aec.SetPrevHash(*s)
+	}
+	return aec
+}
+
+// SetHash sets the "hash" field.
+func (aec *AuditEntryCreate) SetHash(s string) *AuditEntryCreate {
+	aec.mutation.SetHash(s)
+	return aec
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (aec *AuditEntryCreate) SetCreatedAt(t time.Time) *AuditEntryCreate {
+	aec.mutation.SetCreatedAt(t)
+	return aec
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (aec *AuditEntryCreate) SetNillableCreatedAt(t *time.Time) *AuditEntryCreate {
+	if t != nil {
+		aec.SetCreatedAt(*t)
+	}
+	return aec
+}
+
+// Mutation returns the AuditEntryMutation object of the builder.
+func (aec *AuditEntryCreate) Mutation() *AuditEntryMutation {
+	return aec.mutation
+}
+
+// Save creates the AuditEntry in the database.
+func (aec *AuditEntryCreate) Save(ctx context.Context) (*AuditEntry, error) {
+	aec.defaults()
+	return withHooks(ctx, aec.sqlSave, aec.mutation, aec.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (aec *AuditEntryCreate) SaveX(ctx context.Context) *AuditEntry {
+	v, err := aec.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (aec *AuditEntryCreate) Exec(ctx context.Context) error {
+	_, err := aec.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (aec *AuditEntryCreate) ExecX(ctx context.Context) {
+	if err := aec.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (aec *AuditEntryCreate) defaults() {
+	if _, ok := aec.mutation.CreatedAt(); !ok {
+		v := auditentry.DefaultCreatedAt()
+		aec.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (aec *AuditEntryCreate) check() error {
+	if _, ok := aec.mutation.Actor(); !ok {
+		return &ValidationError{Name: "actor", err: errors.New(`ent: missing required field "AuditEntry.actor"`)}
+	}
+	if v, ok := aec.mutation.Actor(); ok {
+		if err := auditentry.ActorValidator(v); err != nil {
+			return &ValidationError{Name: "actor", err: fmt.Errorf(`ent: validator failed for field "AuditEntry.actor": %w`, err)}
+		}
+	}
+	if _, ok := aec.mutation.Action(); !ok {
+		return &ValidationError{Name: "action", err: errors.New(`ent: missing required field "AuditEntry.action"`)}
+	}
+	if v, ok := aec.mutation.Action(); ok {
+		if err := auditentry.ActionValidator(v); err != nil {
+			return &ValidationError{Name: "action", err: fmt.Errorf(`ent: validator failed for field "AuditEntry.action": %w`, err)}
+		}
+	}
+	if v, ok := aec.mutation.TargetType(); ok {
+		if err := auditentry.TargetTypeValidator(v); err != nil {
+			return &ValidationError{Name: "target_type", err: fmt.Errorf(`ent: validator failed for field "AuditEntry.target_type": %w`, err)}
+		}
+	}
+	if v, ok := aec.mutation.TargetID(); ok {
+		if err := auditentry.TargetIDValidator(v); err != nil {
+			return &ValidationError{Name: "target_id", err: fmt.Errorf(`ent: validator failed for field "AuditEntry.target_id": %w`, err)}
+		}
+	}
+	if v, ok := aec.mutation.RequestID(); ok {
+		if err := auditentry.RequestIDValidator(v); err != nil {
+			return &ValidationError{Name: "request_id", err: fmt.Errorf(`ent: validator failed for field "AuditEntry.request_id": %w`, err)}
+		}
+	}
+	if v, ok := aec.mutation.IP(); ok {
+		if err := auditentry.IPValidator(v); err != nil {
+			return &ValidationError{Name: "ip", err: fmt.Errorf(`ent: validator failed for field "AuditEntry.ip": %w`, err)}
+		}
+	}
+	if v, ok := aec.mutation.PrevHash(); ok {
+		if err := auditentry.PrevHashValidator(v); err != nil {
+			return &ValidationError{Name: "prev_hash", err: fmt.Errorf(`ent: validator failed for field "AuditEntry.prev_hash": %w`, err)}
+		}
+	}
+	if _, ok := aec.mutation.Hash(); !ok {
+		return &ValidationError{Name: "hash", err: errors.New(`ent: missing required field "AuditEntry.hash"`)}
+	}
+	if v, ok := aec.mutation.Hash(); ok {
+		if err := auditentry.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "AuditEntry.hash": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (aec *AuditEntryCreate) sqlSave(ctx context.Context) (*AuditEntry, error) {
+	if err := aec.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := aec.createSpec()
+	if err := sqlgraph.CreateNode(ctx, aec.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	aec.mutation.id = &_node.ID
+	aec.mutation.done = true
+	return _node, nil
+}
+
+func (aec *AuditEntryCreate) createSpec() (*AuditEntry, *sqlgraph.CreateSpec) {
+	var (
+		_node = &AuditEntry{config: aec.config}
+		_spec = sqlgraph.NewCreateSpec(auditentry.Table, sqlgraph.NewFieldSpec(auditentry.FieldID, field.TypeInt))
+	)
+	if value, ok := aec.mutation.Actor(); ok {
+		_spec.SetField(auditentry.FieldActor, field.TypeString, value)
+		_node.Actor = value
+	}
+	if value, ok := aec.mutation.Action(); ok {
+		_spec.SetField(auditentry.FieldAction, field.TypeString, value)
+		_node.Action = value
+	}
+	if value, ok := aec.mutation.TargetType(); ok {
+		_spec.SetField(auditentry.FieldTargetType, field.TypeString, value)
+		_node.TargetType = value
+	}
+	if value, ok := aec.mutation.TargetID(); ok {
+		_spec.SetField(auditentry.FieldTargetID, field.TypeString, value)
+		_node.TargetID = value
+	}
+	if value, ok := aec.mutation.RequestID(); ok {
+		_spec.SetField(auditentry.FieldRequestID, field.TypeString, value)
+		_node.RequestID = value
+	}
+	if value, ok := aec.mutation.IP(); ok {
+		_spec.SetField(auditentry.FieldIP, field.TypeString, value)
+		_node.IP = value
+	}
+	if value, ok := aec.mutation.Before(); ok {
+		_spec.SetField(auditentry.FieldBefore, field.TypeString, value)
+		_node.Before = value
+	}
+	if value, ok := aec.mutation.After(); ok {
+		_spec.SetField(auditentry.FieldAfter, field.TypeString, value)
+		_node.After = value
+	}
+	if value, ok := aec.mutation.PrevHash(); ok {
+		_spec.SetField(auditentry.FieldPrevHash, field.TypeString, value)
+		_node.PrevHash = value
+	}
+	if value, ok := aec.mutation.Hash(); ok {
+		_spec.SetField(auditentry.FieldHash, field.TypeString, value)
+		_node.Hash = value
+	}
+	if value, ok := aec.mutation.CreatedAt(); ok {
+		_spec.SetField(auditentry.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// AuditEntryCreateBulk is the builder for creating many AuditEntry entities in bulk.
+type AuditEntryCreateBulk struct {
+	config
+	err      error
+	builders []*AuditEntryCreate
+}
+
+// Save creates the AuditEntry entities in the database.
+func (aecb *AuditEntryCreateBulk) Save(ctx context.Context) ([]*AuditEntry, error) {
+	if aecb.err != nil {
+		return nil, aecb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(aecb.builders))
+	nodes := make([]*AuditEntry, len(aecb.builders))
+	mutators := make([]Mutator, len(aecb.builders))
+	for i := range aecb.builders {
+		func(i int, root context.Context) {
+			builder := aecb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*AuditEntryMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, aecb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, aecb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, aecb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (aecb *AuditEntryCreateBulk) SaveX(ctx context.Context) []*AuditEntry {
+	v, err := aecb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (aecb *AuditEntryCreateBulk) Exec(ctx context.Context) error {
+	_, err := aecb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (aecb *AuditEntryCreateBulk) ExecX(ctx context.Context) {
+	if err := aecb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
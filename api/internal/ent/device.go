@@ -0,0 +1,212 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"via/internal/ent/device"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// Device is the model entity for the Device schema.
+type Device struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Name holds the value of the "name" field.
+	Name string `json:"name,omitempty"`
+	// Kind holds the value of the "kind" field.
+	Kind string `json:"kind,omitempty"`
+	// Branch holds the value of the "branch" field.
+	Branch string `json:"branch,omitempty"`
+	// TokenHash holds the value of the "token_hash" field.
+	TokenHash string `json:"-"`
+	// PairingCodeHash holds the value of the "pairing_code_hash" field.
+	PairingCodeHash string `json:"-"`
+	// PairingExpiresAt holds the value of the "pairing_expires_at" field.
+	PairingExpiresAt *time.Time `json:"pairing_expires_at,omitempty"`
+	// PairedAt holds the value of the "paired_at" field.
+	PairedAt *time.Time `json:"paired_at,omitempty"`
+	// Privacy holds the value of the "privacy" field.
+	Privacy string `json:"privacy,omitempty"`
+	// RevokedAt holds the value of the "revoked_at" field.
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*Device) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case device.FieldID:
+			values[i] = new(sql.NullInt64)
+		case device.FieldName, device.FieldKind, device.FieldBranch, device.FieldTokenHash, device.FieldPairingCodeHash, device.FieldPrivacy:
+			values[i] = new(sql.NullString)
+		case device.FieldPairingExpiresAt, device.FieldPairedAt, device.FieldRevokedAt, device.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the Device fields.
+func (d *Device) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case device.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			d.ID = int(value.Int64)
+		case device.FieldName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field name", values[i])
+			} else if value.Valid {
+				d.Name = value.String
+			}
+		case device.FieldKind:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field kind", values[i])
+			} else if value.Valid {
+				d.Kind = value.String
+			}
+		case device.FieldBranch:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field branch", values[i])
+			} else if value.Valid {
+				d.Branch = value.String
+			}
+		case device.FieldTokenHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field token_hash", values[i])
+			} else if value.Valid {
+				d.TokenHash = value.String
+			}
+		case device.FieldPairingCodeHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field pairing_code_hash", values[i])
+			} else if value.Valid {
+				d.PairingCodeHash = value.String
+			}
+		case device.FieldPairingExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field pairing_expires_at", values[i])
+			} else if value.Valid {
+				d.PairingExpiresAt = new(time.Time)
+				*d.PairingExpiresAt = value.Time
+			}
+		case device.FieldPairedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field paired_at", values[i])
+			} else if value.Valid {
+				d.PairedAt = new(time.Time)
+				*d.PairedAt = value.Time
+			}
+		case device.FieldPrivacy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field privacy", values[i])
+			} else if value.Valid {
+				d.Privacy = value.String
+			}
+		case device.FieldRevokedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field revoked_at", values[i])
+			} else if value.Valid {
+				d.RevokedAt = new(time.Time)
+				*d.RevokedAt = value.Time
+			}
+		case device.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				d.CreatedAt = value.Time
+			}
+		default:
+			d.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the Device.
+// This includes values selected through modifiers, order, etc.
+func (d *Device) Value(name string) (ent.Value, error) {
+	return d.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this Device.
+// Note that you need to call Device.Unwrap() before calling this method if this Device
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (d *Device) Update() *DeviceUpdateOne {
+	return NewDeviceClient(d.config).UpdateOne(d)
+}
+
+// Unwrap unwraps the Device entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (d *Device) Unwrap() *Device {
+	_tx, ok := d.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: Device is not a transactional entity")
+	}
+	d.config.driver = _tx.drv
+	return d
+}
+
+// String implements the fmt.Stringer.
+func (d *Device) String() string {
+	var builder strings.Builder
+	builder.WriteString("Device(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", d.ID))
+	builder.WriteString("name=")
+	builder.WriteString(d.Name)
+	builder.WriteString(", ")
+	builder.WriteString("kind=")
+	builder.WriteString(d.Kind)
+	builder.WriteString(", ")
+	builder.WriteString("branch=")
+	builder.WriteString(d.Branch)
+	builder.WriteString(", ")
+	builder.WriteString("token_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("pairing_code_hash=<sensitive>")
+	builder.WriteString(", ")
+	if v := d.PairingExpiresAt; v != nil {
+		builder.WriteString("pairing_expires_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := d.PairedAt; v != nil {
+		builder.WriteString("paired_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("privacy=")
+	builder.WriteString(d.Privacy)
+	builder.WriteString(", ")
+	if v := d.RevokedAt; v != nil {
+		builder.WriteString("revoked_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(d.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// Devices is a parsable slice of Device.
+type Devices []*Device
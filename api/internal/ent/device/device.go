@@ -0,0 +1,138 @@
+// Code generated by ent, DO NOT EDIT.
+
+package device
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the device type in the database.
+	Label = "device"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldName holds the string denoting the name field in the database.
+	FieldName = "name"
+	// FieldKind holds the string denoting the kind field in the database.
+	FieldKind = "kind"
+	// FieldBranch holds the string denoting the branch field in the database.
+	FieldBranch = "branch"
+	// FieldTokenHash holds the string denoting the token_hash field in the database.
+	FieldTokenHash = "token_hash"
+	// FieldPairingCodeHash holds the string denoting the pairing_code_hash field in the database.
+	FieldPairingCodeHash = "pairing_code_hash"
+	// FieldPairingExpiresAt holds the string denoting the pairing_expires_at field in the database.
+	FieldPairingExpiresAt = "pairing_expires_at"
+	// FieldPairedAt holds the string denoting the paired_at field in the database.
+	FieldPairedAt = "paired_at"
+	// FieldPrivacy holds the string denoting the privacy field in the database.
+	FieldPrivacy = "privacy"
+	// FieldRevokedAt holds the string denoting the revoked_at field in the database.
+	FieldRevokedAt = "revoked_at"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// Table holds the table name of the device in the database.
+	Table = "devices"
+)
+
+// Columns holds all SQL columns for device fields.
+var Columns = []string{
+	FieldID,
+	FieldName,
+	FieldKind,
+	FieldBranch,
+	FieldTokenHash,
+	FieldPairingCodeHash,
+	FieldPairingExpiresAt,
+	FieldPairedAt,
+	FieldPrivacy,
+	FieldRevokedAt,
+	FieldCreatedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// NameValidator is a validator for the "name" field. It is called by the builders before save.
+	NameValidator func(string) error
+	// KindValidator is a validator for the "kind" field. It is called by the builders before save.
+	KindValidator func(string) error
+	// BranchValidator is a validator for the "branch" field. It is called by the builders before save.
+	BranchValidator func(string) error
+	// TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
+	TokenHashValidator func(string) error
+	// PairingCodeHashValidator is a validator for the "pairing_code_hash" field. It is called by the builders before save.
+	PairingCodeHashValidator func(string) error
+	// PrivacyValidator is a validator for the "privacy" field. It is called by the builders before save.
+	PrivacyValidator func(string) error
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
+
+// OrderOption defines the ordering options for the Device queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByName orders the results by the name field.
+func ByName(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldName, opts...).ToFunc()
+}
+
+// ByKind orders the results by the kind field.
+func ByKind(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldKind, opts...).ToFunc()
+}
+
+// ByBranch orders the results by the branch field.
+func ByBranch(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBranch, opts...).ToFunc()
+}
+
+// ByTokenHash orders the results by the token_hash field.
+func ByTokenHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTokenHash, opts...).ToFunc()
+}
+
+// ByPairingCodeHash orders the results by the pairing_code_hash field.
+func ByPairingCodeHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPairingCodeHash, opts...).ToFunc()
+}
+
+// ByPairingExpiresAt orders the results by the pairing_expires_at field.
+func ByPairingExpiresAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPairingExpiresAt, opts...).ToFunc()
+}
+
+// ByPairedAt orders the results by the paired_at field.
+func ByPairedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPairedAt, opts...).ToFunc()
+}
+
+// ByPrivacy orders the results by the privacy field.
+func ByPrivacy(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPrivacy, opts...).ToFunc()
+}
+
+// ByRevokedAt orders the results by the revoked_at field.
+func ByRevokedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRevokedAt, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
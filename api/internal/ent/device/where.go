@@ -0,0 +1,730 @@
+// Code generated by ent, DO NOT EDIT.
+
+package device
+
+import (
+	"time"
+	"via/internal/ent/predicate"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldID, id))
+}
+
+// Name applies equality check predicate on the "name" field. It's identical to NameEQ.
+func Name(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldName, v))
+}
+
+// Kind applies equality check predicate on the "kind" field. It's identical to KindEQ.
+func Kind(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldKind, v))
+}
+
+// Branch applies equality check predicate on the "branch" field. It's identical to BranchEQ.
+func Branch(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldBranch, v))
+}
+
+// TokenHash applies equality check predicate on the "token_hash" field. It's identical to TokenHashEQ.
+func TokenHash(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldTokenHash, v))
+}
+
+// PairingCodeHash applies equality check predicate on the "pairing_code_hash" field. It's identical to PairingCodeHashEQ.
+func PairingCodeHash(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldPairingCodeHash, v))
+}
+
+// PairingExpiresAt applies equality check predicate on the "pairing_expires_at" field. It's identical to PairingExpiresAtEQ.
+func PairingExpiresAt(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldPairingExpiresAt, v))
+}
+
+// PairedAt applies equality check predicate on the "paired_at" field. It's identical to PairedAtEQ.
+func PairedAt(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldPairedAt, v))
+}
+
+// Privacy applies equality check predicate on the "privacy" field. It's identical to PrivacyEQ.
+func Privacy(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldPrivacy, v))
+}
+
+// RevokedAt applies equality check predicate on the "revoked_at" field. It's identical to RevokedAtEQ.
+func RevokedAt(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldRevokedAt, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// NameEQ applies the EQ predicate on the "name" field.
+func NameEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldName, v))
+}
+
+// NameNEQ applies the NEQ predicate on the "name" field.
+func NameNEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldName, v))
+}
+
+// NameIn applies the In predicate on the "name" field.
+func NameIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldName, vs...))
+}
+
+// NameNotIn applies the NotIn predicate on the "name" field.
+func NameNotIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldName, vs...))
+}
+
+// NameGT applies the GT predicate on the "name" field.
+func NameGT(v string) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldName, v))
+}
+
+// NameGTE applies the GTE predicate on the "name" field.
+func NameGTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldName, v))
+}
+
+// NameLT applies the LT predicate on the "name" field.
+func NameLT(v string) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldName, v))
+}
+
+// NameLTE applies the LTE predicate on the "name" field.
+func NameLTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldName, v))
+}
+
+// NameContains applies the Contains predicate on the "name" field.
+func NameContains(v string) predicate.Device {
+	return predicate.Device(sql.FieldContains(FieldName, v))
+}
+
+// NameHasPrefix applies the HasPrefix predicate on the "name" field.
+func NameHasPrefix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasPrefix(FieldName, v))
+}
+
+// NameHasSuffix applies the HasSuffix predicate on the "name" field.
+func NameHasSuffix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasSuffix(FieldName, v))
+}
+
+// NameEqualFold applies the EqualFold predicate on the "name" field.
+func NameEqualFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldEqualFold(FieldName, v))
+}
+
+// NameContainsFold applies the ContainsFold predicate on the "name" field.
+func NameContainsFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldContainsFold(FieldName, v))
+}
+
+// KindEQ applies the EQ predicate on the "kind" field.
+func KindEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldKind, v))
+}
+
+// KindNEQ applies the NEQ predicate on the "kind" field.
+func KindNEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldKind, v))
+}
+
+// KindIn applies the In predicate on the "kind" field.
+func KindIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldKind, vs...))
+}
+
+// KindNotIn applies the NotIn predicate on the "kind" field.
+func KindNotIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldKind, vs...))
+}
+
+// KindGT applies the GT predicate on the "kind" field.
+func KindGT(v string) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldKind, v))
+}
+
+// KindGTE applies the GTE predicate on the "kind" field.
+func KindGTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldKind, v))
+}
+
+// KindLT applies the LT predicate on the "kind" field.
+func KindLT(v string) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldKind, v))
+}
+
+// KindLTE applies the LTE predicate on the "kind" field.
+func KindLTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldKind, v))
+}
+
+// KindContains applies the Contains predicate on the "kind" field.
+func KindContains(v string) predicate.Device {
+	return predicate.Device(sql.FieldContains(FieldKind, v))
+}
+
+// KindHasPrefix applies the HasPrefix predicate on the "kind" field.
+func KindHasPrefix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasPrefix(FieldKind, v))
+}
+
+// KindHasSuffix applies the HasSuffix predicate on the "kind" field.
+func KindHasSuffix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasSuffix(FieldKind, v))
+}
+
+// KindEqualFold applies the EqualFold predicate on the "kind" field.
+func KindEqualFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldEqualFold(FieldKind, v))
+}
+
+// KindContainsFold applies the ContainsFold predicate on the "kind" field.
+func KindContainsFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldContainsFold(FieldKind, v))
+}
+
+// BranchEQ applies the EQ predicate on the "branch" field.
+func BranchEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldBranch, v))
+}
+
+// BranchNEQ applies the NEQ predicate on the "branch" field.
+func BranchNEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldBranch, v))
+}
+
+// BranchIn applies the In predicate on the "branch" field.
+func BranchIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldBranch, vs...))
+}
+
+// BranchNotIn applies the NotIn predicate on the "branch" field.
+func BranchNotIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldBranch, vs...))
+}
+
+// BranchGT applies the GT predicate on the "branch" field.
+func BranchGT(v string) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldBranch, v))
+}
+
+// BranchGTE applies the GTE predicate on the "branch" field.
+func BranchGTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldBranch, v))
+}
+
+// BranchLT applies the LT predicate on the "branch" field.
+func BranchLT(v string) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldBranch, v))
+}
+
+// BranchLTE applies the LTE predicate on the "branch" field.
+func BranchLTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldBranch, v))
+}
+
+// BranchContains applies the Contains predicate on the "branch" field.
+func BranchContains(v string) predicate.Device {
+	return predicate.Device(sql.FieldContains(FieldBranch, v))
+}
+
+// BranchHasPrefix applies the HasPrefix predicate on the "branch" field.
+func BranchHasPrefix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasPrefix(FieldBranch, v))
+}
+
+// BranchHasSuffix applies the HasSuffix predicate on the "branch" field.
+func BranchHasSuffix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasSuffix(FieldBranch, v))
+}
+
+// BranchEqualFold applies the EqualFold predicate on the "branch" field.
+func BranchEqualFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldEqualFold(FieldBranch, v))
+}
+
+// BranchContainsFold applies the ContainsFold predicate on the "branch" field.
+func BranchContainsFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldContainsFold(FieldBranch, v))
+}
+
+// TokenHashEQ applies the EQ predicate on the "token_hash" field.
+func TokenHashEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldTokenHash, v))
+}
+
+// TokenHashNEQ applies the NEQ predicate on the "token_hash" field.
+func TokenHashNEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldTokenHash, v))
+}
+
+// TokenHashIn applies the In predicate on the "token_hash" field.
+func TokenHashIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldTokenHash, vs...))
+}
+
+// TokenHashNotIn applies the NotIn predicate on the "token_hash" field.
+func TokenHashNotIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldTokenHash, vs...))
+}
+
+// TokenHashGT applies the GT predicate on the "token_hash" field.
+func TokenHashGT(v string) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldTokenHash, v))
+}
+
+// TokenHashGTE applies the GTE predicate on the "token_hash" field.
+func TokenHashGTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldTokenHash, v))
+}
+
+// TokenHashLT applies the LT predicate on the "token_hash" field.
+func TokenHashLT(v string) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldTokenHash, v))
+}
+
+// TokenHashLTE applies the LTE predicate on the "token_hash" field.
+func TokenHashLTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldTokenHash, v))
+}
+
+// TokenHashContains applies the Contains predicate on the "token_hash" field.
+func TokenHashContains(v string) predicate.Device {
+	return predicate.Device(sql.FieldContains(FieldTokenHash, v))
+}
+
+// TokenHashHasPrefix applies the HasPrefix predicate on the "token_hash" field.
+func TokenHashHasPrefix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasPrefix(FieldTokenHash, v))
+}
+
+// TokenHashHasSuffix applies the HasSuffix predicate on the "token_hash" field.
+func TokenHashHasSuffix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasSuffix(FieldTokenHash, v))
+}
+
+// TokenHashIsNil applies the IsNil predicate on the "token_hash" field.
+func TokenHashIsNil() predicate.Device {
+	return predicate.Device(sql.FieldIsNull(FieldTokenHash))
+}
+
+// TokenHashNotNil applies the NotNil predicate on the "token_hash" field.
+func TokenHashNotNil() predicate.Device {
+	return predicate.Device(sql.FieldNotNull(FieldTokenHash))
+}
+
+// TokenHashEqualFold applies the EqualFold predicate on the "token_hash" field.
+func TokenHashEqualFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldEqualFold(FieldTokenHash, v))
+}
+
+// TokenHashContainsFold applies the ContainsFold predicate on the "token_hash" field.
+func TokenHashContainsFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldContainsFold(FieldTokenHash, v))
+}
+
+// PairingCodeHashEQ applies the EQ predicate on the "pairing_code_hash" field.
+func PairingCodeHashEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashNEQ applies the NEQ predicate on the "pairing_code_hash" field.
+func PairingCodeHashNEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashIn applies the In predicate on the "pairing_code_hash" field.
+func PairingCodeHashIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldPairingCodeHash, vs...))
+}
+
+// PairingCodeHashNotIn applies the NotIn predicate on the "pairing_code_hash" field.
+func PairingCodeHashNotIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldPairingCodeHash, vs...))
+}
+
+// PairingCodeHashGT applies the GT predicate on the "pairing_code_hash" field.
+func PairingCodeHashGT(v string) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashGTE applies the GTE predicate on the "pairing_code_hash" field.
+func PairingCodeHashGTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashLT applies the LT predicate on the "pairing_code_hash" field.
+func PairingCodeHashLT(v string) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashLTE applies the LTE predicate on the "pairing_code_hash" field.
+func PairingCodeHashLTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashContains applies the Contains predicate on the "pairing_code_hash" field.
+func PairingCodeHashContains(v string) predicate.Device {
+	return predicate.Device(sql.FieldContains(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashHasPrefix applies the HasPrefix predicate on the "pairing_code_hash" field.
+func PairingCodeHashHasPrefix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasPrefix(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashHasSuffix applies the HasSuffix predicate on the "pairing_code_hash" field.
+func PairingCodeHashHasSuffix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasSuffix(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashIsNil applies the IsNil predicate on the "pairing_code_hash" field.
+func PairingCodeHashIsNil() predicate.Device {
+	return predicate.Device(sql.FieldIsNull(FieldPairingCodeHash))
+}
+
+// PairingCodeHashNotNil applies the NotNil predicate on the "pairing_code_hash" field.
+func PairingCodeHashNotNil() predicate.Device {
+	return predicate.Device(sql.FieldNotNull(FieldPairingCodeHash))
+}
+
+// PairingCodeHashEqualFold applies the EqualFold predicate on the "pairing_code_hash" field.
+func PairingCodeHashEqualFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldEqualFold(FieldPairingCodeHash, v))
+}
+
+// PairingCodeHashContainsFold applies the ContainsFold predicate on the "pairing_code_hash" field.
+func PairingCodeHashContainsFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldContainsFold(FieldPairingCodeHash, v))
+}
+
+// PairingExpiresAtEQ applies the EQ predicate on the "pairing_expires_at" field.
+func PairingExpiresAtEQ(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldPairingExpiresAt, v))
+}
+
+// PairingExpiresAtNEQ applies the NEQ predicate on the "pairing_expires_at" field.
+func PairingExpiresAtNEQ(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldPairingExpiresAt, v))
+}
+
+// PairingExpiresAtIn applies the In predicate on the "pairing_expires_at" field.
+func PairingExpiresAtIn(vs ...time.Time) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldPairingExpiresAt, vs...))
+}
+
+// PairingExpiresAtNotIn applies the NotIn predicate on the "pairing_expires_at" field.
+func PairingExpiresAtNotIn(vs ...time.Time) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldPairingExpiresAt, vs...))
+}
+
+// PairingExpiresAtGT applies the GT predicate on the "pairing_expires_at" field.
+func PairingExpiresAtGT(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldPairingExpiresAt, v))
+}
+
+// PairingExpiresAtGTE applies the GTE predicate on the "pairing_expires_at" field.
+func PairingExpiresAtGTE(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldPairingExpiresAt, v))
+}
+
+// PairingExpiresAtLT applies the LT predicate on the "pairing_expires_at" field.
+func PairingExpiresAtLT(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldPairingExpiresAt, v))
+}
+
+// PairingExpiresAtLTE applies the LTE predicate on the "pairing_expires_at" field.
+func PairingExpiresAtLTE(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldPairingExpiresAt, v))
+}
+
+// PairingExpiresAtIsNil applies the IsNil predicate on the "pairing_expires_at" field.
+func PairingExpiresAtIsNil() predicate.Device {
+	return predicate.Device(sql.FieldIsNull(FieldPairingExpiresAt))
+}
+
+// PairingExpiresAtNotNil applies the NotNil predicate on the "pairing_expires_at" field.
+func PairingExpiresAtNotNil() predicate.Device {
+	return predicate.Device(sql.FieldNotNull(FieldPairingExpiresAt))
+}
+
+// PairedAtEQ applies the EQ predicate on the "paired_at" field.
+func PairedAtEQ(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldPairedAt, v))
+}
+
+// PairedAtNEQ applies the NEQ predicate on the "paired_at" field.
+func PairedAtNEQ(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldPairedAt, v))
+}
+
+// PairedAtIn applies the In predicate on the "paired_at" field.
+func PairedAtIn(vs ...time.Time) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldPairedAt, vs...))
+}
+
+// PairedAtNotIn applies the NotIn predicate on the "paired_at" field.
+func PairedAtNotIn(vs ...time.Time) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldPairedAt, vs...))
+}
+
+// PairedAtGT applies the GT predicate on the "paired_at" field.
+func PairedAtGT(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldPairedAt, v))
+}
+
+// PairedAtGTE applies the GTE predicate on the "paired_at" field.
+func PairedAtGTE(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldPairedAt, v))
+}
+
+// PairedAtLT applies the LT predicate on the "paired_at" field.
+func PairedAtLT(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldPairedAt, v))
+}
+
+// PairedAtLTE applies the LTE predicate on the "paired_at" field.
+func PairedAtLTE(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldPairedAt, v))
+}
+
+// PairedAtIsNil applies the IsNil predicate on the "paired_at" field.
+func PairedAtIsNil() predicate.Device {
+	return predicate.Device(sql.FieldIsNull(FieldPairedAt))
+}
+
+// PairedAtNotNil applies the NotNil predicate on the "paired_at" field.
+func PairedAtNotNil() predicate.Device {
+	return predicate.Device(sql.FieldNotNull(FieldPairedAt))
+}
+
+// PrivacyEQ applies the EQ predicate on the "privacy" field.
+func PrivacyEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldPrivacy, v))
+}
+
+// PrivacyNEQ applies the NEQ predicate on the "privacy" field.
+func PrivacyNEQ(v string) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldPrivacy, v))
+}
+
+// PrivacyIn applies the In predicate on the "privacy" field.
+func PrivacyIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldPrivacy, vs...))
+}
+
+// PrivacyNotIn applies the NotIn predicate on the "privacy" field.
+func PrivacyNotIn(vs ...string) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldPrivacy, vs...))
+}
+
+// PrivacyGT applies the GT predicate on the "privacy" field.
+func PrivacyGT(v string) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldPrivacy, v))
+}
+
+// PrivacyGTE applies the GTE predicate on the "privacy" field.
+func PrivacyGTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldPrivacy, v))
+}
+
+// PrivacyLT applies the LT predicate on the "privacy" field.
+func PrivacyLT(v string) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldPrivacy, v))
+}
+
+// PrivacyLTE applies the LTE predicate on the "privacy" field.
+func PrivacyLTE(v string) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldPrivacy, v))
+}
+
+// PrivacyContains applies the Contains predicate on the "privacy" field.
+func PrivacyContains(v string) predicate.Device {
+	return predicate.Device(sql.FieldContains(FieldPrivacy, v))
+}
+
+// PrivacyHasPrefix applies the HasPrefix predicate on the "privacy" field.
+func PrivacyHasPrefix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasPrefix(FieldPrivacy, v))
+}
+
+// PrivacyHasSuffix applies the HasSuffix predicate on the "privacy" field.
+func PrivacyHasSuffix(v string) predicate.Device {
+	return predicate.Device(sql.FieldHasSuffix(FieldPrivacy, v))
+}
+
+// PrivacyIsNil applies the IsNil predicate on the "privacy" field.
+func PrivacyIsNil() predicate.Device {
+	return predicate.Device(sql.FieldIsNull(FieldPrivacy))
+}
+
+// PrivacyNotNil applies the NotNil predicate on the "privacy" field.
+func PrivacyNotNil() predicate.Device {
+	return predicate.Device(sql.FieldNotNull(FieldPrivacy))
+}
+
+// PrivacyEqualFold applies the EqualFold predicate on the "privacy" field.
+func PrivacyEqualFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldEqualFold(FieldPrivacy, v))
+}
+
+// PrivacyContainsFold applies the ContainsFold predicate on the "privacy" field.
+func PrivacyContainsFold(v string) predicate.Device {
+	return predicate.Device(sql.FieldContainsFold(FieldPrivacy, v))
+}
+
+// RevokedAtEQ applies the EQ predicate on the "revoked_at" field.
+func RevokedAtEQ(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldRevokedAt, v))
+}
+
+// RevokedAtNEQ applies the NEQ predicate on the "revoked_at" field.
+func RevokedAtNEQ(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldRevokedAt, v))
+}
+
+// RevokedAtIn applies the In predicate on the "revoked_at" field.
+func RevokedAtIn(vs ...time.Time) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldRevokedAt, vs...))
+}
+
+// RevokedAtNotIn applies the NotIn predicate on the "revoked_at" field.
+func RevokedAtNotIn(vs ...time.Time) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldRevokedAt, vs...))
+}
+
+// RevokedAtGT applies the GT predicate on the "revoked_at" field.
+func RevokedAtGT(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldRevokedAt, v))
+}
+
+// RevokedAtGTE applies the GTE predicate on the "revoked_at" field.
+func RevokedAtGTE(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldRevokedAt, v))
+}
+
+// RevokedAtLT applies the LT predicate on the "revoked_at" field.
+func RevokedAtLT(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldRevokedAt, v))
+}
+
+// RevokedAtLTE applies the LTE predicate on the "revoked_at" field.
+func RevokedAtLTE(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldRevokedAt, v))
+}
+
+// RevokedAtIsNil applies the IsNil predicate on the "revoked_at" field.
+func RevokedAtIsNil() predicate.Device {
+	return predicate.Device(sql.FieldIsNull(FieldRevokedAt))
+}
+
+// RevokedAtNotNil applies the NotNil predicate on the "revoked_at" field.
+func RevokedAtNotNil() predicate.Device {
+	return predicate.Device(sql.FieldNotNull(FieldRevokedAt))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.Device {
+	return predicate.Device(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.Device {
+	return predicate.Device(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.Device {
+	return predicate.Device(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.Device) predicate.Device {
+	return predicate.Device(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.Device) predicate.Device {
+	return predicate.Device(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.Device) predicate.Device {
+	return predicate.Device(sql.NotPredicates(p))
+}
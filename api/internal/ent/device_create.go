@@ -0,0 +1,376 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"via/internal/ent/device"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// DeviceCreate is the builder for creating a Device entity.
+type DeviceCreate struct {
+	config
+	mutation *DeviceMutation
+	hooks    []Hook
+}
+
+// SetName sets the "name" field.
+func (dc *DeviceCreate) SetName(s string) *DeviceCreate {
+	dc.mutation.SetName(s)
+	return dc
+}
+
+// SetKind sets the "kind" field.
+func (dc *DeviceCreate) SetKind(s string) *DeviceCreate {
+	dc.mutation.SetKind(s)
+	return dc
+}
+
+// SetBranch sets the "branch" field.
+func (dc *DeviceCreate) SetBranch(s string) *DeviceCreate {
+	dc.mutation.SetBranch(s)
+	return dc
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (dc *DeviceCreate) SetTokenHash(s string) *DeviceCreate {
+	dc.mutation.SetTokenHash(s)
+	return dc
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillableTokenHash(s *string) *DeviceCreate {
+	if s != nil {
+		dc.SetTokenHash(*s)
+	}
+	return dc
+}
+
+// SetPairingCodeHash sets the "pairing_code_hash" field.
+func (dc *DeviceCreate) SetPairingCodeHash(s string) *DeviceCreate {
+	dc.mutation.SetPairingCodeHash(s)
+	return dc
+}
+
+// SetNillablePairingCodeHash sets the "pairing_code_hash" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillablePairingCodeHash(s *string) *DeviceCreate {
+	if s != nil {
+		dc.SetPairingCodeHash(*s)
+	}
+	return dc
+}
+
+// SetPairingExpiresAt sets the "pairing_expires_at" field.
+func (dc *DeviceCreate) SetPairingExpiresAt(t time.Time) *DeviceCreate {
+	dc.mutation.SetPairingExpiresAt(t)
+	return dc
+}
+
+// SetNillablePairingExpiresAt sets the "pairing_expires_at" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillablePairingExpiresAt(t *time.Time) *DeviceCreate {
+	if t != nil {
+		dc.SetPairingExpiresAt(*t)
+	}
+	return dc
+}
+
+// SetPairedAt sets the "paired_at" field.
+func (dc *DeviceCreate) SetPairedAt(t time.Time) *DeviceCreate {
+	dc.mutation.SetPairedAt(t)
+	return dc
+}
+
+// SetNillablePairedAt sets the "paired_at" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillablePairedAt(t *time.Time) *DeviceCreate {
+	if t != nil {
+		dc.SetPairedAt(*t)
+	}
+	return dc
+}
+
+// SetPrivacy sets the "privacy" field.
+func (dc *DeviceCreate) SetPrivacy(s string) *DeviceCreate {
+	dc.mutation.SetPrivacy(s)
+	return dc
+}
+
+// SetNillablePrivacy sets the "privacy" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillablePrivacy(s *string) *DeviceCreate {
+	if s != nil {
+		dc.SetPrivacy(*s)
+	}
+	return dc
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (dc *DeviceCreate) SetRevokedAt(t time.Time) *DeviceCreate {
+	dc.mutation.SetRevokedAt(t)
+	return dc
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillableRevokedAt(t *time.Time) *DeviceCreate {
+	if t != nil {
+		dc.SetRevokedAt(*t)
+	}
+	return dc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (dc *DeviceCreate) SetCreatedAt(t time.Time) *DeviceCreate {
+	dc.mutation.SetCreatedAt(t)
+	return dc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillableCreatedAt(t *time.Time) *DeviceCreate {
+	if t != nil {
+		dc.SetCreatedAt(*t)
+	}
+	return dc
+}
+
+// Mutation returns the DeviceMutation object of the builder.
+func (dc *DeviceCreate) Mutation() *DeviceMutation {
+	return dc.mutation
+}
+
+// Save creates the Device in the database.
+func (dc *DeviceCreate) Save(ctx context.Context) (*Device, error) {
+	dc.defaults()
+	return withHooks(ctx, dc.sqlSave, dc.mutation, dc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (dc *DeviceCreate) SaveX(ctx context.Context) *Device {
+	v, err := dc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (dc *DeviceCreate) Exec(ctx context.Context) error {
+	_, err := dc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dc *DeviceCreate) ExecX(ctx context.Context) {
+	if err := dc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (dc *DeviceCreate) defaults() {
+	if _, ok := dc.mutation.CreatedAt(); !ok {
+		v := device.DefaultCreatedAt()
+		dc.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (dc *DeviceCreate) check() error {
+	if _, ok := dc.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "Device.name"`)}
+	}
+	if v, ok := dc.mutation.Name(); ok {
+		if err := device.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "Device.name": %w`, err)}
+		}
+	}
+	if _, ok := dc.mutation.Kind(); !ok {
+		return &ValidationError{Name: "kind", err: errors.New(`ent: missing required field "Device.kind"`)}
+	}
+	if v, ok := dc.mutation.Kind(); ok {
+		if err := device.KindValidator(v); err != nil {
+			return &ValidationError{Name: "kind", err: fmt.Errorf(`ent: validator failed for field "Device.kind": %w`, err)}
+		}
+	}
+	if _, ok := dc.mutation.Branch(); !ok {
+		return &ValidationError{Name: "branch", err: errors.New(`ent: missing required field "Device.branch"`)}
+	}
+	if v, ok := dc.mutation.Branch(); ok {
+		if err := device.BranchValidator(v); err != nil {
+			return &ValidationError{Name: "branch", err: fmt.Errorf(`ent: validator failed for field "Device.branch": %w`, err)}
+		}
+	}
+	if v, ok := dc.mutation.TokenHash(); ok {
+		if err := device.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "Device.token_hash": %w`, err)}
+		}
+	}
+	if v, ok := dc.mutation.PairingCodeHash(); ok {
+		if err := device.PairingCodeHashValidator(v); err != nil {
+			return &ValidationError{Name: "pairing_code_hash", err: fmt.Errorf(`ent: validator failed for field "Device.pairing_code_hash": %w`, err)}
+		}
+	}
+	if v, ok := dc.mutation.Privacy(); ok {
+		if err := device.PrivacyValidator(v); err != nil {
+			return &ValidationError{Name: "privacy", err: fmt.Errorf(`ent: validator failed for field "Device.privacy": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (dc *DeviceCreate) sqlSave(ctx context.Context) (*Device, error) {
+	if err := dc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := dc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, dc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	dc.mutation.id = &_node.ID
+	dc.mutation.done = true
+	return _node, nil
+}
+
+func (dc *DeviceCreate) createSpec() (*Device, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Device{config: dc.config}
+		_spec = sqlgraph.NewCreateSpec(device.Table, sqlgraph.NewFieldSpec(device.FieldID, field.TypeInt))
+	)
+	if value, ok := dc.mutation.Name(); ok {
+		_spec.SetField(device.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := dc.mutation.Kind(); ok {
+		_spec.SetField(device.FieldKind, field.TypeString, value)
+		_node.Kind = value
+	}
+	if value, ok := dc.mutation.Branch(); ok {
+		_spec.SetField(device.FieldBranch, field.TypeString, value)
+		_node.Branch = value
+	}
+	if value, ok := dc.mutation.TokenHash(); ok {
+		_spec.SetField(device.FieldTokenHash, field.TypeString, value)
+		_node.TokenHash = value
+	}
+	if value, ok := dc.mutation.PairingCodeHash(); ok {
+		_spec.SetField(device.FieldPairingCodeHash, field.TypeString, value)
+		_node.PairingCodeHash = value
+	}
+	if value, ok := dc.mutation.PairingExpiresAt(); ok {
+		_spec.SetField(device.FieldPairingExpiresAt, field.TypeTime, value)
+		_node.PairingExpiresAt = &value
+	}
+	if value, ok := dc.mutation.PairedAt(); ok {
+		_spec.SetField(device.FieldPairedAt, field.TypeTime, value)
+		_node.PairedAt = &value
+	}
+	if value, ok := dc.mutation.Privacy(); ok {
+		_spec.SetField(device.FieldPrivacy, field.TypeString, value)
+		_node.Privacy = value
+	}
+	if value, ok := dc.mutation.RevokedAt(); ok {
+		_spec.SetField(device.FieldRevokedAt, field.TypeTime, value)
+		_node.RevokedAt = &value
+	}
+	if value, ok := dc.mutation.CreatedAt(); ok {
+		_spec.SetField(device.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// DeviceCreateBulk is the builder for creating many Device entities in bulk.
+type DeviceCreateBulk struct {
+	config
+	err      error
+	builders []*DeviceCreate
+}
+
+// Save creates the Device entities in the database.
+func (dcb *DeviceCreateBulk) Save(ctx context.Context) ([]*Device, error) {
+	if dcb.err != nil {
+		return nil, dcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(dcb.builders))
+	nodes := make([]*Device, len(dcb.builders))
+	mutators := make([]Mutator, len(dcb.builders))
+	for i := range dcb.builders {
+		func(i int, root context.Context) {
+			builder := dcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*DeviceMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, dcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, dcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, dcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (dcb *DeviceCreateBulk) SaveX(ctx context.Context) []*Device {
+	v, err := dcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (dcb *DeviceCreateBulk) Exec(ctx context.Context) error {
+	_, err := dcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dcb *DeviceCreateBulk) ExecX(ctx context.Context) {
+	if err := dcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
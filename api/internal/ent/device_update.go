@@ -0,0 +1,612 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"via/internal/ent/device"
+	"via/internal/ent/predicate"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// DeviceUpdate is the builder for updating Device entities.
+type DeviceUpdate struct {
+	config
+	hooks    []Hook
+	mutation *DeviceMutation
+}
+
+// Where appends a list predicates to the DeviceUpdate builder.
+func (du *DeviceUpdate) Where(ps ...predicate.Device) *DeviceUpdate {
+	du.mutation.Where(ps...)
+	return du
+}
+
+// SetName sets the "name" field.
+func (du *DeviceUpdate) SetName(s string) *DeviceUpdate {
+	du.mutation.SetName(s)
+	return du
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillableName(s *string) *DeviceUpdate {
+	if s != nil {
+		du.SetName(*s)
+	}
+	return du
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (du *DeviceUpdate) SetTokenHash(s string) *DeviceUpdate {
+	du.mutation.SetTokenHash(s)
+	return du
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillableTokenHash(s *string) *DeviceUpdate {
+	if s != nil {
+		du.SetTokenHash(*s)
+	}
+	return du
+}
+
+// ClearTokenHash clears the value of the "token_hash" field.
+func (du *DeviceUpdate) ClearTokenHash() *DeviceUpdate {
+	du.mutation.ClearTokenHash()
+	return du
+}
+
+// SetPairingCodeHash sets the "pairing_code_hash" field.
+func (du *DeviceUpdate) SetPairingCodeHash(s string) *DeviceUpdate {
+	du.mutation.SetPairingCodeHash(s)
+	return du
+}
+
+// SetNillablePairingCodeHash sets the "pairing_code_hash" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillablePairingCodeHash(s *string) *DeviceUpdate {
+	if s != nil {
+		du.SetPairingCodeHash(*s)
+	}
+	return du
+}
+
+// ClearPairingCodeHash clears the value of the "pairing_code_hash" field.
+func (du *DeviceUpdate) ClearPairingCodeHash() *DeviceUpdate {
+	du.mutation.ClearPairingCodeHash()
+	return du
+}
+
+// SetPairingExpiresAt sets the "pairing_expires_at" field.
+func (du *DeviceUpdate) SetPairingExpiresAt(t time.Time) *DeviceUpdate {
+	du.mutation.SetPairingExpiresAt(t)
+	return du
+}
+
+// SetNillablePairingExpiresAt sets the "pairing_expires_at" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillablePairingExpiresAt(t *time.Time) *DeviceUpdate {
+	if t != nil {
+		du.SetPairingExpiresAt(*t)
+	}
+	return du
+}
+
+// ClearPairingExpiresAt clears the value of the "pairing_expires_at" field.
+func (du *DeviceUpdate) ClearPairingExpiresAt() *DeviceUpdate {
+	du.mutation.ClearPairingExpiresAt()
+	return du
+}
+
+// SetPairedAt sets the "paired_at" field.
+func (du *DeviceUpdate) SetPairedAt(t time.Time) *DeviceUpdate {
+	du.mutation.SetPairedAt(t)
+	return du
+}
+
+// SetNillablePairedAt sets the "paired_at" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillablePairedAt(t *time.Time) *DeviceUpdate {
+	if t != nil {
+		du.SetPairedAt(*t)
+	}
+	return du
+}
+
+// ClearPairedAt clears the value of the "paired_at" field.
+func (du *DeviceUpdate) ClearPairedAt() *DeviceUpdate {
+	du.mutation.ClearPairedAt()
+	return du
+}
+
+// SetPrivacy sets the "privacy" field.
+func (du *DeviceUpdate) SetPrivacy(s string) *DeviceUpdate {
+	du.mutation.SetPrivacy(s)
+	return du
+}
+
+// SetNillablePrivacy sets the "privacy" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillablePrivacy(s *string) *DeviceUpdate {
+	if s != nil {
+		du.SetPrivacy(*s)
+	}
+	return du
+}
+
+// ClearPrivacy clears the value of the "privacy" field.
+func (du *DeviceUpdate) ClearPrivacy() *DeviceUpdate {
+	du.mutation.ClearPrivacy()
+	return du
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (du *DeviceUpdate) SetRevokedAt(t time.Time) *DeviceUpdate {
+	du.mutation.SetRevokedAt(t)
+	return du
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillableRevokedAt(t *time.Time) *DeviceUpdate {
+	if t != nil {
+		du.SetRevokedAt(*t)
+	}
+	return du
+}
+
+// ClearRevokedAt clears the value of the "revoked_at" field.
+func (du *DeviceUpdate) ClearRevokedAt() *DeviceUpdate {
+	du.mutation.ClearRevokedAt()
+	return du
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (du *DeviceUpdate) SetCreatedAt(t time.Time) *DeviceUpdate {
+	du.mutation.SetCreatedAt(t)
+	return du
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillableCreatedAt(t *time.Time) *DeviceUpdate {
+	if t != nil {
+		du.SetCreatedAt(*t)
+	}
+	return du
+}
+
+// Mutation returns the DeviceMutation object of the builder.
+func (du *DeviceUpdate) Mutation() *DeviceMutation {
+	return du.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (du *DeviceUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, du.sqlSave, du.mutation, du.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (du *DeviceUpdate) SaveX(ctx context.Context) int {
+	affected, err := du.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (du *DeviceUpdate) Exec(ctx context.Context) error {
+	_, err := du.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (du *DeviceUpdate) ExecX(ctx context.Context) {
+	if err := du.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (du *DeviceUpdate) check() error {
+	if v, ok := du.mutation.Name(); ok {
+		if err := device.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "Device.name": %w`, err)}
+		}
+	}
+	if v, ok := du.mutation.TokenHash(); ok {
+		if err := device.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "Device.token_hash": %w`, err)}
+		}
+	}
+	if v, ok := du.mutation.PairingCodeHash(); ok {
+		if err := device.PairingCodeHashValidator(v); err != nil {
+			return &ValidationError{Name: "pairing_code_hash", err: fmt.Errorf(`ent: validator failed for field "Device.pairing_code_hash": %w`, err)}
+		}
+	}
+	if v, ok := du.mutation.Privacy(); ok {
+		if err := device.PrivacyValidator(v); err != nil {
+			return &ValidationError{Name: "privacy", err: fmt.Errorf(`ent: validator failed for field "Device.privacy": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (du *DeviceUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := du.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(device.Table, device.Columns, sqlgraph.NewFieldSpec(device.FieldID, field.TypeInt))
+	if ps := du.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := du.mutation.Name(); ok {
+		_spec.SetField(device.FieldName, field.TypeString, value)
+	}
+	if value, ok := du.mutation.TokenHash(); ok {
+		_spec.SetField(device.FieldTokenHash, field.TypeString, value)
+	}
+	if du.mutation.TokenHashCleared() {
+		_spec.ClearField(device.FieldTokenHash, field.TypeString)
+	}
+	if value, ok := du.mutation.PairingCodeHash(); ok {
+		_spec.SetField(device.FieldPairingCodeHash, field.TypeString, value)
+	}
+	if du.mutation.PairingCodeHashCleared() {
+		_spec.ClearField(device.FieldPairingCodeHash, field.TypeString)
+	}
+	if value, ok := du.mutation.PairingExpiresAt(); ok {
+		_spec.SetField(device.FieldPairingExpiresAt, field.TypeTime, value)
+	}
+	if du.mutation.PairingExpiresAtCleared() {
+		_spec.ClearField(device.FieldPairingExpiresAt, field.TypeTime)
+	}
+	if value, ok := du.mutation.PairedAt(); ok {
+		_spec.SetField(device.FieldPairedAt, field.TypeTime, value)
+	}
+	if du.mutation.PairedAtCleared() {
+		_spec.ClearField(device.FieldPairedAt, field.TypeTime)
+	}
+	if value, ok := du.mutation.Privacy(); ok {
+		_spec.SetField(device.FieldPrivacy, field.TypeString, value)
+	}
+	if du.mutation.PrivacyCleared() {
+		_spec.ClearField(device.FieldPrivacy, field.TypeString)
+	}
+	if value, ok := du.mutation.RevokedAt(); ok {
+		_spec.SetField(device.FieldRevokedAt, field.TypeTime, value)
+	}
+	if du.mutation.RevokedAtCleared() {
+		_spec.ClearField(device.FieldRevokedAt, field.TypeTime)
+	}
+	if value, ok := du.mutation.CreatedAt(); ok {
+		_spec.SetField(device.FieldCreatedAt, field.TypeTime, value)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, du.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{device.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	du.mutation.done = true
+	return n, nil
+}
+
+// DeviceUpdateOne is the builder for updating a single Device entity.
+type DeviceUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *DeviceMutation
+}
+
+// SetName sets the "name" field.
+func (duo *DeviceUpdateOne) SetName(s string) *DeviceUpdateOne {
+	duo.mutation.SetName(s)
+	return duo
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillableName(s *string) *DeviceUpdateOne {
+	if s != nil {
+		duo.SetName(*s)
+	}
+	return duo
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (duo *DeviceUpdateOne) SetTokenHash(s string) *DeviceUpdateOne {
+	duo.mutation.SetTokenHash(s)
+	return duo
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillableTokenHash(s *string) *DeviceUpdateOne {
+	if s != nil {
+		duo.SetTokenHash(*s)
+	}
+	return duo
+}
+
+// ClearTokenHash clears the value of the "token_hash" field.
+func (duo *DeviceUpdateOne) ClearTokenHash() *DeviceUpdateOne {
+	duo.mutation.ClearTokenHash()
+	return duo
+}
+
+// SetPairingCodeHash sets the "pairing_code_hash" field.
+func (duo *DeviceUpdateOne) SetPairingCodeHash(s string) *DeviceUpdateOne {
+	duo.mutation.SetPairingCodeHash(s)
+	return duo
+}
+
+// SetNillablePairingCodeHash sets the "pairing_code_hash" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillablePairingCodeHash(s *string) *DeviceUpdateOne {
+	if s != nil {
+		duo.SetPairingCodeHash(*s)
+	}
+	return duo
+}
+
+// ClearPairingCodeHash clears the value of the "pairing_code_hash" field.
+func (duo *DeviceUpdateOne) ClearPairingCodeHash() *DeviceUpdateOne {
+	duo.mutation.ClearPairingCodeHash()
+	return duo
+}
+
+// SetPairingExpiresAt sets the "pairing_expires_at" field.
+func (duo *DeviceUpdateOne) SetPairingExpiresAt(t time.Time) *DeviceUpdateOne {
+	duo.mutation.SetPairingExpiresAt(t)
+	return duo
+}
+
+// SetNillablePairingExpiresAt sets the "pairing_expires_at" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillablePairingExpiresAt(t *time.Time) *DeviceUpdateOne {
+	if t != nil {
+		duo.SetPairingExpiresAt(*t)
+	}
+	return duo
+}
+
+// ClearPairingExpiresAt clears the value of the "pairing_expires_at" field.
+func (duo *DeviceUpdateOne) ClearPairingExpiresAt() *DeviceUpdateOne {
+	duo.mutation.ClearPairingExpiresAt()
+	return duo
+}
+
+// SetPairedAt sets the "paired_at" field.
+func (duo *DeviceUpdateOne) SetPairedAt(t time.Time) *DeviceUpdateOne {
+	duo.mutation.SetPairedAt(t)
+	return duo
+}
+
+// SetNillablePairedAt sets the "paired_at" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillablePairedAt(t *time.Time) *DeviceUpdateOne {
+	if t != nil {
+		duo.SetPairedAt(*t)
+	}
+	return duo
+}
+
+// ClearPairedAt clears the value of the "paired_at" field.
+func (duo *DeviceUpdateOne) ClearPairedAt() *DeviceUpdateOne {
+	duo.mutation.ClearPairedAt()
+	return duo
+}
+
+// SetPrivacy sets the "privacy" field.
+func (duo *DeviceUpdateOne) SetPrivacy(s string) *DeviceUpdateOne {
+	duo.mutation.SetPrivacy(s)
+	return duo
+}
+
+// SetNillablePrivacy sets the "privacy" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillablePrivacy(s *string) *DeviceUpdateOne {
+	if s != nil {
+		duo.SetPrivacy(*s)
+	}
+	return duo
+}
+
+// ClearPrivacy clears the value of the "privacy" field.
+func (duo *DeviceUpdateOne) ClearPrivacy() *DeviceUpdateOne {
+	duo.mutation.ClearPrivacy()
+	return duo
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (duo *DeviceUpdateOne) SetRevokedAt(t time.Time) *DeviceUpdateOne {
+	duo.mutation.SetRevokedAt(t)
+	return duo
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillableRevokedAt(t *time.Time) *DeviceUpdateOne {
+	if t != nil {
+		duo.SetRevokedAt(*t)
+	}
+	return duo
+}
+
+// ClearRevokedAt clears the value of the "revoked_at" field.
+func (duo *DeviceUpdateOne) ClearRevokedAt() *DeviceUpdateOne {
+	duo.mutation.ClearRevokedAt()
+	return duo
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (duo *DeviceUpdateOne) SetCreatedAt(t time.Time) *DeviceUpdateOne {
+	duo.mutation.SetCreatedAt(t)
+	return duo
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillableCreatedAt(t *time.Time) *DeviceUpdateOne {
+	if t != nil {
+		duo.SetCreatedAt(*t)
+	}
+	return duo
+}
+
+// Mutation returns the DeviceMutation object of the builder.
+func (duo *DeviceUpdateOne) Mutation() *DeviceMutation {
+	return duo.mutation
+}
+
+// Where appends a list predicates to the DeviceUpdate builder.
+func (duo *DeviceUpdateOne) Where(ps ...predicate.Device) *DeviceUpdateOne {
+	duo.mutation.Where(ps...)
+	return duo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (duo *DeviceUpdateOne) Select(field string, fields ...string) *DeviceUpdateOne {
+	duo.fields = append([]string{field}, fields...)
+	return duo
+}
+
+// Save executes the query and returns the updated Device entity.
+func (duo *DeviceUpdateOne) Save(ctx context.Context) (*Device, error) {
+	return withHooks(ctx, duo.sqlSave, duo.mutation, duo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (duo *DeviceUpdateOne) SaveX(ctx context.Context) *Device {
+	node, err := duo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (duo *DeviceUpdateOne) Exec(ctx context.Context) error {
+	_, err := duo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (duo *DeviceUpdateOne) ExecX(ctx context.Context) {
+	if err := duo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (duo *DeviceUpdateOne) check() error {
+	if v, ok := duo.mutation.Name(); ok {
+		if err := device.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "Device.name": %w`, err)}
+		}
+	}
+	if v, ok := duo.mutation.TokenHash(); ok {
+		if err := device.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "Device.token_hash": %w`, err)}
+		}
+	}
+	if v, ok := duo.mutation.PairingCodeHash(); ok {
+		if err := device.PairingCodeHashValidator(v); err != nil {
+			return &ValidationError{Name: "pairing_code_hash", err: fmt.Errorf(`ent: validator failed for field "Device.pairing_code_hash": %w`, err)}
+		}
+	}
+	if v, ok := duo.mutation.Privacy(); ok {
+		if err := device.PrivacyValidator(v); err != nil {
+			return &ValidationError{Name: "privacy", err: fmt.Errorf(`ent: validator failed for field "Device.privacy": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (duo *DeviceUpdateOne) sqlSave(ctx context.Context) (_node *Device, err error) {
+	if err := duo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(device.Table, device.Columns, sqlgraph.NewFieldSpec(device.FieldID, field.TypeInt))
+	id, ok := duo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Device.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := duo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, device.FieldID)
+		for _, f := range fields {
+			if !device.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != device.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := duo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := duo.mutation.Name(); ok {
+		_spec.SetField(device.FieldName, field.TypeString, value)
+	}
+	if value, ok := duo.mutation.TokenHash(); ok {
+		_spec.SetField(device.FieldTokenHash, field.TypeString, value)
+	}
+	if duo.mutation.TokenHashCleared() {
+		_spec.ClearField(device.FieldTokenHash, field.TypeString)
+	}
+	if value, ok := duo.mutation.PairingCodeHash(); ok {
+		_spec.SetField(device.FieldPairingCodeHash, field.TypeString, value)
+	}
+	if duo.mutation.PairingCodeHashCleared() {
+		_spec.ClearField(device.FieldPairingCodeHash, field.TypeString)
+	}
+	if value, ok := duo.mutation.PairingExpiresAt(); ok {
+		_spec.SetField(device.FieldPairingExpiresAt, field.TypeTime, value)
+	}
+	if duo.mutation.PairingExpiresAtCleared() {
+		_spec.ClearField(device.FieldPairingExpiresAt, field.TypeTime)
+	}
+	if value, ok := duo.mutation.PairedAt(); ok {
+		_spec.SetField(device.FieldPairedAt, field.TypeTime, value)
+	}
+	if duo.mutation.PairedAtCleared() {
+		_spec.ClearField(device.FieldPairedAt, field.TypeTime)
+	}
+	if value, ok := duo.mutation.Privacy(); ok {
+		_spec.SetField(device.FieldPrivacy, field.TypeString, value)
+	}
+	if duo.mutation.PrivacyCleared() {
+		_spec.ClearField(device.FieldPrivacy, field.TypeString)
+	}
+	if value, ok := duo.mutation.RevokedAt(); ok {
+		_spec.SetField(device.FieldRevokedAt, field.TypeTime, value)
+	}
+	if duo.mutation.RevokedAtCleared() {
+		_spec.ClearField(device.FieldRevokedAt, field.TypeTime)
+	}
+	if value, ok := duo.mutation.CreatedAt(); ok {
+		_spec.SetField(device.FieldCreatedAt, field.TypeTime, value)
+	}
+	_node = &Device{config: duo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, duo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{device.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	duo.mutation.done = true
+	return _node, nil
+}
@@ -8,9 +8,12 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"via/internal/ent/auditentry"
+	"via/internal/ent/device"
 	"via/internal/ent/guide"
 	"via/internal/ent/guidehistory"
 	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
@@ -75,9 +78,12 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			guide.Table:        guide.ValidColumn,
-			guidehistory.Table: guidehistory.ValidColumn,
-			operator.Table:     operator.ValidColumn,
+			auditentry.Table:       auditentry.ValidColumn,
+			device.Table:           device.ValidColumn,
+			guide.Table:            guide.ValidColumn,
+			guidehistory.Table:     guidehistory.ValidColumn,
+			operator.Table:         operator.ValidColumn,
+			operatoridentity.Table: operatoridentity.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
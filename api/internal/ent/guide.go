@@ -3,11 +3,13 @@
 package ent
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 	"via/internal/ent/guide"
 	"via/internal/ent/operator"
+	"via/internal/model"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
@@ -22,12 +24,20 @@ type Guide struct {
 	ViaGuideID string `json:"via_guide_id,omitempty"`
 	// Recipient holds the value of the "recipient" field.
 	Recipient string `json:"recipient,omitempty"`
+	// RecipientNormalized holds the value of the "recipient_normalized" field.
+	RecipientNormalized string `json:"recipient_normalized,omitempty"`
 	// Status holds the value of the "status" field.
 	Status string `json:"status,omitempty"`
+	// StatusReason holds the value of the "status_reason" field.
+	StatusReason string `json:"status_reason,omitempty"`
 	// Payment holds the value of the "payment" field.
 	Payment string `json:"payment,omitempty"`
 	// OperatorID holds the value of the "operator_id" field.
 	OperatorID int `json:"operator_id,omitempty"`
+	// ViaSnapshot holds the value of the "via_snapshot" field.
+	ViaSnapshot model.ViaGuide `json:"via_snapshot,omitempty"`
+	// ViaSyncedAt holds the value of the "via_synced_at" field.
+	ViaSyncedAt *time.Time `json:"via_synced_at,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// UpdatedAt holds the value of the "updated_at" field.
@@ -74,11 +84,13 @@ func (*Guide) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
+		case guide.FieldViaSnapshot:
+			values[i] = new([]byte)
 		case guide.FieldID, guide.FieldOperatorID:
 			values[i] = new(sql.NullInt64)
-		case guide.FieldViaGuideID, guide.FieldRecipient, guide.FieldStatus, guide.FieldPayment:
+		case guide.FieldViaGuideID, guide.FieldRecipient, guide.FieldRecipientNormalized, guide.FieldStatus, guide.FieldStatusReason, guide.FieldPayment:
 			values[i] = new(sql.NullString)
-		case guide.FieldCreatedAt, guide.FieldUpdatedAt:
+		case guide.FieldViaSyncedAt, guide.FieldCreatedAt, guide.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -113,12 +125,24 @@ func (gu *Guide) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				gu.Recipient = value.String
 			}
+		case guide.FieldRecipientNormalized:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field recipient_normalized", values[i])
+			} else if value.Valid {
+				gu.RecipientNormalized = value.String
+			}
 		case guide.FieldStatus:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field status", values[i])
 			} else if value.Valid {
 				gu.Status = value.String
 			}
+		case guide.FieldStatusReason:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field status_reason", values[i])
+			} else if value.Valid {
+				gu.StatusReason = value.String
+			}
 		case guide.FieldPayment:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field payment", values[i])
@@ -131,6 +155,21 @@ func (gu *Guide) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				gu.OperatorID = int(value.Int64)
 			}
+		case guide.FieldViaSnapshot:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field via_snapshot", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &gu.ViaSnapshot); err != nil {
+					return fmt.Errorf("unmarshal field via_snapshot: %w", err)
+				}
+			}
+		case guide.FieldViaSyncedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field via_synced_at", values[i])
+			} else if value.Valid {
+				gu.ViaSyncedAt = new(time.Time)
+				*gu.ViaSyncedAt = value.Time
+			}
 		case guide.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -195,15 +234,29 @@ func (gu *Guide) String() string {
 	builder.WriteString("recipient=")
 	builder.WriteString(gu.Recipient)
 	builder.WriteString(", ")
+	builder.WriteString("recipient_normalized=")
+	builder.WriteString(gu.RecipientNormalized)
+	builder.WriteString(", ")
 	builder.WriteString("status=")
 	builder.WriteString(gu.Status)
 	builder.WriteString(", ")
+	builder.WriteString("status_reason=")
+	builder.WriteString(gu.StatusReason)
+	builder.WriteString(", ")
 	builder.WriteString("payment=")
 	builder.WriteString(gu.Payment)
 	builder.WriteString(", ")
 	builder.WriteString("operator_id=")
 	builder.WriteString(fmt.Sprintf("%v", gu.OperatorID))
 	builder.WriteString(", ")
+	builder.WriteString("via_snapshot=")
+	builder.WriteString(fmt.Sprintf("%v", gu.ViaSnapshot))
+	builder.WriteString(", ")
+	if v := gu.ViaSyncedAt; v != nil {
+		builder.WriteString("via_synced_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(gu.CreatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
@@ -18,12 +18,20 @@ const (
 	FieldViaGuideID = "via_guide_id"
 	// FieldRecipient holds the string denoting the recipient field in the database.
 	FieldRecipient = "recipient"
+	// FieldRecipientNormalized holds the string denoting the recipient_normalized field in the database.
+	FieldRecipientNormalized = "recipient_normalized"
 	// FieldStatus holds the string denoting the status field in the database.
 	FieldStatus = "status"
+	// FieldStatusReason holds the string denoting the status_reason field in the database.
+	FieldStatusReason = "status_reason"
 	// FieldPayment holds the string denoting the payment field in the database.
 	FieldPayment = "payment"
 	// FieldOperatorID holds the string denoting the operator_id field in the database.
 	FieldOperatorID = "operator_id"
+	// FieldViaSnapshot holds the string denoting the via_snapshot field in the database.
+	FieldViaSnapshot = "via_snapshot"
+	// FieldViaSyncedAt holds the string denoting the via_synced_at field in the database.
+	FieldViaSyncedAt = "via_synced_at"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
 	FieldCreatedAt = "created_at"
 	// FieldUpdatedAt holds the string denoting the updated_at field in the database.
@@ -55,9 +63,13 @@ var Columns = []string{
 	FieldID,
 	FieldViaGuideID,
 	FieldRecipient,
+	FieldRecipientNormalized,
 	FieldStatus,
+	FieldStatusReason,
 	FieldPayment,
 	FieldOperatorID,
+	FieldViaSnapshot,
+	FieldViaSyncedAt,
 	FieldCreatedAt,
 	FieldUpdatedAt,
 }
@@ -77,8 +89,12 @@ var (
 	ViaGuideIDValidator func(string) error
 	// RecipientValidator is a validator for the "recipient" field. It is called by the builders before save.
 	RecipientValidator func(string) error
+	// RecipientNormalizedValidator is a validator for the "recipient_normalized" field. It is called by the builders before save.
+	RecipientNormalizedValidator func(string) error
 	// StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	StatusValidator func(string) error
+	// StatusReasonValidator is a validator for the "status_reason" field. It is called by the builders before save.
+	StatusReasonValidator func(string) error
 	// PaymentValidator is a validator for the "payment" field. It is called by the builders before save.
 	PaymentValidator func(string) error
 	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
@@ -107,11 +123,21 @@ func ByRecipient(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldRecipient, opts...).ToFunc()
 }
 
+// ByRecipientNormalized orders the results by the recipient_normalized field.
+func ByRecipientNormalized(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRecipientNormalized, opts...).ToFunc()
+}
+
 // ByStatus orders the results by the status field.
 func ByStatus(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldStatus, opts...).ToFunc()
 }
 
+// ByStatusReason orders the results by the status_reason field.
+func ByStatusReason(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldStatusReason, opts...).ToFunc()
+}
+
 // ByPayment orders the results by the payment field.
 func ByPayment(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldPayment, opts...).ToFunc()
@@ -122,6 +148,11 @@ func ByOperatorID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldOperatorID, opts...).ToFunc()
 }
 
+// ByViaSyncedAt orders the results by the via_synced_at field.
+func ByViaSyncedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldViaSyncedAt, opts...).ToFunc()
+}
+
 // ByCreatedAt orders the results by the created_at field.
 func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
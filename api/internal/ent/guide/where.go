@@ -65,11 +65,21 @@ func Recipient(v string) predicate.Guide {
 	return predicate.Guide(sql.FieldEQ(FieldRecipient, v))
 }
 
+// RecipientNormalized applies equality check predicate on the "recipient_normalized" field. It's identical to RecipientNormalizedEQ.
+func RecipientNormalized(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldEQ(FieldRecipientNormalized, v))
+}
+
 // Status applies equality check predicate on the "status" field. It's identical to StatusEQ.
 func Status(v string) predicate.Guide {
 	return predicate.Guide(sql.FieldEQ(FieldStatus, v))
 }
 
+// StatusReason applies equality check predicate on the "status_reason" field. It's identical to StatusReasonEQ.
+func StatusReason(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldEQ(FieldStatusReason, v))
+}
+
 // Payment applies equality check predicate on the "payment" field. It's identical to PaymentEQ.
 func Payment(v string) predicate.Guide {
 	return predicate.Guide(sql.FieldEQ(FieldPayment, v))
@@ -80,6 +90,11 @@ func OperatorID(v int) predicate.Guide {
 	return predicate.Guide(sql.FieldEQ(FieldOperatorID, v))
 }
 
+// ViaSyncedAt applies equality check predicate on the "via_synced_at" field. It's identical to ViaSyncedAtEQ.
+func ViaSyncedAt(v time.Time) predicate.Guide {
+	return predicate.Guide(sql.FieldEQ(FieldViaSyncedAt, v))
+}
+
 // CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
 func CreatedAt(v time.Time) predicate.Guide {
 	return predicate.Guide(sql.FieldEQ(FieldCreatedAt, v))
@@ -220,6 +235,71 @@ func RecipientContainsFold(v string) predicate.Guide {
 	return predicate.Guide(sql.FieldContainsFold(FieldRecipient, v))
 }
 
+// RecipientNormalizedEQ applies the EQ predicate on the "recipient_normalized" field.
+func RecipientNormalizedEQ(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldEQ(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedNEQ applies the NEQ predicate on the "recipient_normalized" field.
+func RecipientNormalizedNEQ(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldNEQ(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedIn applies the In predicate on the "recipient_normalized" field.
+func RecipientNormalizedIn(vs ...string) predicate.Guide {
+	return predicate.Guide(sql.FieldIn(FieldRecipientNormalized, vs...))
+}
+
+// RecipientNormalizedNotIn applies the NotIn predicate on the "recipient_normalized" field.
+func RecipientNormalizedNotIn(vs ...string) predicate.Guide {
+	return predicate.Guide(sql.FieldNotIn(FieldRecipientNormalized, vs...))
+}
+
+// RecipientNormalizedGT applies the GT predicate on the "recipient_normalized" field.
+func RecipientNormalizedGT(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldGT(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedGTE applies the GTE predicate on the "recipient_normalized" field.
+func RecipientNormalizedGTE(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldGTE(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedLT applies the LT predicate on the "recipient_normalized" field.
+func RecipientNormalizedLT(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldLT(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedLTE applies the LTE predicate on the "recipient_normalized" field.
+func RecipientNormalizedLTE(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldLTE(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedContains applies the Contains predicate on the "recipient_normalized" field.
+func RecipientNormalizedContains(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldContains(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedHasPrefix applies the HasPrefix predicate on the "recipient_normalized" field.
+func RecipientNormalizedHasPrefix(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldHasPrefix(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedHasSuffix applies the HasSuffix predicate on the "recipient_normalized" field.
+func RecipientNormalizedHasSuffix(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldHasSuffix(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedEqualFold applies the EqualFold predicate on the "recipient_normalized" field.
+func RecipientNormalizedEqualFold(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldEqualFold(FieldRecipientNormalized, v))
+}
+
+// RecipientNormalizedContainsFold applies the ContainsFold predicate on the "recipient_normalized" field.
+func RecipientNormalizedContainsFold(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldContainsFold(FieldRecipientNormalized, v))
+}
+
 // StatusEQ applies the EQ predicate on the "status" field.
 func StatusEQ(v string) predicate.Guide {
 	return predicate.Guide(sql.FieldEQ(FieldStatus, v))
@@ -285,6 +365,81 @@ func StatusContainsFold(v string) predicate.Guide {
 	return predicate.Guide(sql.FieldContainsFold(FieldStatus, v))
 }
 
+// StatusReasonEQ applies the EQ predicate on the "status_reason" field.
+func StatusReasonEQ(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldEQ(FieldStatusReason, v))
+}
+
+// StatusReasonNEQ applies the NEQ predicate on the "status_reason" field.
+func StatusReasonNEQ(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldNEQ(FieldStatusReason, v))
+}
+
+// StatusReasonIn applies the In predicate on the "status_reason" field.
+func StatusReasonIn(vs ...string) predicate.Guide {
+	return predicate.Guide(sql.FieldIn(FieldStatusReason, vs...))
+}
+
+// StatusReasonNotIn applies the NotIn predicate on the "status_reason" field.
+func StatusReasonNotIn(vs ...string) predicate.Guide {
+	return predicate.Guide(sql.FieldNotIn(FieldStatusReason, vs...))
+}
+
+// StatusReasonGT applies the GT predicate on the "status_reason" field.
+func StatusReasonGT(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldGT(FieldStatusReason, v))
+}
+
+// StatusReasonGTE applies the GTE predicate on the "status_reason" field.
+func StatusReasonGTE(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldGTE(FieldStatusReason, v))
+}
+
+// StatusReasonLT applies the LT predicate on the "status_reason" field.
+func StatusReasonLT(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldLT(FieldStatusReason, v))
+}
+
+// StatusReasonLTE applies the LTE predicate on the "status_reason" field.
+func StatusReasonLTE(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldLTE(FieldStatusReason, v))
+}
+
+// StatusReasonContains applies the Contains predicate on the "status_reason" field.
+func StatusReasonContains(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldContains(FieldStatusReason, v))
+}
+
+// StatusReasonHasPrefix applies the HasPrefix predicate on the "status_reason" field.
+func StatusReasonHasPrefix(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldHasPrefix(FieldStatusReason, v))
+}
+
+// StatusReasonHasSuffix applies the HasSuffix predicate on the "status_reason" field.
+func StatusReasonHasSuffix(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldHasSuffix(FieldStatusReason, v))
+}
+
+// StatusReasonIsNil applies the IsNil predicate on the "status_reason" field.
+func StatusReasonIsNil() predicate.Guide {
+	return predicate.Guide(sql.FieldIsNull(FieldStatusReason))
+}
+
+// StatusReasonNotNil applies the NotNil predicate on the "status_reason" field.
+func StatusReasonNotNil() predicate.Guide {
+	return predicate.Guide(sql.FieldNotNull(FieldStatusReason))
+}
+
+// StatusReasonEqualFold applies the EqualFold predicate on the "status_reason" field.
+func StatusReasonEqualFold(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldEqualFold(FieldStatusReason, v))
+}
+
+// StatusReasonContainsFold applies the ContainsFold predicate on the "status_reason" field.
+func StatusReasonContainsFold(v string) predicate.Guide {
+	return predicate.Guide(sql.FieldContainsFold(FieldStatusReason, v))
+}
+
 // PaymentEQ applies the EQ predicate on the "payment" field.
 func PaymentEQ(v string) predicate.Guide {
 	return predicate.Guide(sql.FieldEQ(FieldPayment, v))
@@ -370,6 +525,66 @@ func OperatorIDNotIn(vs ...int) predicate.Guide {
 	return predicate.Guide(sql.FieldNotIn(FieldOperatorID, vs...))
 }
 
+// ViaSnapshotIsNil applies the IsNil predicate on the "via_snapshot" field.
+func ViaSnapshotIsNil() predicate.Guide {
+	return predicate.Guide(sql.FieldIsNull(FieldViaSnapshot))
+}
+
+// ViaSnapshotNotNil applies the NotNil predicate on the "via_snapshot" field.
+func ViaSnapshotNotNil() predicate.Guide {
+	return predicate.Guide(sql.FieldNotNull(FieldViaSnapshot))
+}
+
+// ViaSyncedAtEQ applies the EQ predicate on the "via_synced_at" field.
+func ViaSyncedAtEQ(v time.Time) predicate.Guide {
+	return predicate.Guide(sql.FieldEQ(FieldViaSyncedAt, v))
+}
+
+// ViaSyncedAtNEQ applies the NEQ predicate on the "via_synced_at" field.
+func ViaSyncedAtNEQ(v time.Time) predicate.Guide {
+	return predicate.Guide(sql.FieldNEQ(FieldViaSyncedAt, v))
+}
+
+// ViaSyncedAtIn applies the In predicate on the "via_synced_at" field.
+func ViaSyncedAtIn(vs ...time.Time) predicate.Guide {
+	return predicate.Guide(sql.FieldIn(FieldViaSyncedAt, vs...))
+}
+
+// ViaSyncedAtNotIn applies the NotIn predicate on the "via_synced_at" field.
+func ViaSyncedAtNotIn(vs ...time.Time) predicate.Guide {
+	return predicate.Guide(sql.FieldNotIn(FieldViaSyncedAt, vs...))
+}
+
+// ViaSyncedAtGT applies the GT predicate on the "via_synced_at" field.
+func ViaSyncedAtGT(v time.Time) predicate.Guide {
+	return predicate.Guide(sql.FieldGT(FieldViaSyncedAt, v))
+}
+
+// ViaSyncedAtGTE applies the GTE predicate on the "via_synced_at" field.
+func ViaSyncedAtGTE(v time.Time) predicate.Guide {
+	return predicate.Guide(sql.FieldGTE(FieldViaSyncedAt, v))
+}
+
+// ViaSyncedAtLT applies the LT predicate on the "via_synced_at" field.
+func ViaSyncedAtLT(v time.Time) predicate.Guide {
+	return predicate.Guide(sql.FieldLT(FieldViaSyncedAt, v))
+}
+
+// ViaSyncedAtLTE applies the LTE predicate on the "via_synced_at" field.
+func ViaSyncedAtLTE(v time.Time) predicate.Guide {
+	return predicate.Guide(sql.FieldLTE(FieldViaSyncedAt, v))
+}
+
+// ViaSyncedAtIsNil applies the IsNil predicate on the "via_synced_at" field.
+func ViaSyncedAtIsNil() predicate.Guide {
+	return predicate.Guide(sql.FieldIsNull(FieldViaSyncedAt))
+}
+
+// ViaSyncedAtNotNil applies the NotNil predicate on the "via_synced_at" field.
+func ViaSyncedAtNotNil() predicate.Guide {
+	return predicate.Guide(sql.FieldNotNull(FieldViaSyncedAt))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Guide {
 	return predicate.Guide(sql.FieldEQ(FieldCreatedAt, v))
@@ -10,6 +10,7 @@ import (
 	"via/internal/ent/guide"
 	"via/internal/ent/guidehistory"
 	"via/internal/ent/operator"
+	"via/internal/model"
 
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
@@ -34,12 +35,32 @@ func (gc *GuideCreate) SetRecipient(s string) *GuideCreate {
 	return gc
 }
 
+// SetRecipientNormalized sets the "recipient_normalized" field.
+func (gc *GuideCreate) SetRecipientNormalized(s string) *GuideCreate {
+	gc.mutation.SetRecipientNormalized(s)
+	return gc
+}
+
 // SetStatus sets the "status" field.
 func (gc *GuideCreate) SetStatus(s string) *GuideCreate {
 	gc.mutation.SetStatus(s)
 	return gc
 }
 
+// SetStatusReason sets the "status_reason" field.
+func (gc *GuideCreate) SetStatusReason(s string) *GuideCreate {
+	gc.mutation.SetStatusReason(s)
+	return gc
+}
+
+// SetNillableStatusReason sets the "status_reason" field if the given value is not nil.
+func (gc *GuideCreate) SetNillableStatusReason(s *string) *GuideCreate {
+	if s != nil {
+		gc.SetStatusReason(*s)
+	}
+	return gc
+}
+
 // SetPayment sets the "payment" field.
 func (gc *GuideCreate) SetPayment(s string) *GuideCreate {
 	gc.mutation.SetPayment(s)
@@ -52,6 +73,34 @@ func (gc *GuideCreate) SetOperatorID(i int) *GuideCreate {
 	return gc
 }
 
+// SetViaSnapshot sets the "via_snapshot" field.
+func (gc *GuideCreate) SetViaSnapshot(mg model.ViaGuide) *GuideCreate {
+	gc.mutation.SetViaSnapshot(mg)
+	return gc
+}
+
+// SetNillableViaSnapshot sets the "via_snapshot" field if the given value is not nil.
+func (gc *GuideCreate) SetNillableViaSnapshot(mg *model.ViaGuide) *GuideCreate {
+	if mg != nil {
+		gc.SetViaSnapshot(*mg)
+	}
+	return gc
+}
+
+// SetViaSyncedAt sets the "via_synced_at" field.
+func (gc *GuideCreate) SetViaSyncedAt(t time.Time) *GuideCreate {
+	gc.mutation.SetViaSyncedAt(t)
+	return gc
+}
+
+// SetNillableViaSyncedAt sets the "via_synced_at" field if the given value is not nil.
+func (gc *GuideCreate) SetNillableViaSyncedAt(t *time.Time) *GuideCreate {
+	if t != nil {
+		gc.SetViaSyncedAt(*t)
+	}
+	return gc
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (gc *GuideCreate) SetCreatedAt(t time.Time) *GuideCreate {
 	gc.mutation.SetCreatedAt(t)
@@ -163,6 +212,14 @@ func (gc *GuideCreate) check() error {
 			return &ValidationError{Name: "recipient", err: fmt.Errorf(`ent: validator failed for field "Guide.recipient": %w`, err)}
 		}
 	}
+	if _, ok := gc.mutation.RecipientNormalized(); !ok {
+		return &ValidationError{Name: "recipient_normalized", err: errors.New(`ent: missing required field "Guide.recipient_normalized"`)}
+	}
+	if v, ok := gc.mutation.RecipientNormalized(); ok {
+		if err := guide.RecipientNormalizedValidator(v); err != nil {
+			return &ValidationError{Name: "recipient_normalized", err: fmt.Errorf(`ent: validator failed for field "Guide.recipient_normalized": %w`, err)}
+		}
+	}
 	if _, ok := gc.mutation.Status(); !ok {
 		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "Guide.status"`)}
 	}
@@ -171,6 +228,11 @@ func (gc *GuideCreate) check() error {
 			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Guide.status": %w`, err)}
 		}
 	}
+	if v, ok := gc.mutation.StatusReason(); ok {
+		if err := guide.StatusReasonValidator(v); err != nil {
+			return &ValidationError{Name: "status_reason", err: fmt.Errorf(`ent: validator failed for field "Guide.status_reason": %w`, err)}
+		}
+	}
 	if _, ok := gc.mutation.Payment(); !ok {
 		return &ValidationError{Name: "payment", err: errors.New(`ent: missing required field "Guide.payment"`)}
 	}
@@ -182,12 +244,6 @@ func (gc *GuideCreate) check() error {
 	if _, ok := gc.mutation.OperatorID(); !ok {
 		return &ValidationError{Name: "operator_id", err: errors.New(`ent: missing required field "Guide.operator_id"`)}
 	}
-	if _, ok := gc.mutation.CreatedAt(); !ok {
-		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Guide.created_at"`)}
-	}
-	if _, ok := gc.mutation.UpdatedAt(); !ok {
-		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "Guide.updated_at"`)}
-	}
 	if len(gc.mutation.OperatorIDs()) == 0 {
 		return &ValidationError{Name: "operator", err: errors.New(`ent: missing required edge "Guide.operator"`)}
 	}
@@ -225,14 +281,30 @@ func (gc *GuideCreate) createSpec() (*Guide, *sqlgraph.CreateSpec) {
 		_spec.SetField(guide.FieldRecipient, field.TypeString, value)
 		_node.Recipient = value
 	}
+	if value, ok := gc.mutation.RecipientNormalized(); ok {
+		_spec.SetField(guide.FieldRecipientNormalized, field.TypeString, value)
+		_node.RecipientNormalized = value
+	}
 	if value, ok := gc.mutation.Status(); ok {
 		_spec.SetField(guide.FieldStatus, field.TypeString, value)
 		_node.Status = value
 	}
+	if value, ok := gc.mutation.StatusReason(); ok {
+		_spec.SetField(guide.FieldStatusReason, field.TypeString, value)
+		_node.StatusReason = value
+	}
 	if value, ok := gc.mutation.Payment(); ok {
 		_spec.SetField(guide.FieldPayment, field.TypeString, value)
 		_node.Payment = value
 	}
+	if value, ok := gc.mutation.ViaSnapshot(); ok {
+		_spec.SetField(guide.FieldViaSnapshot, field.TypeJSON, value)
+		_node.ViaSnapshot = value
+	}
+	if value, ok := gc.mutation.ViaSyncedAt(); ok {
+		_spec.SetField(guide.FieldViaSyncedAt, field.TypeTime, value)
+		_node.ViaSyncedAt = &value
+	}
 	if value, ok := gc.mutation.CreatedAt(); ok {
 		_spec.SetField(guide.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
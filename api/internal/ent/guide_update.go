@@ -11,6 +11,7 @@ import (
 	"via/internal/ent/guidehistory"
 	"via/internal/ent/operator"
 	"via/internal/ent/predicate"
+	"via/internal/model"
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
@@ -44,6 +45,26 @@ func (gu *GuideUpdate) SetNillableStatus(s *string) *GuideUpdate {
 	return gu
 }
 
+// SetStatusReason sets the "status_reason" field.
+func (gu *GuideUpdate) SetStatusReason(s string) *GuideUpdate {
+	gu.mutation.SetStatusReason(s)
+	return gu
+}
+
+// SetNillableStatusReason sets the "status_reason" field if the given value is not nil.
+func (gu *GuideUpdate) SetNillableStatusReason(s *string) *GuideUpdate {
+	if s != nil {
+		gu.SetStatusReason(*s)
+	}
+	return gu
+}
+
+// ClearStatusReason clears the value of the "status_reason" field.
+func (gu *GuideUpdate) ClearStatusReason() *GuideUpdate {
+	gu.mutation.ClearStatusReason()
+	return gu
+}
+
 // SetOperatorID sets the "operator_id" field.
 func (gu *GuideUpdate) SetOperatorID(i int) *GuideUpdate {
 	gu.mutation.SetOperatorID(i)
@@ -58,6 +79,46 @@ func (gu *GuideUpdate) SetNillableOperatorID(i *int) *GuideUpdate {
 	return gu
 }
 
+// SetViaSnapshot sets the "via_snapshot" field.
+func (gu *GuideUpdate) SetViaSnapshot(mg model.ViaGuide) *GuideUpdate {
+	gu.mutation.SetViaSnapshot(mg)
+	return gu
+}
+
+// SetNillableViaSnapshot sets the "via_snapshot" field if the given value is not nil.
+func (gu *GuideUpdate) SetNillableViaSnapshot(mg *model.ViaGuide) *GuideUpdate {
+	if mg != nil {
+		gu.SetViaSnapshot(*mg)
+	}
+	return gu
+}
+
+// ClearViaSnapshot clears the value of the "via_snapshot" field.
+func (gu *GuideUpdate) ClearViaSnapshot() *GuideUpdate {
+	gu.mutation.ClearViaSnapshot()
+	return gu
+}
+
+// SetViaSyncedAt sets the "via_synced_at" field.
+func (gu *GuideUpdate) SetViaSyncedAt(t time.Time) *GuideUpdate {
+	gu.mutation.SetViaSyncedAt(t)
+	return gu
+}
+
+// SetNillableViaSyncedAt sets the "via_synced_at" field if the given value is not nil.
+func (gu *GuideUpdate) SetNillableViaSyncedAt(t *time.Time) *GuideUpdate {
+	if t != nil {
+		gu.SetViaSyncedAt(*t)
+	}
+	return gu
+}
+
+// ClearViaSyncedAt clears the value of the "via_synced_at" field.
+func (gu *GuideUpdate) ClearViaSyncedAt() *GuideUpdate {
+	gu.mutation.ClearViaSyncedAt()
+	return gu
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (gu *GuideUpdate) SetCreatedAt(t time.Time) *GuideUpdate {
 	gu.mutation.SetCreatedAt(t)
@@ -173,6 +234,11 @@ func (gu *GuideUpdate) check() error {
 			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Guide.status": %w`, err)}
 		}
 	}
+	if v, ok := gu.mutation.StatusReason(); ok {
+		if err := guide.StatusReasonValidator(v); err != nil {
+			return &ValidationError{Name: "status_reason", err: fmt.Errorf(`ent: validator failed for field "Guide.status_reason": %w`, err)}
+		}
+	}
 	if gu.mutation.OperatorCleared() && len(gu.mutation.OperatorIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "Guide.operator"`)
 	}
@@ -194,6 +260,24 @@ func (gu *GuideUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := gu.mutation.Status(); ok {
 		_spec.SetField(guide.FieldStatus, field.TypeString, value)
 	}
+	if value, ok := gu.mutation.StatusReason(); ok {
+		_spec.SetField(guide.FieldStatusReason, field.TypeString, value)
+	}
+	if gu.mutation.StatusReasonCleared() {
+		_spec.ClearField(guide.FieldStatusReason, field.TypeString)
+	}
+	if value, ok := gu.mutation.ViaSnapshot(); ok {
+		_spec.SetField(guide.FieldViaSnapshot, field.TypeJSON, value)
+	}
+	if gu.mutation.ViaSnapshotCleared() {
+		_spec.ClearField(guide.FieldViaSnapshot, field.TypeJSON)
+	}
+	if value, ok := gu.mutation.ViaSyncedAt(); ok {
+		_spec.SetField(guide.FieldViaSyncedAt, field.TypeTime, value)
+	}
+	if gu.mutation.ViaSyncedAtCleared() {
+		_spec.ClearField(guide.FieldViaSyncedAt, field.TypeTime)
+	}
 	if value, ok := gu.mutation.CreatedAt(); ok {
 		_spec.SetField(guide.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -308,6 +392,26 @@ func (guo *GuideUpdateOne) SetNillableStatus(s *string) *GuideUpdateOne {
 	return guo
 }
 
+// SetStatusReason sets the "status_reason" field.
+func (guo *GuideUpdateOne) SetStatusReason(s string) *GuideUpdateOne {
+	guo.mutation.SetStatusReason(s)
+	return guo
+}
+
+// SetNillableStatusReason sets the "status_reason" field if the given value is not nil.
+func (guo *GuideUpdateOne) SetNillableStatusReason(s *string) *GuideUpdateOne {
+	if s != nil {
+		guo.SetStatusReason(*s)
+	}
+	return guo
+}
+
+// ClearStatusReason clears the value of the "status_reason" field.
+func (guo *GuideUpdateOne) ClearStatusReason() *GuideUpdateOne {
+	guo.mutation.ClearStatusReason()
+	return guo
+}
+
 // SetOperatorID sets the "operator_id" field.
 func (guo *GuideUpdateOne) SetOperatorID(i int) *GuideUpdateOne {
 	guo.mutation.SetOperatorID(i)
@@ -322,6 +426,46 @@ func (guo *GuideUpdateOne) SetNillableOperatorID(i *int) *GuideUpdateOne {
 	return guo
 }
 
+// SetViaSnapshot sets the "via_snapshot" field.
+func (guo *GuideUpdateOne) SetViaSnapshot(mg model.ViaGuide) *GuideUpdateOne {
+	guo.mutation.SetViaSnapshot(mg)
+	return guo
+}
+
+// SetNillableViaSnapshot sets the "via_snapshot" field if the given value is not nil.
+func (guo *GuideUpdateOne) SetNillableViaSnapshot(mg *model.ViaGuide) *GuideUpdateOne {
+	if mg != nil {
+		guo.SetViaSnapshot(*mg)
+	}
+	return guo
+}
+
+// ClearViaSnapshot clears the value of the "via_snapshot" field.
+func (guo *GuideUpdateOne) ClearViaSnapshot() *GuideUpdateOne {
+	guo.mutation.ClearViaSnapshot()
+	return guo
+}
+
+// SetViaSyncedAt sets the "via_synced_at" field.
+func (guo *GuideUpdateOne) SetViaSyncedAt(t time.Time) *GuideUpdateOne {
+	guo.mutation.SetViaSyncedAt(t)
+	return guo
+}
+
+// SetNillableViaSyncedAt sets the "via_synced_at" field if the given value is not nil.
+func (guo *GuideUpdateOne) SetNillableViaSyncedAt(t *time.Time) *GuideUpdateOne {
+	if t != nil {
+		guo.SetViaSyncedAt(*t)
+	}
+	return guo
+}
+
+// ClearViaSyncedAt clears the value of the "via_synced_at" field.
+func (guo *GuideUpdateOne) ClearViaSyncedAt() *GuideUpdateOne {
+	guo.mutation.ClearViaSyncedAt()
+	return guo
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (guo *GuideUpdateOne) SetCreatedAt(t time.Time) *GuideUpdateOne {
 	guo.mutation.SetCreatedAt(t)
@@ -450,6 +594,11 @@ func (guo *GuideUpdateOne) check() error {
 			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Guide.status": %w`, err)}
 		}
 	}
+	if v, ok := guo.mutation.StatusReason(); ok {
+		if err := guide.StatusReasonValidator(v); err != nil {
+			return &ValidationError{Name: "status_reason", err: fmt.Errorf(`ent: validator failed for field "Guide.status_reason": %w`, err)}
+		}
+	}
 	if guo.mutation.OperatorCleared() && len(guo.mutation.OperatorIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "Guide.operator"`)
 	}
@@ -488,6 +637,24 @@ func (guo *GuideUpdateOne) sqlSave(ctx context.Context) (_node *Guide, err error
 	if value, ok := guo.mutation.Status(); ok {
 		_spec.SetField(guide.FieldStatus, field.TypeString, value)
 	}
+	if value, ok := guo.mutation.StatusReason(); ok {
+		_spec.SetField(guide.FieldStatusReason, field.TypeString, value)
+	}
+	if guo.mutation.StatusReasonCleared() {
+		_spec.ClearField(guide.FieldStatusReason, field.TypeString)
+	}
+	if value, ok := guo.mutation.ViaSnapshot(); ok {
+		_spec.SetField(guide.FieldViaSnapshot, field.TypeJSON, value)
+	}
+	if guo.mutation.ViaSnapshotCleared() {
+		_spec.ClearField(guide.FieldViaSnapshot, field.TypeJSON)
+	}
+	if value, ok := guo.mutation.ViaSyncedAt(); ok {
+		_spec.SetField(guide.FieldViaSyncedAt, field.TypeTime, value)
+	}
+	if guo.mutation.ViaSyncedAtCleared() {
+		_spec.ClearField(guide.FieldViaSyncedAt, field.TypeTime)
+	}
 	if value, ok := guo.mutation.CreatedAt(); ok {
 		_spec.SetField(guide.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -23,6 +23,8 @@ type GuideHistory struct {
 	GuideID int `json:"guide_id,omitempty"`
 	// Status holds the value of the "status" field.
 	Status string `json:"status,omitempty"`
+	// Reason holds the value of the "reason" field.
+	Reason string `json:"reason,omitempty"`
 	// OperatorID holds the value of the "operator_id" field.
 	OperatorID int `json:"operator_id,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
@@ -73,7 +75,7 @@ func (*GuideHistory) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case guidehistory.FieldID, guidehistory.FieldGuideID, guidehistory.FieldOperatorID:
 			values[i] = new(sql.NullInt64)
-		case guidehistory.FieldStatus:
+		case guidehistory.FieldStatus, guidehistory.FieldReason:
 			values[i] = new(sql.NullString)
 		case guidehistory.FieldCreatedAt:
 			values[i] = new(sql.NullTime)
@@ -110,6 +112,12 @@ func (gh *GuideHistory) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				gh.Status = value.String
 			}
+		case guidehistory.FieldReason:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field reason", values[i])
+			} else if value.Valid {
+				gh.Reason = value.String
+			}
 		case guidehistory.FieldOperatorID:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
 				return fmt.Errorf("unexpected type %T for field operator_id", values[i])
@@ -174,6 +182,9 @@ func (gh *GuideHistory) String() string {
 	builder.WriteString("status=")
 	builder.WriteString(gh.Status)
 	builder.WriteString(", ")
+	builder.WriteString("reason=")
+	builder.WriteString(gh.Reason)
+	builder.WriteString(", ")
 	builder.WriteString("operator_id=")
 	builder.WriteString(fmt.Sprintf("%v", gh.OperatorID))
 	builder.WriteString(", ")
@@ -65,6 +65,11 @@ func Status(v string) predicate.GuideHistory {
 	return predicate.GuideHistory(sql.FieldEQ(FieldStatus, v))
 }
 
+// Reason applies equality check predicate on the "reason" field. It's identical to ReasonEQ.
+func Reason(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldEQ(FieldReason, v))
+}
+
 // OperatorID applies equality check predicate on the "operator_id" field. It's identical to OperatorIDEQ.
 func OperatorID(v int) predicate.GuideHistory {
 	return predicate.GuideHistory(sql.FieldEQ(FieldOperatorID, v))
@@ -160,6 +165,81 @@ func StatusContainsFold(v string) predicate.GuideHistory {
 	return predicate.GuideHistory(sql.FieldContainsFold(FieldStatus, v))
 }
 
+// ReasonEQ applies the EQ predicate on the "reason" field.
+func ReasonEQ(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldEQ(FieldReason, v))
+}
+
+// ReasonNEQ applies the NEQ predicate on the "reason" field.
+func ReasonNEQ(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldNEQ(FieldReason, v))
+}
+
+// ReasonIn applies the In predicate on the "reason" field.
+func ReasonIn(vs ...string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldIn(FieldReason, vs...))
+}
+
+// ReasonNotIn applies the NotIn predicate on the "reason" field.
+func ReasonNotIn(vs ...string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldNotIn(FieldReason, vs...))
+}
+
+// ReasonGT applies the GT predicate on the "reason" field.
+func ReasonGT(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldGT(FieldReason, v))
+}
+
+// ReasonGTE applies the GTE predicate on the "reason" field.
+func ReasonGTE(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldGTE(FieldReason, v))
+}
+
+// ReasonLT applies the LT predicate on the "reason" field.
+func ReasonLT(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldLT(FieldReason, v))
+}
+
+// ReasonLTE applies the LTE predicate on the "reason" field.
+func ReasonLTE(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldLTE(FieldReason, v))
+}
+
+// ReasonContains applies the Contains predicate on the "reason" field.
+func ReasonContains(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldContains(FieldReason, v))
+}
+
+// ReasonHasPrefix applies the HasPrefix predicate on the "reason" field.
+func ReasonHasPrefix(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldHasPrefix(FieldReason, v))
+}
+
+// ReasonHasSuffix applies the HasSuffix predicate on the "reason" field.
+func ReasonHasSuffix(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldHasSuffix(FieldReason, v))
+}
+
+// ReasonIsNil applies the IsNil predicate on the "reason" field.
+func ReasonIsNil() predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldIsNull(FieldReason))
+}
+
+// ReasonNotNil applies the NotNil predicate on the "reason" field.
+func ReasonNotNil() predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldNotNull(FieldReason))
+}
+
+// ReasonEqualFold applies the EqualFold predicate on the "reason" field.
+func ReasonEqualFold(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldEqualFold(FieldReason, v))
+}
+
+// ReasonContainsFold applies the ContainsFold predicate on the "reason" field.
+func ReasonContainsFold(v string) predicate.GuideHistory {
+	return predicate.GuideHistory(sql.FieldContainsFold(FieldReason, v))
+}
+
 // OperatorIDEQ applies the EQ predicate on the "operator_id" field.
 func OperatorIDEQ(v int) predicate.GuideHistory {
 	return predicate.GuideHistory(sql.FieldEQ(FieldOperatorID, v))
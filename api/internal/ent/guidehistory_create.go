@@ -34,6 +34,20 @@ func (ghc *GuideHistoryCreate) SetStatus(s string) *GuideHistoryCreate {
 	return ghc
 }
 
+// SetReason sets the "reason" field.
+func (ghc *GuideHistoryCreate) SetReason(s string) *GuideHistoryCreate {
+	ghc.mutation.SetReason(s)
+	return ghc
+}
+
+// SetNillableReason sets the "reason" field if the given value is not nil.
+func (ghc *GuideHistoryCreate) SetNillableReason(s *string) *GuideHistoryCreate {
+	if s != nil {
+		ghc.SetReason(*s)
+	}
+	return ghc
+}
+
 // SetOperatorID sets the "operator_id" field.
 func (ghc *GuideHistoryCreate) SetOperatorID(i int) *GuideHistoryCreate {
 	ghc.mutation.SetOperatorID(i)
@@ -118,6 +132,11 @@ func (ghc *GuideHistoryCreate) check() error {
 			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "GuideHistory.status": %w`, err)}
 		}
 	}
+	if v, ok := ghc.mutation.Reason(); ok {
+		if err := guidehistory.ReasonValidator(v); err != nil {
+			return &ValidationError{Name: "reason", err: fmt.Errorf(`ent: validator failed for field "GuideHistory.reason": %w`, err)}
+		}
+	}
 	if _, ok := ghc.mutation.OperatorID(); !ok {
 		return &ValidationError{Name: "operator_id", err: errors.New(`ent: missing required field "GuideHistory.operator_id"`)}
 	}
@@ -126,9 +145,6 @@ func (ghc *GuideHistoryCreate) check() error {
 			return &ValidationError{Name: "operator_id", err: fmt.Errorf(`ent: validator failed for field "GuideHistory.operator_id": %w`, err)}
 		}
 	}
-	if _, ok := ghc.mutation.CreatedAt(); !ok {
-		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "GuideHistory.created_at"`)}
-	}
 	if len(ghc.mutation.GuideIDs()) == 0 {
 		return &ValidationError{Name: "guide", err: errors.New(`ent: missing required edge "GuideHistory.guide"`)}
 	}
@@ -165,6 +181,10 @@ func (ghc *GuideHistoryCreate) createSpec() (*GuideHistory, *sqlgraph.CreateSpec
 		_spec.SetField(guidehistory.FieldStatus, field.TypeString, value)
 		_node.Status = value
 	}
+	if value, ok := ghc.mutation.Reason(); ok {
+		_spec.SetField(guidehistory.FieldReason, field.TypeString, value)
+		_node.Reason = value
+	}
 	if value, ok := ghc.mutation.CreatedAt(); ok {
 		_spec.SetField(guidehistory.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
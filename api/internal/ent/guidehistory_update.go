@@ -97,6 +97,9 @@ func (ghu *GuideHistoryUpdate) sqlSave(ctx context.Context) (n int, err error) {
 			}
 		}
 	}
+	if ghu.mutation.ReasonCleared() {
+		_spec.ClearField(guidehistory.FieldReason, field.TypeString)
+	}
 	if value, ok := ghu.mutation.CreatedAt(); ok {
 		_spec.SetField(guidehistory.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -219,6 +222,9 @@ func (ghuo *GuideHistoryUpdateOne) sqlSave(ctx context.Context) (_node *GuideHis
 			}
 		}
 	}
+	if ghuo.mutation.ReasonCleared() {
+		_spec.ClearField(guidehistory.FieldReason, field.TypeString)
+	}
 	if value, ok := ghuo.mutation.CreatedAt(); ok {
 		_spec.SetField(guidehistory.FieldCreatedAt, field.TypeTime, value)
 	}
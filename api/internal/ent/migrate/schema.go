@@ -3,20 +3,100 @@
 package migrate
 
 import (
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/dialect/sql/schema"
 	"entgo.io/ent/schema/field"
 )
 
 var (
+	// AuditEntriesColumns holds the columns for the "audit_entries" table.
+	AuditEntriesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "actor", Type: field.TypeString, Size: 100},
+		{Name: "action", Type: field.TypeString, Size: 50},
+		{Name: "target_type", Type: field.TypeString, Nullable: true, Size: 50},
+		{Name: "target_id", Type: field.TypeString, Nullable: true, Size: 100},
+		{Name: "request_id", Type: field.TypeString, Nullable: true, Size: 100},
+		{Name: "ip", Type: field.TypeString, Nullable: true, Size: 64},
+		{Name: "before", Type: field.TypeString, Nullable: true, Size: 2147483647},
+		{Name: "after", Type: field.TypeString, Nullable: true, Size: 2147483647},
+		{Name: "prev_hash", Type: field.TypeString, Nullable: true, Size: 64},
+		{Name: "hash", Type: field.TypeString, Size: 64},
+		{Name: "created_at", Type: field.TypeTime, Default: schema.Expr("CURRENT_TIMESTAMP")},
+	}
+	// AuditEntriesTable holds the schema information for the "audit_entries" table.
+	AuditEntriesTable = &schema.Table{
+		Name:       "audit_entries",
+		Columns:    AuditEntriesColumns,
+		PrimaryKey: []*schema.Column{AuditEntriesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "auditentry_actor",
+				Unique:  false,
+				Columns: []*schema.Column{AuditEntriesColumns[1]},
+			},
+			{
+				Name:    "auditentry_action",
+				Unique:  false,
+				Columns: []*schema.Column{AuditEntriesColumns[2]},
+			},
+			{
+				Name:    "auditentry_target_type_target_id",
+				Unique:  false,
+				Columns: []*schema.Column{AuditEntriesColumns[3], AuditEntriesColumns[4]},
+			},
+			{
+				Name:    "auditentry_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{AuditEntriesColumns[11]},
+			},
+			{
+				Name:    "auditentry_hash",
+				Unique:  true,
+				Columns: []*schema.Column{AuditEntriesColumns[10]},
+			},
+		},
+	}
+	// DevicesColumns holds the columns for the "devices" table.
+	DevicesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "name", Type: field.TypeString, Size: 200},
+		{Name: "kind", Type: field.TypeString, Size: 20},
+		{Name: "branch", Type: field.TypeString, Size: 20},
+		{Name: "token_hash", Type: field.TypeString, Nullable: true, Size: 64},
+		{Name: "pairing_code_hash", Type: field.TypeString, Nullable: true, Size: 64},
+		{Name: "pairing_expires_at", Type: field.TypeTime, Nullable: true},
+		{Name: "paired_at", Type: field.TypeTime, Nullable: true},
+		{Name: "privacy", Type: field.TypeString, Nullable: true, Size: 20},
+		{Name: "revoked_at", Type: field.TypeTime, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime, Default: schema.Expr("CURRENT_TIMESTAMP")},
+	}
+	// DevicesTable holds the schema information for the "devices" table.
+	DevicesTable = &schema.Table{
+		Name:       "devices",
+		Columns:    DevicesColumns,
+		PrimaryKey: []*schema.Column{DevicesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "device_pairing_code_hash",
+				Unique:  true,
+				Columns: []*schema.Column{DevicesColumns[5]},
+			},
+		},
+	}
 	// GuidesColumns holds the columns for the "guides" table.
 	GuidesColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "via_guide_id", Type: field.TypeString, Size: 12},
 		{Name: "recipient", Type: field.TypeString, Size: 100},
+		{Name: "recipient_normalized", Type: field.TypeString, Size: 100},
 		{Name: "status", Type: field.TypeString, Size: 30},
+		{Name: "status_reason", Type: field.TypeString, Nullable: true, Size: 100},
 		{Name: "payment", Type: field.TypeString, Size: 1},
-		{Name: "created_at", Type: field.TypeTime},
-		{Name: "updated_at", Type: field.TypeTime},
+		{Name: "via_snapshot", Type: field.TypeJSON, Nullable: true},
+		{Name: "via_synced_at", Type: field.TypeTime, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime, Default: schema.Expr("CURRENT_TIMESTAMP")},
+		{Name: "updated_at", Type: field.TypeTime, Default: schema.Expr("CURRENT_TIMESTAMP")},
 		{Name: "operator_id", Type: field.TypeInt},
 	}
 	// GuidesTable holds the schema information for the "guides" table.
@@ -27,17 +107,47 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "guides_operators_guides",
-				Columns:    []*schema.Column{GuidesColumns[7]},
+				Columns:    []*schema.Column{GuidesColumns[11]},
 				RefColumns: []*schema.Column{OperatorsColumns[0]},
 				OnDelete:   schema.NoAction,
 			},
 		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "guide_status_updated_at",
+				Unique:  false,
+				Columns: []*schema.Column{GuidesColumns[4], GuidesColumns[10]},
+			},
+			{
+				Name:    "guide_recipient_normalized",
+				Unique:  false,
+				Columns: []*schema.Column{GuidesColumns[3]},
+				Annotation: &entsql.IndexAnnotation{
+					OpClass: "gin_trgm_ops",
+					Type:    "GIN",
+				},
+			},
+			{
+				Name:    "guide_via_guide_id",
+				Unique:  false,
+				Columns: []*schema.Column{GuidesColumns[1]},
+				Annotation: &entsql.IndexAnnotation{
+					OpClass: "varchar_pattern_ops",
+				},
+			},
+			{
+				Name:    "guide_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{GuidesColumns[9]},
+			},
+		},
 	}
 	// GuideHistoriesColumns holds the columns for the "guide_histories" table.
 	GuideHistoriesColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "status", Type: field.TypeString, Size: 30},
-		{Name: "created_at", Type: field.TypeTime},
+		{Name: "reason", Type: field.TypeString, Nullable: true, Size: 100},
+		{Name: "created_at", Type: field.TypeTime, Default: schema.Expr("CURRENT_TIMESTAMP")},
 		{Name: "guide_id", Type: field.TypeInt},
 		{Name: "operator_id", Type: field.TypeInt},
 	}
@@ -49,13 +159,13 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "guide_histories_guides_history",
-				Columns:    []*schema.Column{GuideHistoriesColumns[3]},
+				Columns:    []*schema.Column{GuideHistoriesColumns[4]},
 				RefColumns: []*schema.Column{GuidesColumns[0]},
 				OnDelete:   schema.NoAction,
 			},
 			{
 				Symbol:     "guide_histories_operators_guide_history",
-				Columns:    []*schema.Column{GuideHistoriesColumns[4]},
+				Columns:    []*schema.Column{GuideHistoriesColumns[5]},
 				RefColumns: []*schema.Column{OperatorsColumns[0]},
 				OnDelete:   schema.NoAction,
 			},
@@ -67,8 +177,12 @@ var (
 		{Name: "account", Type: field.TypeString, Unique: true, Size: 200},
 		{Name: "name", Type: field.TypeString, Size: 200},
 		{Name: "enabled", Type: field.TypeBool, Default: false},
-		{Name: "created_at", Type: field.TypeTime},
-		{Name: "updated_at", Type: field.TypeTime},
+		{Name: "role", Type: field.TypeString, Size: 20, Default: "operator"},
+		{Name: "availability", Type: field.TypeString, Size: 20, Default: "offline"},
+		{Name: "password_hash", Type: field.TypeString, Nullable: true, Size: 100},
+		{Name: "totp_secret", Type: field.TypeString, Nullable: true, Size: 100},
+		{Name: "created_at", Type: field.TypeTime, Default: schema.Expr("CURRENT_TIMESTAMP")},
+		{Name: "updated_at", Type: field.TypeTime, Default: schema.Expr("CURRENT_TIMESTAMP")},
 	}
 	// OperatorsTable holds the schema information for the "operators" table.
 	OperatorsTable = &schema.Table{
@@ -76,11 +190,43 @@ var (
 		Columns:    OperatorsColumns,
 		PrimaryKey: []*schema.Column{OperatorsColumns[0]},
 	}
+	// OperatorIdentitiesColumns holds the columns for the "operator_identities" table.
+	OperatorIdentitiesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "issuer", Type: field.TypeString, Size: 500},
+		{Name: "subject", Type: field.TypeString, Size: 255},
+		{Name: "created_at", Type: field.TypeTime, Default: schema.Expr("CURRENT_TIMESTAMP")},
+		{Name: "operator_id", Type: field.TypeInt},
+	}
+	// OperatorIdentitiesTable holds the schema information for the "operator_identities" table.
+	OperatorIdentitiesTable = &schema.Table{
+		Name:       "operator_identities",
+		Columns:    OperatorIdentitiesColumns,
+		PrimaryKey: []*schema.Column{OperatorIdentitiesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "operator_identities_operators_identities",
+				Columns:    []*schema.Column{OperatorIdentitiesColumns[4]},
+				RefColumns: []*schema.Column{OperatorsColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "operatoridentity_issuer_subject",
+				Unique:  true,
+				Columns: []*schema.Column{OperatorIdentitiesColumns[1], OperatorIdentitiesColumns[2]},
+			},
+		},
+	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		AuditEntriesTable,
+		DevicesTable,
 		GuidesTable,
 		GuideHistoriesTable,
 		OperatorsTable,
+		OperatorIdentitiesTable,
 	}
 )
 
@@ -88,4 +234,5 @@ func init() {
 	GuidesTable.ForeignKeys[0].RefTable = OperatorsTable
 	GuideHistoriesTable.ForeignKeys[0].RefTable = GuidesTable
 	GuideHistoriesTable.ForeignKeys[1].RefTable = OperatorsTable
+	OperatorIdentitiesTable.ForeignKeys[0].RefTable = OperatorsTable
 }
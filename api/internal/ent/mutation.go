@@ -8,10 +8,14 @@ import (
 	"fmt"
 	"sync"
 	"time"
+	"via/internal/ent/auditentry"
+	"via/internal/ent/device"
 	"via/internal/ent/guide"
 	"via/internal/ent/guidehistory"
 	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
 	"via/internal/ent/predicate"
+	"via/internal/model"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
@@ -26,45 +30,48 @@ const (
 	OpUpdateOne = ent.OpUpdateOne
 
 	// Node types.
-	TypeGuide        = "Guide"
-	TypeGuideHistory = "GuideHistory"
-	TypeOperator     = "Operator"
+	TypeAuditEntry       = "AuditEntry"
+	TypeDevice           = "Device"
+	TypeGuide            = "Guide"
+	TypeGuideHistory     = "GuideHistory"
+	TypeOperator         = "Operator"
+	TypeOperatorIdentity = "OperatorIdentity"
 )
 
-// GuideMutation represents an operation that mutates the Guide nodes in the graph.
-type GuideMutation struct {
+// AuditEntryMutation represents an operation that mutates the AuditEntry nodes in the graph.
+type AuditEntryMutation struct {
 	config
-	op              Op
-	typ             string
-	id              *int
-	via_guide_id    *string
-	recipient       *string
-	status          *string
-	payment         *string
-	created_at      *time.Time
-	updated_at      *time.Time
-	clearedFields   map[string]struct{}
-	operator        *int
-	clearedoperator bool
-	history         map[int]struct{}
-	removedhistory  map[int]struct{}
-	clearedhistory  bool
-	done            bool
-	oldValue        func(context.Context) (*Guide, error)
-	predicates      []predicate.Guide
-}
-
-var _ ent.Mutation = (*GuideMutation)(nil)
-
-// guideOption allows management of the mutation configuration using functional options.
-type guideOption func(*GuideMutation)
-
-// newGuideMutation creates new mutation for the Guide entity.
-func newGuideMutation(c config, op Op, opts ...guideOption) *GuideMutation {
-	m := &GuideMutation{
+	op            Op
+	typ           string
+	id            *int
+	actor         *string
+	action        *string
+	target_type   *string
+	target_id     *string
+	request_id    *string
+	ip            *string
+	before        *string
+	after         *string
+	prev_hash     *string
+	hash          *string
+	created_at    *time.Time
+	clearedFields map[string]struct{}
+	done          bool
+	oldValue      func(context.Context) (*AuditEntry, error)
+	predicates    []predicate.AuditEntry
+}
+
+var _ ent.Mutation = (*AuditEntryMutation)(nil)
+
+// auditentryOption allows management of the mutation configuration using functional options.
+type auditentryOption func(*AuditEntryMutation)
+
+// newAuditEntryMutation creates new mutation for the AuditEntry entity.
+func newAuditEntryMutation(c config, op Op, opts ...auditentryOption) *AuditEntryMutation {
+	m := &AuditEntryMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeGuide,
+		typ:           TypeAuditEntry,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -73,20 +80,20 @@ func newGuideMutation(c config, op Op, opts ...guideOption) *GuideMutation {
 	return m
 }
 
-// withGuideID sets the ID field of the mutation.
-func withGuideID(id int) guideOption {
-	return func(m *GuideMutation) {
+// withAuditEntryID sets the ID field of the mutation.
+func withAuditEntryID(id int) auditentryOption {
+	return func(m *AuditEntryMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *Guide
+			value *AuditEntry
 		)
-		m.oldValue = func(ctx context.Context) (*Guide, error) {
+		m.oldValue = func(ctx context.Context) (*AuditEntry, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().Guide.Get(ctx, id)
+					value, err = m.Client().AuditEntry.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -95,10 +102,10 @@ func withGuideID(id int) guideOption {
 	}
 }
 
-// withGuide sets the old Guide of the mutation.
-func withGuide(node *Guide) guideOption {
-	return func(m *GuideMutation) {
-		m.oldValue = func(context.Context) (*Guide, error) {
+// withAuditEntry sets the old AuditEntry of the mutation.
+func withAuditEntry(node *AuditEntry) auditentryOption {
+	return func(m *AuditEntryMutation) {
+		m.oldValue = func(context.Context) (*AuditEntry, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -107,7 +114,7 @@ func withGuide(node *Guide) guideOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m GuideMutation) Client() *Client {
+func (m AuditEntryMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -115,7 +122,7 @@ func (m GuideMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m GuideMutation) Tx() (*Tx, error) {
+func (m AuditEntryMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
@@ -126,7 +133,7 @@ func (m GuideMutation) Tx() (*Tx, error) {
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *GuideMutation) ID() (id int, exists bool) {
+func (m *AuditEntryMutation) ID() (id int, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -137,7 +144,7 @@ func (m *GuideMutation) ID() (id int, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *GuideMutation) IDs(ctx context.Context) ([]int, error) {
+func (m *AuditEntryMutation) IDs(ctx context.Context) ([]int, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -146,354 +153,508 @@ func (m *GuideMutation) IDs(ctx context.Context) ([]int, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Guide.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().AuditEntry.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
-// SetViaGuideID sets the "via_guide_id" field.
-func (m *GuideMutation) SetViaGuideID(s string) {
-	m.via_guide_id = &s
+// SetActor sets the "actor" field.
+func (m *AuditEntryMutation) SetActor(s string) {
+	m.actor = &s
 }
 
-// ViaGuideID returns the value of the "via_guide_id" field in the mutation.
-func (m *GuideMutation) ViaGuideID() (r string, exists bool) {
-	v := m.via_guide_id
+// Actor returns the value of the "actor" field in the mutation.
+func (m *AuditEntryMutation) Actor() (r string, exists bool) {
+	v := m.actor
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldViaGuideID returns the old "via_guide_id" field's value of the Guide entity.
-// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// OldActor returns the old "actor" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideMutation) OldViaGuideID(ctx context.Context) (v string, err error) {
+func (m *AuditEntryMutation) OldActor(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldViaGuideID is only allowed on UpdateOne operations")
+		return v, errors.New("OldActor is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldViaGuideID requires an ID field in the mutation")
+		return v, errors.New("OldActor requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldViaGuideID: %w", err)
+		return v, fmt.Errorf("querying old value for OldActor: %w", err)
 	}
-	return oldValue.ViaGuideID, nil
+	return oldValue.Actor, nil
 }
 
-// ResetViaGuideID resets all changes to the "via_guide_id" field.
-func (m *GuideMutation) ResetViaGuideID() {
-	m.via_guide_id = nil
+// ResetActor resets all changes to the "actor" field.
+func (m *AuditEntryMutation) ResetActor() {
+	m.actor = nil
 }
 
-// SetRecipient sets the "recipient" field.
-func (m *GuideMutation) SetRecipient(s string) {
-	m.recipient = &s
+// SetAction sets the "action" field.
+func (m *AuditEntryMutation) SetAction(s string) {
+	m.action = &s
 }
 
-// Recipient returns the value of the "recipient" field in the mutation.
-func (m *GuideMutation) Recipient() (r string, exists bool) {
-	v := m.recipient
+// Action returns the value of the "action" field in the mutation.
+func (m *AuditEntryMutation) Action() (r string, exists bool) {
+	v := m.action
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldRecipient returns the old "recipient" field's value of the Guide entity.
-// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// OldAction returns the old "action" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideMutation) OldRecipient(ctx context.Context) (v string, err error) {
+func (m *AuditEntryMutation) OldAction(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldRecipient is only allowed on UpdateOne operations")
+		return v, errors.New("OldAction is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldRecipient requires an ID field in the mutation")
+		return v, errors.New("OldAction requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldRecipient: %w", err)
+		return v, fmt.Errorf("querying old value for OldAction: %w", err)
 	}
-	return oldValue.Recipient, nil
+	return oldValue.Action, nil
 }
 
-// ResetRecipient resets all changes to the "recipient" field.
-func (m *GuideMutation) ResetRecipient() {
-	m.recipient = nil
+// ResetAction resets all changes to the "action" field.
+func (m *AuditEntryMutation) ResetAction() {
+	m.action = nil
 }
 
-// SetStatus sets the "status" field.
-func (m *GuideMutation) SetStatus(s string) {
-	m.status = &s
+// SetTargetType sets the "target_type" field.
+func (m *AuditEntryMutation) SetTargetType(s string) {
+	m.target_type = &s
 }
 
-// Status returns the value of the "status" field in the mutation.
-func (m *GuideMutation) Status() (r string, exists bool) {
-	v := m.status
+// TargetType returns the value of the "target_type" field in the mutation.
+func (m *AuditEntryMutation) TargetType() (r string, exists bool) {
+	v := m.target_type
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldStatus returns the old "status" field's value of the Guide entity.
-// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// OldTargetType returns the old "target_type" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideMutation) OldStatus(ctx context.Context) (v string, err error) {
+func (m *AuditEntryMutation) OldTargetType(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+		return v, errors.New("OldTargetType is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldStatus requires an ID field in the mutation")
+		return v, errors.New("OldTargetType requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+		return v, fmt.Errorf("querying old value for OldTargetType: %w", err)
 	}
-	return oldValue.Status, nil
+	return oldValue.TargetType, nil
 }
 
-// ResetStatus resets all changes to the "status" field.
-func (m *GuideMutation) ResetStatus() {
-	m.status = nil
+// ClearTargetType clears the value of the "target_type" field.
+func (m *AuditEntryMutation) ClearTargetType() {
+	m.target_type = nil
+	m.clearedFields[auditentry.FieldTargetType] = struct{}{}
 }
 
-// SetPayment sets the "payment" field.
-func (m *GuideMutation) SetPayment(s string) {
-	m.payment = &s
+// TargetTypeCleared returns if the "target_type" field was cleared in this mutation.
+func (m *AuditEntryMutation) TargetTypeCleared() bool {
+	_, ok := m.clearedFields[auditentry.FieldTargetType]
+	return ok
 }
 
-// Payment returns the value of the "payment" field in the mutation.
-func (m *GuideMutation) Payment() (r string, exists bool) {
-	v := m.payment
+// ResetTargetType resets all changes to the "target_type" field.
+func (m *AuditEntryMutation) ResetTargetType() {
+	m.target_type = nil
+	delete(m.clearedFields, auditentry.FieldTargetType)
+}
+
+// SetTargetID sets the "target_id" field.
+func (m *AuditEntryMutation) SetTargetID(s string) {
+	m.target_id = &s
+}
+
+// TargetID returns the value of the "target_id" field in the mutation.
+func (m *AuditEntryMutation) TargetID() (r string, exists bool) {
+	v := m.target_id
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldPayment returns the old "payment" field's value of the Guide entity.
-// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// OldTargetID returns the old "target_id" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideMutation) OldPayment(ctx context.Context) (v string, err error) {
+func (m *AuditEntryMutation) OldTargetID(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldPayment is only allowed on UpdateOne operations")
+		return v, errors.New("OldTargetID is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldPayment requires an ID field in the mutation")
+		return v, errors.New("OldTargetID requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldPayment: %w", err)
+		return v, fmt.Errorf("querying old value for OldTargetID: %w", err)
 	}
-	return oldValue.Payment, nil
+	return oldValue.TargetID, nil
 }
 
-// ResetPayment resets all changes to the "payment" field.
-func (m *GuideMutation) ResetPayment() {
-	m.payment = nil
+// ClearTargetID clears the value of the "target_id" field.
+func (m *AuditEntryMutation) ClearTargetID() {
+	m.target_id = nil
+	m.clearedFields[auditentry.FieldTargetID] = struct{}{}
 }
 
-// SetOperatorID sets the "operator_id" field.
-func (m *GuideMutation) SetOperatorID(i int) {
-	m.operator = &i
+// TargetIDCleared returns if the "target_id" field was cleared in this mutation.
+func (m *AuditEntryMutation) TargetIDCleared() bool {
+	_, ok := m.clearedFields[auditentry.FieldTargetID]
+	return ok
 }
 
-// OperatorID returns the value of the "operator_id" field in the mutation.
-func (m *GuideMutation) OperatorID() (r int, exists bool) {
-	v := m.operator
+// ResetTargetID resets all changes to the "target_id" field.
+func (m *AuditEntryMutation) ResetTargetID() {
+	m.target_id = nil
+	delete(m.clearedFields, auditentry.FieldTargetID)
+}
+
+// SetRequestID sets the "request_id" field.
+func (m *AuditEntryMutation) SetRequestID(s string) {
+	m.request_id = &s
+}
+
+// RequestID returns the value of the "request_id" field in the mutation.
+func (m *AuditEntryMutation) RequestID() (r string, exists bool) {
+	v := m.request_id
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldOperatorID returns the old "operator_id" field's value of the Guide entity.
-// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// OldRequestID returns the old "request_id" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideMutation) OldOperatorID(ctx context.Context) (v int, err error) {
+func (m *AuditEntryMutation) OldRequestID(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldOperatorID is only allowed on UpdateOne operations")
+		return v, errors.New("OldRequestID is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldOperatorID requires an ID field in the mutation")
+		return v, errors.New("OldRequestID requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldOperatorID: %w", err)
+		return v, fmt.Errorf("querying old value for OldRequestID: %w", err)
 	}
-	return oldValue.OperatorID, nil
+	return oldValue.RequestID, nil
 }
 
-// ResetOperatorID resets all changes to the "operator_id" field.
-func (m *GuideMutation) ResetOperatorID() {
-	m.operator = nil
+// ClearRequestID clears the value of the "request_id" field.
+func (m *AuditEntryMutation) ClearRequestID() {
+	m.request_id = nil
+	m.clearedFields[auditentry.FieldRequestID] = struct{}{}
 }
 
-// SetCreatedAt sets the "created_at" field.
-func (m *GuideMutation) SetCreatedAt(t time.Time) {
-	m.created_at = &t
+// RequestIDCleared returns if the "request_id" field was cleared in this mutation.
+func (m *AuditEntryMutation) RequestIDCleared() bool {
+	_, ok := m.clearedFields[auditentry.FieldRequestID]
+	return ok
 }
 
-// CreatedAt returns the value of the "created_at" field in the mutation.
-func (m *GuideMutation) CreatedAt() (r time.Time, exists bool) {
-	v := m.created_at
+// ResetRequestID resets all changes to the "request_id" field.
+func (m *AuditEntryMutation) ResetRequestID() {
+	m.request_id = nil
+	delete(m.clearedFields, auditentry.FieldRequestID)
+}
+
+// SetIP sets the "ip" field.
+func (m *AuditEntryMutation) SetIP(s string) {
+	m.ip = &s
+}
+
+// IP returns the value of the "ip" field in the mutation.
+func (m *AuditEntryMutation) IP() (r string, exists bool) {
+	v := m.ip
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldCreatedAt returns the old "created_at" field's value of the Guide entity.
-// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// OldIP returns the old "ip" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *AuditEntryMutation) OldIP(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldIP is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+		return v, errors.New("OldIP requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldIP: %w", err)
 	}
-	return oldValue.CreatedAt, nil
+	return oldValue.IP, nil
 }
 
-// ResetCreatedAt resets all changes to the "created_at" field.
-func (m *GuideMutation) ResetCreatedAt() {
-	m.created_at = nil
+// ClearIP clears the value of the "ip" field.
+func (m *AuditEntryMutation) ClearIP() {
+	m.ip = nil
+	m.clearedFields[auditentry.FieldIP] = struct{}{}
 }
 
-// SetUpdatedAt sets the "updated_at" field.
-func (m *GuideMutation) SetUpdatedAt(t time.Time) {
-	m.updated_at = &t
+// IPCleared returns if the "ip" field was cleared in this mutation.
+func (m *AuditEntryMutation) IPCleared() bool {
+	_, ok := m.clearedFields[auditentry.FieldIP]
+	return ok
 }
 
-// UpdatedAt returns the value of the "updated_at" field in the mutation.
-func (m *GuideMutation) UpdatedAt() (r time.Time, exists bool) {
-	v := m.updated_at
+// ResetIP resets all changes to the "ip" field.
+func (m *AuditEntryMutation) ResetIP() {
+	m.ip = nil
+	delete(m.clearedFields, auditentry.FieldIP)
+}
+
+// SetBefore sets the "before" field.
+func (m *AuditEntryMutation) SetBefore(s string) {
+	m.before = &s
+}
+
+// Before returns the value of the "before" field in the mutation.
+func (m *AuditEntryMutation) Before() (r string, exists bool) {
+	v := m.before
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldUpdatedAt returns the old "updated_at" field's value of the Guide entity.
-// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// OldBefore returns the old "before" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *AuditEntryMutation) OldBefore(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldBefore is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+		return v, errors.New("OldBefore requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldBefore: %w", err)
 	}
-	return oldValue.UpdatedAt, nil
+	return oldValue.Before, nil
 }
 
-// ResetUpdatedAt resets all changes to the "updated_at" field.
-func (m *GuideMutation) ResetUpdatedAt() {
-	m.updated_at = nil
+// ClearBefore clears the value of the "before" field.
+func (m *AuditEntryMutation) ClearBefore() {
+	m.before = nil
+	m.clearedFields[auditentry.FieldBefore] = struct{}{}
 }
 
-// ClearOperator clears the "operator" edge to the Operator entity.
-func (m *GuideMutation) ClearOperator() {
-	m.clearedoperator = true
-	m.clearedFields[guide.FieldOperatorID] = struct{}{}
+// BeforeCleared returns if the "before" field was cleared in this mutation.
+func (m *AuditEntryMutation) BeforeCleared() bool {
+	_, ok := m.clearedFields[auditentry.FieldBefore]
+	return ok
 }
 
-// OperatorCleared reports if the "operator" edge to the Operator entity was cleared.
-func (m *GuideMutation) OperatorCleared() bool {
-	return m.clearedoperator
+// ResetBefore resets all changes to the "before" field.
+func (m *AuditEntryMutation) ResetBefore() {
+	m.before = nil
+	delete(m.clearedFields, auditentry.FieldBefore)
 }
 
-// OperatorIDs returns the "operator" edge IDs in the mutation.
-// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
-// OperatorID instead. It exists only for internal usage by the builders.
-func (m *GuideMutation) OperatorIDs() (ids []int) {
-	if id := m.operator; id != nil {
-		ids = append(ids, *id)
-	}
-	return
+// SetAfter sets the "after" field.
+func (m *AuditEntryMutation) SetAfter(s string) {
+	m.after = &s
 }
 
-// ResetOperator resets all changes to the "operator" edge.
-func (m *GuideMutation) ResetOperator() {
-	m.operator = nil
-	m.clearedoperator = false
+// After returns the value of the "after" field in the mutation.
+func (m *AuditEntryMutation) After() (r string, exists bool) {
+	v := m.after
+	if v == nil {
+		return
+	}
+	return *v, true
 }
 
-// AddHistoryIDs adds the "history" edge to the GuideHistory entity by ids.
-func (m *GuideMutation) AddHistoryIDs(ids ...int) {
-	if m.history == nil {
-		m.history = make(map[int]struct{})
+// OldAfter returns the old "after" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *AuditEntryMutation) OldAfter(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAfter is only allowed on UpdateOne operations")
 	}
-	for i := range ids {
-		m.history[ids[i]] = struct{}{}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAfter requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAfter: %w", err)
 	}
+	return oldValue.After, nil
 }
 
-// ClearHistory clears the "history" edge to the GuideHistory entity.
-func (m *GuideMutation) ClearHistory() {
-	m.clearedhistory = true
+// ClearAfter clears the value of the "after" field.
+func (m *AuditEntryMutation) ClearAfter() {
+	m.after = nil
+	m.clearedFields[auditentry.FieldAfter] = struct{}{}
 }
 
-// HistoryCleared reports if the "history" edge to the GuideHistory entity was cleared.
-func (m *GuideMutation) HistoryCleared() bool {
-	return m.clearedhistory
+// AfterCleared returns if the "after" field was cleared in this mutation.
+func (m *AuditEntryMutation) AfterCleared() bool {
+	_, ok := m.clearedFields[auditentry.FieldAfter]
+	return ok
 }
 
-// RemoveHistoryIDs removes the "history" edge to the GuideHistory entity by IDs.
-func (m *GuideMutation) RemoveHistoryIDs(ids ...int) {
-	if m.removedhistory == nil {
-		m.removedhistory = make(map[int]struct{})
-	}
-	for i := range ids {
-		delete(m.history, ids[i])
-		m.removedhistory[ids[i]] = struct{}{}
-	}
+// ResetAfter resets all changes to the "after" field.
+func (m *AuditEntryMutation) ResetAfter() {
+	m.after = nil
+	delete(m.clearedFields, auditentry.FieldAfter)
 }
 
-// RemovedHistory returns the removed IDs of the "history" edge to the GuideHistory entity.
-func (m *GuideMutation) RemovedHistoryIDs() (ids []int) {
-	for id := range m.removedhistory {
-		ids = append(ids, id)
+// SetPrevHash sets the "prev_hash" field.
+func (m *AuditEntryMutation) SetPrevHash(s string) {
+	m.prev_hash = &s
+}
+
+// PrevHash returns the value of the "prev_hash" field in the mutation.
+func (m *AuditEntryMutation) PrevHash() (r string, exists bool) {
+	v := m.prev_hash
+	if v == nil {
+		return
 	}
-	return
+	return *v, true
 }
 
-// HistoryIDs returns the "history" edge IDs in the mutation.
-func (m *GuideMutation) HistoryIDs() (ids []int) {
-	for id := range m.history {
-		ids = append(ids, id)
+// OldPrevHash returns the old "prev_hash" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *AuditEntryMutation) OldPrevHash(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPrevHash is only allowed on UpdateOne operations")
 	}
-	return
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPrevHash requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPrevHash: %w", err)
+	}
+	return oldValue.PrevHash, nil
 }
 
-// ResetHistory resets all changes to the "history" edge.
-func (m *GuideMutation) ResetHistory() {
-	m.history = nil
-	m.clearedhistory = false
-	m.removedhistory = nil
+// ClearPrevHash clears the value of the "prev_hash" field.
+func (m *AuditEntryMutation) ClearPrevHash() {
+	m.prev_hash = nil
+	m.clearedFields[auditentry.FieldPrevHash] = struct{}{}
 }
 
-// Where appends a list predicates to the GuideMutation builder.
-func (m *GuideMutation) Where(ps ...predicate.Guide) {
-	m.predicates = append(m.predicates, ps...)
+// PrevHashCleared returns if the "prev_hash" field was cleared in this mutation.
+func (m *AuditEntryMutation) PrevHashCleared() bool {
+	_, ok := m.clearedFields[auditentry.FieldPrevHash]
+	return ok
 }
 
-// WhereP appends storage-level predicates to the GuideMutation builder. Using this method,
-// users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *GuideMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Guide, len(ps))
+// ResetPrevHash resets all changes to the "prev_hash" field.
+func (m *AuditEntryMutation) ResetPrevHash() {
+	m.prev_hash = nil
+	delete(m.clearedFields, auditentry.FieldPrevHash)
+}
+
+// SetHash sets the "hash" field.
+func (m *AuditEntryMutation) SetHash(s string) {
+	m.hash = &s
+}
+
+// Hash returns the value of the "hash" field in the mutation.
+func (m *AuditEntryMutation) Hash() (r string, exists bool) {
+	v := m.hash
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldHash returns the old "hash" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *AuditEntryMutation) OldHash(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHash is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHash requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHash: %w", err)
+	}
+	return oldValue.Hash, nil
+}
+
+// ResetHash resets all changes to the "hash" field.
+func (m *AuditEntryMutation) ResetHash() {
+	m.hash = nil
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *AuditEntryMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *AuditEntryMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the AuditEntry entity.
+// If the AuditEntry object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *AuditEntryMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *AuditEntryMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// Where appends a list predicates to the AuditEntryMutation builder.
+func (m *AuditEntryMutation) Where(ps ...predicate.AuditEntry) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the AuditEntryMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *AuditEntryMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.AuditEntry, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -501,45 +662,57 @@ func (m *GuideMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *GuideMutation) Op() Op {
+func (m *AuditEntryMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *GuideMutation) SetOp(op Op) {
+func (m *AuditEntryMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Guide).
-func (m *GuideMutation) Type() string {
+// Type returns the node type of this mutation (AuditEntry).
+func (m *AuditEntryMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *GuideMutation) Fields() []string {
-	fields := make([]string, 0, 7)
-	if m.via_guide_id != nil {
-		fields = append(fields, guide.FieldViaGuideID)
+func (m *AuditEntryMutation) Fields() []string {
+	fields := make([]string, 0, 11)
+	if m.actor != nil {
+		fields = append(fields, auditentry.FieldActor)
 	}
-	if m.recipient != nil {
-		fields = append(fields, guide.FieldRecipient)
+	if m.action != nil {
+		fields = append(fields, auditentry.FieldAction)
 	}
-	if m.status != nil {
-		fields = append(fields, guide.FieldStatus)
+	if m.target_type != nil {
+		fields = append(fields, auditentry.FieldTargetType)
 	}
-	if m.payment != nil {
-		fields = append(fields, guide.FieldPayment)
+	if m.target_id != nil {
+		fields = append(fields, auditentry.FieldTargetID)
 	}
-	if m.operator != nil {
-		fields = append(fields, guide.FieldOperatorID)
+	if m.request_id != nil {
+		fields = append(fields, auditentry.FieldRequestID)
 	}
-	if m.created_at != nil {
-		fields = append(fields, guide.FieldCreatedAt)
+	if m.ip != nil {
+		fields = append(fields, auditentry.FieldIP)
 	}
-	if m.updated_at != nil {
-		fields = append(fields, guide.FieldUpdatedAt)
+	if m.before != nil {
+		fields = append(fields, auditentry.FieldBefore)
+	}
+	if m.after != nil {
+		fields = append(fields, auditentry.FieldAfter)
+	}
+	if m.prev_hash != nil {
+		fields = append(fields, auditentry.FieldPrevHash)
+	}
+	if m.hash != nil {
+		fields = append(fields, auditentry.FieldHash)
+	}
+	if m.created_at != nil {
+		fields = append(fields, auditentry.FieldCreatedAt)
 	}
 	return fields
 }
@@ -547,22 +720,30 @@ func (m *GuideMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *GuideMutation) Field(name string) (ent.Value, bool) {
+func (m *AuditEntryMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case guide.FieldViaGuideID:
-		return m.ViaGuideID()
-	case guide.FieldRecipient:
-		return m.Recipient()
-	case guide.FieldStatus:
-		return m.Status()
-	case guide.FieldPayment:
-		return m.Payment()
-	case guide.FieldOperatorID:
-		return m.OperatorID()
-	case guide.FieldCreatedAt:
+	case auditentry.FieldActor:
+		return m.Actor()
+	case auditentry.FieldAction:
+		return m.Action()
+	case auditentry.FieldTargetType:
+		return m.TargetType()
+	case auditentry.FieldTargetID:
+		return m.TargetID()
+	case auditentry.FieldRequestID:
+		return m.RequestID()
+	case auditentry.FieldIP:
+		return m.IP()
+	case auditentry.FieldBefore:
+		return m.Before()
+	case auditentry.FieldAfter:
+		return m.After()
+	case auditentry.FieldPrevHash:
+		return m.PrevHash()
+	case auditentry.FieldHash:
+		return m.Hash()
+	case auditentry.FieldCreatedAt:
 		return m.CreatedAt()
-	case guide.FieldUpdatedAt:
-		return m.UpdatedAt()
 	}
 	return nil, false
 }
@@ -570,288 +751,328 @@ func (m *GuideMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *GuideMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *AuditEntryMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case guide.FieldViaGuideID:
-		return m.OldViaGuideID(ctx)
-	case guide.FieldRecipient:
-		return m.OldRecipient(ctx)
-	case guide.FieldStatus:
-		return m.OldStatus(ctx)
-	case guide.FieldPayment:
-		return m.OldPayment(ctx)
-	case guide.FieldOperatorID:
-		return m.OldOperatorID(ctx)
-	case guide.FieldCreatedAt:
+	case auditentry.FieldActor:
+		return m.OldActor(ctx)
+	case auditentry.FieldAction:
+		return m.OldAction(ctx)
+	case auditentry.FieldTargetType:
+		return m.OldTargetType(ctx)
+	case auditentry.FieldTargetID:
+		return m.OldTargetID(ctx)
+	case auditentry.FieldRequestID:
+		return m.OldRequestID(ctx)
+	case auditentry.FieldIP:
+		return m.OldIP(ctx)
+	case auditentry.FieldBefore:
+		return m.OldBefore(ctx)
+	case auditentry.FieldAfter:
+		return m.OldAfter(ctx)
+	case auditentry.FieldPrevHash:
+		return m.OldPrevHash(ctx)
+	case auditentry.FieldHash:
+		return m.OldHash(ctx)
+	case auditentry.FieldCreatedAt:
 		return m.OldCreatedAt(ctx)
-	case guide.FieldUpdatedAt:
-		return m.OldUpdatedAt(ctx)
 	}
-	return nil, fmt.Errorf("unknown Guide field %s", name)
+	return nil, fmt.Errorf("unknown AuditEntry field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *GuideMutation) SetField(name string, value ent.Value) error {
+func (m *AuditEntryMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case guide.FieldViaGuideID:
+	case auditentry.FieldActor:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetViaGuideID(v)
+		m.SetActor(v)
 		return nil
-	case guide.FieldRecipient:
+	case auditentry.FieldAction:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetRecipient(v)
+		m.SetAction(v)
 		return nil
-	case guide.FieldStatus:
+	case auditentry.FieldTargetType:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetStatus(v)
+		m.SetTargetType(v)
 		return nil
-	case guide.FieldPayment:
+	case auditentry.FieldTargetID:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetPayment(v)
+		m.SetTargetID(v)
 		return nil
-	case guide.FieldOperatorID:
-		v, ok := value.(int)
+	case auditentry.FieldRequestID:
+		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetOperatorID(v)
+		m.SetRequestID(v)
 		return nil
-	case guide.FieldCreatedAt:
-		v, ok := value.(time.Time)
+	case auditentry.FieldIP:
+		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetCreatedAt(v)
+		m.SetIP(v)
 		return nil
-	case guide.FieldUpdatedAt:
+	case auditentry.FieldBefore:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBefore(v)
+		return nil
+	case auditentry.FieldAfter:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAfter(v)
+		return nil
+	case auditentry.FieldPrevHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPrevHash(v)
+		return nil
+	case auditentry.FieldHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHash(v)
+		return nil
+	case auditentry.FieldCreatedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetUpdatedAt(v)
+		m.SetCreatedAt(v)
 		return nil
 	}
-	return fmt.Errorf("unknown Guide field %s", name)
+	return fmt.Errorf("unknown AuditEntry field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *GuideMutation) AddedFields() []string {
-	var fields []string
-	return fields
+func (m *AuditEntryMutation) AddedFields() []string {
+	return nil
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *GuideMutation) AddedField(name string) (ent.Value, bool) {
-	switch name {
-	}
+func (m *AuditEntryMutation) AddedField(name string) (ent.Value, bool) {
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *GuideMutation) AddField(name string, value ent.Value) error {
+func (m *AuditEntryMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown Guide numeric field %s", name)
+	return fmt.Errorf("unknown AuditEntry numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *GuideMutation) ClearedFields() []string {
-	return nil
+func (m *AuditEntryMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(auditentry.FieldTargetType) {
+		fields = append(fields, auditentry.FieldTargetType)
+	}
+	if m.FieldCleared(auditentry.FieldTargetID) {
+		fields = append(fields, auditentry.FieldTargetID)
+	}
+	if m.FieldCleared(auditentry.FieldRequestID) {
+		fields = append(fields, auditentry.FieldRequestID)
+	}
+	if m.FieldCleared(auditentry.FieldIP) {
+		fields = append(fields, auditentry.FieldIP)
+	}
+	if m.FieldCleared(auditentry.FieldBefore) {
+		fields = append(fields, auditentry.FieldBefore)
+	}
+	if m.FieldCleared(auditentry.FieldAfter) {
+		fields = append(fields, auditentry.FieldAfter)
+	}
+	if m.FieldCleared(auditentry.FieldPrevHash) {
+		fields = append(fields, auditentry.FieldPrevHash)
+	}
+	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *GuideMutation) FieldCleared(name string) bool {
+func (m *AuditEntryMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *GuideMutation) ClearField(name string) error {
-	return fmt.Errorf("unknown Guide nullable field %s", name)
+func (m *AuditEntryMutation) ClearField(name string) error {
+	switch name {
+	case auditentry.FieldTargetType:
+		m.ClearTargetType()
+		return nil
+	case auditentry.FieldTargetID:
+		m.ClearTargetID()
+		return nil
+	case auditentry.FieldRequestID:
+		m.ClearRequestID()
+		return nil
+	case auditentry.FieldIP:
+		m.ClearIP()
+		return nil
+	case auditentry.FieldBefore:
+		m.ClearBefore()
+		return nil
+	case auditentry.FieldAfter:
+		m.ClearAfter()
+		return nil
+	case auditentry.FieldPrevHash:
+		m.ClearPrevHash()
+		return nil
+	}
+	return fmt.Errorf("unknown AuditEntry nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *GuideMutation) ResetField(name string) error {
+func (m *AuditEntryMutation) ResetField(name string) error {
 	switch name {
-	case guide.FieldViaGuideID:
-		m.ResetViaGuideID()
+	case auditentry.FieldActor:
+		m.ResetActor()
 		return nil
-	case guide.FieldRecipient:
-		m.ResetRecipient()
+	case auditentry.FieldAction:
+		m.ResetAction()
 		return nil
-	case guide.FieldStatus:
-		m.ResetStatus()
+	case auditentry.FieldTargetType:
+		m.ResetTargetType()
 		return nil
-	case guide.FieldPayment:
-		m.ResetPayment()
+	case auditentry.FieldTargetID:
+		m.ResetTargetID()
 		return nil
-	case guide.FieldOperatorID:
-		m.ResetOperatorID()
+	case auditentry.FieldRequestID:
+		m.ResetRequestID()
 		return nil
-	case guide.FieldCreatedAt:
-		m.ResetCreatedAt()
+	case auditentry.FieldIP:
+		m.ResetIP()
 		return nil
-	case guide.FieldUpdatedAt:
-		m.ResetUpdatedAt()
+	case auditentry.FieldBefore:
+		m.ResetBefore()
+		return nil
+	case auditentry.FieldAfter:
+		m.ResetAfter()
+		return nil
+	case auditentry.FieldPrevHash:
+		m.ResetPrevHash()
+		return nil
+	case auditentry.FieldHash:
+		m.ResetHash()
+		return nil
+	case auditentry.FieldCreatedAt:
+		m.ResetCreatedAt()
 		return nil
 	}
-	return fmt.Errorf("unknown Guide field %s", name)
+	return fmt.Errorf("unknown AuditEntry field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *GuideMutation) AddedEdges() []string {
-	edges := make([]string, 0, 2)
-	if m.operator != nil {
-		edges = append(edges, guide.EdgeOperator)
-	}
-	if m.history != nil {
-		edges = append(edges, guide.EdgeHistory)
-	}
+func (m *AuditEntryMutation) AddedEdges() []string {
+	edges := make([]string, 0, 0)
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *GuideMutation) AddedIDs(name string) []ent.Value {
-	switch name {
-	case guide.EdgeOperator:
-		if id := m.operator; id != nil {
-			return []ent.Value{*id}
-		}
-	case guide.EdgeHistory:
-		ids := make([]ent.Value, 0, len(m.history))
-		for id := range m.history {
-			ids = append(ids, id)
-		}
-		return ids
-	}
+func (m *AuditEntryMutation) AddedIDs(name string) []ent.Value {
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *GuideMutation) RemovedEdges() []string {
-	edges := make([]string, 0, 2)
-	if m.removedhistory != nil {
-		edges = append(edges, guide.EdgeHistory)
-	}
+func (m *AuditEntryMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 0)
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *GuideMutation) RemovedIDs(name string) []ent.Value {
-	switch name {
-	case guide.EdgeHistory:
-		ids := make([]ent.Value, 0, len(m.removedhistory))
-		for id := range m.removedhistory {
-			ids = append(ids, id)
-		}
-		return ids
-	}
+func (m *AuditEntryMutation) RemovedIDs(name string) []ent.Value {
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *GuideMutation) ClearedEdges() []string {
-	edges := make([]string, 0, 2)
-	if m.clearedoperator {
-		edges = append(edges, guide.EdgeOperator)
-	}
-	if m.clearedhistory {
-		edges = append(edges, guide.EdgeHistory)
-	}
+func (m *AuditEntryMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 0)
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *GuideMutation) EdgeCleared(name string) bool {
-	switch name {
-	case guide.EdgeOperator:
-		return m.clearedoperator
-	case guide.EdgeHistory:
-		return m.clearedhistory
-	}
+func (m *AuditEntryMutation) EdgeCleared(name string) bool {
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *GuideMutation) ClearEdge(name string) error {
-	switch name {
-	case guide.EdgeOperator:
-		m.ClearOperator()
-		return nil
-	}
-	return fmt.Errorf("unknown Guide unique edge %s", name)
+func (m *AuditEntryMutation) ClearEdge(name string) error {
+	return fmt.Errorf("unknown AuditEntry unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *GuideMutation) ResetEdge(name string) error {
-	switch name {
-	case guide.EdgeOperator:
-		m.ResetOperator()
-		return nil
-	case guide.EdgeHistory:
-		m.ResetHistory()
-		return nil
-	}
-	return fmt.Errorf("unknown Guide edge %s", name)
+func (m *AuditEntryMutation) ResetEdge(name string) error {
+	return fmt.Errorf("unknown AuditEntry edge %s", name)
 }
 
-// GuideHistoryMutation represents an operation that mutates the GuideHistory nodes in the graph.
-type GuideHistoryMutation struct {
+// DeviceMutation represents an operation that mutates the Device nodes in the graph.
+type DeviceMutation struct {
 	config
-	op              Op
-	typ             string
-	id              *int
-	status          *string
-	created_at      *time.Time
-	clearedFields   map[string]struct{}
-	guide           *int
-	clearedguide    bool
-	operator        *int
-	clearedoperator bool
-	done            bool
-	oldValue        func(context.Context) (*GuideHistory, error)
-	predicates      []predicate.GuideHistory
-}
-
-var _ ent.Mutation = (*GuideHistoryMutation)(nil)
-
-// guidehistoryOption allows management of the mutation configuration using functional options.
-type guidehistoryOption func(*GuideHistoryMutation)
-
-// newGuideHistoryMutation creates new mutation for the GuideHistory entity.
-func newGuideHistoryMutation(c config, op Op, opts ...guidehistoryOption) *GuideHistoryMutation {
-	m := &GuideHistoryMutation{
+	op                 Op
+	typ                string
+	id                 *int
+	name               *string
+	kind               *string
+	branch             *string
+	token_hash         *string
+	pairing_code_hash  *string
+	pairing_expires_at *time.Time
+	paired_at          *time.Time
+	privacy            *string
+	revoked_at         *time.Time
+	created_at         *time.Time
+	clearedFields      map[string]struct{}
+	done               bool
+	oldValue           func(context.Context) (*Device, error)
+	predicates         []predicate.Device
+}
+
+var _ ent.Mutation = (*DeviceMutation)(nil)
+
+// deviceOption allows management of the mutation configuration using functional options.
+type deviceOption func(*DeviceMutation)
+
+// newDeviceMutation creates new mutation for the Device entity.
+func newDeviceMutation(c config, op Op, opts ...deviceOption) *DeviceMutation {
+	m := &DeviceMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeGuideHistory,
+		typ:           TypeDevice,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -860,20 +1081,20 @@ func newGuideHistoryMutation(c config, op Op, opts ...guidehistoryOption) *Guide
 	return m
 }
 
-// withGuideHistoryID sets the ID field of the mutation.
-func withGuideHistoryID(id int) guidehistoryOption {
-	return func(m *GuideHistoryMutation) {
+// withDeviceID sets the ID field of the mutation.
+func withDeviceID(id int) deviceOption {
+	return func(m *DeviceMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *GuideHistory
+			value *Device
 		)
-		m.oldValue = func(ctx context.Context) (*GuideHistory, error) {
+		m.oldValue = func(ctx context.Context) (*Device, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().GuideHistory.Get(ctx, id)
+					value, err = m.Client().Device.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -882,10 +1103,10 @@ func withGuideHistoryID(id int) guidehistoryOption {
 	}
 }
 
-// withGuideHistory sets the old GuideHistory of the mutation.
-func withGuideHistory(node *GuideHistory) guidehistoryOption {
-	return func(m *GuideHistoryMutation) {
-		m.oldValue = func(context.Context) (*GuideHistory, error) {
+// withDevice sets the old Device of the mutation.
+func withDevice(node *Device) deviceOption {
+	return func(m *DeviceMutation) {
+		m.oldValue = func(context.Context) (*Device, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -894,7 +1115,7 @@ func withGuideHistory(node *GuideHistory) guidehistoryOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m GuideHistoryMutation) Client() *Client {
+func (m DeviceMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -902,7 +1123,7 @@ func (m GuideHistoryMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m GuideHistoryMutation) Tx() (*Tx, error) {
+func (m DeviceMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
@@ -913,7 +1134,7 @@ func (m GuideHistoryMutation) Tx() (*Tx, error) {
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *GuideHistoryMutation) ID() (id int, exists bool) {
+func (m *DeviceMutation) ID() (id int, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -924,7 +1145,7 @@ func (m *GuideHistoryMutation) ID() (id int, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *GuideHistoryMutation) IDs(ctx context.Context) ([]int, error) {
+func (m *DeviceMutation) IDs(ctx context.Context) ([]int, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -933,521 +1154,3642 @@ func (m *GuideHistoryMutation) IDs(ctx context.Context) ([]int, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().GuideHistory.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().Device.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
-// SetGuideID sets the "guide_id" field.
-func (m *GuideHistoryMutation) SetGuideID(i int) {
-	m.guide = &i
+// SetName sets the "name" field.
+func (m *DeviceMutation) SetName(s string) {
+	m.name = &s
 }
 
-// GuideID returns the value of the "guide_id" field in the mutation.
-func (m *GuideHistoryMutation) GuideID() (r int, exists bool) {
-	v := m.guide
+// Name returns the value of the "name" field in the mutation.
+func (m *DeviceMutation) Name() (r string, exists bool) {
+	v := m.name
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldGuideID returns the old "guide_id" field's value of the GuideHistory entity.
-// If the GuideHistory object wasn't provided to the builder, the object is fetched from the database.
+// OldName returns the old "name" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideHistoryMutation) OldGuideID(ctx context.Context) (v int, err error) {
+func (m *DeviceMutation) OldName(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldGuideID is only allowed on UpdateOne operations")
+		return v, errors.New("OldName is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldGuideID requires an ID field in the mutation")
+		return v, errors.New("OldName requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldGuideID: %w", err)
+		return v, fmt.Errorf("querying old value for OldName: %w", err)
 	}
-	return oldValue.GuideID, nil
+	return oldValue.Name, nil
 }
 
-// ResetGuideID resets all changes to the "guide_id" field.
-func (m *GuideHistoryMutation) ResetGuideID() {
-	m.guide = nil
+// ResetName resets all changes to the "name" field.
+func (m *DeviceMutation) ResetName() {
+	m.name = nil
 }
 
-// SetStatus sets the "status" field.
-func (m *GuideHistoryMutation) SetStatus(s string) {
-	m.status = &s
+// SetKind sets the "kind" field.
+func (m *DeviceMutation) SetKind(s string) {
+	m.kind = &s
 }
 
-// Status returns the value of the "status" field in the mutation.
-func (m *GuideHistoryMutation) Status() (r string, exists bool) {
-	v := m.status
+// Kind returns the value of the "kind" field in the mutation.
+func (m *DeviceMutation) Kind() (r string, exists bool) {
+	v := m.kind
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldStatus returns the old "status" field's value of the GuideHistory entity.
-// If the GuideHistory object wasn't provided to the builder, the object is fetched from the database.
+// OldKind returns the old "kind" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideHistoryMutation) OldStatus(ctx context.Context) (v string, err error) {
+func (m *DeviceMutation) OldKind(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+		return v, errors.New("OldKind is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldStatus requires an ID field in the mutation")
+		return v, errors.New("OldKind requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+		return v, fmt.Errorf("querying old value for OldKind: %w", err)
 	}
-	return oldValue.Status, nil
+	return oldValue.Kind, nil
 }
 
-// ResetStatus resets all changes to the "status" field.
-func (m *GuideHistoryMutation) ResetStatus() {
-	m.status = nil
+// ResetKind resets all changes to the "kind" field.
+func (m *DeviceMutation) ResetKind() {
+	m.kind = nil
 }
 
-// SetOperatorID sets the "operator_id" field.
-func (m *GuideHistoryMutation) SetOperatorID(i int) {
-	m.operator = &i
+// SetBranch sets the "branch" field.
+func (m *DeviceMutation) SetBranch(s string) {
+	m.branch = &s
 }
 
-// OperatorID returns the value of the "operator_id" field in the mutation.
-func (m *GuideHistoryMutation) OperatorID() (r int, exists bool) {
-	v := m.operator
+// Branch returns the value of the "branch" field in the mutation.
+func (m *DeviceMutation) Branch() (r string, exists bool) {
+	v := m.branch
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldOperatorID returns the old "operator_id" field's value of the GuideHistory entity.
-// If the GuideHistory object wasn't provided to the builder, the object is fetched from the database.
+// OldBranch returns the old "branch" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideHistoryMutation) OldOperatorID(ctx context.Context) (v int, err error) {
+func (m *DeviceMutation) OldBranch(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldOperatorID is only allowed on UpdateOne operations")
+		return v, errors.New("OldBranch is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldOperatorID requires an ID field in the mutation")
+		return v, errors.New("OldBranch requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldOperatorID: %w", err)
+		return v, fmt.Errorf("querying old value for OldBranch: %w", err)
 	}
-	return oldValue.OperatorID, nil
+	return oldValue.Branch, nil
 }
 
-// ResetOperatorID resets all changes to the "operator_id" field.
-func (m *GuideHistoryMutation) ResetOperatorID() {
-	m.operator = nil
+// ResetBranch resets all changes to the "branch" field.
+func (m *DeviceMutation) ResetBranch() {
+	m.branch = nil
 }
 
-// SetCreatedAt sets the "created_at" field.
-func (m *GuideHistoryMutation) SetCreatedAt(t time.Time) {
-	m.created_at = &t
+// SetTokenHash sets the "token_hash" field.
+func (m *DeviceMutation) SetTokenHash(s string) {
+	m.token_hash = &s
 }
 
-// CreatedAt returns the value of the "created_at" field in the mutation.
-func (m *GuideHistoryMutation) CreatedAt() (r time.Time, exists bool) {
-	v := m.created_at
+// TokenHash returns the value of the "token_hash" field in the mutation.
+func (m *DeviceMutation) TokenHash() (r string, exists bool) {
+	v := m.token_hash
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldCreatedAt returns the old "created_at" field's value of the GuideHistory entity.
-// If the GuideHistory object wasn't provided to the builder, the object is fetched from the database.
+// OldTokenHash returns the old "token_hash" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *GuideHistoryMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *DeviceMutation) OldTokenHash(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldTokenHash is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+		return v, errors.New("OldTokenHash requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldTokenHash: %w", err)
 	}
-	return oldValue.CreatedAt, nil
+	return oldValue.TokenHash, nil
 }
 
-// ResetCreatedAt resets all changes to the "created_at" field.
-func (m *GuideHistoryMutation) ResetCreatedAt() {
-	m.created_at = nil
+// ClearTokenHash clears the value of the "token_hash" field.
+func (m *DeviceMutation) ClearTokenHash() {
+	m.token_hash = nil
+	m.clearedFields[device.FieldTokenHash] = struct{}{}
 }
 
-// ClearGuide clears the "guide" edge to the Guide entity.
-func (m *GuideHistoryMutation) ClearGuide() {
-	m.clearedguide = true
-	m.clearedFields[guidehistory.FieldGuideID] = struct{}{}
+// TokenHashCleared returns if the "token_hash" field was cleared in this mutation.
+func (m *DeviceMutation) TokenHashCleared() bool {
+	_, ok := m.clearedFields[device.FieldTokenHash]
+	return ok
 }
 
-// GuideCleared reports if the "guide" edge to the Guide entity was cleared.
-func (m *GuideHistoryMutation) GuideCleared() bool {
-	return m.clearedguide
+// ResetTokenHash resets all changes to the "token_hash" field.
+func (m *DeviceMutation) ResetTokenHash() {
+	m.token_hash = nil
+	delete(m.clearedFields, device.FieldTokenHash)
 }
 
-// GuideIDs returns the "guide" edge IDs in the mutation.
-// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
-// GuideID instead. It exists only for internal usage by the builders.
-func (m *GuideHistoryMutation) GuideIDs() (ids []int) {
-	if id := m.guide; id != nil {
-		ids = append(ids, *id)
+// SetPairingCodeHash sets the "pairing_code_hash" field.
+func (m *DeviceMutation) SetPairingCodeHash(s string) {
+	m.pairing_code_hash = &s
+}
+
+// PairingCodeHash returns the value of the "pairing_code_hash" field in the mutation.
+func (m *DeviceMutation) PairingCodeHash() (r string, exists bool) {
+	v := m.pairing_code_hash
+	if v == nil {
+		return
 	}
-	return
+	return *v, true
 }
 
-// ResetGuide resets all changes to the "guide" edge.
-func (m *GuideHistoryMutation) ResetGuide() {
-	m.guide = nil
-	m.clearedguide = false
+// OldPairingCodeHash returns the old "pairing_code_hash" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DeviceMutation) OldPairingCodeHash(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPairingCodeHash is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPairingCodeHash requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPairingCodeHash: %w", err)
+	}
+	return oldValue.PairingCodeHash, nil
 }
 
-// ClearOperator clears the "operator" edge to the Operator entity.
-func (m *GuideHistoryMutation) ClearOperator() {
-	m.clearedoperator = true
-	m.clearedFields[guidehistory.FieldOperatorID] = struct{}{}
+// ClearPairingCodeHash clears the value of the "pairing_code_hash" field.
+func (m *DeviceMutation) ClearPairingCodeHash() {
+	m.pairing_code_hash = nil
+	m.clearedFields[device.FieldPairingCodeHash] = struct{}{}
 }
 
-// OperatorCleared reports if the "operator" edge to the Operator entity was cleared.
-func (m *GuideHistoryMutation) OperatorCleared() bool {
-	return m.clearedoperator
+// PairingCodeHashCleared returns if the "pairing_code_hash" field was cleared in this mutation.
+func (m *DeviceMutation) PairingCodeHashCleared() bool {
+	_, ok := m.clearedFields[device.FieldPairingCodeHash]
+	return ok
 }
 
-// OperatorIDs returns the "operator" edge IDs in the mutation.
-// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
-// OperatorID instead. It exists only for internal usage by the builders.
-func (m *GuideHistoryMutation) OperatorIDs() (ids []int) {
-	if id := m.operator; id != nil {
-		ids = append(ids, *id)
-	}
-	return
+// ResetPairingCodeHash resets all changes to the "pairing_code_hash" field.
+func (m *DeviceMutation) ResetPairingCodeHash() {
+	m.pairing_code_hash = nil
+	delete(m.clearedFields, device.FieldPairingCodeHash)
 }
 
-// ResetOperator resets all changes to the "operator" edge.
-func (m *GuideHistoryMutation) ResetOperator() {
-	m.operator = nil
-	m.clearedoperator = false
+// SetPairingExpiresAt sets the "pairing_expires_at" field.
+func (m *DeviceMutation) SetPairingExpiresAt(t time.Time) {
+	m.pairing_expires_at = &t
 }
 
-// Where appends a list predicates to the GuideHistoryMutation builder.
-func (m *GuideHistoryMutation) Where(ps ...predicate.GuideHistory) {
-	m.predicates = append(m.predicates, ps...)
+// PairingExpiresAt returns the value of the "pairing_expires_at" field in the mutation.
+func (m *DeviceMutation) PairingExpiresAt() (r time.Time, exists bool) {
+	v := m.pairing_expires_at
+	if v == nil {
+		return
+	}
+	return *v, true
 }
 
-// WhereP appends storage-level predicates to the GuideHistoryMutation builder. Using this method,
-// users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *GuideHistoryMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.GuideHistory, len(ps))
-	for i := range ps {
-		p[i] = ps[i]
+// OldPairingExpiresAt returns the old "pairing_expires_at" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DeviceMutation) OldPairingExpiresAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPairingExpiresAt is only allowed on UpdateOne operations")
 	}
-	m.Where(p...)
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPairingExpiresAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPairingExpiresAt: %w", err)
+	}
+	return oldValue.PairingExpiresAt, nil
 }
 
-// Op returns the operation name.
-func (m *GuideHistoryMutation) Op() Op {
-	return m.op
+// ClearPairingExpiresAt clears the value of the "pairing_expires_at" field.
+func (m *DeviceMutation) ClearPairingExpiresAt() {
+	m.pairing_expires_at = nil
+	m.clearedFields[device.FieldPairingExpiresAt] = struct{}{}
 }
 
-// SetOp allows setting the mutation operation.
-func (m *GuideHistoryMutation) SetOp(op Op) {
-	m.op = op
+// PairingExpiresAtCleared returns if the "pairing_expires_at" field was cleared in this mutation.
+func (m *DeviceMutation) PairingExpiresAtCleared() bool {
+	_, ok := m.clearedFields[device.FieldPairingExpiresAt]
+	return ok
 }
 
-// Type returns the node type of this mutation (GuideHistory).
-func (m *GuideHistoryMutation) Type() string {
-	return m.typ
+// ResetPairingExpiresAt resets all changes to the "pairing_expires_at" field.
+func (m *DeviceMutation) ResetPairingExpiresAt() {
+	m.pairing_expires_at = nil
+	delete(m.clearedFields, device.FieldPairingExpiresAt)
 }
 
-// Fields returns all fields that were changed during this mutation. Note that in
-// order to get all numeric fields that were incremented/decremented, call
-// AddedFields().
-func (m *GuideHistoryMutation) Fields() []string {
-	fields := make([]string, 0, 4)
-	if m.guide != nil {
-		fields = append(fields, guidehistory.FieldGuideID)
+// SetPairedAt sets the "paired_at" field.
+func (m *DeviceMutation) SetPairedAt(t time.Time) {
+	m.paired_at = &t
+}
+
+// PairedAt returns the value of the "paired_at" field in the mutation.
+func (m *DeviceMutation) PairedAt() (r time.Time, exists bool) {
+	v := m.paired_at
+	if v == nil {
+		return
 	}
-	if m.status != nil {
-		fields = append(fields, guidehistory.FieldStatus)
+	return *v, true
+}
+
+// OldPairedAt returns the old "paired_at" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DeviceMutation) OldPairedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPairedAt is only allowed on UpdateOne operations")
 	}
-	if m.operator != nil {
-		fields = append(fields, guidehistory.FieldOperatorID)
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPairedAt requires an ID field in the mutation")
 	}
-	if m.created_at != nil {
-		fields = append(fields, guidehistory.FieldCreatedAt)
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPairedAt: %w", err)
 	}
-	return fields
+	return oldValue.PairedAt, nil
 }
 
-// Field returns the value of a field with the given name. The second boolean
-// return value indicates that this field was not set, or was not defined in the
-// schema.
-func (m *GuideHistoryMutation) Field(name string) (ent.Value, bool) {
-	switch name {
-	case guidehistory.FieldGuideID:
-		return m.GuideID()
-	case guidehistory.FieldStatus:
-		return m.Status()
-	case guidehistory.FieldOperatorID:
-		return m.OperatorID()
-	case guidehistory.FieldCreatedAt:
-		return m.CreatedAt()
-	}
-	return nil, false
+// ClearPairedAt clears the value of the "paired_at" field.
+func (m *DeviceMutation) ClearPairedAt() {
+	m.paired_at = nil
+	m.clearedFields[device.FieldPairedAt] = struct{}{}
 }
 
-// OldField returns the old value of the field from the database. An error is
-// returned if the mutation operation is not UpdateOne, or the query to the
-// database failed.
+// PairedAtCleared returns if the "paired_at" field was cleared in this mutation.
+func (m *DeviceMutation) PairedAtCleared() bool {
+	_, ok := m.clearedFields[device.FieldPairedAt]
+	return ok
+}
+
+// ResetPairedAt resets all changes to the "paired_at" field.
+func (m *DeviceMutation) ResetPairedAt() {
+	m.paired_at = nil
+	delete(m.clearedFields, device.FieldPairedAt)
+}
+
+// SetPrivacy sets the "privacy" field.
+func (m *DeviceMutation) SetPrivacy(s string) {
+	m.privacy = &s
+}
+
+// Privacy returns the value of the "privacy" field in the mutation.
+func (m *DeviceMutation) Privacy() (r string, exists bool) {
+	v := m.privacy
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPrivacy returns the old "privacy" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DeviceMutation) OldPrivacy(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPrivacy is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPrivacy requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPrivacy: %w", err)
+	}
+	return oldValue.Privacy, nil
+}
+
+// ClearPrivacy clears the value of the "privacy" field.
+func (m *DeviceMutation) ClearPrivacy() {
+	m.privacy = nil
+	m.clearedFields[device.FieldPrivacy] = struct{}{}
+}
+
+// PrivacyCleared returns if the "privacy" field was cleared in this mutation.
+func (m *DeviceMutation) PrivacyCleared() bool {
+	_, ok := m.clearedFields[device.FieldPrivacy]
+	return ok
+}
+
+// ResetPrivacy resets all changes to the "privacy" field.
+func (m *DeviceMutation) ResetPrivacy() {
+	m.privacy = nil
+	delete(m.clearedFields, device.FieldPrivacy)
+}
+
+// SetRevokedAt sets the "revoked_at" field.
+func (m *DeviceMutation) SetRevokedAt(t time.Time) {
+	m.revoked_at = &t
+}
+
+// RevokedAt returns the value of the "revoked_at" field in the mutation.
+func (m *DeviceMutation) RevokedAt() (r time.Time, exists bool) {
+	v := m.revoked_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRevokedAt returns the old "revoked_at" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DeviceMutation) OldRevokedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRevokedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRevokedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRevokedAt: %w", err)
+	}
+	return oldValue.RevokedAt, nil
+}
+
+// ClearRevokedAt clears the value of the "revoked_at" field.
+func (m *DeviceMutation) ClearRevokedAt() {
+	m.revoked_at = nil
+	m.clearedFields[device.FieldRevokedAt] = struct{}{}
+}
+
+// RevokedAtCleared returns if the "revoked_at" field was cleared in this mutation.
+func (m *DeviceMutation) RevokedAtCleared() bool {
+	_, ok := m.clearedFields[device.FieldRevokedAt]
+	return ok
+}
+
+// ResetRevokedAt resets all changes to the "revoked_at" field.
+func (m *DeviceMutation) ResetRevokedAt() {
+	m.revoked_at = nil
+	delete(m.clearedFields, device.FieldRevokedAt)
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *DeviceMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *DeviceMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the Device entity.
+// If the Device object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DeviceMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *DeviceMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// Where appends a list predicates to the DeviceMutation builder.
+func (m *DeviceMutation) Where(ps ...predicate.Device) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the DeviceMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *DeviceMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Device, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *DeviceMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *DeviceMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (Device).
+func (m *DeviceMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *DeviceMutation) Fields() []string {
+	fields := make([]string, 0, 10)
+	if m.name != nil {
+		fields = append(fields, device.FieldName)
+	}
+	if m.kind != nil {
+		fields = append(fields, device.FieldKind)
+	}
+	if m.branch != nil {
+		fields = append(fields, device.FieldBranch)
+	}
+	if m.token_hash != nil {
+		fields = append(fields, device.FieldTokenHash)
+	}
+	if m.pairing_code_hash != nil {
+		fields = append(fields, device.FieldPairingCodeHash)
+	}
+	if m.pairing_expires_at != nil {
+		fields = append(fields, device.FieldPairingExpiresAt)
+	}
+	if m.paired_at != nil {
+		fields = append(fields, device.FieldPairedAt)
+	}
+	if m.privacy != nil {
+		fields = append(fields, device.FieldPrivacy)
+	}
+	if m.revoked_at != nil {
+		fields = append(fields, device.FieldRevokedAt)
+	}
+	if m.created_at != nil {
+		fields = append(fields, device.FieldCreatedAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *DeviceMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case device.FieldName:
+		return m.Name()
+	case device.FieldKind:
+		return m.Kind()
+	case device.FieldBranch:
+		return m.Branch()
+	case device.FieldTokenHash:
+		return m.TokenHash()
+	case device.FieldPairingCodeHash:
+		return m.PairingCodeHash()
+	case device.FieldPairingExpiresAt:
+		return m.PairingExpiresAt()
+	case device.FieldPairedAt:
+		return m.PairedAt()
+	case device.FieldPrivacy:
+		return m.Privacy()
+	case device.FieldRevokedAt:
+		return m.RevokedAt()
+	case device.FieldCreatedAt:
+		return m.CreatedAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *DeviceMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case device.FieldName:
+		return m.OldName(ctx)
+	case device.FieldKind:
+		return m.OldKind(ctx)
+	case device.FieldBranch:
+		return m.OldBranch(ctx)
+	case device.FieldTokenHash:
+		return m.OldTokenHash(ctx)
+	case device.FieldPairingCodeHash:
+		return m.OldPairingCodeHash(ctx)
+	case device.FieldPairingExpiresAt:
+		return m.OldPairingExpiresAt(ctx)
+	case device.FieldPairedAt:
+		return m.OldPairedAt(ctx)
+	case device.FieldPrivacy:
+		return m.OldPrivacy(ctx)
+	case device.FieldRevokedAt:
+		return m.OldRevokedAt(ctx)
+	case device.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	}
+	return nil, fmt.Errorf("unknown Device field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *DeviceMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case device.FieldName:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetName(v)
+		return nil
+	case device.FieldKind:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetKind(v)
+		return nil
+	case device.FieldBranch:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBranch(v)
+		return nil
+	case device.FieldTokenHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTokenHash(v)
+		return nil
+	case device.FieldPairingCodeHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPairingCodeHash(v)
+		return nil
+	case device.FieldPairingExpiresAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPairingExpiresAt(v)
+		return nil
+	case device.FieldPairedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPairedAt(v)
+		return nil
+	case device.FieldPrivacy:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPrivacy(v)
+		return nil
+	case device.FieldRevokedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRevokedAt(v)
+		return nil
+	case device.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedAt(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Device field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *DeviceMutation) AddedFields() []string {
+	return nil
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *DeviceMutation) AddedField(name string) (ent.Value, bool) {
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *DeviceMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown Device numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *DeviceMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(device.FieldTokenHash) {
+		fields = append(fields, device.FieldTokenHash)
+	}
+	if m.FieldCleared(device.FieldPairingCodeHash) {
+		fields = append(fields, device.FieldPairingCodeHash)
+	}
+	if m.FieldCleared(device.FieldPairingExpiresAt) {
+		fields = append(fields, device.FieldPairingExpiresAt)
+	}
+	if m.FieldCleared(device.FieldPairedAt) {
+		fields = append(fields, device.FieldPairedAt)
+	}
+	if m.FieldCleared(device.FieldPrivacy) {
+		fields = append(fields, device.FieldPrivacy)
+	}
+	if m.FieldCleared(device.FieldRevokedAt) {
+		fields = append(fields, device.FieldRevokedAt)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *DeviceMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *DeviceMutation) ClearField(name string) error {
+	switch name {
+	case device.FieldTokenHash:
+		m.ClearTokenHash()
+		return nil
+	case device.FieldPairingCodeHash:
+		m.ClearPairingCodeHash()
+		return nil
+	case device.FieldPairingExpiresAt:
+		m.ClearPairingExpiresAt()
+		return nil
+	case device.FieldPairedAt:
+		m.ClearPairedAt()
+		return nil
+	case device.FieldPrivacy:
+		m.ClearPrivacy()
+		return nil
+	case device.FieldRevokedAt:
+		m.ClearRevokedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown Device nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *DeviceMutation) ResetField(name string) error {
+	switch name {
+	case device.FieldName:
+		m.ResetName()
+		return nil
+	case device.FieldKind:
+		m.ResetKind()
+		return nil
+	case device.FieldBranch:
+		m.ResetBranch()
+		return nil
+	case device.FieldTokenHash:
+		m.ResetTokenHash()
+		return nil
+	case device.FieldPairingCodeHash:
+		m.ResetPairingCodeHash()
+		return nil
+	case device.FieldPairingExpiresAt:
+		m.ResetPairingExpiresAt()
+		return nil
+	case device.FieldPairedAt:
+		m.ResetPairedAt()
+		return nil
+	case device.FieldPrivacy:
+		m.ResetPrivacy()
+		return nil
+	case device.FieldRevokedAt:
+		m.ResetRevokedAt()
+		return nil
+	case device.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown Device field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *DeviceMutation) AddedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *DeviceMutation) AddedIDs(name string) []ent.Value {
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *DeviceMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *DeviceMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *DeviceMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *DeviceMutation) EdgeCleared(name string) bool {
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *DeviceMutation) ClearEdge(name string) error {
+	return fmt.Errorf("unknown Device unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *DeviceMutation) ResetEdge(name string) error {
+	return fmt.Errorf("unknown Device edge %s", name)
+}
+
+// GuideMutation represents an operation that mutates the Guide nodes in the graph.
+type GuideMutation struct {
+	config
+	op                   Op
+	typ                  string
+	id                   *int
+	via_guide_id         *string
+	recipient            *string
+	recipient_normalized *string
+	status               *string
+	status_reason        *string
+	payment              *string
+	via_snapshot         *model.ViaGuide
+	via_synced_at        *time.Time
+	created_at           *time.Time
+	updated_at           *time.Time
+	clearedFields        map[string]struct{}
+	operator             *int
+	clearedoperator      bool
+	history              map[int]struct{}
+	removedhistory       map[int]struct{}
+	clearedhistory       bool
+	done                 bool
+	oldValue             func(context.Context) (*Guide, error)
+	predicates           []predicate.Guide
+}
+
+var _ ent.Mutation = (*GuideMutation)(nil)
+
+// guideOption allows management of the mutation configuration using functional options.
+type guideOption func(*GuideMutation)
+
+// newGuideMutation creates new mutation for the Guide entity.
+func newGuideMutation(c config, op Op, opts ...guideOption) *GuideMutation {
+	m := &GuideMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeGuide,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withGuideID sets the ID field of the mutation.
+func withGuideID(id int) guideOption {
+	return func(m *GuideMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *Guide
+		)
+		m.oldValue = func(ctx context.Context) (*Guide, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().Guide.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withGuide sets the old Guide of the mutation.
+func withGuide(node *Guide) guideOption {
+	return func(m *GuideMutation) {
+		m.oldValue = func(context.Context) (*Guide, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m GuideMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m GuideMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *GuideMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *GuideMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Guide.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetViaGuideID sets the "via_guide_id" field.
+func (m *GuideMutation) SetViaGuideID(s string) {
+	m.via_guide_id = &s
+}
+
+// ViaGuideID returns the value of the "via_guide_id" field in the mutation.
+func (m *GuideMutation) ViaGuideID() (r string, exists bool) {
+	v := m.via_guide_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldViaGuideID returns the old "via_guide_id" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldViaGuideID(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldViaGuideID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldViaGuideID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldViaGuideID: %w", err)
+	}
+	return oldValue.ViaGuideID, nil
+}
+
+// ResetViaGuideID resets all changes to the "via_guide_id" field.
+func (m *GuideMutation) ResetViaGuideID() {
+	m.via_guide_id = nil
+}
+
+// SetRecipient sets the "recipient" field.
+func (m *GuideMutation) SetRecipient(s string) {
+	m.recipient = &s
+}
+
+// Recipient returns the value of the "recipient" field in the mutation.
+func (m *GuideMutation) Recipient() (r string, exists bool) {
+	v := m.recipient
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRecipient returns the old "recipient" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldRecipient(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRecipient is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRecipient requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRecipient: %w", err)
+	}
+	return oldValue.Recipient, nil
+}
+
+// ResetRecipient resets all changes to the "recipient" field.
+func (m *GuideMutation) ResetRecipient() {
+	m.recipient = nil
+}
+
+// SetRecipientNormalized sets the "recipient_normalized" field.
+func (m *GuideMutation) SetRecipientNormalized(s string) {
+	m.recipient_normalized = &s
+}
+
+// RecipientNormalized returns the value of the "recipient_normalized" field in the mutation.
+func (m *GuideMutation) RecipientNormalized() (r string, exists bool) {
+	v := m.recipient_normalized
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRecipientNormalized returns the old "recipient_normalized" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldRecipientNormalized(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRecipientNormalized is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRecipientNormalized requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRecipientNormalized: %w", err)
+	}
+	return oldValue.RecipientNormalized, nil
+}
+
+// ResetRecipientNormalized resets all changes to the "recipient_normalized" field.
+func (m *GuideMutation) ResetRecipientNormalized() {
+	m.recipient_normalized = nil
+}
+
+// SetStatus sets the "status" field.
+func (m *GuideMutation) SetStatus(s string) {
+	m.status = &s
+}
+
+// Status returns the value of the "status" field in the mutation.
+func (m *GuideMutation) Status() (r string, exists bool) {
+	v := m.status
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldStatus returns the old "status" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldStatus(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldStatus requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+	}
+	return oldValue.Status, nil
+}
+
+// ResetStatus resets all changes to the "status" field.
+func (m *GuideMutation) ResetStatus() {
+	m.status = nil
+}
+
+// SetStatusReason sets the "status_reason" field.
+func (m *GuideMutation) SetStatusReason(s string) {
+	m.status_reason = &s
+}
+
+// StatusReason returns the value of the "status_reason" field in the mutation.
+func (m *GuideMutation) StatusReason() (r string, exists bool) {
+	v := m.status_reason
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldStatusReason returns the old "status_reason" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldStatusReason(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldStatusReason is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldStatusReason requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldStatusReason: %w", err)
+	}
+	return oldValue.StatusReason, nil
+}
+
+// ClearStatusReason clears the value of the "status_reason" field.
+func (m *GuideMutation) ClearStatusReason() {
+	m.status_reason = nil
+	m.clearedFields[guide.FieldStatusReason] = struct{}{}
+}
+
+// StatusReasonCleared returns if the "status_reason" field was cleared in this mutation.
+func (m *GuideMutation) StatusReasonCleared() bool {
+	_, ok := m.clearedFields[guide.FieldStatusReason]
+	return ok
+}
+
+// ResetStatusReason resets all changes to the "status_reason" field.
+func (m *GuideMutation) ResetStatusReason() {
+	m.status_reason = nil
+	delete(m.clearedFields, guide.FieldStatusReason)
+}
+
+// SetPayment sets the "payment" field.
+func (m *GuideMutation) SetPayment(s string) {
+	m.payment = &s
+}
+
+// Payment returns the value of the "payment" field in the mutation.
+func (m *GuideMutation) Payment() (r string, exists bool) {
+	v := m.payment
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPayment returns the old "payment" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldPayment(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPayment is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPayment requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPayment: %w", err)
+	}
+	return oldValue.Payment, nil
+}
+
+// ResetPayment resets all changes to the "payment" field.
+func (m *GuideMutation) ResetPayment() {
+	m.payment = nil
+}
+
+// SetOperatorID sets the "operator_id" field.
+func (m *GuideMutation) SetOperatorID(i int) {
+	m.operator = &i
+}
+
+// OperatorID returns the value of the "operator_id" field in the mutation.
+func (m *GuideMutation) OperatorID() (r int, exists bool) {
+	v := m.operator
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldOperatorID returns the old "operator_id" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldOperatorID(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldOperatorID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldOperatorID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldOperatorID: %w", err)
+	}
+	return oldValue.OperatorID, nil
+}
+
+// ResetOperatorID resets all changes to the "operator_id" field.
+func (m *GuideMutation) ResetOperatorID() {
+	m.operator = nil
+}
+
+// SetViaSnapshot sets the "via_snapshot" field.
+func (m *GuideMutation) SetViaSnapshot(mg model.ViaGuide) {
+	m.via_snapshot = &mg
+}
+
+// ViaSnapshot returns the value of the "via_snapshot" field in the mutation.
+func (m *GuideMutation) ViaSnapshot() (r model.ViaGuide, exists bool) {
+	v := m.via_snapshot
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldViaSnapshot returns the old "via_snapshot" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldViaSnapshot(ctx context.Context) (v model.ViaGuide, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldViaSnapshot is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldViaSnapshot requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldViaSnapshot: %w", err)
+	}
+	return oldValue.ViaSnapshot, nil
+}
+
+// ClearViaSnapshot clears the value of the "via_snapshot" field.
+func (m *GuideMutation) ClearViaSnapshot() {
+	m.via_snapshot = nil
+	m.clearedFields[guide.FieldViaSnapshot] = struct{}{}
+}
+
+// ViaSnapshotCleared returns if the "via_snapshot" field was cleared in this mutation.
+func (m *GuideMutation) ViaSnapshotCleared() bool {
+	_, ok := m.clearedFields[guide.FieldViaSnapshot]
+	return ok
+}
+
+// ResetViaSnapshot resets all changes to the "via_snapshot" field.
+func (m *GuideMutation) ResetViaSnapshot() {
+	m.via_snapshot = nil
+	delete(m.clearedFields, guide.FieldViaSnapshot)
+}
+
+// SetViaSyncedAt sets the "via_synced_at" field.
+func (m *GuideMutation) SetViaSyncedAt(t time.Time) {
+	m.via_synced_at = &t
+}
+
+// ViaSyncedAt returns the value of the "via_synced_at" field in the mutation.
+func (m *GuideMutation) ViaSyncedAt() (r time.Time, exists bool) {
+	v := m.via_synced_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldViaSyncedAt returns the old "via_synced_at" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldViaSyncedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldViaSyncedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldViaSyncedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldViaSyncedAt: %w", err)
+	}
+	return oldValue.ViaSyncedAt, nil
+}
+
+// ClearViaSyncedAt clears the value of the "via_synced_at" field.
+func (m *GuideMutation) ClearViaSyncedAt() {
+	m.via_synced_at = nil
+	m.clearedFields[guide.FieldViaSyncedAt] = struct{}{}
+}
+
+// ViaSyncedAtCleared returns if the "via_synced_at" field was cleared in this mutation.
+func (m *GuideMutation) ViaSyncedAtCleared() bool {
+	_, ok := m.clearedFields[guide.FieldViaSyncedAt]
+	return ok
+}
+
+// ResetViaSyncedAt resets all changes to the "via_synced_at" field.
+func (m *GuideMutation) ResetViaSyncedAt() {
+	m.via_synced_at = nil
+	delete(m.clearedFields, guide.FieldViaSyncedAt)
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *GuideMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *GuideMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *GuideMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (m *GuideMutation) SetUpdatedAt(t time.Time) {
+	m.updated_at = &t
+}
+
+// UpdatedAt returns the value of the "updated_at" field in the mutation.
+func (m *GuideMutation) UpdatedAt() (r time.Time, exists bool) {
+	v := m.updated_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldUpdatedAt returns the old "updated_at" field's value of the Guide entity.
+// If the Guide object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+	}
+	return oldValue.UpdatedAt, nil
+}
+
+// ResetUpdatedAt resets all changes to the "updated_at" field.
+func (m *GuideMutation) ResetUpdatedAt() {
+	m.updated_at = nil
+}
+
+// ClearOperator clears the "operator" edge to the Operator entity.
+func (m *GuideMutation) ClearOperator() {
+	m.clearedoperator = true
+	m.clearedFields[guide.FieldOperatorID] = struct{}{}
+}
+
+// OperatorCleared reports if the "operator" edge to the Operator entity was cleared.
+func (m *GuideMutation) OperatorCleared() bool {
+	return m.clearedoperator
+}
+
+// OperatorIDs returns the "operator" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// OperatorID instead. It exists only for internal usage by the builders.
+func (m *GuideMutation) OperatorIDs() (ids []int) {
+	if id := m.operator; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetOperator resets all changes to the "operator" edge.
+func (m *GuideMutation) ResetOperator() {
+	m.operator = nil
+	m.clearedoperator = false
+}
+
+// AddHistoryIDs adds the "history" edge to the GuideHistory entity by ids.
+func (m *GuideMutation) AddHistoryIDs(ids ...int) {
+	if m.history == nil {
+		m.history = make(map[int]struct{})
+	}
+	for i := range ids {
+		m.history[ids[i]] = struct{}{}
+	}
+}
+
+// ClearHistory clears the "history" edge to the GuideHistory entity.
+func (m *GuideMutation) ClearHistory() {
+	m.clearedhistory = true
+}
+
+// HistoryCleared reports if the "history" edge to the GuideHistory entity was cleared.
+func (m *GuideMutation) HistoryCleared() bool {
+	return m.clearedhistory
+}
+
+// RemoveHistoryIDs removes the "history" edge to the GuideHistory entity by IDs.
+func (m *GuideMutation) RemoveHistoryIDs(ids ...int) {
+	if m.removedhistory == nil {
+		m.removedhistory = make(map[int]struct{})
+	}
+	for i := range ids {
+		delete(m.history, ids[i])
+		m.removedhistory[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedHistory returns the removed IDs of the "history" edge to the GuideHistory entity.
+func (m *GuideMutation) RemovedHistoryIDs() (ids []int) {
+	for id := range m.removedhistory {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// HistoryIDs returns the "history" edge IDs in the mutation.
+func (m *GuideMutation) HistoryIDs() (ids []int) {
+	for id := range m.history {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ResetHistory resets all changes to the "history" edge.
+func (m *GuideMutation) ResetHistory() {
+	m.history = nil
+	m.clearedhistory = false
+	m.removedhistory = nil
+}
+
+// Where appends a list predicates to the GuideMutation builder.
+func (m *GuideMutation) Where(ps ...predicate.Guide) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the GuideMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *GuideMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Guide, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *GuideMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *GuideMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (Guide).
+func (m *GuideMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *GuideMutation) Fields() []string {
+	fields := make([]string, 0, 11)
+	if m.via_guide_id != nil {
+		fields = append(fields, guide.FieldViaGuideID)
+	}
+	if m.recipient != nil {
+		fields = append(fields, guide.FieldRecipient)
+	}
+	if m.recipient_normalized != nil {
+		fields = append(fields, guide.FieldRecipientNormalized)
+	}
+	if m.status != nil {
+		fields = append(fields, guide.FieldStatus)
+	}
+	if m.status_reason != nil {
+		fields = append(fields, guide.FieldStatusReason)
+	}
+	if m.payment != nil {
+		fields = append(fields, guide.FieldPayment)
+	}
+	if m.operator != nil {
+		fields = append(fields, guide.FieldOperatorID)
+	}
+	if m.via_snapshot != nil {
+		fields = append(fields, guide.FieldViaSnapshot)
+	}
+	if m.via_synced_at != nil {
+		fields = append(fields, guide.FieldViaSyncedAt)
+	}
+	if m.created_at != nil {
+		fields = append(fields, guide.FieldCreatedAt)
+	}
+	if m.updated_at != nil {
+		fields = append(fields, guide.FieldUpdatedAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *GuideMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case guide.FieldViaGuideID:
+		return m.ViaGuideID()
+	case guide.FieldRecipient:
+		return m.Recipient()
+	case guide.FieldRecipientNormalized:
+		return m.RecipientNormalized()
+	case guide.FieldStatus:
+		return m.Status()
+	case guide.FieldStatusReason:
+		return m.StatusReason()
+	case guide.FieldPayment:
+		return m.Payment()
+	case guide.FieldOperatorID:
+		return m.OperatorID()
+	case guide.FieldViaSnapshot:
+		return m.ViaSnapshot()
+	case guide.FieldViaSyncedAt:
+		return m.ViaSyncedAt()
+	case guide.FieldCreatedAt:
+		return m.CreatedAt()
+	case guide.FieldUpdatedAt:
+		return m.UpdatedAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *GuideMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case guide.FieldViaGuideID:
+		return m.OldViaGuideID(ctx)
+	case guide.FieldRecipient:
+		return m.OldRecipient(ctx)
+	case guide.FieldRecipientNormalized:
+		return m.OldRecipientNormalized(ctx)
+	case guide.FieldStatus:
+		return m.OldStatus(ctx)
+	case guide.FieldStatusReason:
+		return m.OldStatusReason(ctx)
+	case guide.FieldPayment:
+		return m.OldPayment(ctx)
+	case guide.FieldOperatorID:
+		return m.OldOperatorID(ctx)
+	case guide.FieldViaSnapshot:
+		return m.OldViaSnapshot(ctx)
+	case guide.FieldViaSyncedAt:
+		return m.OldViaSyncedAt(ctx)
+	case guide.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	case guide.FieldUpdatedAt:
+		return m.OldUpdatedAt(ctx)
+	}
+	return nil, fmt.Errorf("unknown Guide field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *GuideMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case guide.FieldViaGuideID:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetViaGuideID(v)
+		return nil
+	case guide.FieldRecipient:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRecipient(v)
+		return nil
+	case guide.FieldRecipientNormalized:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRecipientNormalized(v)
+		return nil
+	case guide.FieldStatus:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetStatus(v)
+		return nil
+	case guide.FieldStatusReason:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetStatusReason(v)
+		return nil
+	case guide.FieldPayment:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPayment(v)
+		return nil
+	case guide.FieldOperatorID:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetOperatorID(v)
+		return nil
+	case guide.FieldViaSnapshot:
+		v, ok := value.(model.ViaGuide)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetViaSnapshot(v)
+		return nil
+	case guide.FieldViaSyncedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetViaSyncedAt(v)
+		return nil
+	case guide.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedAt(v)
+		return nil
+	case guide.FieldUpdatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUpdatedAt(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Guide field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *GuideMutation) AddedFields() []string {
+	var fields []string
+	return fields
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *GuideMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	}
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *GuideMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown Guide numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *GuideMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(guide.FieldStatusReason) {
+		fields = append(fields, guide.FieldStatusReason)
+	}
+	if m.FieldCleared(guide.FieldViaSnapshot) {
+		fields = append(fields, guide.FieldViaSnapshot)
+	}
+	if m.FieldCleared(guide.FieldViaSyncedAt) {
+		fields = append(fields, guide.FieldViaSyncedAt)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *GuideMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *GuideMutation) ClearField(name string) error {
+	switch name {
+	case guide.FieldStatusReason:
+		m.ClearStatusReason()
+		return nil
+	case guide.FieldViaSnapshot:
+		m.ClearViaSnapshot()
+		return nil
+	case guide.FieldViaSyncedAt:
+		m.ClearViaSyncedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown Guide nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *GuideMutation) ResetField(name string) error {
+	switch name {
+	case guide.FieldViaGuideID:
+		m.ResetViaGuideID()
+		return nil
+	case guide.FieldRecipient:
+		m.ResetRecipient()
+		return nil
+	case guide.FieldRecipientNormalized:
+		m.ResetRecipientNormalized()
+		return nil
+	case guide.FieldStatus:
+		m.ResetStatus()
+		return nil
+	case guide.FieldStatusReason:
+		m.ResetStatusReason()
+		return nil
+	case guide.FieldPayment:
+		m.ResetPayment()
+		return nil
+	case guide.FieldOperatorID:
+		m.ResetOperatorID()
+		return nil
+	case guide.FieldViaSnapshot:
+		m.ResetViaSnapshot()
+		return nil
+	case guide.FieldViaSyncedAt:
+		m.ResetViaSyncedAt()
+		return nil
+	case guide.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	case guide.FieldUpdatedAt:
+		m.ResetUpdatedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown Guide field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *GuideMutation) AddedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.operator != nil {
+		edges = append(edges, guide.EdgeOperator)
+	}
+	if m.history != nil {
+		edges = append(edges, guide.EdgeHistory)
+	}
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *GuideMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case guide.EdgeOperator:
+		if id := m.operator; id != nil {
+			return []ent.Value{*id}
+		}
+	case guide.EdgeHistory:
+		ids := make([]ent.Value, 0, len(m.history))
+		for id := range m.history {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *GuideMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.removedhistory != nil {
+		edges = append(edges, guide.EdgeHistory)
+	}
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *GuideMutation) RemovedIDs(name string) []ent.Value {
+	switch name {
+	case guide.EdgeHistory:
+		ids := make([]ent.Value, 0, len(m.removedhistory))
+		for id := range m.removedhistory {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *GuideMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.clearedoperator {
+		edges = append(edges, guide.EdgeOperator)
+	}
+	if m.clearedhistory {
+		edges = append(edges, guide.EdgeHistory)
+	}
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *GuideMutation) EdgeCleared(name string) bool {
+	switch name {
+	case guide.EdgeOperator:
+		return m.clearedoperator
+	case guide.EdgeHistory:
+		return m.clearedhistory
+	}
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *GuideMutation) ClearEdge(name string) error {
+	switch name {
+	case guide.EdgeOperator:
+		m.ClearOperator()
+		return nil
+	}
+	return fmt.Errorf("unknown Guide unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *GuideMutation) ResetEdge(name string) error {
+	switch name {
+	case guide.EdgeOperator:
+		m.ResetOperator()
+		return nil
+	case guide.EdgeHistory:
+		m.ResetHistory()
+		return nil
+	}
+	return fmt.Errorf("unknown Guide edge %s", name)
+}
+
+// GuideHistoryMutation represents an operation that mutates the GuideHistory nodes in the graph.
+type GuideHistoryMutation struct {
+	config
+	op              Op
+	typ             string
+	id              *int
+	status          *string
+	reason          *string
+	created_at      *time.Time
+	clearedFields   map[string]struct{}
+	guide           *int
+	clearedguide    bool
+	operator        *int
+	clearedoperator bool
+	done            bool
+	oldValue        func(context.Context) (*GuideHistory, error)
+	predicates      []predicate.GuideHistory
+}
+
+var _ ent.Mutation = (*GuideHistoryMutation)(nil)
+
+// guidehistoryOption allows management of the mutation configuration using functional options.
+type guidehistoryOption func(*GuideHistoryMutation)
+
+// newGuideHistoryMutation creates new mutation for the GuideHistory entity.
+func newGuideHistoryMutation(c config, op Op, opts ...guidehistoryOption) *GuideHistoryMutation {
+	m := &GuideHistoryMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeGuideHistory,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withGuideHistoryID sets the ID field of the mutation.
+func withGuideHistoryID(id int) guidehistoryOption {
+	return func(m *GuideHistoryMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *GuideHistory
+		)
+		m.oldValue = func(ctx context.Context) (*GuideHistory, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().GuideHistory.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withGuideHistory sets the old GuideHistory of the mutation.
+func withGuideHistory(node *GuideHistory) guidehistoryOption {
+	return func(m *GuideHistoryMutation) {
+		m.oldValue = func(context.Context) (*GuideHistory, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m GuideHistoryMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m GuideHistoryMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *GuideHistoryMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *GuideHistoryMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().GuideHistory.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetGuideID sets the "guide_id" field.
+func (m *GuideHistoryMutation) SetGuideID(i int) {
+	m.guide = &i
+}
+
+// GuideID returns the value of the "guide_id" field in the mutation.
+func (m *GuideHistoryMutation) GuideID() (r int, exists bool) {
+	v := m.guide
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldGuideID returns the old "guide_id" field's value of the GuideHistory entity.
+// If the GuideHistory object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideHistoryMutation) OldGuideID(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldGuideID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldGuideID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldGuideID: %w", err)
+	}
+	return oldValue.GuideID, nil
+}
+
+// ResetGuideID resets all changes to the "guide_id" field.
+func (m *GuideHistoryMutation) ResetGuideID() {
+	m.guide = nil
+}
+
+// SetStatus sets the "status" field.
+func (m *GuideHistoryMutation) SetStatus(s string) {
+	m.status = &s
+}
+
+// Status returns the value of the "status" field in the mutation.
+func (m *GuideHistoryMutation) Status() (r string, exists bool) {
+	v := m.status
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldStatus returns the old "status" field's value of the GuideHistory entity.
+// If the GuideHistory object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideHistoryMutation) OldStatus(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldStatus requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+	}
+	return oldValue.Status, nil
+}
+
+// ResetStatus resets all changes to the "status" field.
+func (m *GuideHistoryMutation) ResetStatus() {
+	m.status = nil
+}
+
+// SetReason sets the "reason" field.
+func (m *GuideHistoryMutation) SetReason(s string) {
+	m.reason = &s
+}
+
+// Reason returns the value of the "reason" field in the mutation.
+func (m *GuideHistoryMutation) Reason() (r string, exists bool) {
+	v := m.reason
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldReason returns the old "reason" field's value of the GuideHistory entity.
+// If the GuideHistory object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideHistoryMutation) OldReason(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldReason is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldReason requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldReason: %w", err)
+	}
+	return oldValue.Reason, nil
+}
+
+// ClearReason clears the value of the "reason" field.
+func (m *GuideHistoryMutation) ClearReason() {
+	m.reason = nil
+	m.clearedFields[guidehistory.FieldReason] = struct{}{}
+}
+
+// ReasonCleared returns if the "reason" field was cleared in this mutation.
+func (m *GuideHistoryMutation) ReasonCleared() bool {
+	_, ok := m.clearedFields[guidehistory.FieldReason]
+	return ok
+}
+
+// ResetReason resets all changes to the "reason" field.
+func (m *GuideHistoryMutation) ResetReason() {
+	m.reason = nil
+	delete(m.clearedFields, guidehistory.FieldReason)
+}
+
+// SetOperatorID sets the "operator_id" field.
+func (m *GuideHistoryMutation) SetOperatorID(i int) {
+	m.operator = &i
+}
+
+// OperatorID returns the value of the "operator_id" field in the mutation.
+func (m *GuideHistoryMutation) OperatorID() (r int, exists bool) {
+	v := m.operator
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldOperatorID returns the old "operator_id" field's value of the GuideHistory entity.
+// If the GuideHistory object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideHistoryMutation) OldOperatorID(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldOperatorID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldOperatorID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldOperatorID: %w", err)
+	}
+	return oldValue.OperatorID, nil
+}
+
+// ResetOperatorID resets all changes to the "operator_id" field.
+func (m *GuideHistoryMutation) ResetOperatorID() {
+	m.operator = nil
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *GuideHistoryMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *GuideHistoryMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the GuideHistory entity.
+// If the GuideHistory object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GuideHistoryMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *GuideHistoryMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// ClearGuide clears the "guide" edge to the Guide entity.
+func (m *GuideHistoryMutation) ClearGuide() {
+	m.clearedguide = true
+	m.clearedFields[guidehistory.FieldGuideID] = struct{}{}
+}
+
+// GuideCleared reports if the "guide" edge to the Guide entity was cleared.
+func (m *GuideHistoryMutation) GuideCleared() bool {
+	return m.clearedguide
+}
+
+// GuideIDs returns the "guide" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// GuideID instead. It exists only for internal usage by the builders.
+func (m *GuideHistoryMutation) GuideIDs() (ids []int) {
+	if id := m.guide; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetGuide resets all changes to the "guide" edge.
+func (m *GuideHistoryMutation) ResetGuide() {
+	m.guide = nil
+	m.clearedguide = false
+}
+
+// ClearOperator clears the "operator" edge to the Operator entity.
+func (m *GuideHistoryMutation) ClearOperator() {
+	m.clearedoperator = true
+	m.clearedFields[guidehistory.FieldOperatorID] = struct{}{}
+}
+
+// OperatorCleared reports if the "operator" edge to the Operator entity was cleared.
+func (m *GuideHistoryMutation) OperatorCleared() bool {
+	return m.clearedoperator
+}
+
+// OperatorIDs returns the "operator" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// OperatorID instead. It exists only for internal usage by the builders.
+func (m *GuideHistoryMutation) OperatorIDs() (ids []int) {
+	if id := m.operator; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetOperator resets all changes to the "operator" edge.
+func (m *GuideHistoryMutation) ResetOperator() {
+	m.operator = nil
+	m.clearedoperator = false
+}
+
+// Where appends a list predicates to the GuideHistoryMutation builder.
+func (m *GuideHistoryMutation) Where(ps ...predicate.GuideHistory) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the GuideHistoryMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *GuideHistoryMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.GuideHistory, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *GuideHistoryMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *GuideHistoryMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (GuideHistory).
+func (m *GuideHistoryMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *GuideHistoryMutation) Fields() []string {
+	fields := make([]string, 0, 5)
+	if m.guide != nil {
+		fields = append(fields, guidehistory.FieldGuideID)
+	}
+	if m.status != nil {
+		fields = append(fields, guidehistory.FieldStatus)
+	}
+	if m.reason != nil {
+		fields = append(fields, guidehistory.FieldReason)
+	}
+	if m.operator != nil {
+		fields = append(fields, guidehistory.FieldOperatorID)
+	}
+	if m.created_at != nil {
+		fields = append(fields, guidehistory.FieldCreatedAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *GuideHistoryMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case guidehistory.FieldGuideID:
+		return m.GuideID()
+	case guidehistory.FieldStatus:
+		return m.Status()
+	case guidehistory.FieldReason:
+		return m.Reason()
+	case guidehistory.FieldOperatorID:
+		return m.OperatorID()
+	case guidehistory.FieldCreatedAt:
+		return m.CreatedAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
 func (m *GuideHistoryMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case guidehistory.FieldGuideID:
-		return m.OldGuideID(ctx)
-	case guidehistory.FieldStatus:
-		return m.OldStatus(ctx)
-	case guidehistory.FieldOperatorID:
-		return m.OldOperatorID(ctx)
-	case guidehistory.FieldCreatedAt:
+	case guidehistory.FieldGuideID:
+		return m.OldGuideID(ctx)
+	case guidehistory.FieldStatus:
+		return m.OldStatus(ctx)
+	case guidehistory.FieldReason:
+		return m.OldReason(ctx)
+	case guidehistory.FieldOperatorID:
+		return m.OldOperatorID(ctx)
+	case guidehistory.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	}
+	return nil, fmt.Errorf("unknown GuideHistory field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *GuideHistoryMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case guidehistory.FieldGuideID:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetGuideID(v)
+		return nil
+	case guidehistory.FieldStatus:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetStatus(v)
+		return nil
+	case guidehistory.FieldReason:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetReason(v)
+		return nil
+	case guidehistory.FieldOperatorID:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetOperatorID(v)
+		return nil
+	case guidehistory.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedAt(v)
+		return nil
+	}
+	return fmt.Errorf("unknown GuideHistory field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *GuideHistoryMutation) AddedFields() []string {
+	var fields []string
+	return fields
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *GuideHistoryMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	}
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *GuideHistoryMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown GuideHistory numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *GuideHistoryMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(guidehistory.FieldReason) {
+		fields = append(fields, guidehistory.FieldReason)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *GuideHistoryMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *GuideHistoryMutation) ClearField(name string) error {
+	switch name {
+	case guidehistory.FieldReason:
+		m.ClearReason()
+		return nil
+	}
+	return fmt.Errorf("unknown GuideHistory nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *GuideHistoryMutation) ResetField(name string) error {
+	switch name {
+	case guidehistory.FieldGuideID:
+		m.ResetGuideID()
+		return nil
+	case guidehistory.FieldStatus:
+		m.ResetStatus()
+		return nil
+	case guidehistory.FieldReason:
+		m.ResetReason()
+		return nil
+	case guidehistory.FieldOperatorID:
+		m.ResetOperatorID()
+		return nil
+	case guidehistory.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown GuideHistory field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *GuideHistoryMutation) AddedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.guide != nil {
+		edges = append(edges, guidehistory.EdgeGuide)
+	}
+	if m.operator != nil {
+		edges = append(edges, guidehistory.EdgeOperator)
+	}
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *GuideHistoryMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case guidehistory.EdgeGuide:
+		if id := m.guide; id != nil {
+			return []ent.Value{*id}
+		}
+	case guidehistory.EdgeOperator:
+		if id := m.operator; id != nil {
+			return []ent.Value{*id}
+		}
+	}
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *GuideHistoryMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 2)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *GuideHistoryMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *GuideHistoryMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.clearedguide {
+		edges = append(edges, guidehistory.EdgeGuide)
+	}
+	if m.clearedoperator {
+		edges = append(edges, guidehistory.EdgeOperator)
+	}
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *GuideHistoryMutation) EdgeCleared(name string) bool {
+	switch name {
+	case guidehistory.EdgeGuide:
+		return m.clearedguide
+	case guidehistory.EdgeOperator:
+		return m.clearedoperator
+	}
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *GuideHistoryMutation) ClearEdge(name string) error {
+	switch name {
+	case guidehistory.EdgeGuide:
+		m.ClearGuide()
+		return nil
+	case guidehistory.EdgeOperator:
+		m.ClearOperator()
+		return nil
+	}
+	return fmt.Errorf("unknown GuideHistory unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *GuideHistoryMutation) ResetEdge(name string) error {
+	switch name {
+	case guidehistory.EdgeGuide:
+		m.ResetGuide()
+		return nil
+	case guidehistory.EdgeOperator:
+		m.ResetOperator()
+		return nil
+	}
+	return fmt.Errorf("unknown GuideHistory edge %s", name)
+}
+
+// OperatorMutation represents an operation that mutates the Operator nodes in the graph.
+type OperatorMutation struct {
+	config
+	op                   Op
+	typ                  string
+	id                   *int
+	account              *string
+	name                 *string
+	enabled              *bool
+	role                 *string
+	availability         *string
+	password_hash        *string
+	totp_secret          *string
+	created_at           *time.Time
+	updated_at           *time.Time
+	clearedFields        map[string]struct{}
+	guides               map[int]struct{}
+	removedguides        map[int]struct{}
+	clearedguides        bool
+	guide_history        map[int]struct{}
+	removedguide_history map[int]struct{}
+	clearedguide_history bool
+	identities           map[int]struct{}
+	removedidentities    map[int]struct{}
+	clearedidentities    bool
+	done                 bool
+	oldValue             func(context.Context) (*Operator, error)
+	predicates           []predicate.Operator
+}
+
+var _ ent.Mutation = (*OperatorMutation)(nil)
+
+// operatorOption allows management of the mutation configuration using functional options.
+type operatorOption func(*OperatorMutation)
+
+// newOperatorMutation creates new mutation for the Operator entity.
+func newOperatorMutation(c config, op Op, opts ...operatorOption) *OperatorMutation {
+	m := &OperatorMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeOperator,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withOperatorID sets the ID field of the mutation.
+func withOperatorID(id int) operatorOption {
+	return func(m *OperatorMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *Operator
+		)
+		m.oldValue = func(ctx context.Context) (*Operator, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().Operator.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withOperator sets the old Operator of the mutation.
+func withOperator(node *Operator) operatorOption {
+	return func(m *OperatorMutation) {
+		m.oldValue = func(context.Context) (*Operator, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m OperatorMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m OperatorMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *OperatorMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *OperatorMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Operator.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetAccount sets the "account" field.
+func (m *OperatorMutation) SetAccount(s string) {
+	m.account = &s
+}
+
+// Account returns the value of the "account" field in the mutation.
+func (m *OperatorMutation) Account() (r string, exists bool) {
+	v := m.account
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAccount returns the old "account" field's value of the Operator entity.
+// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorMutation) OldAccount(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAccount is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAccount requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAccount: %w", err)
+	}
+	return oldValue.Account, nil
+}
+
+// ResetAccount resets all changes to the "account" field.
+func (m *OperatorMutation) ResetAccount() {
+	m.account = nil
+}
+
+// SetName sets the "name" field.
+func (m *OperatorMutation) SetName(s string) {
+	m.name = &s
+}
+
+// Name returns the value of the "name" field in the mutation.
+func (m *OperatorMutation) Name() (r string, exists bool) {
+	v := m.name
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldName returns the old "name" field's value of the Operator entity.
+// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorMutation) OldName(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldName is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldName requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldName: %w", err)
+	}
+	return oldValue.Name, nil
+}
+
+// ResetName resets all changes to the "name" field.
+func (m *OperatorMutation) ResetName() {
+	m.name = nil
+}
+
+// SetEnabled sets the "enabled" field.
+func (m *OperatorMutation) SetEnabled(b bool) {
+	m.enabled = &b
+}
+
+// Enabled returns the value of the "enabled" field in the mutation.
+func (m *OperatorMutation) Enabled() (r bool, exists bool) {
+	v := m.enabled
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldEnabled returns the old "enabled" field's value of the Operator entity.
+// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorMutation) OldEnabled(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldEnabled is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldEnabled requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldEnabled: %w", err)
+	}
+	return oldValue.Enabled, nil
+}
+
+// ResetEnabled resets all changes to the "enabled" field.
+func (m *OperatorMutation) ResetEnabled() {
+	m.enabled = nil
+}
+
+// SetRole sets the "role" field.
+func (m *OperatorMutation) SetRole(s string) {
+	m.role = &s
+}
+
+// Role returns the value of the "role" field in the mutation.
+func (m *OperatorMutation) Role() (r string, exists bool) {
+	v := m.role
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRole returns the old "role" field's value of the Operator entity.
+// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorMutation) OldRole(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRole is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRole requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRole: %w", err)
+	}
+	return oldValue.Role, nil
+}
+
+// ResetRole resets all changes to the "role" field.
+func (m *OperatorMutation) ResetRole() {
+	m.role = nil
+}
+
+// SetAvailability sets the "availability" field.
+func (m *OperatorMutation) SetAvailability(s string) {
+	m.availability = &s
+}
+
+// Availability returns the value of the "availability" field in the mutation.
+func (m *OperatorMutation) Availability() (r string, exists bool) {
+	v := m.availability
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAvailability returns the old "availability" field's value of the Operator entity.
+// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorMutation) OldAvailability(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAvailability is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAvailability requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAvailability: %w", err)
+	}
+	return oldValue.Availability, nil
+}
+
+// ResetAvailability resets all changes to the "availability" field.
+func (m *OperatorMutation) ResetAvailability() {
+	m.availability = nil
+}
+
+// SetPasswordHash sets the "password_hash" field.
+func (m *OperatorMutation) SetPasswordHash(s string) {
+	m.password_hash = &s
+}
+
+// PasswordHash returns the value of the "password_hash" field in the mutation.
+func (m *OperatorMutation) PasswordHash() (r string, exists bool) {
+	v := m.password_hash
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPasswordHash returns the old "password_hash" field's value of the Operator entity.
+// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorMutation) OldPasswordHash(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPasswordHash is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPasswordHash requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPasswordHash: %w", err)
+	}
+	return oldValue.PasswordHash, nil
+}
+
+// ClearPasswordHash clears the value of the "password_hash" field.
+func (m *OperatorMutation) ClearPasswordHash() {
+	m.password_hash = nil
+	m.clearedFields[operator.FieldPasswordHash] = struct{}{}
+}
+
+// PasswordHashCleared returns if the "password_hash" field was cleared in this mutation.
+func (m *OperatorMutation) PasswordHashCleared() bool {
+	_, ok := m.clearedFields[operator.FieldPasswordHash]
+	return ok
+}
+
+// ResetPasswordHash resets all changes to the "password_hash" field.
+func (m *OperatorMutation) ResetPasswordHash() {
+	m.password_hash = nil
+	delete(m.clearedFields, operator.FieldPasswordHash)
+}
+
+// SetTotpSecret sets the "totp_secret" field.
+func (m *OperatorMutation) SetTotpSecret(s string) {
+	m.totp_secret = &s
+}
+
+// TotpSecret returns the value of the "totp_secret" field in the mutation.
+func (m *OperatorMutation) TotpSecret() (r string, exists bool) {
+	v := m.totp_secret
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTotpSecret returns the old "totp_secret" field's value of the Operator entity.
+// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorMutation) OldTotpSecret(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTotpSecret is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTotpSecret requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTotpSecret: %w", err)
+	}
+	return oldValue.TotpSecret, nil
+}
+
+// ClearTotpSecret clears the value of the "totp_secret" field.
+func (m *OperatorMutation) ClearTotpSecret() {
+	m.totp_secret = nil
+	m.clearedFields[operator.FieldTotpSecret] = struct{}{}
+}
+
+// TotpSecretCleared returns if the "totp_secret" field was cleared in this mutation.
+func (m *OperatorMutation) TotpSecretCleared() bool {
+	_, ok := m.clearedFields[operator.FieldTotpSecret]
+	return ok
+}
+
+// ResetTotpSecret resets all changes to the "totp_secret" field.
+func (m *OperatorMutation) ResetTotpSecret() {
+	m.totp_secret = nil
+	delete(m.clearedFields, operator.FieldTotpSecret)
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *OperatorMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *OperatorMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the Operator entity.
+// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *OperatorMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (m *OperatorMutation) SetUpdatedAt(t time.Time) {
+	m.updated_at = &t
+}
+
+// UpdatedAt returns the value of the "updated_at" field in the mutation.
+func (m *OperatorMutation) UpdatedAt() (r time.Time, exists bool) {
+	v := m.updated_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldUpdatedAt returns the old "updated_at" field's value of the Operator entity.
+// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+	}
+	return oldValue.UpdatedAt, nil
+}
+
+// ResetUpdatedAt resets all changes to the "updated_at" field.
+func (m *OperatorMutation) ResetUpdatedAt() {
+	m.updated_at = nil
+}
+
+// AddGuideIDs adds the "guides" edge to the Guide entity by ids.
+func (m *OperatorMutation) AddGuideIDs(ids ...int) {
+	if m.guides == nil {
+		m.guides = make(map[int]struct{})
+	}
+	for i := range ids {
+		m.guides[ids[i]] = struct{}{}
+	}
+}
+
+// ClearGuides clears the "guides" edge to the Guide entity.
+func (m *OperatorMutation) ClearGuides() {
+	m.clearedguides = true
+}
+
+// GuidesCleared reports if the "guides" edge to the Guide entity was cleared.
+func (m *OperatorMutation) GuidesCleared() bool {
+	return m.clearedguides
+}
+
+// RemoveGuideIDs removes the "guides" edge to the Guide entity by IDs.
+func (m *OperatorMutation) RemoveGuideIDs(ids ...int) {
+	if m.removedguides == nil {
+		m.removedguides = make(map[int]struct{})
+	}
+	for i := range ids {
+		delete(m.guides, ids[i])
+		m.removedguides[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedGuides returns the removed IDs of the "guides" edge to the Guide entity.
+func (m *OperatorMutation) RemovedGuidesIDs() (ids []int) {
+	for id := range m.removedguides {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// GuidesIDs returns the "guides" edge IDs in the mutation.
+func (m *OperatorMutation) GuidesIDs() (ids []int) {
+	for id := range m.guides {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ResetGuides resets all changes to the "guides" edge.
+func (m *OperatorMutation) ResetGuides() {
+	m.guides = nil
+	m.clearedguides = false
+	m.removedguides = nil
+}
+
+// AddGuideHistoryIDs adds the "guide_history" edge to the GuideHistory entity by ids.
+func (m *OperatorMutation) AddGuideHistoryIDs(ids ...int) {
+	if m.guide_history == nil {
+		m.guide_history = make(map[int]struct{})
+	}
+	for i := range ids {
+		m.guide_history[ids[i]] = struct{}{}
+	}
+}
+
+// ClearGuideHistory clears the "guide_history" edge to the GuideHistory entity.
+func (m *OperatorMutation) ClearGuideHistory() {
+	m.clearedguide_history = true
+}
+
+// GuideHistoryCleared reports if the "guide_history" edge to the GuideHistory entity was cleared.
+func (m *OperatorMutation) GuideHistoryCleared() bool {
+	return m.clearedguide_history
+}
+
+// RemoveGuideHistoryIDs removes the "guide_history" edge to the GuideHistory entity by IDs.
+func (m *OperatorMutation) RemoveGuideHistoryIDs(ids ...int) {
+	if m.removedguide_history == nil {
+		m.removedguide_history = make(map[int]struct{})
+	}
+	for i := range ids {
+		delete(m.guide_history, ids[i])
+		m.removedguide_history[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedGuideHistory returns the removed IDs of the "guide_history" edge to the GuideHistory entity.
+func (m *OperatorMutation) RemovedGuideHistoryIDs() (ids []int) {
+	for id := range m.removedguide_history {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// GuideHistoryIDs returns the "guide_history" edge IDs in the mutation.
+func (m *OperatorMutation) GuideHistoryIDs() (ids []int) {
+	for id := range m.guide_history {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ResetGuideHistory resets all changes to the "guide_history" edge.
+func (m *OperatorMutation) ResetGuideHistory() {
+	m.guide_history = nil
+	m.clearedguide_history = false
+	m.removedguide_history = nil
+}
+
+// AddIdentityIDs adds the "identities" edge to the OperatorIdentity entity by ids.
+func (m *OperatorMutation) AddIdentityIDs(ids ...int) {
+	if m.identities == nil {
+		m.identities = make(map[int]struct{})
+	}
+	for i := range ids {
+		m.identities[ids[i]] = struct{}{}
+	}
+}
+
+// ClearIdentities clears the "identities" edge to the OperatorIdentity entity.
+func (m *OperatorMutation) ClearIdentities() {
+	m.clearedidentities = true
+}
+
+// IdentitiesCleared reports if the "identities" edge to the OperatorIdentity entity was cleared.
+func (m *OperatorMutation) IdentitiesCleared() bool {
+	return m.clearedidentities
+}
+
+// RemoveIdentityIDs removes the "identities" edge to the OperatorIdentity entity by IDs.
+func (m *OperatorMutation) RemoveIdentityIDs(ids ...int) {
+	if m.removedidentities == nil {
+		m.removedidentities = make(map[int]struct{})
+	}
+	for i := range ids {
+		delete(m.identities, ids[i])
+		m.removedidentities[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedIdentities returns the removed IDs of the "identities" edge to the OperatorIdentity entity.
+func (m *OperatorMutation) RemovedIdentitiesIDs() (ids []int) {
+	for id := range m.removedidentities {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// IdentitiesIDs returns the "identities" edge IDs in the mutation.
+func (m *OperatorMutation) IdentitiesIDs() (ids []int) {
+	for id := range m.identities {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ResetIdentities resets all changes to the "identities" edge.
+func (m *OperatorMutation) ResetIdentities() {
+	m.identities = nil
+	m.clearedidentities = false
+	m.removedidentities = nil
+}
+
+// Where appends a list predicates to the OperatorMutation builder.
+func (m *OperatorMutation) Where(ps ...predicate.Operator) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the OperatorMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *OperatorMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Operator, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *OperatorMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *OperatorMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (Operator).
+func (m *OperatorMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *OperatorMutation) Fields() []string {
+	fields := make([]string, 0, 9)
+	if m.account != nil {
+		fields = append(fields, operator.FieldAccount)
+	}
+	if m.name != nil {
+		fields = append(fields, operator.FieldName)
+	}
+	if m.enabled != nil {
+		fields = append(fields, operator.FieldEnabled)
+	}
+	if m.role != nil {
+		fields = append(fields, operator.FieldRole)
+	}
+	if m.availability != nil {
+		fields = append(fields, operator.FieldAvailability)
+	}
+	if m.password_hash != nil {
+		fields = append(fields, operator.FieldPasswordHash)
+	}
+	if m.totp_secret != nil {
+		fields = append(fields, operator.FieldTotpSecret)
+	}
+	if m.created_at != nil {
+		fields = append(fields, operator.FieldCreatedAt)
+	}
+	if m.updated_at != nil {
+		fields = append(fields, operator.FieldUpdatedAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *OperatorMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case operator.FieldAccount:
+		return m.Account()
+	case operator.FieldName:
+		return m.Name()
+	case operator.FieldEnabled:
+		return m.Enabled()
+	case operator.FieldRole:
+		return m.Role()
+	case operator.FieldAvailability:
+		return m.Availability()
+	case operator.FieldPasswordHash:
+		return m.PasswordHash()
+	case operator.FieldTotpSecret:
+		return m.TotpSecret()
+	case operator.FieldCreatedAt:
+		return m.CreatedAt()
+	case operator.FieldUpdatedAt:
+		return m.UpdatedAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *OperatorMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case operator.FieldAccount:
+		return m.OldAccount(ctx)
+	case operator.FieldName:
+		return m.OldName(ctx)
+	case operator.FieldEnabled:
+		return m.OldEnabled(ctx)
+	case operator.FieldRole:
+		return m.OldRole(ctx)
+	case operator.FieldAvailability:
+		return m.OldAvailability(ctx)
+	case operator.FieldPasswordHash:
+		return m.OldPasswordHash(ctx)
+	case operator.FieldTotpSecret:
+		return m.OldTotpSecret(ctx)
+	case operator.FieldCreatedAt:
 		return m.OldCreatedAt(ctx)
+	case operator.FieldUpdatedAt:
+		return m.OldUpdatedAt(ctx)
 	}
-	return nil, fmt.Errorf("unknown GuideHistory field %s", name)
+	return nil, fmt.Errorf("unknown Operator field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *GuideHistoryMutation) SetField(name string, value ent.Value) error {
+func (m *OperatorMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case guidehistory.FieldGuideID:
-		v, ok := value.(int)
+	case operator.FieldAccount:
+		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetGuideID(v)
+		m.SetAccount(v)
 		return nil
-	case guidehistory.FieldStatus:
+	case operator.FieldName:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetStatus(v)
+		m.SetName(v)
 		return nil
-	case guidehistory.FieldOperatorID:
-		v, ok := value.(int)
+	case operator.FieldEnabled:
+		v, ok := value.(bool)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetOperatorID(v)
+		m.SetEnabled(v)
 		return nil
-	case guidehistory.FieldCreatedAt:
+	case operator.FieldRole:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRole(v)
+		return nil
+	case operator.FieldAvailability:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAvailability(v)
+		return nil
+	case operator.FieldPasswordHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPasswordHash(v)
+		return nil
+	case operator.FieldTotpSecret:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTotpSecret(v)
+		return nil
+	case operator.FieldCreatedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetCreatedAt(v)
 		return nil
+	case operator.FieldUpdatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUpdatedAt(v)
+		return nil
 	}
-	return fmt.Errorf("unknown GuideHistory field %s", name)
+	return fmt.Errorf("unknown Operator field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *GuideHistoryMutation) AddedFields() []string {
-	var fields []string
-	return fields
+func (m *OperatorMutation) AddedFields() []string {
+	return nil
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *GuideHistoryMutation) AddedField(name string) (ent.Value, bool) {
-	switch name {
-	}
+func (m *OperatorMutation) AddedField(name string) (ent.Value, bool) {
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *GuideHistoryMutation) AddField(name string, value ent.Value) error {
+func (m *OperatorMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown GuideHistory numeric field %s", name)
+	return fmt.Errorf("unknown Operator numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *GuideHistoryMutation) ClearedFields() []string {
-	return nil
+func (m *OperatorMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(operator.FieldPasswordHash) {
+		fields = append(fields, operator.FieldPasswordHash)
+	}
+	if m.FieldCleared(operator.FieldTotpSecret) {
+		fields = append(fields, operator.FieldTotpSecret)
+	}
+	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *GuideHistoryMutation) FieldCleared(name string) bool {
+func (m *OperatorMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *GuideHistoryMutation) ClearField(name string) error {
-	return fmt.Errorf("unknown GuideHistory nullable field %s", name)
+func (m *OperatorMutation) ClearField(name string) error {
+	switch name {
+	case operator.FieldPasswordHash:
+		m.ClearPasswordHash()
+		return nil
+	case operator.FieldTotpSecret:
+		m.ClearTotpSecret()
+		return nil
+	}
+	return fmt.Errorf("unknown Operator nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *GuideHistoryMutation) ResetField(name string) error {
+func (m *OperatorMutation) ResetField(name string) error {
 	switch name {
-	case guidehistory.FieldGuideID:
-		m.ResetGuideID()
+	case operator.FieldAccount:
+		m.ResetAccount()
 		return nil
-	case guidehistory.FieldStatus:
-		m.ResetStatus()
+	case operator.FieldName:
+		m.ResetName()
 		return nil
-	case guidehistory.FieldOperatorID:
-		m.ResetOperatorID()
+	case operator.FieldEnabled:
+		m.ResetEnabled()
 		return nil
-	case guidehistory.FieldCreatedAt:
+	case operator.FieldRole:
+		m.ResetRole()
+		return nil
+	case operator.FieldAvailability:
+		m.ResetAvailability()
+		return nil
+	case operator.FieldPasswordHash:
+		m.ResetPasswordHash()
+		return nil
+	case operator.FieldTotpSecret:
+		m.ResetTotpSecret()
+		return nil
+	case operator.FieldCreatedAt:
 		m.ResetCreatedAt()
 		return nil
+	case operator.FieldUpdatedAt:
+		m.ResetUpdatedAt()
+		return nil
 	}
-	return fmt.Errorf("unknown GuideHistory field %s", name)
+	return fmt.Errorf("unknown Operator field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *GuideHistoryMutation) AddedEdges() []string {
-	edges := make([]string, 0, 2)
-	if m.guide != nil {
-		edges = append(edges, guidehistory.EdgeGuide)
+func (m *OperatorMutation) AddedEdges() []string {
+	edges := make([]string, 0, 3)
+	if m.guides != nil {
+		edges = append(edges, operator.EdgeGuides)
 	}
-	if m.operator != nil {
-		edges = append(edges, guidehistory.EdgeOperator)
+	if m.guide_history != nil {
+		edges = append(edges, operator.EdgeGuideHistory)
+	}
+	if m.identities != nil {
+		edges = append(edges, operator.EdgeIdentities)
 	}
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *GuideHistoryMutation) AddedIDs(name string) []ent.Value {
+func (m *OperatorMutation) AddedIDs(name string) []ent.Value {
 	switch name {
-	case guidehistory.EdgeGuide:
-		if id := m.guide; id != nil {
-			return []ent.Value{*id}
+	case operator.EdgeGuides:
+		ids := make([]ent.Value, 0, len(m.guides))
+		for id := range m.guides {
+			ids = append(ids, id)
 		}
-	case guidehistory.EdgeOperator:
-		if id := m.operator; id != nil {
-			return []ent.Value{*id}
+		return ids
+	case operator.EdgeGuideHistory:
+		ids := make([]ent.Value, 0, len(m.guide_history))
+		for id := range m.guide_history {
+			ids = append(ids, id)
 		}
+		return ids
+	case operator.EdgeIdentities:
+		ids := make([]ent.Value, 0, len(m.identities))
+		for id := range m.identities {
+			ids = append(ids, id)
+		}
+		return ids
 	}
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *GuideHistoryMutation) RemovedEdges() []string {
-	edges := make([]string, 0, 2)
+func (m *OperatorMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 3)
+	if m.removedguides != nil {
+		edges = append(edges, operator.EdgeGuides)
+	}
+	if m.removedguide_history != nil {
+		edges = append(edges, operator.EdgeGuideHistory)
+	}
+	if m.removedidentities != nil {
+		edges = append(edges, operator.EdgeIdentities)
+	}
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *GuideHistoryMutation) RemovedIDs(name string) []ent.Value {
+func (m *OperatorMutation) RemovedIDs(name string) []ent.Value {
+	switch name {
+	case operator.EdgeGuides:
+		ids := make([]ent.Value, 0, len(m.removedguides))
+		for id := range m.removedguides {
+			ids = append(ids, id)
+		}
+		return ids
+	case operator.EdgeGuideHistory:
+		ids := make([]ent.Value, 0, len(m.removedguide_history))
+		for id := range m.removedguide_history {
+			ids = append(ids, id)
+		}
+		return ids
+	case operator.EdgeIdentities:
+		ids := make([]ent.Value, 0, len(m.removedidentities))
+		for id := range m.removedidentities {
+			ids = append(ids, id)
+		}
+		return ids
+	}
 	return nil
-}
-
-// ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *GuideHistoryMutation) ClearedEdges() []string {
-	edges := make([]string, 0, 2)
-	if m.clearedguide {
-		edges = append(edges, guidehistory.EdgeGuide)
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *OperatorMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 3)
+	if m.clearedguides {
+		edges = append(edges, operator.EdgeGuides)
 	}
-	if m.clearedoperator {
-		edges = append(edges, guidehistory.EdgeOperator)
+	if m.clearedguide_history {
+		edges = append(edges, operator.EdgeGuideHistory)
+	}
+	if m.clearedidentities {
+		edges = append(edges, operator.EdgeIdentities)
 	}
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *GuideHistoryMutation) EdgeCleared(name string) bool {
+func (m *OperatorMutation) EdgeCleared(name string) bool {
 	switch name {
-	case guidehistory.EdgeGuide:
-		return m.clearedguide
-	case guidehistory.EdgeOperator:
-		return m.clearedoperator
+	case operator.EdgeGuides:
+		return m.clearedguides
+	case operator.EdgeGuideHistory:
+		return m.clearedguide_history
+	case operator.EdgeIdentities:
+		return m.clearedidentities
 	}
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *GuideHistoryMutation) ClearEdge(name string) error {
+func (m *OperatorMutation) ClearEdge(name string) error {
 	switch name {
-	case guidehistory.EdgeGuide:
-		m.ClearGuide()
-		return nil
-	case guidehistory.EdgeOperator:
-		m.ClearOperator()
-		return nil
 	}
-	return fmt.Errorf("unknown GuideHistory unique edge %s", name)
+	return fmt.Errorf("unknown Operator unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *GuideHistoryMutation) ResetEdge(name string) error {
+func (m *OperatorMutation) ResetEdge(name string) error {
 	switch name {
-	case guidehistory.EdgeGuide:
-		m.ResetGuide()
+	case operator.EdgeGuides:
+		m.ResetGuides()
 		return nil
-	case guidehistory.EdgeOperator:
-		m.ResetOperator()
+	case operator.EdgeGuideHistory:
+		m.ResetGuideHistory()
+		return nil
+	case operator.EdgeIdentities:
+		m.ResetIdentities()
 		return nil
 	}
-	return fmt.Errorf("unknown GuideHistory edge %s", name)
+	return fmt.Errorf("unknown Operator edge %s", name)
 }
 
-// OperatorMutation represents an operation that mutates the Operator nodes in the graph.
-type OperatorMutation struct {
+// OperatorIdentityMutation represents an operation that mutates the OperatorIdentity nodes in the graph.
+type OperatorIdentityMutation struct {
 	config
-	op                   Op
-	typ                  string
-	id                   *int
-	account              *string
-	name                 *string
-	enabled              *bool
-	created_at           *time.Time
-	updated_at           *time.Time
-	clearedFields        map[string]struct{}
-	guides               map[int]struct{}
-	removedguides        map[int]struct{}
-	clearedguides        bool
-	guide_history        map[int]struct{}
-	removedguide_history map[int]struct{}
-	clearedguide_history bool
-	done                 bool
-	oldValue             func(context.Context) (*Operator, error)
-	predicates           []predicate.Operator
+	op              Op
+	typ             string
+	id              *int
+	issuer          *string
+	subject         *string
+	created_at      *time.Time
+	clearedFields   map[string]struct{}
+	operator        *int
+	clearedoperator bool
+	done            bool
+	oldValue        func(context.Context) (*OperatorIdentity, error)
+	predicates      []predicate.OperatorIdentity
 }
 
-var _ ent.Mutation = (*OperatorMutation)(nil)
+var _ ent.Mutation = (*OperatorIdentityMutation)(nil)
 
-// operatorOption allows management of the mutation configuration using functional options.
-type operatorOption func(*OperatorMutation)
+// operatoridentityOption allows management of the mutation configuration using functional options.
+type operatoridentityOption func(*OperatorIdentityMutation)
 
-// newOperatorMutation creates new mutation for the Operator entity.
-func newOperatorMutation(c config, op Op, opts ...operatorOption) *OperatorMutation {
-	m := &OperatorMutation{
+// newOperatorIdentityMutation creates new mutation for the OperatorIdentity entity.
+func newOperatorIdentityMutation(c config, op Op, opts ...operatoridentityOption) *OperatorIdentityMutation {
+	m := &OperatorIdentityMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeOperator,
+		typ:           TypeOperatorIdentity,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -1456,20 +4798,20 @@ func newOperatorMutation(c config, op Op, opts ...operatorOption) *OperatorMutat
 	return m
 }
 
-// withOperatorID sets the ID field of the mutation.
-func withOperatorID(id int) operatorOption {
-	return func(m *OperatorMutation) {
+// withOperatorIdentityID sets the ID field of the mutation.
+func withOperatorIdentityID(id int) operatoridentityOption {
+	return func(m *OperatorIdentityMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *Operator
+			value *OperatorIdentity
 		)
-		m.oldValue = func(ctx context.Context) (*Operator, error) {
+		m.oldValue = func(ctx context.Context) (*OperatorIdentity, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().Operator.Get(ctx, id)
+					value, err = m.Client().OperatorIdentity.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -1478,10 +4820,10 @@ func withOperatorID(id int) operatorOption {
 	}
 }
 
-// withOperator sets the old Operator of the mutation.
-func withOperator(node *Operator) operatorOption {
-	return func(m *OperatorMutation) {
-		m.oldValue = func(context.Context) (*Operator, error) {
+// withOperatorIdentity sets the old OperatorIdentity of the mutation.
+func withOperatorIdentity(node *OperatorIdentity) operatoridentityOption {
+	return func(m *OperatorIdentityMutation) {
+		m.oldValue = func(context.Context) (*OperatorIdentity, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -1490,7 +4832,7 @@ func withOperator(node *Operator) operatorOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m OperatorMutation) Client() *Client {
+func (m OperatorIdentityMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -1498,7 +4840,7 @@ func (m OperatorMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m OperatorMutation) Tx() (*Tx, error) {
+func (m OperatorIdentityMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
@@ -1509,7 +4851,7 @@ func (m OperatorMutation) Tx() (*Tx, error) {
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *OperatorMutation) ID() (id int, exists bool) {
+func (m *OperatorIdentityMutation) ID() (id int, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -1520,7 +4862,7 @@ func (m *OperatorMutation) ID() (id int, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *OperatorMutation) IDs(ctx context.Context) ([]int, error) {
+func (m *OperatorIdentityMutation) IDs(ctx context.Context) ([]int, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -1529,309 +4871,192 @@ func (m *OperatorMutation) IDs(ctx context.Context) ([]int, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Operator.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().OperatorIdentity.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
-// SetAccount sets the "account" field.
-func (m *OperatorMutation) SetAccount(s string) {
-	m.account = &s
-}
-
-// Account returns the value of the "account" field in the mutation.
-func (m *OperatorMutation) Account() (r string, exists bool) {
-	v := m.account
-	if v == nil {
-		return
-	}
-	return *v, true
-}
-
-// OldAccount returns the old "account" field's value of the Operator entity.
-// If the Operator object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *OperatorMutation) OldAccount(ctx context.Context) (v string, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldAccount is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldAccount requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldAccount: %w", err)
-	}
-	return oldValue.Account, nil
-}
-
-// ResetAccount resets all changes to the "account" field.
-func (m *OperatorMutation) ResetAccount() {
-	m.account = nil
-}
-
-// SetName sets the "name" field.
-func (m *OperatorMutation) SetName(s string) {
-	m.name = &s
-}
-
-// Name returns the value of the "name" field in the mutation.
-func (m *OperatorMutation) Name() (r string, exists bool) {
-	v := m.name
-	if v == nil {
-		return
-	}
-	return *v, true
-}
-
-// OldName returns the old "name" field's value of the Operator entity.
-// If the Operator object wasn't provided to the builder, the object is fetched from the database.
-// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *OperatorMutation) OldName(ctx context.Context) (v string, err error) {
-	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldName is only allowed on UpdateOne operations")
-	}
-	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldName requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldName: %w", err)
-	}
-	return oldValue.Name, nil
-}
-
-// ResetName resets all changes to the "name" field.
-func (m *OperatorMutation) ResetName() {
-	m.name = nil
-}
-
-// SetEnabled sets the "enabled" field.
-func (m *OperatorMutation) SetEnabled(b bool) {
-	m.enabled = &b
+// SetIssuer sets the "issuer" field.
+func (m *OperatorIdentityMutation) SetIssuer(s string) {
+	m.issuer = &s
 }
 
-// Enabled returns the value of the "enabled" field in the mutation.
-func (m *OperatorMutation) Enabled() (r bool, exists bool) {
-	v := m.enabled
+// Issuer returns the value of the "issuer" field in the mutation.
+func (m *OperatorIdentityMutation) Issuer() (r string, exists bool) {
+	v := m.issuer
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldEnabled returns the old "enabled" field's value of the Operator entity.
-// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// OldIssuer returns the old "issuer" field's value of the OperatorIdentity entity.
+// If the OperatorIdentity object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *OperatorMutation) OldEnabled(ctx context.Context) (v bool, err error) {
+func (m *OperatorIdentityMutation) OldIssuer(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldEnabled is only allowed on UpdateOne operations")
+		return v, errors.New("OldIssuer is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldEnabled requires an ID field in the mutation")
+		return v, errors.New("OldIssuer requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldEnabled: %w", err)
+		return v, fmt.Errorf("querying old value for OldIssuer: %w", err)
 	}
-	return oldValue.Enabled, nil
+	return oldValue.Issuer, nil
 }
 
-// ResetEnabled resets all changes to the "enabled" field.
-func (m *OperatorMutation) ResetEnabled() {
-	m.enabled = nil
+// ResetIssuer resets all changes to the "issuer" field.
+func (m *OperatorIdentityMutation) ResetIssuer() {
+	m.issuer = nil
 }
 
-// SetCreatedAt sets the "created_at" field.
-func (m *OperatorMutation) SetCreatedAt(t time.Time) {
-	m.created_at = &t
+// SetSubject sets the "subject" field.
+func (m *OperatorIdentityMutation) SetSubject(s string) {
+	m.subject = &s
 }
 
-// CreatedAt returns the value of the "created_at" field in the mutation.
-func (m *OperatorMutation) CreatedAt() (r time.Time, exists bool) {
-	v := m.created_at
+// Subject returns the value of the "subject" field in the mutation.
+func (m *OperatorIdentityMutation) Subject() (r string, exists bool) {
+	v := m.subject
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldCreatedAt returns the old "created_at" field's value of the Operator entity.
-// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// OldSubject returns the old "subject" field's value of the OperatorIdentity entity.
+// If the OperatorIdentity object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *OperatorMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *OperatorIdentityMutation) OldSubject(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldSubject is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+		return v, errors.New("OldSubject requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldSubject: %w", err)
 	}
-	return oldValue.CreatedAt, nil
+	return oldValue.Subject, nil
 }
 
-// ResetCreatedAt resets all changes to the "created_at" field.
-func (m *OperatorMutation) ResetCreatedAt() {
-	m.created_at = nil
+// ResetSubject resets all changes to the "subject" field.
+func (m *OperatorIdentityMutation) ResetSubject() {
+	m.subject = nil
 }
 
-// SetUpdatedAt sets the "updated_at" field.
-func (m *OperatorMutation) SetUpdatedAt(t time.Time) {
-	m.updated_at = &t
+// SetOperatorID sets the "operator_id" field.
+func (m *OperatorIdentityMutation) SetOperatorID(i int) {
+	m.operator = &i
 }
 
-// UpdatedAt returns the value of the "updated_at" field in the mutation.
-func (m *OperatorMutation) UpdatedAt() (r time.Time, exists bool) {
-	v := m.updated_at
+// OperatorID returns the value of the "operator_id" field in the mutation.
+func (m *OperatorIdentityMutation) OperatorID() (r int, exists bool) {
+	v := m.operator
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldUpdatedAt returns the old "updated_at" field's value of the Operator entity.
-// If the Operator object wasn't provided to the builder, the object is fetched from the database.
+// OldOperatorID returns the old "operator_id" field's value of the OperatorIdentity entity.
+// If the OperatorIdentity object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *OperatorMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *OperatorIdentityMutation) OldOperatorID(ctx context.Context) (v int, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldOperatorID is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
-	}
-	oldValue, err := m.oldValue(ctx)
-	if err != nil {
-		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
-	}
-	return oldValue.UpdatedAt, nil
-}
-
-// ResetUpdatedAt resets all changes to the "updated_at" field.
-func (m *OperatorMutation) ResetUpdatedAt() {
-	m.updated_at = nil
-}
-
-// AddGuideIDs adds the "guides" edge to the Guide entity by ids.
-func (m *OperatorMutation) AddGuideIDs(ids ...int) {
-	if m.guides == nil {
-		m.guides = make(map[int]struct{})
-	}
-	for i := range ids {
-		m.guides[ids[i]] = struct{}{}
-	}
-}
-
-// ClearGuides clears the "guides" edge to the Guide entity.
-func (m *OperatorMutation) ClearGuides() {
-	m.clearedguides = true
-}
-
-// GuidesCleared reports if the "guides" edge to the Guide entity was cleared.
-func (m *OperatorMutation) GuidesCleared() bool {
-	return m.clearedguides
-}
-
-// RemoveGuideIDs removes the "guides" edge to the Guide entity by IDs.
-func (m *OperatorMutation) RemoveGuideIDs(ids ...int) {
-	if m.removedguides == nil {
-		m.removedguides = make(map[int]struct{})
-	}
-	for i := range ids {
-		delete(m.guides, ids[i])
-		m.removedguides[ids[i]] = struct{}{}
+		return v, errors.New("OldOperatorID requires an ID field in the mutation")
 	}
-}
-
-// RemovedGuides returns the removed IDs of the "guides" edge to the Guide entity.
-func (m *OperatorMutation) RemovedGuidesIDs() (ids []int) {
-	for id := range m.removedguides {
-		ids = append(ids, id)
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldOperatorID: %w", err)
 	}
-	return
+	return oldValue.OperatorID, nil
 }
 
-// GuidesIDs returns the "guides" edge IDs in the mutation.
-func (m *OperatorMutation) GuidesIDs() (ids []int) {
-	for id := range m.guides {
-		ids = append(ids, id)
-	}
-	return
+// ResetOperatorID resets all changes to the "operator_id" field.
+func (m *OperatorIdentityMutation) ResetOperatorID() {
+	m.operator = nil
 }
 
-// ResetGuides resets all changes to the "guides" edge.
-func (m *OperatorMutation) ResetGuides() {
-	m.guides = nil
-	m.clearedguides = false
-	m.removedguides = nil
+// SetCreatedAt sets the "created_at" field.
+func (m *OperatorIdentityMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
 }
 
-// AddGuideHistoryIDs adds the "guide_history" edge to the GuideHistory entity by ids.
-func (m *OperatorMutation) AddGuideHistoryIDs(ids ...int) {
-	if m.guide_history == nil {
-		m.guide_history = make(map[int]struct{})
-	}
-	for i := range ids {
-		m.guide_history[ids[i]] = struct{}{}
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *OperatorIdentityMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
 	}
+	return *v, true
 }
 
-// ClearGuideHistory clears the "guide_history" edge to the GuideHistory entity.
-func (m *OperatorMutation) ClearGuideHistory() {
-	m.clearedguide_history = true
+// OldCreatedAt returns the old "created_at" field's value of the OperatorIdentity entity.
+// If the OperatorIdentity object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OperatorIdentityMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
 }
 
-// GuideHistoryCleared reports if the "guide_history" edge to the GuideHistory entity was cleared.
-func (m *OperatorMutation) GuideHistoryCleared() bool {
-	return m.clearedguide_history
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *OperatorIdentityMutation) ResetCreatedAt() {
+	m.created_at = nil
 }
 
-// RemoveGuideHistoryIDs removes the "guide_history" edge to the GuideHistory entity by IDs.
-func (m *OperatorMutation) RemoveGuideHistoryIDs(ids ...int) {
-	if m.removedguide_history == nil {
-		m.removedguide_history = make(map[int]struct{})
-	}
-	for i := range ids {
-		delete(m.guide_history, ids[i])
-		m.removedguide_history[ids[i]] = struct{}{}
-	}
+// ClearOperator clears the "operator" edge to the Operator entity.
+func (m *OperatorIdentityMutation) ClearOperator() {
+	m.clearedoperator = true
+	m.clearedFields[operatoridentity.FieldOperatorID] = struct{}{}
 }
 
-// RemovedGuideHistory returns the removed IDs of the "guide_history" edge to the GuideHistory entity.
-func (m *OperatorMutation) RemovedGuideHistoryIDs() (ids []int) {
-	for id := range m.removedguide_history {
-		ids = append(ids, id)
-	}
-	return
+// OperatorCleared reports if the "operator" edge to the Operator entity was cleared.
+func (m *OperatorIdentityMutation) OperatorCleared() bool {
+	return m.clearedoperator
 }
 
-// GuideHistoryIDs returns the "guide_history" edge IDs in the mutation.
-func (m *OperatorMutation) GuideHistoryIDs() (ids []int) {
-	for id := range m.guide_history {
-		ids = append(ids, id)
+// OperatorIDs returns the "operator" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// OperatorID instead. It exists only for internal usage by the builders.
+func (m *OperatorIdentityMutation) OperatorIDs() (ids []int) {
+	if id := m.operator; id != nil {
+		ids = append(ids, *id)
 	}
 	return
 }
 
-// ResetGuideHistory resets all changes to the "guide_history" edge.
-func (m *OperatorMutation) ResetGuideHistory() {
-	m.guide_history = nil
-	m.clearedguide_history = false
-	m.removedguide_history = nil
+// ResetOperator resets all changes to the "operator" edge.
+func (m *OperatorIdentityMutation) ResetOperator() {
+	m.operator = nil
+	m.clearedoperator = false
 }
 
-// Where appends a list predicates to the OperatorMutation builder.
-func (m *OperatorMutation) Where(ps ...predicate.Operator) {
+// Where appends a list predicates to the OperatorIdentityMutation builder.
+func (m *OperatorIdentityMutation) Where(ps ...predicate.OperatorIdentity) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the OperatorMutation builder. Using this method,
+// WhereP appends storage-level predicates to the OperatorIdentityMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *OperatorMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Operator, len(ps))
+func (m *OperatorIdentityMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.OperatorIdentity, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -1839,39 +5064,36 @@ func (m *OperatorMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *OperatorMutation) Op() Op {
+func (m *OperatorIdentityMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *OperatorMutation) SetOp(op Op) {
+func (m *OperatorIdentityMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Operator).
-func (m *OperatorMutation) Type() string {
+// Type returns the node type of this mutation (OperatorIdentity).
+func (m *OperatorIdentityMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *OperatorMutation) Fields() []string {
-	fields := make([]string, 0, 5)
-	if m.account != nil {
-		fields = append(fields, operator.FieldAccount)
+func (m *OperatorIdentityMutation) Fields() []string {
+	fields := make([]string, 0, 4)
+	if m.issuer != nil {
+		fields = append(fields, operatoridentity.FieldIssuer)
 	}
-	if m.name != nil {
-		fields = append(fields, operator.FieldName)
+	if m.subject != nil {
+		fields = append(fields, operatoridentity.FieldSubject)
 	}
-	if m.enabled != nil {
-		fields = append(fields, operator.FieldEnabled)
+	if m.operator != nil {
+		fields = append(fields, operatoridentity.FieldOperatorID)
 	}
 	if m.created_at != nil {
-		fields = append(fields, operator.FieldCreatedAt)
-	}
-	if m.updated_at != nil {
-		fields = append(fields, operator.FieldUpdatedAt)
+		fields = append(fields, operatoridentity.FieldCreatedAt)
 	}
 	return fields
 }
@@ -1879,18 +5101,16 @@ func (m *OperatorMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *OperatorMutation) Field(name string) (ent.Value, bool) {
+func (m *OperatorIdentityMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case operator.FieldAccount:
-		return m.Account()
-	case operator.FieldName:
-		return m.Name()
-	case operator.FieldEnabled:
-		return m.Enabled()
-	case operator.FieldCreatedAt:
+	case operatoridentity.FieldIssuer:
+		return m.Issuer()
+	case operatoridentity.FieldSubject:
+		return m.Subject()
+	case operatoridentity.FieldOperatorID:
+		return m.OperatorID()
+	case operatoridentity.FieldCreatedAt:
 		return m.CreatedAt()
-	case operator.FieldUpdatedAt:
-		return m.UpdatedAt()
 	}
 	return nil, false
 }
@@ -1898,236 +5118,191 @@ func (m *OperatorMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *OperatorMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *OperatorIdentityMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case operator.FieldAccount:
-		return m.OldAccount(ctx)
-	case operator.FieldName:
-		return m.OldName(ctx)
-	case operator.FieldEnabled:
-		return m.OldEnabled(ctx)
-	case operator.FieldCreatedAt:
+	case operatoridentity.FieldIssuer:
+		return m.OldIssuer(ctx)
+	case operatoridentity.FieldSubject:
+		return m.OldSubject(ctx)
+	case operatoridentity.FieldOperatorID:
+		return m.OldOperatorID(ctx)
+	case operatoridentity.FieldCreatedAt:
 		return m.OldCreatedAt(ctx)
-	case operator.FieldUpdatedAt:
-		return m.OldUpdatedAt(ctx)
 	}
-	return nil, fmt.Errorf("unknown Operator field %s", name)
+	return nil, fmt.Errorf("unknown OperatorIdentity field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *OperatorMutation) SetField(name string, value ent.Value) error {
+func (m *OperatorIdentityMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case operator.FieldAccount:
+	case operatoridentity.FieldIssuer:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetAccount(v)
+		m.SetIssuer(v)
 		return nil
-	case operator.FieldName:
+	case operatoridentity.FieldSubject:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetName(v)
+		m.SetSubject(v)
 		return nil
-	case operator.FieldEnabled:
-		v, ok := value.(bool)
+	case operatoridentity.FieldOperatorID:
+		v, ok := value.(int)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetEnabled(v)
+		m.SetOperatorID(v)
 		return nil
-	case operator.FieldCreatedAt:
+	case operatoridentity.FieldCreatedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetCreatedAt(v)
 		return nil
-	case operator.FieldUpdatedAt:
-		v, ok := value.(time.Time)
-		if !ok {
-			return fmt.Errorf("unexpected type %T for field %s", value, name)
-		}
-		m.SetUpdatedAt(v)
-		return nil
 	}
-	return fmt.Errorf("unknown Operator field %s", name)
+	return fmt.Errorf("unknown OperatorIdentity field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *OperatorMutation) AddedFields() []string {
-	return nil
+func (m *OperatorIdentityMutation) AddedFields() []string {
+	var fields []string
+	return fields
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *OperatorMutation) AddedField(name string) (ent.Value, bool) {
+func (m *OperatorIdentityMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	}
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *OperatorMutation) AddField(name string, value ent.Value) error {
+func (m *OperatorIdentityMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown Operator numeric field %s", name)
+	return fmt.Errorf("unknown OperatorIdentity numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *OperatorMutation) ClearedFields() []string {
+func (m *OperatorIdentityMutation) ClearedFields() []string {
 	return nil
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *OperatorMutation) FieldCleared(name string) bool {
+func (m *OperatorIdentityMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *OperatorMutation) ClearField(name string) error {
-	return fmt.Errorf("unknown Operator nullable field %s", name)
+func (m *OperatorIdentityMutation) ClearField(name string) error {
+	return fmt.Errorf("unknown OperatorIdentity nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *OperatorMutation) ResetField(name string) error {
+func (m *OperatorIdentityMutation) ResetField(name string) error {
 	switch name {
-	case operator.FieldAccount:
-		m.ResetAccount()
+	case operatoridentity.FieldIssuer:
+		m.ResetIssuer()
 		return nil
-	case operator.FieldName:
-		m.ResetName()
+	case operatoridentity.FieldSubject:
+		m.ResetSubject()
 		return nil
-	case operator.FieldEnabled:
-		m.ResetEnabled()
+	case operatoridentity.FieldOperatorID:
+		m.ResetOperatorID()
 		return nil
-	case operator.FieldCreatedAt:
+	case operatoridentity.FieldCreatedAt:
 		m.ResetCreatedAt()
 		return nil
-	case operator.FieldUpdatedAt:
-		m.ResetUpdatedAt()
-		return nil
 	}
-	return fmt.Errorf("unknown Operator field %s", name)
+	return fmt.Errorf("unknown OperatorIdentity field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *OperatorMutation) AddedEdges() []string {
-	edges := make([]string, 0, 2)
-	if m.guides != nil {
-		edges = append(edges, operator.EdgeGuides)
-	}
-	if m.guide_history != nil {
-		edges = append(edges, operator.EdgeGuideHistory)
+func (m *OperatorIdentityMutation) AddedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.operator != nil {
+		edges = append(edges, operatoridentity.EdgeOperator)
 	}
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *OperatorMutation) AddedIDs(name string) []ent.Value {
+func (m *OperatorIdentityMutation) AddedIDs(name string) []ent.Value {
 	switch name {
-	case operator.EdgeGuides:
-		ids := make([]ent.Value, 0, len(m.guides))
-		for id := range m.guides {
-			ids = append(ids, id)
-		}
-		return ids
-	case operator.EdgeGuideHistory:
-		ids := make([]ent.Value, 0, len(m.guide_history))
-		for id := range m.guide_history {
-			ids = append(ids, id)
+	case operatoridentity.EdgeOperator:
+		if id := m.operator; id != nil {
+			return []ent.Value{*id}
 		}
-		return ids
 	}
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *OperatorMutation) RemovedEdges() []string {
-	edges := make([]string, 0, 2)
-	if m.removedguides != nil {
-		edges = append(edges, operator.EdgeGuides)
-	}
-	if m.removedguide_history != nil {
-		edges = append(edges, operator.EdgeGuideHistory)
-	}
+func (m *OperatorIdentityMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 1)
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *OperatorMutation) RemovedIDs(name string) []ent.Value {
-	switch name {
-	case operator.EdgeGuides:
-		ids := make([]ent.Value, 0, len(m.removedguides))
-		for id := range m.removedguides {
-			ids = append(ids, id)
-		}
-		return ids
-	case operator.EdgeGuideHistory:
-		ids := make([]ent.Value, 0, len(m.removedguide_history))
-		for id := range m.removedguide_history {
-			ids = append(ids, id)
-		}
-		return ids
-	}
+func (m *OperatorIdentityMutation) RemovedIDs(name string) []ent.Value {
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *OperatorMutation) ClearedEdges() []string {
-	edges := make([]string, 0, 2)
-	if m.clearedguides {
-		edges = append(edges, operator.EdgeGuides)
-	}
-	if m.clearedguide_history {
-		edges = append(edges, operator.EdgeGuideHistory)
+func (m *OperatorIdentityMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.clearedoperator {
+		edges = append(edges, operatoridentity.EdgeOperator)
 	}
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *OperatorMutation) EdgeCleared(name string) bool {
+func (m *OperatorIdentityMutation) EdgeCleared(name string) bool {
 	switch name {
-	case operator.EdgeGuides:
-		return m.clearedguides
-	case operator.EdgeGuideHistory:
-		return m.clearedguide_history
+	case operatoridentity.EdgeOperator:
+		return m.clearedoperator
 	}
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *OperatorMutation) ClearEdge(name string) error {
+func (m *OperatorIdentityMutation) ClearEdge(name string) error {
 	switch name {
+	case operatoridentity.EdgeOperator:
+		m.ClearOperator()
+		return nil
 	}
-	return fmt.Errorf("unknown Operator unique edge %s", name)
+	return fmt.Errorf("unknown OperatorIdentity unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *OperatorMutation) ResetEdge(name string) error {
+func (m *OperatorIdentityMutation) ResetEdge(name string) error {
 	switch name {
-	case operator.EdgeGuides:
-		m.ResetGuides()
-		return nil
-	case operator.EdgeGuideHistory:
-		m.ResetGuideHistory()
+	case operatoridentity.EdgeOperator:
+		m.ResetOperator()
 		return nil
 	}
-	return fmt.Errorf("unknown Operator edge %s", name)
+	return fmt.Errorf("unknown OperatorIdentity edge %s", name)
 }
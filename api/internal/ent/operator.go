@@ -23,6 +23,14 @@ type Operator struct {
 	Name string `json:"name,omitempty"`
 	// Enabled holds the value of the "enabled" field.
 	Enabled bool `json:"enabled,omitempty"`
+	// Role holds the value of the "role" field.
+	Role string `json:"role,omitempty"`
+	// Availability holds the value of the "availability" field.
+	Availability string `json:"availability,omitempty"`
+	// PasswordHash holds the value of the "password_hash" field.
+	PasswordHash string `json:"-"`
+	// TotpSecret holds the value of the "totp_secret" field.
+	TotpSecret string `json:"-"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// UpdatedAt holds the value of the "updated_at" field.
@@ -39,9 +47,11 @@ type OperatorEdges struct {
 	Guides []*Guide `json:"guides,omitempty"`
 	// GuideHistory holds the value of the guide_history edge.
 	GuideHistory []*GuideHistory `json:"guide_history,omitempty"`
+	// Identities holds the value of the identities edge.
+	Identities []*OperatorIdentity `json:"identities,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [2]bool
+	loadedTypes [3]bool
 }
 
 // GuidesOrErr returns the Guides value or an error if the edge
@@ -62,6 +72,15 @@ func (e OperatorEdges) GuideHistoryOrErr() ([]*GuideHistory, error) {
 	return nil, &NotLoadedError{edge: "guide_history"}
 }
 
+// IdentitiesOrErr returns the Identities value or an error if the edge
+// was not loaded in eager-loading.
+func (e OperatorEdges) IdentitiesOrErr() ([]*OperatorIdentity, error) {
+	if e.loadedTypes[2] {
+		return e.Identities, nil
+	}
+	return nil, &NotLoadedError{edge: "identities"}
+}
+
 // scanValues returns the types for scanning values from sql.Rows.
 func (*Operator) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
@@ -71,7 +90,7 @@ func (*Operator) scanValues(columns []string) ([]any, error) {
 			values[i] = new(sql.NullBool)
 		case operator.FieldID:
 			values[i] = new(sql.NullInt64)
-		case operator.FieldAccount, operator.FieldName:
+		case operator.FieldAccount, operator.FieldName, operator.FieldRole, operator.FieldAvailability, operator.FieldPasswordHash, operator.FieldTotpSecret:
 			values[i] = new(sql.NullString)
 		case operator.FieldCreatedAt, operator.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
@@ -114,6 +133,30 @@ func (o *Operator) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				o.Enabled = value.Bool
 			}
+		case operator.FieldRole:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field role", values[i])
+			} else if value.Valid {
+				o.Role = value.String
+			}
+		case operator.FieldAvailability:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field availability", values[i])
+			} else if value.Valid {
+				o.Availability = value.String
+			}
+		case operator.FieldPasswordHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field password_hash", values[i])
+			} else if value.Valid {
+				o.PasswordHash = value.String
+			}
+		case operator.FieldTotpSecret:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field totp_secret", values[i])
+			} else if value.Valid {
+				o.TotpSecret = value.String
+			}
 		case operator.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -149,6 +192,11 @@ func (o *Operator) QueryGuideHistory() *GuideHistoryQuery {
 	return NewOperatorClient(o.config).QueryGuideHistory(o)
 }
 
+// QueryIdentities queries the "identities" edge of the Operator entity.
+func (o *Operator) QueryIdentities() *OperatorIdentityQuery {
+	return NewOperatorClient(o.config).QueryIdentities(o)
+}
+
 // Update returns a builder for updating this Operator.
 // Note that you need to call Operator.Unwrap() before calling this method if this Operator
 // was returned from a transaction, and the transaction was committed or rolled back.
@@ -181,6 +229,16 @@ func (o *Operator) String() string {
 	builder.WriteString("enabled=")
 	builder.WriteString(fmt.Sprintf("%v", o.Enabled))
 	builder.WriteString(", ")
+	builder.WriteString("role=")
+	builder.WriteString(o.Role)
+	builder.WriteString(", ")
+	builder.WriteString("availability=")
+	builder.WriteString(o.Availability)
+	builder.WriteString(", ")
+	builder.WriteString("password_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("totp_secret=<sensitive>")
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(o.CreatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
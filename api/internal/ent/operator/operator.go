@@ -20,6 +20,14 @@ const (
 	FieldName = "name"
 	// FieldEnabled holds the string denoting the enabled field in the database.
 	FieldEnabled = "enabled"
+	// FieldRole holds the string denoting the role field in the database.
+	FieldRole = "role"
+	// FieldAvailability holds the string denoting the availability field in the database.
+	FieldAvailability = "availability"
+	// FieldPasswordHash holds the string denoting the password_hash field in the database.
+	FieldPasswordHash = "password_hash"
+	// FieldTotpSecret holds the string denoting the totp_secret field in the database.
+	FieldTotpSecret = "totp_secret"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
 	FieldCreatedAt = "created_at"
 	// FieldUpdatedAt holds the string denoting the updated_at field in the database.
@@ -28,6 +36,8 @@ const (
 	EdgeGuides = "guides"
 	// EdgeGuideHistory holds the string denoting the guide_history edge name in mutations.
 	EdgeGuideHistory = "guide_history"
+	// EdgeIdentities holds the string denoting the identities edge name in mutations.
+	EdgeIdentities = "identities"
 	// Table holds the table name of the operator in the database.
 	Table = "operators"
 	// GuidesTable is the table that holds the guides relation/edge.
@@ -44,6 +54,13 @@ const (
 	GuideHistoryInverseTable = "guide_histories"
 	// GuideHistoryColumn is the table column denoting the guide_history relation/edge.
 	GuideHistoryColumn = "operator_id"
+	// IdentitiesTable is the table that holds the identities relation/edge.
+	IdentitiesTable = "operator_identities"
+	// IdentitiesInverseTable is the table name for the OperatorIdentity entity.
+	// It exists in this package in order to avoid circular dependency with the "operatoridentity" package.
+	IdentitiesInverseTable = "operator_identities"
+	// IdentitiesColumn is the table column denoting the identities relation/edge.
+	IdentitiesColumn = "operator_id"
 )
 
 // Columns holds all SQL columns for operator fields.
@@ -52,6 +69,10 @@ var Columns = []string{
 	FieldAccount,
 	FieldName,
 	FieldEnabled,
+	FieldRole,
+	FieldAvailability,
+	FieldPasswordHash,
+	FieldTotpSecret,
 	FieldCreatedAt,
 	FieldUpdatedAt,
 }
@@ -73,6 +94,18 @@ var (
 	NameValidator func(string) error
 	// DefaultEnabled holds the default value on creation for the "enabled" field.
 	DefaultEnabled bool
+	// DefaultRole holds the default value on creation for the "role" field.
+	DefaultRole string
+	// RoleValidator is a validator for the "role" field. It is called by the builders before save.
+	RoleValidator func(string) error
+	// DefaultAvailability holds the default value on creation for the "availability" field.
+	DefaultAvailability string
+	// AvailabilityValidator is a validator for the "availability" field. It is called by the builders before save.
+	AvailabilityValidator func(string) error
+	// PasswordHashValidator is a validator for the "password_hash" field. It is called by the builders before save.
+	PasswordHashValidator func(string) error
+	// TotpSecretValidator is a validator for the "totp_secret" field. It is called by the builders before save.
+	TotpSecretValidator func(string) error
 	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
 	DefaultCreatedAt func() time.Time
 	// DefaultUpdatedAt holds the default value on creation for the "updated_at" field.
@@ -104,6 +137,26 @@ func ByEnabled(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldEnabled, opts...).ToFunc()
 }
 
+// ByRole orders the results by the role field.
+func ByRole(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRole, opts...).ToFunc()
+}
+
+// ByAvailability orders the results by the availability field.
+func ByAvailability(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAvailability, opts...).ToFunc()
+}
+
+// ByPasswordHash orders the results by the password_hash field.
+func ByPasswordHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPasswordHash, opts...).ToFunc()
+}
+
+// ByTotpSecret orders the results by the totp_secret field.
+func ByTotpSecret(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTotpSecret, opts...).ToFunc()
+}
+
 // ByCreatedAt orders the results by the created_at field.
 func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
@@ -141,6 +194,20 @@ func ByGuideHistory(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
 		sqlgraph.OrderByNeighborTerms(s, newGuideHistoryStep(), append([]sql.OrderTerm{term}, terms...)...)
 	}
 }
+
+// ByIdentitiesCount orders the results by identities count.
+func ByIdentitiesCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newIdentitiesStep(), opts...)
+	}
+}
+
+// ByIdentities orders the results by identities terms.
+func ByIdentities(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newIdentitiesStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
 func newGuidesStep() *sqlgraph.Step {
 	return sqlgraph.NewStep(
 		sqlgraph.From(Table, FieldID),
@@ -155,3 +222,10 @@ func newGuideHistoryStep() *sqlgraph.Step {
 		sqlgraph.Edge(sqlgraph.O2M, false, GuideHistoryTable, GuideHistoryColumn),
 	)
 }
+func newIdentitiesStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(IdentitiesInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, false, IdentitiesTable, IdentitiesColumn),
+	)
+}
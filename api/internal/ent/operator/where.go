@@ -70,6 +70,26 @@ func Enabled(v bool) predicate.Operator {
 	return predicate.Operator(sql.FieldEQ(FieldEnabled, v))
 }
 
+// Role applies equality check predicate on the "role" field. It's identical to RoleEQ.
+func Role(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEQ(FieldRole, v))
+}
+
+// Availability applies equality check predicate on the "availability" field. It's identical to AvailabilityEQ.
+func Availability(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEQ(FieldAvailability, v))
+}
+
+// PasswordHash applies equality check predicate on the "password_hash" field. It's identical to PasswordHashEQ.
+func PasswordHash(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEQ(FieldPasswordHash, v))
+}
+
+// TotpSecret applies equality check predicate on the "totp_secret" field. It's identical to TotpSecretEQ.
+func TotpSecret(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEQ(FieldTotpSecret, v))
+}
+
 // CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
 func CreatedAt(v time.Time) predicate.Operator {
 	return predicate.Operator(sql.FieldEQ(FieldCreatedAt, v))
@@ -220,6 +240,286 @@ func EnabledNEQ(v bool) predicate.Operator {
 	return predicate.Operator(sql.FieldNEQ(FieldEnabled, v))
 }
 
+// RoleEQ applies the EQ predicate on the "role" field.
+func RoleEQ(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEQ(FieldRole, v))
+}
+
+// RoleNEQ applies the NEQ predicate on the "role" field.
+func RoleNEQ(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldNEQ(FieldRole, v))
+}
+
+// RoleIn applies the In predicate on the "role" field.
+func RoleIn(vs ...string) predicate.Operator {
+	return predicate.Operator(sql.FieldIn(FieldRole, vs...))
+}
+
+// RoleNotIn applies the NotIn predicate on the "role" field.
+func RoleNotIn(vs ...string) predicate.Operator {
+	return predicate.Operator(sql.FieldNotIn(FieldRole, vs...))
+}
+
+// RoleGT applies the GT predicate on the "role" field.
+func RoleGT(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldGT(FieldRole, v))
+}
+
+// RoleGTE applies the GTE predicate on the "role" field.
+func RoleGTE(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldGTE(FieldRole, v))
+}
+
+// RoleLT applies the LT predicate on the "role" field.
+func RoleLT(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldLT(FieldRole, v))
+}
+
+// RoleLTE applies the LTE predicate on the "role" field.
+func RoleLTE(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldLTE(FieldRole, v))
+}
+
+// RoleContains applies the Contains predicate on the "role" field.
+func RoleContains(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldContains(FieldRole, v))
+}
+
+// RoleHasPrefix applies the HasPrefix predicate on the "role" field.
+func RoleHasPrefix(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldHasPrefix(FieldRole, v))
+}
+
+// RoleHasSuffix applies the HasSuffix predicate on the "role" field.
+func RoleHasSuffix(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldHasSuffix(FieldRole, v))
+}
+
+// RoleEqualFold applies the EqualFold predicate on the "role" field.
+func RoleEqualFold(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEqualFold(FieldRole, v))
+}
+
+// RoleContainsFold applies the ContainsFold predicate on the "role" field.
+func RoleContainsFold(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldContainsFold(FieldRole, v))
+}
+
+// AvailabilityEQ applies the EQ predicate on the "availability" field.
+func AvailabilityEQ(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEQ(FieldAvailability, v))
+}
+
+// AvailabilityNEQ applies the NEQ predicate on the "availability" field.
+func AvailabilityNEQ(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldNEQ(FieldAvailability, v))
+}
+
+// AvailabilityIn applies the In predicate on the "availability" field.
+func AvailabilityIn(vs ...string) predicate.Operator {
+	return predicate.Operator(sql.FieldIn(FieldAvailability, vs...))
+}
+
+// AvailabilityNotIn applies the NotIn predicate on the "availability" field.
+func AvailabilityNotIn(vs ...string) predicate.Operator {
+	return predicate.Operator(sql.FieldNotIn(FieldAvailability, vs...))
+}
+
+// AvailabilityGT applies the GT predicate on the "availability" field.
+func AvailabilityGT(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldGT(FieldAvailability, v))
+}
+
+// AvailabilityGTE applies the GTE predicate on the "availability" field.
+func AvailabilityGTE(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldGTE(FieldAvailability, v))
+}
+
+// AvailabilityLT applies the LT predicate on the "availability" field.
+func AvailabilityLT(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldLT(FieldAvailability, v))
+}
+
+// AvailabilityLTE applies the LTE predicate on the "availability" field.
+func AvailabilityLTE(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldLTE(FieldAvailability, v))
+}
+
+// AvailabilityContains applies the Contains predicate on the "availability" field.
+func AvailabilityContains(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldContains(FieldAvailability, v))
+}
+
+// AvailabilityHasPrefix applies the HasPrefix predicate on the "availability" field.
+func AvailabilityHasPrefix(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldHasPrefix(FieldAvailability, v))
+}
+
+// AvailabilityHasSuffix applies the HasSuffix predicate on the "availability" field.
+func AvailabilityHasSuffix(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldHasSuffix(FieldAvailability, v))
+}
+
+// AvailabilityEqualFold applies the EqualFold predicate on the "availability" field.
+func AvailabilityEqualFold(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEqualFold(FieldAvailability, v))
+}
+
+// AvailabilityContainsFold applies the ContainsFold predicate on the "availability" field.
+func AvailabilityContainsFold(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldContainsFold(FieldAvailability, v))
+}
+
+// PasswordHashEQ applies the EQ predicate on the "password_hash" field.
+func PasswordHashEQ(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEQ(FieldPasswordHash, v))
+}
+
+// PasswordHashNEQ applies the NEQ predicate on the "password_hash" field.
+func PasswordHashNEQ(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldNEQ(FieldPasswordHash, v))
+}
+
+// PasswordHashIn applies the In predicate on the "password_hash" field.
+func PasswordHashIn(vs ...string) predicate.Operator {
+	return predicate.Operator(sql.FieldIn(FieldPasswordHash, vs...))
+}
+
+// PasswordHashNotIn applies the NotIn predicate on the "password_hash" field.
+func PasswordHashNotIn(vs ...string) predicate.Operator {
+	return predicate.Operator(sql.FieldNotIn(FieldPasswordHash, vs...))
+}
+
+// PasswordHashGT applies the GT predicate on the "password_hash" field.
+func PasswordHashGT(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldGT(FieldPasswordHash, v))
+}
+
+// PasswordHashGTE applies the GTE predicate on the "password_hash" field.
+func PasswordHashGTE(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldGTE(FieldPasswordHash, v))
+}
+
+// PasswordHashLT applies the LT predicate on the "password_hash" field.
+func PasswordHashLT(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldLT(FieldPasswordHash, v))
+}
+
+// PasswordHashLTE applies the LTE predicate on the "password_hash" field.
+func PasswordHashLTE(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldLTE(FieldPasswordHash, v))
+}
+
+// PasswordHashContains applies the Contains predicate on the "password_hash" field.
+func PasswordHashContains(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldContains(FieldPasswordHash, v))
+}
+
+// PasswordHashHasPrefix applies the HasPrefix predicate on the "password_hash" field.
+func PasswordHashHasPrefix(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldHasPrefix(FieldPasswordHash, v))
+}
+
+// PasswordHashHasSuffix applies the HasSuffix predicate on the "password_hash" field.
+func PasswordHashHasSuffix(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldHasSuffix(FieldPasswordHash, v))
+}
+
+// PasswordHashIsNil applies the IsNil predicate on the "password_hash" field.
+func PasswordHashIsNil() predicate.Operator {
+	return predicate.Operator(sql.FieldIsNull(FieldPasswordHash))
+}
+
+// PasswordHashNotNil applies the NotNil predicate on the "password_hash" field.
+func PasswordHashNotNil() predicate.Operator {
+	return predicate.Operator(sql.FieldNotNull(FieldPasswordHash))
+}
+
+// PasswordHashEqualFold applies the EqualFold predicate on the "password_hash" field.
+func PasswordHashEqualFold(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEqualFold(FieldPasswordHash, v))
+}
+
+// PasswordHashContainsFold applies the ContainsFold predicate on the "password_hash" field.
+func PasswordHashContainsFold(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldContainsFold(FieldPasswordHash, v))
+}
+
+// TotpSecretEQ applies the EQ predicate on the "totp_secret" field.
+func TotpSecretEQ(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEQ(FieldTotpSecret, v))
+}
+
+// TotpSecretNEQ applies the NEQ predicate on the "totp_secret" field.
+func TotpSecretNEQ(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldNEQ(FieldTotpSecret, v))
+}
+
+// TotpSecretIn applies the In predicate on the "totp_secret" field.
+func TotpSecretIn(vs ...string) predicate.Operator {
+	return predicate.Operator(sql.FieldIn(FieldTotpSecret, vs...))
+}
+
+// TotpSecretNotIn applies the NotIn predicate on the "totp_secret" field.
+func TotpSecretNotIn(vs ...string) predicate.Operator {
+	return predicate.Operator(sql.FieldNotIn(FieldTotpSecret, vs...))
+}
+
+// TotpSecretGT applies the GT predicate on the "totp_secret" field.
+func TotpSecretGT(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldGT(FieldTotpSecret, v))
+}
+
+// TotpSecretGTE applies the GTE predicate on the "totp_secret" field.
+func TotpSecretGTE(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldGTE(FieldTotpSecret, v))
+}
+
+// TotpSecretLT applies the LT predicate on the "totp_secret" field.
+func TotpSecretLT(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldLT(FieldTotpSecret, v))
+}
+
+// TotpSecretLTE applies the LTE predicate on the "totp_secret" field.
+func TotpSecretLTE(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldLTE(FieldTotpSecret, v))
+}
+
+// TotpSecretContains applies the Contains predicate on the "totp_secret" field.
+func TotpSecretContains(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldContains(FieldTotpSecret, v))
+}
+
+// TotpSecretHasPrefix applies the HasPrefix predicate on the "totp_secret" field.
+func TotpSecretHasPrefix(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldHasPrefix(FieldTotpSecret, v))
+}
+
+// TotpSecretHasSuffix applies the HasSuffix predicate on the "totp_secret" field.
+func TotpSecretHasSuffix(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldHasSuffix(FieldTotpSecret, v))
+}
+
+// TotpSecretIsNil applies the IsNil predicate on the "totp_secret" field.
+func TotpSecretIsNil() predicate.Operator {
+	return predicate.Operator(sql.FieldIsNull(FieldTotpSecret))
+}
+
+// TotpSecretNotNil applies the NotNil predicate on the "totp_secret" field.
+func TotpSecretNotNil() predicate.Operator {
+	return predicate.Operator(sql.FieldNotNull(FieldTotpSecret))
+}
+
+// TotpSecretEqualFold applies the EqualFold predicate on the "totp_secret" field.
+func TotpSecretEqualFold(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldEqualFold(FieldTotpSecret, v))
+}
+
+// TotpSecretContainsFold applies the ContainsFold predicate on the "totp_secret" field.
+func TotpSecretContainsFold(v string) predicate.Operator {
+	return predicate.Operator(sql.FieldContainsFold(FieldTotpSecret, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Operator {
 	return predicate.Operator(sql.FieldEQ(FieldCreatedAt, v))
@@ -346,6 +646,29 @@ func HasGuideHistoryWith(preds ...predicate.GuideHistory) predicate.Operator {
 	})
 }
 
+// HasIdentities applies the HasEdge predicate on the "identities" edge.
+func HasIdentities() predicate.Operator {
+	return predicate.Operator(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.O2M, false, IdentitiesTable, IdentitiesColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasIdentitiesWith applies the HasEdge predicate on the "identities" edge with a given conditions (other predicates).
+func HasIdentitiesWith(preds ...predicate.OperatorIdentity) predicate.Operator {
+	return predicate.Operator(func(s *sql.Selector) {
+		step := newIdentitiesStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.Operator) predicate.Operator {
 	return predicate.Operator(sql.AndPredicates(predicates...))
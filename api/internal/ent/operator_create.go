@@ -10,6 +10,7 @@ import (
 	"via/internal/ent/guide"
 	"via/internal/ent/guidehistory"
 	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
 
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
@@ -48,6 +49,62 @@ func (oc *OperatorCreate) SetNillableEnabled(b *bool) *OperatorCreate {
 	return oc
 }
 
+// SetRole sets the "role" field.
+func (oc *OperatorCreate) SetRole(s string) *OperatorCreate {
+	oc.mutation.SetRole(s)
+	return oc
+}
+
+// SetNillableRole sets the "role" field if the given value is not nil.
+func (oc *OperatorCreate) SetNillableRole(s *string) *OperatorCreate {
+	if s != nil {
+		oc.SetRole(*s)
+	}
+	return oc
+}
+
+// SetAvailability sets the "availability" field.
+func (oc *OperatorCreate) SetAvailability(s string) *OperatorCreate {
+	oc.mutation.SetAvailability(s)
+	return oc
+}
+
+// SetNillableAvailability sets the "availability" field if the given value is not nil.
+func (oc *OperatorCreate) SetNillableAvailability(s *string) *OperatorCreate {
+	if s != nil {
+		oc.SetAvailability(*s)
+	}
+	return oc
+}
+
+// SetPasswordHash sets the "password_hash" field.
+func (oc *OperatorCreate) SetPasswordHash(s string) *OperatorCreate {
+	oc.mutation.SetPasswordHash(s)
+	return oc
+}
+
+// SetNillablePasswordHash sets the "password_hash" field if the given value is not nil.
+func (oc *OperatorCreate) SetNillablePasswordHash(s *string) *OperatorCreate {
+	if s != nil {
+		oc.SetPasswordHash(*s)
+	}
+	return oc
+}
+
+// SetTotpSecret sets the "totp_secret" field.
+func (oc *OperatorCreate) SetTotpSecret(s string) *OperatorCreate {
+	oc.mutation.SetTotpSecret(s)
+	return oc
+}
+
+// SetNillableTotpSecret sets the "totp_secret" field if the given value is not nil.
+func (oc *OperatorCreate) SetNillableTotpSecret(s *string) *OperatorCreate {
+	if s != nil {
+		oc.SetTotpSecret(*s)
+	}
+	return oc
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (oc *OperatorCreate) SetCreatedAt(t time.Time) *OperatorCreate {
 	oc.mutation.SetCreatedAt(t)
@@ -106,6 +163,21 @@ func (oc *OperatorCreate) AddGuideHistory(g ...*GuideHistory) *OperatorCreate {
 	return oc.AddGuideHistoryIDs(ids...)
 }
 
+// AddIdentityIDs adds the "identities" edge to the OperatorIdentity entity by IDs.
+func (oc *OperatorCreate) AddIdentityIDs(ids ...int) *OperatorCreate {
+	oc.mutation.AddIdentityIDs(ids...)
+	return oc
+}
+
+// AddIdentities adds the "identities" edges to the OperatorIdentity entity.
+func (oc *OperatorCreate) AddIdentities(o ...*OperatorIdentity) *OperatorCreate {
+	ids := make([]int, len(o))
+	for i := range o {
+		ids[i] = o[i].ID
+	}
+	return oc.AddIdentityIDs(ids...)
+}
+
 // Mutation returns the OperatorMutation object of the builder.
 func (oc *OperatorCreate) Mutation() *OperatorMutation {
 	return oc.mutation
@@ -145,6 +217,14 @@ func (oc *OperatorCreate) defaults() {
 		v := operator.DefaultEnabled
 		oc.mutation.SetEnabled(v)
 	}
+	if _, ok := oc.mutation.Role(); !ok {
+		v := operator.DefaultRole
+		oc.mutation.SetRole(v)
+	}
+	if _, ok := oc.mutation.Availability(); !ok {
+		v := operator.DefaultAvailability
+		oc.mutation.SetAvailability(v)
+	}
 	if _, ok := oc.mutation.CreatedAt(); !ok {
 		v := operator.DefaultCreatedAt()
 		oc.mutation.SetCreatedAt(v)
@@ -176,11 +256,31 @@ func (oc *OperatorCreate) check() error {
 	if _, ok := oc.mutation.Enabled(); !ok {
 		return &ValidationError{Name: "enabled", err: errors.New(`ent: missing required field "Operator.enabled"`)}
 	}
-	if _, ok := oc.mutation.CreatedAt(); !ok {
-		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Operator.created_at"`)}
+	if _, ok := oc.mutation.Role(); !ok {
+		return &ValidationError{Name: "role", err: errors.New(`ent: missing required field "Operator.role"`)}
 	}
-	if _, ok := oc.mutation.UpdatedAt(); !ok {
-		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "Operator.updated_at"`)}
+	if v, ok := oc.mutation.Role(); ok {
+		if err := operator.RoleValidator(v); err != nil {
+			return &ValidationError{Name: "role", err: fmt.Errorf(`ent: validator failed for field "Operator.role": %w`, err)}
+		}
+	}
+	if _, ok := oc.mutation.Availability(); !ok {
+		return &ValidationError{Name: "availability", err: errors.New(`ent: missing required field "Operator.availability"`)}
+	}
+	if v, ok := oc.mutation.Availability(); ok {
+		if err := operator.AvailabilityValidator(v); err != nil {
+			return &ValidationError{Name: "availability", err: fmt.Errorf(`ent: validator failed for field "Operator.availability": %w`, err)}
+		}
+	}
+	if v, ok := oc.mutation.PasswordHash(); ok {
+		if err := operator.PasswordHashValidator(v); err != nil {
+			return &ValidationError{Name: "password_hash", err: fmt.Errorf(`ent: validator failed for field "Operator.password_hash": %w`, err)}
+		}
+	}
+	if v, ok := oc.mutation.TotpSecret(); ok {
+		if err := operator.TotpSecretValidator(v); err != nil {
+			return &ValidationError{Name: "totp_secret", err: fmt.Errorf(`ent: validator failed for field "Operator.totp_secret": %w`, err)}
+		}
 	}
 	return nil
 }
@@ -220,6 +320,22 @@ func (oc *OperatorCreate) createSpec() (*Operator, *sqlgraph.CreateSpec) {
 		_spec.SetField(operator.FieldEnabled, field.TypeBool, value)
 		_node.Enabled = value
 	}
+	if value, ok := oc.mutation.Role(); ok {
+		_spec.SetField(operator.FieldRole, field.TypeString, value)
+		_node.Role = value
+	}
+	if value, ok := oc.mutation.Availability(); ok {
+		_spec.SetField(operator.FieldAvailability, field.TypeString, value)
+		_node.Availability = value
+	}
+	if value, ok := oc.mutation.PasswordHash(); ok {
+		_spec.SetField(operator.FieldPasswordHash, field.TypeString, value)
+		_node.PasswordHash = value
+	}
+	if value, ok := oc.mutation.TotpSecret(); ok {
+		_spec.SetField(operator.FieldTotpSecret, field.TypeString, value)
+		_node.TotpSecret = value
+	}
 	if value, ok := oc.mutation.CreatedAt(); ok {
 		_spec.SetField(operator.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
@@ -260,6 +376,22 @@ func (oc *OperatorCreate) createSpec() (*Operator, *sqlgraph.CreateSpec) {
 		}
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := oc.mutation.IdentitiesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   operator.IdentitiesTable,
+			Columns: []string{operator.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
@@ -10,6 +10,7 @@ import (
 	"via/internal/ent/guide"
 	"via/internal/ent/guidehistory"
 	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
 	"via/internal/ent/predicate"
 
 	"entgo.io/ent"
@@ -27,6 +28,7 @@ type OperatorQuery struct {
 	predicates       []predicate.Operator
 	withGuides       *GuideQuery
 	withGuideHistory *GuideHistoryQuery
+	withIdentities   *OperatorIdentityQuery
 	// intermediate query (i.e. traversal path).
 	sql  *sql.Selector
 	path func(context.Context) (*sql.Selector, error)
@@ -107,6 +109,28 @@ func (oq *OperatorQuery) QueryGuideHistory() *GuideHistoryQuery {
 	return query
 }
 
+// QueryIdentities chains the current query on the "identities" edge.
+func (oq *OperatorQuery) QueryIdentities() *OperatorIdentityQuery {
+	query := (&OperatorIdentityClient{config: oq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := oq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := oq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(operator.Table, operator.FieldID, selector),
+			sqlgraph.To(operatoridentity.Table, operatoridentity.FieldID),
+			sqlgraph.Edge(sqlgraph.O2M, false, operator.IdentitiesTable, operator.IdentitiesColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(oq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
 // First returns the first Operator entity from the query.
 // Returns a *NotFoundError when no Operator was found.
 func (oq *OperatorQuery) First(ctx context.Context) (*Operator, error) {
@@ -301,6 +325,7 @@ func (oq *OperatorQuery) Clone() *OperatorQuery {
 		predicates:       append([]predicate.Operator{}, oq.predicates...),
 		withGuides:       oq.withGuides.Clone(),
 		withGuideHistory: oq.withGuideHistory.Clone(),
+		withIdentities:   oq.withIdentities.Clone(),
 		// clone intermediate query.
 		sql:  oq.sql.Clone(),
 		path: oq.path,
@@ -329,6 +354,17 @@ func (oq *OperatorQuery) WithGuideHistory(opts ...func(*GuideHistoryQuery)) *Ope
 	return oq
 }
 
+// WithIdentities tells the query-builder to eager-load the nodes that are connected to
+// the "identities" edge. The optional arguments are used to configure the query builder of the edge.
+func (oq *OperatorQuery) WithIdentities(opts ...func(*OperatorIdentityQuery)) *OperatorQuery {
+	query := (&OperatorIdentityClient{config: oq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	oq.withIdentities = query
+	return oq
+}
+
 // GroupBy is used to group vertices by one or more fields/columns.
 // It is often used with aggregate functions, like: count, max, mean, min, sum.
 //
@@ -407,9 +443,10 @@ func (oq *OperatorQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Ope
 	var (
 		nodes       = []*Operator{}
 		_spec       = oq.querySpec()
-		loadedTypes = [2]bool{
+		loadedTypes = [3]bool{
 			oq.withGuides != nil,
 			oq.withGuideHistory != nil,
+			oq.withIdentities != nil,
 		}
 	)
 	_spec.ScanValues = func(columns []string) ([]any, error) {
@@ -444,6 +481,13 @@ func (oq *OperatorQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Ope
 			return nil, err
 		}
 	}
+	if query := oq.withIdentities; query != nil {
+		if err := oq.loadIdentities(ctx, query, nodes,
+			func(n *Operator) { n.Edges.Identities = []*OperatorIdentity{} },
+			func(n *Operator, e *OperatorIdentity) { n.Edges.Identities = append(n.Edges.Identities, e) }); err != nil {
+			return nil, err
+		}
+	}
 	return nodes, nil
 }
 
@@ -507,6 +551,36 @@ func (oq *OperatorQuery) loadGuideHistory(ctx context.Context, query *GuideHisto
 	}
 	return nil
 }
+func (oq *OperatorQuery) loadIdentities(ctx context.Context, query *OperatorIdentityQuery, nodes []*Operator, init func(*Operator), assign func(*Operator, *OperatorIdentity)) error {
+	fks := make([]driver.Value, 0, len(nodes))
+	nodeids := make(map[int]*Operator)
+	for i := range nodes {
+		fks = append(fks, nodes[i].ID)
+		nodeids[nodes[i].ID] = nodes[i]
+		if init != nil {
+			init(nodes[i])
+		}
+	}
+	if len(query.ctx.Fields) > 0 {
+		query.ctx.AppendFieldOnce(operatoridentity.FieldOperatorID)
+	}
+	query.Where(predicate.OperatorIdentity(func(s *sql.Selector) {
+		s.Where(sql.InValues(s.C(operator.IdentitiesColumn), fks...))
+	}))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		fk := n.OperatorID
+		node, ok := nodeids[fk]
+		if !ok {
+			return fmt.Errorf(`unexpected referenced foreign-key "operator_id" returned %v for node %v`, fk, n.ID)
+		}
+		assign(node, n)
+	}
+	return nil
+}
 
 func (oq *OperatorQuery) sqlCount(ctx context.Context) (int, error) {
 	_spec := oq.querySpec()
@@ -10,6 +10,7 @@ import (
 	"via/internal/ent/guide"
 	"via/internal/ent/guidehistory"
 	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
 	"via/internal/ent/predicate"
 
 	"entgo.io/ent/dialect/sql"
@@ -44,6 +45,74 @@ func (ou *OperatorUpdate) SetNillableEnabled(b *bool) *OperatorUpdate {
 	return ou
 }
 
+// SetRole sets the "role" field.
+func (ou *OperatorUpdate) SetRole(s string) *OperatorUpdate {
+	ou.mutation.SetRole(s)
+	return ou
+}
+
+// SetNillableRole sets the "role" field if the given value is not nil.
+func (ou *OperatorUpdate) SetNillableRole(s *string) *OperatorUpdate {
+	if s != nil {
+		ou.SetRole(*s)
+	}
+	return ou
+}
+
+// SetAvailability sets the "availability" field.
+func (ou *OperatorUpdate) SetAvailability(s string) *OperatorUpdate {
+	ou.mutation.SetAvailability(s)
+	return ou
+}
+
+// SetNillableAvailability sets the "availability" field if the given value is not nil.
+func (ou *OperatorUpdate) SetNillableAvailability(s *string) *OperatorUpdate {
+	if s != nil {
+		ou.SetAvailability(*s)
+	}
+	return ou
+}
+
+// SetPasswordHash sets the "password_hash" field.
+func (ou *OperatorUpdate) SetPasswordHash(s string) *OperatorUpdate {
+	ou.mutation.SetPasswordHash(s)
+	return ou
+}
+
+// SetNillablePasswordHash sets the "password_hash" field if the given value is not nil.
+func (ou *OperatorUpdate) SetNillablePasswordHash(s *string) *OperatorUpdate {
+	if s != nil {
+		ou.SetPasswordHash(*s)
+	}
+	return ou
+}
+
+// ClearPasswordHash clears the value of the "password_hash" field.
+func (ou *OperatorUpdate) ClearPasswordHash() *OperatorUpdate {
+	ou.mutation.ClearPasswordHash()
+	return ou
+}
+
+// SetTotpSecret sets the "totp_secret" field.
+func (ou *OperatorUpdate) SetTotpSecret(s string) *OperatorUpdate {
+	ou.mutation.SetTotpSecret(s)
+	return ou
+}
+
+// SetNillableTotpSecret sets the "totp_secret" field if the given value is not nil.
+func (ou *OperatorUpdate) SetNillableTotpSecret(s *string) *OperatorUpdate {
+	if s != nil {
+		ou.SetTotpSecret(*s)
+	}
+	return ou
+}
+
+// ClearTotpSecret clears the value of the "totp_secret" field.
+func (ou *OperatorUpdate) ClearTotpSecret() *OperatorUpdate {
+	ou.mutation.ClearTotpSecret()
+	return ou
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (ou *OperatorUpdate) SetCreatedAt(t time.Time) *OperatorUpdate {
 	ou.mutation.SetCreatedAt(t)
@@ -94,6 +163,21 @@ func (ou *OperatorUpdate) AddGuideHistory(g ...*GuideHistory) *OperatorUpdate {
 	return ou.AddGuideHistoryIDs(ids...)
 }
 
+// AddIdentityIDs adds the "identities" edge to the OperatorIdentity entity by IDs.
+func (ou *OperatorUpdate) AddIdentityIDs(ids ...int) *OperatorUpdate {
+	ou.mutation.AddIdentityIDs(ids...)
+	return ou
+}
+
+// AddIdentities adds the "identities" edges to the OperatorIdentity entity.
+func (ou *OperatorUpdate) AddIdentities(o ...*OperatorIdentity) *OperatorUpdate {
+	ids := make([]int, len(o))
+	for i := range o {
+		ids[i] = o[i].ID
+	}
+	return ou.AddIdentityIDs(ids...)
+}
+
 // Mutation returns the OperatorMutation object of the builder.
 func (ou *OperatorUpdate) Mutation() *OperatorMutation {
 	return ou.mutation
@@ -141,6 +225,27 @@ func (ou *OperatorUpdate) RemoveGuideHistory(g ...*GuideHistory) *OperatorUpdate
 	return ou.RemoveGuideHistoryIDs(ids...)
 }
 
+// ClearIdentities clears all "identities" edges to the OperatorIdentity entity.
+func (ou *OperatorUpdate) ClearIdentities() *OperatorUpdate {
+	ou.mutation.ClearIdentities()
+	return ou
+}
+
+// RemoveIdentityIDs removes the "identities" edge to OperatorIdentity entities by IDs.
+func (ou *OperatorUpdate) RemoveIdentityIDs(ids ...int) *OperatorUpdate {
+	ou.mutation.RemoveIdentityIDs(ids...)
+	return ou
+}
+
+// RemoveIdentities removes "identities" edges to OperatorIdentity entities.
+func (ou *OperatorUpdate) RemoveIdentities(o ...*OperatorIdentity) *OperatorUpdate {
+	ids := make([]int, len(o))
+	for i := range o {
+		ids[i] = o[i].ID
+	}
+	return ou.RemoveIdentityIDs(ids...)
+}
+
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (ou *OperatorUpdate) Save(ctx context.Context) (int, error) {
 	ou.defaults()
@@ -177,7 +282,35 @@ func (ou *OperatorUpdate) defaults() {
 	}
 }
 
+// check runs all checks and user-defined validators on the builder.
+func (ou *OperatorUpdate) check() error {
+	if v, ok := ou.mutation.Role(); ok {
+		if err := operator.RoleValidator(v); err != nil {
+			return &ValidationError{Name: "role", err: fmt.Errorf(`ent: validator failed for field "Operator.role": %w`, err)}
+		}
+	}
+	if v, ok := ou.mutation.Availability(); ok {
+		if err := operator.AvailabilityValidator(v); err != nil {
+			return &ValidationError{Name: "availability", err: fmt.Errorf(`ent: validator failed for field "Operator.availability": %w`, err)}
+		}
+	}
+	if v, ok := ou.mutation.PasswordHash(); ok {
+		if err := operator.PasswordHashValidator(v); err != nil {
+			return &ValidationError{Name: "password_hash", err: fmt.Errorf(`ent: validator failed for field "Operator.password_hash": %w`, err)}
+		}
+	}
+	if v, ok := ou.mutation.TotpSecret(); ok {
+		if err := operator.TotpSecretValidator(v); err != nil {
+			return &ValidationError{Name: "totp_secret", err: fmt.Errorf(`ent: validator failed for field "Operator.totp_secret": %w`, err)}
+		}
+	}
+	return nil
+}
+
 func (ou *OperatorUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := ou.check(); err != nil {
+		return n, err
+	}
 	_spec := sqlgraph.NewUpdateSpec(operator.Table, operator.Columns, sqlgraph.NewFieldSpec(operator.FieldID, field.TypeInt))
 	if ps := ou.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
@@ -189,6 +322,24 @@ func (ou *OperatorUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := ou.mutation.Enabled(); ok {
 		_spec.SetField(operator.FieldEnabled, field.TypeBool, value)
 	}
+	if value, ok := ou.mutation.Role(); ok {
+		_spec.SetField(operator.FieldRole, field.TypeString, value)
+	}
+	if value, ok := ou.mutation.Availability(); ok {
+		_spec.SetField(operator.FieldAvailability, field.TypeString, value)
+	}
+	if value, ok := ou.mutation.PasswordHash(); ok {
+		_spec.SetField(operator.FieldPasswordHash, field.TypeString, value)
+	}
+	if ou.mutation.PasswordHashCleared() {
+		_spec.ClearField(operator.FieldPasswordHash, field.TypeString)
+	}
+	if value, ok := ou.mutation.TotpSecret(); ok {
+		_spec.SetField(operator.FieldTotpSecret, field.TypeString, value)
+	}
+	if ou.mutation.TotpSecretCleared() {
+		_spec.ClearField(operator.FieldTotpSecret, field.TypeString)
+	}
 	if value, ok := ou.mutation.CreatedAt(); ok {
 		_spec.SetField(operator.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -285,6 +436,51 @@ func (ou *OperatorUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	if ou.mutation.IdentitiesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   operator.IdentitiesTable,
+			Columns: []string{operator.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ou.mutation.RemovedIdentitiesIDs(); len(nodes) > 0 && !ou.mutation.IdentitiesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   operator.IdentitiesTable,
+			Columns: []string{operator.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ou.mutation.IdentitiesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   operator.IdentitiesTable,
+			Columns: []string{operator.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
 	if n, err = sqlgraph.UpdateNodes(ctx, ou.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{operator.Label}
@@ -319,6 +515,74 @@ func (ouo *OperatorUpdateOne) SetNillableEnabled(b *bool) *OperatorUpdateOne {
 	return ouo
 }
 
+// SetRole sets the "role" field.
+func (ouo *OperatorUpdateOne) SetRole(s string) *OperatorUpdateOne {
+	ouo.mutation.SetRole(s)
+	return ouo
+}
+
+// SetNillableRole sets the "role" field if the given value is not nil.
+func (ouo *OperatorUpdateOne) SetNillableRole(s *string) *OperatorUpdateOne {
+	if s != nil {
+		ouo.SetRole(*s)
+	}
+	return ouo
+}
+
+// SetAvailability sets the "availability" field.
+func (ouo *OperatorUpdateOne) SetAvailability(s string) *OperatorUpdateOne {
+	ouo.mutation.SetAvailability(s)
+	return ouo
+}
+
+// SetNillableAvailability sets the "availability" field if the given value is not nil.
+func (ouo *OperatorUpdateOne) SetNillableAvailability(s *string) *OperatorUpdateOne {
+	if s != nil {
+		ouo.SetAvailability(*s)
+	}
+	return ouo
+}
+
+// SetPasswordHash sets the "password_hash" field.
+func (ouo *OperatorUpdateOne) SetPasswordHash(s string) *OperatorUpdateOne {
+	ouo.mutation.SetPasswordHash(s)
+	return ouo
+}
+
+// SetNillablePasswordHash sets the "password_hash" field if the given value is not nil.
+func (ouo *OperatorUpdateOne) SetNillablePasswordHash(s *string) *OperatorUpdateOne {
+	if s != nil {
+		ouo.SetPasswordHash(*s)
+	}
+	return ouo
+}
+
+// ClearPasswordHash clears the value of the "password_hash" field.
+func (ouo *OperatorUpdateOne) ClearPasswordHash() *OperatorUpdateOne {
+	ouo.mutation.ClearPasswordHash()
+	return ouo
+}
+
+// SetTotpSecret sets the "totp_secret" field.
+func (ouo *OperatorUpdateOne) SetTotpSecret(s string) *OperatorUpdateOne {
+	ouo.mutation.SetTotpSecret(s)
+	return ouo
+}
+
+// SetNillableTotpSecret sets the "totp_secret" field if the given value is not nil.
+func (ouo *OperatorUpdateOne) SetNillableTotpSecret(s *string) *OperatorUpdateOne {
+	if s != nil {
+		ouo.SetTotpSecret(*s)
+	}
+	return ouo
+}
+
+// ClearTotpSecret clears the value of the "totp_secret" field.
+func (ouo *OperatorUpdateOne) ClearTotpSecret() *OperatorUpdateOne {
+	ouo.mutation.ClearTotpSecret()
+	return ouo
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (ouo *OperatorUpdateOne) SetCreatedAt(t time.Time) *OperatorUpdateOne {
 	ouo.mutation.SetCreatedAt(t)
@@ -369,6 +633,21 @@ func (ouo *OperatorUpdateOne) AddGuideHistory(g ...*GuideHistory) *OperatorUpdat
 	return ouo.AddGuideHistoryIDs(ids...)
 }
 
+// AddIdentityIDs adds the "identities" edge to the OperatorIdentity entity by IDs.
+func (ouo *OperatorUpdateOne) AddIdentityIDs(ids ...int) *OperatorUpdateOne {
+	ouo.mutation.AddIdentityIDs(ids...)
+	return ouo
+}
+
+// AddIdentities adds the "identities" edges to the OperatorIdentity entity.
+func (ouo *OperatorUpdateOne) AddIdentities(o ...*OperatorIdentity) *OperatorUpdateOne {
+	ids := make([]int, len(o))
+	for i := range o {
+		ids[i] = o[i].ID
+	}
+	return ouo.AddIdentityIDs(ids...)
+}
+
 // Mutation returns the OperatorMutation object of the builder.
 func (ouo *OperatorUpdateOne) Mutation() *OperatorMutation {
 	return ouo.mutation
@@ -416,6 +695,27 @@ func (ouo *OperatorUpdateOne) RemoveGuideHistory(g ...*GuideHistory) *OperatorUp
 	return ouo.RemoveGuideHistoryIDs(ids...)
 }
 
+// ClearIdentities clears all "identities" edges to the OperatorIdentity entity.
+func (ouo *OperatorUpdateOne) ClearIdentities() *OperatorUpdateOne {
+	ouo.mutation.ClearIdentities()
+	return ouo
+}
+
+// RemoveIdentityIDs removes the "identities" edge to OperatorIdentity entities by IDs.
+func (ouo *OperatorUpdateOne) RemoveIdentityIDs(ids ...int) *OperatorUpdateOne {
+	ouo.mutation.RemoveIdentityIDs(ids...)
+	return ouo
+}
+
+// RemoveIdentities removes "identities" edges to OperatorIdentity entities.
+func (ouo *OperatorUpdateOne) RemoveIdentities(o ...*OperatorIdentity) *OperatorUpdateOne {
+	ids := make([]int, len(o))
+	for i := range o {
+		ids[i] = o[i].ID
+	}
+	return ouo.RemoveIdentityIDs(ids...)
+}
+
 // Where appends a list predicates to the OperatorUpdate builder.
 func (ouo *OperatorUpdateOne) Where(ps ...predicate.Operator) *OperatorUpdateOne {
 	ouo.mutation.Where(ps...)
@@ -465,7 +765,35 @@ func (ouo *OperatorUpdateOne) defaults() {
 	}
 }
 
+// check runs all checks and user-defined validators on the builder.
+func (ouo *OperatorUpdateOne) check() error {
+	if v, ok := ouo.mutation.Role(); ok {
+		if err := operator.RoleValidator(v); err != nil {
+			return &ValidationError{Name: "role", err: fmt.Errorf(`ent: validator failed for field "Operator.role": %w`, err)}
+		}
+	}
+	if v, ok := ouo.mutation.Availability(); ok {
+		if err := operator.AvailabilityValidator(v); err != nil {
+			return &ValidationError{Name: "availability", err: fmt.Errorf(`ent: validator failed for field "Operator.availability": %w`, err)}
+		}
+	}
+	if v, ok := ouo.mutation.PasswordHash(); ok {
+		if err := operator.PasswordHashValidator(v); err != nil {
+			return &ValidationError{Name: "password_hash", err: fmt.Errorf(`ent: validator failed for field "Operator.password_hash": %w`, err)}
+		}
+	}
+	if v, ok := ouo.mutation.TotpSecret(); ok {
+		if err := operator.TotpSecretValidator(v); err != nil {
+			return &ValidationError{Name: "totp_secret", err: fmt.Errorf(`ent: validator failed for field "Operator.totp_secret": %w`, err)}
+		}
+	}
+	return nil
+}
+
 func (ouo *OperatorUpdateOne) sqlSave(ctx context.Context) (_node *Operator, err error) {
+	if err := ouo.check(); err != nil {
+		return _node, err
+	}
 	_spec := sqlgraph.NewUpdateSpec(operator.Table, operator.Columns, sqlgraph.NewFieldSpec(operator.FieldID, field.TypeInt))
 	id, ok := ouo.mutation.ID()
 	if !ok {
@@ -494,6 +822,24 @@ func (ouo *OperatorUpdateOne) sqlSave(ctx context.Context) (_node *Operator, err
 	if value, ok := ouo.mutation.Enabled(); ok {
 		_spec.SetField(operator.FieldEnabled, field.TypeBool, value)
 	}
+	if value, ok := ouo.mutation.Role(); ok {
+		_spec.SetField(operator.FieldRole, field.TypeString, value)
+	}
+	if value, ok := ouo.mutation.Availability(); ok {
+		_spec.SetField(operator.FieldAvailability, field.TypeString, value)
+	}
+	if value, ok := ouo.mutation.PasswordHash(); ok {
+		_spec.SetField(operator.FieldPasswordHash, field.TypeString, value)
+	}
+	if ouo.mutation.PasswordHashCleared() {
+		_spec.ClearField(operator.FieldPasswordHash, field.TypeString)
+	}
+	if value, ok := ouo.mutation.TotpSecret(); ok {
+		_spec.SetField(operator.FieldTotpSecret, field.TypeString, value)
+	}
+	if ouo.mutation.TotpSecretCleared() {
+		_spec.ClearField(operator.FieldTotpSecret, field.TypeString)
+	}
 	if value, ok := ouo.mutation.CreatedAt(); ok {
 		_spec.SetField(operator.FieldCreatedAt, field.TypeTime, value)
 	}
@@ -590,6 +936,51 @@ func (ouo *OperatorUpdateOne) sqlSave(ctx context.Context) (_node *Operator, err
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	if ouo.mutation.IdentitiesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   operator.IdentitiesTable,
+			Columns: []string{operator.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ouo.mutation.RemovedIdentitiesIDs(); len(nodes) > 0 && !ouo.mutation.IdentitiesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   operator.IdentitiesTable,
+			Columns: []string{operator.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ouo.mutation.IdentitiesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   operator.IdentitiesTable,
+			Columns: []string{operator.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
 	_node = &Operator{config: ouo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
@@ -0,0 +1,168 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// OperatorIdentity is the model entity for the OperatorIdentity schema.
+type OperatorIdentity struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Issuer holds the value of the "issuer" field.
+	Issuer string `json:"issuer,omitempty"`
+	// Subject holds the value of the "subject" field.
+	Subject string `json:"subject,omitempty"`
+	// OperatorID holds the value of the "operator_id" field.
+	OperatorID int `json:"operator_id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the OperatorIdentityQuery when eager-loading is set.
+	Edges        OperatorIdentityEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// OperatorIdentityEdges holds the relations/edges for other nodes in the graph.
+type OperatorIdentityEdges struct {
+	// Operator holds the value of the operator edge.
+	Operator *Operator `json:"operator,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// OperatorOrErr returns the Operator value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e OperatorIdentityEdges) OperatorOrErr() (*Operator, error) {
+	if e.Operator != nil {
+		return e.Operator, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: operator.Label}
+	}
+	return nil, &NotLoadedError{edge: "operator"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*OperatorIdentity) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case operatoridentity.FieldID, operatoridentity.FieldOperatorID:
+			values[i] = new(sql.NullInt64)
+		case operatoridentity.FieldIssuer, operatoridentity.FieldSubject:
+			values[i] = new(sql.NullString)
+		case operatoridentity.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the OperatorIdentity fields.
+func (oi *OperatorIdentity) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case operatoridentity.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			oi.ID = int(value.Int64)
+		case operatoridentity.FieldIssuer:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field issuer", values[i])
+			} else if value.Valid {
+				oi.Issuer = value.String
+			}
+		case operatoridentity.FieldSubject:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field subject", values[i])
+			} else if value.Valid {
+				oi.Subject = value.String
+			}
+		case operatoridentity.FieldOperatorID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field operator_id", values[i])
+			} else if value.Valid {
+				oi.OperatorID = int(value.Int64)
+			}
+		case operatoridentity.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				oi.CreatedAt = value.Time
+			}
+		default:
+			oi.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the OperatorIdentity.
+// This includes values selected through modifiers, order, etc.
+func (oi *OperatorIdentity) Value(name string) (ent.Value, error) {
+	return oi.selectValues.Get(name)
+}
+
+// QueryOperator queries the "operator" edge of the OperatorIdentity entity.
+func (oi *OperatorIdentity) QueryOperator() *OperatorQuery {
+	return NewOperatorIdentityClient(oi.config).QueryOperator(oi)
+}
+
+// Update returns a builder for updating this OperatorIdentity.
+// Note that you need to call OperatorIdentity.Unwrap() before calling this method if this OperatorIdentity
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (oi *OperatorIdentity) Update() *OperatorIdentityUpdateOne {
+	return NewOperatorIdentityClient(oi.config).UpdateOne(oi)
+}
+
+// Unwrap unwraps the OperatorIdentity entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (oi *OperatorIdentity) Unwrap() *OperatorIdentity {
+	_tx, ok := oi.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: OperatorIdentity is not a transactional entity")
+	}
+	oi.config.driver = _tx.drv
+	return oi
+}
+
+// String implements the fmt.Stringer.
+func (oi *OperatorIdentity) String() string {
+	var builder strings.Builder
+	builder.WriteString("OperatorIdentity(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", oi.ID))
+	builder.WriteString("issuer=")
+	builder.WriteString(oi.Issuer)
+	builder.WriteString(", ")
+	builder.WriteString("subject=")
+	builder.WriteString(oi.Subject)
+	builder.WriteString(", ")
+	builder.WriteString("operator_id=")
+	builder.WriteString(fmt.Sprintf("%v", oi.OperatorID))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(oi.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// OperatorIdentities is a parsable slice of OperatorIdentity.
+type OperatorIdentities []*OperatorIdentity
@@ -0,0 +1,275 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// OperatorIdentityCreate is the builder for creating a OperatorIdentity entity.
+type OperatorIdentityCreate struct {
+	config
+	mutation *OperatorIdentityMutation
+	hooks    []Hook
+}
+
+// SetIssuer sets the "issuer" field.
+func (oic *OperatorIdentityCreate) SetIssuer(s string) *OperatorIdentityCreate {
+	oic.mutation.SetIssuer(s)
+	return oic
+}
+
+// SetSubject sets the "subject" field.
+func (oic *OperatorIdentityCreate) SetSubject(s string) *OperatorIdentityCreate {
+	oic.mutation.SetSubject(s)
+	return oic
+}
+
+// SetOperatorID sets the "operator_id" field.
+func (oic *OperatorIdentityCreate) SetOperatorID(i int) *OperatorIdentityCreate {
+	oic.mutation.SetOperatorID(i)
+	return oic
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (oic *OperatorIdentityCreate) SetCreatedAt(t time.Time) *OperatorIdentityCreate {
+	oic.mutation.SetCreatedAt(t)
+	return oic
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (oic *OperatorIdentityCreate) SetNillableCreatedAt(t *time.Time) *OperatorIdentityCreate {
+	if t != nil {
+		oic.SetCreatedAt(*t)
+	}
+	return oic
+}
+
+// SetOperator sets the "operator" edge to the Operator entity.
+func (oic *OperatorIdentityCreate) SetOperator(o *Operator) *OperatorIdentityCreate {
+	return oic.SetOperatorID(o.ID)
+}
+
+// Mutation returns the OperatorIdentityMutation object of the builder.
+func (oic *OperatorIdentityCreate) Mutation() *OperatorIdentityMutation {
+	return oic.mutation
+}
+
+// Save creates the OperatorIdentity in the database.
+func (oic *OperatorIdentityCreate) Save(ctx context.Context) (*OperatorIdentity, error) {
+	oic.defaults()
+	return withHooks(ctx, oic.sqlSave, oic.mutation, oic.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (oic *OperatorIdentityCreate) SaveX(ctx context.Context) *OperatorIdentity {
+	v, err := oic.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (oic *OperatorIdentityCreate) Exec(ctx context.Context) error {
+	_, err := oic.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oic *OperatorIdentityCreate) ExecX(ctx context.Context) {
+	if err := oic.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (oic *OperatorIdentityCreate) defaults() {
+	if _, ok := oic.mutation.CreatedAt(); !ok {
+		v := operatoridentity.DefaultCreatedAt()
+		oic.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (oic *OperatorIdentityCreate) check() error {
+	if _, ok := oic.mutation.Issuer(); !ok {
+		return &ValidationError{Name: "issuer", err: errors.New(`ent: missing required field "OperatorIdentity.issuer"`)}
+	}
+	if v, ok := oic.mutation.Issuer(); ok {
+		if err := operatoridentity.IssuerValidator(v); err != nil {
+			return &ValidationError{Name: "issuer", err: fmt.Errorf(`ent: validator failed for field "OperatorIdentity.issuer": %w`, err)}
+		}
+	}
+	if _, ok := oic.mutation.Subject(); !ok {
+		return &ValidationError{Name: "subject", err: errors.New(`ent: missing required field "OperatorIdentity.subject"`)}
+	}
+	if v, ok := oic.mutation.Subject(); ok {
+		if err := operatoridentity.SubjectValidator(v); err != nil {
+			return &ValidationError{Name: "subject", err: fmt.Errorf(`ent: validator failed for field "OperatorIdentity.subject": %w`, err)}
+		}
+	}
+	if _, ok := oic.mutation.OperatorID(); !ok {
+		return &ValidationError{Name: "operator_id", err: errors.New(`ent: missing required field "OperatorIdentity.operator_id"`)}
+	}
+	if v, ok := oic.mutation.OperatorID(); ok {
+		if err := operatoridentity.OperatorIDValidator(v); err != nil {
+			return &ValidationError{Name: "operator_id", err: fmt.Errorf(`ent: validator failed for field "OperatorIdentity.operator_id": %w`, err)}
+		}
+	}
+	if len(oic.mutation.OperatorIDs()) == 0 {
+		return &ValidationError{Name: "operator", err: errors.New(`ent: missing required edge "OperatorIdentity.operator"`)}
+	}
+	return nil
+}
+
+func (oic *OperatorIdentityCreate) sqlSave(ctx context.Context) (*OperatorIdentity, error) {
+	if err := oic.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := oic.createSpec()
+	if err := sqlgraph.CreateNode(ctx, oic.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	oic.mutation.id = &_node.ID
+	oic.mutation.done = true
+	return _node, nil
+}
+
+func (oic *OperatorIdentityCreate) createSpec() (*OperatorIdentity, *sqlgraph.CreateSpec) {
+	var (
+		_node = &OperatorIdentity{config: oic.config}
+		_spec = sqlgraph.NewCreateSpec(operatoridentity.Table, sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt))
+	)
+	if value, ok := oic.mutation.Issuer(); ok {
+		_spec.SetField(operatoridentity.FieldIssuer, field.TypeString, value)
+		_node.Issuer = value
+	}
+	if value, ok := oic.mutation.Subject(); ok {
+		_spec.SetField(operatoridentity.FieldSubject, field.TypeString, value)
+		_node.Subject = value
+	}
+	if value, ok := oic.mutation.CreatedAt(); ok {
+		_spec.SetField(operatoridentity.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if nodes := oic.mutation.OperatorIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   operatoridentity.OperatorTable,
+			Columns: []string{operatoridentity.OperatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(operator.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.OperatorID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// OperatorIdentityCreateBulk is the builder for creating many OperatorIdentity entities in bulk.
+type OperatorIdentityCreateBulk struct {
+	config
+	err      error
+	builders []*OperatorIdentityCreate
+}
+
+// Save creates the OperatorIdentity entities in the database.
+func (oicb *OperatorIdentityCreateBulk) Save(ctx context.Context) ([]*OperatorIdentity, error) {
+	if oicb.err != nil {
+		return nil, oicb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(oicb.builders))
+	nodes := make([]*OperatorIdentity, len(oicb.builders))
+	mutators := make([]Mutator, len(oicb.builders))
+	for i := range oicb.builders {
+		func(i int, root context.Context) {
+			builder := oicb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*OperatorIdentityMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, oicb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, oicb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, oicb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (oicb *OperatorIdentityCreateBulk) SaveX(ctx context.Context) []*OperatorIdentity {
+	v, err := oicb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (oicb *OperatorIdentityCreateBulk) Exec(ctx context.Context) error {
+	_, err := oicb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oicb *OperatorIdentityCreateBulk) ExecX(ctx context.Context) {
+	if err := oicb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"via/internal/ent/operatoridentity"
+	"via/internal/ent/predicate"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// OperatorIdentityDelete is the builder for deleting a OperatorIdentity entity.
+type OperatorIdentityDelete struct {
+	config
+	hooks    []Hook
+	mutation *OperatorIdentityMutation
+}
+
+// Where appends a list predicates to the OperatorIdentityDelete builder.
+func (oid *OperatorIdentityDelete) Where(ps ...predicate.OperatorIdentity) *OperatorIdentityDelete {
+	oid.mutation.Where(ps...)
+	return oid
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (oid *OperatorIdentityDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, oid.sqlExec, oid.mutation, oid.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oid *OperatorIdentityDelete) ExecX(ctx context.Context) int {
+	n, err := oid.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (oid *OperatorIdentityDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(operatoridentity.Table, sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt))
+	if ps := oid.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, oid.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	oid.mutation.done = true
+	return affected, err
+}
+
+// OperatorIdentityDeleteOne is the builder for deleting a single OperatorIdentity entity.
+type OperatorIdentityDeleteOne struct {
+	oid *OperatorIdentityDelete
+}
+
+// Where appends a list predicates to the OperatorIdentityDelete builder.
+func (oido *OperatorIdentityDeleteOne) Where(ps ...predicate.OperatorIdentity) *OperatorIdentityDeleteOne {
+	oido.oid.mutation.Where(ps...)
+	return oido
+}
+
+// Exec executes the deletion query.
+func (oido *OperatorIdentityDeleteOne) Exec(ctx context.Context) error {
+	n, err := oido.oid.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{operatoridentity.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oido *OperatorIdentityDeleteOne) ExecX(ctx context.Context) {
+	if err := oido.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,606 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
+	"via/internal/ent/predicate"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// OperatorIdentityQuery is the builder for querying OperatorIdentity entities.
+type OperatorIdentityQuery struct {
+	config
+	ctx          *QueryContext
+	order        []operatoridentity.OrderOption
+	inters       []Interceptor
+	predicates   []predicate.OperatorIdentity
+	withOperator *OperatorQuery
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the OperatorIdentityQuery builder.
+func (oiq *OperatorIdentityQuery) Where(ps ...predicate.OperatorIdentity) *OperatorIdentityQuery {
+	oiq.predicates = append(oiq.predicates, ps...)
+	return oiq
+}
+
+// Limit the number of records to be returned by this query.
+func (oiq *OperatorIdentityQuery) Limit(limit int) *OperatorIdentityQuery {
+	oiq.ctx.Limit = &limit
+	return oiq
+}
+
+// Offset to start from.
+func (oiq *OperatorIdentityQuery) Offset(offset int) *OperatorIdentityQuery {
+	oiq.ctx.Offset = &offset
+	return oiq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (oiq *OperatorIdentityQuery) Unique(unique bool) *OperatorIdentityQuery {
+	oiq.ctx.Unique = &unique
+	return oiq
+}
+
+// Order specifies how the records should be ordered.
+func (oiq *OperatorIdentityQuery) Order(o ...operatoridentity.OrderOption) *OperatorIdentityQuery {
+	oiq.order = append(oiq.order, o...)
+	return oiq
+}
+
+// QueryOperator chains the current query on the "operator" edge.
+func (oiq *OperatorIdentityQuery) QueryOperator() *OperatorQuery {
+	query := (&OperatorClient{config: oiq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := oiq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := oiq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(operatoridentity.Table, operatoridentity.FieldID, selector),
+			sqlgraph.To(operator.Table, operator.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, operatoridentity.OperatorTable, operatoridentity.OperatorColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(oiq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// First returns the first OperatorIdentity entity from the query.
+// Returns a *NotFoundError when no OperatorIdentity was found.
+func (oiq *OperatorIdentityQuery) First(ctx context.Context) (*OperatorIdentity, error) {
+	nodes, err := oiq.Limit(1).All(setContextOp(ctx, oiq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{operatoridentity.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (oiq *OperatorIdentityQuery) FirstX(ctx context.Context) *OperatorIdentity {
+	node, err := oiq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first OperatorIdentity ID from the query.
+// Returns a *NotFoundError when no OperatorIdentity ID was found.
+func (oiq *OperatorIdentityQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = oiq.Limit(1).IDs(setContextOp(ctx, oiq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{operatoridentity.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (oiq *OperatorIdentityQuery) FirstIDX(ctx context.Context) int {
+	id, err := oiq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single OperatorIdentity entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one OperatorIdentity entity is found.
+// Returns a *NotFoundError when no OperatorIdentity entities are found.
+func (oiq *OperatorIdentityQuery) Only(ctx context.Context) (*OperatorIdentity, error) {
+	nodes, err := oiq.Limit(2).All(setContextOp(ctx, oiq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{operatoridentity.Label}
+	default:
+		return nil, &NotSingularError{operatoridentity.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (oiq *OperatorIdentityQuery) OnlyX(ctx context.Context) *OperatorIdentity {
+	node, err := oiq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only OperatorIdentity ID in the query.
+// Returns a *NotSingularError when more than one OperatorIdentity ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (oiq *OperatorIdentityQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = oiq.Limit(2).IDs(setContextOp(ctx, oiq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{operatoridentity.Label}
+	default:
+		err = &NotSingularError{operatoridentity.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (oiq *OperatorIdentityQuery) OnlyIDX(ctx context.Context) int {
+	id, err := oiq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of OperatorIdentities.
+func (oiq *OperatorIdentityQuery) All(ctx context.Context) ([]*OperatorIdentity, error) {
+	ctx = setContextOp(ctx, oiq.ctx, ent.OpQueryAll)
+	if err := oiq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*OperatorIdentity, *OperatorIdentityQuery]()
+	return withInterceptors[[]*OperatorIdentity](ctx, oiq, qr, oiq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (oiq *OperatorIdentityQuery) AllX(ctx context.Context) []*OperatorIdentity {
+	nodes, err := oiq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of OperatorIdentity IDs.
+func (oiq *OperatorIdentityQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if oiq.ctx.Unique == nil && oiq.path != nil {
+		oiq.Unique(true)
+	}
+	ctx = setContextOp(ctx, oiq.ctx, ent.OpQueryIDs)
+	if err = oiq.Select(operatoridentity.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (oiq *OperatorIdentityQuery) IDsX(ctx context.Context) []int {
+	ids, err := oiq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (oiq *OperatorIdentityQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, oiq.ctx, ent.OpQueryCount)
+	if err := oiq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, oiq, querierCount[*OperatorIdentityQuery](), oiq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (oiq *OperatorIdentityQuery) CountX(ctx context.Context) int {
+	count, err := oiq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (oiq *OperatorIdentityQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, oiq.ctx, ent.OpQueryExist)
+	switch _, err := oiq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (oiq *OperatorIdentityQuery) ExistX(ctx context.Context) bool {
+	exist, err := oiq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the OperatorIdentityQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (oiq *OperatorIdentityQuery) Clone() *OperatorIdentityQuery {
+	if oiq == nil {
+		return nil
+	}
+	return &OperatorIdentityQuery{
+		config:       oiq.config,
+		ctx:          oiq.ctx.Clone(),
+		order:        append([]operatoridentity.OrderOption{}, oiq.order...),
+		inters:       append([]Interceptor{}, oiq.inters...),
+		predicates:   append([]predicate.OperatorIdentity{}, oiq.predicates...),
+		withOperator: oiq.withOperator.Clone(),
+		// clone intermediate query.
+		sql:  oiq.sql.Clone(),
+		path: oiq.path,
+	}
+}
+
+// WithOperator tells the query-builder to eager-load the nodes that are connected to
+// the "operator" edge. The optional arguments are used to configure the query builder of the edge.
+func (oiq *OperatorIdentityQuery) WithOperator(opts ...func(*OperatorQuery)) *OperatorIdentityQuery {
+	query := (&OperatorClient{config: oiq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	oiq.withOperator = query
+	return oiq
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		Issuer string `json:"issuer,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.OperatorIdentity.Query().
+//		GroupBy(operatoridentity.FieldIssuer).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (oiq *OperatorIdentityQuery) GroupBy(field string, fields ...string) *OperatorIdentityGroupBy {
+	oiq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &OperatorIdentityGroupBy{build: oiq}
+	grbuild.flds = &oiq.ctx.Fields
+	grbuild.label = operatoridentity.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		Issuer string `json:"issuer,omitempty"`
+//	}
+//
+//	client.OperatorIdentity.Query().
+//		Select(operatoridentity.FieldIssuer).
+//		Scan(ctx, &v)
+func (oiq *OperatorIdentityQuery) Select(fields ...string) *OperatorIdentitySelect {
+	oiq.ctx.Fields = append(oiq.ctx.Fields, fields...)
+	sbuild := &OperatorIdentitySelect{OperatorIdentityQuery: oiq}
+	sbuild.label = operatoridentity.Label
+	sbuild.flds, sbuild.scan = &oiq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a OperatorIdentitySelect configured with the given aggregations.
+func (oiq *OperatorIdentityQuery) Aggregate(fns ...AggregateFunc) *OperatorIdentitySelect {
+	return oiq.Select().Aggregate(fns...)
+}
+
+func (oiq *OperatorIdentityQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range oiq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, oiq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range oiq.ctx.Fields {
+		if !operatoridentity.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if oiq.path != nil {
+		prev, err := oiq.path(ctx)
+		if err != nil {
+			return err
+		}
+		oiq.sql = prev
+	}
+	return nil
+}
+
+func (oiq *OperatorIdentityQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*OperatorIdentity, error) {
+	var (
+		nodes       = []*OperatorIdentity{}
+		_spec       = oiq.querySpec()
+		loadedTypes = [1]bool{
+			oiq.withOperator != nil,
+		}
+	)
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*OperatorIdentity).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &OperatorIdentity{config: oiq.config}
+		nodes = append(nodes, node)
+		node.Edges.loadedTypes = loadedTypes
+		return node.assignValues(columns, values)
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, oiq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	if query := oiq.withOperator; query != nil {
+		if err := oiq.loadOperator(ctx, query, nodes, nil,
+			func(n *OperatorIdentity, e *Operator) { n.Edges.Operator = e }); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (oiq *OperatorIdentityQuery) loadOperator(ctx context.Context, query *OperatorQuery, nodes []*OperatorIdentity, init func(*OperatorIdentity), assign func(*OperatorIdentity, *Operator)) error {
+	ids := make([]int, 0, len(nodes))
+	nodeids := make(map[int][]*OperatorIdentity)
+	for i := range nodes {
+		fk := nodes[i].OperatorID
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(operator.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "operator_id" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+
+func (oiq *OperatorIdentityQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := oiq.querySpec()
+	_spec.Node.Columns = oiq.ctx.Fields
+	if len(oiq.ctx.Fields) > 0 {
+		_spec.Unique = oiq.ctx.Unique != nil && *oiq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, oiq.driver, _spec)
+}
+
+func (oiq *OperatorIdentityQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(operatoridentity.Table, operatoridentity.Columns, sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt))
+	_spec.From = oiq.sql
+	if unique := oiq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if oiq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := oiq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, operatoridentity.FieldID)
+		for i := range fields {
+			if fields[i] != operatoridentity.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+		if oiq.withOperator != nil {
+			_spec.Node.AddColumnOnce(operatoridentity.FieldOperatorID)
+		}
+	}
+	if ps := oiq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := oiq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := oiq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := oiq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (oiq *OperatorIdentityQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(oiq.driver.Dialect())
+	t1 := builder.Table(operatoridentity.Table)
+	columns := oiq.ctx.Fields
+	if len(columns) == 0 {
+		columns = operatoridentity.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if oiq.sql != nil {
+		selector = oiq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if oiq.ctx.Unique != nil && *oiq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, p := range oiq.predicates {
+		p(selector)
+	}
+	for _, p := range oiq.order {
+		p(selector)
+	}
+	if offset := oiq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := oiq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// OperatorIdentityGroupBy is the group-by builder for OperatorIdentity entities.
+type OperatorIdentityGroupBy struct {
+	selector
+	build *OperatorIdentityQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (oigb *OperatorIdentityGroupBy) Aggregate(fns ...AggregateFunc) *OperatorIdentityGroupBy {
+	oigb.fns = append(oigb.fns, fns...)
+	return oigb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (oigb *OperatorIdentityGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, oigb.build.ctx, ent.OpQueryGroupBy)
+	if err := oigb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*OperatorIdentityQuery, *OperatorIdentityGroupBy](ctx, oigb.build, oigb, oigb.build.inters, v)
+}
+
+func (oigb *OperatorIdentityGroupBy) sqlScan(ctx context.Context, root *OperatorIdentityQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(oigb.fns))
+	for _, fn := range oigb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*oigb.flds)+len(oigb.fns))
+		for _, f := range *oigb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*oigb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := oigb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// OperatorIdentitySelect is the builder for selecting fields of OperatorIdentity entities.
+type OperatorIdentitySelect struct {
+	*OperatorIdentityQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (ois *OperatorIdentitySelect) Aggregate(fns ...AggregateFunc) *OperatorIdentitySelect {
+	ois.fns = append(ois.fns, fns...)
+	return ois
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ois *OperatorIdentitySelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ois.ctx, ent.OpQuerySelect)
+	if err := ois.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*OperatorIdentityQuery, *OperatorIdentitySelect](ctx, ois.OperatorIdentityQuery, ois, ois.inters, v)
+}
+
+func (ois *OperatorIdentitySelect) sqlScan(ctx context.Context, root *OperatorIdentityQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(ois.fns))
+	for _, fn := range ois.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*ois.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ois.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
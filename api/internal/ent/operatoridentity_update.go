@@ -0,0 +1,232 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"via/internal/ent/operatoridentity"
+	"via/internal/ent/predicate"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// OperatorIdentityUpdate is the builder for updating OperatorIdentity entities.
+type OperatorIdentityUpdate struct {
+	config
+	hooks    []Hook
+	mutation *OperatorIdentityMutation
+}
+
+// Where appends a list predicates to the OperatorIdentityUpdate builder.
+func (oiu *OperatorIdentityUpdate) Where(ps ...predicate.OperatorIdentity) *OperatorIdentityUpdate {
+	oiu.mutation.Where(ps...)
+	return oiu
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (oiu *OperatorIdentityUpdate) SetCreatedAt(t time.Time) *OperatorIdentityUpdate {
+	oiu.mutation.SetCreatedAt(t)
+	return oiu
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (oiu *OperatorIdentityUpdate) SetNillableCreatedAt(t *time.Time) *OperatorIdentityUpdate {
+	if t != nil {
+		oiu.SetCreatedAt(*t)
+	}
+	return oiu
+}
+
+// Mutation returns the OperatorIdentityMutation object of the builder.
+func (oiu *OperatorIdentityUpdate) Mutation() *OperatorIdentityMutation {
+	return oiu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (oiu *OperatorIdentityUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, oiu.sqlSave, oiu.mutation, oiu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (oiu *OperatorIdentityUpdate) SaveX(ctx context.Context) int {
+	affected, err := oiu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (oiu *OperatorIdentityUpdate) Exec(ctx context.Context) error {
+	_, err := oiu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oiu *OperatorIdentityUpdate) ExecX(ctx context.Context) {
+	if err := oiu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (oiu *OperatorIdentityUpdate) check() error {
+	if oiu.mutation.OperatorCleared() && len(oiu.mutation.OperatorIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "OperatorIdentity.operator"`)
+	}
+	return nil
+}
+
+func (oiu *OperatorIdentityUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := oiu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(operatoridentity.Table, operatoridentity.Columns, sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt))
+	if ps := oiu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := oiu.mutation.CreatedAt(); ok {
+		_spec.SetField(operatoridentity.FieldCreatedAt, field.TypeTime, value)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, oiu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{operatoridentity.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	oiu.mutation.done = true
+	return n, nil
+}
+
+// OperatorIdentityUpdateOne is the builder for updating a single OperatorIdentity entity.
+type OperatorIdentityUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *OperatorIdentityMutation
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (oiuo *OperatorIdentityUpdateOne) SetCreatedAt(t time.Time) *OperatorIdentityUpdateOne {
+	oiuo.mutation.SetCreatedAt(t)
+	return oiuo
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (oiuo *OperatorIdentityUpdateOne) SetNillableCreatedAt(t *time.Time) *OperatorIdentityUpdateOne {
+	if t != nil {
+		oiuo.SetCreatedAt(*t)
+	}
+	return oiuo
+}
+
+// Mutation returns the OperatorIdentityMutation object of the builder.
+func (oiuo *OperatorIdentityUpdateOne) Mutation() *OperatorIdentityMutation {
+	return oiuo.mutation
+}
+
+// Where appends a list predicates to the OperatorIdentityUpdate builder.
+func (oiuo *OperatorIdentityUpdateOne) Where(ps ...predicate.OperatorIdentity) *OperatorIdentityUpdateOne {
+	oiuo.mutation.Where(ps...)
+	return oiuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (oiuo *OperatorIdentityUpdateOne) Select(field string, fields ...string) *OperatorIdentityUpdateOne {
+	oiuo.fields = append([]string{field}, fields...)
+	return oiuo
+}
+
+// Save executes the query and returns the updated OperatorIdentity entity.
+func (oiuo *OperatorIdentityUpdateOne) Save(ctx context.Context) (*OperatorIdentity, error) {
+	return withHooks(ctx, oiuo.sqlSave, oiuo.mutation, oiuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (oiuo *OperatorIdentityUpdateOne) SaveX(ctx context.Context) *OperatorIdentity {
+	node, err := oiuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (oiuo *OperatorIdentityUpdateOne) Exec(ctx context.Context) error {
+	_, err := oiuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (oiuo *OperatorIdentityUpdateOne) ExecX(ctx context.Context) {
+	if err := oiuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (oiuo *OperatorIdentityUpdateOne) check() error {
+	if oiuo.mutation.OperatorCleared() && len(oiuo.mutation.OperatorIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "OperatorIdentity.operator"`)
+	}
+	return nil
+}
+
+func (oiuo *OperatorIdentityUpdateOne) sqlSave(ctx context.Context) (_node *OperatorIdentity, err error) {
+	if err := oiuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(operatoridentity.Table, operatoridentity.Columns, sqlgraph.NewFieldSpec(operatoridentity.FieldID, field.TypeInt))
+	id, ok := oiuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "OperatorIdentity.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := oiuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, operatoridentity.FieldID)
+		for _, f := range fields {
+			if !operatoridentity.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != operatoridentity.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := oiuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := oiuo.mutation.CreatedAt(); ok {
+		_spec.SetField(operatoridentity.FieldCreatedAt, field.TypeTime, value)
+	}
+	_node = &OperatorIdentity{config: oiuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, oiuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{operatoridentity.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	oiuo.mutation.done = true
+	return _node, nil
+}
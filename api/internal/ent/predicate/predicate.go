@@ -6,6 +6,12 @@ import (
 	"entgo.io/ent/dialect/sql"
 )
 
+// AuditEntry is the predicate function for auditentry builders.
+type AuditEntry func(*sql.Selector)
+
+// Device is the predicate function for device builders.
+type Device func(*sql.Selector)
+
 // Guide is the predicate function for guide builders.
 type Guide func(*sql.Selector)
 
@@ -14,3 +20,6 @@ type GuideHistory func(*sql.Selector)
 
 // Operator is the predicate function for operator builders.
 type Operator func(*sql.Selector)
+
+// OperatorIdentity is the predicate function for operatoridentity builders.
+type OperatorIdentity func(*sql.Selector)
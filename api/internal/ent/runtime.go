@@ -4,9 +4,12 @@ package ent
 
 import (
 	"time"
+	"via/internal/ent/auditentry"
+	"via/internal/ent/device"
 	"via/internal/ent/guide"
 	"via/internal/ent/guidehistory"
 	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
 	"via/internal/ent/schema"
 )
 
@@ -14,6 +17,158 @@ import (
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	auditentryFields := schema.AuditEntry{}.Fields()
+	_ = auditentryFields
+	// auditentryDescActor is the schema descriptor for actor field.
+	auditentryDescActor := auditentryFields[0].Descriptor()
+	// auditentry.ActorValidator is a validator for the "actor" field. It is called by the builders before save.
+	auditentry.ActorValidator = func() func(string) error {
+		validators := auditentryDescActor.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(actor string) error {
+			for _, fn := range fns {
+				if err := fn(actor); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// auditentryDescAction is the schema descriptor for action field.
+	auditentryDescAction := auditentryFields[1].Descriptor()
+	// auditentry.ActionValidator is a validator for the "action" field. It is called by the builders before save.
+	auditentry.ActionValidator = func() func(string) error {
+		validators := auditentryDescAction.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(action string) error {
+			for _, fn := range fns {
+				if err := fn(action); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// auditentryDescTargetType is the schema descriptor for target_type field.
+	auditentryDescTargetType := auditentryFields[2].Descriptor()
+	// auditentry.TargetTypeValidator is a validator for the "target_type" field. It is called by the builders before save.
+	auditentry.TargetTypeValidator = auditentryDescTargetType.Validators[0].(func(string) error)
+	// auditentryDescTargetID is the schema descriptor for target_id field.
+	auditentryDescTargetID := auditentryFields[3].Descriptor()
+	// auditentry.TargetIDValidator is a validator for the "target_id" field. It is called by the builders before save.
+	auditentry.TargetIDValidator = auditentryDescTargetID.Validators[0].(func(string) error)
+	// auditentryDescRequestID is the schema descriptor for request_id field.
+	auditentryDescRequestID := auditentryFields[4].Descriptor()
+	// auditentry.RequestIDValidator is a validator for the "request_id" field. It is called by the builders before save.
+	auditentry.RequestIDValidator = auditentryDescRequestID.Validators[0].(func(string) error)
+	// auditentryDescIP is the schema descriptor for ip field.
+	auditentryDescIP := auditentryFields[5].Descriptor()
+	// auditentry.IPValidator is a validator for the "ip" field. It is called by the builders before save.
+	auditentry.IPValidator = auditentryDescIP.Validators[0].(func(string) error)
+	// auditentryDescPrevHash is the schema descriptor for prev_hash field.
+	auditentryDescPrevHash := auditentryFields[8].Descriptor()
+	// auditentry.PrevHashValidator is a validator for the "prev_hash" field. It is called by the builders before save.
+	auditentry.PrevHashValidator = auditentryDescPrevHash.Validators[0].(func(string) error)
+	// auditentryDescHash is the schema descriptor for hash field.
+	auditentryDescHash := auditentryFields[9].Descriptor()
+	// auditentry.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	auditentry.HashValidator = func() func(string) error {
+		validators := auditentryDescHash.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(hash string) error {
+			for _, fn := range fns {
+				if err := fn(hash); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// auditentryDescCreatedAt is the schema descriptor for created_at field.
+	auditentryDescCreatedAt := auditentryFields[10].Descriptor()
+	// auditentry.DefaultCreatedAt holds the default value on creation for the created_at field.
+	auditentry.DefaultCreatedAt = auditentryDescCreatedAt.Default.(func() time.Time)
+	deviceFields := schema.Device{}.Fields()
+	_ = deviceFields
+	// deviceDescName is the schema descriptor for name field.
+	deviceDescName := deviceFields[0].Descriptor()
+	// device.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	device.NameValidator = func() func(string) error {
+		validators := deviceDescName.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(name string) error {
+			for _, fn := range fns {
+				if err := fn(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// deviceDescKind is the schema descriptor for kind field.
+	deviceDescKind := deviceFields[1].Descriptor()
+	// device.KindValidator is a validator for the "kind" field. It is called by the builders before save.
+	device.KindValidator = func() func(string) error {
+		validators := deviceDescKind.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(kind string) error {
+			for _, fn := range fns {
+				if err := fn(kind); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// deviceDescBranch is the schema descriptor for branch field.
+	deviceDescBranch := deviceFields[2].Descriptor()
+	// device.BranchValidator is a validator for the "branch" field. It is called by the builders before save.
+	device.BranchValidator = func() func(string) error {
+		validators := deviceDescBranch.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(branch string) error {
+			for _, fn := range fns {
+				if err := fn(branch); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// deviceDescTokenHash is the schema descriptor for token_hash field.
+	deviceDescTokenHash := deviceFields[3].Descriptor()
+	// device.TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
+	device.TokenHashValidator = deviceDescTokenHash.Validators[0].(func(string) error)
+	// deviceDescPairingCodeHash is the schema descriptor for pairing_code_hash field.
+	deviceDescPairingCodeHash := deviceFields[4].Descriptor()
+	// device.PairingCodeHashValidator is a validator for the "pairing_code_hash" field. It is called by the builders before save.
+	device.PairingCodeHashValidator = deviceDescPairingCodeHash.Validators[0].(func(string) error)
+	// deviceDescPrivacy is the schema descriptor for privacy field.
+	deviceDescPrivacy := deviceFields[7].Descriptor()
+	// device.PrivacyValidator is a validator for the "privacy" field. It is called by the builders before save.
+	device.PrivacyValidator = deviceDescPrivacy.Validators[0].(func(string) error)
+	// deviceDescCreatedAt is the schema descriptor for created_at field.
+	deviceDescCreatedAt := deviceFields[9].Descriptor()
+	// device.DefaultCreatedAt holds the default value on creation for the created_at field.
+	device.DefaultCreatedAt = deviceDescCreatedAt.Default.(func() time.Time)
 	guideFields := schema.Guide{}.Fields()
 	_ = guideFields
 	// guideDescViaGuideID is the schema descriptor for via_guide_id field.
@@ -53,8 +208,26 @@ func init() {
 			return nil
 		}
 	}()
+	// guideDescRecipientNormalized is the schema descriptor for recipient_normalized field.
+	guideDescRecipientNormalized := guideFields[2].Descriptor()
+	// guide.RecipientNormalizedValidator is a validator for the "recipient_normalized" field. It is called by the builders before save.
+	guide.RecipientNormalizedValidator = func() func(string) error {
+		validators := guideDescRecipientNormalized.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(recipient_normalized string) error {
+			for _, fn := range fns {
+				if err := fn(recipient_normalized); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
 	// guideDescStatus is the schema descriptor for status field.
-	guideDescStatus := guideFields[2].Descriptor()
+	guideDescStatus := guideFields[3].Descriptor()
 	// guide.StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	guide.StatusValidator = func() func(string) error {
 		validators := guideDescStatus.Validators
@@ -71,8 +244,12 @@ func init() {
 			return nil
 		}
 	}()
+	// guideDescStatusReason is the schema descriptor for status_reason field.
+	guideDescStatusReason := guideFields[4].Descriptor()
+	// guide.StatusReasonValidator is a validator for the "status_reason" field. It is called by the builders before save.
+	guide.StatusReasonValidator = guideDescStatusReason.Validators[0].(func(string) error)
 	// guideDescPayment is the schema descriptor for payment field.
-	guideDescPayment := guideFields[3].Descriptor()
+	guideDescPayment := guideFields[5].Descriptor()
 	// guide.PaymentValidator is a validator for the "payment" field. It is called by the builders before save.
 	guide.PaymentValidator = func() func(string) error {
 		validators := guideDescPayment.Validators
@@ -91,11 +268,11 @@ func init() {
 		}
 	}()
 	// guideDescCreatedAt is the schema descriptor for created_at field.
-	guideDescCreatedAt := guideFields[5].Descriptor()
+	guideDescCreatedAt := guideFields[9].Descriptor()
 	// guide.DefaultCreatedAt holds the default value on creation for the created_at field.
 	guide.DefaultCreatedAt = guideDescCreatedAt.Default.(func() time.Time)
 	// guideDescUpdatedAt is the schema descriptor for updated_at field.
-	guideDescUpdatedAt := guideFields[6].Descriptor()
+	guideDescUpdatedAt := guideFields[10].Descriptor()
 	// guide.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	guide.DefaultUpdatedAt = guideDescUpdatedAt.Default.(func() time.Time)
 	// guide.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
@@ -120,12 +297,16 @@ func init() {
 			return nil
 		}
 	}()
+	// guidehistoryDescReason is the schema descriptor for reason field.
+	guidehistoryDescReason := guidehistoryFields[2].Descriptor()
+	// guidehistory.ReasonValidator is a validator for the "reason" field. It is called by the builders before save.
+	guidehistory.ReasonValidator = guidehistoryDescReason.Validators[0].(func(string) error)
 	// guidehistoryDescOperatorID is the schema descriptor for operator_id field.
-	guidehistoryDescOperatorID := guidehistoryFields[2].Descriptor()
+	guidehistoryDescOperatorID := guidehistoryFields[3].Descriptor()
 	// guidehistory.OperatorIDValidator is a validator for the "operator_id" field. It is called by the builders before save.
 	guidehistory.OperatorIDValidator = guidehistoryDescOperatorID.Validators[0].(func(int) error)
 	// guidehistoryDescCreatedAt is the schema descriptor for created_at field.
-	guidehistoryDescCreatedAt := guidehistoryFields[3].Descriptor()
+	guidehistoryDescCreatedAt := guidehistoryFields[4].Descriptor()
 	// guidehistory.DefaultCreatedAt holds the default value on creation for the created_at field.
 	guidehistory.DefaultCreatedAt = guidehistoryDescCreatedAt.Default.(func() time.Time)
 	operatorFields := schema.Operator{}.Fields()
@@ -170,14 +351,108 @@ func init() {
 	operatorDescEnabled := operatorFields[2].Descriptor()
 	// operator.DefaultEnabled holds the default value on creation for the enabled field.
 	operator.DefaultEnabled = operatorDescEnabled.Default.(bool)
+	// operatorDescRole is the schema descriptor for role field.
+	operatorDescRole := operatorFields[3].Descriptor()
+	// operator.DefaultRole holds the default value on creation for the role field.
+	operator.DefaultRole = operatorDescRole.Default.(string)
+	// operator.RoleValidator is a validator for the "role" field. It is called by the builders before save.
+	operator.RoleValidator = func() func(string) error {
+		validators := operatorDescRole.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(role string) error {
+			for _, fn := range fns {
+				if err := fn(role); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// operatorDescAvailability is the schema descriptor for availability field.
+	operatorDescAvailability := operatorFields[4].Descriptor()
+	// operator.DefaultAvailability holds the default value on creation for the availability field.
+	operator.DefaultAvailability = operatorDescAvailability.Default.(string)
+	// operator.AvailabilityValidator is a validator for the "availability" field. It is called by the builders before save.
+	operator.AvailabilityValidator = func() func(string) error {
+		validators := operatorDescAvailability.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(availability string) error {
+			for _, fn := range fns {
+				if err := fn(availability); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// operatorDescPasswordHash is the schema descriptor for password_hash field.
+	operatorDescPasswordHash := operatorFields[5].Descriptor()
+	// operator.PasswordHashValidator is a validator for the "password_hash" field. It is called by the builders before save.
+	operator.PasswordHashValidator = operatorDescPasswordHash.Validators[0].(func(string) error)
+	// operatorDescTotpSecret is the schema descriptor for totp_secret field.
+	operatorDescTotpSecret := operatorFields[6].Descriptor()
+	// operator.TotpSecretValidator is a validator for the "totp_secret" field. It is called by the builders before save.
+	operator.TotpSecretValidator = operatorDescTotpSecret.Validators[0].(func(string) error)
 	// operatorDescCreatedAt is the schema descriptor for created_at field.
-	operatorDescCreatedAt := operatorFields[3].Descriptor()
+	operatorDescCreatedAt := operatorFields[7].Descriptor()
 	// operator.DefaultCreatedAt holds the default value on creation for the created_at field.
 	operator.DefaultCreatedAt = operatorDescCreatedAt.Default.(func() time.Time)
 	// operatorDescUpdatedAt is the schema descriptor for updated_at field.
-	operatorDescUpdatedAt := operatorFields[4].Descriptor()
+	operatorDescUpdatedAt := operatorFields[8].Descriptor()
 	// operator.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	operator.DefaultUpdatedAt = operatorDescUpdatedAt.Default.(func() time.Time)
 	// operator.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
 	operator.UpdateDefaultUpdatedAt = operatorDescUpdatedAt.UpdateDefault.(func() time.Time)
+	operatoridentityFields := schema.OperatorIdentity{}.Fields()
+	_ = operatoridentityFields
+	// operatoridentityDescIssuer is the schema descriptor for issuer field.
+	operatoridentityDescIssuer := operatoridentityFields[0].Descriptor()
+	// operatoridentity.IssuerValidator is a validator for the "issuer" field. It is called by the builders before save.
+	operatoridentity.IssuerValidator = func() func(string) error {
+		validators := operatoridentityDescIssuer.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(issuer string) error {
+			for _, fn := range fns {
+				if err := fn(issuer); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// operatoridentityDescSubject is the schema descriptor for subject field.
+	operatoridentityDescSubject := operatoridentityFields[1].Descriptor()
+	// operatoridentity.SubjectValidator is a validator for the "subject" field. It is called by the builders before save.
+	operatoridentity.SubjectValidator = func() func(string) error {
+		validators := operatoridentityDescSubject.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(subject string) error {
+			for _, fn := range fns {
+				if err := fn(subject); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// operatoridentityDescOperatorID is the schema descriptor for operator_id field.
+	operatoridentityDescOperatorID := operatoridentityFields[2].Descriptor()
+	// operatoridentity.OperatorIDValidator is a validator for the "operator_id" field. It is called by the builders before save.
+	operatoridentity.OperatorIDValidator = operatoridentityDescOperatorID.Validators[0].(func(int) error)
+	// operatoridentityDescCreatedAt is the schema descriptor for created_at field.
+	operatoridentityDescCreatedAt := operatoridentityFields[3].Descriptor()
+	// operatoridentity.DefaultCreatedAt holds the default value on creation for the created_at field.
+	operatoridentity.DefaultCreatedAt = operatoridentityDescCreatedAt.Default.(func() time.Time)
 }
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// AuditEntry holds the schema definition for the AuditEntry entity, an operator, admin or device action.
+// Entries are append only, each one is chained to the previous one by its hash.
+type AuditEntry struct {
+	ent.Schema
+}
+
+// Fields of the AuditEntry.
+func (AuditEntry) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("actor").
+			Immutable().
+			NotEmpty().
+			MaxLen(100),
+		field.String("action").
+			Immutable().
+			NotEmpty().
+			MaxLen(50),
+		field.String("target_type").
+			Immutable().
+			Optional().
+			MaxLen(50),
+		field.String("target_id").
+			Immutable().
+			Optional().
+			MaxLen(100),
+		field.String("request_id").
+			Immutable().
+			Optional().
+			MaxLen(100),
+		field.String("ip").
+			Immutable().
+			Optional().
+			MaxLen(64),
+		field.Text("before").
+			Immutable().
+			Optional(),
+		field.Text("after").
+			Immutable().
+			Optional(),
+		field.String("prev_hash").
+			Immutable().
+			Optional().
+			MaxLen(64),
+		field.String("hash").
+			Immutable().
+			NotEmpty().
+			MaxLen(64),
+		field.Time("created_at").
+			Immutable().
+			Default(time.Now).
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
+	}
+}
+
+// Indexes of the AuditEntry, searched by actor, action, target and date.
+func (AuditEntry) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("actor"),
+		index.Fields("action"),
+		index.Fields("target_type", "target_id"),
+		index.Fields("created_at"),
+		index.Fields("hash").
+			Unique(),
+	}
+}
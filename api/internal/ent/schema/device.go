@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Device holds the schema definition for the Device entity, a kiosk or monitor of a branch paired by an admin.
+type Device struct {
+	ent.Schema
+}
+
+// Fields of the Device.
+func (Device) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").
+			NotEmpty().
+			MaxLen(200),
+		field.String("kind").
+			Immutable().
+			NotEmpty().
+			MaxLen(20),
+		field.String("branch").
+			Immutable().
+			NotEmpty().
+			MaxLen(20),
+		field.String("token_hash").
+			Optional().
+			Sensitive().
+			MaxLen(64),
+		field.String("pairing_code_hash").
+			Optional().
+			Sensitive().
+			MaxLen(64),
+		field.Time("pairing_expires_at").
+			Optional().
+			Nillable(),
+		field.Time("paired_at").
+			Optional().
+			Nillable(),
+		field.String("privacy").
+			Optional().
+			MaxLen(20),
+		field.Time("revoked_at").
+			Optional().
+			Nillable(),
+		field.Time("created_at").
+			Default(time.Now).
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
+	}
+}
+
+// Indexes of the Device, a pairing code is looked up by its hash.
+func (Device) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("pairing_code_hash").
+			Unique(),
+	}
+}
@@ -2,10 +2,13 @@ package schema
 
 import (
 	"time"
+	"via/internal/model"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 )
 
 // Guide holds the schema definition for the Guide entity.
@@ -25,20 +28,35 @@ func (Guide) Fields() []ent.Field {
 			NotEmpty().
 			Immutable().
 			MaxLen(100),
+		// lower cased and without accents, see biz_guide_search.NormalizeName
+		field.String("recipient_normalized").
+			NotEmpty().
+			Immutable().
+			MaxLen(100),
 		field.String("status").
 			NotEmpty().
 			MaxLen(30),
+		field.String("status_reason").
+			Optional().
+			MaxLen(100),
 		field.String("payment").
 			NotEmpty().
 			Immutable().
 			MaxLen(1).
 			MinLen(1),
 		field.Int("operator_id"),
+		field.JSON("via_snapshot", model.ViaGuide{}).
+			Optional(),
+		field.Time("via_synced_at").
+			Optional().
+			Nillable(),
 		field.Time("created_at").
-			Default(time.Now),
+			Default(time.Now).
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
 		field.Time("updated_at").
 			Default(time.Now).
-			UpdateDefault(time.Now),
+			UpdateDefault(time.Now).
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
 	}
 }
 
@@ -54,3 +72,17 @@ func (Guide) Edges() []ent.Edge {
 		edge.To("history", GuideHistory.Type),
 	}
 }
+
+// Indexes of the Guide.
+func (Guide) Indexes() []ent.Index {
+	return []ent.Index{
+		// open guides are queried by status on every SSE refresh
+		index.Fields("status", "updated_at"),
+		// operator search: recipient substrings, guide id prefixes and date ranges
+		index.Fields("recipient_normalized").
+			Annotations(entsql.IndexType("GIN"), entsql.OpClass("gin_trgm_ops")),
+		index.Fields("via_guide_id").
+			Annotations(entsql.OpClass("varchar_pattern_ops")),
+		index.Fields("created_at"),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 )
@@ -22,11 +23,16 @@ func (GuideHistory) Fields() []ent.Field {
 			Immutable().
 			NotEmpty().
 			MaxLen(30),
+		field.String("reason").
+			Immutable().
+			Optional().
+			MaxLen(100),
 		field.Int("operator_id").
 			Immutable().
 			Positive(),
 		field.Time("created_at").
-			Default(time.Now),
+			Default(time.Now).
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
 	}
 }
 
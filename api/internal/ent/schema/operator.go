@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 )
@@ -27,11 +28,29 @@ func (Operator) Fields() []ent.Field {
 			MaxLen(200),
 		field.Bool("enabled").
 			Default(false),
+		field.String("role").
+			NotEmpty().
+			MaxLen(20).
+			Default("operator"),
+		field.String("availability").
+			NotEmpty().
+			MaxLen(20).
+			Default("offline"),
+		field.String("password_hash").
+			Optional().
+			Sensitive().
+			MaxLen(100),
+		field.String("totp_secret").
+			Optional().
+			Sensitive().
+			MaxLen(100),
 		field.Time("created_at").
-			Default(time.Now),
+			Default(time.Now).
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
 		field.Time("updated_at").
 			Default(time.Now).
-			UpdateDefault(time.Now),
+			UpdateDefault(time.Now).
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
 	}
 }
 
@@ -40,5 +59,6 @@ func (Operator) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("guides", Guide.Type),
 		edge.To("guide_history", GuideHistory.Type),
+		edge.To("identities", OperatorIdentity.Type),
 	}
 }
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// OperatorIdentity holds the schema definition for the OperatorIdentity entity, an IdP account the operator logs in with.
+type OperatorIdentity struct {
+	ent.Schema
+}
+
+// Fields of the OperatorIdentity.
+func (OperatorIdentity) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("issuer").
+			Immutable().
+			NotEmpty().
+			MaxLen(500),
+		field.String("subject").
+			Immutable().
+			NotEmpty().
+			MaxLen(255),
+		field.Int("operator_id").
+			Immutable().
+			Positive(),
+		field.Time("created_at").
+			Default(time.Now).
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
+	}
+}
+
+// Edges of the OperatorIdentity.
+func (OperatorIdentity) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("operator", Operator.Type).
+			Ref("identities").
+			Required().
+			Unique().
+			Field("operator_id").
+			Immutable(),
+	}
+}
+
+// Indexes of the OperatorIdentity, an IdP account belongs to a single operator.
+func (OperatorIdentity) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("issuer", "subject").
+			Unique(),
+	}
+}
@@ -12,12 +12,18 @@ import (
 // Tx is a transactional client that is created by calling Client.Tx().
 type Tx struct {
 	config
+	// AuditEntry is the client for interacting with the AuditEntry builders.
+	AuditEntry *AuditEntryClient
+	// Device is the client for interacting with the Device builders.
+	Device *DeviceClient
 	// Guide is the client for interacting with the Guide builders.
 	Guide *GuideClient
 	// GuideHistory is the client for interacting with the GuideHistory builders.
 	GuideHistory *GuideHistoryClient
 	// Operator is the client for interacting with the Operator builders.
 	Operator *OperatorClient
+	// OperatorIdentity is the client for interacting with the OperatorIdentity builders.
+	OperatorIdentity *OperatorIdentityClient
 
 	// lazily loaded.
 	client     *Client
@@ -149,9 +155,12 @@ func (tx *Tx) Client() *Client {
 }
 
 func (tx *Tx) init() {
+	tx.AuditEntry = NewAuditEntryClient(tx.config)
+	tx.Device = NewDeviceClient(tx.config)
 	tx.Guide = NewGuideClient(tx.config)
 	tx.GuideHistory = NewGuideHistoryClient(tx.config)
 	tx.Operator = NewOperatorClient(tx.config)
+	tx.OperatorIdentity = NewOperatorIdentityClient(tx.config)
 }
 
 // txDriver wraps the given dialect.Tx with a nop dialect.Driver implementation.
@@ -161,7 +170,7 @@ func (tx *Tx) init() {
 // of them in order to commit or rollback the transaction.
 //
 // If a closed transaction is embedded in one of the generated entities, and the entity
-// applies a query, for example: Guide.QueryXXX(), the query will be executed
+// applies a query, for example: AuditEntry.QueryXXX(), the query will be executed
 // through the driver which created this transaction.
 //
 // Note that txDriver is not goroutine safe.
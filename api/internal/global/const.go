@@ -8,3 +8,6 @@ const RequestIDKey contextKey = "requestID"
 const NewGuideChannel string = "new_guide"
 const GuideStatusChangeChannel string = "guide_status_change"
 const GuideAssignmentChannel string = "guide_assignment"
+const GuideSyncChannel string = "guide_sync"
+const DeviceRevokedChannel string = "device_revoked"
+const OperatorAvailableChannel string = "operator_available"
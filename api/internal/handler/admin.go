@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	biz_audit "via/internal/biz/audit"
+	biz_guide_closeday "via/internal/biz/guide/closeday"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/middleware"
+	"via/internal/model"
+	response "via/internal/response"
+)
+
+type CloseDayOutput struct {
+	Summary model.DaySummary `json:"summary"`
+}
+
+// CloseDay holds or suspends the guides still in process and archives the old ones,
+// returning the summary of the day. The summary is returned even when the archival fails.
+func CloseDay(cfg biz_guide_closeday.CloseDayCfg) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*CloseDayOutput]{}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "operator_id", r.Context().Value(middleware.OperatorIDKey))
+
+		summary, err := biz_guide_closeday.New(cfg).Close(r.Context())
+		res.Data = &CloseDayOutput{Summary: summary}
+		// the guides of the summary are left out, their count is enough to trace the closing
+		recordAudit(r, biz_audit.CLOSE_DAY, "", "", nil,
+			map[string]any{"closed": summary.Closed, "archived": summary.Archived, "failed": err != nil})
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "failed to close the day")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		logger.Info(r.Context(), "msg", "day closed", "closed", summary.Closed, "archived", summary.Archived)
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
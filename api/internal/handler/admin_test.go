@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	biz_guide_closeday "via/internal/biz/guide/closeday"
+	biz_guide_status "via/internal/biz/guide/status"
+	"via/internal/i18n"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	mock_guide_provider "via/internal/provider/guide/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/response"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCloseDay(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+	open := []model.Guide{
+		{ID: 1, ViaGuideID: "100000000001", Status: biz_guide_status.INITIAL, Operator: model.Operator{ID: 1, Account: "SYSTEM"}},
+	}
+
+	tests := []struct {
+		name           string
+		archiveErr     error
+		expectedStatus int
+	}{
+		{name: "day closed", expectedStatus: http.StatusOK},
+		{name: "archival error", archiveErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			mockGuide.On("GetGuidesByStatus", mock.Anything, mock.Anything).Return(open, nil)
+			mockGuide.On("UpdateGuide", mock.Anything, mock.Anything).Return(nil)
+			mockGuide.On("ArchiveGuides", mock.Anything, mock.Anything, mock.Anything).Return(3, tt.archiveErr)
+			mockPubSub.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			req := newOperatorRequest(http.MethodPost, "/admin/close-day", nil, 1)
+			w := httptest.NewRecorder()
+			CloseDay(biz_guide_closeday.CloseDayCfg{ArchiveDays: 90}).ServeHTTP(w, req)
+
+			var resp response.Response[CloseDayOutput]
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, 1, resp.Data.Summary.Closed)
+			assert.Equal(t, map[string]int{biz_guide_status.INITIAL: 1}, resp.Data.Summary.PerStatus)
+			assert.Len(t, resp.Data.Summary.Unresolved, 1)
+			if tt.archiveErr != nil {
+				assert.Equal(t, i18n.Get(req, i18n.MsgInternalServerError), resp.Message)
+				return
+			}
+			assert.Equal(t, 3, resp.Data.Summary.Archived)
+		})
+	}
+}
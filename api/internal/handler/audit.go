@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	biz_audit "via/internal/biz/audit"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/middleware"
+	"via/internal/model"
+	response "via/internal/response"
+)
+
+// recordAudit appends the action of the request on the target to the audit log
+func recordAudit(r *http.Request, action, targetType string, targetId any, before, after any) {
+	middleware.RecordAudit(r, biz_audit.Event{Action: action, TargetType: targetType, TargetID: fmt.Sprint(targetId),
+		Before: before, After: after})
+}
+
+type SearchAuditOutput struct {
+	Entries    []model.AuditEntry `json:"entries"`
+	NextBefore int                `json:"nextBefore,omitempty"` // before parameter of the next page
+}
+
+// SearchAudit searches the audit log by the query parameters: actor, action, targetType, targetId,
+// from and to (dates, both included), limit and before (the id the page ends before). Newest entries first
+func SearchAudit() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*SearchAuditOutput]{}
+		search, err := parseAuditSearch(r.URL.Query())
+		if err != nil {
+			log.Get().Warn(r.Context(), "msg", "invalid audit search", "filters", queryNames(r.URL.Query()), "error", err.Error())
+			res.Error = i18n.Error(r, i18n.MsgSearchInvalid, response.Details(err)...)
+			response.WriteJSON(w, r, res, http.StatusBadRequest)
+			return
+		}
+		entries, err := biz_audit.Search(r.Context(), search)
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to search audit entries")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		data := &SearchAuditOutput{Entries: entries}
+		if len(entries) == search.Limit {
+			data.NextBefore = entries[len(entries)-1].ID
+		}
+		res.Data = data
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+func parseAuditSearch(query url.Values) (model.AuditSearch, error) {
+	search := model.AuditSearch{
+		Actor:      query.Get("actor"),
+		Action:     query.Get("action"),
+		TargetType: query.Get("targetType"),
+		TargetID:   query.Get("targetId"),
+		Limit:      biz_audit.DEFAULT_LIMIT,
+	}
+	if v := query.Get("from"); v != "" {
+		from, err := time.ParseInLocation(searchDateLayout, v, time.Local)
+		if err != nil {
+			return search, response.FieldError{Field: "from", Code: response.FIELD_INVALID}
+		}
+		search.From = &from
+	}
+	if v := query.Get("to"); v != "" {
+		to, err := time.ParseInLocation(searchDateLayout, v, time.Local)
+		if err != nil {
+			return search, response.FieldError{Field: "to", Code: response.FIELD_INVALID}
+		}
+		// the whole day is included
+		to = to.AddDate(0, 0, 1)
+		search.To = &to
+	}
+	if search.From != nil && search.To != nil && !search.From.Before(*search.To) {
+		return search, response.FieldError{Field: "from", Code: response.FIELD_OUT_OF_RANGE}
+	}
+	if v := query.Get("before"); v != "" {
+		before, err := strconv.Atoi(v)
+		if err != nil || before <= 0 {
+			return search, response.FieldError{Field: "before", Code: response.FIELD_INVALID}
+		}
+		search.BeforeID = before
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return search, response.FieldError{Field: "limit", Code: response.FIELD_INVALID}
+		}
+		if limit <= 0 || limit > biz_audit.MAX_LIMIT {
+			return search, response.FieldError{Field: "limit", Code: response.FIELD_OUT_OF_RANGE}
+		}
+		search.Limit = limit
+	}
+	return search, nil
+}
+
+// VerifyAudit checks the hash chain of the whole audit log, a broken chain reports the first entry
+// changed or following a removed one
+func VerifyAudit() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*model.AuditVerification]{}
+		verification, err := biz_audit.Verify(r.Context())
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to verify audit log")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		if !verification.Valid {
+			log.Get().Warn(r.Context(), "msg", "audit log chain broken", "entry_id", verification.BrokenAt)
+		}
+		res.Data = &verification
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	biz_audit "via/internal/biz/audit"
+	"via/internal/i18n"
+	"via/internal/model"
+	audit_provider "via/internal/provider/audit"
+	mock_audit_provider "via/internal/provider/audit/mock"
+	"via/internal/response"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearchAudit(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	entries := []model.AuditEntry{{ID: 9, Action: biz_audit.GUIDE_ASSIGN}, {ID: 8, Action: biz_audit.GUIDE_ASSIGN}}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(*mock_audit_provider.MockAuditProvider)
+		expectedStatus int
+		expectedMsg    string
+		expectedNext   int
+	}{
+		{
+			name:           "invalid filter",
+			query:          "?limit=0",
+			setupMocks:     func(*mock_audit_provider.MockAuditProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    i18n.MsgSearchInvalid,
+		},
+		{
+			name:  "failed search",
+			query: "",
+			setupMocks: func(a *mock_audit_provider.MockAuditProvider) {
+				a.On("SearchAuditEntries", mock.Anything, mock.Anything).Return([]model.AuditEntry(nil), errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    i18n.MsgInternalServerError,
+		},
+		{
+			name:  "last page",
+			query: "?action=guide.assign",
+			setupMocks: func(a *mock_audit_provider.MockAuditProvider) {
+				a.On("SearchAuditEntries", mock.Anything, model.AuditSearch{Action: biz_audit.GUIDE_ASSIGN,
+					Limit: biz_audit.DEFAULT_LIMIT}).Return(entries, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "next page",
+			query: "?targetType=guide&targetId=123&before=10&limit=2",
+			setupMocks: func(a *mock_audit_provider.MockAuditProvider) {
+				a.On("SearchAuditEntries", mock.Anything, model.AuditSearch{TargetType: biz_audit.TARGET_GUIDE,
+					TargetID: "123", BeforeID: 10, Limit: 2}).Return(entries, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedNext:   8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuditProvider := new(mock_audit_provider.MockAuditProvider)
+			audit_provider.Set(mockAuditProvider)
+			tt.setupMocks(mockAuditProvider)
+
+			req := newOperatorRequest(http.MethodGet, "/admin/audit"+tt.query, nil, 1)
+			w := httptest.NewRecorder()
+
+			SearchAudit().ServeHTTP(w, req)
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp response.Response[SearchAuditOutput]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Len(t, resp.Data.Entries, 2)
+				assert.Equal(t, tt.expectedNext, resp.Data.NextBefore)
+			} else {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			}
+			mockAuditProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestParseAuditSearch(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	search, err := parseAuditSearch(map[string][]string{
+		"actor":  {"operator:42"},
+		"from":   {"2026-10-01"},
+		"to":     {"2026-10-19"},
+		"before": {"100"},
+		"limit":  {"500"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.AuditSearch{Actor: "operator:42", From: &from, To: &to, BeforeID: 100, Limit: 500}, search)
+
+	invalid := []map[string][]string{
+		{"from": {"19/10/2026"}},
+		{"to": {"19/10/2026"}},
+		{"from": {"2026-10-20"}, "to": {"2026-10-19"}},
+		{"before": {"x"}},
+		{"before": {"0"}},
+		{"limit": {"x"}},
+		{"limit": {"0"}},
+		{"limit": {"501"}},
+	}
+	for _, query := range invalid {
+		_, err := parseAuditSearch(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestVerifyAudit(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	tests := []struct {
+		name           string
+		entries        []model.AuditEntry
+		getErr         error
+		expectedStatus int
+		expectedValid  bool
+	}{
+		{name: "valid log", entries: []model.AuditEntry{}, expectedStatus: http.StatusOK, expectedValid: true},
+		{name: "broken chain", entries: []model.AuditEntry{{ID: 1, PrevHash: "x"}}, expectedStatus: http.StatusOK},
+		{name: "failed verification", getErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuditProvider := new(mock_audit_provider.MockAuditProvider)
+			audit_provider.Set(mockAuditProvider)
+			mockAuditProvider.On("GetAuditEntriesAfter", mock.Anything, 0, mock.Anything).Return(tt.entries, tt.getErr)
+
+			req := newOperatorRequest(http.MethodGet, "/admin/audit/verify", nil, 1)
+			w := httptest.NewRecorder()
+
+			VerifyAudit().ServeHTTP(w, req)
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp response.Response[model.AuditVerification]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, tt.expectedValid, resp.Data.Valid)
+			} else {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, i18n.MsgInternalServerError)
+			}
+		})
+	}
+}
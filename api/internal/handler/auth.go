@@ -3,15 +3,24 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"via/internal/auth"
+	biz_audit "via/internal/biz/audit"
 	biz_operator "via/internal/biz/operator"
 	"via/internal/ds"
 	"via/internal/i18n"
+	"via/internal/idp"
+	"via/internal/middleware"
+	"via/internal/model"
+	operator_provider "via/internal/provider/operator"
 	"via/internal/response"
+	"via/internal/session"
 
 	"via/internal/log"
 
@@ -22,19 +31,30 @@ const (
 	stateKey       = "state"
 	redirectURIKey = "redirect_uri"
 	codeKey        = "code"
+	providerKey    = "provider"
 )
 
+// Login sends the operator to the identity provider of the provider param, the default one when missing
 func Login() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		state, codeChallenge, challengeMethod, err := auth.Get().GenerateState(r.Context(), r.URL.Query().Get(redirectURIKey))
+		provider, ok := auth.Get().GetProvider(r.URL.Query().Get(providerKey))
+		if !ok {
+			log.Get().Warn(r.Context(), "msg", "unknown identity provider", "provider", r.URL.Query().Get(providerKey))
+			response.WriteJSON(w, r, response.Response[any]{
+				Error: i18n.Error(r, i18n.MsgAuthProviderNotFound),
+			}, http.StatusBadRequest)
+			return
+		}
+		state, codeChallenge, challengeMethod, err := auth.Get().GenerateState(r.Context(), provider.Name,
+			r.URL.Query().Get(redirectURIKey))
 		if err != nil {
 			log.Get().Error(r.Context(), err, "msg", "failed to generate state")
 			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgInternalServerError),
+				Error: i18n.Error(r, i18n.MsgInternalServerError),
 			}, http.StatusInternalServerError)
 			return
 		}
-		authURL := auth.Get().OAuth2Config.AuthCodeURL(state,
+		authURL := provider.OAuth2Config.AuthCodeURL(state,
 			oauth2.AccessTypeOffline,
 			oauth2.SetAuthURLParam("code_challenge_method", challengeMethod),
 			oauth2.SetAuthURLParam("code_challenge", codeChallenge))
@@ -42,34 +62,44 @@ func Login() http.Handler {
 	})
 }
 
+// LoginCallback finishes the login with the identity provider. The operator is found by the IdP
+// account, on its first login by the verified email a trusted IdP gives and the account is linked to it
+// from then on.
 func LoginCallback(cfg auth.OAuthConfig) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		code := r.URL.Query().Get(codeKey)
 		state := r.URL.Query().Get(stateKey)
-		oauth2Config := auth.Get().OAuth2Config
 		authState, found, err := auth.Get().GetState(r.Context(), state)
 		if err != nil {
 			log.Get().Error(r.Context(), err, "msg", "failed to get auth state")
 			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgInternalServerError),
+				Error: i18n.Error(r, i18n.MsgInternalServerError),
 			}, http.StatusInternalServerError)
 			return
 		}
 		if !found {
 			log.Get().Warn(r.Context(), "msg", "auth state not found")
 			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgAuthStateNotFound),
+				Error: i18n.Error(r, i18n.MsgAuthStateNotFound),
+			}, http.StatusBadRequest)
+			return
+		}
+		provider, ok := auth.Get().GetProvider(authState.Provider)
+		if !ok {
+			log.Get().Warn(r.Context(), "msg", "unknown identity provider", "provider", authState.Provider)
+			response.WriteJSON(w, r, response.Response[any]{
+				Error: i18n.Error(r, i18n.MsgAuthProviderNotFound),
 			}, http.StatusBadRequest)
 			return
 		}
-		token, err := oauth2Config.Exchange(r.Context(),
+		token, err := provider.OAuth2Config.Exchange(r.Context(),
 			code,
 			oauth2.SetAuthURLParam("code_verifier", authState.CodeVerifier))
 
 		if err != nil {
-			log.Get().Error(r.Context(), err, "msg", "failed to exchange token")
+			log.Get().Error(r.Context(), err, "msg", "failed to exchange token", "provider", provider.Name)
 			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgAuthFailedToExchangeToken),
+				Error: i18n.Error(r, i18n.MsgAuthFailedToExchangeToken),
 			}, http.StatusUnauthorized)
 			return
 		}
@@ -78,79 +108,223 @@ func LoginCallback(cfg auth.OAuthConfig) http.Handler {
 		if !ok {
 			log.Get().Error(r.Context(), nil, "msg", "missing id_token in token response")
 			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgAuthFailedToExchangeToken),
-			}, http.StatusUnauthorized)
-			return
-		}
-
-		client := oauth2Config.Client(r.Context(), token)
-		resp, err := client.Get(cfg.UserInfoURL)
-		if err != nil || resp.StatusCode != http.StatusOK {
-			log.Get().Error(r.Context(), err, "msg", "failed to get user info", "resp_status", resp.StatusCode)
-			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgAuthFailedToGetUserInfo),
+				Error: i18n.Error(r, i18n.MsgAuthFailedToExchangeToken),
 			}, http.StatusUnauthorized)
 			return
 		}
-		defer resp.Body.Close()
-		var userInfo struct {
-			Email string `json:"email"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
-			log.Get().Error(r.Context(), err, "msg", "failed to decode user info response")
-			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgInternalServerError),
-			}, http.StatusInternalServerError)
-			return
-		}
-		operator, err := biz_operator.GetOperatorByAccount(r.Context(), userInfo.Email)
+		identity, err := verifyIdTokenFunc(r.Context(), provider.Name, idToken)
 		if err != nil {
-			log.Get().Error(r.Context(), err, "msg", "failed to get operator by account")
+			log.Get().Error(r.Context(), err, "msg", "failed to verify id_token", "provider", provider.Name)
 			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgInternalServerError),
-			}, http.StatusInternalServerError)
+				Error: i18n.Error(r, i18n.MsgAuthTokenInvalid),
+			}, http.StatusUnauthorized)
 			return
 		}
-		if operator.ID == 0 {
-			log.Get().Error(r.Context(), err, "msg", "operator not found", "account", userInfo.Email)
-			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgOperatorInvalid),
-			}, http.StatusUnauthorized)
+
+		operator, ok := getOperatorByIdentity(w, r, provider, token, identity)
+		if !ok {
 			return
 		}
 		if !operator.Enabled {
-			log.Get().Error(r.Context(), err, "msg", "operator is not active", "account", userInfo.Email)
+			log.Get().Error(r.Context(), err, "msg", "operator is not active", "account", operator.Account)
 			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgOperatorUnauthorized),
+				Error: i18n.Error(r, i18n.MsgOperatorUnauthorized),
 			}, http.StatusUnauthorized)
 			return
 		}
 
-		signedToken, err := auth.GenerateAuthToken(operator, idToken, cfg)
+		signedToken, refreshToken, err := auth.Get().StartSession(r.Context(), r, operator, provider.Name, idToken, cfg)
 		if err != nil {
-			log.Get().Error(r.Context(), err, "msg", "failed to sign JWT token")
+			log.Get().Error(r.Context(), err, "msg", "failed to start session")
 			response.WriteJSON(w, r, response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgInternalServerError),
+				Error: i18n.Error(r, i18n.MsgInternalServerError),
 			}, http.StatusInternalServerError)
 			return
 		}
-		err = ds.Get().Set(r.Context(), idToken, token.AccessToken, cfg.JWTExpirationInSeconds)
+		// the IdP access token is revoked on logout, it is kept while the session can be renewed
+		err = ds.Get().Set(r.Context(), idToken, token.AccessToken, cfg.JWTRefreshExpirationInSeconds)
 		if err != nil {
 			log.Get().Error(r.Context(), err, "msg", "unable to store access token", "error")
 		}
 		auth.SetAuthToken(w, signedToken, cfg)
+		auth.SetRefreshToken(w, refreshToken, cfg)
+		middleware.RecordAudit(r, biz_audit.Event{Actor: middleware.OperatorActor(operator.ID),
+			Action: biz_audit.AUTH_LOGIN, TargetType: biz_audit.TARGET_OPERATOR, TargetID: strconv.Itoa(operator.ID),
+			After: map[string]string{"provider": provider.Name}})
 		log.Get().Info(r.Context(), "msg", "redirecting", "uri", authState.RedirectURI)
 		http.Redirect(w, r, authState.RedirectURI, http.StatusSeeOther)
 	})
 }
 
+var verifyIdTokenFunc = verifyIdToken
+
+func verifyIdToken(ctx context.Context, provider string, idToken string) (idp.Identity, error) {
+	return idp.Get().Verify(ctx, provider, idToken)
+}
+
+// getOperatorByIdentity returns the operator linked to the IdP account. An account not linked yet of a
+// provider trusted with the emails is matched by the verified email of its user info and linked, the
+// email may change afterwards. The accounts of the other providers are linked by an admin.
+func getOperatorByIdentity(w http.ResponseWriter, r *http.Request, provider *auth.Provider, token *oauth2.Token,
+	identity idp.Identity) (model.Operator, bool) {
+	operator, err := operator_provider.Get().GetOperatorByIdentity(r.Context(), identity.Issuer, identity.Subject)
+	if err != nil {
+		log.Get().Error(r.Context(), err, "msg", "failed to get operator by identity")
+		response.WriteJSON(w, r, response.Response[any]{
+			Error: i18n.Error(r, i18n.MsgInternalServerError),
+		}, http.StatusInternalServerError)
+		return model.Operator{}, false
+	}
+	if operator.ID != 0 {
+		return operator, true
+	}
+	if !provider.TrustEmail {
+		writeIdentityNotLinked(w, r, provider, identity)
+		return model.Operator{}, false
+	}
+
+	client := provider.OAuth2Config.Client(r.Context(), token)
+	resp, err := client.Get(provider.UserInfoURL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		log.Get().Error(r.Context(), err, "msg", "failed to get user info", "provider", provider.Name)
+		response.WriteJSON(w, r, response.Response[any]{
+			Error: i18n.Error(r, i18n.MsgAuthFailedToGetUserInfo),
+		}, http.StatusUnauthorized)
+		return model.Operator{}, false
+	}
+	defer resp.Body.Close()
+	var userInfo struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
+		log.Get().Error(r.Context(), err, "msg", "failed to decode user info response")
+		response.WriteJSON(w, r, response.Response[any]{
+			Error: i18n.Error(r, i18n.MsgInternalServerError),
+		}, http.StatusInternalServerError)
+		return model.Operator{}, false
+	}
+	if !userInfo.EmailVerified {
+		writeIdentityNotLinked(w, r, provider, identity)
+		return model.Operator{}, false
+	}
+	operator, err = biz_operator.GetOperatorByAccount(r.Context(), userInfo.Email)
+	if err != nil {
+		log.Get().Error(r.Context(), err, "msg", "failed to get operator by account")
+		response.WriteJSON(w, r, response.Response[any]{
+			Error: i18n.Error(r, i18n.MsgInternalServerError),
+		}, http.StatusInternalServerError)
+		return model.Operator{}, false
+	}
+	if operator.ID == 0 {
+		log.Get().Error(r.Context(), err, "msg", "operator not found", "account", userInfo.Email)
+		response.WriteJSON(w, r, response.Response[any]{
+			Error: i18n.Error(r, i18n.MsgOperatorInvalid),
+		}, http.StatusUnauthorized)
+		return model.Operator{}, false
+	}
+	// a concurrent first login may link it first, the operator is found by email this time
+	if err := operator_provider.Get().LinkOperatorIdentity(r.Context(), operator.ID, identity.Issuer,
+		identity.Subject); err != nil {
+		log.Get().Error(r.Context(), err, "msg", "unable to link operator identity", "account", operator.Account)
+	} else {
+		log.Get().Info(r.Context(), "msg", "operator identity linked", "account", operator.Account,
+			"provider", provider.Name)
+	}
+	return operator, true
+}
+
+// writeIdentityNotLinked refuses an IdP account no operator is linked to, its subject is logged for the
+// admin to link it
+func writeIdentityNotLinked(w http.ResponseWriter, r *http.Request, provider *auth.Provider, identity idp.Identity) {
+	log.Get().Warn(r.Context(), "msg", "identity not linked to an operator", "provider", provider.Name,
+		"issuer", identity.Issuer, "subject", identity.Subject)
+	response.WriteJSON(w, r, response.Response[any]{
+		Error: i18n.Error(r, i18n.MsgOperatorNotLinked),
+	}, http.StatusUnauthorized)
+}
+
+type LocalLoginInput struct {
+	Account  string `json:"account"`
+	Password string `json:"password"`
+	Code     string `json:"code"` // the TOTP code of the authenticator app
+}
+
+type LocalLoginOutput struct {
+	ExpiresIn int `json:"expiresIn"` // seconds until the access token expires
+}
+
+// LocalLogin is the emergency login with the operator password and TOTP code while the identity
+// providers are down. Every failure counts for the lockout of the requester.
+func LocalLogin(cfg auth.OAuthConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*LocalLoginOutput]{}
+		var input LocalLoginInput
+		if ok := getJsonBody(w, r, &input); !ok {
+			return
+		}
+		operator, err := auth.Get().LocalLogin(r.Context(), input.Account, input.Password, input.Code)
+		if errors.Is(err, auth.ErrLocalLoginInvalid) {
+			middleware.RegisterLockoutFailure(r)
+			res.Error = i18n.Error(r, i18n.MsgLocalLoginInvalid)
+			response.WriteJSON(w, r, res, http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to check local login")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		signedToken, refreshToken, err := auth.Get().StartSession(r.Context(), r, operator, auth.LocalProvider, "", cfg)
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to start session")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		auth.SetAuthToken(w, signedToken, cfg)
+		auth.SetRefreshToken(w, refreshToken, cfg)
+		middleware.RecordAudit(r, biz_audit.Event{Actor: middleware.OperatorActor(operator.ID),
+			Action: biz_audit.AUTH_LOCAL_LOGIN, TargetType: biz_audit.TARGET_OPERATOR, TargetID: strconv.Itoa(operator.ID)})
+		log.Get().Warn(r.Context(), "msg", "operator logged in locally", "account", operator.Account)
+		res.Data = &LocalLoginOutput{ExpiresIn: cfg.JWTExpirationInSeconds}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+type GetAuthProvidersOutput struct {
+	Providers  []string `json:"providers"`
+	LocalLogin bool     `json:"localLogin"`
+}
+
+// GetAuthProviders lists the identity providers the login page offers
+func GetAuthProviders(cfg auth.OAuthConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		providers := []string{}
+		for name := range auth.Get().Providers {
+			providers = append(providers, name)
+		}
+		sort.Strings(providers)
+		response.WriteJSON(w, r, response.Response[*GetAuthProvidersOutput]{
+			Data: &GetAuthProvidersOutput{Providers: providers, LocalLogin: cfg.LocalLogin},
+		}, http.StatusOK)
+	})
+}
+
 func LogOut(cfg auth.OAuthConfig) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := response.Response[any]{}
+		if refreshToken, err := auth.GetRefreshToken(r); err == nil {
+			auth.DelRefreshToken(w, cfg)
+			if err := auth.Get().RevokeRefreshToken(r.Context(), refreshToken); err != nil {
+				log.Get().Error(r.Context(), err, "msg", "unable to revoke refresh token")
+			}
+		}
 		token, err := auth.GetAuthToken(r)
 		if err != nil {
 			log.Get().Warn(r.Context(), "msg", "access token not found")
-			resp.Message = i18n.Get(r, i18n.MsgAuthTokenNotFoud)
+			resp.Error = i18n.Error(r, i18n.MsgAuthTokenNotFoud)
 			response.WriteJSON(w, r, resp, http.StatusOK)
 			return
 		}
@@ -160,27 +334,75 @@ func LogOut(cfg auth.OAuthConfig) http.Handler {
 		claims, _, err := auth.ParseTokenWithClaims(token)
 		if err != nil {
 			log.Get().Warn(r.Context(), "msg", "unable to parse JWT token", "error", err.Error())
-			resp.Message = i18n.Get(r, i18n.MsgAuthTokenInvalid)
+			resp.Error = i18n.Error(r, i18n.MsgAuthTokenInvalid)
+			response.WriteJSON(w, r, resp, http.StatusOK)
+			return
+		}
+		if err := session.Get().Revoke(r.Context(), claims.SessionID); err != nil {
+			log.Get().Error(r.Context(), err, "msg", "unable to revoke session", "session_id", claims.SessionID)
+		}
+		middleware.RecordAudit(r, biz_audit.Event{Actor: middleware.OperatorActor(claims.OperatorID),
+			Action: biz_audit.AUTH_LOGOUT, TargetType: biz_audit.TARGET_SESSION, TargetID: claims.SessionID})
+		provider, found := auth.Get().GetProvider(claims.GetProvider())
+		if !found {
+			// a local login or a provider no longer configured, there is no IdP token to revoke
 			response.WriteJSON(w, r, resp, http.StatusOK)
 			return
 		}
 		ok, accessToken, err := ds.Get().Get(r.Context(), claims.IDPIDToken)
 		if err != nil {
 			log.Get().Error(r.Context(), err, "msg", "unable get access token from DS", "error")
-			resp.Message = i18n.Get(r, i18n.MsgInternalServerError)
+			resp.Error = i18n.Error(r, i18n.MsgInternalServerError)
 			response.WriteJSON(w, r, resp, http.StatusOK)
 			return
 		}
 		if !ok {
 			log.Get().Warn(r.Context(), "msg", "access token not found in DS")
-			resp.Message = i18n.Get(r, i18n.MsgAccessTokenNotFound)
+			resp.Error = i18n.Error(r, i18n.MsgAccessTokenNotFound)
 			response.WriteJSON(w, r, resp, http.StatusOK)
 			return
 		}
 		response.WriteJSON(w, r, resp, http.StatusOK)
 
-		go revokeToken(r.Context(), accessToken, cfg.RevokeTokenURL)
+		go revokeToken(r.Context(), accessToken, provider.RevokeTokenURL)
+
+	})
+}
 
+type RefreshOutput struct {
+	ExpiresIn int `json:"expiresIn"` // seconds until the new access token expires
+}
+
+// Refresh renews the operator session, the refresh token is rotated and a new access token issued.
+// Reusing a rotated refresh token ends the session.
+func Refresh(cfg auth.OAuthConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*RefreshOutput]{}
+		refreshToken, err := auth.GetRefreshToken(r)
+		if err != nil {
+			log.Get().Warn(r.Context(), "msg", "missing refresh token", "error", err.Error())
+			res.Error = i18n.Error(r, i18n.MsgAuthTokenNotFoud)
+			response.WriteJSON(w, r, res, http.StatusUnauthorized)
+			return
+		}
+		_, err = auth.Get().Renew(r.Context(), w, refreshToken, cfg)
+		if errors.Is(err, auth.ErrRefreshTokenInvalid) || errors.Is(err, auth.ErrRefreshTokenReused) {
+			log.Get().Warn(r.Context(), "msg", "session can not be renewed", "error", err.Error())
+			auth.DelAuthToken(w, cfg)
+			auth.DelRefreshToken(w, cfg)
+			res.Error = i18n.Error(r, i18n.MsgSessionExpired)
+			response.WriteJSON(w, r, res, http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to renew session")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		log.Get().Info(r.Context(), "msg", "session renewed")
+		res.Data = &RefreshOutput{ExpiresIn: cfg.JWTExpirationInSeconds}
+		response.WriteJSON(w, r, res, http.StatusOK)
 	})
 }
 
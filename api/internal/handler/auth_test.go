@@ -20,10 +20,14 @@ import (
 	jwt_key_mock "via/internal/jwt/mock"
 	"via/internal/log"
 	mock_log "via/internal/log/mock"
+	"via/internal/middleware"
 	"via/internal/model"
+	"via/internal/ratelimit"
 	"via/internal/testutil"
+	"via/internal/totp"
 
 	"via/internal/response"
+	"via/internal/session"
 
 	"golang.org/x/oauth2"
 
@@ -31,6 +35,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	auth_mock "via/internal/auth/mock"
+	"via/internal/idp"
 
 	operator_provider "via/internal/provider/operator"
 	mock_operator_provider "via/internal/provider/operator/mock"
@@ -98,6 +103,45 @@ func TestLogin_GenerateStateError(t *testing.T) {
 	mockDS.AssertExpectations(t)
 }
 
+func TestLogin_Providers(t *testing.T) {
+	log.Set(&mock_log.MockNoOpLogger{})
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedURL    string
+	}{
+		{name: "named provider", query: "?provider=entra", expectedStatus: http.StatusTemporaryRedirect,
+			expectedURL: "https://login.microsoftonline.com/authorize"},
+		{name: "default provider not configured", expectedStatus: http.StatusBadRequest},
+		{name: "unknown provider", query: "?provider=github", expectedStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			var stored string
+			mockDS.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				stored = args.String(2)
+			}).Return(nil).Maybe()
+			auth.Set(auth.New(auth.OAuthConfig{ProviderConfigs: map[string]auth.ProviderConfig{
+				"entra": {ClientSecret: "sec", AuthURL: "https://login.microsoftonline.com/authorize"},
+			}}, mockDS))
+			req := httptest.NewRequest(http.MethodGet, "/auth/login"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			Login().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedURL == "" {
+				assertJSONErrorResponse(t, req, w, http.StatusBadRequest, i18n.MsgAuthProviderNotFound)
+				return
+			}
+			assert.True(t, strings.HasPrefix(w.Header().Get("Location"), tt.expectedURL))
+			assert.Contains(t, stored, `"Provider":"entra"`)
+		})
+	}
+}
+
 // Helper to mock HTTP Client RoundTrip
 type roundTripFunc func(req *http.Request) (*http.Response, error)
 
@@ -105,7 +149,33 @@ func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
 
+// isRefreshKey matches the datastore keys of the refresh tokens
+var isRefreshKey = mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "refresh:") })
+
+// isSessionKey matches the datastore keys of the sessions and their operator index
+var isSessionKey = mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "session") })
+
+// userInfoClient answers the user info request with the email and whether it is verified
+func userInfoClient(email string, verified bool) *http.Client {
+	return &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, _ := json.Marshal(map[string]any{"email": email, "email_verified": verified})
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body)), Header: make(http.Header)}, nil
+		}),
+	}
+}
+
+func hasCookie(res *http.Response, name string) bool {
+	for _, cookie := range res.Cookies() {
+		if cookie.Name == name && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func TestLoginCallback(t *testing.T) {
+	testutil.InjectNoOpAuditProvider()
 	type testCase struct {
 		name           string
 		setupMocks     func(mockDS *mock_ds.MockDS, mockOAuthCfg *auth_mock.MockOAuth2Cfg, mockOperatorProvider *mock_operator_provider.MockOperatorProvider)
@@ -114,6 +184,8 @@ func TestLoginCallback(t *testing.T) {
 		expectRedirect bool
 		expectCookie   bool
 		customJWTKey   func()
+		verifyErr      error
+		untrusted      bool // the provider does not link the accounts by email
 	}
 
 	tests := []testCase{
@@ -122,14 +194,17 @@ func TestLoginCallback(t *testing.T) {
 			setupMocks: func(mockDS *mock_ds.MockDS, mockOAuthCfg *auth_mock.MockOAuth2Cfg, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
 				mockDS.On("Get", mock.Anything, mock.Anything).
 					Return(true, `{"CodeVerifier":"test_verifier","RedirectURI":"https://client.app/callback"}`, nil)
-				mockDS.On("Set", mock.Anything, "dummy_id_token", mock.Anything, mock.Anything).Return(nil)
+				mockDS.On("Set", mock.Anything, "dummy_id_token", mock.Anything, 7200).Return(nil)
+				mockDS.On("Set", mock.Anything, isRefreshKey, mock.Anything, 7200).Return(nil).Once()
+				mockOperatorProvider.On("LinkOperatorIdentity", mock.Anything, 1, "https://idp.test", "subject-1").
+					Return(nil).Once()
 
 				exchangedToken := &oauth2.Token{}
 				exchangedToken = exchangedToken.WithExtra(map[string]any{"id_token": "dummy_id_token"})
 				mockOAuthCfg.On("Exchange", mock.Anything, "test_code", mock.Anything).Return(exchangedToken, nil)
 				mockOAuthCfg.On("Client", mock.Anything, exchangedToken).Return(&http.Client{
 					Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
-						body, _ := json.Marshal(map[string]string{"email": "test@example.com"})
+						body, _ := json.Marshal(map[string]any{"email": "test@example.com", "email_verified": true})
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Body:       io.NopCloser(bytes.NewReader(body)),
@@ -155,13 +230,14 @@ func TestLoginCallback(t *testing.T) {
 				mockDS.On("Get", mock.Anything, mock.Anything).
 					Return(true, `{"CodeVerifier":"test_verifier","RedirectURI":"https://client.app/callback"}`, nil)
 				mockDS.On("Set", mock.Anything, "dummy_id_token", mock.Anything, mock.Anything).Return(errors.New("ds fail"))
+				mockDS.On("Set", mock.Anything, isRefreshKey, mock.Anything, 7200).Return(nil).Once()
 
 				exchangedToken := &oauth2.Token{}
 				exchangedToken = exchangedToken.WithExtra(map[string]any{"id_token": "dummy_id_token"})
 				mockOAuthCfg.On("Exchange", mock.Anything, "test_code", mock.Anything).Return(exchangedToken, nil)
 				mockOAuthCfg.On("Client", mock.Anything, exchangedToken).Return(&http.Client{
 					Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
-						body, _ := json.Marshal(map[string]string{"email": "test@example.com"})
+						body, _ := json.Marshal(map[string]any{"email": "test@example.com", "email_verified": true})
 						return &http.Response{
 							StatusCode: http.StatusOK,
 							Body:       io.NopCloser(bytes.NewReader(body)),
@@ -181,6 +257,126 @@ func TestLoginCallback(t *testing.T) {
 			expectRedirect: true,
 			expectCookie:   true,
 		},
+		{
+			name: "error issuing refresh token",
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOAuthCfg *auth_mock.MockOAuth2Cfg, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, mock.Anything).
+					Return(true, `{"CodeVerifier":"test_verifier","RedirectURI":"https://client.app/callback"}`, nil)
+				mockDS.On("Set", mock.Anything, isRefreshKey, mock.Anything, 7200).Return(errors.New("ds fail")).Once()
+
+				exchangedToken := &oauth2.Token{}
+				exchangedToken = exchangedToken.WithExtra(map[string]any{"id_token": "dummy_id_token"})
+				mockOAuthCfg.On("Exchange", mock.Anything, "test_code", mock.Anything).Return(exchangedToken, nil)
+				mockOAuthCfg.On("Client", mock.Anything, exchangedToken).Return(&http.Client{
+					Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+						body, _ := json.Marshal(map[string]any{"email": "test@example.com", "email_verified": true})
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewReader(body)),
+							Header:     make(http.Header),
+						}, nil
+					}),
+				})
+
+				mockOperatorProvider.On("GetOperatorByAccount", mock.Anything, "test@example.com").
+					Return(model.Operator{ID: 1, Account: "test@example.com", Enabled: true}, nil)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    i18n.MsgInternalServerError,
+		},
+		{
+			name: "operator found by its identity",
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOAuthCfg *auth_mock.MockOAuth2Cfg, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, mock.Anything).
+					Return(true, `{"CodeVerifier":"test_verifier","RedirectURI":"https://client.app/callback","Provider":"default"}`, nil)
+				mockDS.On("Set", mock.Anything, "dummy_id_token", mock.Anything, 7200).Return(nil)
+				mockDS.On("Set", mock.Anything, isRefreshKey, mock.Anything, 7200).Return(nil).Once()
+				exchangedToken := (&oauth2.Token{}).WithExtra(map[string]any{"id_token": "dummy_id_token"})
+				mockOAuthCfg.On("Exchange", mock.Anything, "test_code", mock.Anything).Return(exchangedToken, nil)
+				mockOperatorProvider.On("GetOperatorByIdentity", mock.Anything, "https://idp.test", "subject-1").
+					Return(model.Operator{ID: 1, Account: "renamed@example.com", Enabled: true}, nil).Once()
+			},
+			expectedStatus: http.StatusSeeOther,
+			expectRedirect: true,
+			expectCookie:   true,
+		},
+		{
+			name: "identity linked by a concurrent login",
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOAuthCfg *auth_mock.MockOAuth2Cfg, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, mock.Anything).
+					Return(true, `{"CodeVerifier":"test_verifier","RedirectURI":"https://client.app/callback"}`, nil)
+				mockDS.On("Set", mock.Anything, "dummy_id_token", mock.Anything, 7200).Return(nil)
+				mockDS.On("Set", mock.Anything, isRefreshKey, mock.Anything, 7200).Return(nil).Once()
+				exchangedToken := (&oauth2.Token{}).WithExtra(map[string]any{"id_token": "dummy_id_token"})
+				mockOAuthCfg.On("Exchange", mock.Anything, "test_code", mock.Anything).Return(exchangedToken, nil)
+				mockOAuthCfg.On("Client", mock.Anything, exchangedToken).Return(userInfoClient("test@example.com", true))
+				mockOperatorProvider.On("GetOperatorByAccount", mock.Anything, "test@example.com").
+					Return(model.Operator{ID: 1, Account: "test@example.com", Enabled: true}, nil)
+				mockOperatorProvider.On("LinkOperatorIdentity", mock.Anything, 1, "https://idp.test", "subject-1").
+					Return(errors.New("duplicate key")).Once()
+			},
+			expectedStatus: http.StatusSeeOther,
+			expectRedirect: true,
+			expectCookie:   true,
+		},
+		{
+			name: "email not verified",
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOAuthCfg *auth_mock.MockOAuth2Cfg, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, mock.Anything).
+					Return(true, `{"CodeVerifier":"test_verifier","RedirectURI":"https://client.app/callback"}`, nil)
+				exchangedToken := (&oauth2.Token{}).WithExtra(map[string]any{"id_token": "dummy_id_token"})
+				mockOAuthCfg.On("Exchange", mock.Anything, "test_code", mock.Anything).Return(exchangedToken, nil)
+				mockOAuthCfg.On("Client", mock.Anything, exchangedToken).Return(userInfoClient("test@example.com", false))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    i18n.MsgOperatorNotLinked,
+		},
+		{
+			name: "provider not trusted with the emails",
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOAuthCfg *auth_mock.MockOAuth2Cfg, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, mock.Anything).
+					Return(true, `{"CodeVerifier":"test_verifier","RedirectURI":"https://client.app/callback"}`, nil)
+				exchangedToken := (&oauth2.Token{}).WithExtra(map[string]any{"id_token": "dummy_id_token"})
+				mockOAuthCfg.On("Exchange", mock.Anything, "test_code", mock.Anything).Return(exchangedToken, nil)
+			},
+			untrusted:      true,
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    i18n.MsgOperatorNotLinked,
+		},
+		{
+			name: "unknown provider",
+			setupMocks: func(mockDS *mock_ds.MockDS, _ *auth_mock.MockOAuth2Cfg, _ *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, mock.Anything).
+					Return(true, `{"CodeVerifier":"verifier","RedirectURI":"https://redirect","Provider":"entra"}`, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    i18n.MsgAuthProviderNotFound,
+		},
+		{
+			name: "id_token not verified",
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOAuthCfg *auth_mock.MockOAuth2Cfg, _ *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, mock.Anything).
+					Return(true, `{"CodeVerifier":"verifier","RedirectURI":"https://redirect"}`, nil)
+				exchangedToken := (&oauth2.Token{}).WithExtra(map[string]any{"id_token": "forged"})
+				mockOAuthCfg.On("Exchange", mock.Anything, "test_code", mock.Anything).Return(exchangedToken, nil)
+			},
+			verifyErr:      errors.New("failed to verify token signature"),
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    i18n.MsgAuthTokenInvalid,
+		},
+		{
+			name: "error getting operator by identity",
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOAuthCfg *auth_mock.MockOAuth2Cfg, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, mock.Anything).
+					Return(true, `{"CodeVerifier":"verifier","RedirectURI":"https://redirect"}`, nil)
+				exchangedToken := (&oauth2.Token{}).WithExtra(map[string]any{"id_token": "token"})
+				mockOAuthCfg.On("Exchange", mock.Anything, "test_code", mock.Anything).Return(exchangedToken, nil)
+				mockOperatorProvider.On("GetOperatorByIdentity", mock.Anything, "https://idp.test", "subject-1").
+					Return(model.Operator{}, errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    i18n.MsgInternalServerError,
+		},
 		{
 			name: "error getting auth state",
 			setupMocks: func(mockDS *mock_ds.MockDS, _ *auth_mock.MockOAuth2Cfg, _ *mock_operator_provider.MockOperatorProvider) {
@@ -271,7 +467,7 @@ func TestLoginCallback(t *testing.T) {
 				mockOAuthCfg.On("Client", mock.Anything, exchangedToken).
 					Return(&http.Client{
 						Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
-							userInfo := map[string]string{"email": "test@example.com"}
+							userInfo := map[string]any{"email": "test@example.com", "email_verified": true}
 							body, _ := json.Marshal(userInfo)
 							return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBuffer(body))}, nil
 						}),
@@ -294,7 +490,7 @@ func TestLoginCallback(t *testing.T) {
 				mockOAuthCfg.On("Client", mock.Anything, exchangedToken).
 					Return(&http.Client{
 						Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
-							userInfo := map[string]string{"email": "test@example.com"}
+							userInfo := map[string]any{"email": "test@example.com", "email_verified": true}
 							body, _ := json.Marshal(userInfo)
 							return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBuffer(body))}, nil
 						}),
@@ -317,7 +513,7 @@ func TestLoginCallback(t *testing.T) {
 				mockOAuthCfg.On("Client", mock.Anything, exchangedToken).
 					Return(&http.Client{
 						Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
-							userInfo := map[string]string{"email": "test@example.com"}
+							userInfo := map[string]any{"email": "test@example.com", "email_verified": true}
 							body, _ := json.Marshal(userInfo)
 							return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBuffer(body))}, nil
 						}),
@@ -340,7 +536,7 @@ func TestLoginCallback(t *testing.T) {
 				mockOAuthCfg.On("Client", mock.Anything, exchangedToken).
 					Return(&http.Client{
 						Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
-							userInfo := map[string]string{"email": "test@example.com"}
+							userInfo := map[string]any{"email": "test@example.com", "email_verified": true}
 							body, _ := json.Marshal(userInfo)
 							return &http.Response{
 								StatusCode: http.StatusOK,
@@ -375,10 +571,23 @@ func TestLoginCallback(t *testing.T) {
 			mockDS := new(mock_ds.MockDS)
 			mockOAuthCfg := new(auth_mock.MockOAuth2Cfg)
 			mockOperatorProvider := new(mock_operator_provider.MockOperatorProvider)
+			// registered first, the auth state mocks match any other key
+			mockDS.On("SAdd", mock.Anything, "sessions:operator:1:ids", mock.Anything, 7200).Return(nil).Maybe()
+			mockDS.On("Set", mock.Anything, isSessionKey, mock.Anything, 7200).Return(nil).Maybe()
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(mockDS, mockOAuthCfg, mockOperatorProvider)
 			}
+			// registered last, the cases expecting a linked identity register their own
+			mockOperatorProvider.On("GetOperatorByIdentity", mock.Anything, mock.Anything, mock.Anything).
+				Return(model.Operator{}, nil).Maybe()
+			mockOperatorProvider.On("LinkOperatorIdentity", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(nil).Maybe()
+			verifyIdTokenFunc = func(ctx context.Context, provider string, idToken string) (idp.Identity, error) {
+				assert.Equal(t, auth.DefaultProvider, provider)
+				return idp.Identity{Issuer: "https://idp.test", Subject: "subject-1"}, tt.verifyErr
+			}
+			t.Cleanup(func() { verifyIdTokenFunc = verifyIdToken })
 
 			if tt.customJWTKey != nil {
 				tt.customJWTKey()
@@ -387,8 +596,10 @@ func TestLoginCallback(t *testing.T) {
 			}
 
 			ds.Set(mockDS)
-			auth.Set(auth.New(auth.OAuthConfig{ClientSecret: "mySecret"}, mockDS))
-			auth.Get().OAuth2Config = mockOAuthCfg
+			session.Set(session.New(mockDS, 7200))
+			auth.Set(auth.New(auth.OAuthConfig{ClientSecret: "mySecret", JWTRefreshExpirationInSeconds: 7200,
+				UserInfoURL: "https://userinfo.test", TrustEmail: !tt.untrusted}, mockDS))
+			auth.Get().Providers[auth.DefaultProvider].OAuth2Config = mockOAuthCfg
 			operator_provider.Set(mockOperatorProvider)
 			log.Set(&mock_log.MockNoOpLogger{})
 
@@ -401,7 +612,6 @@ func TestLoginCallback(t *testing.T) {
 				JWTExpirationInSeconds:        3600,
 				JWTRefreshExpirationInSeconds: 7200,
 				CookieSecure:                  false,
-				UserInfoURL:                   "https://userinfo.test",
 			}).ServeHTTP(w, req)
 
 			res := w.Result()
@@ -411,6 +621,7 @@ func TestLoginCallback(t *testing.T) {
 				assert.Equal(t, "https://client.app/callback", res.Header.Get("Location"))
 				if tt.expectCookie {
 					assert.NotEmpty(t, res.Header.Get("Set-Cookie"))
+					assert.True(t, hasCookie(res, auth.RefreshTokenKey), "expected refresh token cookie")
 				}
 			} else {
 				assert.Equal(t, tt.expectedStatus, res.StatusCode)
@@ -740,7 +951,11 @@ func TestLoginCallback_Errors(t *testing.T) {
 	}
 }*/
 
+// logoutSession is the session of the access tokens logged out
+var logoutSession = session.Session{Session: model.Session{ID: "s1"}, JTI: "j1", IDPIDToken: "idp-token"}
+
 func TestLogOut_AllCases(t *testing.T) {
+	testutil.InjectNoOpAuditProvider()
 
 	tests := []struct {
 		name            string
@@ -773,9 +988,11 @@ func TestLogOut_AllCases(t *testing.T) {
 				mockDS := new(mock_ds.MockDS)
 				mockDS.On("Get", mock.Anything, "idp-token").
 					Return(false, "", errors.New("ds error"))
+				mockDS.On("Del", mock.Anything, "session:s1").Return(errors.New("ds error")).Once()
 				ds.Set(mockDS)
+				session.Set(session.New(mockDS, 7200))
 				testutil.InjectMockJWTKey()
-				token, _ := auth.GenerateAuthToken(model.Operator{}, "idp-token", auth.OAuthConfig{JWTExpirationInSeconds: 10})
+				token, _ := auth.GenerateAuthToken(model.Operator{}, logoutSession, auth.OAuthConfig{JWTExpirationInSeconds: 10})
 				req.AddCookie(&http.Cookie{
 					Name:  auth.AuthTokenKey,
 					Value: token,
@@ -791,9 +1008,11 @@ func TestLogOut_AllCases(t *testing.T) {
 				mockDS := new(mock_ds.MockDS)
 				mockDS.On("Get", mock.Anything, "idp-token").
 					Return(false, "", nil)
+				mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
 				ds.Set(mockDS)
+				session.Set(session.New(mockDS, 7200))
 				testutil.InjectMockJWTKey()
-				token, _ := auth.GenerateAuthToken(model.Operator{}, "idp-token", auth.OAuthConfig{JWTExpirationInSeconds: 10})
+				token, _ := auth.GenerateAuthToken(model.Operator{}, logoutSession, auth.OAuthConfig{JWTExpirationInSeconds: 10})
 				req.AddCookie(&http.Cookie{
 					Name:  auth.AuthTokenKey,
 					Value: token,
@@ -808,9 +1027,11 @@ func TestLogOut_AllCases(t *testing.T) {
 				mockDS := new(mock_ds.MockDS)
 				mockDS.On("Get", mock.Anything, "idp-token").
 					Return(true, "access-token", nil)
+				mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
 				ds.Set(mockDS)
+				session.Set(session.New(mockDS, 7200))
 				testutil.InjectMockJWTKey()
-				token, _ := auth.GenerateAuthToken(model.Operator{}, "idp-token", auth.OAuthConfig{JWTExpirationInSeconds: 10})
+				token, _ := auth.GenerateAuthToken(model.Operator{}, logoutSession, auth.OAuthConfig{JWTExpirationInSeconds: 10})
 				req.AddCookie(&http.Cookie{
 					Name:  auth.AuthTokenKey,
 					Value: token,
@@ -819,6 +1040,49 @@ func TestLogOut_AllCases(t *testing.T) {
 			expectedMessage: "",
 			expectRevoke:    true,
 		},
+		{
+			name: "Local login session has no IdP token",
+			setup: func(req *http.Request) {
+				mockDS := new(mock_ds.MockDS)
+				mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
+				ds.Set(mockDS)
+				session.Set(session.New(mockDS, 7200))
+				testutil.InjectMockJWTKey()
+				local := session.Session{Session: model.Session{ID: "s1", Provider: auth.LocalProvider}, JTI: "j1"}
+				token, _ := auth.GenerateAuthToken(model.Operator{}, local, auth.OAuthConfig{JWTExpirationInSeconds: 10})
+				req.AddCookie(&http.Cookie{Name: auth.AuthTokenKey, Value: token})
+			},
+			expectedMessage: "",
+			expectRevoke:    false,
+		},
+		{
+			name: "Refresh token revoked",
+			setup: func(req *http.Request) {
+				mockDS := new(mock_ds.MockDS)
+				mockDS.On("Get", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "refresh:token:") })).
+					Return(true, "s1", nil).Once()
+				mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
+				ds.Set(mockDS)
+				session.Set(session.New(mockDS, 7200))
+				auth.Set(auth.New(auth.OAuthConfig{ClientSecret: "mySecret"}, mockDS))
+				req.AddCookie(&http.Cookie{Name: auth.RefreshTokenKey, Value: "refresh"})
+			},
+			expectedMessage: i18n.MsgAuthTokenNotFoud,
+			expectRevoke:    false,
+		},
+		{
+			name: "Refresh token revoke fails",
+			setup: func(req *http.Request) {
+				mockDS := new(mock_ds.MockDS)
+				mockDS.On("Get", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "refresh:token:") })).
+					Return(false, "", errors.New("ds error")).Once()
+				ds.Set(mockDS)
+				auth.Set(auth.New(auth.OAuthConfig{ClientSecret: "mySecret"}, mockDS))
+				req.AddCookie(&http.Cookie{Name: auth.RefreshTokenKey, Value: "refresh"})
+			},
+			expectedMessage: i18n.MsgAuthTokenNotFoud,
+			expectRevoke:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -827,20 +1091,21 @@ func TestLogOut_AllCases(t *testing.T) {
 			httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
 				revokeCalled = true
 				assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+				assert.Equal(t, "https://revoke.url", req.URL.String())
 				return &http.Response{
 					StatusCode: http.StatusOK,
 					Body:       io.NopCloser(bytes.NewBufferString("")),
 				}, nil
 			})}
 			req := httptest.NewRequest(http.MethodGet, "/logout", nil)
+			auth.Set(auth.New(auth.OAuthConfig{ClientSecret: "mySecret", RevokeTokenURL: "https://revoke.url"},
+				new(mock_ds.MockDS)))
 			tt.setup(req)
 
 			log.Set(&mock_log.MockNoOpLogger{})
 
 			w := httptest.NewRecorder()
-			LogOut(auth.OAuthConfig{
-				RevokeTokenURL: "https://revoke.url",
-			}).ServeHTTP(w, req)
+			LogOut(auth.OAuthConfig{}).ServeHTTP(w, req)
 
 			res := w.Result()
 			defer res.Body.Close()
@@ -897,3 +1162,241 @@ func TestRevokeToken_AllCases(t *testing.T) {
 		revokeToken(ctx, "token", "https://example.com")
 	})
 }
+
+func TestRefresh(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectMockJWTKey()
+	t.Cleanup(jwt_key.Reset)
+	cfg := auth.OAuthConfig{JWTExpirationInSeconds: 3600, JWTRefreshExpirationInSeconds: 7200}
+	isTokenKey := mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "refresh:token:") })
+	isUsedKey := mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "refresh:used:") })
+	stored := `{"id":"s1","operatorId":1,"jti":"j1","idpIdToken":"idp-token"}`
+
+	tests := []struct {
+		name           string
+		cookie         bool
+		setupMocks     func(mockDS *mock_ds.MockDS, mockOperator *mock_operator_provider.MockOperatorProvider)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name:   "success",
+			cookie: true,
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOperator *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, isTokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, isUsedKey, "s1", 7200).Return(true, nil).Once()
+				mockDS.On("Get", mock.Anything, "session:s1").Return(true, stored, nil).Once()
+				mockDS.On("Set", mock.Anything, isTokenKey, "s1", 7200).Return(nil).Once()
+				mockDS.On("Set", mock.Anything, "session:s1", mock.Anything, 7200).Return(nil).Once()
+				mockDS.On("SAdd", mock.Anything, "sessions:operator:1:ids", "s1", 7200).Return(nil).Once()
+				mockOperator.On("GetOperatorById", mock.Anything, 1).
+					Return(model.Operator{ID: 1, Enabled: true, Role: biz_operator.ROLE_OPERATOR}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing refresh token",
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    i18n.MsgAuthTokenNotFoud,
+		},
+		{
+			name:   "unknown refresh token",
+			cookie: true,
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOperator *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, isTokenKey).Return(false, "", nil).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    i18n.MsgSessionExpired,
+		},
+		{
+			name:   "reused refresh token",
+			cookie: true,
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOperator *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, isTokenKey).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, isUsedKey, "s1", 7200).Return(false, nil).Once()
+				mockDS.On("Del", mock.Anything, "session:s1").Return(nil).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    i18n.MsgSessionExpired,
+		},
+		{
+			name:   "error getting refresh token",
+			cookie: true,
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOperator *mock_operator_provider.MockOperatorProvider) {
+				mockDS.On("Get", mock.Anything, isTokenKey).Return(false, "", errors.New("ds error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    i18n.MsgInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockDS, mockOperator)
+			}
+			auth.Set(auth.New(auth.OAuthConfig{ClientSecret: "mySecret", JWTRefreshExpirationInSeconds: 7200}, mockDS))
+			session.Set(session.New(mockDS, 7200))
+			operator_provider.Set(mockOperator)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", nil)
+			if tt.cookie {
+				req.AddCookie(&http.Cookie{Name: auth.RefreshTokenKey, Value: "refresh"})
+			}
+			w := httptest.NewRecorder()
+			Refresh(cfg).ServeHTTP(w, req)
+
+			res := w.Result()
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			var resp response.Response[RefreshOutput]
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&resp))
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, 3600, resp.Data.ExpiresIn)
+				assert.True(t, hasCookie(res, auth.AuthTokenKey), "expected access token cookie")
+				assert.True(t, hasCookie(res, auth.RefreshTokenKey), "expected refresh token cookie")
+			} else {
+				assert.Equal(t, i18n.Get(req, tt.expectedMsg), resp.Message)
+				assert.False(t, hasCookie(res, auth.RefreshTokenKey))
+			}
+			mockDS.AssertExpectations(t)
+			mockOperator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLocalLogin(t *testing.T) {
+	testutil.InjectNoOpAuditProvider()
+	secret, _ := totp.GenerateSecret()
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	hash, _ := auth.HashPassword("secret password")
+	operator := model.Operator{ID: 1, Account: "ana@example.com", Enabled: true}
+	credentials := model.OperatorCredentials{PasswordHash: hash, TOTPSecret: secret}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(mockDS *mock_ds.MockDS, mockOperatorProvider *mock_operator_provider.MockOperatorProvider)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name: "success",
+			body: `{"account":"ana@example.com","password":"secret password","code":"` + code + `"}`,
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockOperatorProvider.On("GetOperatorCredentials", mock.Anything, "ana@example.com").
+					Return(operator, credentials, nil).Once()
+				mockDS.On("SetNX", mock.Anything, mock.Anything, "ana@example.com", totp.Window).Return(true, nil).Once()
+				mockDS.On("SAdd", mock.Anything, "sessions:operator:1:ids", mock.Anything, 7200).Return(nil).Once()
+				mockDS.On("Set", mock.Anything, isSessionKey, mock.Anything, 7200).Return(nil).Once()
+				mockDS.On("Set", mock.Anything, isRefreshKey, mock.Anything, 7200).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid body",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    i18n.MsgBadRequest,
+		},
+		{
+			name: "wrong password counts for the lockout",
+			body: `{"account":"ana@example.com","password":"guess","code":"` + code + `"}`,
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockOperatorProvider.On("GetOperatorCredentials", mock.Anything, "ana@example.com").
+					Return(operator, credentials, nil).Once()
+				mockDS.On("IncrExpire", mock.Anything, "LocalLoginLockout:failures:ip", mock.Anything).Return(2, nil).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    i18n.MsgLocalLoginInvalid,
+		},
+		{
+			name: "error getting credentials",
+			body: `{"account":"ana@example.com","password":"secret password","code":"` + code + `"}`,
+			setupMocks: func(_ *mock_ds.MockDS, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockOperatorProvider.On("GetOperatorCredentials", mock.Anything, "ana@example.com").
+					Return(model.Operator{}, model.OperatorCredentials{}, errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    i18n.MsgInternalServerError,
+		},
+		{
+			name: "error starting session",
+			body: `{"account":"ana@example.com","password":"secret password","code":"` + code + `"}`,
+			setupMocks: func(mockDS *mock_ds.MockDS, mockOperatorProvider *mock_operator_provider.MockOperatorProvider) {
+				mockOperatorProvider.On("GetOperatorCredentials", mock.Anything, "ana@example.com").
+					Return(operator, credentials, nil).Once()
+				mockDS.On("SetNX", mock.Anything, mock.Anything, "ana@example.com", totp.Window).Return(true, nil).Once()
+				mockDS.On("Set", mock.Anything, isSessionKey, mock.Anything, 7200).Return(errors.New("ds error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    i18n.MsgInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.InjectMockJWTKey()
+			t.Cleanup(jwt_key.Reset)
+			log.Set(&mock_log.MockNoOpLogger{})
+			mockDS := new(mock_ds.MockDS)
+			mockOperatorProvider := new(mock_operator_provider.MockOperatorProvider)
+			mockDS.On("Get", mock.Anything, "LocalLoginLockout:lock:ip").Return(false, "", nil).Once()
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockDS, mockOperatorProvider)
+			}
+			session.Set(session.New(mockDS, 7200))
+			auth.Set(auth.New(auth.OAuthConfig{ClientSecret: "mySecret", JWTRefreshExpirationInSeconds: 7200}, mockDS))
+			operator_provider.Set(mockOperatorProvider)
+			cfg := auth.OAuthConfig{JWTExpirationInSeconds: 3600, JWTRefreshExpirationInSeconds: 7200}
+			lockout := middleware.Lockout(middleware.LockoutMiddleware{
+				Lockout:   ratelimit.NewLockout("LocalLoginLockout", ratelimit.LockoutCfg{MaxFailures: 10}, mockDS),
+				KeyGetter: func(*http.Request) string { return "ip" },
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/local", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			lockout(LocalLogin(cfg)).ServeHTTP(w, req)
+
+			if tt.expectedMsg != "" {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			} else {
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				var resp response.Response[LocalLoginOutput]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, 3600, resp.Data.ExpiresIn)
+				claims, _, err := auth.ParseTokenWithClaims(authCookie(w.Result()))
+				assert.NoError(t, err)
+				assert.Equal(t, auth.LocalProvider, claims.Provider)
+				assert.True(t, hasCookie(w.Result(), auth.RefreshTokenKey))
+			}
+			mockDS.AssertExpectations(t)
+			mockOperatorProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func authCookie(res *http.Response) string {
+	for _, cookie := range res.Cookies() {
+		if cookie.Name == auth.AuthTokenKey {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+func TestGetAuthProviders(t *testing.T) {
+	auth.Set(auth.New(auth.OAuthConfig{ClientID: "google", ClientSecret: "sec", ProviderConfigs: map[string]auth.ProviderConfig{
+		"entra": {ClientSecret: "sec"},
+	}}, new(mock_ds.MockDS)))
+	req := httptest.NewRequest(http.MethodGet, "/auth/providers", nil)
+	w := httptest.NewRecorder()
+
+	GetAuthProviders(auth.OAuthConfig{LocalLogin: true}).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp response.Response[GetAuthProvidersOutput]
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, GetAuthProvidersOutput{Providers: []string{"default", "entra"}, LocalLogin: true}, resp.Data)
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"via/internal/auth"
+	biz_audit "via/internal/biz/audit"
+	biz_device "via/internal/biz/device"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/middleware"
+	"via/internal/model"
+	response "via/internal/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CreateDeviceInput struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Branch string `json:"branch"`
+	// Privacy of a monitor, empty uses the branch default
+	Privacy string `json:"privacy"`
+}
+
+type DevicePairingOutput struct {
+	Device      model.Device `json:"device"`
+	PairingCode string       `json:"pairingCode"`
+	ExpiresIn   int          `json:"expiresIn"` // in seconds
+}
+
+// CreateDevice registers a kiosk or monitor of a branch, the pairing code returned is entered on the device
+func CreateDevice(cfg biz_device.DeviceCfg) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*DevicePairingOutput]{}
+		var input CreateDeviceInput
+		if !getJsonBody(w, r, &input) {
+			return
+		}
+		input.Name = strings.TrimSpace(input.Name)
+		input.Branch = strings.TrimSpace(input.Branch)
+		if details := validateDeviceInput(input); len(details) > 0 {
+			log.Get().Warn(r.Context(), "msg", "invalid device", "kind", input.Kind)
+			res.Error = i18n.Error(r, i18n.MsgDeviceInvalid, details...)
+			response.WriteJSON(w, r, res, http.StatusBadRequest)
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "operator_id", r.Context().Value(middleware.OperatorIDKey))
+
+		device, code, err := biz_device.Create(r.Context(),
+			model.Device{Name: input.Name, Kind: input.Kind, Branch: input.Branch, Privacy: input.Privacy}, cfg)
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "failed to create device")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		recordAudit(r, biz_audit.DEVICE_CREATE, biz_audit.TARGET_DEVICE, device.ID, nil, device)
+		logger.Info(r.Context(), "msg", "device created", "device_id", device.ID, "kind", device.Kind)
+		res.Data = &DevicePairingOutput{Device: device, PairingCode: code, ExpiresIn: cfg.PairingCodeTTL}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+type GetDevicesOutput struct {
+	Devices []model.Device `json:"devices"`
+}
+
+func GetDevices() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*GetDevicesOutput]{}
+		devices, err := biz_device.GetDevices(r.Context())
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to get devices")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		res.Data = &GetDevicesOutput{Devices: devices}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+// CreateDevicePairingCode gives the device a new pairing code, to pair it again or a replacement of it
+func CreateDevicePairingCode(cfg biz_device.DeviceCfg) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*DevicePairingOutput]{}
+		deviceId := 0
+		if deviceId = getDeviceId(w, r); deviceId == 0 {
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "operator_id", r.Context().Value(middleware.OperatorIDKey), "device_id", deviceId)
+
+		code, found, err := biz_device.NewPairingCode(r.Context(), deviceId, cfg)
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "failed to create pairing code")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			logger.Warn(r.Context(), "msg", "device not found")
+			res.Error = i18n.Error(r, i18n.MsgDeviceNotFound)
+			response.WriteJSON(w, r, res, http.StatusNotFound)
+			return
+		}
+		recordAudit(r, biz_audit.DEVICE_PAIRING_CODE, biz_audit.TARGET_DEVICE, deviceId, nil, nil)
+		logger.Info(r.Context(), "msg", "device pairing code created")
+		res.Data = &DevicePairingOutput{Device: model.Device{ID: deviceId}, PairingCode: code, ExpiresIn: cfg.PairingCodeTTL}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+// RevokeDevice rejects the device token, the device has to be created again to be used
+func RevokeDevice() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[any]{}
+		deviceId := 0
+		if deviceId = getDeviceId(w, r); deviceId == 0 {
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "operator_id", r.Context().Value(middleware.OperatorIDKey), "device_id", deviceId)
+
+		found, err := biz_device.Revoke(r.Context(), deviceId)
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "failed to revoke device")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			logger.Warn(r.Context(), "msg", "device not found")
+			res.Error = i18n.Error(r, i18n.MsgDeviceNotFound)
+			response.WriteJSON(w, r, res, http.StatusNotFound)
+			return
+		}
+		recordAudit(r, biz_audit.DEVICE_REVOKE, biz_audit.TARGET_DEVICE, deviceId, nil, nil)
+		logger.Info(r.Context(), "msg", "device revoked")
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+type SetDevicePrivacyInput struct {
+	Privacy string `json:"privacy"` // empty uses the branch default
+}
+
+// SetDevicePrivacy changes how the monitor identifies the guides, it applies when the monitor reconnects
+func SetDevicePrivacy() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[any]{}
+		deviceId := 0
+		if deviceId = getDeviceId(w, r); deviceId == 0 {
+			return
+		}
+		var input SetDevicePrivacyInput
+		if !getJsonBody(w, r, &input) {
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "operator_id", r.Context().Value(middleware.OperatorIDKey), "device_id", deviceId)
+
+		found, err := biz_device.SetPrivacy(r.Context(), deviceId, input.Privacy)
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "failed to set device privacy")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			logger.Warn(r.Context(), "msg", "device not found")
+			res.Error = i18n.Error(r, i18n.MsgDeviceNotFound)
+			response.WriteJSON(w, r, res, http.StatusNotFound)
+			return
+		}
+		recordAudit(r, biz_audit.DEVICE_PRIVACY, biz_audit.TARGET_DEVICE, deviceId, nil,
+			map[string]string{"privacy": input.Privacy})
+		logger.Info(r.Context(), "msg", "device privacy set", "privacy", input.Privacy)
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+type PairDeviceInput struct {
+	Code string `json:"code"`
+}
+
+type PairDeviceOutput struct {
+	Device model.Device `json:"device"`
+	Token  string       `json:"token"` // also set as a cookie, for the devices sending it as the x-device-token header
+}
+
+// PairDevice exchanges the pairing code entered on the device for its long lived token
+func PairDevice(cfg biz_device.DeviceCfg, oauthCfg auth.OAuthConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*PairDeviceOutput]{}
+		var input PairDeviceInput
+		if !getJsonBody(w, r, &input) {
+			return
+		}
+		device, token, err := biz_device.Pair(r.Context(), input.Code)
+		if errors.Is(err, biz_device.ErrPairingCodeInvalid) {
+			middleware.RegisterLockoutFailure(r)
+			log.Get().Warn(r.Context(), "msg", "invalid pairing code")
+			res.Error = i18n.Error(r, i18n.MsgPairingCodeInvalid)
+			response.WriteJSON(w, r, res, http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to pair device")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		auth.SetDeviceToken(w, token, oauthCfg, cfg.CookieMaxAge)
+		middleware.RecordAudit(r, biz_audit.Event{Actor: "device:" + strconv.Itoa(device.ID), Action: biz_audit.DEVICE_PAIR,
+			TargetType: biz_audit.TARGET_DEVICE, TargetID: strconv.Itoa(device.ID)})
+		log.Get().Info(r.Context(), "msg", "device paired", "device_id", device.ID, "kind", device.Kind)
+		res.Data = &PairDeviceOutput{Device: device, Token: token}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+// validateDeviceInput returns the failures of the trimmed text fields of the device to create, the
+// kind and privacy are checked against the api document by middleware.ValidateRequest
+func validateDeviceInput(input CreateDeviceInput) []response.FieldError {
+	details := []response.FieldError{}
+	details = appendTextFieldError(details, "name", input.Name, 200)
+	details = appendTextFieldError(details, "branch", input.Branch, 20)
+	return details
+}
+
+func appendTextFieldError(details []response.FieldError, field, value string, maxLength int) []response.FieldError {
+	switch {
+	case value == "":
+		return append(details, response.FieldError{Field: field, Code: response.FIELD_REQUIRED})
+	case len(value) > maxLength:
+		return append(details, response.FieldError{Field: field, Code: response.FIELD_TOO_LONG})
+	}
+	return details
+}
+
+func getDeviceId(w http.ResponseWriter, r *http.Request) int {
+	deviceId, err := strconv.Atoi(chi.URLParam(r, "deviceId"))
+	if err != nil || deviceId <= 0 {
+		log.Get().Warn(r.Context(), "msg", "invalid device id", "device_id", chi.URLParam(r, "deviceId"))
+		response.WriteJSON(w, r, response.Response[any]{Error: i18n.Error(r, i18n.MsgDeviceInvalid)}, http.StatusBadRequest)
+		return 0
+	}
+	return deviceId
+}
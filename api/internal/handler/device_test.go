@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"via/internal/auth"
+	biz_device "via/internal/biz/device"
+	biz_monitor "via/internal/biz/monitor"
+	"via/internal/global"
+	"via/internal/i18n"
+	"via/internal/model"
+	device_provider "via/internal/provider/device"
+	mock_device_provider "via/internal/provider/device/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	response "via/internal/response"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var deviceCfg = biz_device.DeviceCfg{PairingCodeTTL: 600, CookieMaxAge: 86400}
+
+func TestCreateDevice(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+
+	tests := []struct {
+		name           string
+		body           string
+		expected       model.Device
+		createErr      error
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "device created", body: `{"name":" Entrance ","kind":"kiosk","branch":"123"}`,
+			expected:       model.Device{Name: "Entrance", Kind: biz_device.KIND_KIOSK, Branch: "123"},
+			expectedStatus: http.StatusOK},
+		{name: "missing name", body: `{"kind":"monitor","branch":"123"}`,
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgDeviceInvalid},
+		{name: "missing branch", body: `{"name":"Hall","kind":"monitor"}`,
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgDeviceInvalid},
+		{name: "monitor with privacy", body: `{"name":"Hall","kind":"monitor","branch":"123","privacy":"ticket"}`,
+			expected:       model.Device{Name: "Hall", Kind: biz_device.KIND_MONITOR, Branch: "123", Privacy: biz_monitor.PRIVACY_TICKET},
+			expectedStatus: http.StatusOK},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgBadRequest},
+		{name: "error creating device", body: `{"name":"Hall","kind":"monitor","branch":"123"}`,
+			expected:  model.Device{Name: "Hall", Kind: biz_device.KIND_MONITOR, Branch: "123"},
+			createErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError,
+			expectedMsg: i18n.MsgInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_device_provider.MockDeviceProvider)
+			device_provider.Set(mockProvider)
+			created := tt.expected
+			created.ID = 4
+			mockProvider.On("CreateDevice", mock.Anything, tt.expected, mock.Anything).Return(created, tt.createErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/devices", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			CreateDevice(deviceCfg).ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+				return
+			}
+			var resp response.Response[DevicePairingOutput]
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, created, resp.Data.Device)
+			assert.Len(t, resp.Data.PairingCode, 8)
+			assert.Equal(t, 600, resp.Data.ExpiresIn)
+		})
+	}
+}
+
+func TestValidateDeviceInput(t *testing.T) {
+	assert.Empty(t, validateDeviceInput(CreateDeviceInput{Name: "Hall", Kind: biz_device.KIND_MONITOR, Branch: "123"}))
+	assert.Equal(t, []response.FieldError{
+		{Field: "name", Code: response.FIELD_REQUIRED},
+		{Field: "branch", Code: response.FIELD_TOO_LONG},
+	}, validateDeviceInput(CreateDeviceInput{Kind: biz_device.KIND_MONITOR, Branch: strings.Repeat("1", 21)}))
+}
+
+func TestGetDevices(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mockProvider := new(mock_device_provider.MockDeviceProvider)
+	device_provider.Set(mockProvider)
+	mockProvider.On("GetDevices", mock.Anything).Return([]model.Device{{ID: 4, Name: "Hall"}}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/devices", nil)
+	w := httptest.NewRecorder()
+	GetDevices().ServeHTTP(w, req)
+
+	var resp response.Response[GetDevicesOutput]
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []model.Device{{ID: 4, Name: "Hall"}}, resp.Data.Devices)
+
+	mockProvider.On("GetDevices", mock.Anything).Return([]model.Device{}, errors.New("db error")).Once()
+	w = httptest.NewRecorder()
+	GetDevices().ServeHTTP(w, req)
+	assertJSONErrorResponse(t, req, w, http.StatusInternalServerError, i18n.MsgInternalServerError)
+}
+
+func TestCreateDevicePairingCode(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+
+	tests := []struct {
+		name           string
+		deviceId       string
+		found          bool
+		setErr         error
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "pairing code created", deviceId: "4", found: true, expectedStatus: http.StatusOK},
+		{name: "device not found", deviceId: "4", expectedStatus: http.StatusNotFound, expectedMsg: i18n.MsgDeviceNotFound},
+		{name: "invalid device id", deviceId: "x", expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgDeviceInvalid},
+		{name: "error setting pairing code", deviceId: "4", setErr: errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_device_provider.MockDeviceProvider)
+			device_provider.Set(mockProvider)
+			mockProvider.On("SetDevicePairing", mock.Anything, 4, mock.Anything).Return(tt.found, tt.setErr)
+
+			req := newSessionRequest(http.MethodPost, map[string]string{"deviceId": tt.deviceId}, 1)
+			w := httptest.NewRecorder()
+			CreateDevicePairingCode(deviceCfg).ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+				return
+			}
+			var resp response.Response[DevicePairingOutput]
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, 4, resp.Data.Device.ID)
+			assert.Len(t, resp.Data.PairingCode, 8)
+		})
+	}
+}
+
+func TestRevokeDevice(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+
+	tests := []struct {
+		name           string
+		deviceId       string
+		found          bool
+		revokeErr      error
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "device revoked", deviceId: "4", found: true, expectedStatus: http.StatusOK},
+		{name: "device not found", deviceId: "4", expectedStatus: http.StatusNotFound, expectedMsg: i18n.MsgDeviceNotFound},
+		{name: "invalid device id", deviceId: "0", expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgDeviceInvalid},
+		{name: "error revoking device", deviceId: "4", revokeErr: errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_device_provider.MockDeviceProvider)
+			device_provider.Set(mockProvider)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			mockProvider.On("RevokeDevice", mock.Anything, 4).Return(tt.found, tt.revokeErr)
+			mockPubSub.On("Publish", mock.Anything, global.DeviceRevokedChannel, "4").Return(nil)
+
+			req := newSessionRequest(http.MethodDelete, map[string]string{"deviceId": tt.deviceId}, 1)
+			w := httptest.NewRecorder()
+			RevokeDevice().ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+				return
+			}
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockPubSub.AssertCalled(t, "Publish", mock.Anything, global.DeviceRevokedChannel, "4")
+		})
+	}
+}
+
+func TestSetDevicePrivacy(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+
+	tests := []struct {
+		name           string
+		deviceId       string
+		body           string
+		found          bool
+		setErr         error
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "privacy set", deviceId: "4", body: `{"privacy":"first_name"}`, found: true, expectedStatus: http.StatusOK},
+		{name: "branch default", deviceId: "4", body: `{"privacy":""}`, found: true, expectedStatus: http.StatusOK},
+		{name: "invalid body", deviceId: "4", body: `{`, expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgBadRequest},
+		{name: "invalid device id", deviceId: "x", body: `{"privacy":"ticket"}`,
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgDeviceInvalid},
+		{name: "device not found", deviceId: "4", body: `{"privacy":"ticket"}`,
+			expectedStatus: http.StatusNotFound, expectedMsg: i18n.MsgDeviceNotFound},
+		{name: "error setting privacy", deviceId: "4", body: `{"privacy":"ticket"}`, setErr: errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_device_provider.MockDeviceProvider)
+			device_provider.Set(mockProvider)
+			mockProvider.On("SetDevicePrivacy", mock.Anything, 4, mock.Anything).Return(tt.found, tt.setErr)
+
+			req := newSessionRequest(http.MethodPut, map[string]string{"deviceId": tt.deviceId}, 1)
+			req.Body = io.NopCloser(strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			SetDevicePrivacy().ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+				return
+			}
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+func TestPairDevice(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+	monitor := model.Device{ID: 4, Kind: biz_device.KIND_MONITOR, Branch: "123"}
+
+	tests := []struct {
+		name           string
+		body           string
+		device         model.Device
+		pairErr        error
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "device paired", body: `{"code":"ABCD-2345"}`, device: monitor, expectedStatus: http.StatusOK},
+		{name: "invalid code", body: `{"code":"ABCD-2345"}`, expectedStatus: http.StatusUnauthorized,
+			expectedMsg: i18n.MsgPairingCodeInvalid},
+		{name: "invalid body", body: `[`, expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgBadRequest},
+		{name: "error pairing", body: `{"code":"ABCD-2345"}`, pairErr: errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_device_provider.MockDeviceProvider)
+			device_provider.Set(mockProvider)
+			mockProvider.On("PairDevice", mock.Anything, mock.Anything, mock.Anything).Return(tt.device, tt.pairErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/device/pair", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			PairDevice(deviceCfg, auth.OAuthConfig{CookieSecure: true}).ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+				assert.Empty(t, w.Result().Cookies())
+				return
+			}
+			var resp response.Response[PairDeviceOutput]
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, monitor, resp.Data.Device)
+			assert.True(t, strings.HasPrefix(resp.Data.Token, "4."))
+			cookie := w.Result().Cookies()[0]
+			assert.Equal(t, auth.DeviceTokenKey, cookie.Name)
+			assert.Equal(t, resp.Data.Token, cookie.Value)
+		})
+	}
+}
@@ -3,7 +3,13 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"time"
+	biz_audit "via/internal/biz/audit"
 	biz_config "via/internal/biz/config"
+	biz_guide_assign "via/internal/biz/guide/assign"
+	biz_guide_search "via/internal/biz/guide/search"
 	biz_guide_status "via/internal/biz/guide/status"
 	biz_operator "via/internal/biz/operator"
 	"via/internal/global"
@@ -25,6 +31,7 @@ type CreateGuideToWidthdrawInput struct {
 
 type CreateGuideToWidthdrawOutput struct {
 	WithdrawMessage string `json:"withdrawMessage"`
+	Ticket          int    `json:"ticket"` // number the guide is called by on the monitors
 }
 
 func CreateGuideToWidthdraw(biz biz_config.BussinessCfg) http.Handler {
@@ -35,10 +42,6 @@ func CreateGuideToWidthdraw(biz biz_config.BussinessCfg) http.Handler {
 		if ok := getJsonBody(w, r, &input); !ok {
 			return
 		}
-
-		if ok := isValidViaGuideId(w, r, input.ViaGuideId); !ok {
-			return
-		}
 		logger := log.Get()
 		logger.WithLogFieldsInRequest(r, "via_guide_id", input.ViaGuideId)
 
@@ -56,8 +59,8 @@ func CreateGuideToWidthdraw(biz biz_config.BussinessCfg) http.Handler {
 		}
 
 		if ok := isInvalidViaGuideToWithdraw(viaGuide, biz); !ok {
-			logger.Warn(r.Context(), "msg", "not able to create a new guide to process", "via_guide", viaGuide)
-			res.Message = i18n.Get(r, i18n.MsgGuideInvalid)
+			logger.Warn(r.Context(), "msg", "not able to create a new guide to process", "via_guide_status", viaGuide.Status)
+			res.Error = i18n.Error(r, i18n.MsgGuideInvalid)
 			response.WriteJSON(w, r, res, http.StatusBadRequest)
 			return
 		}
@@ -71,8 +74,10 @@ func CreateGuideToWidthdraw(biz biz_config.BussinessCfg) http.Handler {
 		if guide.ID != 0 {
 			logger.WithLogFieldsInRequest(r, "guide_id", guide.ID)
 			if biz_guide_status.IsAbleToReInit(guide.Status) {
-				guide_provider.Get().UpdateGuide(r.Context(), model.Guide{ID: guide.ID, Status: biz_guide_status.INITIAL})
+				guide_provider.Get().UpdateGuide(r.Context(),
+					model.Guide{ID: guide.ID, Status: biz_guide_status.INITIAL, ViaSnapshot: &viaGuide})
 				logger.Info(r.Context(), "msg", "guide re-init")
+				data.Ticket = guide.ID
 				data.WithdrawMessage = getWithDrawMessage(r, inProcess, viaGuide.ID)
 				response.WriteJSON(w, r, res, http.StatusOK)
 				return
@@ -80,8 +85,21 @@ func CreateGuideToWidthdraw(biz biz_config.BussinessCfg) http.Handler {
 
 			if !biz_guide_status.IsValidToCreateForWithdraw(guide.Status) {
 				logger.Warn(r.Context(), "msg", "not able to create a new guide to process",
-					"via_guide", viaGuide, "guide", guide)
-				res.Message = i18n.Get(r, i18n.MsgGuideInvalid)
+					"via_guide_status", viaGuide.Status, "guide_status", guide.Status)
+				res.Error = i18n.Error(r, i18n.MsgGuideInvalid)
+				response.WriteJSON(w, r, res, http.StatusBadRequest)
+				return
+			}
+		} else {
+			// a guide closed long ago left the hot tables, the kiosk must not open it again
+			archived, err := guide_provider.Get().GetArchivedGuideByViaGuideId(r.Context(), viaGuide.ID)
+			if ok := isFailedToFetchGuide(w, r, err); ok {
+				return
+			}
+			if archived.ID != 0 && !biz_guide_status.IsValidToCreateForWithdraw(archived.Status) {
+				logger.Warn(r.Context(), "msg", "not able to create an archived guide to process",
+					"archived_guide_id", archived.ID, "guide_status", archived.Status)
+				res.Error = i18n.Error(r, i18n.MsgGuideInvalid)
 				response.WriteJSON(w, r, res, http.StatusBadRequest)
 				return
 			}
@@ -90,7 +108,7 @@ func CreateGuideToWidthdraw(biz biz_config.BussinessCfg) http.Handler {
 
 		if err != nil {
 			logger.Error(r.Context(), err, "msg", "failed to create guide")
-			res.Message = i18n.Get(r, i18n.MsgInternalServerError)
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
 			status := http.StatusInternalServerError
 			response.WriteJSON(w, r, res, status)
 			return
@@ -101,6 +119,7 @@ func CreateGuideToWidthdraw(biz biz_config.BussinessCfg) http.Handler {
 		if err != nil {
 			logger.Error(r.Context(), err, "msg", "unable to publish event", "channel", global.NewGuideChannel)
 		}
+		data.Ticket = id
 		data.WithdrawMessage = getWithDrawMessage(r, inProcess, viaGuide.ID)
 		response.WriteJSON(w, r, res, http.StatusOK)
 	})
@@ -108,8 +127,14 @@ func CreateGuideToWidthdraw(biz biz_config.BussinessCfg) http.Handler {
 
 type GetOperatorGuideOutput struct {
 	OperatorGuides []model.OperatorGuide `json:"operatorGuides"`
+	Total          int                   `json:"total"`                // guides matching the filters, in every page
+	NextCursor     string                `json:"nextCursor,omitempty"` // missing on the last page
 }
 
+// GetOperatorGuide loads the guides in operator statuses filtered by the query parameters:
+// status (comma separated), mine (only the ones assigned to the operator), payment,
+// sort (createdAt or updatedAt), order (asc or desc), limit and the cursor of the previous page.
+// Oldest guides first by default.
 func GetOperatorGuide(r *http.Request) response.Response[any] {
 	res := response.Response[any]{}
 	operatorGuides := []model.OperatorGuide{}
@@ -118,7 +143,7 @@ func GetOperatorGuide(r *http.Request) response.Response[any] {
 	operatorId, ok := operatorIdCtx.(int)
 	if !ok {
 		log.Get().Warn(r.Context(), "msg", "missing operator id in context", "operator_id", operatorIdCtx)
-		res.Message = i18n.Get(r, i18n.MsgOperatorInvalid)
+		res.Error = i18n.Error(r, i18n.MsgOperatorInvalid)
 		res.HttpStatus = http.StatusUnauthorized
 		return res
 	}
@@ -126,38 +151,107 @@ func GetOperatorGuide(r *http.Request) response.Response[any] {
 	logger := log.Get()
 	logger.WithLogFieldsInRequest(r, "operator_id", operatorId)
 
-	guides, err := guide_provider.Get().GetGuidesByStatus(r.Context(), biz_guide_status.GetOperatorStatus())
+	search, err := parseOperatorGuideFeed(r.URL.Query(), operatorId)
+	if err != nil {
+		logger.Warn(r.Context(), "msg", "invalid operator guides filter", "filters", queryNames(r.URL.Query()), "error", err.Error())
+		res.Error = i18n.Error(r, i18n.MsgSearchInvalid, response.Details(err)...)
+		res.HttpStatus = http.StatusBadRequest
+		return res
+	}
 
+	page, err := guide_provider.Get().SearchGuides(r.Context(), search)
+	total := len(page.Guides)
+	// only a single page feed is counted by its length
+	if err == nil && (page.Next != nil || search.Cursor != nil) {
+		total, err = guide_provider.Get().CountGuides(r.Context(), search)
+	}
 	if err != nil {
 		log.Get().Error(r.Context(), err, "msg", "failed to fetch guide")
-		res.Message = i18n.Get(r, i18n.MsgInternalServerError)
+		res.Error = i18n.Error(r, i18n.MsgInternalServerError)
 		res.HttpStatus = http.StatusInternalServerError
 		return res
 	}
 
-	for _, guide := range guides {
-		operatorGuides = append(operatorGuides,
-			model.OperatorGuide{
-				GuideId:    guide.ID,
-				Recipient:  guide.Recipient,
-				Status:     biz_guide_status.GetStatusDescription(response.GetLanguage(r), guide.Status),
-				Operator:   guide.Operator,
-				Selectable: guide.Operator.ID == biz_operator.OPERATOR_SYSTEM || guide.Operator.ID == operatorId,
-				ViaGuideId: guide.ViaGuideID,
-				Payment:    biz_config.GetPaymentDescription(response.GetLanguage(r), guide.Payment),
-				LastChange: guide.UpdatedAt,
-			})
+	ids := []int{}
+	for _, guide := range page.Guides {
+		if guide.Operator.ID != biz_operator.OPERATOR_SYSTEM && !slices.Contains(ids, guide.Operator.ID) {
+			ids = append(ids, guide.Operator.ID)
+		}
+	}
+	online := getOnlineOperators(r, ids)
+	for _, guide := range page.Guides {
+		operatorGuide := toOperatorGuide(r, guide, operatorId)
+		operatorGuide.OperatorOnline = online[guide.Operator.ID]
+		operatorGuides = append(operatorGuides, operatorGuide)
+	}
+	logger.Info(r.Context(), "msg", "returning operator guides", "total", total)
+	data := GetOperatorGuideOutput{OperatorGuides: operatorGuides, Total: total}
+	if page.Next != nil {
+		data.NextCursor = biz_guide_search.EncodeCursor(search, *page.Next)
 	}
-	logger.Info(r.Context(), "msg", "returning operator guides")
-	res.Data = GetOperatorGuideOutput{OperatorGuides: operatorGuides}
+	res.Data = data
 	res.HttpStatus = http.StatusOK
 	return res
 }
 
+// parseOperatorGuideFeed validates the operator guides filters, the status ones are limited
+// to the operator statuses
+func parseOperatorGuideFeed(query url.Values, operatorId int) (model.GuideSearch, error) {
+	search := model.GuideSearch{
+		Status:  biz_guide_status.GetOperatorStatus(),
+		Payment: query.Get("payment"),
+		Sort:    biz_guide_search.SORT_CREATED_AT,
+		Limit:   biz_guide_search.MAX_LIMIT,
+	}
+	if v := query.Get("status"); v != "" {
+		status, err := parseStatusFilter(v, func(status string) bool {
+			return slices.Contains(biz_guide_status.GetOperatorStatus(), status)
+		})
+		if err != nil {
+			return search, err
+		}
+		search.Status = status
+	}
+	switch query.Get("mine") {
+	case "", "false":
+	case "true":
+		search.OperatorID = operatorId
+	default:
+		return search, response.FieldError{Field: "mine", Code: response.FIELD_INVALID}
+	}
+	if !isValidPaymentFilter(search.Payment) {
+		return search, response.FieldError{Field: "payment", Code: response.FIELD_INVALID}
+	}
+	if err := parseSearchOrder(query, &search); err != nil {
+		return search, err
+	}
+	return search, parseSearchCursor(query, &search)
+}
+
+// toOperatorGuide describes a guide in the request language as seen by the operator
+func toOperatorGuide(r *http.Request, guide model.Guide, operatorId int) model.OperatorGuide {
+	lang := response.GetLanguage(r)
+	return model.OperatorGuide{
+		GuideId:      guide.ID,
+		Recipient:    guide.Recipient,
+		Status:       biz_guide_status.GetStatusDescription(lang, guide.Status),
+		Operator:     guide.Operator,
+		Selectable:   guide.Operator.ID == biz_operator.OPERATOR_SYSTEM || guide.Operator.ID == operatorId,
+		ViaGuideId:   guide.ViaGuideID,
+		Payment:      biz_config.GetPaymentDescription(lang, guide.Payment),
+		LastChange:   guide.UpdatedAt,
+		StatusReason: biz_guide_status.GetReasonDescription(lang, guide.StatusReason),
+		ViaSnapshot:  guide.ViaSnapshot,
+		ViaSyncedAt:  guide.ViaSyncedAt,
+	}
+}
+
 type AssignGuideToOperatorOutput struct {
 	Guide model.Guide `json:"guide"`
 }
 
+// AssignGuideToOperator gives the guide to the logged operator. A guide attended by another active
+// operator is only taken by a supervisor with force=true.
 func AssignGuideToOperator() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		res := response.Response[AssignGuideToOperatorOutput]{}
@@ -172,16 +266,87 @@ func AssignGuideToOperator() http.Handler {
 			return
 		}
 		logger.WithLogFieldsInRequest(r, "operator_id", operatorId)
-		err := guide_provider.Get().UpdateGuide(r.Context(), model.Guide{ID: guideId, Operator: model.Operator{ID: operatorId}})
-		if err != nil {
-			log.Get().Error(r.Context(), err, "msg", "failed assigning operator to guide")
-			res.Message = i18n.Get(r, i18n.MsgInternalServerError)
-			status := http.StatusInternalServerError
-			response.WriteJSON(w, r, res, status)
+		force, ok := isForced(w, r)
+		if !ok {
+			return
+		}
+		err := biz_guide_assign.Take(r.Context(), guideId, operatorId, force)
+		if ok := isFailedToChangeOwner(w, r, err); ok {
+			return
+		}
+		recordAudit(r, biz_audit.GUIDE_ASSIGN, biz_audit.TARGET_GUIDE, guideId, nil,
+			map[string]any{"operatorId": operatorId, "forced": force})
+		logger.Info(r.Context(), "msg", "operator assigned to guide", "forced", force)
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+// ReleaseGuide gives the guide back to SYSTEM, for anybody to take it. Only the operator attending
+// it, or a supervisor with force=true, releases it.
+func ReleaseGuide() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[any]{}
+		valid, guideId := isValidGuideId(w, r, chi.URLParam(r, "guideId"))
+		if !valid {
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "guide_id", guideId)
+		operatorId := 0
+		if operatorId = isValidOperatorId(w, r); operatorId == 0 {
+			return
+		}
+		logger.WithLogFieldsInRequest(r, "operator_id", operatorId)
+		force, ok := isForced(w, r)
+		if !ok {
+			return
+		}
+		err := biz_guide_assign.Release(r.Context(), guideId, operatorId, force)
+		if ok := isFailedToChangeOwner(w, r, err); ok {
+			return
+		}
+		recordAudit(r, biz_audit.GUIDE_RELEASE, biz_audit.TARGET_GUIDE, guideId, nil,
+			map[string]any{"operatorId": biz_operator.OPERATOR_SYSTEM, "forced": force})
+		logger.Info(r.Context(), "msg", "guide released", "forced", force)
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+type TransferGuideInput struct {
+	OperatorId int `json:"operatorId"`
+}
+
+// TransferGuide hands the guide to another active operator. Only the operator attending it,
+// or a supervisor with force=true, transfers it.
+func TransferGuide() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[any]{}
+		valid, guideId := isValidGuideId(w, r, chi.URLParam(r, "guideId"))
+		if !valid {
 			return
 		}
-		logger.Info(r.Context(), "msg", "operator assigned to guide")
-		pubsub.Get().Publish(r.Context(), global.GuideAssignmentChannel, fmt.Sprintf("{\"guide_id\":\"%d\"}", guideId))
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "guide_id", guideId)
+		operatorId := 0
+		if operatorId = isValidOperatorId(w, r); operatorId == 0 {
+			return
+		}
+		logger.WithLogFieldsInRequest(r, "operator_id", operatorId)
+		force, ok := isForced(w, r)
+		if !ok {
+			return
+		}
+		var input TransferGuideInput
+		if ok := getJsonBody(w, r, &input); !ok {
+			return
+		}
+		err := biz_guide_assign.Transfer(r.Context(), guideId, operatorId, input.OperatorId, force)
+		if ok := isFailedToChangeOwner(w, r, err); ok {
+			return
+		}
+		recordAudit(r, biz_audit.GUIDE_TRANSFER, biz_audit.TARGET_GUIDE, guideId, nil,
+			map[string]any{"operatorId": input.OperatorId, "forced": force})
+		logger.Info(r.Context(), "msg", "guide transferred", "to_operator_id", input.OperatorId, "forced", force)
 		response.WriteJSON(w, r, res, http.StatusOK)
 	})
 }
@@ -259,16 +424,74 @@ func UpdateGuideStatus() http.Handler {
 		}
 
 		logger.WithLogFieldsInRequest(r, "status", input.Status)
+		// the previous status is only audited, the update goes on without it
+		var before any
+		if guide, err := guide_provider.Get().GetGuideById(r.Context(), guideId); err == nil && guide.ID != 0 {
+			before = map[string]string{"status": guide.Status}
+		}
 		err := guide_provider.Get().UpdateGuide(r.Context(), model.Guide{ID: guideId, Status: input.Status})
 		if err != nil {
 			log.Get().Error(r.Context(), err, "msg", "failed updating guide status")
-			res.Message = i18n.Get(r, i18n.MsgInternalServerError)
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
 			status := http.StatusInternalServerError
 			response.WriteJSON(w, r, res, status)
 			return
 		}
+		recordAudit(r, biz_audit.GUIDE_STATUS, biz_audit.TARGET_GUIDE, guideId, before,
+			map[string]string{"status": input.Status})
 		logger.Info(r.Context(), "msg", "guide status updated")
 		pubsub.Get().Publish(r.Context(), global.GuideStatusChangeChannel, fmt.Sprintf("{\"guide_id\":\"%d\"}", guideId))
 		response.WriteJSON(w, r, res, http.StatusOK)
 	})
 }
+
+type SyncGuideOutput struct {
+	ViaSnapshot model.ViaGuide `json:"viaSnapshot"`
+	ViaSyncedAt time.Time      `json:"viaSyncedAt"`
+}
+
+// SyncGuide refreshes the carrier tracking snapshot stored with the guide
+func SyncGuide() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*SyncGuideOutput]{}
+		valid, guideId := isValidGuideId(w, r, chi.URLParam(r, "guideId"))
+		if !valid {
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "guide_id", guideId)
+
+		guide, err := guide_provider.Get().GetGuideById(r.Context(), guideId)
+		if ok := isFailedToFetchGuide(w, r, err); ok {
+			return
+		}
+		if ok := isGuideNotFound(w, r, guide.ViaGuideID); !ok {
+			return
+		}
+		logger.WithLogFieldsInRequest(r, "via_guide_id", guide.ViaGuideID)
+
+		viaGuide, err := via_guide_provider.Get().GetGuide(via_guide_provider.WithoutCache(r.Context()), guide.ViaGuideID)
+		if ok := isFailedToFetchGuide(w, r, err); ok {
+			return
+		}
+		if ok := isGuideNotFound(w, r, viaGuide.ID); !ok {
+			return
+		}
+
+		syncedAt := time.Now()
+		err = guide_provider.Get().SyncGuide(r.Context(), guideId, viaGuide, syncedAt)
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "failed updating guide snapshot")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		logger.Info(r.Context(), "msg", "guide snapshot synced")
+		err = pubsub.Get().Publish(r.Context(), global.GuideSyncChannel, fmt.Sprintf("{\"guide_id\":\"%d\"}", guideId))
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "unable to publish event", "channel", global.GuideSyncChannel)
+		}
+		res.Data = &SyncGuideOutput{ViaSnapshot: viaGuide, ViaSyncedAt: syncedAt}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
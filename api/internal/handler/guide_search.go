@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	biz_config "via/internal/biz/config"
+	biz_guide_search "via/internal/biz/guide/search"
+	biz_guide_status "via/internal/biz/guide/status"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	response "via/internal/response"
+)
+
+const searchDateLayout = "2006-01-02"
+
+var viaGuideIdPrefix = regexp.MustCompile(`^\d{1,12}$`)
+
+type SearchGuidesOutput struct {
+	Guides     []model.OperatorGuide `json:"guides"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+}
+
+// SearchGuides searches every guide, whatever its status, by the query parameters:
+// viaGuideId (prefix), recipient, operatorId, status (comma separated), payment,
+// from and to (creation dates, both included), sort (createdAt or updatedAt),
+// order (asc or desc), limit and the cursor of the previous page
+func SearchGuides() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*SearchGuidesOutput]{}
+		operatorId := 0
+		if operatorId = isValidOperatorId(w, r); operatorId == 0 {
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "operator_id", operatorId)
+
+		search, err := parseGuideSearch(r.URL.Query())
+		if err != nil {
+			logger.Warn(r.Context(), "msg", "invalid guide search", "filters", queryNames(r.URL.Query()), "error", err.Error())
+			res.Error = i18n.Error(r, i18n.MsgSearchInvalid, response.Details(err)...)
+			response.WriteJSON(w, r, res, http.StatusBadRequest)
+			return
+		}
+
+		page, err := guide_provider.Get().SearchGuides(r.Context(), search)
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "failed to search guides")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+
+		data := &SearchGuidesOutput{Guides: []model.OperatorGuide{}}
+		for _, guide := range page.Guides {
+			data.Guides = append(data.Guides, toOperatorGuide(r, guide, operatorId))
+		}
+		if page.Next != nil {
+			data.NextCursor = biz_guide_search.EncodeCursor(search, *page.Next)
+		}
+		res.Data = data
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+// parseGuideSearch validates the search query parameters, newest guides first by default
+func parseGuideSearch(query url.Values) (model.GuideSearch, error) {
+	search := model.GuideSearch{
+		ViaGuideIDPrefix: query.Get("viaGuideId"),
+		Recipient:        biz_guide_search.NormalizeName(query.Get("recipient")),
+		Payment:          query.Get("payment"),
+		Sort:             biz_guide_search.SORT_CREATED_AT,
+		Desc:             true,
+		Limit:            biz_guide_search.DEFAULT_LIMIT,
+	}
+	if search.ViaGuideIDPrefix != "" && !viaGuideIdPrefix.MatchString(search.ViaGuideIDPrefix) {
+		return search, response.FieldError{Field: "viaGuideId", Code: response.FIELD_INVALID}
+	}
+	if v := query.Get("operatorId"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			return search, response.FieldError{Field: "operatorId", Code: response.FIELD_INVALID}
+		}
+		search.OperatorID = id
+	}
+	if v := query.Get("status"); v != "" {
+		status, err := parseStatusFilter(v, biz_guide_status.IsValid)
+		if err != nil {
+			return search, err
+		}
+		search.Status = status
+	}
+	if !isValidPaymentFilter(search.Payment) {
+		return search, response.FieldError{Field: "payment", Code: response.FIELD_INVALID}
+	}
+	if v := query.Get("from"); v != "" {
+		from, err := time.ParseInLocation(searchDateLayout, v, time.Local)
+		if err != nil {
+			return search, response.FieldError{Field: "from", Code: response.FIELD_INVALID}
+		}
+		search.From = &from
+	}
+	if v := query.Get("to"); v != "" {
+		to, err := time.ParseInLocation(searchDateLayout, v, time.Local)
+		if err != nil {
+			return search, response.FieldError{Field: "to", Code: response.FIELD_INVALID}
+		}
+		// the whole day is included
+		to = to.AddDate(0, 0, 1)
+		search.To = &to
+	}
+	if search.From != nil && search.To != nil && !search.From.Before(*search.To) {
+		return search, response.FieldError{Field: "from", Code: response.FIELD_OUT_OF_RANGE}
+	}
+	if err := parseSearchOrder(query, &search); err != nil {
+		return search, err
+	}
+	return search, parseSearchCursor(query, &search)
+}
+
+// parseStatusFilter returns the comma separated statuses of v, all of them must be allowed
+func parseStatusFilter(v string, allowed func(string) bool) ([]string, error) {
+	statuses := []string{}
+	for _, status := range strings.Split(v, ",") {
+		if !allowed(status) {
+			return nil, response.FieldError{Field: "status", Code: response.FIELD_INVALID}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func isValidPaymentFilter(payment string) bool {
+	return payment == "" || payment == biz_config.PAID_SHIPPING || payment == biz_config.PAID_ON_DESTINATION
+}
+
+// parseSearchOrder overrides the default sort, order and limit of search with the query ones
+func parseSearchOrder(query url.Values, search *model.GuideSearch) error {
+	if v := query.Get("sort"); v != "" {
+		if !biz_guide_search.IsValidSort(v) {
+			return response.FieldError{Field: "sort", Code: response.FIELD_INVALID}
+		}
+		search.Sort = v
+	}
+	switch query.Get("order") {
+	case "":
+	case "asc":
+		search.Desc = false
+	case "desc":
+		search.Desc = true
+	default:
+		return response.FieldError{Field: "order", Code: response.FIELD_INVALID}
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return response.FieldError{Field: "limit", Code: response.FIELD_INVALID}
+		}
+		if limit <= 0 || limit > biz_guide_search.MAX_LIMIT {
+			return response.FieldError{Field: "limit", Code: response.FIELD_OUT_OF_RANGE}
+		}
+		search.Limit = limit
+	}
+	return nil
+}
+
+// parseSearchCursor sets the cursor of the page to return from the query one, search must already
+// have its sort, order and filters since the cursor is only valid for them
+func parseSearchCursor(query url.Values, search *model.GuideSearch) error {
+	if v := query.Get("cursor"); v != "" {
+		cursor, err := biz_guide_search.DecodeCursor(v, *search)
+		if err != nil {
+			return fmt.Errorf("%w: %w", response.FieldError{Field: "cursor", Code: response.FIELD_INVALID}, err)
+		}
+		search.Cursor = &cursor
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	biz_config "via/internal/biz/config"
+	biz_guide_search "via/internal/biz/guide/search"
+	biz_guide_status "via/internal/biz/guide/status"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/i18n"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	mock_guide_provider "via/internal/provider/guide/mock"
+	"via/internal/response"
+	"via/internal/testutil"
+)
+
+func TestSearchGuides(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	cursor := model.GuideCursor{Value: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), ID: 7}
+	byUpdate := model.GuideSearch{Recipient: "jose pena", Sort: biz_guide_search.SORT_UPDATED_AT, Limit: 5}
+	byPrefix := model.GuideSearch{ViaGuideIDPrefix: "9990", Sort: biz_guide_search.SORT_CREATED_AT, Desc: true}
+	guide := model.Guide{ID: 8, ViaGuideID: "999025862539", Recipient: "JOSÉ PEÑA", Status: biz_guide_status.DELIVERED,
+		Payment: biz_config.PAID_SHIPPING, Operator: model.Operator{ID: biz_operator.OPERATOR_SYSTEM}}
+
+	tests := []struct {
+		name           string
+		query          string
+		operatorId     any
+		setupMocks     func(*mock_guide_provider.MockGuideProvider)
+		expectedStatus int
+		expectedMsg    string
+		expectedCursor string
+	}{
+		{
+			name:           "missing operator",
+			query:          "",
+			setupMocks:     func(*mock_guide_provider.MockGuideProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedMsg:    i18n.MsgOperatorInvalid,
+		},
+		{
+			name:           "invalid filter",
+			query:          "?status=unknown",
+			operatorId:     42,
+			setupMocks:     func(*mock_guide_provider.MockGuideProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    i18n.MsgSearchInvalid,
+		},
+		{
+			name:       "failed search",
+			query:      "",
+			operatorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider) {
+				g.On("SearchGuides", mock.Anything, mock.Anything).Return(model.GuidePage{}, errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    i18n.MsgInternalServerError,
+		},
+		{
+			name:       "last page",
+			query:      "?recipient=Jose%20Pena&sort=updatedAt&order=asc&limit=5&cursor=" + biz_guide_search.EncodeCursor(byUpdate, cursor),
+			operatorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider) {
+				g.On("SearchGuides", mock.Anything, mock.MatchedBy(func(s model.GuideSearch) bool {
+					return s.Recipient == "jose pena" && s.Sort == biz_guide_search.SORT_UPDATED_AT && !s.Desc &&
+						s.Limit == 5 && s.Cursor != nil && s.Cursor.ID == cursor.ID
+				})).Return(model.GuidePage{Guides: []model.Guide{guide}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "next page",
+			query:      "?viaGuideId=9990",
+			operatorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider) {
+				g.On("SearchGuides", mock.Anything, mock.MatchedBy(func(s model.GuideSearch) bool {
+					return s.ViaGuideIDPrefix == "9990" && s.Desc && s.Limit == biz_guide_search.DEFAULT_LIMIT
+				})).Return(model.GuidePage{Guides: []model.Guide{guide}, Next: &cursor}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedCursor: biz_guide_search.EncodeCursor(byPrefix, cursor),
+		},
+		{
+			name:           "cursor of another sort",
+			query:          "?recipient=Jose%20Pena&order=asc&limit=5&cursor=" + biz_guide_search.EncodeCursor(byUpdate, cursor),
+			operatorId:     42,
+			setupMocks:     func(*mock_guide_provider.MockGuideProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    i18n.MsgSearchInvalid,
+		},
+		{
+			name:           "cursor of other filters",
+			query:          "?recipient=Ana&sort=updatedAt&order=asc&limit=5&cursor=" + biz_guide_search.EncodeCursor(byUpdate, cursor),
+			operatorId:     42,
+			setupMocks:     func(*mock_guide_provider.MockGuideProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    i18n.MsgSearchInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuideProvider)
+			tt.setupMocks(mockGuideProvider)
+
+			req := newOperatorRequest(http.MethodGet, "/guide/search"+tt.query, nil, tt.operatorId)
+			w := httptest.NewRecorder()
+
+			SearchGuides().ServeHTTP(w, req)
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp response.Response[SearchGuidesOutput]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Len(t, resp.Data.Guides, 1)
+				assert.Equal(t, guide.ViaGuideID, resp.Data.Guides[0].ViaGuideId)
+				assert.Equal(t, "Entregado", resp.Data.Guides[0].Status)
+				assert.True(t, resp.Data.Guides[0].Selectable)
+				assert.Equal(t, tt.expectedCursor, resp.Data.NextCursor)
+			} else {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			}
+			mockGuideProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestParseGuideSearch(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	search, err := parseGuideSearch(map[string][]string{
+		"operatorId": {"3"},
+		"status":     {biz_guide_status.ON_HOLD + "," + biz_guide_status.DELIVERED},
+		"payment":    {biz_config.PAID_ON_DESTINATION},
+		"from":       {"2026-10-01"},
+		"to":         {"2026-10-19"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.GuideSearch{
+		OperatorID: 3,
+		Status:     []string{biz_guide_status.ON_HOLD, biz_guide_status.DELIVERED},
+		Payment:    biz_config.PAID_ON_DESTINATION,
+		From:       &from,
+		To:         &to,
+		Sort:       biz_guide_search.SORT_CREATED_AT,
+		Desc:       true,
+		Limit:      biz_guide_search.DEFAULT_LIMIT,
+	}, search)
+
+	invalid := []map[string][]string{
+		{"viaGuideId": {"99A"}},
+		{"viaGuideId": {"9990258625391"}},
+		{"operatorId": {"x"}},
+		{"operatorId": {"0"}},
+		{"status": {biz_guide_status.ON_HOLD + ",x"}},
+		{"payment": {"X"}},
+		{"from": {"19/10/2026"}},
+		{"to": {"19/10/2026"}},
+		{"from": {"2026-10-20"}, "to": {"2026-10-19"}},
+		{"sort": {"recipient"}},
+		{"order": {"up"}},
+		{"limit": {"0"}},
+		{"limit": {"101"}},
+		{"cursor": {"x"}},
+	}
+	for _, query := range invalid {
+		_, err := parseGuideSearch(query)
+		assert.Error(t, err, query)
+	}
+
+	_, err = parseGuideSearch(map[string][]string{"from": {"2026-10-20"}, "to": {"2026-10-19"}})
+	assert.Equal(t, []response.FieldError{{Field: "from", Code: response.FIELD_OUT_OF_RANGE}}, response.Details(err))
+	_, err = parseGuideSearch(map[string][]string{"cursor": {"x"}})
+	assert.Equal(t, []response.FieldError{{Field: "cursor", Code: response.FIELD_INVALID}}, response.Details(err))
+}
@@ -9,21 +9,32 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+	biz_audit "via/internal/biz/audit"
+	audit_provider "via/internal/provider/audit"
+	mock_audit_provider "via/internal/provider/audit/mock"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	biz_config "via/internal/biz/config"
+	biz_guide_search "via/internal/biz/guide/search"
 	biz_guide_status "via/internal/biz/guide/status"
 	biz_operator "via/internal/biz/operator"
+	"via/internal/ds"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/global"
 	"via/internal/i18n"
 	"via/internal/log"
 	mock_log "via/internal/log/mock"
 	"via/internal/middleware"
 	"via/internal/model"
+	"via/internal/presence"
 	guide_provider "via/internal/provider/guide"
 	mock_guide_provider "via/internal/provider/guide/mock"
+	operator_provider "via/internal/provider/operator"
+	mock_operator_provider "via/internal/provider/operator/mock"
 	via_guide_provider "via/internal/provider/via/guide"
 	mock_via_guide_provider "via/internal/provider/via/guide/mock"
 	"via/internal/pubsub"
@@ -38,6 +49,9 @@ func assertJSONErrorResponse(t *testing.T, req *http.Request, w *httptest.Respon
 	err := json.NewDecoder(w.Body).Decode(&resp)
 	assert.NoError(t, err)
 	assert.Equal(t, i18n.Get(req, expectedMsg), resp.Message)
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, expectedMsg, resp.Error.Code)
+	}
 	assert.Equal(t, expectedStatus, w.Code)
 }
 
@@ -94,11 +108,6 @@ func TestCreateGuideToWithdraw(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
 
-	t.Run("invalid ViaGuideId", func(t *testing.T) {
-		rec := makeRequest("")
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
-	})
-
 	t.Run("error fetching via guide", func(t *testing.T) {
 		resetMocks()
 		mockVia.On("GetGuide", mock.Anything, "123456789012").Return(model.ViaGuide{}, errors.New("error"))
@@ -159,6 +168,7 @@ func TestCreateGuideToWithdraw(t *testing.T) {
 		mockPubSub.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		rec := makeRequest("123456789012")
 		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"ticket":10`)
 	})
 
 	t.Run("invalid status to create guide", func(t *testing.T) {
@@ -171,16 +181,56 @@ func TestCreateGuideToWithdraw(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
 
+	t.Run("archived guide is not created again", func(t *testing.T) {
+		resetMocks()
+		mockVia.On("GetGuide", mock.Anything, "123456789012").Return(
+			model.ViaGuide{ID: "123456789012", Status: biz.WithdrawStatus, Destination: model.ViaDestination{ID: biz.ViaBranch}}, nil)
+		mockGuide.On("GetGuideByViaGuideId", mock.Anything, "123456789012").Return(model.Guide{}, nil)
+		mockGuide.On("GetArchivedGuideByViaGuideId", mock.Anything, "123456789012").
+			Return(model.Guide{ID: 10, Status: biz_guide_status.DELIVERED}, nil)
+		rec := makeRequest("123456789012")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockGuide.AssertNotCalled(t, "CreateGuide", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error fetching archived guide", func(t *testing.T) {
+		resetMocks()
+		mockVia.On("GetGuide", mock.Anything, "123456789012").Return(
+			model.ViaGuide{ID: "123456789012", Status: biz.WithdrawStatus, Destination: model.ViaDestination{ID: biz.ViaBranch}}, nil)
+		mockGuide.On("GetGuideByViaGuideId", mock.Anything, "123456789012").Return(model.Guide{}, nil)
+		mockGuide.On("GetArchivedGuideByViaGuideId", mock.Anything, "123456789012").
+			Return(model.Guide{}, errors.New("error fetching guide"))
+		rec := makeRequest("123456789012")
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("archived partial delivery creates a new guide", func(t *testing.T) {
+		resetMocks()
+		viaGuideId := "123456789015"
+		mockVia.On("GetGuide", mock.Anything, viaGuideId).Return(
+			model.ViaGuide{ID: viaGuideId, Status: biz.WithdrawStatus, Destination: model.ViaDestination{ID: biz.ViaBranch}}, nil)
+		mockGuide.On("GetGuideByViaGuideId", mock.Anything, viaGuideId).Return(model.Guide{}, nil)
+		mockGuide.On("GetArchivedGuideByViaGuideId", mock.Anything, viaGuideId).
+			Return(model.Guide{ID: 10, Status: biz_guide_status.PARTIAL_DELIVERED}, nil)
+		mockGuide.On("CreateGuide", mock.Anything, mock.Anything).Return(201, nil)
+		mockPubSub.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		rec := makeRequest(viaGuideId)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"ticket":201`)
+	})
+
 	t.Run("success create new guide", func(t *testing.T) {
 		resetMocks()
 		viaGuideId := "123456789013"
 		mockVia.On("GetGuide", mock.Anything, viaGuideId).Return(
 			model.ViaGuide{ID: viaGuideId, Status: biz.WithdrawStatus, Destination: model.ViaDestination{ID: biz.ViaBranch}}, nil)
 		mockGuide.On("GetGuideByViaGuideId", mock.Anything, viaGuideId).Return(model.Guide{}, nil)
+		mockGuide.On("GetArchivedGuideByViaGuideId", mock.Anything, viaGuideId).Return(model.Guide{}, nil)
 		mockGuide.On("CreateGuide", mock.Anything, mock.Anything).Return(200, nil)
 		mockPubSub.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		rec := makeRequest(viaGuideId)
 		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"ticket":200`)
 	})
 
 	t.Run("error creating guide", func(t *testing.T) {
@@ -189,6 +239,7 @@ func TestCreateGuideToWithdraw(t *testing.T) {
 		mockVia.On("GetGuide", mock.Anything, viaGuideId).Return(
 			model.ViaGuide{ID: viaGuideId, Status: biz.WithdrawStatus, Destination: model.ViaDestination{ID: biz.ViaBranch}}, nil)
 		mockGuide.On("GetGuideByViaGuideId", mock.Anything, viaGuideId).Return(model.Guide{}, nil)
+		mockGuide.On("GetArchivedGuideByViaGuideId", mock.Anything, viaGuideId).Return(model.Guide{}, nil)
 		mockGuide.On("CreateGuide", mock.Anything, mock.Anything).Return(0, errors.New("error"))
 		rec := makeRequest(viaGuideId)
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
@@ -203,36 +254,122 @@ func TestGetOperatorGuide(t *testing.T) {
 		guide_provider.Set(mockGuideProvider)
 		mockPubSub := new(mock_pubsub.MockPubSub)
 		pubsub.Set(mockPubSub)
+		syncedAt := time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC)
 		guides := []model.Guide{
-			{ID: 2, Recipient: "John", Status: "INIT", Operator: model.Operator{ID: 42}, ViaGuideID: "V123", Payment: "PREPAID"},
+			{ID: 2, Recipient: "John", Status: "INIT", Operator: model.Operator{ID: 42}, ViaGuideID: "V123", Payment: "PREPAID",
+				ViaSnapshot: &model.ViaGuide{ID: "V123", Sender: "ACME"}, ViaSyncedAt: &syncedAt},
+			{ID: 3, Recipient: "Jane", Status: biz_guide_status.ON_HOLD, StatusReason: biz_guide_status.REASON_EXPIRED,
+				Operator: model.Operator{ID: biz_operator.OPERATOR_SYSTEM}, ViaGuideID: "V124", Payment: "PREPAID"},
 		}
 
 		req := newOperatorRequest(http.MethodGet, "/guide/operator", nil, 42)
 
 		operatorGuide := model.OperatorGuide{
-			GuideId:    guides[0].ID,
-			Recipient:  guides[0].Recipient,
-			Status:     biz_guide_status.GetStatusDescription(response.GetLanguage(req), guides[0].Status),
-			Operator:   guides[0].Operator,
-			Selectable: guides[0].Operator.ID == biz_operator.OPERATOR_SYSTEM || guides[0].Operator.ID == 42,
-			ViaGuideId: guides[0].ViaGuideID,
-			Payment:    biz_config.GetPaymentDescription(response.GetLanguage(req), guides[0].Payment),
-			LastChange: guides[0].UpdatedAt,
+			GuideId:     guides[0].ID,
+			Recipient:   guides[0].Recipient,
+			Status:      biz_guide_status.GetStatusDescription(response.GetLanguage(req), guides[0].Status),
+			Operator:    guides[0].Operator,
+			Selectable:  guides[0].Operator.ID == biz_operator.OPERATOR_SYSTEM || guides[0].Operator.ID == 42,
+			ViaGuideId:  guides[0].ViaGuideID,
+			Payment:     biz_config.GetPaymentDescription(response.GetLanguage(req), guides[0].Payment),
+			LastChange:  guides[0].UpdatedAt,
+			ViaSnapshot: guides[0].ViaSnapshot,
+			ViaSyncedAt: guides[0].ViaSyncedAt,
+			// only the operators owning a guide are looked up
+			OperatorOnline: true,
+		}
+		expiredGuide := model.OperatorGuide{
+			GuideId:      guides[1].ID,
+			Recipient:    guides[1].Recipient,
+			Status:       biz_guide_status.GetStatusDescription(response.GetLanguage(req), guides[1].Status),
+			StatusReason: "Vencida por inactividad",
+			Operator:     guides[1].Operator,
+			Selectable:   true,
+			ViaGuideId:   guides[1].ViaGuideID,
+			Payment:      biz_config.GetPaymentDescription(response.GetLanguage(req), guides[1].Payment),
 		}
 
-		mockGuideProvider.On("GetGuidesByStatus", mock.Anything, mock.Anything).
-			Return(guides, nil).Once()
+		mockGuideProvider.On("SearchGuides", mock.Anything, mock.MatchedBy(func(s model.GuideSearch) bool {
+			return assert.ObjectsAreEqual(biz_guide_status.GetOperatorStatus(), s.Status) && s.OperatorID == 0 && !s.Desc
+		})).Return(model.GuidePage{Guides: guides}, nil).Once()
 
 		mockPubSub.On("Subscribe", mock.Anything, []any{})
+		mockDS := new(mock_ds.MockDS)
+		ds.Set(mockDS)
+		presence.Set(presence.New(presence.PresenceCfg{}))
+		t.Cleanup(func() { presence.Set(nil) })
+		mockDS.On("SMembers", mock.Anything, "presence:operator:42:connections").Return([]string{"a"}, nil).Once()
 
 		resp := GetOperatorGuide(req)
 		data, ok := resp.Data.(GetOperatorGuideOutput)
 		assert.True(t, ok, "expected type GetOperatorGuideOutput")
-		assert.Equal(t, []model.OperatorGuide{operatorGuide}, data.OperatorGuides)
+		assert.Equal(t, []model.OperatorGuide{operatorGuide, expiredGuide}, data.OperatorGuides)
+		assert.Equal(t, 2, data.Total)
+		assert.Equal(t, http.StatusOK, resp.HttpStatus)
+		mockGuideProvider.AssertExpectations(t)
+		mockDS.AssertExpectations(t)
+	})
+
+	t.Run("truncated page", func(t *testing.T) {
+		mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+		guide_provider.Set(mockGuideProvider)
+		guides := []model.Guide{{ID: 2, Status: biz_guide_status.ON_HOLD, Operator: model.Operator{ID: 42}}}
+		search := mock.MatchedBy(func(s model.GuideSearch) bool {
+			return assert.ObjectsAreEqual([]string{biz_guide_status.ON_HOLD}, s.Status) && s.OperatorID == 42 &&
+				s.Limit == 1 && s.Sort == biz_guide_search.SORT_UPDATED_AT && s.Desc
+		})
+		mockGuideProvider.On("SearchGuides", mock.Anything, search).
+			Return(model.GuidePage{Guides: guides, Next: &model.GuideCursor{ID: 2}}, nil).Once()
+		mockGuideProvider.On("CountGuides", mock.Anything, search).Return(130, nil).Once()
+
+		req := newOperatorRequest(http.MethodGet, "/operator/guides?status=onHold&mine=true&sort=updatedAt&order=desc&limit=1", nil, 42)
+
+		resp := GetOperatorGuide(req)
+		data := resp.Data.(GetOperatorGuideOutput)
+		assert.Len(t, data.OperatorGuides, 1)
+		assert.Equal(t, 130, data.Total)
+		assert.Equal(t, biz_guide_search.EncodeCursor(model.GuideSearch{Status: []string{biz_guide_status.ON_HOLD}, OperatorID: 42,
+			Sort: biz_guide_search.SORT_UPDATED_AT, Desc: true}, model.GuideCursor{ID: 2}), data.NextCursor)
+		assert.Equal(t, http.StatusOK, resp.HttpStatus)
+		mockGuideProvider.AssertExpectations(t)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+		guide_provider.Set(mockGuideProvider)
+		cursor := model.GuideCursor{Value: time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC), ID: 2}
+		guides := []model.Guide{{ID: 3, Status: biz_guide_status.ON_HOLD, Operator: model.Operator{ID: 42}}}
+		search := mock.MatchedBy(func(s model.GuideSearch) bool {
+			return s.Cursor != nil && s.Cursor.ID == cursor.ID && s.Cursor.Value.Equal(cursor.Value)
+		})
+		mockGuideProvider.On("SearchGuides", mock.Anything, search).Return(model.GuidePage{Guides: guides}, nil).Once()
+		// the guides of the previous pages are counted too
+		mockGuideProvider.On("CountGuides", mock.Anything, search).Return(101, nil).Once()
+
+		feed := model.GuideSearch{Status: biz_guide_status.GetOperatorStatus(), Sort: biz_guide_search.SORT_CREATED_AT}
+		req := newOperatorRequest(http.MethodGet, "/operator/guides?cursor="+biz_guide_search.EncodeCursor(feed, cursor), nil, 42)
+
+		resp := GetOperatorGuide(req)
+		data := resp.Data.(GetOperatorGuideOutput)
+		assert.Len(t, data.OperatorGuides, 1)
+		assert.Equal(t, 101, data.Total)
+		assert.Empty(t, data.NextCursor)
 		assert.Equal(t, http.StatusOK, resp.HttpStatus)
 		mockGuideProvider.AssertExpectations(t)
 	})
 
+	t.Run("invalid filter", func(t *testing.T) {
+		mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+		guide_provider.Set(mockGuideProvider)
+		req := newOperatorRequest(http.MethodGet, "/operator/guides?status=delivered", nil, 42)
+
+		resp := GetOperatorGuide(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.HttpStatus)
+		assert.Equal(t, i18n.MsgSearchInvalid, resp.Error.Code)
+		mockGuideProvider.AssertExpectations(t)
+	})
+
 	t.Run("invalid operator ID", func(t *testing.T) {
 		req := newOperatorRequest(http.MethodGet, "/guide/operator", nil, nil)
 
@@ -246,8 +383,24 @@ func TestGetOperatorGuide(t *testing.T) {
 		guide_provider.Set(mockGuideProvider)
 		t.Cleanup(func() { guide_provider.Set(nil) })
 
-		mockGuideProvider.On("GetGuidesByStatus", mock.Anything, mock.Anything).
-			Return([]model.Guide{}, errors.New("db error")).Once()
+		mockGuideProvider.On("SearchGuides", mock.Anything, mock.Anything).
+			Return(model.GuidePage{}, errors.New("db error")).Once()
+
+		req := newOperatorRequest(http.MethodGet, "/guide/operator", nil, 42)
+
+		resp := GetOperatorGuide(req)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.HttpStatus)
+	})
+
+	t.Run("error counting guides", func(t *testing.T) {
+		mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+		guide_provider.Set(mockGuideProvider)
+		t.Cleanup(func() { guide_provider.Set(nil) })
+
+		mockGuideProvider.On("SearchGuides", mock.Anything, mock.Anything).
+			Return(model.GuidePage{Guides: []model.Guide{{ID: 2}}, Next: &model.GuideCursor{ID: 2}}, nil).Once()
+		mockGuideProvider.On("CountGuides", mock.Anything, mock.Anything).Return(0, errors.New("db error")).Once()
 
 		req := newOperatorRequest(http.MethodGet, "/guide/operator", nil, 42)
 
@@ -257,8 +410,39 @@ func TestGetOperatorGuide(t *testing.T) {
 	})
 }
 
+func TestParseOperatorGuideFeed(t *testing.T) {
+	search, err := parseOperatorGuideFeed(map[string][]string{"payment": {biz_config.PAID_SHIPPING}, "mine": {"false"}}, 42)
+	assert.NoError(t, err)
+	assert.Equal(t, model.GuideSearch{
+		Status:  biz_guide_status.GetOperatorStatus(),
+		Payment: biz_config.PAID_SHIPPING,
+		Sort:    biz_guide_search.SORT_CREATED_AT,
+		Limit:   biz_guide_search.MAX_LIMIT,
+	}, search)
+
+	invalid := []map[string][]string{
+		{"status": {biz_guide_status.DELIVERED}},
+		{"mine": {"yes"}},
+		{"payment": {"X"}},
+		{"sort": {"recipient"}},
+		{"limit": {"1000"}},
+		{"cursor": {"not a cursor"}},
+	}
+	for _, query := range invalid {
+		_, err := parseOperatorGuideFeed(query, 42)
+		assert.Error(t, err, query)
+	}
+}
+
+// newOwnerRequest builds a guide owner change request of an operator with role
+func newOwnerRequest(path, guideId string, body []byte, operatorId any, role string) *http.Request {
+	req := newGuideRequest(http.MethodPost, path, guideId, body, operatorId)
+	return req.WithContext(context.WithValue(req.Context(), middleware.OperatorRoleKey, role))
+}
+
 func TestAssignGuideToOperator(t *testing.T) {
 	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
 
 	t.Run("success", func(t *testing.T) {
 		mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
@@ -266,10 +450,12 @@ func TestAssignGuideToOperator(t *testing.T) {
 		mockPubSub := new(mock_pubsub.MockPubSub)
 		pubsub.Set(mockPubSub)
 
-		mockGuideProvider.On("UpdateGuide", mock.Anything, mock.Anything).
-			Return(nil).Once()
+		mockGuideProvider.On("GetGuideById", mock.Anything, 123).
+			Return(model.Guide{ID: 123, Operator: model.Operator{ID: biz_operator.OPERATOR_SYSTEM}}, nil).Once()
+		mockGuideProvider.On("AssignGuide", mock.Anything, 123, biz_operator.OPERATOR_SYSTEM, 42).
+			Return(true, nil).Once()
 
-		mockPubSub.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockPubSub.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"123"}`).Return(nil)
 
 		req := newGuideRequest(http.MethodPost, "/guide/assign/123", "123", nil, 42)
 		w := httptest.NewRecorder()
@@ -280,6 +466,56 @@ func TestAssignGuideToOperator(t *testing.T) {
 		mockGuideProvider.AssertExpectations(t)
 	})
 
+	t.Run("forced by a supervisor", func(t *testing.T) {
+		mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+		guide_provider.Set(mockGuideProvider)
+		mockPubSub := new(mock_pubsub.MockPubSub)
+		pubsub.Set(mockPubSub)
+
+		mockGuideProvider.On("GetGuideById", mock.Anything, 123).
+			Return(model.Guide{ID: 123, Operator: model.Operator{ID: 7}}, nil).Once()
+		mockGuideProvider.On("AssignGuide", mock.Anything, 123, 7, 42).Return(true, nil).Once()
+		mockPubSub.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"123"}`).Return(nil)
+
+		req := newOwnerRequest("/guide/assign/123?force=true", "123", nil, 42, biz_operator.ROLE_SUPERVISOR)
+		w := httptest.NewRecorder()
+
+		AssignGuideToOperator().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockGuideProvider.AssertExpectations(t)
+	})
+
+	t.Run("force not allowed", func(t *testing.T) {
+		req := newOwnerRequest("/guide/assign/123?force=true", "123", nil, 42, biz_operator.ROLE_OPERATOR)
+		w := httptest.NewRecorder()
+
+		AssignGuideToOperator().ServeHTTP(w, req)
+
+		assertJSONErrorResponse(t, req, w, http.StatusForbidden, i18n.MsgOperatorForbidden)
+	})
+
+	t.Run("attended by another operator", func(t *testing.T) {
+		mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+		guide_provider.Set(mockGuideProvider)
+		mockOperatorProvider := new(mock_operator_provider.MockOperatorProvider)
+		operator_provider.Set(mockOperatorProvider)
+
+		mockGuideProvider.On("GetGuideById", mock.Anything, 123).
+			Return(model.Guide{ID: 123, Operator: model.Operator{ID: 7}}, nil).Once()
+		mockOperatorProvider.On("GetOperatorById", mock.Anything, 7).
+			Return(model.Operator{ID: 7, Enabled: true, Availability: biz_operator.AVAILABILITY_AVAILABLE}, nil).Once()
+
+		req := newGuideRequest(http.MethodPost, "/guide/assign/123", "123", nil, 42)
+		w := httptest.NewRecorder()
+
+		AssignGuideToOperator().ServeHTTP(w, req)
+
+		assertJSONErrorResponse(t, req, w, http.StatusConflict, i18n.MsgGuideAssignedToOther)
+		mockGuideProvider.AssertExpectations(t)
+		mockOperatorProvider.AssertExpectations(t)
+	})
+
 	t.Run("missing guide ID", func(t *testing.T) {
 		req := newGuideRequest(http.MethodPost, "/guide/assign/", "", nil, 42)
 		w := httptest.NewRecorder()
@@ -312,8 +548,10 @@ func TestAssignGuideToOperator(t *testing.T) {
 		guide_provider.Set(mockGuideProvider)
 		t.Cleanup(func() { guide_provider.Set(nil) })
 
-		mockGuideProvider.On("UpdateGuide", mock.Anything, mock.Anything).
-			Return(errors.New("db error")).Once()
+		mockGuideProvider.On("GetGuideById", mock.Anything, 123).
+			Return(model.Guide{ID: 123, Operator: model.Operator{ID: biz_operator.OPERATOR_SYSTEM}}, nil).Once()
+		mockGuideProvider.On("AssignGuide", mock.Anything, 123, biz_operator.OPERATOR_SYSTEM, 42).
+			Return(false, errors.New("db error")).Once()
 
 		req := newGuideRequest(http.MethodPost, "/guide/assign/123", "123", nil, 42)
 		w := httptest.NewRecorder()
@@ -325,6 +563,162 @@ func TestAssignGuideToOperator(t *testing.T) {
 	})
 }
 
+func TestReleaseGuide(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+
+	tests := []struct {
+		name           string
+		guideId        string
+		query          string
+		operatorId     any
+		role           string
+		owner          int
+		expectMove     bool
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "owner releases", guideId: "123", operatorId: 42, owner: 42, expectMove: true, expectedStatus: http.StatusOK},
+		{name: "supervisor releases", guideId: "123", query: "?force=true", operatorId: 42, role: biz_operator.ROLE_SUPERVISOR,
+			owner: 7, expectMove: true, expectedStatus: http.StatusOK},
+		{name: "not the owner", guideId: "123", operatorId: 42, owner: 7, expectedStatus: http.StatusForbidden,
+			expectedMsg: i18n.MsgGuideNotOwned},
+		{name: "guide not found", guideId: "123", operatorId: 42, expectedStatus: http.StatusNotFound, expectedMsg: i18n.MsgGuideNotFound},
+		{name: "force not allowed", guideId: "123", query: "?force=true", operatorId: 42, role: biz_operator.ROLE_OPERATOR,
+			expectedStatus: http.StatusForbidden, expectedMsg: i18n.MsgOperatorForbidden},
+		{name: "missing operator", guideId: "123", expectedStatus: http.StatusUnauthorized, expectedMsg: i18n.MsgOperatorInvalid},
+		{name: "invalid guide ID", guideId: "abc", operatorId: 42, expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgGuideInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuideProvider)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			if tt.expectedMsg != i18n.MsgOperatorForbidden && tt.operatorId != nil && tt.guideId == "123" {
+				guide := model.Guide{}
+				if tt.owner != 0 {
+					guide = model.Guide{ID: 123, Operator: model.Operator{ID: tt.owner}}
+				}
+				mockGuideProvider.On("GetGuideById", mock.Anything, 123).Return(guide, nil).Once()
+			}
+			if tt.expectMove {
+				mockGuideProvider.On("AssignGuide", mock.Anything, 123, tt.owner, biz_operator.OPERATOR_SYSTEM).Return(true, nil).Once()
+				mockPubSub.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"123"}`).Return(nil).Once()
+			}
+
+			req := newOwnerRequest("/guide/"+tt.guideId+"/release"+tt.query, tt.guideId, nil, tt.operatorId, tt.role)
+			w := httptest.NewRecorder()
+			ReleaseGuide().ServeHTTP(w, req)
+
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, http.StatusOK, w.Code)
+			} else {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			}
+			mockGuideProvider.AssertExpectations(t)
+			mockPubSub.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferGuide(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+	active := model.Operator{ID: 7, Enabled: true, Availability: biz_operator.AVAILABILITY_AVAILABLE}
+
+	tests := []struct {
+		name           string
+		guideId        string
+		query          string
+		body           string
+		operatorId     any
+		role           string
+		setupMocks     func(*mock_guide_provider.MockGuideProvider, *mock_operator_provider.MockOperatorProvider, *mock_pubsub.MockPubSub)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name: "owner transfers", guideId: "123", body: `{"operatorId":7}`, operatorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, o *mock_operator_provider.MockOperatorProvider, p *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(model.Guide{ID: 123, Operator: model.Operator{ID: 42}}, nil).Once()
+				o.On("GetOperatorById", mock.Anything, 7).Return(active, nil).Once()
+				g.On("AssignGuide", mock.Anything, 123, 42, 7).Return(true, nil).Once()
+				p.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"123"}`).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "target offline", guideId: "123", body: `{"operatorId":7}`, operatorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, o *mock_operator_provider.MockOperatorProvider, _ *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(model.Guide{ID: 123, Operator: model.Operator{ID: 42}}, nil).Once()
+				o.On("GetOperatorById", mock.Anything, 7).Return(model.Operator{ID: 7, Enabled: true,
+					Availability: biz_operator.AVAILABILITY_OFFLINE}, nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgOperatorInvalid,
+		},
+		{
+			name: "changed in the meantime", guideId: "123", body: `{"operatorId":7}`, operatorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, o *mock_operator_provider.MockOperatorProvider, _ *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(model.Guide{ID: 123, Operator: model.Operator{ID: 42}}, nil).Once()
+				o.On("GetOperatorById", mock.Anything, 7).Return(active, nil).Once()
+				g.On("AssignGuide", mock.Anything, 123, 42, 7).Return(false, nil).Once()
+			},
+			expectedStatus: http.StatusConflict, expectedMsg: i18n.MsgGuideAssignedToOther,
+		},
+		{
+			name: "invalid body", guideId: "123", body: `{`, operatorId: 42,
+			setupMocks: func(*mock_guide_provider.MockGuideProvider, *mock_operator_provider.MockOperatorProvider, *mock_pubsub.MockPubSub) {
+			},
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgBadRequest,
+		},
+		{
+			name: "force not allowed", guideId: "123", query: "?force=true", body: `{"operatorId":7}`, operatorId: 42,
+			setupMocks: func(*mock_guide_provider.MockGuideProvider, *mock_operator_provider.MockOperatorProvider, *mock_pubsub.MockPubSub) {
+			},
+			expectedStatus: http.StatusForbidden, expectedMsg: i18n.MsgOperatorForbidden,
+		},
+		{
+			name: "missing operator", guideId: "123", body: `{"operatorId":7}`,
+			setupMocks: func(*mock_guide_provider.MockGuideProvider, *mock_operator_provider.MockOperatorProvider, *mock_pubsub.MockPubSub) {
+			},
+			expectedStatus: http.StatusUnauthorized, expectedMsg: i18n.MsgOperatorInvalid,
+		},
+		{
+			name: "invalid guide ID", guideId: "abc", body: `{"operatorId":7}`, operatorId: 42,
+			setupMocks: func(*mock_guide_provider.MockGuideProvider, *mock_operator_provider.MockOperatorProvider, *mock_pubsub.MockPubSub) {
+			},
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgGuideInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuideProvider)
+			mockOperatorProvider := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperatorProvider)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			tt.setupMocks(mockGuideProvider, mockOperatorProvider, mockPubSub)
+
+			req := newOwnerRequest("/guide/"+tt.guideId+"/transfer"+tt.query, tt.guideId, []byte(tt.body), tt.operatorId, tt.role)
+			w := httptest.NewRecorder()
+			TransferGuide().ServeHTTP(w, req)
+
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, http.StatusOK, w.Code)
+			} else {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			}
+			mockGuideProvider.AssertExpectations(t)
+			mockOperatorProvider.AssertExpectations(t)
+			mockPubSub.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetGuideStatusOptions(t *testing.T) {
 	testutil.InjectNoOpLogger()
 
@@ -466,6 +860,7 @@ func TestUpdateGuideStatus(t *testing.T) {
 	})
 
 	t.Run("failed DB update", func(t *testing.T) {
+		mockGuideProvider.On("GetGuideById", mock.Anything, 123).Return(model.Guide{}, errors.New("db error")).Once()
 		mockGuideProvider.On("UpdateGuide", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
 
 		body, _ := json.Marshal(UpdateGuideStatusInput{Status: "DELIVERED"})
@@ -478,7 +873,18 @@ func TestUpdateGuideStatus(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
+		mockGuideProvider.On("GetGuideById", mock.Anything, 123).Return(model.Guide{ID: 123, Status: "IN_PROCESS"}, nil).Once()
 		mockGuideProvider.On("UpdateGuide", mock.Anything, mock.Anything).Return(nil).Once()
+		mockPubSub := new(mock_pubsub.MockPubSub)
+		pubsub.Set(mockPubSub)
+		mockPubSub.On("Publish", mock.Anything, global.GuideStatusChangeChannel, `{"guide_id":"123"}`).Return(nil).Once()
+		mockAuditProvider := new(mock_audit_provider.MockAuditProvider)
+		audit_provider.Set(mockAuditProvider)
+		defer audit_provider.Set(nil)
+		mockAuditProvider.On("AppendAuditEntry", mock.Anything, mock.MatchedBy(func(entry model.AuditEntry) bool {
+			return entry.Actor == "operator:42" && entry.Action == biz_audit.GUIDE_STATUS && entry.TargetID == "123" &&
+				string(entry.Before) == `{"status":"IN_PROCESS"}` && string(entry.After) == `{"status":"DELIVERED"}`
+		})).Return(model.AuditEntry{}, nil).Once()
 
 		body, _ := json.Marshal(UpdateGuideStatusInput{Status: "DELIVERED"})
 		w := httptest.NewRecorder()
@@ -487,5 +893,109 @@ func TestUpdateGuideStatus(t *testing.T) {
 		UpdateGuideStatus().ServeHTTP(w, req)
 		assert.Equal(t, http.StatusOK, w.Code)
 		mockGuideProvider.AssertExpectations(t)
+		mockAuditProvider.AssertExpectations(t)
 	})
 }
+
+func TestSyncGuide(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	viaGuide := model.ViaGuide{ID: "999025862539", Status: "CRR", Sender: "ROLDAN PAULA", Packages: 2}
+
+	tests := []struct {
+		name           string
+		guideId        string
+		setupMocks     func(*mock_guide_provider.MockGuideProvider, *mock_via_guide_provider.MockViaGuideProvider, *mock_pubsub.MockPubSub)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name:    "invalid guide ID",
+			guideId: "abc",
+			setupMocks: func(*mock_guide_provider.MockGuideProvider, *mock_via_guide_provider.MockViaGuideProvider, *mock_pubsub.MockPubSub) {
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    i18n.MsgGuideInvalid,
+		},
+		{
+			name:    "guide not found",
+			guideId: "123",
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, _ *mock_via_guide_provider.MockViaGuideProvider, _ *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(model.Guide{}, nil).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    i18n.MsgGuideNotFound,
+		},
+		{
+			name:    "carrier unavailable",
+			guideId: "123",
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, v *mock_via_guide_provider.MockViaGuideProvider, _ *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(model.Guide{ID: 123, ViaGuideID: viaGuide.ID}, nil).Once()
+				v.On("GetGuide", mock.Anything, viaGuide.ID).Return(model.ViaGuide{}, via_guide_provider.ErrUnavailable).Once()
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedMsg:    i18n.MsgGuideProviderUnavailable,
+		},
+		{
+			name:    "guide not found at carrier",
+			guideId: "123",
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, v *mock_via_guide_provider.MockViaGuideProvider, _ *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(model.Guide{ID: 123, ViaGuideID: viaGuide.ID}, nil).Once()
+				v.On("GetGuide", mock.Anything, viaGuide.ID).Return(model.ViaGuide{}, nil).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    i18n.MsgGuideNotFound,
+		},
+		{
+			name:    "failed DB update",
+			guideId: "123",
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, v *mock_via_guide_provider.MockViaGuideProvider, _ *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(model.Guide{ID: 123, ViaGuideID: viaGuide.ID}, nil).Once()
+				v.On("GetGuide", mock.Anything, viaGuide.ID).Return(viaGuide, nil).Once()
+				g.On("SyncGuide", mock.Anything, 123, viaGuide, mock.Anything).Return(errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    i18n.MsgInternalServerError,
+		},
+		{
+			name:    "success",
+			guideId: "123",
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, v *mock_via_guide_provider.MockViaGuideProvider, p *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(model.Guide{ID: 123, ViaGuideID: viaGuide.ID}, nil).Once()
+				v.On("GetGuide", mock.MatchedBy(via_guide_provider.IsWithoutCache), viaGuide.ID).Return(viaGuide, nil).Once()
+				g.On("SyncGuide", mock.Anything, 123, viaGuide, mock.Anything).Return(nil).Once()
+				p.On("Publish", mock.Anything, global.GuideSyncChannel, `{"guide_id":"123"}`).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuideProvider)
+			mockVia := new(mock_via_guide_provider.MockViaGuideProvider)
+			via_guide_provider.Set(mockVia)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			tt.setupMocks(mockGuideProvider, mockVia, mockPubSub)
+
+			req := newGuideRequest(http.MethodPost, "/guide/"+tt.guideId+"/sync", tt.guideId, nil, 42)
+			w := httptest.NewRecorder()
+
+			SyncGuide().ServeHTTP(w, req)
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp response.Response[SyncGuideOutput]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, viaGuide, resp.Data.ViaSnapshot)
+				assert.False(t, resp.Data.ViaSyncedAt.IsZero())
+			} else {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			}
+			mockGuideProvider.AssertExpectations(t)
+			mockVia.AssertExpectations(t)
+			mockPubSub.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	jwt_key "via/internal/jwt"
+	"via/internal/log"
+
+	jose "github.com/go-jose/go-jose/v4"
+)
+
+// GetJWKS publishes the public keys the operator tokens are verified with, so other services can
+// verify them. The upcoming signing key is published before it signs anything.
+func GetJWKS() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{}}
+		for _, key := range jwt_key.GetKeys() {
+			set.Keys = append(set.Keys, jose.JSONWebKey{
+				Key:       key.Public,
+				KeyID:     key.ID,
+				Algorithm: key.Method.Alg(),
+				Use:       "sig",
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if err := json.NewEncoder(w).Encode(set); err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to write jwks")
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	jwt_key "via/internal/jwt"
+	"via/internal/testutil"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJWKS(t *testing.T) {
+	testutil.InjectMockJWTKey()
+	t.Cleanup(jwt_key.Reset)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	GetJWKS().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var set jose.JSONWebKeySet
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&set))
+	assert.Len(t, set.Keys, 1)
+	key := set.Keys[0]
+	assert.Equal(t, jwt_key.GetKeyID(), key.KeyID)
+	assert.Equal(t, "RS256", key.Algorithm)
+	assert.Equal(t, "sig", key.Use)
+	assert.True(t, key.IsPublic())
+	assert.Equal(t, jwt_key.GetPublicKey(), key.Key)
+}
@@ -4,9 +4,12 @@ import (
 	"math/rand"
 	"net/http"
 	"time"
+	biz_config "via/internal/biz/config"
 	biz_guide_status "via/internal/biz/guide/status"
+	biz_monitor "via/internal/biz/monitor"
 	"via/internal/i18n"
 	"via/internal/log"
+	"via/internal/middleware"
 	"via/internal/model"
 	guide_provider "via/internal/provider/guide"
 	"via/internal/response"
@@ -20,23 +23,30 @@ func init() {
 	rand.New(rand.NewSource(time.Now().UnixNano()))
 }
 
-func GetMonitorEvents(r *http.Request) response.Response[any] {
-	res := response.Response[any]{}
-	monitorEvents := []model.MonitorEvent{}
-	guides, err := guide_provider.Get().GetGuidesByStatus(r.Context(), biz_guide_status.GetMonitorStatus())
-	if err != nil {
-		log.Get().Error(r.Context(), err, "msg", "failed to fetch guide")
-		res.Message = i18n.Get(r, i18n.MsgInternalServerError)
-		res.HttpStatus = http.StatusInternalServerError
+// GetMonitorEvents loads the guides shown on the monitor, identified as the privacy mode of the monitor
+// allows or, when it has none, as the branch default
+func GetMonitorEvents(biz biz_config.BussinessCfg) func(r *http.Request) response.Response[any] {
+	return func(r *http.Request) response.Response[any] {
+		res := response.Response[any]{}
+		monitorEvents := []model.MonitorEvent{}
+		guides, err := guide_provider.Get().GetGuidesByStatus(r.Context(), biz_guide_status.GetMonitorStatus())
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to fetch guide")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			res.HttpStatus = http.StatusInternalServerError
+			return res
+		}
+		privacy := biz.MonitorPrivacy
+		if device, ok := r.Context().Value(middleware.DeviceKey).(model.Device); ok && device.Privacy != "" {
+			privacy = device.Privacy
+		}
+		for _, guide := range guides {
+			event := biz_monitor.NewEvent(guide, privacy)
+			event.Status = model.GetMonitorEventStatusDescriptionByGuideStatus(response.GetLanguage(r), guide.Status)
+			event.Highlight = model.GetHighlightByGuideStatus(guide.Status)
+			monitorEvents = append(monitorEvents, event)
+		}
+		res.Data = GetMonitorEventOutput{monitorEvents}
 		return res
 	}
-	for _, guide := range guides {
-		monitorEvents = append(monitorEvents,
-			model.MonitorEvent{GuideId: guide.ViaGuideID,
-				Recipient: guide.Recipient,
-				Status:    model.GetMonitorEventStatusDescriptionByGuideStatus(response.GetLanguage(r), guide.Status),
-				Highlight: model.GetHighlightByGuideStatus(guide.Status)})
-	}
-	res.Data = GetMonitorEventOutput{monitorEvents}
-	return res
 }
@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	biz_config "via/internal/biz/config"
 	biz_guide_status "via/internal/biz/guide/status"
+	biz_monitor "via/internal/biz/monitor"
 	"via/internal/i18n"
+	"via/internal/middleware"
 	"via/internal/model"
 	guide_provider "via/internal/provider/guide"
 	mock_guide_provider "via/internal/provider/guide/mock"
@@ -18,30 +23,54 @@ import (
 
 func TestGetMonitorEvents(t *testing.T) {
 	testutil.InjectNoOpLogger()
+	biz := biz_config.BussinessCfg{MonitorPrivacy: biz_monitor.PRIVACY_INITIALS}
 
 	t.Run("success", func(t *testing.T) {
-		mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
-		guide_provider.Set(mockGuideProvider)
-		mockGuideProvider.On("GetGuidesByStatus", mock.Anything, biz_guide_status.GetMonitorStatus()).
-			Return([]model.Guide{
-				{
-					ViaGuideID: "V123",
-					Recipient:  "John Doe",
-					Status:     biz_guide_status.ON_HOLD,
-				},
-			}, nil).Twice()
-
-		req := httptest.NewRequest(http.MethodGet, "/monitor/events", nil)
+		tests := []struct {
+			name              string
+			device            *model.Device
+			expectedGuideId   string
+			expectedRecipient string
+		}{
+			{name: "branch default", expectedGuideId: "123456789012", expectedRecipient: "J. D."},
+			{name: "monitor without privacy", device: &model.Device{ID: 4},
+				expectedGuideId: "123456789012", expectedRecipient: "J. D."},
+			{name: "monitor privacy", device: &model.Device{ID: 4, Privacy: biz_monitor.PRIVACY_GUIDE_LAST4},
+				expectedGuideId: "9012"},
+		}
 
-		res := GetMonitorEvents(req)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
+				guide_provider.Set(mockGuideProvider)
+				mockGuideProvider.On("GetGuidesByStatus", mock.Anything, biz_guide_status.GetMonitorStatus()).
+					Return([]model.Guide{
+						{
+							ID:         7,
+							ViaGuideID: "123456789012",
+							Recipient:  "John Doe",
+							Status:     biz_guide_status.ON_HOLD,
+						},
+					}, nil).Once()
 
-		data := res.Data.(GetMonitorEventOutput)
+				req := httptest.NewRequest(http.MethodGet, "/monitor/events", nil)
+				if tt.device != nil {
+					req = req.WithContext(context.WithValue(req.Context(), middleware.DeviceKey, *tt.device))
+				}
 
-		assert.Len(t, data.Events, 1)
-		assert.Equal(t, "V123", data.Events[0].GuideId)
-		assert.Equal(t, "John Doe", data.Events[0].Recipient)
-		assert.NotEmpty(t, data.Events[0].Status)
+				res := GetMonitorEvents(biz)(req)
 
+				data := res.Data.(GetMonitorEventOutput)
+				assert.Len(t, data.Events, 1)
+				assert.Equal(t, 7, data.Events[0].Ticket)
+				assert.Equal(t, tt.expectedGuideId, data.Events[0].GuideId)
+				assert.Equal(t, tt.expectedRecipient, data.Events[0].Recipient)
+				assert.NotEmpty(t, data.Events[0].Status)
+				payload, err := json.Marshal(res)
+				assert.NoError(t, err)
+				assert.NotContains(t, string(payload), "John")
+			})
+		}
 	})
 
 	t.Run("error fetching guides", func(t *testing.T) {
@@ -52,8 +81,8 @@ func TestGetMonitorEvents(t *testing.T) {
 
 		req := httptest.NewRequest(http.MethodGet, "/monitor/events", nil)
 
-		res := GetMonitorEvents(req)
+		res := GetMonitorEvents(biz)(req)
 
-		assert.Equal(t, i18n.Get(req, i18n.MsgInternalServerError), res.Message)
+		assert.Equal(t, i18n.Error(req, i18n.MsgInternalServerError), res.Error)
 	})
 }
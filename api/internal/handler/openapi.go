@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/openapi"
+	"via/internal/response"
+)
+
+const docsStyle = `
+body { font-family: sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: .4rem; text-align: left; vertical-align: top; }
+code { font-size: .95em; }
+.method { font-weight: bold; }
+`
+
+// docsPage lists the operations of the document, rendered by the api itself so the browser does not
+// load any third party script; the document is at openapi.json for the OpenAPI tools
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Via API</title>
+  <style>` + docsStyle + `</style>
+</head>
+<body>
+  <h1>Via API</h1>
+  <p>OpenAPI document: <a href="openapi.yaml">openapi.yaml</a>, <a href="openapi.json">openapi.json</a>. Error codes: <code>docs/errors.md</code>.</p>
+  <table>
+    <tr><th>Method</th><th>Path</th><th>Summary</th><th>Parameters</th><th>Validation error</th></tr>
+    {{- range .}}
+    <tr>
+      <td class="method">{{.Method}}</td>
+      <td><code>{{.Path}}</code></td>
+      <td>{{.Operation.Summary}}</td>
+      <td>{{range .Operation.Parameters}}<code>{{.Name}}</code> ({{.In}}{{if .Required}}, required{{end}}) {{end}}</td>
+      <td>{{if .Operation.ErrorCode}}<code>{{.Operation.ErrorCode}}</code>{{end}}</td>
+    </tr>
+    {{- end}}
+  </table>
+</body>
+</html>
+`
+
+var docsTemplate = template.Must(template.New("docs").Parse(docsPage))
+
+// docsPolicy only lets the page apply its own style
+var docsPolicy = func() string {
+	sum := sha256.Sum256([]byte(docsStyle))
+	return "default-src 'none'; style-src 'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}()
+
+// GetOpenAPIYAML publishes the OpenAPI document of the REST and SSE APIs as written
+func GetOpenAPIYAML() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeDocument(w, r, "application/yaml", openapi.YAML())
+	})
+}
+
+// GetOpenAPIJSON publishes the OpenAPI document of the REST and SSE APIs in JSON
+func GetOpenAPIJSON() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeDocument(w, r, "application/json", openapi.JSON())
+	})
+}
+
+// GetDocs serves the browsable docs of the OpenAPI document
+func GetDocs() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var page bytes.Buffer
+		if err := docsTemplate.Execute(&page, openapi.Get().Endpoints()); err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to render the openapi docs")
+			response.WriteJSON(w, r, response.Response[any]{
+				Error: i18n.Error(r, i18n.MsgInternalServerError),
+			}, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Security-Policy", docsPolicy)
+		writeDocument(w, r, "text/html; charset=utf-8", page.Bytes())
+	})
+}
+
+func writeDocument(w http.ResponseWriter, r *http.Request, contentType string, document []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if _, err := w.Write(document); err != nil {
+		log.Get().Error(r.Context(), err, "msg", "failed to write openapi document")
+	}
+}
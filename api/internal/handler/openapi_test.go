@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"via/internal/openapi"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOpenAPI(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	tests := []struct {
+		name                string
+		handler             http.Handler
+		expectedContentType string
+		expectedBody        []byte
+	}{
+		{name: "yaml", handler: GetOpenAPIYAML(), expectedContentType: "application/yaml", expectedBody: openapi.YAML()},
+		{name: "json", handler: GetOpenAPIJSON(), expectedContentType: "application/json", expectedBody: openapi.JSON()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, w.Body.Bytes())
+		})
+	}
+}
+
+func TestGetDocs(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	req := httptest.NewRequest(http.MethodGet, "/docs", nil)
+	w := httptest.NewRecorder()
+	GetDocs().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, docsPolicy, w.Header().Get("Content-Security-Policy"))
+	body := w.Body.String()
+	assert.Contains(t, body, "<code>/guide-to-withdraw/{viaGuideId}</code>")
+	assert.Contains(t, body, "<code>guide_invalid</code>")
+	assert.NotContains(t, body, "<script")
+	assert.NotContains(t, body, "https://")
+}
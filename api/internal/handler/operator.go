@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	biz_audit "via/internal/biz/audit"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/global"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/model"
+	"via/internal/presence"
+	operator_provider "via/internal/provider/operator"
+	"via/internal/pubsub"
+	response "via/internal/response"
+)
+
+type SetOperatorAvailabilityInput struct {
+	Availability string `json:"availability"`
+}
+
+type SetOperatorAvailabilityOutput struct {
+	Availability string `json:"availability"`
+}
+
+// SetOperatorAvailability changes the availability of the logged operator, only available
+// operators are given new guides
+func SetOperatorAvailability() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*SetOperatorAvailabilityOutput]{}
+		operatorId := 0
+		if operatorId = isValidOperatorId(w, r); operatorId == 0 {
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "operator_id", operatorId)
+
+		var input SetOperatorAvailabilityInput
+		if ok := getJsonBody(w, r, &input); !ok {
+			return
+		}
+		if err := operator_provider.Get().SetOperatorAvailability(r.Context(), operatorId, input.Availability); err != nil {
+			logger.Error(r.Context(), err, "msg", "failed updating operator availability")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		recordAudit(r, biz_audit.OPERATOR_AVAILABILITY, biz_audit.TARGET_OPERATOR, operatorId, nil,
+			map[string]string{"availability": input.Availability})
+		logger.Info(r.Context(), "msg", "operator availability updated", "availability", input.Availability)
+		if input.Availability == biz_operator.AVAILABILITY_AVAILABLE {
+			// the assigner gives the operator the guides nobody could take
+			err := pubsub.Get().Publish(r.Context(), global.OperatorAvailableChannel, fmt.Sprintf("{\"operator_id\":\"%d\"}", operatorId))
+			if err != nil {
+				logger.Error(r.Context(), err, "msg", "unable to publish event", "channel", global.OperatorAvailableChannel)
+			}
+		}
+		res.Data = &SetOperatorAvailabilityOutput{Availability: input.Availability}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+type GetOnlineOperatorsOutput struct {
+	Operators []model.Operator `json:"operators"`
+}
+
+// GetOnlineOperators returns the operators with an open guides feed
+func GetOnlineOperators() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*GetOnlineOperatorsOutput]{}
+		operators, err := operator_provider.Get().GetOperators(r.Context())
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to get operators")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		ids := []int{}
+		for _, operator := range operators {
+			ids = append(ids, operator.ID)
+		}
+		online := getOnlineOperators(r, ids)
+		data := &GetOnlineOperatorsOutput{Operators: []model.Operator{}}
+		for _, operator := range operators {
+			if online[operator.ID] {
+				data.Operators = append(data.Operators, operator)
+			}
+		}
+		res.Data = data
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+// getOnlineOperators reports which operators are online, presence is informative so
+// a failure leaves every operator offline
+func getOnlineOperators(r *http.Request, operatorIds []int) map[int]bool {
+	if presence.Get() == nil {
+		return map[int]bool{}
+	}
+	online, err := presence.Get().Online(r.Context(), operatorIds)
+	if err != nil {
+		log.Get().Error(r.Context(), err, "msg", "failed to get operators presence")
+		return map[int]bool{}
+	}
+	return online
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"via/internal/ds"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/global"
+	"via/internal/i18n"
+	"via/internal/model"
+	"via/internal/presence"
+	operator_provider "via/internal/provider/operator"
+	mock_operator_provider "via/internal/provider/operator/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/response"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetOperatorAvailability(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+
+	tests := []struct {
+		name           string
+		body           string
+		operatorId     any
+		updateErr      error
+		expectUpdate   bool
+		expectPublish  bool
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "missing operator", body: `{"availability":"available"}`, expectedStatus: http.StatusUnauthorized,
+			expectedMsg: i18n.MsgOperatorInvalid},
+		{name: "invalid body", body: `{`, operatorId: 42, expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgBadRequest},
+		{name: "failed update", body: `{"availability":"onBreak"}`, operatorId: 42, updateErr: errors.New("db error"),
+			expectUpdate: true, expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+		{name: "success", body: `{"availability":"onBreak"}`, operatorId: 42, expectUpdate: true, expectedStatus: http.StatusOK},
+		{name: "available announced", body: `{"availability":"available"}`, operatorId: 42, expectUpdate: true,
+			expectPublish: true, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			var input SetOperatorAvailabilityInput
+			_ = json.Unmarshal([]byte(tt.body), &input)
+			if tt.expectUpdate {
+				mockOperator.On("SetOperatorAvailability", mock.Anything, 42, input.Availability).
+					Return(tt.updateErr).Once()
+			}
+			if tt.expectPublish {
+				mockPubSub.On("Publish", mock.Anything, global.OperatorAvailableChannel, `{"operator_id":"42"}`).
+					Return(errors.New("redis down")).Once()
+			}
+
+			req := newOperatorRequest(http.MethodPut, "/operator/availability", []byte(tt.body), tt.operatorId)
+			w := httptest.NewRecorder()
+			SetOperatorAvailability().ServeHTTP(w, req)
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp response.Response[SetOperatorAvailabilityOutput]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, input.Availability, resp.Data.Availability)
+			} else {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			}
+			mockOperator.AssertExpectations(t)
+			mockPubSub.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetOnlineOperators(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	t.Cleanup(func() { presence.Set(nil) })
+	operators := []model.Operator{{ID: 2}, {ID: 3}}
+
+	tests := []struct {
+		name           string
+		tracked        bool
+		operatorsErr   error
+		presenceErr    error
+		expected       []model.Operator
+		expectedStatus int
+	}{
+		{name: "success", tracked: true, expected: []model.Operator{{ID: 2}}, expectedStatus: http.StatusOK},
+		{name: "presence not tracked", expected: []model.Operator{}, expectedStatus: http.StatusOK},
+		{name: "error getting presence", tracked: true, presenceErr: errors.New("ds error"), expected: []model.Operator{},
+			expectedStatus: http.StatusOK},
+		{name: "error getting operators", operatorsErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockOperator.On("GetOperators", mock.Anything).Return(operators, tt.operatorsErr).Once()
+			mockDS := new(mock_ds.MockDS)
+			ds.Set(mockDS)
+			presence.Set(nil)
+			if tt.tracked {
+				presence.Set(presence.New(presence.PresenceCfg{}))
+				mockDS.On("SMembers", mock.Anything, "presence:operator:2:connections").Return([]string{"a"}, tt.presenceErr).Once()
+				if tt.presenceErr == nil {
+					mockDS.On("SMembers", mock.Anything, "presence:operator:3:connections").Return([]string{}, nil).Once()
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/operators/online", nil)
+			w := httptest.NewRecorder()
+			GetOnlineOperators().ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, i18n.MsgInternalServerError)
+			} else {
+				var resp response.Response[GetOnlineOperatorsOutput]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, tt.expected, resp.Data.Operators)
+			}
+			mockOperator.AssertExpectations(t)
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// methods the routes may have, checked for the Allow header
+var routeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// NotFound answers the requests to a path no route has
+func NotFound() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Get().Warn(r.Context(), "msg", "route not found", "method", r.Method, "path", r.URL.Path)
+		response.WriteJSON(w, r, response.Response[any]{Error: i18n.Error(r, i18n.MsgRouteNotFound)}, http.StatusNotFound)
+	})
+}
+
+// MethodNotAllowed answers the requests with a method the route does not have, the Allow header
+// lists the ones it has
+func MethodNotAllowed() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.Routes != nil {
+			for _, method := range routeMethods {
+				if rctx.Routes.Match(chi.NewRouteContext(), method, r.URL.Path) {
+					w.Header().Add("Allow", method)
+				}
+			}
+		}
+		log.Get().Warn(r.Context(), "msg", "method not allowed", "method", r.Method, "path", r.URL.Path)
+		response.WriteJSON(w, r, response.Response[any]{Error: i18n.Error(r, i18n.MsgMethodNotAllowed)},
+			http.StatusMethodNotAllowed)
+	})
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"via/internal/i18n"
+	"via/internal/response"
+	"via/internal/testutil"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteErrors(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	r := chi.NewRouter()
+	r.NotFound(NotFound().ServeHTTP)
+	r.MethodNotAllowed(MethodNotAllowed().ServeHTTP)
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	r.Get("/guide/{guideId}/status-options", ok)
+	r.Put("/guide/{guideId}/status", ok)
+	r.Get("/operator/sessions", ok)
+	r.Delete("/operator/sessions", ok)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedMsg    string
+		expectedAllow  []string
+	}{
+		{name: "unknown path", method: http.MethodGet, path: "/guides", expectedStatus: http.StatusNotFound,
+			expectedMsg: i18n.MsgRouteNotFound},
+		{name: "method of another route", method: http.MethodGet, path: "/guide/1/status",
+			expectedStatus: http.StatusMethodNotAllowed, expectedMsg: i18n.MsgMethodNotAllowed,
+			expectedAllow: []string{http.MethodPut}},
+		{name: "several methods allowed", method: http.MethodPost, path: "/operator/sessions",
+			expectedStatus: http.StatusMethodNotAllowed, expectedMsg: i18n.MsgMethodNotAllowed,
+			expectedAllow: []string{http.MethodGet, http.MethodDelete}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			assert.Equal(t, tt.expectedAllow, w.Header().Values("Allow"))
+		})
+	}
+}
+
+func TestIdValidationDetails(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	tests := []struct {
+		name            string
+		validate        func(w http.ResponseWriter, r *http.Request)
+		expectedMsg     string
+		expectedDetails []response.FieldError
+	}{
+		{name: "missing guide id", validate: func(w http.ResponseWriter, r *http.Request) { isValidGuideId(w, r, "") },
+			expectedMsg: i18n.MsgGuideRequired, expectedDetails: []response.FieldError{{Field: "guideId", Code: response.FIELD_REQUIRED}}},
+		{name: "invalid guide id", validate: func(w http.ResponseWriter, r *http.Request) { isValidGuideId(w, r, "x") },
+			expectedMsg: i18n.MsgGuideInvalid, expectedDetails: []response.FieldError{{Field: "guideId", Code: response.FIELD_INVALID}}},
+		{name: "missing operator id", validate: func(w http.ResponseWriter, r *http.Request) { isValidOperatorId(w, r) },
+			expectedMsg: i18n.MsgOperatorInvalid, expectedDetails: []response.FieldError{{Field: "operatorId", Code: response.FIELD_REQUIRED}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/guide", nil)
+			w := httptest.NewRecorder()
+
+			tt.validate(w, req)
+
+			var resp response.Response[any]
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			if assert.NotNil(t, resp.Error) {
+				assert.Equal(t, tt.expectedMsg, resp.Error.Code)
+				assert.Equal(t, tt.expectedDetails, resp.Error.Details)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"via/internal/auth"
+	biz_audit "via/internal/biz/audit"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/middleware"
+	"via/internal/model"
+	response "via/internal/response"
+	"via/internal/session"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GetSessionsOutput struct {
+	Sessions []model.Session `json:"sessions"`
+}
+
+// GetSessions returns the active sessions of the logged operator, or of the operatorId one for admins
+func GetSessions() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*GetSessionsOutput]{}
+		operatorId := 0
+		if operatorId = getSessionOwner(w, r); operatorId == 0 {
+			return
+		}
+		sessions, err := session.Get().List(r.Context(), operatorId)
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to get sessions", "operator_id", operatorId)
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		current, _ := r.Context().Value(middleware.SessionIDKey).(string)
+		for i := range sessions {
+			sessions[i].Current = sessions[i].ID == current
+		}
+		res.Data = &GetSessionsOutput{Sessions: sessions}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+type RevokeSessionsOutput struct {
+	Revoked int `json:"revoked"`
+}
+
+// RevokeSession ends one session of the logged operator, or of the operatorId one for admins
+func RevokeSession(cfg auth.OAuthConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*RevokeSessionsOutput]{}
+		operatorId := 0
+		if operatorId = getSessionOwner(w, r); operatorId == 0 {
+			return
+		}
+		sessionId := chi.URLParam(r, "sessionId")
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "operator_id", operatorId, "session_id", sessionId)
+
+		s, found, err := session.Get().Get(r.Context(), sessionId)
+		if err == nil && found && s.OperatorID == operatorId {
+			err = session.Get().Revoke(r.Context(), sessionId)
+		}
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "failed to revoke session")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		// another operator session is reported missing, not forbidden
+		if !found || s.OperatorID != operatorId {
+			logger.Warn(r.Context(), "msg", "session not found")
+			res.Error = i18n.Error(r, i18n.MsgSessionNotFound)
+			response.WriteJSON(w, r, res, http.StatusNotFound)
+			return
+		}
+		clearCurrentSession(w, r, cfg, sessionId)
+		recordAudit(r, biz_audit.SESSION_REVOKE, biz_audit.TARGET_SESSION, sessionId, nil,
+			map[string]int{"operatorId": operatorId})
+		logger.Info(r.Context(), "msg", "session revoked")
+		res.Data = &RevokeSessionsOutput{Revoked: 1}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+// RevokeSessions logs the operator out everywhere: the logged one, or the operatorId one for admins
+func RevokeSessions(cfg auth.OAuthConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*RevokeSessionsOutput]{}
+		operatorId := 0
+		if operatorId = getSessionOwner(w, r); operatorId == 0 {
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "operator_id", operatorId)
+
+		revoked, err := session.Get().RevokeAll(r.Context(), operatorId)
+		if err != nil {
+			logger.Error(r.Context(), err, "msg", "failed to revoke sessions")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		if loggedId, _ := r.Context().Value(middleware.OperatorIDKey).(int); loggedId == operatorId {
+			current, _ := r.Context().Value(middleware.SessionIDKey).(string)
+			clearCurrentSession(w, r, cfg, current)
+		}
+		recordAudit(r, biz_audit.SESSION_REVOKE, biz_audit.TARGET_OPERATOR, operatorId, nil,
+			map[string]int{"revoked": revoked})
+		logger.Info(r.Context(), "msg", "sessions revoked", "revoked", revoked)
+		res.Data = &RevokeSessionsOutput{Revoked: revoked}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+// getSessionOwner returns the operator whose sessions are managed, the operatorId path parameter
+// is only routed for admins
+func getSessionOwner(w http.ResponseWriter, r *http.Request) int {
+	param := chi.URLParam(r, "operatorId")
+	if param == "" {
+		return isValidOperatorId(w, r)
+	}
+	operatorId, err := strconv.Atoi(param)
+	if err != nil || operatorId <= 0 {
+		log.Get().Warn(r.Context(), "msg", "invalid operator id", "operator_id", param)
+		response.WriteJSON(w, r, response.Response[any]{Error: i18n.Error(r, i18n.MsgOperatorInvalid,
+			response.FieldError{Field: "operatorId", Code: response.FIELD_INVALID})}, http.StatusBadRequest)
+		return 0
+	}
+	return operatorId
+}
+
+// clearCurrentSession removes the cookies when the revoked session is the one of the request
+func clearCurrentSession(w http.ResponseWriter, r *http.Request, cfg auth.OAuthConfig, sessionId string) {
+	if current, _ := r.Context().Value(middleware.SessionIDKey).(string); current != sessionId {
+		return
+	}
+	auth.DelAuthToken(w, cfg)
+	auth.DelRefreshToken(w, cfg)
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"via/internal/auth"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/i18n"
+	"via/internal/middleware"
+	"via/internal/model"
+	response "via/internal/response"
+	"via/internal/session"
+	"via/internal/testutil"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newSessionRequest is a request of the logged operator 5 with session s1, the path parameters are
+// the ones routed for admins
+func newSessionRequest(method string, params map[string]string, operatorId any) *http.Request {
+	req := httptest.NewRequest(method, "/operator/sessions", nil)
+	rctx := chi.NewRouteContext()
+	for key, value := range params {
+		rctx.URLParams.Add(key, value)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	if operatorId != nil {
+		ctx = context.WithValue(ctx, middleware.OperatorIDKey, operatorId)
+	}
+	return req.WithContext(context.WithValue(ctx, middleware.SessionIDKey, "s1"))
+}
+
+func TestGetSessions(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	tests := []struct {
+		name           string
+		params         map[string]string
+		operatorId     any
+		indexKey       string
+		indexErr       error
+		expected       []model.Session
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "own sessions", operatorId: 5, indexKey: "sessions:operator:5:ids",
+			expected:       []model.Session{{ID: "s1", OperatorID: 5, Current: true}, {ID: "s2", OperatorID: 5}},
+			expectedStatus: http.StatusOK},
+		{name: "sessions of another operator", params: map[string]string{"operatorId": "7"}, operatorId: 5,
+			indexKey:       "sessions:operator:7:ids",
+			expected:       []model.Session{{ID: "s1", OperatorID: 5, Current: true}, {ID: "s2", OperatorID: 5}},
+			expectedStatus: http.StatusOK},
+		{name: "invalid operator", params: map[string]string{"operatorId": "x"}, operatorId: 5,
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgOperatorInvalid},
+		{name: "missing operator", expectedStatus: http.StatusUnauthorized, expectedMsg: i18n.MsgOperatorInvalid},
+		{name: "error getting sessions", operatorId: 5, indexKey: "sessions:operator:5:ids", indexErr: errors.New("ds error"),
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			session.Set(session.New(mockDS, 7200))
+			if tt.indexKey != "" {
+				mockDS.On("SMembers", mock.Anything, tt.indexKey).Return([]string{"s1", "s2"}, tt.indexErr).Once()
+			}
+			mockDS.On("Get", mock.Anything, "session:s1").Return(true, `{"id":"s1","operatorId":5}`, nil)
+			mockDS.On("Get", mock.Anything, "session:s2").Return(true, `{"id":"s2","operatorId":5}`, nil)
+
+			req := newSessionRequest(http.MethodGet, tt.params, tt.operatorId)
+			w := httptest.NewRecorder()
+			GetSessions().ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+				return
+			}
+			var resp response.Response[GetSessionsOutput]
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, tt.expected, resp.Data.Sessions)
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+
+	tests := []struct {
+		name           string
+		params         map[string]string
+		stored         string
+		getErr         error
+		delErr         error
+		expectDel      bool
+		expectCleared  bool
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "current session", params: map[string]string{"sessionId": "s1"}, stored: `{"id":"s1","operatorId":5}`,
+			expectDel: true, expectCleared: true, expectedStatus: http.StatusOK},
+		{name: "other session", params: map[string]string{"sessionId": "s2"}, stored: `{"id":"s2","operatorId":5}`,
+			expectDel: true, expectedStatus: http.StatusOK},
+		{name: "session of another operator by an admin", params: map[string]string{"operatorId": "7", "sessionId": "s2"},
+			stored: `{"id":"s2","operatorId":7}`, expectDel: true, expectedStatus: http.StatusOK},
+		{name: "session of another operator", params: map[string]string{"sessionId": "s2"}, stored: `{"id":"s2","operatorId":7}`,
+			expectedStatus: http.StatusNotFound, expectedMsg: i18n.MsgSessionNotFound},
+		{name: "session not found", params: map[string]string{"sessionId": "s2"},
+			expectedStatus: http.StatusNotFound, expectedMsg: i18n.MsgSessionNotFound},
+		{name: "invalid operator", params: map[string]string{"operatorId": "0", "sessionId": "s2"},
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgOperatorInvalid},
+		{name: "error getting session", params: map[string]string{"sessionId": "s2"}, getErr: errors.New("ds error"),
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+		{name: "error revoking session", params: map[string]string{"sessionId": "s2"}, stored: `{"id":"s2","operatorId":5}`,
+			delErr: errors.New("ds error"), expectDel: true,
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			session.Set(session.New(mockDS, 7200))
+			sessionId := tt.params["sessionId"]
+			mockDS.On("Get", mock.Anything, "session:"+sessionId).Return(tt.stored != "", tt.stored, tt.getErr).Maybe()
+			if tt.expectDel {
+				mockDS.On("Del", mock.Anything, "session:"+sessionId).Return(tt.delErr).Once()
+			}
+
+			req := newSessionRequest(http.MethodDelete, tt.params, 5)
+			w := httptest.NewRecorder()
+			RevokeSession(auth.OAuthConfig{}).ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			} else {
+				var resp response.Response[RevokeSessionsOutput]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, 1, resp.Data.Revoked)
+			}
+			assert.Equal(t, tt.expectCleared, len(w.Result().Cookies()) == 2)
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+
+	tests := []struct {
+		name           string
+		params         map[string]string
+		indexKey       string
+		delErr         error
+		expectCleared  bool
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "log out everywhere", indexKey: "sessions:operator:5:ids", expectCleared: true, expectedStatus: http.StatusOK},
+		{name: "sessions of another operator", params: map[string]string{"operatorId": "7"}, indexKey: "sessions:operator:7:ids",
+			expectedStatus: http.StatusOK},
+		{name: "invalid operator", params: map[string]string{"operatorId": "-1"},
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgOperatorInvalid},
+		{name: "error revoking sessions", indexKey: "sessions:operator:5:ids", delErr: errors.New("ds error"),
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			session.Set(session.New(mockDS, 7200))
+			if tt.indexKey != "" {
+				mockDS.On("SMembers", mock.Anything, tt.indexKey).Return([]string{"s1", "s2"}, nil).Once()
+				mockDS.On("Del", mock.Anything, mock.Anything).Return(tt.delErr)
+				mockDS.On("SRem", mock.Anything, tt.indexKey, mock.Anything).Return(nil).Maybe()
+			}
+
+			req := newSessionRequest(http.MethodDelete, tt.params, 5)
+			w := httptest.NewRecorder()
+			RevokeSessions(auth.OAuthConfig{}).ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			} else {
+				var resp response.Response[RevokeSessionsOutput]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, 2, resp.Data.Revoked)
+				mockDS.AssertCalled(t, "SRem", mock.Anything, tt.indexKey, "s2")
+			}
+			assert.Equal(t, tt.expectCleared, len(w.Result().Cookies()) == 2)
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	biz_audit "via/internal/biz/audit"
+	biz_guide_assign "via/internal/biz/guide/assign"
+	biz_guide_reconcile "via/internal/biz/guide/reconcile"
+	biz_guide_status "via/internal/biz/guide/status"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/model"
+	guide_provider "via/internal/provider/guide"
+	operator_provider "via/internal/provider/operator"
+	response "via/internal/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GetDiscrepanciesOutput struct {
+	Report model.ReconciliationReport `json:"report"`
+}
+
+// GetDiscrepancies returns the guides whose state disagrees with the carrier on the last reconciliation
+func GetDiscrepancies() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*GetDiscrepanciesOutput]{}
+		report, found, err := biz_guide_reconcile.GetReport(r.Context())
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to get reconciliation report")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			report.Discrepancies = []model.Discrepancy{}
+		}
+		lang := response.GetLanguage(r)
+		for i, d := range report.Discrepancies {
+			report.Discrepancies[i].Description = biz_guide_status.GetReasonDescription(lang, d.Reason)
+			report.Discrepancies[i].Status = biz_guide_status.GetStatusDescription(lang, d.Status)
+		}
+		res.Data = &GetDiscrepanciesOutput{Report: report}
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+type GetWorkloadOutput struct {
+	Workload []model.OperatorWorkload `json:"workload"`
+}
+
+// GetWorkload returns every operator with its availability, presence and the guides it is attending
+func GetWorkload() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[*GetWorkloadOutput]{}
+		operators, err := operator_provider.Get().GetOperators(r.Context())
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to get operators")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		workload, err := guide_provider.Get().GetOperatorWorkload(r.Context(), biz_guide_status.GetInProcessStatus())
+		if err != nil {
+			log.Get().Error(r.Context(), err, "msg", "failed to get operator workload")
+			res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+			response.WriteJSON(w, r, res, http.StatusInternalServerError)
+			return
+		}
+		ids := []int{}
+		for _, operator := range operators {
+			ids = append(ids, operator.ID)
+		}
+		online := getOnlineOperators(r, ids)
+		data := &GetWorkloadOutput{Workload: []model.OperatorWorkload{}}
+		for _, operator := range operators {
+			data.Workload = append(data.Workload, model.OperatorWorkload{Operator: operator, Guides: workload[operator.ID],
+				Online: online[operator.ID]})
+		}
+		res.Data = data
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
+
+type OverrideGuideAssignmentInput struct {
+	OperatorId int `json:"operatorId"`
+}
+
+// OverrideGuideAssignment gives the guide to any enabled operator, whatever its availability
+// and workload, or back to SYSTEM
+func OverrideGuideAssignment() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := response.Response[any]{}
+		valid, guideId := isValidGuideId(w, r, chi.URLParam(r, "guideId"))
+		if !valid {
+			return
+		}
+		supervisorId := 0
+		if supervisorId = isValidOperatorId(w, r); supervisorId == 0 {
+			return
+		}
+		logger := log.Get()
+		logger.WithLogFieldsInRequest(r, "guide_id", guideId, "supervisor_id", supervisorId)
+
+		var input OverrideGuideAssignmentInput
+		if ok := getJsonBody(w, r, &input); !ok {
+			return
+		}
+		previous, err := biz_guide_assign.Override(r.Context(), guideId, input.OperatorId)
+		if isFailedToChangeOwner(w, r, err) {
+			return
+		}
+		recordAudit(r, biz_audit.GUIDE_REASSIGN, biz_audit.TARGET_GUIDE, guideId,
+			map[string]int{"operatorId": previous}, map[string]int{"operatorId": input.OperatorId})
+		logger.Info(r.Context(), "msg", "guide assignment overridden", "operator_id", input.OperatorId,
+			"previous_operator_id", previous)
+		response.WriteJSON(w, r, res, http.StatusOK)
+	})
+}
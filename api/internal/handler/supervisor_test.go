@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	biz_guide_reconcile "via/internal/biz/guide/reconcile"
+	biz_guide_status "via/internal/biz/guide/status"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/ds"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/global"
+	"via/internal/i18n"
+	"via/internal/model"
+	"via/internal/presence"
+	guide_provider "via/internal/provider/guide"
+	mock_guide_provider "via/internal/provider/guide/mock"
+	operator_provider "via/internal/provider/operator"
+	mock_operator_provider "via/internal/provider/operator/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/response"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetDiscrepancies(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	report := model.ReconciliationReport{Checked: 2, Discrepancies: []model.Discrepancy{
+		{GuideId: 1, Status: biz_guide_status.DELIVERED, ViaStatus: "CRR", Reason: biz_guide_reconcile.NOT_DELIVERED_AT_CARRIER},
+	}}
+	data, _ := json.Marshal(report)
+
+	tests := []struct {
+		name           string
+		found          bool
+		value          string
+		err            error
+		expectedStatus int
+		expectedCount  int
+	}{
+		{name: "report found", found: true, value: string(data), expectedStatus: http.StatusOK, expectedCount: 1},
+		{name: "no report yet", expectedStatus: http.StatusOK},
+		{name: "ds error", err: errors.New("ds error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			ds.Set(mockDS)
+			mockDS.On("Get", mock.Anything, mock.Anything).Return(tt.found, tt.value, tt.err)
+
+			req := httptest.NewRequest(http.MethodGet, "/supervisor/discrepancies", nil)
+			w := httptest.NewRecorder()
+			GetDiscrepancies().ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, i18n.MsgInternalServerError)
+				return
+			}
+			var resp response.Response[GetDiscrepanciesOutput]
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Len(t, resp.Data.Report.Discrepancies, tt.expectedCount)
+			if tt.expectedCount > 0 {
+				d := resp.Data.Report.Discrepancies[0]
+				assert.Equal(t, "Entregado", d.Status)
+				assert.Equal(t, "Entregada en el sistema pero no en Via", d.Description)
+			}
+		})
+	}
+}
+
+func TestGetWorkload(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	operators := []model.Operator{{ID: 2, Availability: biz_operator.AVAILABILITY_AVAILABLE}, {ID: 3}}
+
+	tests := []struct {
+		name           string
+		operatorsErr   error
+		workloadErr    error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "error getting operators", operatorsErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+		{name: "error getting workload", workloadErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockOperator.On("GetOperators", mock.Anything).Return(operators, tt.operatorsErr).Once()
+			if tt.operatorsErr == nil {
+				mockGuide.On("GetOperatorWorkload", mock.Anything, biz_guide_status.GetInProcessStatus()).
+					Return(map[int]int{biz_operator.OPERATOR_SYSTEM: 4, 2: 3}, tt.workloadErr).Once()
+			}
+			mockDS := new(mock_ds.MockDS)
+			ds.Set(mockDS)
+			presence.Set(presence.New(presence.PresenceCfg{}))
+			t.Cleanup(func() { presence.Set(nil) })
+			mockDS.On("SMembers", mock.Anything, "presence:operator:2:connections").Return([]string{"a"}, nil).Maybe()
+			mockDS.On("SMembers", mock.Anything, "presence:operator:3:connections").Return([]string{}, nil).Maybe()
+
+			req := httptest.NewRequest(http.MethodGet, "/supervisor/workload", nil)
+			w := httptest.NewRecorder()
+			GetWorkload().ServeHTTP(w, req)
+
+			if tt.expectedStatus != http.StatusOK {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, i18n.MsgInternalServerError)
+			} else {
+				var resp response.Response[GetWorkloadOutput]
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, []model.OperatorWorkload{{Operator: operators[0], Guides: 3, Online: true}, {Operator: operators[1]}},
+					resp.Data.Workload)
+			}
+			mockOperator.AssertExpectations(t)
+			mockGuide.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOverrideGuideAssignment(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+	guide := model.Guide{ID: 123, ViaGuideID: "999025862539", Operator: model.Operator{ID: 2}}
+
+	tests := []struct {
+		name           string
+		guideId        string
+		body           string
+		supervisorId   any
+		setupMocks     func(*mock_guide_provider.MockGuideProvider, *mock_operator_provider.MockOperatorProvider, *mock_pubsub.MockPubSub)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name: "invalid guide ID", guideId: "abc", body: `{"operatorId":3}`, supervisorId: 42,
+			setupMocks: func(*mock_guide_provider.MockGuideProvider, *mock_operator_provider.MockOperatorProvider, *mock_pubsub.MockPubSub) {
+			},
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgGuideInvalid,
+		},
+		{
+			name: "missing supervisor", guideId: "123", body: `{"operatorId":3}`,
+			setupMocks: func(*mock_guide_provider.MockGuideProvider, *mock_operator_provider.MockOperatorProvider, *mock_pubsub.MockPubSub) {
+			},
+			expectedStatus: http.StatusUnauthorized, expectedMsg: i18n.MsgOperatorInvalid,
+		},
+		{
+			name: "invalid body", guideId: "123", body: `{`, supervisorId: 42,
+			setupMocks: func(*mock_guide_provider.MockGuideProvider, *mock_operator_provider.MockOperatorProvider, *mock_pubsub.MockPubSub) {
+			},
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgBadRequest,
+		},
+		{
+			name: "error getting operator", guideId: "123", body: `{"operatorId":3}`, supervisorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, o *mock_operator_provider.MockOperatorProvider, _ *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(guide, nil).Once()
+				o.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{}, errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError,
+		},
+		{
+			name: "disabled operator", guideId: "123", body: `{"operatorId":3}`, supervisorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, o *mock_operator_provider.MockOperatorProvider, _ *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(guide, nil).Once()
+				o.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{ID: 3}, nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest, expectedMsg: i18n.MsgOperatorInvalid,
+		},
+		{
+			name: "guide not found", guideId: "123", body: `{"operatorId":3}`, supervisorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, _ *mock_operator_provider.MockOperatorProvider, _ *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(model.Guide{}, nil).Once()
+			},
+			expectedStatus: http.StatusNotFound, expectedMsg: i18n.MsgGuideNotFound,
+		},
+		{
+			name: "failed update", guideId: "123", body: `{"operatorId":3}`, supervisorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, o *mock_operator_provider.MockOperatorProvider, _ *mock_pubsub.MockPubSub) {
+				o.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{ID: 3, Enabled: true}, nil).Once()
+				g.On("GetGuideById", mock.Anything, 123).Return(guide, nil).Once()
+				g.On("AssignGuide", mock.Anything, 123, 2, 3).Return(false, errors.New("db error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError,
+		},
+		{
+			name: "owner changed meanwhile", guideId: "123", body: `{"operatorId":3}`, supervisorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, o *mock_operator_provider.MockOperatorProvider, _ *mock_pubsub.MockPubSub) {
+				o.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{ID: 3, Enabled: true}, nil).Once()
+				g.On("GetGuideById", mock.Anything, 123).Return(guide, nil).Once()
+				g.On("AssignGuide", mock.Anything, 123, 2, 3).Return(false, nil).Once()
+			},
+			expectedStatus: http.StatusConflict, expectedMsg: i18n.MsgGuideAssignedToOther,
+		},
+		{
+			name: "assigned to an operator", guideId: "123", body: `{"operatorId":3}`, supervisorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, o *mock_operator_provider.MockOperatorProvider, p *mock_pubsub.MockPubSub) {
+				o.On("GetOperatorById", mock.Anything, 3).Return(model.Operator{ID: 3, Enabled: true}, nil).Once()
+				g.On("GetGuideById", mock.Anything, 123).Return(guide, nil).Once()
+				g.On("AssignGuide", mock.Anything, 123, 2, 3).Return(true, nil).Once()
+				p.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"123"}`).Return(errors.New("pubsub error")).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "released to SYSTEM", guideId: "123", body: `{"operatorId":1}`, supervisorId: 42,
+			setupMocks: func(g *mock_guide_provider.MockGuideProvider, _ *mock_operator_provider.MockOperatorProvider, p *mock_pubsub.MockPubSub) {
+				g.On("GetGuideById", mock.Anything, 123).Return(guide, nil).Once()
+				g.On("AssignGuide", mock.Anything, 123, 2, biz_operator.OPERATOR_SYSTEM).Return(true, nil).Once()
+				p.On("Publish", mock.Anything, global.GuideAssignmentChannel, `{"guide_id":"123"}`).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGuide := new(mock_guide_provider.MockGuideProvider)
+			guide_provider.Set(mockGuide)
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			mockPubSub := new(mock_pubsub.MockPubSub)
+			pubsub.Set(mockPubSub)
+			tt.setupMocks(mockGuide, mockOperator, mockPubSub)
+
+			req := newGuideRequest(http.MethodPut, "/supervisor/guide/"+tt.guideId+"/operator", tt.guideId, []byte(tt.body), tt.supervisorId)
+			w := httptest.NewRecorder()
+			OverrideGuideAssignment().ServeHTTP(w, req)
+
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, http.StatusOK, w.Code)
+			} else {
+				assertJSONErrorResponse(t, req, w, tt.expectedStatus, tt.expectedMsg)
+			}
+			mockGuide.AssertExpectations(t)
+			mockOperator.AssertExpectations(t)
+			mockPubSub.AssertExpectations(t)
+		})
+	}
+}
@@ -2,17 +2,21 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"regexp"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
 	biz_config "via/internal/biz/config"
+	biz_guide_assign "via/internal/biz/guide/assign"
+	biz_operator "via/internal/biz/operator"
 	"via/internal/i18n"
 	"via/internal/log"
 	"via/internal/middleware"
 	"via/internal/model"
+	via_guide_provider "via/internal/provider/via/guide"
 	response "via/internal/response"
 )
 
@@ -60,36 +64,30 @@ func getJsonBody(w http.ResponseWriter, r *http.Request, input any) bool {
 	res := response.Response[any]{}
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		log.Get().Error(r.Context(), err, "msg", "failed to decode body")
-		res.Message = i18n.Get(r, i18n.MsgBadRequest)
+		res.Error = i18n.Error(r, i18n.MsgBadRequest)
 		response.WriteJSON(w, r, res, http.StatusBadRequest)
 		return false
 	}
 	return true
 }
 
-func isValidViaGuideId(w http.ResponseWriter, r *http.Request, viaGuideId string) bool {
-	res := response.Response[any]{}
-	if viaGuideId == "" {
-		log.Get().Warn(r.Context(), "msg", "missing via guide id")
-		res.Message = i18n.Get(r, i18n.MsgGuideRequired)
-		response.WriteJSON(w, r, res, http.StatusBadRequest)
-		return false
-	}
-
-	if match, _ := regexp.MatchString(`^\d{12}$`, viaGuideId); !match {
-		log.Get().Warn(r.Context(), "msg", "invalid guide code")
-		res.Message = i18n.Get(r, i18n.MsgGuideInvalid)
-		response.WriteJSON(w, r, res, http.StatusBadRequest)
-		return false
+// queryNames returns the names of the query parameters, sorted, to log a query leaving out
+// its values: a recipient name is personal data
+func queryNames(query url.Values) []string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
 	}
-	return true
+	slices.Sort(names)
+	return names
 }
 
 func isGuideNotFound(w http.ResponseWriter, r *http.Request, id string) bool {
 	res := response.Response[any]{}
 	if id == "" {
+		middleware.RegisterLockoutFailure(r)
 		log.Get().Warn(r.Context(), "msg", "guide not found")
-		res.Message = i18n.Get(r, i18n.MsgGuideNotFound)
+		res.Error = i18n.Error(r, i18n.MsgGuideNotFound)
 		status := http.StatusNotFound
 		response.WriteJSON(w, r, res, status)
 		return false
@@ -111,10 +109,17 @@ func isInvalidViaGuideToWithdraw(viaGuide model.ViaGuide, biz biz_config.Bussine
 }
 
 func isFailedToFetchGuide(w http.ResponseWriter, r *http.Request, err error) bool {
+	if errors.Is(err, via_guide_provider.ErrUnavailable) {
+		res := response.Response[any]{}
+		log.Get().Error(r.Context(), err, "msg", "via guide provider unavailable")
+		res.Error = i18n.Error(r, i18n.MsgGuideProviderUnavailable)
+		response.WriteJSON(w, r, res, http.StatusServiceUnavailable)
+		return true
+	}
 	if err != nil {
 		res := response.Response[any]{}
 		log.Get().Error(r.Context(), err, "msg", "failed to fetch guide")
-		res.Message = i18n.Get(r, i18n.MsgInternalServerError)
+		res.Error = i18n.Error(r, i18n.MsgInternalServerError)
 		status := http.StatusInternalServerError
 		response.WriteJSON(w, r, res, status)
 		return true
@@ -126,14 +131,14 @@ func isValidGuideId(w http.ResponseWriter, r *http.Request, guideId string) (boo
 	res := response.Response[any]{}
 	if guideId == "" {
 		log.Get().Warn(r.Context(), "msg", "missing guide id")
-		res.Message = i18n.Get(r, i18n.MsgGuideRequired)
+		res.Error = i18n.Error(r, i18n.MsgGuideRequired, response.FieldError{Field: "guideId", Code: response.FIELD_REQUIRED})
 		response.WriteJSON(w, r, res, http.StatusBadRequest)
 		return false, 0
 	}
 
 	if id, err := strconv.Atoi(guideId); err != nil {
 		log.Get().Warn(r.Context(), "msg", "invalid guide id", "guide_id", guideId)
-		res.Message = i18n.Get(r, i18n.MsgGuideInvalid)
+		res.Error = i18n.Error(r, i18n.MsgGuideInvalid, response.FieldError{Field: "guideId", Code: response.FIELD_INVALID})
 		response.WriteJSON(w, r, res, http.StatusBadRequest)
 		return false, 0
 	} else {
@@ -147,9 +152,52 @@ func isValidOperatorId(w http.ResponseWriter, r *http.Request) int {
 	if !ok {
 		res := response.Response[any]{}
 		log.Get().Warn(r.Context(), "msg", "missing operator id in context", "operator_id", operatorIdCtx)
-		res.Message = i18n.Get(r, i18n.MsgOperatorInvalid)
+		res.Error = i18n.Error(r, i18n.MsgOperatorInvalid, response.FieldError{Field: "operatorId", Code: response.FIELD_REQUIRED})
 		response.WriteJSON(w, r, res, http.StatusUnauthorized)
 		return 0
 	}
 	return operatorId
 }
+
+// isForced reports whether the request forces the operation with force=true, which only
+// supervisors are allowed to
+func isForced(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	if r.URL.Query().Get("force") != "true" {
+		return false, true
+	}
+	role, _ := r.Context().Value(middleware.OperatorRoleKey).(string)
+	if !biz_operator.HasRole(role, biz_operator.ROLE_SUPERVISOR) {
+		log.Get().Warn(r.Context(), "msg", "operator role not allowed to force", "role", role)
+		response.WriteJSON(w, r, response.Response[any]{Error: i18n.Error(r, i18n.MsgOperatorForbidden)}, http.StatusForbidden)
+		return false, false
+	}
+	return true, true
+}
+
+// isFailedToChangeOwner writes the response of a failed guide owner change
+func isFailedToChangeOwner(w http.ResponseWriter, r *http.Request, err error) bool {
+	if err == nil {
+		return false
+	}
+	res := response.Response[any]{}
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, biz_guide_assign.ErrGuideNotFound):
+		res.Error, status = i18n.Error(r, i18n.MsgGuideNotFound), http.StatusNotFound
+	case errors.Is(err, biz_guide_assign.ErrAssignedToOther):
+		res.Error, status = i18n.Error(r, i18n.MsgGuideAssignedToOther), http.StatusConflict
+	case errors.Is(err, biz_guide_assign.ErrNotOwner):
+		res.Error, status = i18n.Error(r, i18n.MsgGuideNotOwned), http.StatusForbidden
+	case errors.Is(err, biz_guide_assign.ErrOperatorInvalid):
+		res.Error, status = i18n.Error(r, i18n.MsgOperatorInvalid), http.StatusBadRequest
+	default:
+		res.Error = i18n.Error(r, i18n.MsgInternalServerError)
+	}
+	if status == http.StatusInternalServerError {
+		log.Get().Error(r.Context(), err, "msg", "failed changing guide operator")
+	} else {
+		log.Get().Warn(r.Context(), "msg", "guide operator not changed", "reason", err.Error())
+	}
+	response.WriteJSON(w, r, res, status)
+	return true
+}
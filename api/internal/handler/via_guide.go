@@ -7,6 +7,7 @@ import (
 	biz_config "via/internal/biz/config"
 	biz_guide_status "via/internal/biz/guide/status"
 	"via/internal/log"
+	"via/internal/middleware"
 	guide_provider "via/internal/provider/guide"
 	via_guide_provider "via/internal/provider/via/guide"
 	response "via/internal/response"
@@ -22,12 +23,9 @@ type GetGuideToWithdrawOutput struct {
 func GetGuideToWithdraw(biz biz_config.BussinessCfg) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		res := response.Response[*GetGuideToWithdrawOutput]{}
+		// the via guide id is checked against the api document by middleware.ValidateRequest
 		viaGuideId := chi.URLParam(r, "viaGuideId")
 
-		if ok := isValidViaGuideId(w, r, viaGuideId); !ok {
-			return
-		}
-
 		logger := log.Get()
 		logger.WithLogFieldsInRequest(r, "viaGuideId", viaGuideId)
 
@@ -39,6 +37,7 @@ func GetGuideToWithdraw(biz biz_config.BussinessCfg) http.Handler {
 		data := &GetGuideToWithdrawOutput{EnabledToWithdraw: false}
 		res.Data = data
 		if viaGuide.ID == "" {
+			middleware.RegisterLockoutFailure(r)
 			data.WithdrawMessage = getWithDrawMessage(r, notFound, viaGuideId)
 			logger.Info(r.Context(), "msg", "guide not found")
 			response.WriteJSON(w, r, res, http.StatusOK)
@@ -106,7 +105,7 @@ func GetGuideToWithdraw(biz biz_config.BussinessCfg) http.Handler {
 			response.WriteJSON(w, r, res, http.StatusOK)
 			return
 		}
-		logger.Info(r.Context(), "msg", "guide not available to start withdraw process", "guide", viaGuide)
+		logger.Info(r.Context(), "msg", "guide not available to start withdraw process", "via_guide_status", viaGuide.Status)
 		data.WithdrawMessage = getWithDrawMessage(r, notAvailable, viaGuideId)
 		response.WriteJSON(w, r, res, http.StatusOK)
 	})
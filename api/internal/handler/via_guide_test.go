@@ -64,24 +64,6 @@ func TestGetGuideHandler(t *testing.T) {
 		expectedStatus               int
 		expectedResponse             response.Response[GetGuideToWithdrawOutput]
 	}{
-		{
-			name:           "missing id",
-			guideID:        "",
-			expectedStatus: http.StatusBadRequest,
-			expectedResponse: response.Response[GetGuideToWithdrawOutput]{RequestID: "0001",
-				Message:    i18n.Get(newRequestWithID(""), i18n.MsgGuideRequired),
-				HttpStatus: http.StatusBadRequest},
-		},
-
-		{
-			name:           "invalid id",
-			guideID:        "abc123",
-			expectedStatus: http.StatusBadRequest,
-			expectedResponse: response.Response[GetGuideToWithdrawOutput]{RequestID: "abc1230001",
-				Message:    i18n.Get(newRequestWithID("abc123"), i18n.MsgGuideInvalid),
-				HttpStatus: http.StatusBadRequest},
-		},
-
 		{
 			name:              "provider error",
 			guideID:           "123456789012",
@@ -89,10 +71,23 @@ func TestGetGuideHandler(t *testing.T) {
 			expectedStatus:    http.StatusInternalServerError,
 			expectedResponse: response.Response[GetGuideToWithdrawOutput]{RequestID: "1234567890120001",
 				Message:    i18n.Get(newRequestWithID("123456789012"), i18n.MsgInternalServerError),
+				Error:      i18n.Error(newRequestWithID("123456789012"), i18n.MsgInternalServerError),
 				HttpStatus: http.StatusInternalServerError},
 			guideViaProviderCallExpected: true,
 		},
 
+		{
+			name:              "provider unavailable",
+			guideID:           "123456789019",
+			guideViaMockError: via_guide_provider.ErrUnavailable,
+			expectedStatus:    http.StatusServiceUnavailable,
+			expectedResponse: response.Response[GetGuideToWithdrawOutput]{RequestID: "1234567890190001",
+				Message:    i18n.Get(newRequestWithID("123456789019"), i18n.MsgGuideProviderUnavailable),
+				Error:      i18n.Error(newRequestWithID("123456789019"), i18n.MsgGuideProviderUnavailable),
+				HttpStatus: http.StatusServiceUnavailable},
+			guideViaProviderCallExpected: true,
+		},
+
 		{
 			name:               "guide not found (empty ID)",
 			guideID:            "123456789013",
@@ -286,6 +281,7 @@ func TestGetGuideHandler(t *testing.T) {
 			expectedStatus:            http.StatusInternalServerError,
 			expectedResponse: response.Response[GetGuideToWithdrawOutput]{RequestID: "1234567890230001",
 				Message:    i18n.Get(newRequestWithID("123456789023"), i18n.MsgInternalServerError),
+				Error:      i18n.Error(newRequestWithID("123456789023"), i18n.MsgInternalServerError),
 				HttpStatus: http.StatusInternalServerError},
 		},
 		{
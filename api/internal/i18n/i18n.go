@@ -16,6 +16,7 @@ const (
 	MsgTooManyRequestsError      = "too_many_requests_error"
 	MsgOperatorInvalid           = "operator_invalid"
 	MsgOperatorUnauthorized      = "operator_unauthorized"
+	MsgOperatorForbidden         = "operator_forbidden"
 	MsgAuthStateNotFound         = "auth_state_not_found"
 	MsgAuthFailedToExchangeToken = "auth_failed_to_exchange_token"
 	MsgAuthFailedToGetUserInfo   = "auth_failed_to_get_user_info"
@@ -23,6 +24,23 @@ const (
 	MsgAuthTokenNotFoud          = "auth_token_not_found"
 	MsgAuthTokenInvalid          = "invalid_auth_token"
 	MsgAccessTokenNotFound       = "access_token_not_found"
+	MsgLockedOut                 = "locked_out"
+	MsgDeviceUnauthorized        = "device_unauthorized"
+	MsgGuideProviderUnavailable  = "guide_provider_unavailable"
+	MsgSearchInvalid             = "search_invalid"
+	MsgGuideAssignedToOther      = "guide_assigned_to_other"
+	MsgGuideNotOwned             = "guide_not_owned"
+	MsgSessionExpired            = "session_expired"
+	MsgSessionNotFound           = "session_not_found"
+	MsgAuthProviderNotFound      = "auth_provider_not_found"
+	MsgLocalLoginInvalid         = "local_login_invalid"
+	MsgDeviceInvalid             = "device_invalid"
+	MsgDeviceNotFound            = "device_not_found"
+	MsgPairingCodeInvalid        = "pairing_code_invalid"
+	MsgOperatorNotLinked         = "operator_not_linked"
+	MsgRouteNotFound             = "route_not_found"
+	MsgMethodNotAllowed          = "method_not_allowed"
+	MsgRequestTooLarge           = "request_too_large"
 )
 
 var messages = map[string]map[string]string{
@@ -37,6 +55,7 @@ var messages = map[string]map[string]string{
 		MsgTooManyRequestsError:      "Demasiadas solicitudes, por favor intente más tarde.",
 		MsgOperatorInvalid:           "El Id de Operador es inválido",
 		MsgOperatorUnauthorized:      "Operador no autorizado.",
+		MsgOperatorForbidden:         "El operador no tiene permisos para esta operación.",
 		MsgAuthStateNotFound:         "Estado de autenticación no encontrado.",
 		MsgAuthFailedToExchangeToken: "Error al intercambiar el token de autenticación.",
 		MsgAuthFailedToGetUserInfo:   "Error al obtener la información del usuario.",
@@ -44,6 +63,23 @@ var messages = map[string]map[string]string{
 		MsgAuthTokenNotFoud:          "Token de autenticación no encontrado.",
 		MsgAuthTokenInvalid:          "Token de autenticación inválido.",
 		MsgAccessTokenNotFound:       "Token de acceso no encontrado.",
+		MsgLockedOut:                 "Demasiadas consultas fallidas, por favor intente más tarde.",
+		MsgDeviceUnauthorized:        "Dispositivo no autorizado.",
+		MsgGuideProviderUnavailable:  "El sistema de consulta de envíos no está disponible, por favor intente más tarde.",
+		MsgSearchInvalid:             "Los filtros de búsqueda son inválidos.",
+		MsgGuideAssignedToOther:      "La guía está siendo atendida por otro operador.",
+		MsgGuideNotOwned:             "La guía no está asignada al operador.",
+		MsgSessionExpired:            "La sesión expiró, inicie sesión nuevamente.",
+		MsgSessionNotFound:           "Sesión no encontrada.",
+		MsgAuthProviderNotFound:      "Proveedor de identidad desconocido.",
+		MsgLocalLoginInvalid:         "Cuenta, contraseña o código inválidos.",
+		MsgDeviceInvalid:             "Los datos del dispositivo son inválidos.",
+		MsgDeviceNotFound:            "Dispositivo no encontrado.",
+		MsgPairingCodeInvalid:        "El código de vinculación es inválido o expiró.",
+		MsgOperatorNotLinked:         "La cuenta no está vinculada a un operador, solicite a un administrador que la vincule.",
+		MsgRouteNotFound:             "Recurso no encontrado.",
+		MsgMethodNotAllowed:          "Método no permitido para el recurso.",
+		MsgRequestTooLarge:           "La solicitud excede el tamaño permitido.",
 	},
 	"en": {
 		MsgRequestTimeout:          "Request timeout.",
@@ -64,3 +100,8 @@ func Get(r *http.Request, key string) string {
 	lang := response.GetLanguage(r)
 	return GetWithLang(lang, key)
 }
+
+// Error is the error of a response for the message key, the key is its code
+func Error(r *http.Request, key string, details ...response.FieldError) *response.Error {
+	return &response.Error{Code: key, Message: Get(r, key), Details: details}
+}
@@ -3,7 +3,9 @@ package i18n
 import (
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"via/internal/response"
 )
 
 func TestGetWithLang(t *testing.T) {
@@ -115,3 +117,18 @@ func TestGet(t *testing.T) {
 		})
 	}
 }
+
+func TestError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	field := response.FieldError{Field: "limit", Code: response.FIELD_OUT_OF_RANGE}
+
+	got := Error(req, MsgSearchInvalid, field)
+	expected := &response.Error{Code: MsgSearchInvalid, Message: "Los filtros de búsqueda son inválidos.",
+		Details: []response.FieldError{field}}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected '%+v', got '%+v'", expected, got)
+	}
+	if got := Error(req, MsgGuideNotFound); got.Code != MsgGuideNotFound || got.Details != nil {
+		t.Errorf("expected code '%s' without details, got '%+v'", MsgGuideNotFound, got)
+	}
+}
@@ -0,0 +1,234 @@
+package idp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"via/internal/log"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	jose "github.com/go-jose/go-jose/v4"
+)
+
+type IDPCfg struct {
+	KeysRefresh  int `env:"KEYS_REFRESH" envDefault:"3600" json:"keysRefresh"`    // in seconds, the signing keys are fetched again after it
+	KeysRefetch  int `env:"KEYS_REFETCH" envDefault:"60" json:"keysRefetch"`      // in seconds, minimum wait between fetches of an unknown key
+	OfflineGrace int `env:"OFFLINE_GRACE" envDefault:"86400" json:"offlineGrace"` // in seconds, the cached keys are trusted for it while the IdP is unreachable
+	Timeout      int `env:"TIMEOUT" envDefault:"10" json:"timeout"`               // in seconds
+}
+
+var (
+	ErrKeyNotFound     = errors.New("signing key not found")
+	ErrKeysExpired     = errors.New("signing keys expired")
+	ErrUnknownProvider = errors.New("unknown identity provider")
+)
+
+// Identity is the IdP account an id token was issued for
+type Identity struct {
+	Issuer  string
+	Subject string
+}
+
+// Verifier checks the IdP id tokens against its signing keys, which are fetched in the background and
+// cached. The keys are fetched again after the refresh interval, meanwhile the stale ones are served,
+// or when a token is signed with an unknown key, a rotation, which waits for the fetch. While the IdP
+// is unreachable the cached keys are kept for the offline grace.
+type Verifier struct {
+	verifier *oidc.IDTokenVerifier
+	jwksURL  string
+	algs     []jose.SignatureAlgorithm
+	client   *http.Client
+	refresh  time.Duration
+	refetch  time.Duration
+	grace    time.Duration
+
+	mutex      sync.RWMutex
+	keys       []jose.JSONWebKey
+	fetchedAt  time.Time     // last successful fetch
+	checkedAt  time.Time     // last fetch attempt
+	refreshing chan struct{} // closed when the running fetch ends, nil while none runs
+}
+
+// New discovers the issuer and starts fetching its signing keys in the background
+func New(ctx context.Context, cfg IDPCfg, issuer string, clientID string) (*Verifier, error) {
+	client := &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, client), issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discovering issuer %s: %w", issuer, err)
+	}
+	var discovery struct {
+		JWKSURL string   `json:"jwks_uri"`
+		Algs    []string `json:"id_token_signing_alg_values_supported"`
+	}
+	if err := provider.Claims(&discovery); err != nil {
+		return nil, fmt.Errorf("decoding issuer %s discovery: %w", issuer, err)
+	}
+	if len(discovery.Algs) == 0 {
+		discovery.Algs = []string{oidc.RS256}
+	}
+	v := &Verifier{
+		jwksURL: discovery.JWKSURL,
+		client:  client,
+		refresh: time.Duration(cfg.KeysRefresh) * time.Second,
+		refetch: time.Duration(cfg.KeysRefetch) * time.Second,
+		grace:   time.Duration(cfg.OfflineGrace) * time.Second,
+	}
+	for _, alg := range discovery.Algs {
+		v.algs = append(v.algs, jose.SignatureAlgorithm(alg))
+	}
+	// the IdP token proves the login, the session outlives its short expiry through the refresh token rotation
+	v.verifier = oidc.NewVerifier(issuer, v, &oidc.Config{
+		ClientID:             clientID,
+		SupportedSigningAlgs: discovery.Algs,
+		SkipExpiryCheck:      true,
+	})
+	v.startRefresh()
+	return v, nil
+}
+
+// Verify checks the id token signature, issuer and audience
+func (v *Verifier) Verify(ctx context.Context, idToken string) (Identity, error) {
+	token, err := v.verifier.Verify(ctx, idToken)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{Issuer: token.Issuer, Subject: token.Subject}, nil
+}
+
+// Verifiers are the verifiers of the identity providers by name
+type Verifiers map[string]*Verifier
+
+// Verify checks the id token with the verifier of the provider
+func (vs Verifiers) Verify(ctx context.Context, provider string, idToken string) (Identity, error) {
+	v, ok := vs[provider]
+	if !ok {
+		return Identity{}, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+	return v.Verify(ctx, idToken)
+}
+
+// VerifySignature implements oidc.KeySet with the cached keys
+func (v *Verifier) VerifySignature(ctx context.Context, token string) ([]byte, error) {
+	jws, err := jose.ParseSigned(token, v.algs)
+	if err != nil {
+		return nil, fmt.Errorf("parsing token: %w", err)
+	}
+	keyID := jws.Signatures[0].Header.KeyID
+	keys, err := v.keysFor(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if payload, err := jws.Verify(&key); err == nil {
+			return payload, nil
+		}
+	}
+	return nil, errors.New("failed to verify token signature")
+}
+
+// keysFor returns the cached keys matching the key id, fetching them again when stale or unknown
+func (v *Verifier) keysFor(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
+	keys, fetchedAt, checkedAt := v.cached(keyID)
+	now := time.Now()
+	switch {
+	case len(keys) == 0 && now.Sub(checkedAt) >= v.refetch:
+		// the key may have been rotated, the token waits for the fetch as long as its request lasts
+		select {
+		case <-v.startRefresh():
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		keys, fetchedAt, _ = v.cached(keyID)
+	case now.Sub(checkedAt) >= v.refresh:
+		v.startRefresh()
+	}
+	if now.Sub(fetchedAt) > v.grace {
+		return nil, ErrKeysExpired
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+	return keys, nil
+}
+
+func (v *Verifier) cached(keyID string) ([]jose.JSONWebKey, time.Time, time.Time) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	keys := []jose.JSONWebKey{}
+	for _, key := range v.keys {
+		if keyID == "" || key.KeyID == keyID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, v.fetchedAt, v.checkedAt
+}
+
+// startRefresh fetches the keys in the background unless a fetch is running, the returned channel is
+// closed when it ends. The fetch does not hold the cached keys and is bound by the client timeout only,
+// not by the request that started it.
+func (v *Verifier) startRefresh() <-chan struct{} {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if v.refreshing != nil {
+		return v.refreshing
+	}
+	done := make(chan struct{})
+	v.refreshing = done
+	go func() {
+		defer close(done)
+		ctx := context.Background()
+		keys, err := v.download(ctx)
+		v.mutex.Lock()
+		defer v.mutex.Unlock()
+		v.refreshing = nil
+		v.checkedAt = time.Now()
+		if err != nil {
+			log.Get().Warn(ctx, "msg", "unable to refresh IdP signing keys, using the cached ones", "error", err.Error())
+			return
+		}
+		v.keys = keys
+		v.fetchedAt = v.checkedAt
+	}()
+	return done
+}
+
+func (v *Verifier) download(ctx context.Context) ([]jose.JSONWebKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.jwksURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating keys request: %w", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching keys: status %d", resp.StatusCode)
+	}
+	var set jose.JSONWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decoding keys: %w", err)
+	}
+	return set.Keys, nil
+}
+
+var (
+	instance Verifiers
+	mutex    = &sync.RWMutex{}
+)
+
+func Get() Verifiers {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return instance
+}
+
+func Set(verifiers Verifiers) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	instance = verifiers
+}
@@ -0,0 +1,267 @@
+package idp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+	"via/internal/testutil"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cfg = IDPCfg{KeysRefresh: 3600, KeysRefetch: 60, OfflineGrace: 86400, Timeout: 5}
+
+// fakeIssuer is an OIDC issuer whose keys can be rotated or made unreachable
+type fakeIssuer struct {
+	*oidctest.Server
+	url        string
+	down       atomic.Bool
+	keyFetches atomic.Int32
+}
+
+func newFakeIssuer(t *testing.T, keys ...oidctest.PublicKey) *fakeIssuer {
+	issuer := &fakeIssuer{Server: &oidctest.Server{PublicKeys: keys}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if issuer.down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/keys" {
+			issuer.keyFetches.Add(1)
+		}
+		issuer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	issuer.SetIssuer(srv.URL)
+	issuer.url = srv.URL
+	return issuer
+}
+
+func newKey(t *testing.T, keyID string) (*rsa.PrivateKey, oidctest.PublicKey) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return priv, oidctest.PublicKey{PublicKey: priv.Public(), KeyID: keyID, Algorithm: oidc.RS256}
+}
+
+func (f *fakeIssuer) sign(priv *rsa.PrivateKey, keyID string, audience string) string {
+	return oidctest.SignIDToken(priv, keyID, oidc.RS256, `{"iss":"`+f.url+`","aud":"`+audience+`","sub":"ana",`+
+		`"exp":`+strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)+`}`)
+}
+
+// idle waits for the running fetch of the keys to end
+func idle(v *Verifier) {
+	v.mutex.RLock()
+	refreshing := v.refreshing
+	v.mutex.RUnlock()
+	if refreshing != nil {
+		<-refreshing
+	}
+}
+
+func TestNew(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	_, key := newKey(t, "k1")
+	issuer := newFakeIssuer(t, key)
+
+	v, err := New(context.Background(), cfg, issuer.url, "client")
+	require.NoError(t, err)
+	idle(v)
+	assert.Len(t, v.keys, 1)
+	assert.Equal(t, int32(1), issuer.keyFetches.Load())
+
+	issuer.down.Store(true)
+	_, err = New(context.Background(), cfg, issuer.url, "client")
+	assert.ErrorContains(t, err, "discovering issuer")
+}
+
+func TestNew_KeysError(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	priv, key := newKey(t, "k1")
+	issuer := newFakeIssuer(t, key)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/keys" {
+			w.Write([]byte("{"))
+			return
+		}
+		issuer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	issuer.SetIssuer(srv.URL)
+	issuer.url = srv.URL
+
+	v, err := New(context.Background(), cfg, srv.URL, "client")
+	require.NoError(t, err)
+	idle(v)
+	assert.Empty(t, v.keys)
+	assert.False(t, v.checkedAt.IsZero())
+
+	_, err = v.Verify(context.Background(), issuer.sign(priv, "k1", "client"))
+	assert.ErrorContains(t, err, ErrKeysExpired.Error())
+}
+
+func TestVerify_WaitsForTheKeys(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	priv, key := newKey(t, "k1")
+	issuer := newFakeIssuer(t, key)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/keys" {
+			<-release
+		}
+		issuer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	issuer.SetIssuer(srv.URL)
+	issuer.url = srv.URL
+
+	v, err := New(context.Background(), cfg, srv.URL, "client")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = v.Verify(ctx, issuer.sign(priv, "k1", "client"))
+	assert.ErrorContains(t, err, context.Canceled.Error())
+
+	verified := make(chan error)
+	go func() {
+		_, err := v.Verify(context.Background(), issuer.sign(priv, "k1", "client"))
+		verified <- err
+	}()
+	close(release)
+	assert.NoError(t, <-verified)
+}
+
+func TestVerify(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	priv, key := newKey(t, "k1")
+	rotated, rotatedKey := newKey(t, "k2")
+
+	tests := []struct {
+		name        string
+		token       func(issuer *fakeIssuer) string
+		setup       func(v *Verifier, issuer *fakeIssuer)
+		expectErr   error // wrapped as text by oidc
+		expectFail  bool
+		expectFetch int32
+	}{
+		{name: "valid token from the cached keys",
+			token: func(issuer *fakeIssuer) string { return issuer.sign(priv, "k1", "client") }},
+		{name: "another audience", token: func(issuer *fakeIssuer) string { return issuer.sign(priv, "k1", "other") },
+			expectFail: true},
+		{name: "malformed token", token: func(*fakeIssuer) string { return "not-a-token" }, expectFail: true},
+		{name: "signed with another key of the same id",
+			token: func(issuer *fakeIssuer) string { return issuer.sign(rotated, "k1", "client") }, expectFail: true},
+		{name: "rotated key is fetched",
+			token: func(issuer *fakeIssuer) string { return issuer.sign(rotated, "k2", "client") },
+			setup: func(v *Verifier, issuer *fakeIssuer) {
+				issuer.PublicKeys = []oidctest.PublicKey{key, rotatedKey}
+				v.checkedAt = time.Now().Add(-2 * time.Minute)
+			},
+			expectFetch: 1},
+		{name: "unknown key is not fetched again right away",
+			token: func(issuer *fakeIssuer) string { return issuer.sign(rotated, "k2", "client") },
+			setup: func(v *Verifier, issuer *fakeIssuer) {
+				issuer.PublicKeys = []oidctest.PublicKey{key, rotatedKey}
+			},
+			expectErr: ErrKeyNotFound},
+		{name: "stale keys are served while they are refreshed",
+			token: func(issuer *fakeIssuer) string { return issuer.sign(priv, "k1", "client") },
+			setup: func(v *Verifier, issuer *fakeIssuer) {
+				v.checkedAt = time.Now().Add(-2 * time.Hour)
+			},
+			expectFetch: 1},
+		{name: "cached keys are kept while the IdP is unreachable",
+			token: func(issuer *fakeIssuer) string { return issuer.sign(priv, "k1", "client") },
+			setup: func(v *Verifier, issuer *fakeIssuer) {
+				issuer.down.Store(true)
+				v.checkedAt = time.Now().Add(-2 * time.Hour)
+				v.fetchedAt = v.checkedAt
+			}},
+		{name: "cached keys expire after the offline grace",
+			token: func(issuer *fakeIssuer) string { return issuer.sign(priv, "k1", "client") },
+			setup: func(v *Verifier, issuer *fakeIssuer) {
+				issuer.down.Store(true)
+				v.checkedAt = time.Now().Add(-48 * time.Hour)
+				v.fetchedAt = v.checkedAt
+			},
+			expectErr: ErrKeysExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t, key)
+			v, err := New(context.Background(), cfg, issuer.url, "client")
+			require.NoError(t, err)
+			idle(v)
+			issuer.keyFetches.Store(0)
+			if tt.setup != nil {
+				tt.setup(v, issuer)
+			}
+
+			identity, err := v.Verify(context.Background(), tt.token(issuer))
+			idle(v)
+
+			switch {
+			case tt.expectErr != nil:
+				assert.ErrorContains(t, err, tt.expectErr.Error())
+			case tt.expectFail:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, Identity{Issuer: issuer.url, Subject: "ana"}, identity)
+			}
+			assert.Equal(t, tt.expectFetch, issuer.keyFetches.Load())
+		})
+	}
+}
+
+func TestDownload_Errors(t *testing.T) {
+	v := &Verifier{client: http.DefaultClient}
+
+	v.jwksURL = ":invalid"
+	_, err := v.download(context.Background())
+	assert.ErrorContains(t, err, "creating keys request")
+
+	v.jwksURL = "http://127.0.0.1:0/keys"
+	_, err = v.download(context.Background())
+	assert.ErrorContains(t, err, "fetching keys")
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	v.jwksURL = srv.URL
+	_, err = v.download(context.Background())
+	assert.EqualError(t, err, "fetching keys: status 404")
+}
+
+func TestVerifiers(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	priv, key := newKey(t, "k1")
+	issuer := newFakeIssuer(t, key)
+	v, err := New(context.Background(), cfg, issuer.url, "client")
+	require.NoError(t, err)
+	verifiers := Verifiers{"google": v}
+
+	identity, err := verifiers.Verify(context.Background(), "google", issuer.sign(priv, "k1", "client"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ana", identity.Subject)
+
+	_, err = verifiers.Verify(context.Background(), "entra", issuer.sign(priv, "k1", "client"))
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+}
+
+func TestGetSet(t *testing.T) {
+	verifiers := Verifiers{"default": &Verifier{}}
+	Set(verifiers)
+	assert.Equal(t, verifiers, Get())
+	Set(nil)
+	assert.Nil(t, Get())
+}
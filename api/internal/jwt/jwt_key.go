@@ -1,59 +1,192 @@
 package jwt_key
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"via/internal/secret"
 
+	jose "github.com/go-jose/go-jose/v4"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type JWTConfig struct {
-	PrivateKey           string `env:"PRIVATE_KEY" json:"-"`
-	PublicKey            string `env:"PUBLIC_KEY" json:"-"`
-	PrivateKeySecretFile string `env:"PRIVATE_KEY_FILE" json:"privateKeySecretFile"`
-	PublicKeySecretFile  string `env:"PUBLIC_KEY_FILE" json:"publicKeySecretFile"`
+	PrivateKey           string   `env:"PRIVATE_KEY" json:"-"`
+	PublicKey            string   `env:"PUBLIC_KEY" json:"-"`
+	PrivateKeySecretFile string   `env:"PRIVATE_KEY_FILE" json:"privateKeySecretFile"`
+	PublicKeySecretFile  string   `env:"PUBLIC_KEY_FILE" json:"publicKeySecretFile"`
+	Algorithm            string   `env:"ALGORITHM" json:"algorithm"`                                    // inferred from the key when empty
+	VerifyKeySecretFiles []string `env:"VERIFY_KEY_FILES" envSeparator:"," json:"verifyKeySecretFiles"` // public keys only accepted, the retired and the upcoming ones
 	jwt.SigningMethod
 }
 
+// Key is a public key the tokens are verified with, identified by its thumbprint
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	Public crypto.PublicKey
+}
+
 var (
-	privateKey    *rsa.PrivateKey
-	publicKey     *rsa.PublicKey
+	privateKey    crypto.PrivateKey
+	publicKey     crypto.PublicKey
+	keyID         string
+	keys          map[string]Key
 	once          sync.Once
 	mutex         sync.Mutex
 	signingMethod jwt.SigningMethod
 )
 
+// Init loads the signing keypair and the verify only public keys. A key is rotated by publishing
+// the new public key as a verify key, then signing with it and keeping the old public key as a
+// verify key until the tokens it signed expire.
 func Init(cfg JWTConfig) error {
 	var err error
 	once.Do(func() {
 		if cfg.PublicKey == "" {
 			cfg.PublicKey = secret.Get().Read(cfg.PublicKeySecretFile)
 		}
-		publicKey, err = jwt.ParseRSAPublicKeyFromPEM([]byte(cfg.PublicKey))
+		publicKey, err = parsePublicKeyFromPEM(cfg.PublicKey)
 		if err != nil {
 			return
 		}
 		if cfg.PrivateKey == "" {
 			cfg.PrivateKey = secret.Get().Read(cfg.PrivateKeySecretFile)
 		}
-		privateKey, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(cfg.PrivateKey))
+		privateKey, err = ParsePrivateKeyFromPEM(cfg.PrivateKey)
 		if err != nil {
 			return
 		}
 		if cfg.SigningMethod == nil {
-			cfg.SigningMethod = jwt.SigningMethodRS256
+			cfg.SigningMethod, err = methodFor(publicKey, cfg.Algorithm)
+			if err != nil {
+				return
+			}
 		}
 		signingMethod = cfg.SigningMethod
+
+		var signing Key
+		signing, err = newKey(publicKey, signingMethod)
+		if err != nil {
+			return
+		}
+		keyID = signing.ID
+		keys = map[string]Key{keyID: signing}
+		for _, file := range cfg.VerifyKeySecretFiles {
+			var key Key
+			key, err = parseVerifyKey(secret.Get().Read(file))
+			if err != nil {
+				err = fmt.Errorf("verify key %s: %w", file, err)
+				return
+			}
+			// the signing key listed again keeps its configured algorithm
+			if _, ok := keys[key.ID]; !ok {
+				keys[key.ID] = key
+			}
+		}
 	})
 	return err
 }
 
-func GetPrivateKey() *rsa.PrivateKey {
+func parsePublicKeyFromPEM(pem string) (crypto.PublicKey, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(pem)); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM([]byte(pem)); err == nil {
+		return key, nil
+	}
+	key, err := jwt.ParseEdPublicKeyFromPEM([]byte(pem))
+	if err != nil {
+		return nil, errors.New("public key must be a PEM encoded RSA, ECDSA or Ed25519 key")
+	}
+	return key, nil
+}
+
+// ParsePrivateKeyFromPEM parses a PEM encoded RSA, ECDSA or Ed25519 private key
+func ParsePrivateKeyFromPEM(pem string) (crypto.PrivateKey, error) {
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(pem)); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPrivateKeyFromPEM([]byte(pem)); err == nil {
+		return key, nil
+	}
+	key, err := jwt.ParseEdPrivateKeyFromPEM([]byte(pem))
+	if err != nil {
+		return nil, errors.New("private key must be a PEM encoded RSA, ECDSA or Ed25519 key")
+	}
+	return key, nil
+}
+
+func parseVerifyKey(pem string) (Key, error) {
+	public, err := parsePublicKeyFromPEM(pem)
+	if err != nil {
+		return Key{}, err
+	}
+	method, err := methodFor(public, "")
+	if err != nil {
+		return Key{}, err
+	}
+	return newKey(public, method)
+}
+
+// methodFor returns the configured algorithm, checking it suits the key, or the usual one of the key type
+func methodFor(key crypto.PublicKey, algorithm string) (jwt.SigningMethod, error) {
+	var usual jwt.SigningMethod
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		usual = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case 384:
+			usual = jwt.SigningMethodES384
+		case 521:
+			usual = jwt.SigningMethodES512
+		default:
+			usual = jwt.SigningMethodES256
+		}
+	case ed25519.PublicKey:
+		usual = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	if algorithm == "" {
+		return usual, nil
+	}
+	method := jwt.GetSigningMethod(algorithm)
+	suits := false
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, suits = key.(*rsa.PublicKey)
+	case *jwt.SigningMethodECDSA:
+		suits = method == usual
+	case *jwt.SigningMethodEd25519:
+		_, suits = key.(ed25519.PublicKey)
+	}
+	if !suits {
+		return nil, fmt.Errorf("algorithm %s does not suit a %T key", algorithm, key)
+	}
+	return method, nil
+}
+
+func newKey(public crypto.PublicKey, method jwt.SigningMethod) (Key, error) {
+	thumbprint, err := (&jose.JSONWebKey{Key: public}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return Key{}, fmt.Errorf("computing key id: %w", err)
+	}
+	return Key{ID: base64.RawURLEncoding.EncodeToString(thumbprint), Method: method, Public: public}, nil
+}
+
+func GetPrivateKey() crypto.PrivateKey {
 	return privateKey
 }
 
-func GetPublicKey() *rsa.PublicKey {
+func GetPublicKey() crypto.PublicKey {
 	return publicKey
 }
 
@@ -61,10 +194,38 @@ func GetSigningMethod() jwt.SigningMethod {
 	return signingMethod
 }
 
+// GetKeyID returns the id of the signing key, set as the kid header of the tokens
+func GetKeyID() string {
+	return keyID
+}
+
+// GetKey returns the key a token with the kid header is verified with
+func GetKey(id string) (Key, bool) {
+	key, ok := keys[id]
+	return key, ok
+}
+
+// GetKeys returns the signing key first and then the verify only ones
+func GetKeys() []Key {
+	list := []Key{}
+	for id, key := range keys {
+		if id != keyID {
+			list = append(list, key)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	if signing, ok := keys[keyID]; ok {
+		list = append([]Key{signing}, list...)
+	}
+	return list
+}
+
 func Reset() {
 	mutex.Lock()
 	defer mutex.Unlock()
 	privateKey = nil
 	publicKey = nil
+	keyID = ""
+	keys = nil
 	once = sync.Once{}
 }
@@ -1,6 +1,13 @@
 package jwt_key
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 	jwt_key_mock "via/internal/jwt/mock"
 	"via/internal/secret"
@@ -50,6 +57,8 @@ func TestJWTKey_Reset(t *testing.T) {
 	Reset()
 	assert.Nil(t, GetPrivateKey())
 	assert.Nil(t, GetPublicKey())
+	assert.Empty(t, GetKeyID())
+	assert.Empty(t, GetKeys())
 }
 
 func TestJWTKey_Init_PrivateKeyInvalid(t *testing.T) {
@@ -88,3 +97,112 @@ func TestJWTKey_Init_PublicKeyInvalid(t *testing.T) {
 	assert.Nil(t, GetPublicKey())
 	assert.Nil(t, GetPrivateKey())
 }
+
+func pemKeys(t *testing.T, private crypto.PrivateKey, public crypto.PublicKey) (string, string) {
+	t.Helper()
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(private)
+	assert.NoError(t, err)
+	publicBytes, err := x509.MarshalPKIXPublicKey(public)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}))
+}
+
+func TestJWTKey_Init_KeyTypes(t *testing.T) {
+	ec256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ec384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	ec521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name      string
+		private   crypto.PrivateKey
+		public    crypto.PublicKey
+		algorithm string
+		expected  jwt.SigningMethod
+		expectErr bool
+	}{
+		{name: "ES256", private: ec256, public: ec256.Public(), expected: jwt.SigningMethodES256},
+		{name: "ES384", private: ec384, public: ec384.Public(), expected: jwt.SigningMethodES384},
+		{name: "ES512", private: ec521, public: ec521.Public(), expected: jwt.SigningMethodES512},
+		{name: "EdDSA", private: edPrivate, public: edPublic, expected: jwt.SigningMethodEdDSA},
+		{name: "ECDSA algorithm of another curve", private: ec256, public: ec256.Public(), algorithm: "ES384", expectErr: true},
+		{name: "RSA algorithm for an EdDSA key", private: edPrivate, public: edPublic, algorithm: "RS256", expectErr: true},
+		{name: "EdDSA algorithm", private: edPrivate, public: edPublic, algorithm: "EdDSA", expected: jwt.SigningMethodEdDSA},
+		{name: "unknown algorithm", private: ec256, public: ec256.Public(), algorithm: "XX256", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Reset()
+			privatePEM, publicPEM := pemKeys(t, tt.private, tt.public)
+
+			err := Init(JWTConfig{PrivateKey: privatePEM, PublicKey: publicPEM, Algorithm: tt.algorithm})
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, GetSigningMethod())
+			token, err := jwt.New(GetSigningMethod()).SignedString(GetPrivateKey())
+			assert.NoError(t, err)
+			_, err = jwt.Parse(token, func(*jwt.Token) (any, error) { return GetPublicKey(), nil })
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestJWTKey_Init_RSAAlgorithm(t *testing.T) {
+	Reset()
+	err := Init(JWTConfig{PrivateKey: jwt_key_mock.GetPrivateKey(), PublicKey: jwt_key_mock.GetPublicKey(), Algorithm: "PS512"})
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodPS512, GetSigningMethod())
+}
+
+func TestJWTKey_Init_VerifyKeys(t *testing.T) {
+	Reset()
+	retired, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, retiredPEM := pemKeys(t, retired, retired.Public())
+	mockSecret := new(mock_secret.MockSecret)
+	mockSecret.On("Read", "retired.pem").Return(retiredPEM)
+	secret.Set(mockSecret)
+
+	err := Init(JWTConfig{
+		PrivateKey:           jwt_key_mock.GetPrivateKey(),
+		PublicKey:            jwt_key_mock.GetPublicKey(),
+		VerifyKeySecretFiles: []string{"retired.pem"},
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, GetKeyID())
+	keys := GetKeys()
+	assert.Len(t, keys, 2)
+	assert.Equal(t, GetKeyID(), keys[0].ID, "the signing key is listed first")
+	assert.Equal(t, jwt.SigningMethodES256, keys[1].Method)
+	key, ok := GetKey(keys[1].ID)
+	assert.True(t, ok)
+	assert.Equal(t, retired.Public(), key.Public)
+	_, ok = GetKey("unknown")
+	assert.False(t, ok)
+}
+
+func TestJWTKey_Init_VerifyKeyInvalid(t *testing.T) {
+	Reset()
+	mockSecret := new(mock_secret.MockSecret)
+	mockSecret.On("Read", "retired.pem").Return("INVALID")
+	secret.Set(mockSecret)
+
+	err := Init(JWTConfig{
+		PrivateKey:           jwt_key_mock.GetPrivateKey(),
+		PublicKey:            jwt_key_mock.GetPublicKey(),
+		VerifyKeySecretFiles: []string{"retired.pem"},
+	})
+
+	assert.ErrorContains(t, err, "verify key retired.pem")
+}
+
+func TestMethodFor_UnsupportedKey(t *testing.T) {
+	_, err := methodFor("key", "")
+	assert.EqualError(t, err, "unsupported key type string")
+}
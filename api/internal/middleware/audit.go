@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	biz_audit "via/internal/biz/audit"
+	"via/internal/global"
+	"via/internal/model"
+)
+
+// RecordAudit appends the event to the audit log with the request id, the client IP and, when the event
+// has none, the operator or device of the request as its actor
+func RecordAudit(r *http.Request, event biz_audit.Event) {
+	if event.Actor == "" {
+		event.Actor = AuditActor(r)
+	}
+	event.RequestID, _ = r.Context().Value(global.RequestIDKey).(string)
+	event.IP = r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		event.IP = host
+	}
+	biz_audit.Record(r.Context(), event)
+}
+
+// AuditActor identifies the operator or device of the request, anonymous when it has none
+func AuditActor(r *http.Request) string {
+	if operatorId, ok := r.Context().Value(OperatorIDKey).(int); ok {
+		return OperatorActor(operatorId)
+	}
+	if device, ok := r.Context().Value(DeviceKey).(model.Device); ok {
+		return "device:" + strconv.Itoa(device.ID)
+	}
+	return "anonymous"
+}
+
+func OperatorActor(operatorId int) string {
+	return "operator:" + strconv.Itoa(operatorId)
+}
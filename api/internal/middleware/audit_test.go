@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	biz_audit "via/internal/biz/audit"
+	"via/internal/global"
+	"via/internal/model"
+	audit_provider "via/internal/provider/audit"
+	mock_audit_provider "via/internal/provider/audit/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecordAudit(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	tests := []struct {
+		name        string
+		ctx         func(context.Context) context.Context
+		remoteAddr  string
+		actor       string
+		expectActor string
+		expectIP    string
+	}{
+		{name: "operator request", remoteAddr: "10.0.0.1:5432", expectActor: "operator:42", expectIP: "10.0.0.1",
+			ctx: func(ctx context.Context) context.Context { return context.WithValue(ctx, OperatorIDKey, 42) }},
+		{name: "device request", remoteAddr: "10.0.0.2:5432", expectActor: "device:4", expectIP: "10.0.0.2",
+			ctx: func(ctx context.Context) context.Context {
+				return context.WithValue(ctx, DeviceKey, model.Device{ID: 4})
+			}},
+		{name: "anonymous request", remoteAddr: "10.0.0.3", expectActor: "anonymous", expectIP: "10.0.0.3",
+			ctx: func(ctx context.Context) context.Context { return ctx }},
+		{name: "actor of the event", remoteAddr: "10.0.0.1:5432", actor: "operator:7", expectActor: "operator:7",
+			expectIP: "10.0.0.1",
+			ctx:      func(ctx context.Context) context.Context { return context.WithValue(ctx, OperatorIDKey, 42) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_audit_provider.MockAuditProvider)
+			audit_provider.Set(mockProvider)
+			mockProvider.On("AppendAuditEntry", mock.Anything, mock.MatchedBy(func(entry model.AuditEntry) bool {
+				return entry.Actor == tt.expectActor && entry.IP == tt.expectIP && entry.RequestID == "req-1" &&
+					entry.Action == biz_audit.SESSION_REVOKE
+			})).Return(model.AuditEntry{}, nil).Once()
+			req := httptest.NewRequest(http.MethodPost, "/session/revoke", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req = req.WithContext(tt.ctx(context.WithValue(req.Context(), global.RequestIDKey, "req-1")))
+
+			RecordAudit(req, biz_audit.Event{Actor: tt.actor, Action: biz_audit.SESSION_REVOKE})
+
+			mockProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOperatorActor(t *testing.T) {
+	assert.Equal(t, "operator:42", OperatorActor(42))
+}
@@ -5,21 +5,23 @@ import (
 	"net/http"
 	"via/internal/auth"
 	"via/internal/i18n"
+	"via/internal/idp"
 	"via/internal/log"
 	"via/internal/response"
-
-	"github.com/coreos/go-oidc/v3/oidc"
+	"via/internal/session"
 )
 
 type OperatorIDKeyType string
 
 const OperatorIDKey OperatorIDKeyType = "operatorId"
+const OperatorRoleKey OperatorIDKeyType = "operatorRole"
+const SessionIDKey OperatorIDKeyType = "sessionId"
 
-func Auth(cfg auth.OAuthConfig) func(http.Handler) http.Handler {
+func Auth() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			res := response.Response[any]{
-				Message: i18n.Get(r, i18n.MsgOperatorUnauthorized),
+				Error: i18n.Error(r, i18n.MsgOperatorUnauthorized),
 			}
 			token, err := auth.GetAuthToken(r)
 			if err != nil {
@@ -33,31 +35,86 @@ func Auth(cfg auth.OAuthConfig) func(http.Handler) http.Handler {
 				response.WriteJSON(w, r, res, http.StatusUnauthorized)
 				return
 			}
-
-			if ok, err := verifiIdTokenFunc(r.Context(), claims.IDPIDToken, cfg.ClientID, cfg.IDPIssuer); err != nil || !ok {
-				log.Get().Error(r.Context(), err, "msg", "failed to verify IDP token")
+			// a revoked session or an access token replaced by a renewal is rejected before it expires
+			active, err := session.Get().IsActive(r.Context(), claims.SessionID, claims.ID)
+			if err != nil {
+				log.Get().Error(r.Context(), err, "msg", "failed to check session", "session_id", claims.SessionID)
+				response.WriteJSON(w, r, response.Response[any]{
+					Error: i18n.Error(r, i18n.MsgInternalServerError),
+				}, http.StatusInternalServerError)
+				return
+			}
+			if !active {
+				log.Get().Warn(r.Context(), "msg", "session not active", "session_id", claims.SessionID,
+					"operator_id", claims.OperatorID)
 				response.WriteJSON(w, r, res, http.StatusUnauthorized)
 				return
 			}
 
-			r = r.WithContext(context.WithValue(r.Context(), OperatorIDKey, claims.OperatorID))
+			// the local login sessions have no IdP token to verify
+			if claims.GetProvider() != auth.LocalProvider {
+				if err := verifiIdTokenFunc(r.Context(), claims.GetProvider(), claims.IDPIDToken); err != nil {
+					log.Get().Error(r.Context(), err, "msg", "failed to verify IDP token")
+					response.WriteJSON(w, r, res, http.StatusUnauthorized)
+					return
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), OperatorIDKey, claims.OperatorID)
+			ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
+			r = r.WithContext(context.WithValue(ctx, OperatorRoleKey, claims.Role))
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-var verifiIdTokenFunc = verifiIdToken
-
-func verifiIdToken(ctx context.Context, idToken, clientID, issuer string) (bool, error) {
-	provider, err := oidc.NewProvider(ctx, issuer)
-	if err != nil {
-		log.Get().Error(ctx, err, "msg", "failed to create OIDC provider")
-		return false, err
+// RenewSession signs a new access token from the refresh token cookie when the access one expired,
+// so long lived connections reconnect without sending the operator back to the IdP. It must run before Auth.
+func RenewSession(cfg auth.OAuthConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token, err := auth.GetAuthToken(r); err == nil {
+				if _, _, err := auth.ParseTokenWithClaims(token); err == nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			refreshToken, err := auth.GetRefreshToken(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			accessToken, err := auth.Get().Renew(r.Context(), w, refreshToken, cfg)
+			if err != nil {
+				log.Get().Warn(r.Context(), "msg", "unable to renew session", "error", err.Error())
+				next.ServeHTTP(w, r)
+				return
+			}
+			log.Get().Info(r.Context(), "msg", "session renewed")
+			next.ServeHTTP(w, withAuthToken(r, accessToken))
+		})
 	}
-	verifier := provider.Verifier(&oidc.Config{ClientID: clientID})
-	_, err = verifier.Verify(ctx, idToken)
-	if err != nil {
-		return false, err
+}
+
+// withAuthToken replaces the access token cookie of the request
+func withAuthToken(r *http.Request, token string) *http.Request {
+	cookies := r.Cookies()
+	r = r.Clone(r.Context())
+	r.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != auth.AuthTokenKey {
+			r.AddCookie(cookie)
+		}
 	}
-	return true, nil
+	r.AddCookie(&http.Cookie{Name: auth.AuthTokenKey, Value: token})
+	return r
+}
+
+var verifiIdTokenFunc = verifiIdToken
+
+// verifiIdToken checks the IdP token with the cached signing keys of its provider, no request
+// reaches the IdP while they are fresh
+func verifiIdToken(ctx context.Context, provider string, idToken string) error {
+	_, err := idp.Get().Verify(ctx, provider, idToken)
+	return err
 }
@@ -7,10 +7,15 @@ import (
 	"net/http/httptest"
 	"testing"
 	"via/internal/auth"
+	mock_ds "via/internal/ds/mock"
 	"via/internal/model"
+	operator_provider "via/internal/provider/operator"
+	mock_operator_provider "via/internal/provider/operator/mock"
+	"via/internal/session"
 	"via/internal/testutil"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestAuthMiddleware(t *testing.T) {
@@ -19,17 +24,20 @@ func TestAuthMiddleware(t *testing.T) {
 	type testCase struct {
 		name                 string
 		token                string
-		verifyFunc           func(ctx context.Context, idToken, clientID, issuer string) (bool, error)
+		session              string
+		sessionErr           error
+		verifyFunc           func(ctx context.Context, provider string, idToken string) error
 		expectedStatus       int
 		expectNextHandlerRun bool
 	}
 
-	cfg := auth.OAuthConfig{
-		ClientID:  "client-id",
-		IDPIssuer: "https://issuer",
-	}
-
-	validToken, _ := auth.GenerateAuthToken(model.Operator{ID: 5}, "idp-token", auth.OAuthConfig{JWTExpirationInSeconds: 10})
+	s := session.Session{Session: model.Session{ID: "s1", OperatorID: 5}, JTI: "j1", IDPIDToken: "idp-token"}
+	validToken, _ := auth.GenerateAuthToken(model.Operator{ID: 5, Role: "supervisor"}, s, auth.OAuthConfig{JWTExpirationInSeconds: 10})
+	activeSession := `{"id":"s1","operatorId":5,"jti":"j1"}`
+	local := s
+	local.Provider = auth.LocalProvider
+	local.IDPIDToken = ""
+	localToken, _ := auth.GenerateAuthToken(model.Operator{ID: 5, Role: "supervisor"}, local, auth.OAuthConfig{JWTExpirationInSeconds: 10})
 
 	cases := []testCase{
 		{
@@ -42,20 +50,50 @@ func TestAuthMiddleware(t *testing.T) {
 			token:          "invalid",
 			expectedStatus: http.StatusUnauthorized,
 		},
+		{
+			name:           "revoked session",
+			token:          validToken,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "access token replaced by a renewal",
+			token:          validToken,
+			session:        `{"id":"s1","operatorId":5,"jti":"j2"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "error checking session",
+			token:          validToken,
+			sessionErr:     errors.New("ds error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
 		{
 			name:           "id token verify fails",
 			token:          validToken,
+			session:        activeSession,
 			expectedStatus: http.StatusUnauthorized,
-			verifyFunc: func(ctx context.Context, idToken string, clientID string, issuer string) (bool, error) {
-				return false, errors.New("verify error")
+			verifyFunc: func(ctx context.Context, provider string, idToken string) error {
+				return errors.New("verify error")
 			},
 		},
 		{
 			name:           "success",
 			token:          validToken,
+			session:        activeSession,
 			expectedStatus: http.StatusOK,
-			verifyFunc: func(ctx context.Context, idToken string, clientID string, issuer string) (bool, error) {
-				return true, nil
+			verifyFunc: func(ctx context.Context, provider string, idToken string) error {
+				assert.Equal(t, auth.DefaultProvider, provider)
+				return nil
+			},
+			expectNextHandlerRun: true,
+		},
+		{
+			name:           "local login session has no IdP token",
+			token:          localToken,
+			session:        activeSession,
+			expectedStatus: http.StatusOK,
+			verifyFunc: func(ctx context.Context, provider string, idToken string) error {
+				return errors.New("not verified")
 			},
 			expectNextHandlerRun: true,
 		},
@@ -66,6 +104,9 @@ func TestAuthMiddleware(t *testing.T) {
 			if tc.verifyFunc != nil {
 				verifiIdTokenFunc = tc.verifyFunc
 			}
+			mockDS := new(mock_ds.MockDS)
+			session.Set(session.New(mockDS, 7200))
+			mockDS.On("Get", mock.Anything, "session:s1").Return(tc.session != "", tc.session, tc.sessionErr)
 			req := httptest.NewRequest(http.MethodGet, "/", nil)
 			if tc.token != "" {
 				req.AddCookie(&http.Cookie{
@@ -82,10 +123,12 @@ func TestAuthMiddleware(t *testing.T) {
 				val := r.Context().Value(OperatorIDKey)
 				if tc.expectNextHandlerRun {
 					assert.Equal(t, 5, val)
+					assert.Equal(t, "supervisor", r.Context().Value(OperatorRoleKey))
+					assert.Equal(t, "s1", r.Context().Value(SessionIDKey))
 				}
 			})
 
-			handler := Auth(cfg)(next)
+			handler := Auth()(next)
 			handler.ServeHTTP(rr, req)
 
 			assert.Equal(t, tc.expectedStatus, rr.Code)
@@ -93,3 +136,81 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRenewSession(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectMockJWTKey()
+	cfg := auth.OAuthConfig{JWTExpirationInSeconds: 3600, JWTRefreshExpirationInSeconds: 7200}
+	s := session.Session{Session: model.Session{ID: "s1", OperatorID: 5}, JTI: "j1", IDPIDToken: "idp-token"}
+	validToken, _ := auth.GenerateAuthToken(model.Operator{ID: 5}, s, cfg)
+	expiredToken, _ := auth.GenerateAuthToken(model.Operator{ID: 5}, s, auth.OAuthConfig{JWTExpirationInSeconds: -10})
+	stored := `{"id":"s1","operatorId":5,"jti":"j1","idpIdToken":"idp-token"}`
+
+	tests := []struct {
+		name          string
+		accessToken   string
+		refreshToken  string
+		renew         bool
+		renewed       bool
+		expectRenewed bool
+	}{
+		{name: "valid access token", accessToken: validToken, refreshToken: "refresh"},
+		{name: "no refresh token", accessToken: expiredToken},
+		{name: "expired access token", accessToken: expiredToken, refreshToken: "refresh", renew: true, renewed: true,
+			expectRenewed: true},
+		{name: "missing access token", refreshToken: "refresh", renew: true, renewed: true, expectRenewed: true},
+		{name: "refresh token not renewed", refreshToken: "refresh", renew: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			auth.Set(auth.New(auth.OAuthConfig{ClientSecret: "sec", JWTRefreshExpirationInSeconds: 7200}, mockDS))
+			session.Set(session.New(mockDS, 7200))
+			mockOperator := new(mock_operator_provider.MockOperatorProvider)
+			operator_provider.Set(mockOperator)
+			if tt.renew && !tt.renewed {
+				mockDS.On("Get", mock.Anything, mock.Anything).Return(false, "", nil).Once()
+			}
+			if tt.renewed {
+				mockDS.On("Get", mock.Anything, mock.Anything).Return(true, "s1", nil).Once()
+				mockDS.On("SetNX", mock.Anything, mock.Anything, "s1", 7200).Return(true, nil).Once()
+				mockDS.On("Get", mock.Anything, "session:s1").Return(true, stored, nil).Once()
+				mockDS.On("Set", mock.Anything, mock.Anything, mock.Anything, 7200).Return(nil).Twice()
+				mockDS.On("SAdd", mock.Anything, "sessions:operator:5:ids", "s1", 7200).Return(nil).Once()
+				mockOperator.On("GetOperatorById", mock.Anything, 5).Return(model.Operator{ID: 5, Enabled: true}, nil).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/operator/guides", nil)
+			req.AddCookie(&http.Cookie{Name: "lang", Value: "es"})
+			if tt.accessToken != "" {
+				req.AddCookie(&http.Cookie{Name: auth.AuthTokenKey, Value: tt.accessToken})
+			}
+			if tt.refreshToken != "" {
+				req.AddCookie(&http.Cookie{Name: auth.RefreshTokenKey, Value: tt.refreshToken})
+			}
+			rr := httptest.NewRecorder()
+			var seen string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen, _ = auth.GetAuthToken(r)
+				_, err := r.Cookie("lang")
+				assert.NoError(t, err, "other cookies are kept")
+			})
+
+			RenewSession(cfg)(next).ServeHTTP(rr, req)
+
+			if tt.expectRenewed {
+				assert.NotEqual(t, tt.accessToken, seen)
+				claims, _, err := auth.ParseTokenWithClaims(seen)
+				assert.NoError(t, err)
+				assert.Equal(t, 5, claims.OperatorID)
+				assert.Len(t, rr.Result().Cookies(), 2)
+			} else {
+				assert.Equal(t, tt.accessToken, seen)
+				assert.Empty(t, rr.Result().Cookies())
+			}
+			mockDS.AssertExpectations(t)
+			mockOperator.AssertExpectations(t)
+		})
+	}
+}
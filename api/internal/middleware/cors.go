@@ -10,14 +10,14 @@ type CORSCfg struct {
 	Enabled bool   `env:"ENABLED" envDefault:"true" json:"enabled"`
 	Origins string `env:"ORIGINS" envDefault:"*" json:"origins"`
 	Methods string `env:"METHODS" envDefault:"GET,POST,PUT,PATCH,DELETE,OPTIONS" json:"methods"`
-	Headers string `env:"HEADERS" envDefault:"Content-Type,Authorization,bypass-tunnel-reminder,Accept-Language" json:"headers"`
+	Headers string `env:"HEADERS" envDefault:"Content-Type,Authorization,bypass-tunnel-reminder,Accept-Language,x-device-token" json:"headers"`
 }
 
 func CORS(cfg CORSCfg) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			logger := log.Get()
-			r = logger.WithLogFieldsInRequest(r, "method", r.Method, "uri", r.RequestURI, "proto", r.Proto)
+			r = logger.WithLogFieldsInRequest(r, "method", r.Method, "path", r.URL.Path, "proto", r.Proto)
 			logger.Info(r.Context(), "msg", "CORS middleware invoked")
 			origin := r.Header.Get("Origin")
 			if cfg.Origins == "*" {
@@ -95,4 +95,16 @@ func TestCORSMiddleware(t *testing.T) {
 		assert.Equal(t, "", res.Header.Get("Access-Control-Allow-Methods"))
 		assert.Equal(t, "", res.Header.Get("Access-Control-Allow-Headers"))
 	})
+
+	t.Run("query string is not logged", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/guides/search?recipient=JUAN+PEREZ", nil)
+		rec := httptest.NewRecorder()
+		fields := []any{"method", http.MethodGet, "path", "/guides/search", "proto", req.Proto}
+		mockLog.On("WithLogFieldsInRequest", req, fields).Return(req)
+		mockLog.On("Info", req.Context(), mock.Anything)
+
+		CORS(CORSCfg{Origins: "*"})(http.HandlerFunc(testHandler)).ServeHTTP(rec, req)
+
+		mockLog.AssertCalled(t, "WithLogFieldsInRequest", req, fields)
+	})
 }
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"via/internal/auth"
+	biz_device "via/internal/biz/device"
+	"via/internal/global"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/model"
+	"via/internal/pubsub"
+	"via/internal/response"
+)
+
+type DeviceKeyType string
+
+const DeviceKey DeviceKeyType = "device"
+
+const DeviceTokenHeader = "x-device-token"
+
+// DeviceMiddleware accepts the paired devices of a kind bound to the branch
+type DeviceMiddleware struct {
+	Cfg    biz_device.DeviceCfg
+	Kind   string
+	Branch string
+}
+
+// Device identifies the paired kiosk or monitor by its token, sent in the header or the cookie set
+// when paired; requests without a token are only accepted when the token is not required. The
+// rejected tokens count as failures for the Lockout mounted before it.
+func Device(dm DeviceMiddleware) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get(DeviceTokenHeader)
+			if token == "" {
+				token, _ = auth.GetDeviceToken(r)
+			}
+			if token == "" {
+				if dm.Cfg.Required {
+					log.Get().Warn(r.Context(), "msg", "missing device token")
+					response.WriteJSON(w, r, response.Response[any]{
+						Error: i18n.Error(r, i18n.MsgDeviceUnauthorized)}, http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			device, ok, err := biz_device.Authenticate(r.Context(), token)
+			if err != nil {
+				log.Get().Error(r.Context(), err, "msg", "failed to authenticate device")
+				response.WriteJSON(w, r, response.Response[any]{
+					Error: i18n.Error(r, i18n.MsgInternalServerError)}, http.StatusInternalServerError)
+				return
+			}
+			if !ok || device.Kind != dm.Kind || device.Branch != dm.Branch {
+				log.Get().Warn(r.Context(), "msg", "device not allowed", "device_id", device.ID,
+					"kind", device.Kind, "branch", device.Branch)
+				RegisterLockoutFailure(r)
+				response.WriteJSON(w, r, response.Response[any]{
+					Error: i18n.Error(r, i18n.MsgDeviceUnauthorized)}, http.StatusUnauthorized)
+				return
+			}
+			r = log.Get().WithLogFieldsInRequest(r, "device_id", device.ID)
+			r = r.WithContext(context.WithValue(r.Context(), DeviceKey, device))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DeviceRevocation ends the request as soon as its device is revoked, it must run after Device and
+// keeps the event streams of a revoked monitor from going on
+func DeviceRevocation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		device, ok := r.Context().Value(DeviceKey).(model.Device)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		sub, err := pubsub.Get().Subscribe(r.Context(), global.DeviceRevokedChannel)
+		if err != nil {
+			// the device was just authenticated, it is revoked on its next connection
+			log.Get().Error(r.Context(), err, "msg", "unable to watch device revocation")
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, cancel := context.WithCancel(r.Context())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				select {
+				case msg, ok := <-sub.Channel():
+					if !ok {
+						return
+					}
+					if id, _ := msg.Payload.(string); id == strconv.Itoa(device.ID) {
+						log.Get().Warn(ctx, "msg", "device revoked, closing the request")
+						cancel()
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		defer func() {
+			cancel()
+			<-done
+			sub.Close()
+		}()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"via/internal/auth"
+	biz_device "via/internal/biz/device"
+	biz_language "via/internal/biz/language"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/global"
+	"via/internal/i18n"
+	"via/internal/model"
+	device_provider "via/internal/provider/device"
+	mock_device_provider "via/internal/provider/device/mock"
+	"via/internal/pubsub"
+	mock_pubsub "via/internal/pubsub/mock"
+	"via/internal/ratelimit"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// deviceToken is the token of device 4, whose stored hash is the sha256 of "secret"
+const deviceToken = "4.secret"
+const deviceTokenHash = "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"
+
+func TestDeviceMiddleware(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	kiosk := model.Device{ID: 4, Kind: biz_device.KIND_KIOSK, Branch: "123"}
+
+	tests := []struct {
+		name         string
+		required     bool
+		header       string
+		cookie       string
+		device       model.Device
+		getErr       error
+		expectedCode int
+		expectedMsg  string
+		expectDevice bool
+	}{
+		{name: "token not required and missing", expectedCode: http.StatusOK},
+		{name: "token required and missing", required: true, expectedCode: http.StatusUnauthorized,
+			expectedMsg: i18n.MsgDeviceUnauthorized},
+		{name: "valid header token", required: true, header: deviceToken, device: kiosk,
+			expectedCode: http.StatusOK, expectDevice: true},
+		{name: "valid cookie token", required: true, cookie: deviceToken, device: kiosk,
+			expectedCode: http.StatusOK, expectDevice: true},
+		{name: "invalid token", header: "4.other", device: kiosk, expectedCode: http.StatusUnauthorized,
+			expectedMsg: i18n.MsgDeviceUnauthorized},
+		{name: "device of another kind", header: deviceToken,
+			device:       model.Device{ID: 4, Kind: biz_device.KIND_MONITOR, Branch: "123"},
+			expectedCode: http.StatusUnauthorized, expectedMsg: i18n.MsgDeviceUnauthorized},
+		{name: "device of another branch", header: deviceToken,
+			device:       model.Device{ID: 4, Kind: biz_device.KIND_KIOSK, Branch: "456"},
+			expectedCode: http.StatusUnauthorized, expectedMsg: i18n.MsgDeviceUnauthorized},
+		{name: "lookup error", header: deviceToken, getErr: errors.New("db error"),
+			expectedCode: http.StatusInternalServerError, expectedMsg: i18n.MsgInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(mock_device_provider.MockDeviceProvider)
+			device_provider.Set(mockProvider)
+			mockProvider.On("GetDeviceCredentials", mock.Anything, 4).
+				Return(tt.device, model.DeviceCredentials{TokenHash: deviceTokenHash}, tt.getErr)
+			var device model.Device
+			handler := Device(DeviceMiddleware{
+				Cfg:    biz_device.DeviceCfg{Required: tt.required},
+				Kind:   biz_device.KIND_KIOSK,
+				Branch: "123",
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				device, _ = r.Context().Value(DeviceKey).(model.Device)
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/guide-to-withdraw/123456789012", nil)
+			if tt.header != "" {
+				req.Header.Set(DeviceTokenHeader, tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: auth.DeviceTokenKey, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectDevice {
+				assert.Equal(t, tt.device, device)
+			} else {
+				assert.Empty(t, device)
+			}
+			if tt.expectedMsg != "" {
+				assert.Contains(t, rec.Body.String(), i18n.GetWithLang(biz_language.DEFAULT, tt.expectedMsg))
+			}
+		})
+	}
+}
+
+func TestDeviceMiddleware_Lockout(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mockProvider := new(mock_device_provider.MockDeviceProvider)
+	device_provider.Set(mockProvider)
+	mockProvider.On("GetDeviceCredentials", mock.Anything, 4).
+		Return(model.Device{ID: 4, Kind: biz_device.KIND_KIOSK, Branch: "123"},
+			model.DeviceCredentials{TokenHash: deviceTokenHash}, nil)
+	lockoutCfg := ratelimit.LockoutCfg{MaxFailures: 2, FailureWindow: 60, BaseLockout: 10, MaxLockout: 60}
+	mockDS := new(mock_ds.MockDS)
+	mockDS.On("Get", mock.Anything, "lockout:lock:ip").Return(false, "", nil)
+	mockDS.On("IncrExpire", mock.Anything, "lockout:failures:ip", lockoutCfg.FailureWindow).Return(1, nil).Once()
+
+	handler := Lockout(LockoutMiddleware{
+		Lockout:   ratelimit.NewLockout("lockout", lockoutCfg, mockDS),
+		KeyGetter: func(r *http.Request) string { return "ip" },
+	})(Device(DeviceMiddleware{Kind: biz_device.KIND_KIOSK, Branch: "123"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})))
+	for _, token := range []string{deviceToken, "4.other"} {
+		req := httptest.NewRequest(http.MethodGet, "/guide-to-withdraw/123456789012", nil)
+		req.Header.Set(DeviceTokenHeader, token)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// only the rejected token counts as a failure
+	mockDS.AssertNumberOfCalls(t, "IncrExpire", 1)
+}
+
+func TestDeviceRevocation(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	monitor := model.Device{ID: 4, Kind: biz_device.KIND_MONITOR}
+
+	// serve runs the request until the handler sees its context done or the wait elapses
+	serve := func(ctx context.Context) bool {
+		ended := false
+		handler := DeviceRevocation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+				ended = true
+			case <-time.After(200 * time.Millisecond):
+			}
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/monitor/events", nil).WithContext(ctx)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return ended
+	}
+	subscribe := func(messages ...pubsub.Message) *mock_pubsub.MockSubscription {
+		ch := make(chan pubsub.Message, len(messages))
+		for _, msg := range messages {
+			ch <- msg
+		}
+		sub := new(mock_pubsub.MockSubscription)
+		sub.On("Channel").Return((<-chan pubsub.Message)(ch))
+		sub.On("Close").Return(nil)
+		mockPubSub := new(mock_pubsub.MockPubSub)
+		mockPubSub.On("Subscribe", mock.Anything, global.DeviceRevokedChannel).Return(sub, nil)
+		pubsub.Set(mockPubSub)
+		return sub
+	}
+	withDevice := context.WithValue(context.Background(), DeviceKey, monitor)
+
+	t.Run("revoked device ends the request", func(t *testing.T) {
+		sub := subscribe(pubsub.Message{Payload: "5"}, pubsub.Message{Payload: "4"})
+		assert.True(t, serve(withDevice))
+		sub.AssertCalled(t, "Close")
+	})
+
+	t.Run("other devices revoked", func(t *testing.T) {
+		subscribe(pubsub.Message{Payload: "5"})
+		assert.False(t, serve(withDevice))
+	})
+
+	t.Run("subscription closed", func(t *testing.T) {
+		ch := make(chan pubsub.Message)
+		close(ch)
+		sub := new(mock_pubsub.MockSubscription)
+		sub.On("Channel").Return((<-chan pubsub.Message)(ch))
+		sub.On("Close").Return(nil)
+		mockPubSub := new(mock_pubsub.MockPubSub)
+		mockPubSub.On("Subscribe", mock.Anything, global.DeviceRevokedChannel).Return(sub, nil)
+		pubsub.Set(mockPubSub)
+		assert.False(t, serve(withDevice))
+	})
+
+	t.Run("subscribe error keeps the request", func(t *testing.T) {
+		mockPubSub := new(mock_pubsub.MockPubSub)
+		mockPubSub.On("Subscribe", mock.Anything, global.DeviceRevokedChannel).Return(nil, errors.New("pubsub down"))
+		pubsub.Set(mockPubSub)
+		assert.False(t, serve(withDevice))
+	})
+
+	t.Run("request without device", func(t *testing.T) {
+		mockPubSub := new(mock_pubsub.MockPubSub)
+		pubsub.Set(mockPubSub)
+		assert.False(t, serve(context.Background()))
+		mockPubSub.AssertNotCalled(t, "Subscribe")
+	})
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/openapi"
+	"via/internal/response"
+)
+
+// ValidateRequest rejects the requests not matching the parameters and body of their operation in
+// the document, with the x-error-code of the operation and the failed fields. The body is read up to
+// maxBodyBytes. The routes missing in the document are let through for the router to answer.
+func ValidateRequest(doc *openapi.Document, maxBodyBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+			op, details, err := doc.Validate(r)
+			if op == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				log.Get().Warn(r.Context(), "msg", "request body too large", "operation", op.OperationID, "limit", tooLarge.Limit)
+				response.WriteJSON(w, r, response.Response[any]{
+					Error: i18n.Error(r, i18n.MsgRequestTooLarge),
+				}, http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				log.Get().Warn(r.Context(), "msg", "invalid request body", "operation", op.OperationID, "error", err.Error())
+				response.WriteJSON(w, r, response.Response[any]{
+					Error: i18n.Error(r, i18n.MsgBadRequest),
+				}, http.StatusBadRequest)
+				return
+			}
+			if len(details) > 0 {
+				code := op.ErrorCode
+				if code == "" {
+					code = i18n.MsgBadRequest
+				}
+				log.Get().Warn(r.Context(), "msg", "invalid request", "operation", op.OperationID, "details", details)
+				response.WriteJSON(w, r, response.Response[any]{
+					Error: i18n.Error(r, code, details...),
+				}, http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
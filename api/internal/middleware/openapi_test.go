@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"via/internal/i18n"
+	"via/internal/openapi"
+	"via/internal/response"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRequest(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	tests := []struct {
+		name            string
+		method          string
+		target          string
+		body            string
+		expectedStatus  int
+		expectedCode    string
+		expectedDetails []response.FieldError
+	}{
+		{name: "valid", method: http.MethodPut, target: "/guide/12/status", body: `{"status":"onHold"}`,
+			expectedStatus: http.StatusOK},
+		{name: "operation error code", method: http.MethodPut, target: "/guide/12/status", body: `{"status":"lost"}`,
+			expectedStatus: http.StatusBadRequest, expectedCode: i18n.MsgGuideInvalid,
+			expectedDetails: []response.FieldError{{Field: "status", Code: response.FIELD_INVALID}}},
+		{name: "default error code", method: http.MethodPut, target: "/operator/availability", body: `{"availability":"busy"}`,
+			expectedStatus: http.StatusBadRequest, expectedCode: i18n.MsgBadRequest,
+			expectedDetails: []response.FieldError{{Field: "availability", Code: response.FIELD_INVALID}}},
+		{name: "body not json", method: http.MethodPut, target: "/guide/12/status", body: `{`,
+			expectedStatus: http.StatusBadRequest, expectedCode: i18n.MsgBadRequest},
+		{name: "body too large", method: http.MethodPut, target: "/guide/12/status", body: `{"status":"onHold","reason":"` + strings.Repeat("a", 64) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge, expectedCode: i18n.MsgRequestTooLarge},
+		{name: "route not documented", method: http.MethodGet, target: "/unknown", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			body := ""
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				read, _ := io.ReadAll(r.Body)
+				body = string(read)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			ValidateRequest(openapi.Get(), 64)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.body, body)
+				return
+			}
+			var resp response.Response[any]
+			assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+			if assert.NotNil(t, resp.Error) {
+				assert.Equal(t, tt.expectedCode, resp.Error.Code)
+				assert.Equal(t, tt.expectedDetails, resp.Error.Details)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"via/internal/presence"
+)
+
+// Presence keeps the operator online while the request is being served, it must run after Auth
+func Presence(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operatorId, ok := r.Context().Value(OperatorIDKey).(int)
+		tracker := presence.Get()
+		if !ok || tracker == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, cancel := context.WithCancel(r.Context())
+		done := tracker.Track(ctx, operatorId)
+		defer func() {
+			cancel()
+			<-done
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"via/internal/ds"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/presence"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPresence(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	t.Cleanup(func() { presence.Set(nil) })
+
+	tests := []struct {
+		name        string
+		operatorId  any
+		tracked     bool
+		expectTrack bool
+	}{
+		{name: "tracks the operator while serving", operatorId: 7, tracked: true, expectTrack: true},
+		{name: "missing operator id", operatorId: nil, tracked: true},
+		{name: "presence not tracked", operatorId: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			ds.Set(mockDS)
+			presence.Set(nil)
+			if tt.tracked {
+				presence.Set(presence.New(presence.PresenceCfg{Heartbeat: 60, TTL: 90}))
+			}
+			if tt.expectTrack {
+				mockDS.On("SAdd", mock.Anything, "presence:operator:7:connections", mock.Anything, 90).Return(nil).Once()
+				mockDS.On("SRem", mock.Anything, "presence:operator:7:connections", mock.Anything).Return(nil).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/operator/guides", nil)
+			if tt.operatorId != nil {
+				req = req.WithContext(context.WithValue(req.Context(), OperatorIDKey, tt.operatorId))
+			}
+			rr := httptest.NewRecorder()
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			Presence(next).ServeHTTP(rr, req)
+
+			assert.True(t, called)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
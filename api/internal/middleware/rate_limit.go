@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"via/internal/i18n"
@@ -16,6 +18,22 @@ type RateLimitMiddleware struct {
 	KeyGetter   KeyGetter
 }
 
+type LockoutMiddleware struct {
+	Lockout   *ratelimit.Lockout
+	KeyGetter KeyGetter
+}
+
+type lockoutKeyType string
+
+const lockoutKey lockoutKeyType = "lockout"
+
+type lockoutTarget struct {
+	lockout *ratelimit.Lockout
+	key     string
+}
+
+var ErrEnumerationDetected = errors.New("enumeration pattern detected")
+
 func NewRateLimitMiddleware(rateLimiters map[string]RateLimitMiddleware) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -24,26 +42,93 @@ func NewRateLimitMiddleware(rateLimiters map[string]RateLimitMiddleware) func(ht
 				next.ServeHTTP(w, r)
 				return
 			}
+			if allow(w, r, rm) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
 
-			key := fmt.Sprintf("%s:%s", rm.RateLimiter.ID, rm.KeyGetter(r))
-			allowed, err := rm.RateLimiter.Allow(r.Context(), key)
+// RateLimit applies a single rate limiter to every request of the routes it is used on,
+// intended for routes with URL parameters.
+func RateLimit(rm RateLimitMiddleware) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if allow(w, r, rm) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+func allow(w http.ResponseWriter, r *http.Request, rm RateLimitMiddleware) bool {
+	key := fmt.Sprintf("%s:%s", rm.RateLimiter.ID, rm.KeyGetter(r))
+	allowed, err := rm.RateLimiter.Allow(r.Context(), key)
+	if err != nil {
+		log.Get().Error(r.Context(), err, "msg", "error checking rate limit",
+			"key", key, "id", rm.RateLimiter.ID)
+		response.WriteJSON(w, r,
+			response.Response[any]{Error: i18n.Error(r, i18n.MsgInternalServerError)}, http.StatusInternalServerError)
+		return false
+	}
+
+	if !allowed {
+		log.Get().Warn(r.Context(), "msg", "rate limit",
+			"key", key, "id", rm.RateLimiter.ID)
+		response.WriteJSON(w, r,
+			response.Response[any]{Error: i18n.Error(r, i18n.MsgTooManyRequestsError)}, http.StatusTooManyRequests)
+		return false
+	}
+	return true
+}
+
+// Lockout rejects requests from locked keys; handlers report failures with RegisterLockoutFailure.
+func Lockout(lm LockoutMiddleware) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := lm.KeyGetter(r)
+			locked, err := lm.Lockout.IsLocked(r.Context(), key)
 			if err != nil {
-				log.Get().Error(r.Context(), err, "msg", "error checking rate limit",
-					"key", key, "id", rm.RateLimiter.ID)
+				log.Get().Error(r.Context(), err, "msg", "error checking lockout",
+					"key", key, "id", lm.Lockout.ID)
 				response.WriteJSON(w, r,
-					response.Response[any]{Message: i18n.Get(r, i18n.MsgInternalServerError)}, http.StatusInternalServerError)
+					response.Response[any]{Error: i18n.Error(r, i18n.MsgInternalServerError)}, http.StatusInternalServerError)
 				return
 			}
-
-			if !allowed {
-				log.Get().Warn(r.Context(), "msg", "rate limit",
-					"key", key, "id", rm.RateLimiter.ID)
+			if locked {
+				log.Get().Warn(r.Context(), "msg", "locked out",
+					"key", key, "id", lm.Lockout.ID)
 				response.WriteJSON(w, r,
-					response.Response[any]{Message: i18n.Get(r, i18n.MsgTooManyRequestsError)}, http.StatusTooManyRequests)
+					response.Response[any]{Error: i18n.Error(r, i18n.MsgLockedOut)}, http.StatusTooManyRequests)
 				return
 			}
-
-			next.ServeHTTP(w, r)
+			ctx := context.WithValue(r.Context(), lockoutKey, lockoutTarget{lockout: lm.Lockout, key: key})
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// RegisterLockoutFailure counts a failure for the requester, it does nothing
+// when the route is not protected by a Lockout middleware.
+func RegisterLockoutFailure(r *http.Request) {
+	target, ok := r.Context().Value(lockoutKey).(lockoutTarget)
+	if !ok {
+		return
+	}
+	logger := log.Get()
+	level, err := target.lockout.RegisterFailure(r.Context(), target.key)
+	if err != nil {
+		logger.Error(r.Context(), err, "msg", "error registering lockout failure",
+			"key", target.key, "id", target.lockout.ID)
+		return
+	}
+	if level == 0 {
+		return
+	}
+	logger.Warn(r.Context(), "msg", "lockout applied", "key", target.key, "id", target.lockout.ID,
+		"level", level, "duration", target.lockout.Duration(level).String())
+	if target.lockout.IsAlert(level) {
+		logger.Error(r.Context(), ErrEnumerationDetected, "msg", "alert", "key", target.key,
+			"id", target.lockout.ID, "level", level)
+	}
+}
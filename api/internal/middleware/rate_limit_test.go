@@ -28,9 +28,9 @@ func TestRateLimitMiddleware(t *testing.T) {
 		expectBody   string
 	}
 	mockDS := new(mock_ds.MockDS)
-	mockDS.On("Incr", mock.Anything, mock.Anything).Return(0, errors.New("ds error")).Once()
-	mockDS.On("Incr", mock.Anything, mock.Anything).Return(2, nil).Once()
-	mockDS.On("Incr", mock.Anything, mock.Anything).Return(6, nil).Once()
+	mockDS.On("IncrExpire", mock.Anything, mock.Anything, 1).Return(0, errors.New("ds error")).Once()
+	mockDS.On("IncrExpire", mock.Anything, mock.Anything, 1).Return(2, nil).Once()
+	mockDS.On("IncrExpire", mock.Anything, mock.Anything, 1).Return(6, nil).Once()
 	rateLimit := RateLimitMiddleware{
 		RateLimiter: ratelimit.New("rateLimiter", 5, 1*time.Second, mockDS),
 		KeyGetter:   func(r *http.Request) string { return "key" },
@@ -85,3 +85,109 @@ func TestRateLimitMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimit(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mockDS := new(mock_ds.MockDS)
+	mockDS.On("IncrExpire", mock.Anything, "rateLimiter:key", 1).Return(2, nil).Once()
+	mockDS.On("IncrExpire", mock.Anything, "rateLimiter:key", 1).Return(6, nil).Once()
+	handler := RateLimit(RateLimitMiddleware{
+		RateLimiter: ratelimit.New("rateLimiter", 5, 1*time.Second, mockDS),
+		KeyGetter:   func(r *http.Request) string { return "key" },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, expectedCode := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/guide-to-withdraw/123456789012", nil))
+		assert.Equal(t, expectedCode, rec.Code)
+	}
+	mockDS.AssertExpectations(t)
+}
+
+func TestLockoutMiddleware(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	lockoutCfg := ratelimit.LockoutCfg{MaxFailures: 2, FailureWindow: 60, BaseLockout: 10, MaxLockout: 60,
+		LevelTTL: 300, AlertLevel: 1}
+
+	tests := []struct {
+		name          string
+		getFound      bool
+		getErr        error
+		failure       bool
+		failures      int
+		expectedCode  int
+		expectedBody  string
+		expectLockSet bool
+	}{
+		{
+			name:         "lockout check error",
+			getErr:       errors.New("ds error"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: i18n.GetWithLang(biz_language.DEFAULT, i18n.MsgInternalServerError),
+		},
+		{
+			name:         "locked out",
+			getFound:     true,
+			expectedCode: http.StatusTooManyRequests,
+			expectedBody: i18n.GetWithLang(biz_language.DEFAULT, i18n.MsgLockedOut),
+		},
+		{
+			name:         "allowed without failure",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "failure under limit",
+			failure:      true,
+			failures:     1,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:          "failure reaching limit locks key",
+			failure:       true,
+			failures:      2,
+			expectedCode:  http.StatusOK,
+			expectLockSet: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockDS.On("Get", mock.Anything, "lockout:lock:key").Return(tt.getFound, "", tt.getErr)
+			mockDS.On("IncrExpire", mock.Anything, "lockout:failures:key", 60).Return(tt.failures, nil)
+			mockDS.On("Incr", mock.Anything, "lockout:level:key").Return(1, nil)
+			mockDS.On("Expire", mock.Anything, "lockout:level:key", 300).Return(nil)
+			mockDS.On("Set", mock.Anything, "lockout:lock:key", "1", 10).Return(nil)
+			mockDS.On("Del", mock.Anything, "lockout:failures:key").Return(nil)
+
+			handler := Lockout(LockoutMiddleware{
+				Lockout:   ratelimit.NewLockout("lockout", lockoutCfg, mockDS),
+				KeyGetter: func(r *http.Request) string { return "key" },
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.failure {
+					RegisterLockoutFailure(r)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/guide-to-withdraw/123456789012", nil))
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			if tt.expectLockSet {
+				mockDS.AssertCalled(t, "Set", mock.Anything, "lockout:lock:key", "1", 10)
+			} else {
+				mockDS.AssertNotCalled(t, "Set", mock.Anything, "lockout:lock:key", "1", 10)
+			}
+		})
+	}
+}
+
+func TestRegisterLockoutFailure_WithoutLockout(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	assert.NotPanics(t, func() {
+		RegisterLockoutFailure(httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"via/internal/i18n"
 	"via/internal/log"
+	"via/internal/response"
 )
 
 func Recover(next http.Handler) http.Handler {
@@ -12,7 +14,8 @@ func Recover(next http.Handler) http.Handler {
 		defer func() {
 			if rec := recover(); rec != nil {
 				log.Get().Error(r.Context(), fmt.Errorf("%v", rec), "msg", "recovering from panic", "stack", string(debug.Stack()))
-				http.Error(w, "internal server error", http.StatusInternalServerError)
+				response.WriteJSONError(w, r, response.Response[any]{Error: i18n.Error(r, i18n.MsgInternalServerError)},
+					http.StatusInternalServerError)
 			}
 		}()
 		next.ServeHTTP(w, r)
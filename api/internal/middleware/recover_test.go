@@ -1,12 +1,15 @@
 package middleware_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"via/internal/i18n"
 	"via/internal/log"
 	mock_log "via/internal/log/mock"
 	"via/internal/middleware"
+	"via/internal/response"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,7 +33,9 @@ func TestRecoverMiddleware(t *testing.T) {
 
 	// Response verification
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	assert.Equal(t, "internal server error\n", rec.Body.String())
+	var res response.Response[any]
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	assert.Equal(t, i18n.MsgInternalServerError, res.Error.Code)
 
 	// Verify that the logger was called with the expected message
 	mockLog.AssertCalled(t, "Error", mock.Anything, mock.Anything, mock.Anything)
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	biz_audit "via/internal/biz/audit"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/i18n"
+	"via/internal/log"
+	"via/internal/response"
+)
+
+// RequireRole lets through operators granted the role, it must run after Auth
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			operatorRole, _ := r.Context().Value(OperatorRoleKey).(string)
+			if !biz_operator.HasRole(operatorRole, role) {
+				log.Get().Warn(r.Context(), "msg", "operator role not allowed",
+					"operator_id", r.Context().Value(OperatorIDKey), "role", operatorRole, "required_role", role)
+				RecordAudit(r, biz_audit.Event{Action: biz_audit.AUTH_FORBIDDEN, After: map[string]string{
+					"method": r.Method, "path": r.URL.Path, "role": operatorRole, "requiredRole": role}})
+				response.WriteJSON(w, r, response.Response[any]{
+					Error: i18n.Error(r, i18n.MsgOperatorForbidden),
+				}, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
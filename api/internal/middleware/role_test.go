@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	biz_operator "via/internal/biz/operator"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	testutil.InjectNoOpAuditProvider()
+
+	tests := []struct {
+		name           string
+		role           any
+		expectedStatus int
+	}{
+		{name: "missing role", role: nil, expectedStatus: http.StatusForbidden},
+		{name: "operator", role: biz_operator.ROLE_OPERATOR, expectedStatus: http.StatusForbidden},
+		{name: "supervisor", role: biz_operator.ROLE_SUPERVISOR, expectedStatus: http.StatusOK},
+		{name: "admin", role: biz_operator.ROLE_ADMIN, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.role != nil {
+				req = req.WithContext(context.WithValue(req.Context(), OperatorRoleKey, tt.role))
+			}
+			rr := httptest.NewRecorder()
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			RequireRole(biz_operator.ROLE_SUPERVISOR)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, called)
+		})
+	}
+}
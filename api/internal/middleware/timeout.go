@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
 	"maps"
 	"net/http"
 	"runtime/debug"
@@ -91,10 +92,12 @@ func Timeout(duration time.Duration) func(http.Handler) http.Handler {
 			go func() {
 				defer func() {
 					if rec := recover(); rec != nil {
-						log.Get().Error(r.Context(), rec.(error), "msg", "recovering from panic in timeout middleware",
+						log.Get().Error(r.Context(), fmt.Errorf("%v", rec), "msg", "recovering from panic in timeout middleware",
 							"stack", string(debug.Stack()))
-						http.Error(tw, "internal server error", http.StatusInternalServerError)
-						return
+						response.WriteJSONError(tw, r, response.Response[any]{Error: i18n.Error(r, i18n.MsgInternalServerError)},
+							http.StatusInternalServerError)
+						// the error is sent right away instead of after the timeout
+						close(done)
 					}
 				}()
 				next.ServeHTTP(tw, r)
@@ -120,7 +123,7 @@ func Timeout(duration time.Duration) func(http.Handler) http.Handler {
 				}
 				logger.WithLogFieldsInRequest(r, "status", statusCode)
 				logger.Error(r.Context(), err, "msg", i18n.GetWithLang("en", msg))
-				response.WriteJSONError(w, r, response.Response[any]{Message: i18n.Get(r, msg)}, statusCode)
+				response.WriteJSONError(w, r, response.Response[any]{Error: i18n.Error(r, msg)}, statusCode)
 			case <-done: // handler completed successfully
 				tw.copy(r)
 			}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"via/internal/i18n"
 	"via/internal/log"
 	mock_log "via/internal/log/mock"
+	"via/internal/response"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -178,3 +180,29 @@ func TestTimeoutWriter_Copy_SkipsWhenNotWritten(t *testing.T) {
 		t.Error("expected no response written when nothing was written")
 	}
 }
+
+func TestTimeoutMiddleware_Panic(t *testing.T) {
+	mockLogger := new(mock_log.MockLogger)
+	log.Set(mockLogger)
+	mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything).Return().Once()
+	mockLogger.On("WithLogFieldsInRequest", mock.Anything, []any{"status", http.StatusInternalServerError}).
+		Return(httptest.NewRequest(http.MethodGet, "/", nil)).Once()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("something went wrong")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	Timeout(time.Second)(handler).ServeHTTP(rec, req)
+
+	assert.Less(t, time.Since(start), time.Second, "the error is not held until the timeout")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	var res response.Response[any]
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	assert.Equal(t, i18n.MsgInternalServerError, res.Error.Code)
+	errArg, ok := mockLogger.Calls[0].Arguments.Get(1).(error)
+	assert.True(t, ok)
+	assert.EqualError(t, errArg, "something went wrong")
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry is an action of an operator, admin, device or admin command. Before and after hold the
+// changed values as json, the hash chains it to the previous entry
+type AuditEntry struct {
+	ID         int             `json:"id"`
+	Actor      string          `json:"actor"` // operator:<id>, device:<id> or cli
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType,omitempty"`
+	TargetID   string          `json:"targetId,omitempty"`
+	RequestID  string          `json:"requestId,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	PrevHash   string          `json:"prevHash,omitempty"`
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// AuditSearch filters an audit search, zero values do not filter. Newest entries first
+type AuditSearch struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time // created at or after
+	To         *time.Time // created before
+	BeforeID   int        // entries older than the last one of the previous page
+	Limit      int
+}
+
+// AuditVerification is the result of checking the hash chain of the audit log
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Entries  int    `json:"entries"`            // checked until the first broken one
+	BrokenAt int    `json:"brokenAt,omitempty"` // id of the first entry not matching its hash or the previous one
+	LastHash string `json:"lastHash,omitempty"` // kept apart, it reveals entries removed from the end
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type UnresolvedGuide struct {
+	GuideId    int       `json:"guideId"`
+	ViaGuideId string    `json:"viaGuideId"`
+	Recipient  string    `json:"recipient"`
+	Status     string    `json:"status"`
+	Operator   Operator  `json:"operator"`
+	LastChange time.Time `json:"lastChange"`
+}
+
+type DaySummary struct {
+	ClosedAt    time.Time         `json:"closedAt"`
+	PerStatus   map[string]int    `json:"perStatus"`
+	PerOperator map[string]int    `json:"perOperator"` // by operator account
+	Unresolved  []UnresolvedGuide `json:"unresolved"`
+	Closed      int               `json:"closed"`
+	Failed      int               `json:"failed"`
+	Archived    int               `json:"archived"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Device is a kiosk or monitor of a branch, it calls the public endpoints with the token it got when paired
+type Device struct {
+	ID               int        `json:"id"`
+	Name             string     `json:"name"`
+	Kind             string     `json:"kind"`
+	Branch           string     `json:"branch"`
+	Privacy          string     `json:"privacy,omitempty"`          // monitors only, empty uses the branch default
+	PairingExpiresAt *time.Time `json:"pairingExpiresAt,omitempty"` // set while a pairing code is pending
+	PairedAt         *time.Time `json:"pairedAt,omitempty"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+}
+
+// DeviceCredentials is the hash of the device token, empty until the device is paired
+type DeviceCredentials struct {
+	TokenHash string `json:"-"`
+}
+
+// DevicePairing is a pending pairing code of a device, only its hash is stored
+type DevicePairing struct {
+	CodeHash  string    `json:"-"`
+	ExpiresAt time.Time `json:"-"`
+}
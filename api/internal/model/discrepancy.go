@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+type Discrepancy struct {
+	GuideId     int       `json:"guideId"`
+	ViaGuideId  string    `json:"viaGuideId"`
+	Status      string    `json:"status"`
+	ViaStatus   string    `json:"viaStatus"`
+	Reason      string    `json:"reason"`
+	Description string    `json:"description,omitempty"`
+	Operator    Operator  `json:"operator"`
+	DetectedAt  time.Time `json:"detectedAt"`
+}
+
+type ReconciliationReport struct {
+	RunAt         time.Time     `json:"runAt"`
+	Checked       int           `json:"checked"`
+	Failed        int           `json:"failed"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
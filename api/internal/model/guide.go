@@ -5,12 +5,15 @@ import (
 )
 
 type Guide struct {
-	ID         int       `json:"id"`
-	ViaGuideID string    `json:"viaGuideId"`
-	Recipient  string    `json:"recipient"`
-	Operator   Operator  `json:"operator"`
-	Status     string    `json:"status"`
-	Payment    string    `json:"payment"`
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	ID           int        `json:"id"`
+	ViaGuideID   string     `json:"viaGuideId"`
+	Recipient    string     `json:"recipient"`
+	Operator     Operator   `json:"operator"`
+	Status       string     `json:"status"`
+	StatusReason string     `json:"statusReason,omitempty"`
+	Payment      string     `json:"payment"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	ViaSnapshot  *ViaGuide  `json:"viaSnapshot,omitempty"`
+	ViaSyncedAt  *time.Time `json:"viaSyncedAt,omitempty"`
 }
@@ -5,5 +5,6 @@ import "time"
 type GuideHistory struct {
 	ID        int       `json:"id"`
 	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
 	Timestamp time.Time `json:"-"`
 }
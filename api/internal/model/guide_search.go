@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// GuideSearch filters a guide search, zero values do not filter
+type GuideSearch struct {
+	ViaGuideIDPrefix string
+	Recipient        string // normalized, every word must be contained in the recipient
+	OperatorID       int
+	Status           []string
+	Payment          string
+	From             *time.Time // created at or after
+	To               *time.Time // created before
+	Sort             string
+	Desc             bool
+	Cursor           *GuideCursor
+	Limit            int
+}
+
+// GuideCursor is the position after the last guide of a page: its sort value and id, with the
+// sort, order and filters of the search it continues
+type GuideCursor struct {
+	Sort    string    `json:"s"`
+	Desc    bool      `json:"d,omitempty"`
+	Filters string    `json:"f"` // fingerprint of the filters
+	Value   time.Time `json:"v"`
+	ID      int       `json:"id"`
+}
+
+type GuidePage struct {
+	Guides []Guide
+	Next   *GuideCursor // nil on the last page
+}
@@ -4,9 +4,12 @@ import (
 	biz_guide_status "via/internal/biz/guide/status"
 )
 
+// MonitorEvent is shown on the public monitor, its guide id and recipient are masked as the monitor
+// privacy mode requires and left empty when the mode hides them
 type MonitorEvent struct {
-	GuideId   string `json:"guideId"`
-	Recipient string `json:"recipient"`
+	Ticket    int    `json:"ticket"`
+	GuideId   string `json:"guideId,omitempty"`
+	Recipient string `json:"recipient,omitempty"`
 	Status    string `json:"status"`
 	Highlight bool   `json:"highlight"`
 }
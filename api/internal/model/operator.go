@@ -1,8 +1,16 @@
 package model
 
 type Operator struct {
-	ID      int    `json:"id"`
-	Account string `json:"account"`
-	Name    string `json:"name"`
-	Enabled bool   `json:"enabled"`
+	ID           int    `json:"id"`
+	Account      string `json:"account"`
+	Name         string `json:"name"`
+	Enabled      bool   `json:"enabled"`
+	Role         string `json:"role,omitempty"`
+	Availability string `json:"availability,omitempty"`
+}
+
+// OperatorCredentials are the secrets of the emergency local login, empty when it is not enabled for the operator
+type OperatorCredentials struct {
+	PasswordHash string `json:"-"`
+	TOTPSecret   string `json:"-"`
 }
@@ -3,12 +3,16 @@ package model
 import "time"
 
 type OperatorGuide struct {
-	GuideId    int       `json:"guideId"`
-	ViaGuideId string    `json:"viaGuideId"`
-	Recipient  string    `json:"recipient"`
-	Status     string    `json:"status"`
-	LastChange time.Time `json:"lastChange"`
-	Payment    string    `json:"payment"`
-	Operator   Operator  `json:"operator"`
-	Selectable bool      `json:"selectable"`
+	GuideId        int        `json:"guideId"`
+	ViaGuideId     string     `json:"viaGuideId"`
+	Recipient      string     `json:"recipient"`
+	Status         string     `json:"status"`
+	StatusReason   string     `json:"statusReason,omitempty"`
+	LastChange     time.Time  `json:"lastChange"`
+	Payment        string     `json:"payment"`
+	Operator       Operator   `json:"operator"`
+	OperatorOnline bool       `json:"operatorOnline"`
+	Selectable     bool       `json:"selectable"`
+	ViaSnapshot    *ViaGuide  `json:"viaSnapshot,omitempty"`
+	ViaSyncedAt    *time.Time `json:"viaSyncedAt,omitempty"`
 }
@@ -0,0 +1,7 @@
+package model
+
+type OperatorWorkload struct {
+	Operator Operator `json:"operator"`
+	Guides   int      `json:"guides"` // in process guides assigned to the operator
+	Online   bool     `json:"online"`
+}
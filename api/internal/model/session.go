@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Session is an operator login, it lasts while its refresh token keeps being renewed
+type Session struct {
+	ID         string    `json:"id"`
+	OperatorID int       `json:"operatorId"`
+	CreatedAt  time.Time `json:"createdAt"`
+	RenewedAt  time.Time `json:"renewedAt"`
+	UserAgent  string    `json:"userAgent"`
+	IP         string    `json:"ip"`
+	Provider   string    `json:"provider,omitempty"` // the identity provider the operator logged in with
+	Current    bool      `json:"current"`            // the session of the request
+}
@@ -0,0 +1,8 @@
+package model
+
+type ViaGuideEvent struct {
+	Status      string         `json:"status"`
+	Date        string         `json:"date"`
+	Route       string         `json:"route"`
+	Destination ViaDestination `json:"destination"`
+}
@@ -0,0 +1,394 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var spec []byte
+
+// Document holds the parts of the OpenAPI document the requests are validated with
+type Document struct {
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components"`
+
+	routes []route
+}
+
+type Components struct {
+	Schemas    map[string]*Schema    `yaml:"schemas"`
+	Parameters map[string]*Parameter `yaml:"parameters"`
+}
+
+type PathItem struct {
+	Get    *Operation `yaml:"get"`
+	Post   *Operation `yaml:"post"`
+	Put    *Operation `yaml:"put"`
+	Delete *Operation `yaml:"delete"`
+}
+
+type Operation struct {
+	OperationID string       `yaml:"operationId"`
+	Summary     string       `yaml:"summary"`
+	Parameters  []*Parameter `yaml:"parameters"`
+	RequestBody *RequestBody `yaml:"requestBody"`
+	// ErrorCode answers the requests failing the validation, bad_request when empty
+	ErrorCode string `yaml:"x-error-code"`
+}
+
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Style    string  `yaml:"style"`
+	Explode  *bool   `yaml:"explode"`
+	Schema   *Schema `yaml:"schema"`
+	// the keywords the validator does not know, the document fails to load with them
+	Other map[string]any `yaml:",inline"`
+}
+
+type RequestBody struct {
+	Required bool                 `yaml:"required"`
+	Content  map[string]MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       string             `yaml:"type"`
+	Format     string             `yaml:"format"`
+	Enum       []string           `yaml:"enum"`
+	Pattern    string             `yaml:"pattern"`
+	MinLength  *int               `yaml:"minLength"`
+	MaxLength  *int               `yaml:"maxLength"`
+	Minimum    *float64           `yaml:"minimum"`
+	Maximum    *float64           `yaml:"maximum"`
+	Required   []string           `yaml:"required"`
+	Properties map[string]*Schema `yaml:"properties"`
+	Items      *Schema            `yaml:"items"`
+	// the keywords the validator does not know, a request schema fails to load with them
+	Other map[string]any `yaml:",inline"`
+}
+
+// annotations are the keywords not constraining the values, allowed in the request schemas and parameters
+var annotations = []string{"description", "title", "example", "examples", "default", "deprecated"}
+
+// Endpoint is an operation of the document with its method and path
+type Endpoint struct {
+	Method    string
+	Path      string
+	Operation *Operation
+}
+
+// route is a path of the document split in segments, the parameter ones keep their braces
+type route struct {
+	path     string
+	segments []string
+	item     *PathItem
+}
+
+var (
+	document *Document
+	jsonSpec []byte
+	once     sync.Once
+)
+
+// Get returns the embedded document, it panics when it is broken since the api can not be served without it
+func Get() *Document {
+	once.Do(func() {
+		var err error
+		if document, err = Parse(spec); err != nil {
+			panic(err)
+		}
+		if jsonSpec, err = toJSON(spec); err != nil {
+			panic(err)
+		}
+	})
+	return document
+}
+
+// YAML returns the embedded document as written
+func YAML() []byte {
+	return spec
+}
+
+// JSON returns the embedded document converted to JSON
+func JSON() []byte {
+	Get()
+	return jsonSpec
+}
+
+// Parse loads an OpenAPI document, every reference must be found in its components
+func Parse(data []byte) (*Document, error) {
+	doc := &Document{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi document: %w", err)
+	}
+	for name, schema := range doc.Components.Schemas {
+		if err := doc.check(schema); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+	for path, item := range doc.Paths {
+		for method, op := range item.operations() {
+			for i, param := range op.Parameters {
+				if param.Ref == "" {
+					continue
+				}
+				resolved, ok := doc.Components.Parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+				if !ok {
+					return nil, fmt.Errorf("%s %s: parameter %s not found", method, path, param.Ref)
+				}
+				if resolved.Ref != "" {
+					return nil, fmt.Errorf("%s %s: parameter %s references another parameter", method, path, param.Ref)
+				}
+				op.Parameters[i] = resolved
+			}
+			for _, param := range op.Parameters {
+				if err := doc.checkParameter(param); err != nil {
+					return nil, fmt.Errorf("%s %s: parameter %s: %w", method, path, param.Name, err)
+				}
+			}
+			if op.RequestBody != nil {
+				for _, media := range op.RequestBody.Content {
+					if err := doc.check(media.Schema); err != nil {
+						return nil, fmt.Errorf("%s %s: %w", method, path, err)
+					}
+					if err := doc.checkRequest(media.Schema, "body", map[*Schema]bool{}); err != nil {
+						return nil, fmt.Errorf("%s %s: %w", method, path, err)
+					}
+				}
+			}
+		}
+		doc.routes = append(doc.routes, route{path: path, segments: strings.Split(strings.Trim(path, "/"), "/"), item: item})
+	}
+	// the literal segments win over the parameters, /guide/search is not the guide "search"
+	sort.Slice(doc.routes, func(i, j int) bool {
+		return literals(doc.routes[i].segments) > literals(doc.routes[j].segments) ||
+			literals(doc.routes[i].segments) == literals(doc.routes[j].segments) && doc.routes[i].path < doc.routes[j].path
+	})
+	return doc, nil
+}
+
+// Operation returns the operation of the method and request path, with the values of its path parameters
+func (d *Document) Operation(method, path string) (*Operation, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, route := range d.routes {
+		params, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		op, ok := route.item.operations()[method]
+		return op, params, ok
+	}
+	return nil, nil, false
+}
+
+// Methods returns the paths of the document with their methods, as "GET /ping"
+func (d *Document) Methods() []string {
+	methods := []string{}
+	for path, item := range d.Paths {
+		for method := range item.operations() {
+			methods = append(methods, method+" "+path)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// Endpoints returns the operations of the document sorted by path and method
+func (d *Document) Endpoints() []Endpoint {
+	endpoints := []Endpoint{}
+	for path, item := range d.Paths {
+		for method, op := range item.operations() {
+			endpoints = append(endpoints, Endpoint{Method: method, Path: path, Operation: op})
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+	return endpoints
+}
+
+// check fails on the references not found and the patterns not compiling, the referenced schemas
+// are checked with the components
+func (d *Document) check(schema *Schema) error {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		if _, ok := d.resolve(schema); !ok {
+			return fmt.Errorf("schema %s not found", schema.Ref)
+		}
+		return nil
+	}
+	if schema.Pattern != "" {
+		if _, err := regexp.Compile(schema.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern %s: %w", schema.Pattern, err)
+		}
+	}
+	for _, property := range schema.Properties {
+		if err := d.check(property); err != nil {
+			return err
+		}
+	}
+	return d.check(schema.Items)
+}
+
+// checkParameter fails on the parameters the validator would let through unchecked: the ones out of
+// the path and query, serialized other than as comma separated values or with unknown keywords
+func (d *Document) checkParameter(param *Parameter) error {
+	if param.In != "path" && param.In != "query" {
+		return fmt.Errorf("unsupported location %s", param.In)
+	}
+	if err := checkKeywords(param.Other); err != nil {
+		return err
+	}
+	if err := d.check(param.Schema); err != nil {
+		return err
+	}
+	if param.Schema == nil {
+		return nil
+	}
+	if err := d.checkRequest(param.Schema, param.Name, map[*Schema]bool{}); err != nil {
+		return err
+	}
+	schema, _ := d.resolve(param.Schema)
+	switch {
+	case schema.Type == "object":
+		return errors.New("unsupported object parameter")
+	case schema.Type == "array" && (param.Style != "form" || param.Explode == nil || *param.Explode):
+		return errors.New("array parameters must be comma separated, style form and explode false")
+	case schema.Type != "array" && (param.Style != "" || param.Explode != nil):
+		return errors.New("unsupported style")
+	}
+	return nil
+}
+
+// checkRequest fails on the keywords of a request schema the validator does not check, the requests
+// would otherwise pass unchecked what the document forbids
+func (d *Document) checkRequest(schema *Schema, field string, seen map[*Schema]bool) error {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		resolved, ok := d.resolve(schema)
+		if !ok {
+			return fmt.Errorf("%s: schema %s not found", field, schema.Ref)
+		}
+		if resolved.Ref != "" {
+			return fmt.Errorf("%s: schema %s references another schema", field, schema.Ref)
+		}
+		// a recursive schema is checked once
+		if seen[resolved] {
+			return nil
+		}
+		seen[resolved] = true
+		return d.checkRequest(resolved, field, seen)
+	}
+	if err := checkKeywords(schema.Other); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	switch schema.Type {
+	case "object":
+		for name, property := range schema.Properties {
+			if err := d.checkRequest(property, join(field, name), seen); err != nil {
+				return err
+			}
+		}
+	case "array":
+		if schema.Items == nil {
+			return fmt.Errorf("%s: array without items", field)
+		}
+		return d.checkRequest(schema.Items, field+"[]", seen)
+	case "string", "integer", "number", "boolean":
+	default:
+		return fmt.Errorf("%s: unsupported type %q", field, schema.Type)
+	}
+	return nil
+}
+
+// checkKeywords fails on the first keyword, sorted, neither an annotation nor an extension
+func checkKeywords(other map[string]any) error {
+	keywords := []string{}
+	for keyword := range other {
+		if !slices.Contains(annotations, keyword) && !strings.HasPrefix(keyword, "x-") {
+			keywords = append(keywords, keyword)
+		}
+	}
+	if len(keywords) == 0 {
+		return nil
+	}
+	slices.Sort(keywords)
+	return fmt.Errorf("unsupported keyword %s", keywords[0])
+}
+
+func (d *Document) resolve(schema *Schema) (*Schema, bool) {
+	if schema.Ref == "" {
+		return schema, true
+	}
+	resolved, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	return resolved, ok
+}
+
+func (p *PathItem) operations() map[string]*Operation {
+	operations := map[string]*Operation{}
+	for method, op := range map[string]*Operation{"GET": p.Get, "POST": p.Post, "PUT": p.Put, "DELETE": p.Delete} {
+		if op != nil {
+			operations[method] = op
+		}
+	}
+	return operations
+}
+
+func (r route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, "{") {
+			params[strings.Trim(segment, "{}")] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func literals(segments []string) int {
+	count := 0
+	for _, segment := range segments {
+		if !strings.HasPrefix(segment, "{") {
+			count++
+		}
+	}
+	return count
+}
+
+func toJSON(data []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi document: %w", err)
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,333 @@
+package openapi
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	biz_config "via/internal/biz/config"
+	biz_device "via/internal/biz/device"
+	biz_guide_search "via/internal/biz/guide/search"
+	biz_guide_status "via/internal/biz/guide/status"
+	biz_monitor "via/internal/biz/monitor"
+	biz_operator "via/internal/biz/operator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	doc := Get()
+
+	assert.NotEmpty(t, doc.Methods())
+	var spec map[string]any
+	assert.NoError(t, json.Unmarshal(JSON(), &spec))
+	assert.Equal(t, "3.0.3", spec["openapi"])
+	assert.Contains(t, spec["paths"], "/guide-to-withdraw/{viaGuideId}")
+}
+
+func TestEndpoints(t *testing.T) {
+	endpoints := Get().Endpoints()
+
+	assert.Len(t, endpoints, len(Get().Methods()))
+	assert.True(t, slices.IsSortedFunc(endpoints, func(a, b Endpoint) int {
+		return strings.Compare(a.Path+" "+a.Method, b.Path+" "+b.Method)
+	}))
+	for _, endpoint := range endpoints {
+		if endpoint.Path == "/ping" {
+			assert.Equal(t, "GET", endpoint.Method)
+			assert.Equal(t, "ping", endpoint.Operation.OperationID)
+			assert.NotEmpty(t, endpoint.Operation.Summary)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expectedErr string
+	}{
+		{name: "valid", spec: `
+paths:
+  /guide/{guideId}:
+    get:
+      parameters:
+        - $ref: "#/components/parameters/GuideId"
+components:
+  parameters:
+    GuideId:
+      name: guideId
+      in: path
+      schema:
+        type: integer
+`},
+		{name: "not yaml", spec: "paths: [", expectedErr: "failed to parse openapi document"},
+		{name: "parameter not found", spec: `
+paths:
+  /guide/{guideId}:
+    get:
+      parameters:
+        - $ref: "#/components/parameters/GuideId"
+`, expectedErr: "GET /guide/{guideId}: parameter #/components/parameters/GuideId not found"},
+		{name: "schema not found", spec: `
+paths:
+  /guide:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Guide"
+`, expectedErr: "POST /guide: schema #/components/schemas/Guide not found"},
+		{name: "invalid pattern", spec: `
+components:
+  schemas:
+    ViaGuideId:
+      type: string
+      pattern: '^(\d{12}$'
+`, expectedErr: "schema ViaGuideId: invalid pattern"},
+		{name: "annotations and response schemas are not checked", spec: `
+paths:
+  /guide:
+    post:
+      parameters:
+        - name: status
+          in: query
+          description: Comma separated statuses
+          style: form
+          explode: false
+          schema:
+            type: array
+            items:
+              type: string
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            default: 50
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Guide"
+components:
+  schemas:
+    Guide:
+      type: object
+      description: A guide
+      x-internal: true
+      properties:
+        guide:
+          $ref: "#/components/schemas/Guide"
+    Output:
+      allOf:
+        - $ref: "#/components/schemas/Guide"
+`},
+		{name: "unsupported keyword in a request body", spec: `
+paths:
+  /guide:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Guide"
+components:
+  schemas:
+    Guide:
+      type: object
+      additionalProperties: false
+      properties:
+        status:
+          oneOf:
+            - type: string
+`, expectedErr: "POST /guide: body: unsupported keyword additionalProperties"},
+		{name: "unsupported keyword in a request property", spec: `
+paths:
+  /guide:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                reason:
+                  type: string
+                  nullable: true
+`, expectedErr: "POST /guide: body.reason: unsupported keyword nullable"},
+		{name: "request schema without type", spec: `
+paths:
+  /guide:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                status:
+                  anyOf:
+                    - type: string
+                    - type: integer
+`, expectedErr: "POST /guide: body.status: unsupported keyword anyOf"},
+		{name: "reference to a reference", spec: `
+paths:
+  /guide:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Guide"
+components:
+  schemas:
+    Guide:
+      $ref: "#/components/schemas/Other"
+    Other:
+      type: object
+`, expectedErr: "POST /guide: body: schema #/components/schemas/Guide references another schema"},
+		{name: "parameter referencing a parameter", spec: `
+paths:
+  /guide:
+    get:
+      parameters:
+        - $ref: "#/components/parameters/Lang"
+components:
+  parameters:
+    Lang:
+      $ref: "#/components/parameters/Other"
+`, expectedErr: "GET /guide: parameter #/components/parameters/Lang references another parameter"},
+		{name: "header parameter", spec: `
+paths:
+  /guide:
+    get:
+      parameters:
+        - name: x-trace
+          in: header
+          schema:
+            type: string
+`, expectedErr: "GET /guide: parameter x-trace: unsupported location header"},
+		{name: "exploded array parameter", spec: `
+paths:
+  /guide:
+    get:
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+`, expectedErr: "GET /guide: parameter status: array parameters must be comma separated"},
+		{name: "unsupported parameter keyword", spec: `
+paths:
+  /guide:
+    get:
+      parameters:
+        - name: status
+          in: query
+          allowEmptyValue: true
+          schema:
+            type: string
+`, expectedErr: "GET /guide: parameter status: unsupported keyword allowEmptyValue"},
+		{name: "unsupported parameter schema keyword", spec: `
+paths:
+  /guide:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            exclusiveMinimum: true
+`, expectedErr: "GET /guide: parameter limit: limit: unsupported keyword exclusiveMinimum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.spec))
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestOperation(t *testing.T) {
+	doc := Get()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedOp     string
+		expectedParams map[string]string
+		expectedFound  bool
+	}{
+		{name: "literal path", method: "GET", path: "/guide/search", expectedOp: "searchGuides",
+			expectedParams: map[string]string{}, expectedFound: true},
+		{name: "path parameter", method: "PUT", path: "/guide/12/status", expectedOp: "updateGuideStatus",
+			expectedParams: map[string]string{"guideId": "12"}, expectedFound: true},
+		{name: "two path parameters", method: "DELETE", path: "/admin/operator/3/sessions/abc",
+			expectedOp: "revokeOperatorSession", expectedParams: map[string]string{"operatorId": "3", "sessionId": "abc"},
+			expectedFound: true},
+		{name: "method not documented", method: "DELETE", path: "/guide/search"},
+		{name: "path not documented", method: "GET", path: "/unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, params, found := doc.Operation(tt.method, tt.path)
+
+			assert.Equal(t, tt.expectedFound, found)
+			if !tt.expectedFound {
+				return
+			}
+			assert.Equal(t, tt.expectedOp, op.OperationID)
+			assert.Equal(t, tt.expectedParams, params)
+		})
+	}
+}
+
+func TestOperation_LiteralFirst(t *testing.T) {
+	doc, err := Parse([]byte(`
+paths:
+  /guide/{guideId}:
+    get:
+      operationId: getGuide
+  /guide/search:
+    get:
+      operationId: searchGuides
+`))
+	assert.NoError(t, err)
+
+	op, _, _ := doc.Operation("GET", "/guide/search")
+	assert.Equal(t, "searchGuides", op.OperationID)
+	op, params, _ := doc.Operation("GET", "/guide/12")
+	assert.Equal(t, "getGuide", op.OperationID)
+	assert.Equal(t, map[string]string{"guideId": "12"}, params)
+}
+
+// TestEnums keeps the enums of the document in sync with the values the business accepts
+func TestEnums(t *testing.T) {
+	schemas := Get().Components.Schemas
+
+	for _, status := range schemas["GuideStatus"].Enum {
+		assert.True(t, biz_guide_status.IsValid(status), status)
+	}
+	assert.Subset(t, schemas["GuideStatus"].Enum, append(biz_guide_status.GetOperatorStatus(),
+		biz_guide_status.PARTIAL_DELIVERED, biz_guide_status.DELIVERED))
+	assert.ElementsMatch(t, biz_guide_status.GetOperatorStatus(), schemas["OperatorGuideStatus"].Enum)
+	assert.ElementsMatch(t, []string{biz_config.PAID_SHIPPING, biz_config.PAID_ON_DESTINATION}, schemas["Payment"].Enum)
+	assert.ElementsMatch(t, []string{biz_device.KIND_KIOSK, biz_device.KIND_MONITOR}, schemas["DeviceKind"].Enum)
+	for _, availability := range schemas["Availability"].Enum {
+		assert.True(t, biz_operator.IsValidAvailability(availability), availability)
+	}
+	for _, privacy := range schemas["DevicePrivacy"].Enum {
+		assert.True(t, privacy == "" || biz_monitor.IsValidPrivacy(privacy), privacy)
+	}
+	assert.Equal(t, float64(biz_guide_search.MAX_LIMIT), *Get().Components.Parameters["GuideLimit"].Schema.Maximum)
+}
@@ -0,0 +1,226 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+	"via/internal/response"
+)
+
+const dateLayout = "2006-01-02"
+
+// ErrBodyInvalid is returned when the body is not the JSON the operation expects
+var ErrBodyInvalid = errors.New("request body is not valid json")
+
+var patterns sync.Map
+
+// Validate checks the parameters and the JSON body of r against its operation, the body is read and
+// put back for the handler. It returns the operation, nil when the document does not have it, with
+// the failures of the fields.
+func (d *Document) Validate(r *http.Request) (*Operation, []response.FieldError, error) {
+	op, pathParams, ok := d.Operation(r.Method, r.URL.Path)
+	if !ok {
+		return nil, nil, nil
+	}
+	details := []response.FieldError{}
+	query := r.URL.Query()
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			details = d.validateParam(details, param, pathParams[param.Name], pathParams[param.Name] != "")
+		case "query":
+			details = d.validateParam(details, param, query.Get(param.Name), query.Has(param.Name))
+		}
+	}
+	if op.RequestBody == nil {
+		return op, details, nil
+	}
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok {
+		return op, details, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return op, details, fmt.Errorf("failed to read body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) == 0 && !op.RequestBody.Required {
+		return op, details, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return op, details, fmt.Errorf("%w: %w", ErrBodyInvalid, err)
+	}
+	if _, isObject := value.(map[string]any); !isObject {
+		return op, details, ErrBodyInvalid
+	}
+	return op, d.validateValue(details, "", media.Schema, value), nil
+}
+
+func (d *Document) validateParam(details []response.FieldError, param *Parameter, value string, found bool) []response.FieldError {
+	if !found {
+		if param.Required {
+			return append(details, response.FieldError{Field: param.Name, Code: response.FIELD_REQUIRED})
+		}
+		return details
+	}
+	if param.Schema == nil {
+		return details
+	}
+	schema, _ := d.resolve(param.Schema)
+	if schema.Type == "array" {
+		for _, item := range strings.Split(value, ",") {
+			if code := d.checkParam(schema.Items, item); code != "" {
+				return append(details, response.FieldError{Field: param.Name, Code: code})
+			}
+		}
+		return details
+	}
+	if code := d.checkParam(schema, value); code != "" {
+		return append(details, response.FieldError{Field: param.Name, Code: code})
+	}
+	return details
+}
+
+// checkParam returns the failure code of the value of a parameter, empty when valid
+func (d *Document) checkParam(schema *Schema, value string) string {
+	schema, _ = d.resolve(schema)
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return response.FIELD_INVALID
+		}
+		return checkRange(schema, float64(n))
+	case "boolean":
+		if value != "true" && value != "false" {
+			return response.FIELD_INVALID
+		}
+		return ""
+	}
+	return checkString(schema, value)
+}
+
+func (d *Document) validateValue(details []response.FieldError, field string, schema *Schema, value any) []response.FieldError {
+	if schema == nil {
+		return details
+	}
+	schema, _ = d.resolve(schema)
+	invalid := func() []response.FieldError {
+		return append(details, response.FieldError{Field: field, Code: response.FIELD_INVALID})
+	}
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return invalid()
+		}
+		for _, name := range schema.Required {
+			if _, found := object[name]; !found {
+				details = append(details, response.FieldError{Field: join(field, name), Code: response.FIELD_REQUIRED})
+			}
+		}
+		// sorted by name so the details do not change between requests
+		names := []string{}
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			if v, found := object[name]; found {
+				details = d.validateValue(details, join(field, name), schema.Properties[name], v)
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return invalid()
+		}
+		for i, item := range items {
+			details = d.validateValue(details, field+"["+strconv.Itoa(i)+"]", schema.Items, item)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return invalid()
+		}
+		if code := checkString(schema, s); code != "" {
+			details = append(details, response.FieldError{Field: field, Code: code})
+		}
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			return invalid()
+		}
+		f, err := n.Float64()
+		if err != nil || schema.Type == "integer" && strings.ContainsAny(n.String(), ".eE") {
+			return invalid()
+		}
+		if code := checkRange(schema, f); code != "" {
+			details = append(details, response.FieldError{Field: field, Code: code})
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return invalid()
+		}
+	}
+	return details
+}
+
+func checkString(schema *Schema, value string) string {
+	if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, value) {
+		return response.FIELD_INVALID
+	}
+	if schema.Pattern != "" && !compile(schema.Pattern).MatchString(value) {
+		return response.FIELD_INVALID
+	}
+	if schema.Format == "date" {
+		if _, err := time.Parse(dateLayout, value); err != nil {
+			return response.FIELD_INVALID
+		}
+	}
+	length := utf8.RuneCountInString(value)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		return response.FIELD_REQUIRED
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return response.FIELD_TOO_LONG
+	}
+	return ""
+}
+
+func checkRange(schema *Schema, value float64) string {
+	if schema.Minimum != nil && value < *schema.Minimum || schema.Maximum != nil && value > *schema.Maximum {
+		return response.FIELD_OUT_OF_RANGE
+	}
+	return ""
+}
+
+// compile caches the patterns of the document, they are checked when it is parsed
+func compile(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	patterns.Store(pattern, re)
+	return re
+}
+
+func join(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"via/internal/response"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		target          string
+		body            string
+		expectedOp      string
+		expectedDetails []response.FieldError
+		expectedErr     error
+	}{
+		{name: "valid via guide id", method: http.MethodGet, target: "/guide-to-withdraw/123456789012",
+			expectedOp: "getGuideToWithdraw"},
+		{name: "invalid via guide id", method: http.MethodGet, target: "/guide-to-withdraw/abc123",
+			expectedOp:      "getGuideToWithdraw",
+			expectedDetails: []response.FieldError{{Field: "viaGuideId", Code: response.FIELD_INVALID}}},
+		{name: "missing via guide id in body", method: http.MethodPost, target: "/guide-to-withdraw", body: `{}`,
+			expectedOp:      "createGuideToWithdraw",
+			expectedDetails: []response.FieldError{{Field: "viaGuideId", Code: response.FIELD_REQUIRED}}},
+		{name: "short via guide id in body", method: http.MethodPost, target: "/guide-to-withdraw",
+			body: `{"viaGuideId":"1234"}`, expectedOp: "createGuideToWithdraw",
+			expectedDetails: []response.FieldError{{Field: "viaGuideId", Code: response.FIELD_INVALID}}},
+		{name: "known status", method: http.MethodPut, target: "/guide/12/status", body: `{"status":"onHold"}`,
+			expectedOp: "updateGuideStatus"},
+		{name: "unknown status", method: http.MethodPut, target: "/guide/12/status", body: `{"status":"lost"}`,
+			expectedOp:      "updateGuideStatus",
+			expectedDetails: []response.FieldError{{Field: "status", Code: response.FIELD_INVALID}}},
+		{name: "status not a string", method: http.MethodPut, target: "/guide/12/status", body: `{"status":1}`,
+			expectedOp:      "updateGuideStatus",
+			expectedDetails: []response.FieldError{{Field: "status", Code: response.FIELD_INVALID}}},
+		{name: "invalid guide id", method: http.MethodPut, target: "/guide/abc/status", body: `{"status":"onHold"}`,
+			expectedOp:      "updateGuideStatus",
+			expectedDetails: []response.FieldError{{Field: "guideId", Code: response.FIELD_INVALID}}},
+		{name: "guide id out of range", method: http.MethodPost, target: "/guide/0/sync", expectedOp: "syncGuide",
+			expectedDetails: []response.FieldError{{Field: "guideId", Code: response.FIELD_OUT_OF_RANGE}}},
+		{name: "integer with decimals", method: http.MethodPost, target: "/guide/12/transfer", body: `{"operatorId":1.5}`,
+			expectedOp:      "transferGuide",
+			expectedDetails: []response.FieldError{{Field: "operatorId", Code: response.FIELD_INVALID}}},
+		{name: "invalid force", method: http.MethodPost, target: "/guide/12/assign?force=yes",
+			expectedOp:      "assignGuideToOperator",
+			expectedDetails: []response.FieldError{{Field: "force", Code: response.FIELD_INVALID}}},
+		{name: "every field failing", method: http.MethodPost, target: "/admin/devices",
+			body:       `{"name":"` + strings.Repeat("a", 201) + `","kind":"tablet","privacy":"full_name"}`,
+			expectedOp: "createDevice",
+			expectedDetails: []response.FieldError{
+				{Field: "branch", Code: response.FIELD_REQUIRED},
+				{Field: "kind", Code: response.FIELD_INVALID},
+				{Field: "name", Code: response.FIELD_TOO_LONG},
+				{Field: "privacy", Code: response.FIELD_INVALID},
+			}},
+		{name: "branch default privacy", method: http.MethodPut, target: "/admin/devices/4/privacy", body: `{"privacy":""}`,
+			expectedOp: "setDevicePrivacy"},
+		{name: "valid search", method: http.MethodGet,
+			target:     "/guide/search?status=initial,onHold&from=2026-01-31&limit=100&order=asc&payment=D",
+			expectedOp: "searchGuides"},
+		{name: "invalid search", method: http.MethodGet,
+			target:     "/guide/search?status=initial,lost&from=31/01/2026&limit=101&order=up&viaGuideId=12a",
+			expectedOp: "searchGuides",
+			expectedDetails: []response.FieldError{
+				{Field: "viaGuideId", Code: response.FIELD_INVALID},
+				{Field: "status", Code: response.FIELD_INVALID},
+				{Field: "from", Code: response.FIELD_INVALID},
+				{Field: "order", Code: response.FIELD_INVALID},
+				{Field: "limit", Code: response.FIELD_OUT_OF_RANGE},
+			}},
+		{name: "status not for operators", method: http.MethodGet, target: "/operator/guides?status=delivered&mine=true",
+			expectedOp:      "getOperatorGuides",
+			expectedDetails: []response.FieldError{{Field: "status", Code: response.FIELD_INVALID}}},
+		{name: "body not json", method: http.MethodPut, target: "/operator/availability", body: `{`,
+			expectedOp: "setOperatorAvailability", expectedErr: ErrBodyInvalid},
+		{name: "body not an object", method: http.MethodPut, target: "/operator/availability", body: `["available"]`,
+			expectedOp: "setOperatorAvailability", expectedErr: ErrBodyInvalid},
+		{name: "missing body", method: http.MethodPut, target: "/operator/availability",
+			expectedOp: "setOperatorAvailability", expectedErr: ErrBodyInvalid},
+		{name: "route not documented", method: http.MethodGet, target: "/unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+
+			op, details, err := Get().Validate(req)
+
+			if tt.expectedOp == "" {
+				assert.Nil(t, op)
+				return
+			}
+			assert.Equal(t, tt.expectedOp, op.OperationID)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr), err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expectedDetails == nil {
+				assert.Empty(t, details)
+			} else {
+				assert.Equal(t, tt.expectedDetails, details)
+			}
+			body, _ := io.ReadAll(req.Body)
+			assert.Equal(t, tt.body, string(body), "the body is put back for the handler")
+		})
+	}
+}
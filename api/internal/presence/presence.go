@@ -0,0 +1,120 @@
+package presence
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+	"via/internal/ds"
+	"via/internal/log"
+)
+
+type PresenceCfg struct {
+	Heartbeat int `env:"HEARTBEAT" envDefault:"15" json:"heartbeat"` // in seconds
+	TTL       int `env:"TTL" envDefault:"45" json:"ttl"`             // in seconds, an operator without heartbeats is offline after it
+}
+
+const (
+	defaultHeartbeat = 15
+	// heartbeats missed before the operator is offline, when the TTL is not longer than the heartbeat
+	defaultMissedBeats = 3
+)
+
+// Presence tracks the operators online, an operator is online while it keeps a feed connection open.
+// The open connections of an operator are kept in a set, refreshed by each of them on every heartbeat:
+// the operator is offline once the last one leaves, or the TTL after the last heartbeat when its
+// API instance stopped without leaving.
+type Presence struct {
+	heartbeat time.Duration
+	ttl       int
+}
+
+// New falls back to the default heartbeat when the configured one is not positive, and to three
+// heartbeats when the TTL would expire the presence before the next heartbeat
+func New(cfg PresenceCfg) *Presence {
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = defaultHeartbeat
+	}
+	if cfg.TTL <= cfg.Heartbeat {
+		cfg.TTL = defaultMissedBeats * cfg.Heartbeat
+	}
+	return &Presence{heartbeat: time.Duration(cfg.Heartbeat) * time.Second, ttl: cfg.TTL}
+}
+
+func key(operatorId int) string {
+	return fmt.Sprintf("presence:operator:%d:connections", operatorId)
+}
+
+// Track marks the operator online until ctx is done. The returned channel is closed once
+// the connection is cleared from the operator presence.
+func (p *Presence) Track(ctx context.Context, operatorId int) <-chan struct{} {
+	done := make(chan struct{})
+	connectionId := newConnectionId()
+	p.beat(ctx, operatorId, connectionId)
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.beat(ctx, operatorId, connectionId)
+			case <-ctx.Done():
+				p.leave(context.WithoutCancel(ctx), operatorId, connectionId)
+				return
+			}
+		}
+	}()
+	return done
+}
+
+func (p *Presence) beat(ctx context.Context, operatorId int, connectionId string) {
+	if err := ds.Get().SAdd(ctx, key(operatorId), connectionId, p.ttl); err != nil {
+		log.Get().Error(ctx, err, "msg", "failed refreshing operator presence", "operator_id", operatorId)
+	}
+}
+
+// leave removes the connection, the operator stays online while another one is open
+func (p *Presence) leave(ctx context.Context, operatorId int, connectionId string) {
+	if err := ds.Get().SRem(ctx, key(operatorId), connectionId); err != nil {
+		log.Get().Error(ctx, err, "msg", "failed clearing operator presence", "operator_id", operatorId)
+	}
+}
+
+// Online returns which of the operators are online
+func (p *Presence) Online(ctx context.Context, operatorIds []int) (map[int]bool, error) {
+	online := map[int]bool{}
+	for _, operatorId := range operatorIds {
+		connections, err := ds.Get().SMembers(ctx, key(operatorId))
+		if err != nil {
+			return online, fmt.Errorf("getting operator %d presence: %w", operatorId, err)
+		}
+		online[operatorId] = len(connections) > 0
+	}
+	return online, nil
+}
+
+func newConnectionId() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+var (
+	instance *Presence
+	mutex    = &sync.RWMutex{}
+)
+
+func Get() *Presence {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return instance
+}
+
+func Set(presence *Presence) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	instance = presence
+}
@@ -0,0 +1,150 @@
+package presence
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"via/internal/ds"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             PresenceCfg
+		expectHeartbeat time.Duration
+		expectTTL       int
+	}{
+		{name: "configured", cfg: PresenceCfg{Heartbeat: 15, TTL: 45}, expectHeartbeat: 15 * time.Second, expectTTL: 45},
+		{name: "no heartbeat", cfg: PresenceCfg{Heartbeat: 0, TTL: 60}, expectHeartbeat: 15 * time.Second, expectTTL: 60},
+		{name: "negative heartbeat", cfg: PresenceCfg{Heartbeat: -1}, expectHeartbeat: 15 * time.Second, expectTTL: 45},
+		{name: "ttl shorter than the heartbeat", cfg: PresenceCfg{Heartbeat: 10, TTL: 5}, expectHeartbeat: 10 * time.Second,
+			expectTTL: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.cfg)
+			assert.Equal(t, tt.expectHeartbeat, p.heartbeat)
+			assert.Equal(t, tt.expectTTL, p.ttl)
+		})
+	}
+}
+
+func TestTrack(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	tests := []struct {
+		name   string
+		addErr error
+		remErr error
+	}{
+		{name: "removes its connection"},
+		{name: "error removing the connection", remErr: errors.New("ds error")},
+		{name: "error refreshing presence", addErr: errors.New("ds error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			ds.Set(mockDS)
+			connectionId := ""
+			once := sync.Once{}
+			beats := make(chan struct{}, 2)
+			mockDS.On("SAdd", mock.Anything, "presence:operator:7:connections", mock.Anything, 45).
+				Run(func(args mock.Arguments) {
+					once.Do(func() { connectionId = args.String(2) })
+					select {
+					case beats <- struct{}{}:
+					default:
+					}
+				}).Return(tt.addErr)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			p := &Presence{heartbeat: time.Millisecond, ttl: 45}
+			done := p.Track(ctx, 7)
+			// the first heartbeat is sent right away and the ticker keeps refreshing it
+			<-beats
+			<-beats
+
+			mockDS.On("SRem", mock.Anything, "presence:operator:7:connections", connectionId).Return(tt.remErr).Once()
+			cancel()
+			<-done
+
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrack_Connections(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mockDS := new(mock_ds.MockDS)
+	ds.Set(mockDS)
+	connections := map[string]bool{}
+	lock := sync.Mutex{}
+	mockDS.On("SAdd", mock.Anything, "presence:operator:7:connections", mock.Anything, 45).
+		Run(func(args mock.Arguments) {
+			lock.Lock()
+			defer lock.Unlock()
+			connections[args.String(2)] = true
+		}).Return(nil)
+	mockDS.On("SRem", mock.Anything, "presence:operator:7:connections", mock.Anything).
+		Run(func(args mock.Arguments) {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(connections, args.String(2))
+		}).Return(nil)
+	openConnections := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(connections)
+	}
+	p := &Presence{heartbeat: time.Hour, ttl: 45}
+
+	// two tabs of the same operator, closing one keeps the operator online
+	firstCtx, closeFirst := context.WithCancel(context.Background())
+	first := p.Track(firstCtx, 7)
+	secondCtx, closeSecond := context.WithCancel(context.Background())
+	second := p.Track(secondCtx, 7)
+
+	assert.Equal(t, 2, openConnections())
+
+	closeFirst()
+	<-first
+	assert.Equal(t, 1, openConnections())
+
+	closeSecond()
+	<-second
+	assert.Equal(t, 0, openConnections())
+}
+
+func TestOnline(t *testing.T) {
+	mockDS := new(mock_ds.MockDS)
+	ds.Set(mockDS)
+	mockDS.On("SMembers", mock.Anything, "presence:operator:2:connections").Return([]string{"a"}, nil).Once()
+	mockDS.On("SMembers", mock.Anything, "presence:operator:3:connections").Return([]string{}, nil).Once()
+
+	online, err := New(PresenceCfg{}).Online(context.Background(), []int{2, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{2: true, 3: false}, online)
+	mockDS.AssertExpectations(t)
+
+	mockDS.On("SMembers", mock.Anything, "presence:operator:4:connections").Return(nil, errors.New("ds error")).Once()
+	_, err = New(PresenceCfg{}).Online(context.Background(), []int{4})
+	assert.EqualError(t, err, "getting operator 4 presence: ds error")
+}
+
+func TestGetSet(t *testing.T) {
+	p := New(PresenceCfg{})
+	Set(p)
+	assert.Equal(t, p, Get())
+	Set(nil)
+	assert.Nil(t, Get())
+}
@@ -0,0 +1,30 @@
+package audit_provider
+
+import (
+	"context"
+	"sync"
+	"via/internal/model"
+)
+
+type AuditProvider interface {
+	AppendAuditEntry(ctx context.Context, entry model.AuditEntry) (model.AuditEntry, error)
+	SearchAuditEntries(ctx context.Context, search model.AuditSearch) ([]model.AuditEntry, error)
+	GetAuditEntriesAfter(ctx context.Context, afterId int, limit int) ([]model.AuditEntry, error)
+}
+
+var (
+	instance AuditProvider
+	mutex    = &sync.RWMutex{}
+)
+
+func Get() AuditProvider {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return instance
+}
+
+func Set(auditProvider AuditProvider) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	instance = auditProvider
+}
@@ -0,0 +1,159 @@
+package audit_ent_provider
+
+import (
+	"context"
+	dbsql "database/sql"
+	"errors"
+	"fmt"
+	biz_audit "via/internal/biz/audit"
+	ent_client "via/internal/client/ent"
+	db_pool "via/internal/db/pool"
+	"via/internal/ent"
+	"via/internal/ent/auditentry"
+	"via/internal/ent/predicate"
+	"via/internal/log"
+	"via/internal/model"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+type AuditEntProvider struct {
+	client *ent.Client
+	db     *dbsql.DB // appending locks the chain, which ent does not model
+}
+
+func New() AuditEntProvider {
+	return AuditEntProvider{ent_client.Get(), db_pool.Get()}
+}
+
+// appendLockKey is the advisory lock held while appending, so the entries are chained one after the other
+const appendLockKey = 4200312
+
+const (
+	appendLockQuery  = `SELECT pg_advisory_xact_lock($1)`
+	lastHashQuery    = `SELECT hash FROM audit_entries ORDER BY id DESC LIMIT 1`
+	insertEntryQuery = `INSERT INTO audit_entries (actor, action, target_type, target_id, request_id, ip, before, after,
+	prev_hash, hash, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+)
+
+func nullable(value []byte) any {
+	if value == nil {
+		return nil
+	}
+	return string(value)
+}
+
+func fromEntAuditEntry(entry ent.AuditEntry) model.AuditEntry {
+	audit := model.AuditEntry{
+		ID:         entry.ID,
+		Actor:      entry.Actor,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		RequestID:  entry.RequestID,
+		IP:         entry.IP,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+		CreatedAt:  entry.CreatedAt,
+	}
+	if entry.Before != "" {
+		audit.Before = []byte(entry.Before)
+	}
+	if entry.After != "" {
+		audit.After = []byte(entry.After)
+	}
+	return audit
+}
+
+// AppendAuditEntry chains the entry to the last one and inserts it, returning it with its id and hashes
+func (p AuditEntProvider) AppendAuditEntry(ctx context.Context, entry model.AuditEntry) (model.AuditEntry, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed starting audit transaction")
+		return model.AuditEntry{}, fmt.Errorf("failed starting audit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, appendLockQuery, appendLockKey); err != nil {
+		log.Get().Error(ctx, err, "msg", "failed locking audit entries")
+		return model.AuditEntry{}, fmt.Errorf("failed locking audit entries: %w", err)
+	}
+	err = tx.QueryRowContext(ctx, lastHashQuery).Scan(&entry.PrevHash)
+	if err != nil && !errors.Is(err, dbsql.ErrNoRows) {
+		log.Get().Error(ctx, err, "msg", "failed querying last audit entry")
+		return model.AuditEntry{}, fmt.Errorf("failed querying last audit entry: %w", err)
+	}
+	entry.Hash = biz_audit.Hash(entry)
+	err = tx.QueryRowContext(ctx, insertEntryQuery, entry.Actor, entry.Action, entry.TargetType, entry.TargetID,
+		entry.RequestID, entry.IP, nullable(entry.Before), nullable(entry.After), entry.PrevHash, entry.Hash,
+		entry.CreatedAt).Scan(&entry.ID)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed inserting audit entry", "action", entry.Action)
+		return model.AuditEntry{}, fmt.Errorf("failed inserting audit entry: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		log.Get().Error(ctx, err, "msg", "failed committing audit transaction")
+		return model.AuditEntry{}, fmt.Errorf("failed committing audit transaction: %w", err)
+	}
+	return entry, nil
+}
+
+// SearchAuditEntries returns the entries matching the search filters, newest first
+func (p AuditEntProvider) SearchAuditEntries(ctx context.Context, search model.AuditSearch) ([]model.AuditEntry, error) {
+	entries := []model.AuditEntry{}
+	where := []predicate.AuditEntry{}
+	if search.Actor != "" {
+		where = append(where, auditentry.Actor(search.Actor))
+	}
+	if search.Action != "" {
+		where = append(where, auditentry.Action(search.Action))
+	}
+	if search.TargetType != "" {
+		where = append(where, auditentry.TargetType(search.TargetType))
+	}
+	if search.TargetID != "" {
+		where = append(where, auditentry.TargetID(search.TargetID))
+	}
+	if search.From != nil {
+		where = append(where, auditentry.CreatedAtGTE(*search.From))
+	}
+	if search.To != nil {
+		where = append(where, auditentry.CreatedAtLT(*search.To))
+	}
+	if search.BeforeID != 0 {
+		where = append(where, auditentry.IDLT(search.BeforeID))
+	}
+	ae, err := p.client.AuditEntry.
+		Query().
+		Where(where...).
+		Order(auditentry.ByID(sql.OrderDesc())).
+		Limit(search.Limit).
+		All(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed searching AuditEntries")
+		return entries, fmt.Errorf("failed searching AuditEntries: %w", err)
+	}
+	for _, entry := range ae {
+		entries = append(entries, fromEntAuditEntry(*entry))
+	}
+	return entries, nil
+}
+
+// GetAuditEntriesAfter returns up to limit entries following the one with afterId, in the order they were chained
+func (p AuditEntProvider) GetAuditEntriesAfter(ctx context.Context, afterId int, limit int) ([]model.AuditEntry, error) {
+	entries := []model.AuditEntry{}
+	ae, err := p.client.AuditEntry.
+		Query().
+		Where(auditentry.IDGT(afterId)).
+		Order(auditentry.ByID(sql.OrderAsc())).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed getting AuditEntries", "after_id", afterId)
+		return entries, fmt.Errorf("failed getting AuditEntries: %w", err)
+	}
+	for _, entry := range ae {
+		entries = append(entries, fromEntAuditEntry(*entry))
+	}
+	return entries, nil
+}
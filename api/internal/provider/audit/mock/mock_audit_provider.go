@@ -0,0 +1,43 @@
+package mock_audit_provider
+
+import (
+	"context"
+	"via/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditProvider struct {
+	mock.Mock
+}
+
+func (m *MockAuditProvider) AppendAuditEntry(ctx context.Context, entry model.AuditEntry) (model.AuditEntry, error) {
+	args := m.Called(ctx, entry)
+	return args.Get(0).(model.AuditEntry), args.Error(1)
+}
+
+func (m *MockAuditProvider) SearchAuditEntries(ctx context.Context, search model.AuditSearch) ([]model.AuditEntry, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).([]model.AuditEntry), args.Error(1)
+}
+
+func (m *MockAuditProvider) GetAuditEntriesAfter(ctx context.Context, afterId int, limit int) ([]model.AuditEntry, error) {
+	args := m.Called(ctx, afterId, limit)
+	return args.Get(0).([]model.AuditEntry), args.Error(1)
+}
+
+// MockNoOpAuditProvider accepts every entry, for the tests not checking the audit log
+type MockNoOpAuditProvider struct {
+}
+
+func (m *MockNoOpAuditProvider) AppendAuditEntry(ctx context.Context, entry model.AuditEntry) (model.AuditEntry, error) {
+	return entry, nil
+}
+
+func (m *MockNoOpAuditProvider) SearchAuditEntries(ctx context.Context, search model.AuditSearch) ([]model.AuditEntry, error) {
+	return []model.AuditEntry{}, nil
+}
+
+func (m *MockNoOpAuditProvider) GetAuditEntriesAfter(ctx context.Context, afterId int, limit int) ([]model.AuditEntry, error) {
+	return []model.AuditEntry{}, nil
+}
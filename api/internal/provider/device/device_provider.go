@@ -0,0 +1,34 @@
+package device_provider
+
+import (
+	"context"
+	"sync"
+	"via/internal/model"
+)
+
+type DeviceProvider interface {
+	CreateDevice(ctx context.Context, device model.Device, pairing model.DevicePairing) (model.Device, error)
+	GetDevices(ctx context.Context) ([]model.Device, error)
+	GetDeviceCredentials(ctx context.Context, id int) (model.Device, model.DeviceCredentials, error)
+	SetDevicePairing(ctx context.Context, id int, pairing model.DevicePairing) (bool, error)
+	PairDevice(ctx context.Context, codeHash string, credentials model.DeviceCredentials) (model.Device, error)
+	RevokeDevice(ctx context.Context, id int) (bool, error)
+	SetDevicePrivacy(ctx context.Context, id int, privacy string) (bool, error)
+}
+
+var (
+	instance DeviceProvider
+	mutex    = &sync.RWMutex{}
+)
+
+func Get() DeviceProvider {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return instance
+}
+
+func Set(deviceProvider DeviceProvider) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	instance = deviceProvider
+}
@@ -0,0 +1,167 @@
+package device_ent_provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+	ent_client "via/internal/client/ent"
+	"via/internal/ent"
+	"via/internal/ent/device"
+	"via/internal/log"
+	"via/internal/model"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+type DeviceEntProvider struct {
+	client *ent.Client
+}
+
+func New() DeviceEntProvider {
+	return DeviceEntProvider{ent_client.Get()}
+}
+
+func fromEntDevice(device ent.Device) model.Device {
+	return model.Device{
+		ID:               device.ID,
+		Name:             device.Name,
+		Kind:             device.Kind,
+		Branch:           device.Branch,
+		Privacy:          device.Privacy,
+		PairingExpiresAt: device.PairingExpiresAt,
+		PairedAt:         device.PairedAt,
+		RevokedAt:        device.RevokedAt,
+		CreatedAt:        device.CreatedAt,
+	}
+}
+
+func (d DeviceEntProvider) CreateDevice(ctx context.Context, dev model.Device, pairing model.DevicePairing) (model.Device, error) {
+	created, err := d.client.Device.
+		Create().
+		SetName(dev.Name).
+		SetKind(dev.Kind).
+		SetBranch(dev.Branch).
+		SetPrivacy(dev.Privacy).
+		SetPairingCodeHash(pairing.CodeHash).
+		SetPairingExpiresAt(pairing.ExpiresAt).
+		Save(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed creating Device", "name", dev.Name)
+		return model.Device{}, fmt.Errorf("failed creating Device: %w", err)
+	}
+	return fromEntDevice(*created), nil
+}
+
+func (d DeviceEntProvider) GetDevices(ctx context.Context) ([]model.Device, error) {
+	devices := []model.Device{}
+	devs, err := d.client.Device.
+		Query().
+		Order(device.ByID(sql.OrderAsc())).
+		All(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed getting Devices")
+		return devices, fmt.Errorf("failed getting Devices: %w", err)
+	}
+	for _, dev := range devs {
+		devices = append(devices, fromEntDevice(*dev))
+	}
+	return devices, nil
+}
+
+// GetDeviceCredentials returns the device with its token hash, an empty device when it does not exist
+func (d DeviceEntProvider) GetDeviceCredentials(ctx context.Context, id int) (model.Device, model.DeviceCredentials, error) {
+	dev, err := d.client.Device.Get(ctx, id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return model.Device{}, model.DeviceCredentials{}, nil
+		}
+		log.Get().Error(ctx, err, "msg", "failed querying device credentials", "device_id", id)
+		return model.Device{}, model.DeviceCredentials{}, fmt.Errorf("failed querying device credentials: %w", err)
+	}
+	return fromEntDevice(*dev), model.DeviceCredentials{TokenHash: dev.TokenHash}, nil
+}
+
+// SetDevicePairing replaces the pending pairing code of the device. It reports false when the device
+// does not exist or was revoked
+func (d DeviceEntProvider) SetDevicePairing(ctx context.Context, id int, pairing model.DevicePairing) (bool, error) {
+	updated, err := d.client.Device.
+		Update().
+		Where(device.ID(id), device.RevokedAtIsNil()).
+		SetPairingCodeHash(pairing.CodeHash).
+		SetPairingExpiresAt(pairing.ExpiresAt).
+		Save(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed updating Device pairing", "device_id", id)
+		return false, fmt.Errorf("failed updating Device pairing: %w", err)
+	}
+	return updated > 0, nil
+}
+
+// PairDevice uses the pairing code once, replacing the device token. It returns an empty device when the
+// code is unknown, expired or was just used by another device
+func (d DeviceEntProvider) PairDevice(ctx context.Context, codeHash string, credentials model.DeviceCredentials) (model.Device, error) {
+	now := time.Now()
+	dev, err := d.client.Device.
+		Query().
+		Where(device.PairingCodeHash(codeHash), device.PairingExpiresAtGT(now), device.RevokedAtIsNil()).
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return model.Device{}, nil
+		}
+		log.Get().Error(ctx, err, "msg", "failed querying device by pairing code")
+		return model.Device{}, fmt.Errorf("failed querying device by pairing code: %w", err)
+	}
+	// the code is cleared in the same statement, a concurrent pairing with it updates nothing
+	updated, err := d.client.Device.
+		Update().
+		Where(device.ID(dev.ID), device.PairingCodeHash(codeHash)).
+		SetTokenHash(credentials.TokenHash).
+		SetPairedAt(now).
+		ClearPairingCodeHash().
+		ClearPairingExpiresAt().
+		Save(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed pairing Device", "device_id", dev.ID)
+		return model.Device{}, fmt.Errorf("failed pairing Device: %w", err)
+	}
+	if updated == 0 {
+		return model.Device{}, nil
+	}
+	dev.PairedAt = &now
+	dev.PairingExpiresAt = nil
+	return fromEntDevice(*dev), nil
+}
+
+// RevokeDevice rejects the device token from then on. It reports false when the device does not exist
+// or was already revoked
+func (d DeviceEntProvider) RevokeDevice(ctx context.Context, id int) (bool, error) {
+	updated, err := d.client.Device.
+		Update().
+		Where(device.ID(id), device.RevokedAtIsNil()).
+		SetRevokedAt(time.Now()).
+		ClearTokenHash().
+		ClearPairingCodeHash().
+		ClearPairingExpiresAt().
+		Save(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed revoking Device", "device_id", id)
+		return false, fmt.Errorf("failed revoking Device: %w", err)
+	}
+	return updated > 0, nil
+}
+
+// SetDevicePrivacy changes how the monitor identifies the guides. It reports false when the device does
+// not exist or was revoked
+func (d DeviceEntProvider) SetDevicePrivacy(ctx context.Context, id int, privacy string) (bool, error) {
+	updated, err := d.client.Device.
+		Update().
+		Where(device.ID(id), device.RevokedAtIsNil()).
+		SetPrivacy(privacy).
+		Save(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed updating Device privacy", "device_id", id)
+		return false, fmt.Errorf("failed updating Device privacy: %w", err)
+	}
+	return updated > 0, nil
+}
@@ -0,0 +1,47 @@
+package mock_device_provider
+
+import (
+	"context"
+	"via/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeviceProvider struct {
+	mock.Mock
+}
+
+func (m *MockDeviceProvider) CreateDevice(ctx context.Context, device model.Device, pairing model.DevicePairing) (model.Device, error) {
+	args := m.Called(ctx, device, pairing)
+	return args.Get(0).(model.Device), args.Error(1)
+}
+
+func (m *MockDeviceProvider) GetDevices(ctx context.Context) ([]model.Device, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Device), args.Error(1)
+}
+
+func (m *MockDeviceProvider) GetDeviceCredentials(ctx context.Context, id int) (model.Device, model.DeviceCredentials, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(model.Device), args.Get(1).(model.DeviceCredentials), args.Error(2)
+}
+
+func (m *MockDeviceProvider) SetDevicePairing(ctx context.Context, id int, pairing model.DevicePairing) (bool, error) {
+	args := m.Called(ctx, id, pairing)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDeviceProvider) PairDevice(ctx context.Context, codeHash string, credentials model.DeviceCredentials) (model.Device, error) {
+	args := m.Called(ctx, codeHash, credentials)
+	return args.Get(0).(model.Device), args.Error(1)
+}
+
+func (m *MockDeviceProvider) RevokeDevice(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDeviceProvider) SetDevicePrivacy(ctx context.Context, id int, privacy string) (bool, error) {
+	args := m.Called(ctx, id, privacy)
+	return args.Bool(0), args.Error(1)
+}
@@ -2,13 +2,21 @@ package guide_ent_provider
 
 import (
 	"context"
+	dbsql "database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+	biz_guide_search "via/internal/biz/guide/search"
 	biz_guide_status "via/internal/biz/guide/status"
 	biz_operator "via/internal/biz/operator"
 	ent_client "via/internal/client/ent"
+	db_pool "via/internal/db/pool"
 	"via/internal/ent"
 	"via/internal/ent/guide"
 	"via/internal/ent/guidehistory"
+	"via/internal/ent/predicate"
 	"via/internal/log"
 	"via/internal/model"
 
@@ -17,10 +25,11 @@ import (
 
 type GuideEntProvider struct {
 	client *ent.Client
+	db     *dbsql.DB // archival moves rows between tables and syncs keep updated_at, which ent does not model
 }
 
 func New() GuideEntProvider {
-	return GuideEntProvider{ent_client.Get()}
+	return GuideEntProvider{ent_client.Get(), db_pool.Get()}
 }
 
 func fromEntGuide(guide ent.Guide) model.Guide {
@@ -32,22 +41,30 @@ func fromEntGuide(guide ent.Guide) model.Guide {
 			Name:    guide.Edges.Operator.Name,
 			Enabled: guide.Edges.Operator.Enabled}
 	}
-	return model.Guide{
-		ID:         guide.ID,
-		ViaGuideID: guide.ViaGuideID,
-		Recipient:  guide.Recipient,
-		Payment:    guide.Payment,
-		Status:     guide.Status,
-		Operator:   operator,
-		CreatedAt:  guide.CreatedAt,
-		UpdatedAt:  guide.UpdatedAt,
+	g := model.Guide{
+		ID:           guide.ID,
+		ViaGuideID:   guide.ViaGuideID,
+		Recipient:    guide.Recipient,
+		Payment:      guide.Payment,
+		Status:       guide.Status,
+		StatusReason: guide.StatusReason,
+		Operator:     operator,
+		CreatedAt:    guide.CreatedAt,
+		UpdatedAt:    guide.UpdatedAt,
 	}
+	// guides created before the snapshot was stored have never been synced
+	if guide.ViaSyncedAt != nil {
+		g.ViaSnapshot = &guide.ViaSnapshot
+		g.ViaSyncedAt = guide.ViaSyncedAt
+	}
+	return g
 }
 
 func fromEntGuideHistory(guideHistory ent.GuideHistory) model.GuideHistory {
 	return model.GuideHistory{
 		ID:        guideHistory.ID,
 		Status:    guideHistory.Status,
+		Reason:    guideHistory.Reason,
 		Timestamp: guideHistory.CreatedAt,
 	}
 }
@@ -76,8 +93,11 @@ func (p GuideEntProvider) CreateGuide(ctx context.Context, viaGuide model.ViaGui
 		SetViaGuideID(viaGuide.ID).
 		SetStatus(biz_guide_status.INITIAL).
 		SetRecipient(viaGuide.Recipient).
+		SetRecipientNormalized(biz_guide_search.NormalizeName(viaGuide.Recipient)).
 		SetOperatorID(biz_operator.OPERATOR_SYSTEM).
 		SetPayment(viaGuide.Payment).
+		SetViaSnapshot(viaGuide).
+		SetViaSyncedAt(time.Now()).
 		Save(ctx)
 	if err != nil {
 		log.Get().Error(ctx, err, "msg", "failed creating Guide")
@@ -104,6 +124,188 @@ func (p GuideEntProvider) GetGuidesByStatus(ctx context.Context, status []string
 	return guides, nil
 }
 
+func (p GuideEntProvider) GetGuidesByStatusUpdatedAfter(ctx context.Context, status []string, after time.Time) ([]model.Guide, error) {
+	guides := []model.Guide{}
+	gp, err := p.client.Guide.
+		Query().
+		Where(guide.StatusIn(status...), guide.UpdatedAtGT(after)).
+		WithOperator().
+		Order(guide.ByUpdatedAt(sql.OrderAsc())).
+		All(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed getting Guides by status updated after", "after", after)
+		return guides, fmt.Errorf("failed getting Guides by status updated after: %w", err)
+	}
+	for _, guide := range gp {
+		guides = append(guides, fromEntGuide(*guide))
+	}
+	return guides, nil
+}
+
+func (p GuideEntProvider) GetGuidesByStatusUpdatedBefore(ctx context.Context, status []string, before time.Time) ([]model.Guide, error) {
+	guides := []model.Guide{}
+	gp, err := p.client.Guide.
+		Query().
+		Where(guide.StatusIn(status...), guide.UpdatedAtLT(before)).
+		Order(guide.ByUpdatedAt(sql.OrderAsc())).
+		All(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed getting Guides by status updated before", "before", before)
+		return guides, fmt.Errorf("failed getting Guides by status updated before: %w", err)
+	}
+	for _, guide := range gp {
+		guides = append(guides, fromEntGuide(*guide))
+	}
+	return guides, nil
+}
+
+// SearchGuides returns a page of the guides matching search, in any status. Pages are keyed by
+// the sort value and the id of the last guide, so concurrent changes do not shift them.
+func (p GuideEntProvider) SearchGuides(ctx context.Context, search model.GuideSearch) (model.GuidePage, error) {
+	page := model.GuidePage{Guides: []model.Guide{}}
+	where := searchPredicates(search)
+
+	column := guide.FieldCreatedAt
+	if search.Sort == biz_guide_search.SORT_UPDATED_AT {
+		column = guide.FieldUpdatedAt
+	}
+	order, after, afterID := ent.Asc(column, guide.FieldID), sql.FieldGT, guide.IDGT
+	if search.Desc {
+		order, after, afterID = ent.Desc(column, guide.FieldID), sql.FieldLT, guide.IDLT
+	}
+	if search.Cursor != nil {
+		where = append(where, guide.Or(
+			after(column, search.Cursor.Value),
+			guide.And(sql.FieldEQ(column, search.Cursor.Value), afterID(search.Cursor.ID)),
+		))
+	}
+
+	// one more than the limit tells whether there is a next page
+	gp, err := p.client.Guide.
+		Query().
+		Where(where...).
+		WithOperator().
+		Order(order).
+		Limit(search.Limit + 1).
+		All(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed searching Guides")
+		return page, fmt.Errorf("failed searching Guides: %w", err)
+	}
+	if len(gp) > search.Limit {
+		gp = gp[:search.Limit]
+		last := gp[len(gp)-1]
+		page.Next = &model.GuideCursor{Value: last.CreatedAt, ID: last.ID}
+		if column == guide.FieldUpdatedAt {
+			page.Next.Value = last.UpdatedAt
+		}
+	}
+	for _, guide := range gp {
+		page.Guides = append(page.Guides, fromEntGuide(*guide))
+	}
+	return page, nil
+}
+
+// CountGuides returns how many guides match the search filters, the cursor and limit are ignored
+func (p GuideEntProvider) CountGuides(ctx context.Context, search model.GuideSearch) (int, error) {
+	count, err := p.client.Guide.
+		Query().
+		Where(searchPredicates(search)...).
+		Count(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed counting Guides")
+		return 0, fmt.Errorf("failed counting Guides: %w", err)
+	}
+	return count, nil
+}
+
+func searchPredicates(search model.GuideSearch) []predicate.Guide {
+	where := []predicate.Guide{}
+	if search.ViaGuideIDPrefix != "" {
+		where = append(where, guide.ViaGuideIDHasPrefix(search.ViaGuideIDPrefix))
+	}
+	for _, word := range strings.Fields(search.Recipient) {
+		where = append(where, guide.RecipientNormalizedContains(word))
+	}
+	if search.OperatorID != 0 {
+		where = append(where, guide.OperatorID(search.OperatorID))
+	}
+	if len(search.Status) > 0 {
+		where = append(where, guide.StatusIn(search.Status...))
+	}
+	if search.Payment != "" {
+		where = append(where, guide.Payment(search.Payment))
+	}
+	if search.From != nil {
+		where = append(where, guide.CreatedAtGTE(*search.From))
+	}
+	if search.To != nil {
+		where = append(where, guide.CreatedAtLT(*search.To))
+	}
+	return where
+}
+
+// GetOperatorWorkload returns how many guides in status each operator has, by operator id
+func (p GuideEntProvider) GetOperatorWorkload(ctx context.Context, status []string) (map[int]int, error) {
+	workload := map[int]int{}
+	var rows []struct {
+		OperatorID int `json:"operator_id"`
+		Count      int `json:"count"`
+	}
+	err := p.client.Guide.
+		Query().
+		Where(guide.StatusIn(status...)).
+		GroupBy(guide.FieldOperatorID).
+		Aggregate(ent.Count()).
+		Scan(ctx, &rows)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed getting operator workload")
+		return workload, fmt.Errorf("failed getting operator workload: %w", err)
+	}
+	for _, row := range rows {
+		workload[row.OperatorID] = row.Count
+	}
+	return workload, nil
+}
+
+// AssignGuide moves the guide to the operator to, only when it still belongs to the operator from.
+// It reports false when somebody else took the guide in the meantime.
+func (p GuideEntProvider) AssignGuide(ctx context.Context, guideId int, from int, to int) (bool, error) {
+	_, err := p.client.Guide.
+		UpdateOneID(guideId).
+		Where(guide.OperatorID(from)).
+		SetOperatorID(to).
+		Save(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return false, nil
+		}
+		log.Get().Error(ctx, err, "msg", "error assigning guide", "guide_id", guideId, "operator_id", to)
+		return false, fmt.Errorf("failed assigning guide: %w", err)
+	}
+	return true, nil
+}
+
+// ExpireGuide puts the guide on hold as the SYSTEM operator, only when it is still in status
+// and untouched since before. It reports false when the guide changed in the meantime.
+func (p GuideEntProvider) ExpireGuide(ctx context.Context, guideId int, status string, before time.Time, reason string) (bool, error) {
+	_, err := p.client.Guide.
+		UpdateOneID(guideId).
+		Where(guide.Status(status), guide.UpdatedAtLT(before)).
+		SetStatus(biz_guide_status.ON_HOLD).
+		SetOperatorID(biz_operator.OPERATOR_SYSTEM).
+		SetStatusReason(reason).
+		Save(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return false, nil
+		}
+		log.Get().Error(ctx, err, "msg", "error expiring guide", "guide_id", guideId)
+		return false, fmt.Errorf("failed expiring guide: %w", err)
+	}
+	return true, nil
+}
+
 func (p GuideEntProvider) UpdateGuide(ctx context.Context, guide model.Guide) error {
 	guideUpdateOne := p.client.Guide.UpdateOneID(guide.ID)
 	if guide.Operator.ID != 0 {
@@ -111,6 +313,19 @@ func (p GuideEntProvider) UpdateGuide(ctx context.Context, guide model.Guide) er
 	}
 	if guide.Status != "" {
 		guideUpdateOne.SetStatus(guide.Status)
+		// the reason belongs to the status, a new status without one clears it
+		if guide.StatusReason != "" {
+			guideUpdateOne.SetStatusReason(guide.StatusReason)
+		} else {
+			guideUpdateOne.ClearStatusReason()
+		}
+	}
+	if guide.ViaSnapshot != nil {
+		syncedAt := time.Now()
+		if guide.ViaSyncedAt != nil {
+			syncedAt = *guide.ViaSyncedAt
+		}
+		guideUpdateOne.SetViaSnapshot(*guide.ViaSnapshot).SetViaSyncedAt(syncedAt)
 	}
 	guideUpdated, err := guideUpdateOne.Save(ctx)
 	if err != nil {
@@ -121,6 +336,22 @@ func (p GuideEntProvider) UpdateGuide(ctx context.Context, guide model.Guide) er
 	return err
 }
 
+// syncGuideQuery leaves updated_at out, ent would set it on any update
+const syncGuideQuery = `UPDATE guides SET via_snapshot = $1, via_synced_at = $2 WHERE id = $3`
+
+func (p GuideEntProvider) SyncGuide(ctx context.Context, guideId int, snapshot model.ViaGuide, syncedAt time.Time) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed encoding guide snapshot: %w", err)
+	}
+	if _, err := p.db.ExecContext(ctx, syncGuideQuery, data, syncedAt, guideId); err != nil {
+		log.Get().Error(ctx, err, "msg", "error syncing guide", "guide_id", guideId)
+		return fmt.Errorf("failed syncing guide: %w", err)
+	}
+	log.Get().Info(ctx, "msg", "guide synced", "guide_id", guideId)
+	return nil
+}
+
 func (p GuideEntProvider) GetGuideById(ctx context.Context, id int) (model.Guide, error) {
 	guide, err := p.client.Guide.Get(ctx, id)
 	if err != nil {
@@ -154,3 +385,86 @@ func (p GuideEntProvider) GetGuideHistory(ctx context.Context, guideId int) ([]m
 	}
 	return guideHistory, nil
 }
+
+const (
+	// the ids archived by this run scope the history and the deletes, the archive keeps growing
+	archiveGuidesQuery = `INSERT INTO guides_archive (id, via_guide_id, recipient, status, status_reason, payment,
+		operator_id, via_snapshot, via_synced_at, created_at, updated_at)
+	SELECT id, via_guide_id, recipient, status, status_reason, payment,
+		operator_id, via_snapshot, via_synced_at, created_at, updated_at
+	FROM guides WHERE status = ANY($1) AND updated_at < $2
+	FOR UPDATE
+	RETURNING id`
+	archiveGuideHistoriesQuery = `INSERT INTO guide_histories_archive (id, guide_id, status, reason, operator_id, created_at)
+	SELECT id, guide_id, status, reason, operator_id, created_at
+	FROM guide_histories WHERE guide_id = ANY($1)`
+	deleteArchivedGuideHistoriesQuery = `DELETE FROM guide_histories WHERE guide_id = ANY($1)`
+	deleteArchivedGuidesQuery         = `DELETE FROM guides WHERE id = ANY($1)`
+	archivedGuideQuery                = `SELECT id, status FROM guides_archive WHERE via_guide_id = $1
+	ORDER BY created_at DESC LIMIT 1`
+)
+
+// ArchiveGuides moves the guides in status not updated since before, along with their history,
+// to the archive tables in a single transaction. It returns the number of archived guides.
+func (p GuideEntProvider) ArchiveGuides(ctx context.Context, status []string, before time.Time) (int, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed starting archive transaction")
+		return 0, fmt.Errorf("failed starting archive transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := archiveGuides(ctx, tx, status, before)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed archiving guides", "before", before)
+		return 0, fmt.Errorf("failed archiving guides: %w", err)
+	}
+	archived := len(ids)
+	if archived > 0 {
+		for _, query := range []string{archiveGuideHistoriesQuery, deleteArchivedGuideHistoriesQuery, deleteArchivedGuidesQuery} {
+			if _, err := tx.ExecContext(ctx, query, ids); err != nil {
+				log.Get().Error(ctx, err, "msg", "failed archiving guides", "before", before)
+				return 0, fmt.Errorf("failed archiving guides: %w", err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Get().Error(ctx, err, "msg", "failed committing archive transaction")
+		return 0, fmt.Errorf("failed committing archive transaction: %w", err)
+	}
+	log.Get().Info(ctx, "msg", "guides archived", "archived", archived, "before", before)
+	return archived, nil
+}
+
+// archiveGuides copies the guides to the archive, returning the ids copied
+func archiveGuides(ctx context.Context, tx *dbsql.Tx, status []string, before time.Time) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, archiveGuidesQuery, status, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetArchivedGuideByViaGuideId returns the last archived guide of the via guide id, with its id and
+// status, an empty guide when none was archived
+func (p GuideEntProvider) GetArchivedGuideByViaGuideId(ctx context.Context, viaGuideId string) (model.Guide, error) {
+	guide := model.Guide{ViaGuideID: viaGuideId}
+	err := p.db.QueryRowContext(ctx, archivedGuideQuery, viaGuideId).Scan(&guide.ID, &guide.Status)
+	if err != nil {
+		if errors.Is(err, dbsql.ErrNoRows) {
+			return model.Guide{}, nil
+		}
+		log.Get().Error(ctx, err, "msg", "failed querying archived guide by viaGuideId")
+		return model.Guide{}, fmt.Errorf("failed querying archived guide by viaGuideId: %w", err)
+	}
+	return guide, nil
+}
@@ -3,6 +3,7 @@ package guide_provider
 import (
 	"context"
 	"sync"
+	"time"
 	"via/internal/model"
 )
 
@@ -10,7 +11,19 @@ type GuideProvider interface {
 	GetGuideByViaGuideId(ctx context.Context, viaGuideId string) (model.Guide, error)
 	CreateGuide(ctx context.Context, guide model.ViaGuide) (int, error)
 	GetGuidesByStatus(ctx context.Context, status []string) ([]model.Guide, error)
+	GetGuidesByStatusUpdatedAfter(ctx context.Context, status []string, after time.Time) ([]model.Guide, error)
+	GetGuidesByStatusUpdatedBefore(ctx context.Context, status []string, before time.Time) ([]model.Guide, error)
+	SearchGuides(ctx context.Context, search model.GuideSearch) (model.GuidePage, error)
+	CountGuides(ctx context.Context, search model.GuideSearch) (int, error)
+	GetOperatorWorkload(ctx context.Context, status []string) (map[int]int, error)
+	AssignGuide(ctx context.Context, guideId int, from int, to int) (bool, error)
+	ExpireGuide(ctx context.Context, guideId int, status string, before time.Time, reason string) (bool, error)
+	ArchiveGuides(ctx context.Context, status []string, before time.Time) (int, error)
+	GetArchivedGuideByViaGuideId(ctx context.Context, viaGuideId string) (model.Guide, error)
 	UpdateGuide(ctx context.Context, guide model.Guide) error
+	// SyncGuide stores the carrier snapshot of the guide, keeping its updated_at since a refresh
+	// from the carrier is not a change of the guide
+	SyncGuide(ctx context.Context, guideId int, snapshot model.ViaGuide, syncedAt time.Time) error
 	GetGuideById(ctx context.Context, id int) (model.Guide, error)
 	GetGuideHistory(ctx context.Context, guideId int) ([]model.GuideHistory, error)
 }
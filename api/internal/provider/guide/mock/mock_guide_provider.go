@@ -2,6 +2,7 @@ package mock_guide_provider
 
 import (
 	"context"
+	"time"
 	"via/internal/model"
 
 	"github.com/stretchr/testify/mock"
@@ -26,11 +27,56 @@ func (m *MockGuideProvider) GetGuidesByStatus(ctx context.Context, status []stri
 	return args.Get(0).([]model.Guide), args.Error(1)
 }
 
+func (m *MockGuideProvider) GetGuidesByStatusUpdatedAfter(ctx context.Context, status []string, after time.Time) ([]model.Guide, error) {
+	args := m.Called(ctx, status, after)
+	return args.Get(0).([]model.Guide), args.Error(1)
+}
+
+func (m *MockGuideProvider) GetGuidesByStatusUpdatedBefore(ctx context.Context, status []string, before time.Time) ([]model.Guide, error) {
+	args := m.Called(ctx, status, before)
+	return args.Get(0).([]model.Guide), args.Error(1)
+}
+
+func (m *MockGuideProvider) SearchGuides(ctx context.Context, search model.GuideSearch) (model.GuidePage, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).(model.GuidePage), args.Error(1)
+}
+
+func (m *MockGuideProvider) CountGuides(ctx context.Context, search model.GuideSearch) (int, error) {
+	args := m.Called(ctx, search)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockGuideProvider) GetOperatorWorkload(ctx context.Context, status []string) (map[int]int, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).(map[int]int), args.Error(1)
+}
+
+func (m *MockGuideProvider) AssignGuide(ctx context.Context, guideId int, from int, to int) (bool, error) {
+	args := m.Called(ctx, guideId, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockGuideProvider) ExpireGuide(ctx context.Context, guideId int, status string, before time.Time, reason string) (bool, error) {
+	args := m.Called(ctx, guideId, status, before, reason)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockGuideProvider) ArchiveGuides(ctx context.Context, status []string, before time.Time) (int, error) {
+	args := m.Called(ctx, status, before)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockGuideProvider) UpdateGuide(ctx context.Context, guide model.Guide) error {
 	args := m.Called(ctx, guide)
 	return args.Error(0)
 }
 
+func (m *MockGuideProvider) SyncGuide(ctx context.Context, guideId int, snapshot model.ViaGuide, syncedAt time.Time) error {
+	args := m.Called(ctx, guideId, snapshot, syncedAt)
+	return args.Error(0)
+}
+
 func (m *MockGuideProvider) GetGuideById(ctx context.Context, id int) (model.Guide, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(model.Guide), args.Error(1)
@@ -40,3 +86,8 @@ func (m *MockGuideProvider) GetGuideHistory(ctx context.Context, guideId int) ([
 	args := m.Called(ctx, guideId)
 	return args.Get(0).([]model.GuideHistory), args.Error(1)
 }
+
+func (m *MockGuideProvider) GetArchivedGuideByViaGuideId(ctx context.Context, viaGuideId string) (model.Guide, error) {
+	args := m.Called(ctx, viaGuideId)
+	return args.Get(0).(model.Guide), args.Error(1)
+}
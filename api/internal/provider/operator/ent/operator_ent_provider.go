@@ -7,6 +7,7 @@ import (
 	ent_client "via/internal/client/ent"
 	"via/internal/ent"
 	"via/internal/ent/operator"
+	"via/internal/ent/operatoridentity"
 	"via/internal/log"
 	"via/internal/model"
 
@@ -23,10 +24,12 @@ func New() OperatorEntProvider {
 
 func fromEntOperator(operator ent.Operator) model.Operator {
 	return model.Operator{
-		ID:      operator.ID,
-		Name:    operator.Name,
-		Account: operator.Account,
-		Enabled: operator.Enabled,
+		ID:           operator.ID,
+		Name:         operator.Name,
+		Account:      operator.Account,
+		Enabled:      operator.Enabled,
+		Role:         operator.Role,
+		Availability: operator.Availability,
 	}
 }
 
@@ -63,3 +66,142 @@ func (o OperatorEntProvider) GetOperatorByAccount(ctx context.Context, account s
 	}
 	return fromEntOperator(*operator), err
 }
+
+func (o OperatorEntProvider) CreateOperator(ctx context.Context, operator model.Operator) (int, error) {
+	op, err := o.client.Operator.
+		Create().
+		SetAccount(operator.Account).
+		SetName(operator.Name).
+		SetEnabled(operator.Enabled).
+		SetRole(operator.Role).
+		Save(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed creating Operator", "account", operator.Account)
+		return 0, fmt.Errorf("failed creating Operator: %w", err)
+	}
+	return op.ID, nil
+}
+
+// SetOperatorEnabled enables or disables the operator, reporting false when the account does not exist
+func (o OperatorEntProvider) SetOperatorEnabled(ctx context.Context, account string, enabled bool) (bool, error) {
+	updated, err := o.client.Operator.
+		Update().
+		Where(operator.AccountEQ(account)).
+		SetEnabled(enabled).
+		Save(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed updating Operator", "account", account)
+		return false, fmt.Errorf("failed updating Operator: %w", err)
+	}
+	return updated > 0, nil
+}
+
+func (o OperatorEntProvider) GetOperatorById(ctx context.Context, id int) (model.Operator, error) {
+	operator, err := o.client.Operator.Get(ctx, id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return model.Operator{}, nil
+		}
+		log.Get().Error(ctx, err, "msg", "failed querying operator by id")
+		return model.Operator{}, fmt.Errorf("failed querying operator by id: %w", err)
+	}
+	return fromEntOperator(*operator), nil
+}
+
+// GetOperatorsByAvailability returns the enabled operators in availability, SYSTEM excluded
+func (o OperatorEntProvider) GetOperatorsByAvailability(ctx context.Context, availability string) ([]model.Operator, error) {
+	operators := []model.Operator{}
+	ops, err := o.client.Operator.
+		Query().
+		Where(operator.IDNEQ(biz_operator.OPERATOR_SYSTEM), operator.Enabled(true), operator.Availability(availability)).
+		Order(operator.ByID(sql.OrderAsc())).
+		All(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed getting Operators by availability", "availability", availability)
+		return operators, fmt.Errorf("failed getting Operators by availability: %w", err)
+	}
+	for _, op := range ops {
+		operators = append(operators, fromEntOperator(*op))
+	}
+	return operators, nil
+}
+
+func (o OperatorEntProvider) SetOperatorAvailability(ctx context.Context, id int, availability string) error {
+	err := o.client.Operator.
+		UpdateOneID(id).
+		SetAvailability(availability).
+		Exec(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed updating Operator availability", "operator_id", id)
+		return fmt.Errorf("failed updating Operator availability: %w", err)
+	}
+	return nil
+}
+
+// GetOperatorByIdentity returns the operator the IdP account is linked to, an empty operator when none is
+func (o OperatorEntProvider) GetOperatorByIdentity(ctx context.Context, issuer string, subject string) (model.Operator, error) {
+	operator, err := o.client.OperatorIdentity.
+		Query().
+		Where(operatoridentity.Issuer(issuer), operatoridentity.Subject(subject)).
+		QueryOperator().
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return model.Operator{}, nil
+		}
+		log.Get().Error(ctx, err, "msg", "failed querying operator by identity", "issuer", issuer)
+		return model.Operator{}, fmt.Errorf("failed querying operator by identity: %w", err)
+	}
+	return fromEntOperator(*operator), nil
+}
+
+func (o OperatorEntProvider) LinkOperatorIdentity(ctx context.Context, id int, issuer string, subject string) error {
+	err := o.client.OperatorIdentity.
+		Create().
+		SetOperatorID(id).
+		SetIssuer(issuer).
+		SetSubject(subject).
+		Exec(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed linking Operator identity", "operator_id", id, "issuer", issuer)
+		return fmt.Errorf("failed linking Operator identity: %w", err)
+	}
+	return nil
+}
+
+// GetOperatorCredentials returns the operator with its local login secrets, an empty operator when the account does not exist
+func (o OperatorEntProvider) GetOperatorCredentials(ctx context.Context, account string) (model.Operator, model.OperatorCredentials, error) {
+	operator, err := o.client.Operator.
+		Query().
+		Where(operator.AccountEQ(account)).
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return model.Operator{}, model.OperatorCredentials{}, nil
+		}
+		log.Get().Error(ctx, err, "msg", "failed querying operator credentials")
+		return model.Operator{}, model.OperatorCredentials{}, fmt.Errorf("failed querying operator credentials: %w", err)
+	}
+	return fromEntOperator(*operator), model.OperatorCredentials{
+		PasswordHash: operator.PasswordHash,
+		TOTPSecret:   operator.TotpSecret,
+	}, nil
+}
+
+// SetOperatorCredentials replaces the local login secrets, empty ones disable it. It reports false when the account does not exist
+func (o OperatorEntProvider) SetOperatorCredentials(ctx context.Context, account string, credentials model.OperatorCredentials) (bool, error) {
+	update := o.client.Operator.
+		Update().
+		Where(operator.AccountEQ(account))
+	if credentials.PasswordHash == "" {
+		update.ClearPasswordHash().ClearTotpSecret()
+	} else {
+		update.SetPasswordHash(credentials.PasswordHash).SetTotpSecret(credentials.TOTPSecret)
+	}
+	updated, err := update.Save(ctx)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "failed updating Operator credentials", "account", account)
+		return false, fmt.Errorf("failed updating Operator credentials: %w", err)
+	}
+	return updated > 0, nil
+}
@@ -20,3 +20,48 @@ func (m *MockOperatorProvider) GetOperatorByAccount(ctx context.Context, account
 	args := m.Called(ctx, account)
 	return args.Get(0).(model.Operator), args.Error(1)
 }
+
+func (m *MockOperatorProvider) CreateOperator(ctx context.Context, operator model.Operator) (int, error) {
+	args := m.Called(ctx, operator)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOperatorProvider) SetOperatorEnabled(ctx context.Context, account string, enabled bool) (bool, error) {
+	args := m.Called(ctx, account, enabled)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOperatorProvider) GetOperatorById(ctx context.Context, id int) (model.Operator, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(model.Operator), args.Error(1)
+}
+
+func (m *MockOperatorProvider) GetOperatorsByAvailability(ctx context.Context, availability string) ([]model.Operator, error) {
+	args := m.Called(ctx, availability)
+	return args.Get(0).([]model.Operator), args.Error(1)
+}
+
+func (m *MockOperatorProvider) SetOperatorAvailability(ctx context.Context, id int, availability string) error {
+	args := m.Called(ctx, id, availability)
+	return args.Error(0)
+}
+
+func (m *MockOperatorProvider) GetOperatorByIdentity(ctx context.Context, issuer string, subject string) (model.Operator, error) {
+	args := m.Called(ctx, issuer, subject)
+	return args.Get(0).(model.Operator), args.Error(1)
+}
+
+func (m *MockOperatorProvider) LinkOperatorIdentity(ctx context.Context, id int, issuer string, subject string) error {
+	args := m.Called(ctx, id, issuer, subject)
+	return args.Error(0)
+}
+
+func (m *MockOperatorProvider) GetOperatorCredentials(ctx context.Context, account string) (model.Operator, model.OperatorCredentials, error) {
+	args := m.Called(ctx, account)
+	return args.Get(0).(model.Operator), args.Get(1).(model.OperatorCredentials), args.Error(2)
+}
+
+func (m *MockOperatorProvider) SetOperatorCredentials(ctx context.Context, account string, credentials model.OperatorCredentials) (bool, error) {
+	args := m.Called(ctx, account, credentials)
+	return args.Bool(0), args.Error(1)
+}
@@ -9,6 +9,15 @@ import (
 type OperatorProvider interface {
 	GetOperators(ctx context.Context) ([]model.Operator, error)
 	GetOperatorByAccount(ctx context.Context, account string) (model.Operator, error)
+	CreateOperator(ctx context.Context, operator model.Operator) (int, error)
+	SetOperatorEnabled(ctx context.Context, account string, enabled bool) (bool, error)
+	GetOperatorById(ctx context.Context, id int) (model.Operator, error)
+	GetOperatorsByAvailability(ctx context.Context, availability string) ([]model.Operator, error)
+	SetOperatorAvailability(ctx context.Context, id int, availability string) error
+	GetOperatorByIdentity(ctx context.Context, issuer string, subject string) (model.Operator, error)
+	LinkOperatorIdentity(ctx context.Context, id int, issuer string, subject string) error
+	GetOperatorCredentials(ctx context.Context, account string) (model.Operator, model.OperatorCredentials, error)
+	SetOperatorCredentials(ctx context.Context, account string, credentials model.OperatorCredentials) (bool, error)
 }
 
 var (
@@ -0,0 +1,106 @@
+package via_guide_api_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	http_client "via/internal/client/http"
+	"via/internal/log"
+	"via/internal/model"
+	via_guide_provider "via/internal/provider/via/guide"
+	"via/internal/secret"
+)
+
+// ViaGuideAPIProvider fetches guides from the carrier JSON API.
+type ViaGuideAPIProvider struct {
+	client *http_client.HttpClient
+}
+
+type destinationResponse struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+type guideResponse struct {
+	ID          string              `json:"id"`
+	Reference   string              `json:"reference"`
+	Status      string              `json:"status"`
+	Packages    int                 `json:"packages"`
+	Weight      float64             `json:"weight"`
+	Payment     string              `json:"payment"`
+	Route       string              `json:"route"`
+	Date        string              `json:"date"`
+	Sender      string              `json:"sender"`
+	Recipient   string              `json:"recipient"`
+	Destination destinationResponse `json:"destination"`
+}
+
+func New(cfg http_client.HttpClientCfg) *ViaGuideAPIProvider {
+	if cfg.AuthorizationHeaderSecret == "" && cfg.AuthorizationHeaderSecretFile != "" {
+		cfg.AuthorizationHeaderSecret = secret.Get().Read(cfg.AuthorizationHeaderSecretFile)
+	}
+	return &ViaGuideAPIProvider{client: http_client.New(cfg)}
+}
+
+func (p *ViaGuideAPIProvider) GetGuide(ctx context.Context, id string) (model.ViaGuide, error) {
+	logger := log.Get()
+	reqRes := fmt.Sprintf("%s/guides/%s", p.client.BaseURL, url.PathEscape(id))
+	logger.Info(ctx, "msg", "http request", "resource", reqRes)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqRes, nil)
+	if err != nil {
+		logger.Error(ctx, err, "msg", "error creatig request")
+		return model.ViaGuide{}, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.client.AuthorizationHeader != "" {
+		req.Header.Set("Authorization", p.client.AuthorizationHeader)
+	}
+
+	resp, err := p.client.Requester.Do(req)
+	if err != nil {
+		logger.Error(ctx, err, "msg", "error making HTTP request")
+		return model.ViaGuide{}, fmt.Errorf("%w: making HTTP request: %w", via_guide_provider.ErrTransient, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return model.ViaGuide{}, nil
+	case via_guide_provider.IsTransientStatus(resp.StatusCode):
+		logger.Error(ctx, nil, "msg", "unexpected status code", "status", resp.StatusCode)
+		return model.ViaGuide{}, fmt.Errorf("%w: unexpected status code: %d", via_guide_provider.ErrTransient, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		logger.Error(ctx, nil, "msg", "unexpected status code", "status", resp.StatusCode)
+		return model.ViaGuide{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error(ctx, err, "msg", "error reading response")
+		return model.ViaGuide{}, fmt.Errorf("%w: reading response: %w", via_guide_provider.ErrTransient, err)
+	}
+	var guide guideResponse
+	if err := json.Unmarshal(body, &guide); err != nil {
+		logger.Error(ctx, err, "msg", "error decoding response")
+		return model.ViaGuide{}, fmt.Errorf("decoding response: %w", err)
+	}
+	return model.ViaGuide{
+		ID:        guide.ID,
+		Reference: guide.Reference,
+		Status:    guide.Status,
+		Packages:  guide.Packages,
+		Weight:    guide.Weight,
+		Payment:   guide.Payment,
+		Route:     guide.Route,
+		Date:      guide.Date,
+		Sender:    guide.Sender,
+		Recipient: guide.Recipient,
+		Destination: model.ViaDestination{
+			ID:          guide.Destination.ID,
+			Description: guide.Destination.Description,
+		},
+	}, nil
+}
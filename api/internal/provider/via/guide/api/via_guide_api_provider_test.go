@@ -0,0 +1,129 @@
+package via_guide_api_provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"testing/iotest"
+	http_client "via/internal/client/http"
+	mock_http "via/internal/client/http/mock"
+	"via/internal/model"
+	via_guide_provider "via/internal/provider/via/guide"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func response(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewBufferString(body))}
+}
+
+func TestGetGuide(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	tests := []struct {
+		name        string
+		mockResp    *http.Response
+		mockErr     error
+		expectErr   bool
+		transient   bool
+		expectGuide model.ViaGuide
+	}{
+		{
+			name:      "http client error",
+			mockErr:   errors.New("http error"),
+			expectErr: true,
+			transient: true,
+		},
+		{
+			name:      "server error",
+			mockResp:  response(http.StatusServiceUnavailable, "down"),
+			expectErr: true,
+			transient: true,
+		},
+		{
+			name:      "rate limited",
+			mockResp:  response(http.StatusTooManyRequests, "slow down"),
+			expectErr: true,
+			transient: true,
+		},
+		{
+			name:      "request timeout",
+			mockResp:  response(http.StatusRequestTimeout, "timeout"),
+			expectErr: true,
+			transient: true,
+		},
+		{
+			name:      "body cut",
+			mockResp:  &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(iotest.ErrReader(io.ErrUnexpectedEOF))},
+			expectErr: true,
+			transient: true,
+		},
+		{
+			name:      "unexpected status",
+			mockResp:  response(http.StatusUnauthorized, "unauthorized"),
+			expectErr: true,
+		},
+		{
+			name:      "malformed body",
+			mockResp:  response(http.StatusOK, "{"),
+			expectErr: true,
+		},
+		{
+			name:        "guide not found",
+			mockResp:    response(http.StatusNotFound, ""),
+			expectGuide: model.ViaGuide{},
+		},
+		{
+			name: "success",
+			mockResp: response(http.StatusOK, `{"id":"999025862539","reference":"REF","status":"CRR","packages":2,
+				"weight":1.5,"payment":"D","route":"TUC-MDQ","date":"13/03/25","sender":"ROLDAN PAULA",
+				"recipient":"DANIELA AGUIRRE","destination":{"id":"7600","description":"MAR DEL PLATA"}}`),
+			expectGuide: model.ViaGuide{
+				ID:          "999025862539",
+				Reference:   "REF",
+				Status:      "CRR",
+				Packages:    2,
+				Weight:      1.5,
+				Payment:     "D",
+				Route:       "TUC-MDQ",
+				Date:        "13/03/25",
+				Sender:      "ROLDAN PAULA",
+				Recipient:   "DANIELA AGUIRRE",
+				Destination: model.ViaDestination{ID: "7600", Description: "MAR DEL PLATA"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRequester := new(mock_http.MockHttpRequester)
+			mockRequester.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Method == http.MethodGet &&
+					req.URL.String() == "http://fake-url.com/guides/999025862539" &&
+					req.Header.Get("Authorization") == "secret"
+			})).Return(tt.mockResp, tt.mockErr)
+
+			provider := &ViaGuideAPIProvider{client: &http_client.HttpClient{
+				Requester:           mockRequester,
+				BaseURL:             "http://fake-url.com",
+				AuthorizationHeader: "secret",
+			}}
+
+			guide, err := provider.GetGuide(context.Background(), "999025862539")
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.transient, errors.Is(err, via_guide_provider.ErrTransient))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectGuide, guide)
+			}
+			mockRequester.AssertExpectations(t)
+		})
+	}
+}
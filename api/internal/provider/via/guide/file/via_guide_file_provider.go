@@ -0,0 +1,47 @@
+package via_guide_file_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"via/internal/model"
+)
+
+type FileProviderCfg struct {
+	Dir string `env:"DIR" envDefault:"fixtures/via_guides" json:"dir"`
+}
+
+// ViaGuideFileProvider serves via guides loaded from a directory of JSON files,
+// one guide per file, to develop and demo without the carrier system.
+type ViaGuideFileProvider struct {
+	guides map[string]model.ViaGuide
+}
+
+func New(cfg FileProviderCfg) (*ViaGuideFileProvider, error) {
+	files, err := filepath.Glob(filepath.Join(cfg.Dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("listing fixtures: %w", err)
+	}
+	guides := make(map[string]model.ViaGuide, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading fixture %s: %w", file, err)
+		}
+		var guide model.ViaGuide
+		if err := json.Unmarshal(data, &guide); err != nil {
+			return nil, fmt.Errorf("decoding fixture %s: %w", file, err)
+		}
+		if guide.ID == "" {
+			return nil, fmt.Errorf("fixture %s: missing guide id", file)
+		}
+		guides[guide.ID] = guide
+	}
+	return &ViaGuideFileProvider{guides: guides}, nil
+}
+
+func (p *ViaGuideFileProvider) GetGuide(ctx context.Context, id string) (model.ViaGuide, error) {
+	return p.guides[id], nil
+}
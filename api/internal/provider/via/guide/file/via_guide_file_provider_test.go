@@ -0,0 +1,53 @@
+package via_guide_file_provider
+
+import (
+	"context"
+	"testing"
+	"via/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("invalid fixture", func(t *testing.T) {
+		_, err := New(FileProviderCfg{Dir: "testdata/invalid"})
+		assert.ErrorContains(t, err, "decoding fixture")
+	})
+
+	t.Run("fixture without id", func(t *testing.T) {
+		_, err := New(FileProviderCfg{Dir: "testdata/missing_id"})
+		assert.ErrorContains(t, err, "missing guide id")
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		p, err := New(FileProviderCfg{Dir: t.TempDir()})
+		require.NoError(t, err)
+		assert.Empty(t, p.guides)
+	})
+}
+
+func TestGetGuide(t *testing.T) {
+	p, err := New(FileProviderCfg{Dir: "testdata"})
+	require.NoError(t, err)
+
+	guide, err := p.GetGuide(context.Background(), "999025862539")
+	require.NoError(t, err)
+	assert.Equal(t, model.ViaGuide{
+		ID:          "999025862539",
+		Reference:   "999025862539",
+		Status:      "CRR",
+		Packages:    1,
+		Weight:      1.714,
+		Payment:     "D",
+		Route:       "TUC-MDQ",
+		Date:        "13/03/25",
+		Sender:      "ROLDAN PAULA",
+		Recipient:   "DANIELA AGUIRRE",
+		Destination: model.ViaDestination{ID: "7600", Description: "MAR DEL PLATA"},
+	}, guide)
+
+	guide, err = p.GetGuide(context.Background(), "000000000000")
+	require.NoError(t, err)
+	assert.Equal(t, model.ViaGuide{}, guide)
+}
@@ -0,0 +1,184 @@
+package via_guide_resilient_provider
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+	"via/internal/breaker"
+	"via/internal/cache"
+	"via/internal/log"
+	"via/internal/model"
+	via_guide_provider "via/internal/provider/via/guide"
+)
+
+type ResilienceCfg struct {
+	Retries         int `env:"RETRIES" envDefault:"2" json:"retries"`
+	RetryBackoff    int `env:"RETRY_BACKOFF" envDefault:"200" json:"retryBackoff"` // in milliseconds, doubled on each retry
+	BreakerFailures int `env:"BREAKER_FAILURES" envDefault:"5" json:"breakerFailures"`
+	BreakerOpen     int `env:"BREAKER_OPEN" envDefault:"30" json:"breakerOpen"`         // in seconds
+	CacheTTL        int `env:"CACHE_TTL" envDefault:"60" json:"cacheTtl"`               // in seconds
+	StaleTTL        int `env:"STALE_TTL" envDefault:"3600" json:"staleTtl"`             // in seconds, served while the carrier is down
+	AttemptTimeout  int `env:"ATTEMPT_TIMEOUT" envDefault:"5000" json:"attemptTimeout"` // in milliseconds for each request to the carrier, 0 for none
+	Timeout         int `env:"TIMEOUT" envDefault:"12000" json:"timeout"`               // in milliseconds for every attempt and backoff, 0 for none
+}
+
+// requestShare is the part of the time left to the request that the attempts may take, the rest
+// is kept to answer with the stale guide or the error
+const requestShare = 0.8
+
+const cacheKeyPrefix = "via_guide:"
+
+var metrics = expvar.NewMap("via_guide_provider")
+
+type cachedViaGuide struct {
+	Guide     model.ViaGuide `json:"guide"`
+	FetchedAt time.Time      `json:"fetchedAt"`
+}
+
+// ResilientViaGuideProvider decorates a ViaGuideProvider with retries, a circuit breaker
+// and a cache that keeps serving stale guides while the carrier is down.
+type ResilientViaGuideProvider struct {
+	provider via_guide_provider.ViaGuideProvider
+	cache    *cache.Cache
+	breaker  *breaker.Breaker
+	cfg      ResilienceCfg
+}
+
+func New(cfg ResilienceCfg, provider via_guide_provider.ViaGuideProvider, cache *cache.Cache) *ResilientViaGuideProvider {
+	p := &ResilientViaGuideProvider{
+		provider: provider,
+		cache:    cache,
+		breaker:  breaker.New(cfg.BreakerFailures, time.Duration(cfg.BreakerOpen)*time.Second),
+		cfg:      cfg,
+	}
+	metrics.Set("breaker_state", expvar.Func(func() any { return p.breaker.State() }))
+	return p
+}
+
+var now = time.Now
+
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p *ResilientViaGuideProvider) GetGuide(ctx context.Context, id string) (model.ViaGuide, error) {
+	logger := log.Get()
+	// a sync reads the carrier, neither the cached nor the stale guide is an answer
+	var cached cachedViaGuide
+	found := false
+	if !via_guide_provider.IsWithoutCache(ctx) {
+		cached, found = p.getCached(ctx, id)
+	}
+	if found && now().Sub(cached.FetchedAt) < time.Duration(p.cfg.CacheTTL)*time.Second {
+		metrics.Add("cache_hit", 1)
+		return cached.Guide, nil
+	}
+	metrics.Add("cache_miss", 1)
+
+	if !p.breaker.Allow() {
+		metrics.Add("breaker_rejected", 1)
+		logger.Warn(ctx, "msg", "via guide provider circuit open", "via_guide_id", id)
+		return p.stale(ctx, cached, found, via_guide_provider.ErrUnavailable)
+	}
+
+	guide, err := p.fetch(ctx, id)
+	if err == nil {
+		p.breaker.Success()
+		if guide.ID != "" {
+			p.setCached(ctx, id, guide)
+		}
+		return guide, nil
+	}
+	if !errors.Is(err, via_guide_provider.ErrTransient) {
+		// the carrier answered, the failure is not about its availability
+		p.breaker.Success()
+		return guide, err
+	}
+	p.breaker.Failure()
+	logger.Error(ctx, err, "msg", "via guide provider failed", "via_guide_id", id, "breaker_state", p.breaker.State())
+	return p.stale(ctx, cached, found, fmt.Errorf("%w: %w", via_guide_provider.ErrUnavailable, err))
+}
+
+func (p *ResilientViaGuideProvider) fetch(ctx context.Context, id string) (model.ViaGuide, error) {
+	if budget, ok := p.budget(ctx); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+	backoff := time.Duration(p.cfg.RetryBackoff) * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		guide, err := p.attempt(ctx, id)
+		if err == nil || !errors.Is(err, via_guide_provider.ErrTransient) || attempt >= p.cfg.Retries {
+			return guide, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+			// no time left for another attempt
+			return guide, err
+		}
+		metrics.Add("retries", 1)
+		log.Get().Warn(ctx, "msg", "retrying via guide request", "via_guide_id", id, "attempt", attempt+1,
+			"error", err.Error())
+		if sleepErr := sleep(ctx, backoff); sleepErr != nil {
+			return guide, err
+		}
+		backoff *= 2
+	}
+}
+
+func (p *ResilientViaGuideProvider) attempt(ctx context.Context, id string) (model.ViaGuide, error) {
+	if p.cfg.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.cfg.AttemptTimeout)*time.Millisecond)
+		defer cancel()
+	}
+	return p.provider.GetGuide(ctx, id)
+}
+
+// budget is the time for every attempt, bounded by the part of the request time left, false when
+// there is no limit
+func (p *ResilientViaGuideProvider) budget(ctx context.Context) (time.Duration, bool) {
+	budget := time.Duration(p.cfg.Timeout) * time.Millisecond
+	limited := p.cfg.Timeout > 0
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Duration(float64(time.Until(deadline)) * requestShare); !limited || left < budget {
+			budget, limited = left, true
+		}
+	}
+	return budget, limited
+}
+
+func (p *ResilientViaGuideProvider) stale(ctx context.Context, cached cachedViaGuide, found bool, err error) (model.ViaGuide, error) {
+	if !found {
+		return model.ViaGuide{}, err
+	}
+	metrics.Add("cache_stale", 1)
+	log.Get().Warn(ctx, "msg", "serving stale via guide", "via_guide_id", cached.Guide.ID,
+		"fetched_at", cached.FetchedAt)
+	return cached.Guide, nil
+}
+
+func (p *ResilientViaGuideProvider) getCached(ctx context.Context, id string) (cachedViaGuide, bool) {
+	var cached cachedViaGuide
+	found, err := p.cache.Get(ctx, cacheKeyPrefix+id, &cached)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "unable to read cached via guide", "via_guide_id", id)
+		return cachedViaGuide{}, false
+	}
+	return cached, found
+}
+
+func (p *ResilientViaGuideProvider) setCached(ctx context.Context, id string, guide model.ViaGuide) {
+	err := p.cache.Set(ctx, cacheKeyPrefix+id, cachedViaGuide{Guide: guide, FetchedAt: now()}, p.cfg.StaleTTL)
+	if err != nil {
+		log.Get().Error(ctx, err, "msg", "unable to cache via guide", "via_guide_id", id)
+	}
+}
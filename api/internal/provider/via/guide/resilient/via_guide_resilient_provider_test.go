@@ -0,0 +1,213 @@
+package via_guide_resilient_provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	"via/internal/cache"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/model"
+	via_guide_provider "via/internal/provider/via/guide"
+	mock_via_guide_provider "via/internal/provider/via/guide/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const viaGuideId = "123456789012"
+
+func cachedValue(t *testing.T, guide model.ViaGuide, fetchedAt time.Time) string {
+	data, err := json.Marshal(cachedViaGuide{Guide: guide, FetchedAt: fetchedAt})
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestResilientViaGuideProvider_GetGuide(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	fixedNow := time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixedNow }
+	sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	t.Cleanup(func() { now = time.Now })
+
+	cfg := ResilienceCfg{Retries: 2, RetryBackoff: 1, BreakerFailures: 1, BreakerOpen: 60, CacheTTL: 60, StaleTTL: 3600}
+	guide := model.ViaGuide{ID: viaGuideId, Status: "CRR"}
+	transientErr := fmt.Errorf("%w: timeout", via_guide_provider.ErrTransient)
+	key := cacheKeyPrefix + viaGuideId
+
+	tests := []struct {
+		name          string
+		cached        string
+		noCache       bool
+		providerCalls []error
+		providerGuide model.ViaGuide
+		expectGuide   model.ViaGuide
+		expectErr     error
+		expectCached  bool
+	}{
+		{
+			name:        "fresh cache hit",
+			cached:      cachedValue(t, guide, fixedNow.Add(-10*time.Second)),
+			expectGuide: guide,
+		},
+		{
+			name:          "cache miss fetches and caches",
+			providerCalls: []error{nil},
+			providerGuide: guide,
+			expectGuide:   guide,
+			expectCached:  true,
+		},
+		{
+			name:          "guide not found is not cached",
+			providerCalls: []error{nil},
+			providerGuide: model.ViaGuide{},
+			expectGuide:   model.ViaGuide{},
+		},
+		{
+			name:          "transient error retried",
+			providerCalls: []error{transientErr, nil},
+			providerGuide: guide,
+			expectGuide:   guide,
+			expectCached:  true,
+		},
+		{
+			name:          "non transient error is not retried",
+			providerCalls: []error{errors.New("parse error")},
+			expectErr:     errors.New("parse error"),
+		},
+		{
+			name:          "sync reads the carrier over a fresh cache",
+			cached:        cachedValue(t, guide, fixedNow.Add(-10*time.Second)),
+			noCache:       true,
+			providerCalls: []error{nil},
+			providerGuide: guide,
+			expectGuide:   guide,
+			expectCached:  true,
+		},
+		{
+			name:          "carrier down serves stale guide",
+			cached:        cachedValue(t, guide, fixedNow.Add(-10*time.Minute)),
+			providerCalls: []error{transientErr, transientErr, transientErr},
+			expectGuide:   guide,
+		},
+		{
+			name:      "sync does not serve the stale guide",
+			cached:    cachedValue(t, guide, fixedNow.Add(-10*time.Minute)),
+			noCache:   true,
+			expectErr: via_guide_provider.ErrUnavailable,
+		},
+		{
+			name:      "circuit open without cache",
+			expectErr: via_guide_provider.ErrUnavailable,
+		},
+	}
+
+	p := New(cfg, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockDS.On("Get", mock.Anything, key).Return(tt.cached != "", tt.cached, nil)
+			mockDS.On("Set", mock.Anything, key, mock.Anything, cfg.StaleTTL).Return(nil)
+			mockProvider := new(mock_via_guide_provider.MockViaGuideProvider)
+			for _, err := range tt.providerCalls {
+				mockProvider.On("GetGuide", mock.Anything, viaGuideId).Return(tt.providerGuide, err).Once()
+			}
+			p.provider = mockProvider
+			p.cache = cache.New(mockDS)
+
+			ctx := context.Background()
+			if tt.noCache {
+				ctx = via_guide_provider.WithoutCache(ctx)
+			}
+
+			result, err := p.GetGuide(ctx, viaGuideId)
+
+			if tt.expectErr != nil {
+				assert.ErrorContains(t, err, tt.expectErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectGuide, result)
+			}
+			mockProvider.AssertExpectations(t)
+			if tt.expectCached {
+				mockDS.AssertCalled(t, "Set", mock.Anything, key, mock.Anything, cfg.StaleTTL)
+			} else {
+				mockDS.AssertNotCalled(t, "Set", mock.Anything, key, mock.Anything, cfg.StaleTTL)
+			}
+		})
+	}
+}
+
+func TestResilientViaGuideProvider_BreakerOpensOnTransientFailures(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	mockDS := new(mock_ds.MockDS)
+	mockDS.On("Get", mock.Anything, mock.Anything).Return(false, "", nil)
+	mockProvider := new(mock_via_guide_provider.MockViaGuideProvider)
+	mockProvider.On("GetGuide", mock.Anything, viaGuideId).
+		Return(model.ViaGuide{}, fmt.Errorf("%w: timeout", via_guide_provider.ErrTransient)).Once()
+
+	p := New(ResilienceCfg{BreakerFailures: 1, BreakerOpen: 60}, mockProvider, cache.New(mockDS))
+
+	_, err := p.GetGuide(context.Background(), viaGuideId)
+	assert.ErrorIs(t, err, via_guide_provider.ErrUnavailable)
+	assert.ErrorIs(t, err, via_guide_provider.ErrTransient)
+
+	_, err = p.GetGuide(context.Background(), viaGuideId)
+	assert.ErrorIs(t, err, via_guide_provider.ErrUnavailable)
+	mockProvider.AssertNumberOfCalls(t, "GetGuide", 1)
+}
+
+func TestResilientViaGuideProvider_Timeouts(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	transientErr := fmt.Errorf("%w: timeout", via_guide_provider.ErrTransient)
+
+	tests := []struct {
+		name            string
+		cfg             ResilienceCfg
+		requestTimeout  time.Duration
+		expectCalls     int
+		expectMaxWindow time.Duration
+	}{
+		{name: "each attempt is bounded", cfg: ResilienceCfg{Retries: 1, AttemptTimeout: 100, Timeout: 10000},
+			expectCalls: 2, expectMaxWindow: 100 * time.Millisecond},
+		{name: "attempts are bounded by the request deadline",
+			cfg:            ResilienceCfg{Retries: 1, AttemptTimeout: 5000, Timeout: 10000},
+			requestTimeout: time.Second, expectCalls: 2, expectMaxWindow: 800 * time.Millisecond},
+		{name: "no retry without time for the backoff",
+			cfg:            ResilienceCfg{Retries: 2, RetryBackoff: 2000, AttemptTimeout: 5000, Timeout: 10000},
+			requestTimeout: time.Second, expectCalls: 1, expectMaxWindow: 800 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockDS.On("Get", mock.Anything, mock.Anything).Return(false, "", nil)
+			mockProvider := new(mock_via_guide_provider.MockViaGuideProvider)
+			mockProvider.On("GetGuide", mock.Anything, viaGuideId).Return(model.ViaGuide{}, transientErr).
+				Run(func(args mock.Arguments) {
+					deadline, ok := args.Get(0).(context.Context).Deadline()
+					assert.True(t, ok, "the attempt has a deadline")
+					assert.LessOrEqual(t, time.Until(deadline), tt.expectMaxWindow)
+				})
+			tt.cfg.BreakerFailures = 10
+			p := New(tt.cfg, mockProvider, cache.New(mockDS))
+			ctx := context.Background()
+			if tt.requestTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.requestTimeout)
+				defer cancel()
+			}
+
+			_, err := p.GetGuide(ctx, viaGuideId)
+
+			assert.ErrorIs(t, err, via_guide_provider.ErrUnavailable)
+			mockProvider.AssertNumberOfCalls(t, "GetGuide", tt.expectCalls)
+		})
+	}
+}
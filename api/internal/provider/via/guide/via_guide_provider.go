@@ -2,10 +2,48 @@ package via_guide_provider
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"sync"
 	"via/internal/model"
 )
 
+// Sources of via guides selectable by configuration
+const (
+	SOURCE_WEB     = "web"
+	SOURCE_API     = "api"
+	SOURCE_FIXTURE = "fixture"
+)
+
+var (
+	// ErrTransient marks failures worth a retry, like network errors, cut responses, carrier server errors or its rate limit.
+	ErrTransient = errors.New("transient via guide provider error")
+	// ErrUnavailable is returned when the carrier system is considered down.
+	ErrUnavailable = errors.New("via guide provider unavailable")
+)
+
+// IsTransientStatus reports whether the carrier answered with a status worth a retry: a timeout,
+// its rate limit or a server error
+func IsTransientStatus(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests ||
+		status >= http.StatusInternalServerError
+}
+
+type noCacheKeyType string
+
+const noCacheKey noCacheKeyType = "no_cache"
+
+// WithoutCache asks the providers to read the guide from the carrier, for the explicit syncs
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey, true)
+}
+
+// IsWithoutCache reports whether the guide must be read from the carrier
+func IsWithoutCache(ctx context.Context) bool {
+	noCache, _ := ctx.Value(noCacheKey).(bool)
+	return noCache
+}
+
 type ViaGuideProvider interface {
 	GetGuide(ctx context.Context, id string) (model.ViaGuide, error)
 }
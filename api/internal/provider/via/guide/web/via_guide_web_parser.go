@@ -4,115 +4,255 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 	"via/internal/model"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html/charset"
 )
 
 var (
 	ErrNoResultRow   = errors.New("no result row found")
 	ErrMissingColumn = errors.New("missing expected column")
+	ErrLayoutChanged = errors.New("carrier layout changed")
 	ErrMalformedHTML = errors.New("malformed HTML")
 	ErrBadResponse   = errors.New("malformed response")
 )
 
+const (
+	COL_GUIDE       = "Envío"
+	COL_REFERENCE   = "Referencia"
+	COL_STATUS      = "Estado"
+	COL_PACKAGES    = "BUL"
+	COL_WEIGHT      = "PR Kg"
+	COL_PAYMENT     = "Portes"
+	COL_ROUTE       = "Ruta"
+	COL_DATE        = "Fecha"
+	COL_SENDER      = "Remitente"
+	COL_RECIPIENT   = "Destinatario"
+	COL_DESTINATION = "Acciones"
+)
+
+// requiredColumns are the carrier columns the parser reads, a missing one means the layout changed
+var requiredColumns = []string{
+	COL_GUIDE, COL_REFERENCE, COL_STATUS, COL_PACKAGES, COL_WEIGHT, COL_PAYMENT,
+	COL_ROUTE, COL_DATE, COL_SENDER, COL_RECIPIENT, COL_DESTINATION,
+}
+
+const viaDateLayout = "02/01/06"
+
+var (
+	commentedTDRe   = regexp.MustCompile(`<!--\s*(<td[^>]*>.*?</td>)\s*-->`)
+	recordsFoundRe  = regexp.MustCompile(`(\d+)\s+registros encontrados`)
+	whitespaceRunRe = regexp.MustCompile(`\s+`)
+)
+
+// ParseWarning describes a value the parser could not read, the guide is still returned
+type ParseWarning struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+func (w ParseWarning) String() string {
+	return fmt.Sprintf("row %d, column %q, value %q: %s", w.Row, w.Column, w.Value, w.Message)
+}
+
 type ViaResponseParser interface {
-	Parse(data []byte, v any) error
+	Parse(data []byte, v any) ([]ParseWarning, error)
 }
 
 type HistoricalQueryResponseParser struct {
 }
 
-func (p HistoricalQueryResponseParser) Parse(data []byte, v any) error {
+// Parse reads the historical query result. The first row is the current state of the guide,
+// the following rows are its tracking history.
+func (p HistoricalQueryResponseParser) Parse(data []byte, v any) ([]ParseWarning, error) {
 	guidePtr, ok := v.(*model.ViaGuide)
 	if !ok {
-		return fmt.Errorf("invalid type for output: expected *model.ViaGuide")
+		return nil, fmt.Errorf("invalid type for output: expected *model.ViaGuide")
+	}
+
+	data, err := decodeCharset(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedHTML, err)
 	}
 
 	// remove comments from <td> elements
-	htmlStr := uncommentTDs(string(data))
+	htmlStr := commentedTDRe.ReplaceAllString(string(data), "$1")
 	// new reader with cleaned HTML
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlStr))
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrMalformedHTML, err)
+		return nil, fmt.Errorf("%w: %v", ErrMalformedHTML, err)
 	}
 
 	// extract header row
-	headerRow := doc.Find("#listado tr.cabecera").First()
 	headerMap := make(map[string]int)
-
-	headerRow.Find("td").Each(func(i int, s *goquery.Selection) {
-		text := strings.TrimSpace(s.Find("span.cabecera").Text())
+	doc.Find("#listado tr.cabecera").First().ChildrenFiltered("td").Each(func(i int, s *goquery.Selection) {
+		text := normalizeText(s.Find("span.cabecera").Text())
 		if text != "" {
 			headerMap[text] = i
 		}
 	})
 
 	if len(headerMap) == 0 {
-		return ErrNoResultRow
+		return nil, ErrNoResultRow
+	}
+	if err := checkLayout(headerMap); err != nil {
+		return nil, err
 	}
 
-	requiredHeaders := []string{
-		"Envío", "Estado", "Acciones",
+	rows := doc.Find("#listado tbody.cuerpo_tabla").First().ChildrenFiltered("tr")
+	if rows.Length() == 0 {
+		return nil, ErrNoResultRow
 	}
-	for _, header := range requiredHeaders {
-		if _, ok := headerMap[header]; !ok {
-			return fmt.Errorf("%w: %s", ErrMissingColumn, header)
+
+	var warnings []ParseWarning
+	if found := recordsFoundRe.FindStringSubmatch(doc.Find("tr.titulo").Text()); found != nil {
+		if expected, _ := strconv.Atoi(found[1]); expected != rows.Length() {
+			warnings = append(warnings, ParseWarning{
+				Column:  "registros encontrados",
+				Value:   found[1],
+				Message: fmt.Sprintf("parsed %d rows", rows.Length()),
+			})
 		}
 	}
 
-	// Get data
-	row := doc.Find("#listado tbody.cuerpo_tabla tr").First()
+	var guide model.ViaGuide
+	rows.Each(func(i int, row *goquery.Selection) {
+		r := rowReader{row: i + 1, cells: row.ChildrenFiltered("td"), headers: headerMap}
+		if i == 0 {
+			guide = model.ViaGuide{
+				ID:          r.text(COL_GUIDE),
+				Reference:   r.text(COL_REFERENCE),
+				Status:      r.text(COL_STATUS),
+				Packages:    r.int(COL_PACKAGES),
+				Weight:      r.float(COL_WEIGHT),
+				Payment:     r.text(COL_PAYMENT),
+				Route:       r.text(COL_ROUTE),
+				Date:        r.date(COL_DATE),
+				Sender:      r.text(COL_SENDER),
+				Recipient:   r.text(COL_RECIPIENT),
+				Destination: r.destination(COL_DESTINATION),
+			}
+		} else {
+			guide.History = append(guide.History, model.ViaGuideEvent{
+				Status:      r.text(COL_STATUS),
+				Date:        r.date(COL_DATE),
+				Route:       r.text(COL_ROUTE),
+				Destination: r.destination(COL_DESTINATION),
+			})
+		}
+		warnings = append(warnings, r.warnings...)
+	})
+
+	*guidePtr = guide
+	return warnings, nil
+}
 
-	cells := row.Find("td")
-	getCell := func(header string) string {
-		idx := headerMap[header]
-		return strings.TrimSpace(cells.Eq(idx).Text())
+// checkLayout reports the required columns the carrier no longer sends, along with the
+// columns it does not know about, which usually are the renamed ones
+func checkLayout(headerMap map[string]int) error {
+	var missing []string
+	for _, header := range requiredColumns {
+		if _, ok := headerMap[header]; !ok {
+			missing = append(missing, header)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
 	}
 
-	parseInt := func(header string) int {
-		v, _ := strconv.Atoi(getCell(header))
-		return v
+	known := make(map[string]bool, len(requiredColumns))
+	for _, header := range requiredColumns {
+		known[header] = true
+	}
+	var unknown []string
+	for header := range headerMap {
+		if !known[header] {
+			unknown = append(unknown, header)
+		}
 	}
+	slices.Sort(unknown)
+	return fmt.Errorf("%w: %w: %s (unknown columns: %s)", ErrLayoutChanged, ErrMissingColumn,
+		strings.Join(missing, ", "), strings.Join(unknown, ", "))
+}
 
-	parseFloat := func(header string) float64 {
-		v, _ := strconv.ParseFloat(strings.ReplaceAll(getCell(header), ",", "."), 64)
-		return v
+// decodeCharset converts the carrier response to UTF-8, the carrier serves ISO-8859-1 pages
+func decodeCharset(data []byte) ([]byte, error) {
+	if utf8.Valid(data) {
+		return data, nil
 	}
+	enc, _, _ := charset.DetermineEncoding(data, "text/html; charset=iso-8859-1")
+	return enc.NewDecoder().Bytes(data)
+}
 
-	parseDestination := func(header string) model.ViaDestination {
-		parts := strings.Fields(getCell(header))
-		if len(parts) < 2 {
-			return model.ViaDestination{}
-		}
+type rowReader struct {
+	row      int
+	cells    *goquery.Selection
+	headers  map[string]int
+	warnings []ParseWarning
+}
 
-		description := strings.Join(parts[1:], " ")
-		return model.ViaDestination{
-			ID:          parts[0],
-			Description: description,
-		}
+func (r *rowReader) warn(column, value, message string) {
+	r.warnings = append(r.warnings, ParseWarning{Row: r.row, Column: column, Value: value, Message: message})
+}
 
+func (r *rowReader) text(header string) string {
+	idx := r.headers[header]
+	if idx >= r.cells.Length() {
+		r.warn(header, "", "missing cell")
+		return ""
 	}
+	return normalizeText(r.cells.Eq(idx).Text())
+}
 
-	*guidePtr = model.ViaGuide{
-		ID:          getCell("Envío"),
-		Reference:   getCell("Referencia"),
-		Status:      getCell("Estado"),
-		Packages:    parseInt("BUL"),
-		Weight:      parseFloat("PR Kg"),
-		Payment:     getCell("Portes"),
-		Route:       getCell("Ruta"),
-		Date:        getCell("Fecha"),
-		Sender:      getCell("Remitente"),
-		Recipient:   getCell("Destinatario"),
-		Destination: parseDestination("Acciones"),
+func (r *rowReader) int(header string) int {
+	value := r.text(header)
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		r.warn(header, value, "invalid integer")
+	}
+	return v
+}
+
+func (r *rowReader) float(header string) float64 {
+	value := r.text(header)
+	v, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+	if err != nil {
+		r.warn(header, value, "invalid decimal")
+	}
+	return v
+}
+
+func (r *rowReader) date(header string) string {
+	value := r.text(header)
+	if _, err := time.Parse(viaDateLayout, value); err != nil {
+		r.warn(header, value, "invalid date")
+	}
+	return value
+}
+
+// destination reads the "<branch id> <description>" cell. The carrier renders it,
+// commented out, right where the actions column is announced in the header
+func (r *rowReader) destination(header string) model.ViaDestination {
+	value := r.text(header)
+	parts := strings.Fields(value)
+	if len(parts) < 2 {
+		r.warn(header, value, "invalid destination")
+		return model.ViaDestination{}
+	}
+	return model.ViaDestination{
+		ID:          parts[0],
+		Description: strings.Join(parts[1:], " "),
 	}
-	return nil
 }
 
-func uncommentTDs(html string) string {
-	re := regexp.MustCompile(`<!--\s*(<td[^>]*>.*?</td>)\s*-->`)
-	return re.ReplaceAllString(html, "$1")
+func normalizeText(s string) string {
+	return strings.TrimSpace(whitespaceRunRe.ReplaceAllString(strings.ReplaceAll(s, "\u00a0", " "), " "))
 }
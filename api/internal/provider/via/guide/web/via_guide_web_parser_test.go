@@ -1,7 +1,12 @@
 package via_guide_web_provider
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"via/internal/log"
 	"via/internal/model"
@@ -18,7 +23,7 @@ func TestParseHistoricalQueryResponse(t *testing.T) {
 		f, err := os.ReadFile("testdata/consulta_historico_resultado_success.html")
 		require.NoError(t, err)
 		var guide model.ViaGuide
-		err = HistoricalQueryResponseParser{}.Parse(f, &guide)
+		_, err = HistoricalQueryResponseParser{}.Parse(f, &guide)
 		require.NoError(t, err)
 
 		expected := model.ViaGuide{
@@ -41,7 +46,7 @@ func TestParseHistoricalQueryResponse(t *testing.T) {
 		f, err := os.ReadFile("testdata/consulta_historico_resultado_empty.html")
 		require.NoError(t, err)
 		var guide model.ViaGuide
-		err = HistoricalQueryResponseParser{}.Parse(f, &guide)
+		_, err = HistoricalQueryResponseParser{}.Parse(f, &guide)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no result row found")
 	})
@@ -51,8 +56,67 @@ func TestParseHistoricalQueryResponse(t *testing.T) {
 		require.NoError(t, err)
 		var guide model.ViaGuide
 
-		err = HistoricalQueryResponseParser{}.Parse(f, &guide)
+		_, err = HistoricalQueryResponseParser{}.Parse(f, &guide)
 		require.Error(t, err)
 		require.ErrorIs(t, err, ErrMissingColumn)
+		require.ErrorIs(t, err, ErrLayoutChanged)
 	})
+
+	t.Run("invalid output type", func(t *testing.T) {
+		var guide model.Guide
+		_, err := HistoricalQueryResponseParser{}.Parse([]byte("<html></html>"), &guide)
+		require.Error(t, err)
+	})
+}
+
+var update = flag.Bool("update", false, "update golden files")
+
+type goldenResult struct {
+	Guide    model.ViaGuide `json:"guide"`
+	Warnings []ParseWarning `json:"warnings"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// TestParseHistoricalQueryResponseGolden parses the anonymized carrier pages in testdata/golden
+// and compares the result with the .golden.json next to each page, run with -update to rewrite them
+func TestParseHistoricalQueryResponseGolden(t *testing.T) {
+	files, err := filepath.Glob("testdata/golden/*.html")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			var result goldenResult
+			result.Warnings, err = HistoricalQueryResponseParser{}.Parse(data, &result.Guide)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			got, err := json.MarshalIndent(result, "", "  ")
+			require.NoError(t, err)
+
+			goldenFile := strings.TrimSuffix(file, ".html") + ".golden.json"
+			if *update {
+				require.NoError(t, os.WriteFile(goldenFile, append(got, '\n'), 0o644))
+			}
+			want, err := os.ReadFile(goldenFile)
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+		})
+	}
+}
+
+func TestParseHistoricalQueryResponseErrors(t *testing.T) {
+	data, err := os.ReadFile("testdata/golden/renamed_column.html")
+	require.NoError(t, err)
+	_, err = HistoricalQueryResponseParser{}.Parse(data, &model.ViaGuide{})
+	require.ErrorIs(t, err, ErrLayoutChanged)
+	require.ErrorIs(t, err, ErrMissingColumn)
+
+	data, err = os.ReadFile("testdata/golden/no_rows.html")
+	require.NoError(t, err)
+	_, err = HistoricalQueryResponseParser{}.Parse(data, &model.ViaGuide{})
+	require.True(t, errors.Is(err, ErrNoResultRow))
 }
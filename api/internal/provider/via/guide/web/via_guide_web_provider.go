@@ -11,6 +11,7 @@ import (
 	http_client "via/internal/client/http"
 	"via/internal/log"
 	"via/internal/model"
+	via_guide_provider "via/internal/provider/via/guide"
 	"via/internal/secret"
 )
 
@@ -52,27 +53,37 @@ func (p *ViaGuideWebProvider) GetGuide(ctx context.Context, id string) (model.Vi
 	resp, err := p.client.Requester.Do(req)
 	if err != nil {
 		logger.Error(ctx, err, "msg", "error making HTTP request")
-		return model.ViaGuide{}, fmt.Errorf("making HTTP request: %w", err)
+		return model.ViaGuide{}, fmt.Errorf("%w: making HTTP request: %w", via_guide_provider.ErrTransient, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		logger.Error(ctx, err, "msg", "unexpected status code")
+		if via_guide_provider.IsTransientStatus(resp.StatusCode) {
+			return model.ViaGuide{}, fmt.Errorf("%w: unexpected status code: %d", via_guide_provider.ErrTransient, resp.StatusCode)
+		}
 		return model.ViaGuide{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		logger.Error(ctx, err, "msg", "error reading response")
-		return model.ViaGuide{}, fmt.Errorf("error reading response: %w", err)
+		// the connection dropped in the middle of the page
+		return model.ViaGuide{}, fmt.Errorf("%w: error reading response: %w", via_guide_provider.ErrTransient, err)
 	}
 	var guide model.ViaGuide
-	err = p.guideParser.Parse(bodyBytes, &guide)
+	warnings, err := p.guideParser.Parse(bodyBytes, &guide)
 
 	if err != nil {
+		if errors.Is(err, ErrLayoutChanged) {
+			logger.Error(ctx, err, "msg", "via guide page layout changed", "via_guide_id", id)
+		}
 		if !errors.Is(err, ErrNoResultRow) {
 			return guide, err
 		}
 	}
+	for _, warning := range warnings {
+		logger.Warn(ctx, "msg", "via guide parse warning", "via_guide_id", id, "warning", warning.String())
+	}
 	return guide, nil
 }
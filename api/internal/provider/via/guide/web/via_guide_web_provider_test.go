@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"testing"
 	http_client "via/internal/client/http"
 	mock_http "via/internal/client/http/mock"
 	"via/internal/model"
+	via_guide_provider "via/internal/provider/via/guide"
 	"via/internal/testutil"
 
 	"github.com/stretchr/testify/assert"
@@ -17,16 +19,17 @@ import (
 )
 
 type mockParser struct {
-	err   error
-	guide model.ViaGuide
+	err      error
+	guide    model.ViaGuide
+	warnings []ParseWarning
 }
 
-func (m *mockParser) Parse(data []byte, v any) error {
+func (m *mockParser) Parse(data []byte, v any) ([]ParseWarning, error) {
 	ptr, ok := v.(*model.ViaGuide)
 	if ok {
 		*ptr = m.guide
 	}
-	return m.err
+	return m.warnings, m.err
 }
 
 func TestGetGuide(t *testing.T) {
@@ -38,8 +41,10 @@ func TestGetGuide(t *testing.T) {
 		mockErr      error
 		parserErr    error
 		expectErr    bool
+		transient    bool
 		expectGuide  model.ViaGuide
 		parserGuide  model.ViaGuide
+		warnings     []ParseWarning
 		responseBody string
 		statusCode   int
 	}{
@@ -47,6 +52,34 @@ func TestGetGuide(t *testing.T) {
 			name:      "http client error",
 			mockErr:   errors.New("http error"),
 			expectErr: true,
+			transient: true,
+		},
+		{
+			name:      "server error status code",
+			expectErr: true,
+			transient: true,
+			mockResp: &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Body:       io.NopCloser(bytes.NewBufferString("error")),
+			},
+		},
+		{
+			name:      "rate limited",
+			expectErr: true,
+			transient: true,
+			mockResp: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Body:       io.NopCloser(bytes.NewBufferString("slow down")),
+			},
+		},
+		{
+			name:      "request timeout",
+			expectErr: true,
+			transient: true,
+			mockResp: &http.Response{
+				StatusCode: http.StatusRequestTimeout,
+				Body:       io.NopCloser(bytes.NewBufferString("timeout")),
+			},
 		},
 		{
 			name:         "non-200 status code",
@@ -66,6 +99,7 @@ func TestGetGuide(t *testing.T) {
 				Body:       &errorReadCloser{},
 			},
 			expectErr: true,
+			transient: true,
 		},
 		{
 			name:         "parser returns generic error",
@@ -90,6 +124,29 @@ func TestGetGuide(t *testing.T) {
 			},
 			expectGuide: model.ViaGuide{ID: "123"},
 		},
+		{
+			name:         "parser detects layout change",
+			statusCode:   http.StatusOK,
+			responseBody: "html...",
+			parserErr:    fmt.Errorf("%w: %w: BUL", ErrLayoutChanged, ErrMissingColumn),
+			mockResp: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString("html...")),
+			},
+			expectErr: true,
+		},
+		{
+			name:         "parser warnings are not errors",
+			statusCode:   http.StatusOK,
+			responseBody: "html...",
+			parserGuide:  model.ViaGuide{ID: "999"},
+			warnings:     []ParseWarning{{Row: 1, Column: "BUL", Value: "x", Message: "invalid integer"}},
+			mockResp: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString("html...")),
+			},
+			expectGuide: model.ViaGuide{ID: "999"},
+		},
 		{
 			name:         "success",
 			statusCode:   http.StatusOK,
@@ -114,8 +171,9 @@ func TestGetGuide(t *testing.T) {
 			}
 
 			parser := &mockParser{
-				err:   tt.parserErr,
-				guide: tt.parserGuide,
+				err:      tt.parserErr,
+				guide:    tt.parserGuide,
+				warnings: tt.warnings,
 			}
 
 			provider := &ViaGuideWebProvider{
@@ -127,6 +185,7 @@ func TestGetGuide(t *testing.T) {
 
 			if tt.expectErr {
 				assert.Error(t, err)
+				assert.Equal(t, tt.transient, errors.Is(err, via_guide_provider.ErrTransient))
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectGuide, guide)
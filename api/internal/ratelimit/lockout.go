@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"via/internal/ds"
+)
+
+// LimitCfg holds the amount of requests allowed in a time window.
+type LimitCfg struct {
+	Limit  int `env:"LIMIT" envDefault:"30" json:"limit"`
+	Window int `env:"WINDOW" envDefault:"60" json:"window"` // in seconds
+}
+
+// LockoutCfg holds the escalation policy applied after repeated failures.
+type LockoutCfg struct {
+	MaxFailures   int `env:"MAX_FAILURES" envDefault:"10" json:"maxFailures"`
+	FailureWindow int `env:"FAILURE_WINDOW" envDefault:"600" json:"failureWindow"` // in seconds
+	BaseLockout   int `env:"BASE_LOCKOUT" envDefault:"60" json:"baseLockout"`      // in seconds
+	MaxLockout    int `env:"MAX_LOCKOUT" envDefault:"3600" json:"maxLockout"`      // in seconds
+	LevelTTL      int `env:"LEVEL_TTL" envDefault:"86400" json:"levelTtl"`         // in seconds
+	AlertLevel    int `env:"ALERT_LEVEL" envDefault:"3" json:"alertLevel"`
+}
+
+// Lockout blocks a key once it accumulates too many failures, doubling the
+// lock duration each time the key is locked again within LevelTTL.
+type Lockout struct {
+	ID  string
+	cfg LockoutCfg
+	ds  ds.DS
+}
+
+func NewLockout(id string, cfg LockoutCfg, ds ds.DS) *Lockout {
+	return &Lockout{
+		ID:  id,
+		cfg: cfg,
+		ds:  ds,
+	}
+}
+
+func (l *Lockout) IsLocked(ctx context.Context, key string) (bool, error) {
+	found, _, err := l.ds.Get(ctx, l.lockKey(key))
+	if err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
+// RegisterFailure counts a failure for the key and returns the lockout level
+// applied, 0 meaning the key was not locked.
+func (l *Lockout) RegisterFailure(ctx context.Context, key string) (int, error) {
+	failuresKey := l.failuresKey(key)
+	// the window starts with the first failure, in the same step so concurrent failures are all counted
+	failures, err := l.ds.IncrExpire(ctx, failuresKey, l.cfg.FailureWindow)
+	if err != nil {
+		return 0, err
+	}
+	if failures < int64(l.cfg.MaxFailures) {
+		return 0, nil
+	}
+
+	levelKey := l.levelKey(key)
+	level, err := l.ds.Incr(ctx, levelKey)
+	if err != nil {
+		return 0, err
+	}
+	// refresh level expiration, keeping its value
+	if err = l.ds.Expire(ctx, levelKey, l.cfg.LevelTTL); err != nil {
+		return 0, err
+	}
+	if err = l.ds.Set(ctx, l.lockKey(key), strconv.FormatInt(level, 10),
+		int(l.Duration(int(level)).Seconds())); err != nil {
+		return 0, err
+	}
+	if err = l.ds.Del(ctx, failuresKey); err != nil {
+		return 0, err
+	}
+	return int(level), nil
+}
+
+// Duration returns the lock duration for a lockout level.
+func (l *Lockout) Duration(level int) time.Duration {
+	seconds := l.cfg.BaseLockout
+	for i := 1; i < level && seconds < l.cfg.MaxLockout; i++ {
+		seconds *= 2
+	}
+	if seconds > l.cfg.MaxLockout {
+		seconds = l.cfg.MaxLockout
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// IsAlert reports whether a lockout level denotes an enumeration pattern.
+func (l *Lockout) IsAlert(level int) bool {
+	return l.cfg.AlertLevel > 0 && level >= l.cfg.AlertLevel
+}
+
+func (l *Lockout) failuresKey(key string) string {
+	return fmt.Sprintf("%s:failures:%s", l.ID, key)
+}
+
+func (l *Lockout) levelKey(key string) string {
+	return fmt.Sprintf("%s:level:%s", l.ID, key)
+}
+
+func (l *Lockout) lockKey(key string) string {
+	return fmt.Sprintf("%s:lock:%s", l.ID, key)
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	mock_ds "via/internal/ds/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var lockoutCfg = LockoutCfg{
+	MaxFailures:   3,
+	FailureWindow: 60,
+	BaseLockout:   10,
+	MaxLockout:    50,
+	LevelTTL:      300,
+	AlertLevel:    2,
+}
+
+func TestLockout_IsLocked(t *testing.T) {
+	tests := []struct {
+		name       string
+		found      bool
+		err        error
+		wantLocked bool
+		wantErr    bool
+	}{
+		{name: "not locked"},
+		{name: "locked", found: true, wantLocked: true},
+		{name: "ds error", err: errors.New("get failed"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockDS.On("Get", mock.Anything, "test:lock:key").Return(tt.found, "1", tt.err)
+
+			locked, err := NewLockout("test", lockoutCfg, mockDS).IsLocked(context.Background(), "key")
+
+			assert.Equal(t, tt.wantLocked, locked)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLockout_RegisterFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		incrErr   error
+		level     int
+		levelErr  error
+		lockErr   error
+		wantLevel int
+		wantErr   bool
+	}{
+		{name: "first failure", failures: 1},
+		{name: "under max failures", failures: 2},
+		{name: "first lockout", failures: 3, level: 1, wantLevel: 1},
+		{name: "escalated lockout", failures: 3, level: 3, wantLevel: 3},
+		{name: "error on failures incr", incrErr: errors.New("incr failed"), wantErr: true},
+		{name: "error on level incr", failures: 3, levelErr: errors.New("incr failed"), wantErr: true},
+		{name: "error on lock set", failures: 3, level: 1, lockErr: errors.New("set failed"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			lockout := NewLockout("test", lockoutCfg, mockDS)
+			mockDS.On("IncrExpire", mock.Anything, "test:failures:key", lockoutCfg.FailureWindow).
+				Return(tt.failures, tt.incrErr)
+			if tt.failures >= lockoutCfg.MaxFailures {
+				mockDS.On("Incr", mock.Anything, "test:level:key").Return(tt.level, tt.levelErr)
+				mockDS.On("Expire", mock.Anything, "test:level:key", lockoutCfg.LevelTTL).Return(nil)
+				mockDS.On("Set", mock.Anything, "test:lock:key", mock.Anything,
+					int(lockout.Duration(tt.level).Seconds())).Return(tt.lockErr)
+				mockDS.On("Del", mock.Anything, "test:failures:key").Return(nil)
+			}
+
+			level, err := lockout.RegisterFailure(context.Background(), "key")
+
+			assert.Equal(t, tt.wantLevel, level)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLockout_Duration(t *testing.T) {
+	lockout := NewLockout("test", lockoutCfg, nil)
+	assert.Equal(t, 10*time.Second, lockout.Duration(1))
+	assert.Equal(t, 20*time.Second, lockout.Duration(2))
+	assert.Equal(t, 40*time.Second, lockout.Duration(3))
+	assert.Equal(t, 50*time.Second, lockout.Duration(4))
+	assert.Equal(t, 50*time.Second, lockout.Duration(10))
+}
+
+func TestLockout_IsAlert(t *testing.T) {
+	lockout := NewLockout("test", lockoutCfg, nil)
+	assert.False(t, lockout.IsAlert(1))
+	assert.True(t, lockout.IsAlert(2))
+	assert.False(t, NewLockout("test", LockoutCfg{}, nil).IsAlert(5))
+}
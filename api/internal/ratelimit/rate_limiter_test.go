@@ -20,7 +20,6 @@ func TestRateLimiter_Allow(t *testing.T) {
 		key       string
 		incrCount int
 		incrErr   error
-		setErr    error
 		wantAllow bool
 		wantErr   bool
 	}
@@ -67,7 +66,7 @@ func TestRateLimiter_Allow(t *testing.T) {
 			},
 		},
 		{
-			name: "error on Incr",
+			name: "error on IncrExpire",
 			fields: fields{
 				limit:  3,
 				window: 5 * time.Second,
@@ -78,19 +77,6 @@ func TestRateLimiter_Allow(t *testing.T) {
 				wantErr: true,
 			},
 		},
-		{
-			name: "error on Set when first request",
-			fields: fields{
-				limit:  3,
-				window: 5 * time.Second,
-			},
-			args: args{
-				key:       "user5",
-				incrCount: 1,
-				setErr:    errors.New("set failed"),
-				wantErr:   true,
-			},
-		},
 	}
 
 	for _, tt := range tests {
@@ -100,15 +86,8 @@ func TestRateLimiter_Allow(t *testing.T) {
 
 			mockDS := new(mock_ds.MockDS)
 
-			if tt.args.incrErr != nil {
-				mockDS.On("Incr", mock.Anything, tt.args.key).Return(0, tt.args.incrErr)
-			} else {
-				mockDS.On("Incr", mock.Anything, tt.args.key).Return(tt.args.incrCount, nil)
-				if tt.args.incrCount == 1 {
-					mockDS.On("Set", mock.Anything, tt.args.key, "1", int(tt.fields.window.Seconds())).
-						Return(tt.args.setErr)
-				}
-			}
+			mockDS.On("IncrExpire", mock.Anything, tt.args.key, int(tt.fields.window.Seconds())).
+				Return(tt.args.incrCount, tt.args.incrErr)
 
 			rl := New("test", tt.fields.limit, tt.fields.window, mockDS)
 			allowed, err := rl.Allow(context.Background(), tt.args.key)
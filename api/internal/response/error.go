@@ -0,0 +1,85 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Codes of the field validation failures
+const (
+	FIELD_REQUIRED     = "required"
+	FIELD_INVALID      = "invalid"
+	FIELD_TOO_LONG     = "too_long"
+	FIELD_OUT_OF_RANGE = "out_of_range"
+)
+
+// ProblemContentType is the RFC 7807 media type, the errors are written as problems to the clients accepting it
+const ProblemContentType = "application/problem+json"
+
+// Error tells why the request failed. The code is stable, the clients branch on it instead of the
+// http status or the message, which is localized
+type Error struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+	DocsURL string       `json:"docsUrl,omitempty"`
+}
+
+// FieldError is the validation failure of an input field. The input parsers return it as an error
+// so the handlers add it to the details of the response
+type FieldError struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + strings.ReplaceAll(e.Code, "_", " ")
+}
+
+// Details returns the field failure in err, none when err is not a FieldError
+func Details(err error) []FieldError {
+	var fieldErr FieldError
+	if errors.As(err, &fieldErr) {
+		return []FieldError{fieldErr}
+	}
+	return nil
+}
+
+// Problem is an error in the RFC 7807 form, extended with its code, request id and field failures
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Instance  string       `json:"instance"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+var docsURL string
+
+// SetDocsURL sets the page documenting the error codes, every error links to the anchor of its code
+func SetDocsURL(url string) {
+	docsURL = url
+}
+
+func acceptsProblem(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ProblemContentType)
+}
+
+func newProblem(r *http.Request, e Error, status int, requestID string) Problem {
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     e.Message,
+		Status:    status,
+		Instance:  r.URL.Path,
+		Code:      e.Code,
+		RequestID: requestID,
+		Errors:    e.Details,
+	}
+	if e.DocsURL != "" {
+		problem.Type = e.DocsURL
+	}
+	return problem
+}
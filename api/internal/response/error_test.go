@@ -0,0 +1,88 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"via/internal/global"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteJSON_Error(t *testing.T) {
+	details := []FieldError{{Field: "limit", Code: FIELD_OUT_OF_RANGE}}
+
+	tests := []struct {
+		name            string
+		accept          string
+		docsURL         string
+		status          int
+		expectedType    string
+		expectedDocsURL string
+		expectProblem   bool
+	}{
+		{name: "json error", status: http.StatusBadRequest},
+		{name: "json error with docs", docsURL: "https://docs/errors.md", status: http.StatusBadRequest,
+			expectedDocsURL: "https://docs/errors.md#search_invalid"},
+		{name: "problem", accept: "application/problem+json, application/json", status: http.StatusBadRequest,
+			expectedType: "about:blank", expectProblem: true},
+		{name: "problem with docs", accept: ProblemContentType, docsURL: "https://docs/errors.md",
+			status: http.StatusBadRequest, expectedType: "https://docs/errors.md#search_invalid", expectProblem: true},
+		{name: "problem not written on success", accept: ProblemContentType, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDocsURL(tt.docsURL)
+			t.Cleanup(func() { SetDocsURL("") })
+			req := httptest.NewRequest(http.MethodGet, "/guide/search", nil)
+			req = req.WithContext(context.WithValue(req.Context(), global.RequestIDKey, "req-1"))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rr := httptest.NewRecorder()
+			res := Response[any]{Error: &Error{Code: "search_invalid", Message: "invalid search", Details: details}}
+
+			WriteJSON(rr, req, res, tt.status)
+
+			assert.Equal(t, tt.status, rr.Code)
+			if tt.expectProblem {
+				assert.Equal(t, ProblemContentType, rr.Header().Get("Content-Type"))
+				var got Problem
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, Problem{Type: tt.expectedType, Title: "invalid search", Status: tt.status,
+					Instance: "/guide/search", Code: "search_invalid", RequestID: "req-1", Errors: details}, got)
+				return
+			}
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			var got Response[any]
+			assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+			assert.Equal(t, "invalid search", got.Message)
+			assert.Equal(t, &Error{Code: "search_invalid", Message: "invalid search", Details: details,
+				DocsURL: tt.expectedDocsURL}, got.Error)
+			assert.Empty(t, res.Error.DocsURL, "the error of the caller is left as is")
+		})
+	}
+}
+
+func TestWriteJSONEvent_Error(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monitor/events", nil)
+	rr := httptest.NewRecorder()
+
+	WriteJSONEvent(rr, req, Response[any]{Error: &Error{Code: "internal_error", Message: "internal error"}})
+
+	assert.Equal(t, `data:{"data":null,"message":"internal error","error":{"code":"internal_error","message":"internal error"},"requestId":"","http_status":0}`+"\n\n",
+		rr.Body.String())
+}
+
+func TestDetails(t *testing.T) {
+	field := FieldError{Field: "cursor", Code: FIELD_INVALID}
+	assert.Equal(t, []FieldError{field}, Details(field))
+	assert.Equal(t, []FieldError{field}, Details(fmt.Errorf("%w: %w", field, errors.New("bad cursor"))))
+	assert.Nil(t, Details(errors.New("db error")))
+	assert.EqualError(t, FieldError{Field: "limit", Code: FIELD_OUT_OF_RANGE}, "limit out of range")
+}
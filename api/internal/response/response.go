@@ -12,6 +12,7 @@ import (
 type Response[T any] struct {
 	Data       T      `json:"data"`
 	Message    string `json:"message"`
+	Error      *Error `json:"error,omitempty"`
 	RequestID  string `json:"requestId"`
 	HttpStatus int    `json:"http_status"`
 }
@@ -29,19 +30,44 @@ func WriteJSONError[T any](w http.ResponseWriter, r *http.Request, res Response[
 }
 
 func writeJSON[T any](w http.ResponseWriter, r *http.Request, res Response[T], status int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
 	res.HttpStatus = status
 	if requestID, ok := r.Context().Value(global.RequestIDKey).(string); ok {
 		res.RequestID = requestID
 	}
+	res = withError(res)
+	if res.Error != nil && status >= http.StatusBadRequest && acceptsProblem(r) {
+		w.Header().Set("Content-Type", ProblemContentType)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(newProblem(r, *res.Error, status, res.RequestID))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(res)
 }
 
+// withError fills the message and the docs url from the error of the response, the message is kept
+// for the clients not reading the error yet
+func withError[T any](res Response[T]) Response[T] {
+	if res.Error == nil {
+		return res
+	}
+	e := *res.Error
+	if res.Message == "" {
+		res.Message = e.Message
+	}
+	if docsURL != "" && e.DocsURL == "" {
+		e.DocsURL = docsURL + "#" + e.Code
+	}
+	res.Error = &e
+	return res
+}
+
 func WriteJSONEvent[T any](w http.ResponseWriter, r *http.Request, res Response[T]) {
 	if requestID, ok := r.Context().Value(global.RequestIDKey).(string); ok {
 		res.RequestID = requestID
 	}
+	res = withError(res)
 	var data strings.Builder
 	json.NewEncoder(&data).Encode(res)
 	fmt.Fprintf(w, "data:%s\n\n", strings.TrimSpace(data.String()))
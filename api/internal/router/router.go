@@ -1,13 +1,20 @@
 package router
 
 import (
+	"expvar"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
+	biz_device "via/internal/biz/device"
+	biz_operator "via/internal/biz/operator"
 	"via/internal/config"
 	"via/internal/ds"
 	"via/internal/global"
 	"via/internal/handler"
 	"via/internal/middleware"
+	"via/internal/model"
+	"via/internal/openapi"
 	"via/internal/ratelimit"
 	"via/internal/response"
 	"via/internal/sse"
@@ -16,11 +23,39 @@ import (
 )
 
 var rateFunctionByIP = func(r *http.Request) string {
-	return r.RemoteAddr
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateFunctionByDevice identifies paired devices by id and anybody else by IP
+var rateFunctionByDevice = func(r *http.Request) string {
+	if device, ok := r.Context().Value(middleware.DeviceKey).(model.Device); ok {
+		return "device:" + strconv.Itoa(device.ID)
+	}
+	return "ip:" + rateFunctionByIP(r)
+}
+
+// deviceAuth limits by IP the requests reaching the device token check, so guessing tokens is
+// rate limited and locked out before any device is known
+func deviceAuth(r chi.Router, cfg config.Config) {
+	r.Use(middleware.RateLimit(middleware.RateLimitMiddleware{
+		RateLimiter: ratelimit.New("DeviceAuth", cfg.DeviceAuthLimit.Limit,
+			time.Duration(cfg.DeviceAuthLimit.Window)*time.Second, ds.Get()),
+		KeyGetter: rateFunctionByIP,
+	}))
+	r.Use(middleware.Lockout(middleware.LockoutMiddleware{
+		Lockout:   ratelimit.NewLockout("DeviceAuthLockout", cfg.DeviceAuthLockout, ds.Get()),
+		KeyGetter: rateFunctionByIP,
+	}))
 }
 
 func NewRest(cfg config.Config) http.Handler {
 	r := chi.NewRouter()
+	// the requests are validated last, once the device, the operator and the rate limits allowed them
+	validate := middleware.ValidateRequest(openapi.Get(), cfg.Application.MaxBodyBytes)
 	r.Use(middleware.CORS(cfg.CORS))
 	r.Use(middleware.Recover)
 	r.Use(middleware.Timeout(time.Duration(cfg.Application.RequestTimeout) * time.Second))
@@ -31,40 +66,206 @@ func NewRest(cfg config.Config) http.Handler {
 				RateLimiter: ratelimit.New("Login", 6, 1*time.Minute, ds.Get()),
 				KeyGetter:   rateFunctionByIP,
 			},
+			"/auth/refresh": {
+				RateLimiter: ratelimit.New("Refresh", 12, 1*time.Minute, ds.Get()),
+				KeyGetter:   rateFunctionByIP,
+			},
+			"/auth/local": {
+				RateLimiter: ratelimit.New("LocalLogin", 6, 1*time.Minute, ds.Get()),
+				KeyGetter:   rateFunctionByIP,
+			},
+			"/device/pair": {
+				RateLimiter: ratelimit.New("DevicePairing", 6, 1*time.Minute, ds.Get()),
+				KeyGetter:   rateFunctionByIP,
+			},
 		},
 	))
+	r.NotFound(handler.NotFound().ServeHTTP)
+	r.MethodNotAllowed(handler.MethodNotAllowed().ServeHTTP)
 
-	r.Get("/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		response.WriteJSON(w, r, response.Response[any]{Data: "ok", Message: "ping status"}, http.StatusOK)
-	}))
+	r.Group(func(r chi.Router) {
+		r.Use(validate)
+
+		r.Get("/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response.WriteJSON(w, r, response.Response[any]{Data: "ok", Message: "ping status"}, http.StatusOK)
+		}))
+
+		r.Get("/.well-known/jwks.json", middleware.LogHandlerExecution("handler.GetJWKS",
+			handler.GetJWKS().ServeHTTP))
+
+		// the document of the REST and SSE APIs and its docs UI
+		r.Get("/openapi.yaml", handler.GetOpenAPIYAML().ServeHTTP)
+		r.Get("/openapi.json", handler.GetOpenAPIJSON().ServeHTTP)
+		r.Get("/docs", handler.GetDocs().ServeHTTP)
+	})
 
 	// Routes
-	r.Get("/guide-to-withdraw/{viaGuideId}", middleware.LogHandlerExecution("handler.GetGuideToWithdraw",
-		handler.GetGuideToWithdraw(cfg.Bussiness).ServeHTTP))
+	r.Group(func(r chi.Router) {
+		deviceAuth(r, cfg)
+		r.Use(middleware.Device(middleware.DeviceMiddleware{
+			Cfg:    cfg.Device,
+			Kind:   biz_device.KIND_KIOSK,
+			Branch: cfg.Bussiness.ViaBranch,
+		}))
+		r.Use(middleware.RateLimit(middleware.RateLimitMiddleware{
+			RateLimiter: ratelimit.New("GuideLookup", cfg.GuideLookupLimit.Limit,
+				time.Duration(cfg.GuideLookupLimit.Window)*time.Second, ds.Get()),
+			KeyGetter: rateFunctionByDevice,
+		}))
+		r.Use(middleware.Lockout(middleware.LockoutMiddleware{
+			Lockout:   ratelimit.NewLockout("GuideLookupLockout", cfg.GuideLookupLockout, ds.Get()),
+			KeyGetter: rateFunctionByDevice,
+		}))
+		r.Use(validate)
 
-	r.Post("/guide-to-withdraw", middleware.LogHandlerExecution("handler.CreateGuideToWidthdraw",
-		handler.CreateGuideToWidthdraw(cfg.Bussiness).ServeHTTP))
+		r.Get("/guide-to-withdraw/{viaGuideId}", middleware.LogHandlerExecution("handler.GetGuideToWithdraw",
+			handler.GetGuideToWithdraw(cfg.Bussiness).ServeHTTP))
 
-	r.Get("/auth/login", middleware.LogHandlerExecution("handler.Login",
-		handler.Login().ServeHTTP))
+		r.Post("/guide-to-withdraw", middleware.LogHandlerExecution("handler.CreateGuideToWidthdraw",
+			handler.CreateGuideToWidthdraw(cfg.Bussiness).ServeHTTP))
+	})
 
-	r.Get("/auth/callback", middleware.LogHandlerExecution("handler.LoginCallback",
-		handler.LoginCallback(cfg.OAuth).ServeHTTP))
+	// the kiosks and monitors exchange the pairing code for their token
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Lockout(middleware.LockoutMiddleware{
+			Lockout:   ratelimit.NewLockout("DevicePairingLockout", cfg.DevicePairingLockout, ds.Get()),
+			KeyGetter: rateFunctionByIP,
+		}))
+		r.Use(validate)
 
-	r.Post("/auth/logout", middleware.LogHandlerExecution("handler.LogOut",
-		handler.LogOut(cfg.OAuth).ServeHTTP))
+		r.Post("/device/pair", middleware.LogHandlerExecution("handler.PairDevice",
+			handler.PairDevice(cfg.Device, cfg.OAuth).ServeHTTP))
+	})
 
 	r.Group(func(r chi.Router) {
-		r.Use(middleware.Auth(cfg.OAuth))
+		r.Use(validate)
+
+		r.Get("/auth/login", middleware.LogHandlerExecution("handler.Login",
+			handler.Login().ServeHTTP))
 
-		r.Post("/guide/{guideId}/assign", middleware.LogHandlerExecution("handler.AssignGuideToOperator",
-			handler.AssignGuideToOperator().ServeHTTP))
+		r.Get("/auth/callback", middleware.LogHandlerExecution("handler.LoginCallback",
+			handler.LoginCallback(cfg.OAuth).ServeHTTP))
 
-		r.Get("/guide/{guideId}/status-options", middleware.LogHandlerExecution("handler.GetGuideStatusOptions",
-			handler.GetGuideStatusOptions().ServeHTTP))
+		r.Post("/auth/logout", middleware.LogHandlerExecution("handler.LogOut",
+			handler.LogOut(cfg.OAuth).ServeHTTP))
 
-		r.Put("/guide/{guideId}/status", middleware.LogHandlerExecution("handler.UpdateGuideStatus",
-			handler.UpdateGuideStatus().ServeHTTP))
+		r.Post("/auth/refresh", middleware.LogHandlerExecution("handler.Refresh",
+			handler.Refresh(cfg.OAuth).ServeHTTP))
+
+		r.Get("/auth/providers", middleware.LogHandlerExecution("handler.GetAuthProviders",
+			handler.GetAuthProviders(cfg.OAuth).ServeHTTP))
+	})
+
+	// emergency login while the identity providers are unreachable
+	if cfg.OAuth.LocalLogin {
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Lockout(middleware.LockoutMiddleware{
+				Lockout:   ratelimit.NewLockout("LocalLoginLockout", cfg.LocalLoginLockout, ds.Get()),
+				KeyGetter: rateFunctionByIP,
+			}))
+			r.Use(validate)
+
+			r.Post("/auth/local", middleware.LogHandlerExecution("handler.LocalLogin",
+				handler.LocalLogin(cfg.OAuth).ServeHTTP))
+		})
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Auth())
+
+		r.Group(func(r chi.Router) {
+			r.Use(validate)
+
+			r.Post("/guide/{guideId}/assign", middleware.LogHandlerExecution("handler.AssignGuideToOperator",
+				handler.AssignGuideToOperator().ServeHTTP))
+
+			r.Post("/guide/{guideId}/release", middleware.LogHandlerExecution("handler.ReleaseGuide",
+				handler.ReleaseGuide().ServeHTTP))
+
+			r.Post("/guide/{guideId}/transfer", middleware.LogHandlerExecution("handler.TransferGuide",
+				handler.TransferGuide().ServeHTTP))
+
+			r.Get("/guide/{guideId}/status-options", middleware.LogHandlerExecution("handler.GetGuideStatusOptions",
+				handler.GetGuideStatusOptions().ServeHTTP))
+
+			r.Put("/guide/{guideId}/status", middleware.LogHandlerExecution("handler.UpdateGuideStatus",
+				handler.UpdateGuideStatus().ServeHTTP))
+
+			r.Post("/guide/{guideId}/sync", middleware.LogHandlerExecution("handler.SyncGuide",
+				handler.SyncGuide().ServeHTTP))
+
+			r.Get("/guide/search", middleware.LogHandlerExecution("handler.SearchGuides",
+				handler.SearchGuides().ServeHTTP))
+
+			r.Put("/operator/availability", middleware.LogHandlerExecution("handler.SetOperatorAvailability",
+				handler.SetOperatorAvailability().ServeHTTP))
+
+			r.Get("/operators/online", middleware.LogHandlerExecution("handler.GetOnlineOperators",
+				handler.GetOnlineOperators().ServeHTTP))
+
+			r.Get("/operator/sessions", middleware.LogHandlerExecution("handler.GetSessions",
+				handler.GetSessions().ServeHTTP))
+
+			r.Delete("/operator/sessions", middleware.LogHandlerExecution("handler.RevokeSessions",
+				handler.RevokeSessions(cfg.OAuth).ServeHTTP))
+
+			r.Delete("/operator/sessions/{sessionId}", middleware.LogHandlerExecution("handler.RevokeSession",
+				handler.RevokeSession(cfg.OAuth).ServeHTTP))
+
+			r.Get("/metrics", expvar.Handler().ServeHTTP)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireRole(biz_operator.ROLE_SUPERVISOR))
+			r.Use(validate)
+
+			r.Get("/supervisor/discrepancies", middleware.LogHandlerExecution("handler.GetDiscrepancies",
+				handler.GetDiscrepancies().ServeHTTP))
+
+			r.Get("/supervisor/workload", middleware.LogHandlerExecution("handler.GetWorkload",
+				handler.GetWorkload().ServeHTTP))
+
+			r.Put("/supervisor/guide/{guideId}/operator", middleware.LogHandlerExecution("handler.OverrideGuideAssignment",
+				handler.OverrideGuideAssignment().ServeHTTP))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireRole(biz_operator.ROLE_ADMIN))
+			r.Use(validate)
+
+			r.Post("/admin/close-day", middleware.LogHandlerExecution("handler.CloseDay",
+				handler.CloseDay(cfg.CloseDay).ServeHTTP))
+
+			r.Get("/admin/operator/{operatorId}/sessions", middleware.LogHandlerExecution("handler.GetSessions",
+				handler.GetSessions().ServeHTTP))
+
+			r.Delete("/admin/operator/{operatorId}/sessions", middleware.LogHandlerExecution("handler.RevokeSessions",
+				handler.RevokeSessions(cfg.OAuth).ServeHTTP))
+
+			r.Delete("/admin/operator/{operatorId}/sessions/{sessionId}", middleware.LogHandlerExecution("handler.RevokeSession",
+				handler.RevokeSession(cfg.OAuth).ServeHTTP))
+
+			r.Get("/admin/audit", middleware.LogHandlerExecution("handler.SearchAudit",
+				handler.SearchAudit().ServeHTTP))
+
+			r.Get("/admin/audit/verify", middleware.LogHandlerExecution("handler.VerifyAudit",
+				handler.VerifyAudit().ServeHTTP))
+
+			r.Get("/admin/devices", middleware.LogHandlerExecution("handler.GetDevices",
+				handler.GetDevices().ServeHTTP))
+
+			r.Post("/admin/devices", middleware.LogHandlerExecution("handler.CreateDevice",
+				handler.CreateDevice(cfg.Device).ServeHTTP))
+
+			r.Post("/admin/devices/{deviceId}/pairing-code", middleware.LogHandlerExecution("handler.CreateDevicePairingCode",
+				handler.CreateDevicePairingCode(cfg.Device).ServeHTTP))
+
+			r.Put("/admin/devices/{deviceId}/privacy", middleware.LogHandlerExecution("handler.SetDevicePrivacy",
+				handler.SetDevicePrivacy().ServeHTTP))
+
+			r.Delete("/admin/devices/{deviceId}", middleware.LogHandlerExecution("handler.RevokeDevice",
+				handler.RevokeDevice().ServeHTTP))
+		})
 	})
 
 	return r
@@ -72,6 +273,8 @@ func NewRest(cfg config.Config) http.Handler {
 
 func NewSSE(cfg config.Config) http.Handler {
 	r := chi.NewRouter()
+	// the requests are validated last, once the device, the operator and the rate limits allowed them
+	validate := middleware.ValidateRequest(openapi.Get(), cfg.Application.MaxBodyBytes)
 	r.Use(middleware.CORS(cfg.CORS))
 	r.Use(middleware.Recover)
 	r.Use(middleware.Request)
@@ -87,28 +290,53 @@ func NewSSE(cfg config.Config) http.Handler {
 			},
 		},
 	))
+	r.NotFound(handler.NotFound().ServeHTTP)
+	r.MethodNotAllowed(handler.MethodNotAllowed().ServeHTTP)
 	// Routes
-	r.Get("/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		response.WriteJSON(w, r, response.Response[any]{Data: "ok", Message: "ping status"}, http.StatusOK)
-	}))
+	r.Group(func(r chi.Router) {
+		r.Use(validate)
 
-	r.Get("/monitor/events", middleware.LogHandlerExecution("handler.GetMonitorEvents",
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			sse.HandleSSE(w, r, handler.GetMonitorEvents, global.NewGuideChannel, global.GuideStatusChangeChannel)
-		})))
+		r.Get("/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response.WriteJSON(w, r, response.Response[any]{Data: "ok", Message: "ping status"}, http.StatusOK)
+		}))
+	})
 
-	r.Get("/auth/login", middleware.LogHandlerExecution("handler.Login",
-		handler.Login().ServeHTTP))
+	r.Group(func(r chi.Router) {
+		deviceAuth(r, cfg)
+		r.Use(middleware.Device(middleware.DeviceMiddleware{
+			Cfg:    cfg.Device,
+			Kind:   biz_device.KIND_MONITOR,
+			Branch: cfg.Bussiness.ViaBranch,
+		}))
+		r.Use(middleware.DeviceRevocation)
+		r.Use(validate)
 
-	r.Get("/auth/callback", middleware.LogHandlerExecution("handler.LoginCallback",
-		handler.LoginCallback(cfg.OAuth).ServeHTTP))
+		r.Get("/monitor/events", middleware.LogHandlerExecution("handler.GetMonitorEvents",
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sse.HandleSSE(w, r, handler.GetMonitorEvents(cfg.Bussiness), global.NewGuideChannel, global.GuideStatusChangeChannel)
+			})))
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(validate)
+
+		r.Get("/auth/login", middleware.LogHandlerExecution("handler.Login",
+			handler.Login().ServeHTTP))
+
+		r.Get("/auth/callback", middleware.LogHandlerExecution("handler.LoginCallback",
+			handler.LoginCallback(cfg.OAuth).ServeHTTP))
+	})
 
 	r.Group(func(r chi.Router) {
-		r.Use(middleware.Auth(cfg.OAuth))
+		r.Use(middleware.RenewSession(cfg.OAuth))
+		r.Use(middleware.Auth())
+		r.Use(middleware.Presence)
+		r.Use(validate)
 
 		r.Get("/operator/guides", middleware.LogHandlerExecution("handler.GetOperatorGuides",
 			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				sse.HandleSSE(w, r, handler.GetOperatorGuide, global.NewGuideChannel, global.GuideAssignmentChannel, global.GuideStatusChangeChannel)
+				sse.HandleSSE(w, r, handler.GetOperatorGuide, global.NewGuideChannel, global.GuideAssignmentChannel, global.GuideStatusChangeChannel,
+					global.GuideSyncChannel)
 			})))
 	})
 	return r
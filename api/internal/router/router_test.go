@@ -1,38 +1,70 @@
 // internal/router/router_test.go
 package router_test
 
-/*
-func TestRouter_New(t *testing.T) {
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"via/internal/auth"
+	"via/internal/config"
+	"via/internal/openapi"
+	"via/internal/router"
+	"via/internal/testutil"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRoutesDocumented keeps the api document and the routes of the REST and SSE routers in sync
+func TestRoutesDocumented(t *testing.T) {
 	testutil.InjectNoOpLogger()
-	cfg := config.Config{
-		Application: config.Application{
-			RequestTimeout: 5,
-		},
-		CORS: middleware.CORSCfg{
-			Origins: "*",
-		},
-		Bussiness: biz_config.BussinessCfg{
-			ViaBranch:       "001",
-			PendingStatus:   "PENDING",
-			DeliveredStatus: "DELIVERED",
-			WithdrawStatus:  "WITHDRAW",
-		},
+	cfg := config.Config{OAuth: auth.OAuthConfig{LocalLogin: true}}
+
+	routes := []string{}
+	for _, h := range []http.Handler{router.NewRest(cfg), router.NewSSE(cfg)} {
+		err := chi.Walk(h.(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			if !slices.Contains(routes, method+" "+route) {
+				routes = append(routes, method+" "+route)
+			}
+			return nil
+		})
+		assert.NoError(t, err)
 	}
+	slices.Sort(routes)
 
-	h := router.New(cfg)
-	mockViaGuideProvider := new(mock_via_guide_provider.MockViaGuideProvider)
-	mockViaGuideProvider.On("GetGuide", mock.Anything, "123456789012").Return(model.ViaGuide{ID: "123456789012"}, nil)
-	via_guide_provider.Set(mockViaGuideProvider)
+	assert.Equal(t, openapi.Get().Methods(), routes)
+}
 
-	mockGuideProvider := new(mock_guide_provider.MockGuideProvider)
-	guide_provider.Set(mockGuideProvider)
-	r := httptest.NewRequest(http.MethodGet, "/guide/123456789012", nil)
-	w := httptest.NewRecorder()
+// TestRouteErrors keeps the structured errors of the unknown routes and methods on both routers
+func TestRouteErrors(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	cfg := config.Config{}
+
+	for _, h := range []http.Handler{router.NewRest(cfg), router.NewSSE(cfg)} {
+		mux := h.(*chi.Mux)
 
-	h.ServeHTTP(w, r)
+		w := httptest.NewRecorder()
+		mux.NotFoundHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nope", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"route_not_found"`)
 
-	if w.Code == 400 || w.Code == 500 {
-		t.Errorf("expected router to handle request gracefully, got status %d", w.Code)
+		w = httptest.NewRecorder()
+		mux.MethodNotAllowedHandler().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/ping", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"method_not_allowed"`)
 	}
 }
-*/
+
+func TestValidationAfterAuth(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	cfg := config.Config{}
+	cfg.Application.RequestTimeout = 5
+	cfg.Application.MaxBodyBytes = 1024
+
+	w := httptest.NewRecorder()
+	router.NewRest(cfg).ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/guide/12/status", strings.NewReader(`{"status":"lost"}`)))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+	"via/internal/ds"
+	"via/internal/log"
+
+	"github.com/google/uuid"
+)
+
+const lockKeyPrefix = "scheduler:lock:"
+
+// Job is a task run every Interval by a single API instance
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs periodically. Before each run an instance takes the job lock in ds,
+// held for the job interval, so a run happens once per interval whatever the number of instances.
+// A run longer than the interval keeps renewing the lock, and is cancelled if the lock is lost:
+// a job running until its context is done is run by a single instance at a time, the others
+// take it over within an interval when it stops.
+type Scheduler struct {
+	ds         ds.DS
+	instanceID string
+	jobs       []Job
+	wg         sync.WaitGroup
+}
+
+func New(ds ds.DS) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{ds: ds, instanceID: host + ":" + uuid.New().String()}
+}
+
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches the jobs until ctx is done
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+			for {
+				s.RunOnce(ctx, job)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(job)
+	}
+}
+
+// Wait blocks until the running jobs have returned after ctx is done
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// RunOnce runs the job if this instance gets its lock, reporting whether it ran
+func (s *Scheduler) RunOnce(ctx context.Context, job Job) bool {
+	logger := log.Get()
+	ttl := int(job.Interval / time.Second)
+	if ttl < 1 {
+		ttl = 1
+	}
+	acquired, err := s.ds.SetNX(ctx, lockKeyPrefix+job.Name, s.instanceID, ttl)
+	if err != nil {
+		logger.Error(ctx, err, "msg", "unable to acquire job lock", "job", job.Name)
+		return false
+	}
+	if !acquired {
+		logger.Debug(ctx, "msg", "job locked by another instance", "job", job.Name)
+		return false
+	}
+
+	start := time.Now()
+	runCtx, release := s.hold(ctx, lockKeyPrefix+job.Name, ttl)
+	defer func() {
+		release(ttl - int(time.Since(start)/time.Second))
+	}()
+	logger.Info(ctx, "msg", "job started", "job", job.Name, "instance", s.instanceID)
+	if err := job.Run(runCtx); err != nil {
+		logger.Error(ctx, err, "msg", "job failed", "job", job.Name, "elapsed", time.Since(start).String())
+		return true
+	}
+	logger.Info(ctx, "msg", "job finished", "job", job.Name, "elapsed", time.Since(start).String())
+	return true
+}
+
+// hold renews the lock taken by this instance every third of its ttl, the returned context is
+// cancelled when the lock is lost so the work stops before another instance takes it over. release
+// stops the renewal, leaving the lock for the seconds given, 0 or less frees it.
+func (s *Scheduler) hold(ctx context.Context, key string, ttl int) (context.Context, func(seconds int)) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(time.Duration(ttl) * time.Second / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			held, err := s.ds.Renew(ctx, key, s.instanceID, ttl)
+			if err != nil {
+				// the lock lasts for ttl since the last renewal, the next tick retries
+				log.Get().Error(ctx, err, "msg", "unable to renew lock", "key", key)
+				continue
+			}
+			if !held {
+				log.Get().Warn(ctx, "msg", "lock lost, stopping", "key", key)
+				cancel()
+				return
+			}
+		}
+	}()
+	return ctx, func(seconds int) {
+		cancel()
+		<-done
+		if seconds < 0 {
+			seconds = 0
+		}
+		// ctx is cancelled by now, the lock is still set to the seconds asked
+		if _, err := s.ds.Renew(context.WithoutCancel(ctx), key, s.instanceID, seconds); err != nil {
+			log.Get().Error(ctx, err, "msg", "unable to release lock", "key", key)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunOnce(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	tests := []struct {
+		name      string
+		acquired  bool
+		lockErr   error
+		jobErr    error
+		expectRan bool
+	}{
+		{name: "lock acquired", acquired: true, expectRan: true},
+		{name: "job fails", acquired: true, jobErr: errors.New("job error"), expectRan: true},
+		{name: "locked by another instance", acquired: false},
+		{name: "lock error", lockErr: errors.New("ds error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockDS.On("SetNX", mock.Anything, "scheduler:lock:reconcile", mock.Anything, 60).
+				Return(tt.acquired, tt.lockErr).Once()
+			if tt.expectRan {
+				// released for what is left of the interval
+				mockDS.On("Renew", mock.Anything, "scheduler:lock:reconcile", mock.Anything, 60).Return(true, nil).Once()
+			}
+			s := New(mockDS)
+
+			called := false
+			ran := s.RunOnce(context.Background(), Job{Name: "reconcile", Interval: time.Minute,
+				Run: func(ctx context.Context) error {
+					called = true
+					return tt.jobErr
+				}})
+
+			assert.Equal(t, tt.expectRan, ran)
+			assert.Equal(t, tt.expectRan, called)
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	testutil.InjectNoOpLogger()
+	mockDS := new(mock_ds.MockDS)
+	mockDS.On("SetNX", mock.Anything, "scheduler:lock:tick", mock.Anything, 1).Return(true, nil)
+	mockDS.On("Renew", mock.Anything, "scheduler:lock:tick", mock.Anything, mock.Anything).Return(true, nil)
+
+	var runs atomic.Int32
+	s := New(mockDS)
+	s.Add(Job{Name: "tick", Interval: 10 * time.Millisecond, Run: func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	cancel()
+	s.Wait()
+}
+
+func TestRunOnce_Renewal(t *testing.T) {
+	testutil.InjectNoOpLogger()
+
+	tests := []struct {
+		name           string
+		held           bool
+		expectCancel   bool
+		releaseSeconds int
+	}{
+		// the run took the whole interval, the lock is freed
+		{name: "lock renewed while running", held: true},
+		{name: "lock lost cancels the job", held: false, expectCancel: true, releaseSeconds: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockDS.On("SetNX", mock.Anything, "scheduler:lock:slow", mock.Anything, 1).Return(true, nil).Once()
+			mockDS.On("Renew", mock.Anything, "scheduler:lock:slow", mock.Anything, mock.Anything).Return(tt.held, nil)
+			s := New(mockDS)
+
+			cancelled := false
+			s.RunOnce(context.Background(), Job{Name: "slow", Interval: time.Second,
+				Run: func(ctx context.Context) error {
+					select {
+					case <-ctx.Done():
+						cancelled = true
+					case <-time.After(1200 * time.Millisecond):
+					}
+					return nil
+				}})
+
+			assert.Equal(t, tt.expectCancel, cancelled)
+			mockDS.AssertCalled(t, "Renew", mock.Anything, "scheduler:lock:slow", mock.Anything, 1)
+			mockDS.AssertCalled(t, "Renew", mock.Anything, "scheduler:lock:slow", mock.Anything, tt.releaseSeconds)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"via/internal/cache"
+	"via/internal/ds"
+	"via/internal/model"
+)
+
+// Session is the stored operator login, only the access token with its jti is accepted for it
+type Session struct {
+	model.Session
+	JTI        string `json:"jti"`
+	IDPIDToken string `json:"idpIdToken"`
+}
+
+// Store keeps the active sessions, a session is revoked by removing it
+type Store struct {
+	ds    ds.DS
+	cache *cache.Cache
+	ttl   int // in seconds
+}
+
+func New(ds ds.DS, ttlSeconds int) *Store {
+	return &Store{ds: ds, cache: cache.New(ds), ttl: ttlSeconds}
+}
+
+func key(id string) string {
+	return "session:" + id
+}
+
+// the set of the session ids of the operator
+func operatorKey(operatorId int) string {
+	return "sessions:operator:" + strconv.Itoa(operatorId) + ":ids"
+}
+
+// Save stores the session for another TTL and indexes it under its operator. The index is a set,
+// concurrent logins of the same operator are all indexed.
+func (s *Store) Save(ctx context.Context, session Session) error {
+	if err := s.cache.Set(ctx, key(session.ID), session, s.ttl); err != nil {
+		return fmt.Errorf("storing session: %w", err)
+	}
+	if err := s.ds.SAdd(ctx, operatorKey(session.OperatorID), session.ID, s.ttl); err != nil {
+		return fmt.Errorf("storing operator sessions: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, id string) (Session, bool, error) {
+	var session Session
+	found, err := s.cache.Get(ctx, key(id), &session)
+	if err != nil {
+		return session, false, fmt.Errorf("getting session: %w", err)
+	}
+	return session, found, nil
+}
+
+// IsActive reports whether the session was not revoked and the access token is its latest one
+func (s *Store) IsActive(ctx context.Context, id string, jti string) (bool, error) {
+	session, found, err := s.Get(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return found && session.JTI == jti, nil
+}
+
+// List returns the active sessions of the operator, the expired or revoked ones leave the index
+func (s *Store) List(ctx context.Context, operatorId int) ([]model.Session, error) {
+	ids, err := s.ids(ctx, operatorId)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []model.Session{}
+	for _, id := range ids {
+		session, found, err := s.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			if err := s.ds.SRem(ctx, operatorKey(operatorId), id); err != nil {
+				return nil, fmt.Errorf("removing operator session: %w", err)
+			}
+			continue
+		}
+		sessions = append(sessions, session.Session)
+	}
+	return sessions, nil
+}
+
+// Revoke ends the session, its access and refresh tokens are rejected from then on
+func (s *Store) Revoke(ctx context.Context, id string) error {
+	if err := s.ds.Del(ctx, key(id)); err != nil {
+		return fmt.Errorf("revoking session %s: %w", id, err)
+	}
+	return nil
+}
+
+// RevokeAll ends every session of the operator, returning how many there were. Each revoked id
+// leaves the index alone, a login saved meanwhile stays indexed.
+func (s *Store) RevokeAll(ctx context.Context, operatorId int) (int, error) {
+	ids, err := s.ids(ctx, operatorId)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if err := s.Revoke(ctx, id); err != nil {
+			return 0, err
+		}
+		if err := s.ds.SRem(ctx, operatorKey(operatorId), id); err != nil {
+			return 0, fmt.Errorf("revoking operator sessions: %w", err)
+		}
+	}
+	return len(ids), nil
+}
+
+func (s *Store) ids(ctx context.Context, operatorId int) ([]string, error) {
+	ids, err := s.ds.SMembers(ctx, operatorKey(operatorId))
+	if err != nil {
+		return nil, fmt.Errorf("getting operator sessions: %w", err)
+	}
+	return ids, nil
+}
+
+var (
+	instance *Store
+	mutex    = &sync.RWMutex{}
+)
+
+func Get() *Store {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return instance
+}
+
+func Set(store *Store) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	instance = store
+}
@@ -0,0 +1,183 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	mock_ds "via/internal/ds/mock"
+	"via/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var ctx = context.Background()
+
+func newSession(id string, jti string) Session {
+	return Session{
+		Session: model.Session{ID: id, OperatorID: 7, CreatedAt: time.Unix(100, 0).UTC(), RenewedAt: time.Unix(200, 0).UTC()},
+		JTI:     jti,
+	}
+}
+
+func toJSON(t *testing.T, v any) string {
+	bytes, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(bytes)
+}
+
+func TestSave(t *testing.T) {
+	tests := []struct {
+		name      string
+		setErr    error
+		indexErr  error
+		expectErr bool
+	}{
+		{name: "session indexed"},
+		{name: "error storing session", setErr: errors.New("ds error"), expectErr: true},
+		{name: "error storing index", indexErr: errors.New("ds error"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			s := newSession("s1", "j1")
+			mockDS.On("Set", ctx, "session:s1", toJSON(t, s), 60).Return(tt.setErr)
+			if tt.setErr == nil {
+				mockDS.On("SAdd", ctx, "sessions:operator:7:ids", "s1", 60).Return(tt.indexErr)
+			}
+
+			err := New(mockDS, 60).Save(ctx, s)
+
+			assert.Equal(t, tt.expectErr, err != nil)
+			mockDS.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIsActive(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		getErr    error
+		jti       string
+		expected  bool
+		expectErr bool
+	}{
+		{name: "latest token", value: toJSON(t, newSession("s1", "j1")), jti: "j1", expected: true},
+		{name: "renewed token", value: toJSON(t, newSession("s1", "j2")), jti: "j1"},
+		{name: "revoked session", jti: "j1"},
+		{name: "invalid session", value: "{", jti: "j1", expectErr: true},
+		{name: "error getting session", getErr: errors.New("ds error"), jti: "j1", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockDS.On("Get", ctx, "session:s1").Return(tt.value != "", tt.value, tt.getErr)
+
+			active, err := New(mockDS, 60).IsActive(ctx, "s1", tt.jti)
+
+			assert.Equal(t, tt.expected, active)
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name       string
+		index      []string
+		indexErr   error
+		sessionErr error
+		remErr     error
+		expected   []model.Session
+		expectRem  bool
+		expectErr  bool
+	}{
+		{name: "no sessions", expected: []model.Session{}},
+		{name: "active sessions", index: []string{"s1"}, expected: []model.Session{newSession("s1", "j1").Session}},
+		{name: "revoked sessions leave the index", index: []string{"s1", "s2"},
+			expected: []model.Session{newSession("s1", "j1").Session}, expectRem: true},
+		{name: "error getting index", indexErr: errors.New("ds error"), expectErr: true},
+		{name: "error getting session", index: []string{"s1"}, sessionErr: errors.New("ds error"), expectErr: true},
+		{name: "error removing from index", index: []string{"s1", "s2"}, remErr: errors.New("ds error"), expectRem: true,
+			expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockDS.On("SMembers", ctx, "sessions:operator:7:ids").Return(tt.index, tt.indexErr)
+			mockDS.On("Get", ctx, "session:s1").Return(true, toJSON(t, newSession("s1", "j1")), tt.sessionErr)
+			mockDS.On("Get", ctx, "session:s2").Return(false, "", nil)
+			if tt.expectRem {
+				mockDS.On("SRem", ctx, "sessions:operator:7:ids", "s2").Return(tt.remErr).Once()
+			}
+
+			sessions, err := New(mockDS, 60).List(ctx, 7)
+
+			assert.Equal(t, tt.expectErr, err != nil)
+			if !tt.expectErr {
+				assert.Equal(t, tt.expected, sessions)
+			}
+			if !tt.expectRem {
+				mockDS.AssertNotCalled(t, "SRem", ctx, "sessions:operator:7:ids", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	mockDS := new(mock_ds.MockDS)
+	mockDS.On("Del", ctx, "session:s1").Return(nil).Once()
+	assert.NoError(t, New(mockDS, 60).Revoke(ctx, "s1"))
+
+	mockDS.On("Del", ctx, "session:s1").Return(errors.New("ds error")).Once()
+	assert.Error(t, New(mockDS, 60).Revoke(ctx, "s1"))
+}
+
+func TestRevokeAll(t *testing.T) {
+	tests := []struct {
+		name      string
+		index     []string
+		indexErr  error
+		delErr    error
+		remErr    error
+		expected  int
+		expectErr bool
+	}{
+		{name: "no sessions"},
+		{name: "every session revoked", index: []string{"s1", "s2"}, expected: 2},
+		{name: "error getting index", indexErr: errors.New("ds error"), expectErr: true},
+		{name: "error revoking session", index: []string{"s1"}, delErr: errors.New("ds error"), expectErr: true},
+		{name: "error removing from index", index: []string{"s1"}, remErr: errors.New("ds error"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDS := new(mock_ds.MockDS)
+			mockDS.On("SMembers", ctx, "sessions:operator:7:ids").Return(tt.index, tt.indexErr)
+			mockDS.On("Del", ctx, "session:s1").Return(tt.delErr)
+			mockDS.On("Del", ctx, "session:s2").Return(tt.delErr)
+			mockDS.On("SRem", ctx, "sessions:operator:7:ids", "s1").Return(tt.remErr)
+			mockDS.On("SRem", ctx, "sessions:operator:7:ids", "s2").Return(tt.remErr)
+
+			revoked, err := New(mockDS, 60).RevokeAll(ctx, 7)
+
+			assert.Equal(t, tt.expected, revoked)
+			assert.Equal(t, tt.expectErr, err != nil)
+			mockDS.AssertNotCalled(t, "Del", ctx, "sessions:operator:7:ids")
+		})
+	}
+}
+
+func TestGetSet(t *testing.T) {
+	store := New(new(mock_ds.MockDS), 60)
+	Set(store)
+	assert.Same(t, store, Get())
+	Set(nil)
+	assert.Nil(t, Get())
+}
@@ -20,7 +20,7 @@ func HandleSSE(w http.ResponseWriter, r *http.Request, loader Loader[any], event
 	if !ok {
 		log.Get().Error(r.Context(), errors.New("unable to get flush from writer"),
 			"msg", "unable to create SSE")
-		res := response.Response[any]{Message: i18n.Get(r, i18n.MsgInternalServerError)}
+		res := response.Response[any]{Error: i18n.Error(r, i18n.MsgInternalServerError)}
 		response.WriteJSONEvent(w, r, res)
 		return
 	}
@@ -28,7 +28,7 @@ func HandleSSE(w http.ResponseWriter, r *http.Request, loader Loader[any], event
 	sub, err := pubsub.Get().Subscribe(r.Context(), eventSource...)
 	if err != nil {
 		log.Get().Error(r.Context(), err, "msg", "unable to subscribe", "channel", eventSource)
-		res := response.Response[any]{Message: i18n.Get(r, i18n.MsgInternalServerError)}
+		res := response.Response[any]{Error: i18n.Error(r, i18n.MsgInternalServerError)}
 		response.WriteJSONEvent(w, r, res)
 		return
 	}
@@ -44,7 +44,7 @@ func HandleSSE(w http.ResponseWriter, r *http.Request, loader Loader[any], event
 		case event, ok := <-sub.Channel():
 			if !ok {
 				log.Get().Warn(r.Context(), "msg", "Subscription channel closed")
-				res := response.Response[any]{Message: i18n.Get(r, i18n.MsgInternalServerError)}
+				res := response.Response[any]{Error: i18n.Error(r, i18n.MsgInternalServerError)}
 				response.WriteJSONEvent(w, r, res)
 				return
 			}
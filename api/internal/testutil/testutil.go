@@ -7,12 +7,18 @@ import (
 	jwt_key_mock "via/internal/jwt/mock"
 	"via/internal/log"
 	mock_log "via/internal/log/mock"
+	audit_provider "via/internal/provider/audit"
+	mock_audit_provider "via/internal/provider/audit/mock"
 )
 
 func InjectNoOpLogger() {
 	log.Set(new(mock_log.MockNoOpLogger))
 }
 
+func InjectNoOpAuditProvider() {
+	audit_provider.Set(new(mock_audit_provider.MockNoOpAuditProvider))
+}
+
 func InjectMockJWTKey() {
 	jwt_key.Reset()
 	jwt_key.Init(jwt_key.JWTConfig{
@@ -0,0 +1,85 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	digits = 6
+	period = 30 // in seconds
+	skew   = 1  // steps accepted before and after the current one, the clock drift of the device
+)
+
+// Window is how long, in seconds, a code is accepted
+const Window = (2*skew + 1) * period
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var randRead = rand.Read
+
+// GenerateSecret returns a random base32 secret of 160 bits, the size RFC 4226 recommends
+func GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := randRead(b); err != nil {
+		return "", fmt.Errorf("generating secret: %w", err)
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// URI is the otpauth URI the authenticator apps import, usually from a QR code
+func URI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(period))
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + query.Encode()
+}
+
+// Code returns the code of the time step, as RFC 6238 with HMAC-SHA1
+func Code(secret string, step uint64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", fmt.Errorf("decoding secret: %w", err)
+	}
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, step)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, value%1_000_000), nil
+}
+
+// Step returns the time step of t
+func Step(t time.Time) uint64 {
+	return uint64(t.Unix()) / period
+}
+
+// Validate checks the code against the steps around t, returning the matching step so a reuse of
+// the code can be rejected
+func Validate(secret, code string, t time.Time) (uint64, bool) {
+	if len(code) != digits {
+		return 0, false
+	}
+	current := Step(t)
+	for step := current - skew; step <= current+skew; step++ {
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package totp
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the RFC 6238 SHA1 test secret, "12345678901234567890"
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		unix     int64
+		expected string
+	}{
+		{name: "59", unix: 59, expected: "287082"},
+		{name: "1111111109", unix: 1111111109, expected: "081804"},
+		{name: "1234567890", unix: 1234567890, expected: "005924"},
+		{name: "2000000000", unix: 2000000000, expected: "279037"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, code)
+		})
+	}
+
+	_, err := Code("not base32!", 1)
+	assert.ErrorContains(t, err, "decoding secret")
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current, _ := Code(rfcSecret, Step(now))
+	previous, _ := Code(rfcSecret, Step(now)-1)
+	old, _ := Code(rfcSecret, Step(now)-2)
+
+	tests := []struct {
+		name       string
+		secret     string
+		code       string
+		expectStep uint64
+		expectOk   bool
+	}{
+		{name: "current step", secret: rfcSecret, code: current, expectStep: Step(now), expectOk: true},
+		{name: "previous step, clock drift", secret: rfcSecret, code: previous, expectStep: Step(now) - 1, expectOk: true},
+		{name: "expired code", secret: rfcSecret, code: old},
+		{name: "wrong length", secret: rfcSecret, code: "12345"},
+		{name: "invalid secret", secret: "not base32!", code: current},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, now)
+			assert.Equal(t, tt.expectOk, ok)
+			assert.Equal(t, tt.expectStep, step)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+	_, err = Code(secret, 1)
+	assert.NoError(t, err)
+
+	randRead = func([]byte) (int, error) { return 0, errors.New("no entropy") }
+	t.Cleanup(func() { randRead = rand.Read })
+	_, err = GenerateSecret()
+	assert.EqualError(t, err, "generating secret: no entropy")
+}
+
+func TestURI(t *testing.T) {
+	assert.Equal(t, "otpauth://totp/via:ana@example.com?digits=6&issuer=via&period=30&secret=ABC",
+		URI("via", "ana@example.com", "ABC"))
+}
@@ -1,7 +1,22 @@
 import { ref, nextTick, onMounted, onBeforeUnmount, computed, handleError } from 'vue'
-import {assignGuideToOperator, getGuideStatusOptions, changeGuideStatus, handleAuthRedirect, doLogout } from '../services/api'
+import {assignGuideToOperator, releaseGuide, getGuideStatusOptions, changeGuideStatus, syncGuide, setAvailability, handleAuthRedirect, doLogout, doLogoutEverywhere } from '../services/api'
 import {apiSSEUrl, apiSSE} from '../services/apiConfig'
 
+const PAGE_SIZE = 50
+
+// statuses of the operator feed, as the api describes them
+const operatorStatusOptions = [
+  { id: 'initial', description: 'Inicial' },
+  { id: 'pendingRecipientIdentify', description: 'Pendiente de identificación de destinatario' },
+  { id: 'recipientIdentified', description: 'Destinatario identificado' },
+  { id: 'pendingPayment', description: 'Pendiente de pago' },
+  { id: 'paymentProcessed', description: 'Pago realizado' },
+  { id: 'pendingCounterDelivery', description: 'Pendiente de retiro por mostrador' },
+  { id: 'pendingWarehouseDelivery', description: 'Pendiente de retiro por depósito' },
+  { id: 'onHold', description: 'En espera' },
+  { id: 'suspended', description: 'Suspendida' },
+]
+
 export default function useOperator({
   activityPanel,
   showConfirmModal,
@@ -13,10 +28,18 @@ export default function useOperator({
   loggingOut
 }) {
   const operatorGuides = ref([])
+  const totalGuides = ref(0)
+  const onlyMine = ref(false)
+  const filters = ref({ status: '', payment: '', sort: 'createdAt', order: 'asc' })
+  // cursors of the pages shown after the first one, the last is the current page
+  const pageCursors = ref([])
+  const nextCursor = ref('')
   const activeGuide = ref(null)
   const statusOptions = ref([])
   const loadingStatusOptions = ref(false)
   const pendingStatusChange = ref({ guideId: null, status: null, viaGuideId: null })
+  const syncingGuide = ref(false)
+  const availability = ref('offline')
   
 
   let operatorGuidesSource = null
@@ -36,13 +59,24 @@ export default function useOperator({
     }
   }
 
+  const operatorGuidesUri = () => {
+    const params = new URLSearchParams({ lang: 'es', limit: PAGE_SIZE, sort: filters.value.sort, order: filters.value.order })
+    if (onlyMine.value) params.set('mine', 'true')
+    if (filters.value.status) params.set('status', filters.value.status)
+    if (filters.value.payment) params.set('payment', filters.value.payment)
+    if (pageCursors.value.length > 0) params.set('cursor', pageCursors.value[pageCursors.value.length - 1])
+    return `/operator/guides?${params}`
+  }
+
   const fetchOperatorGuides = async () => {
-    operatorGuidesSource = new EventSource(`${apiSSEUrl}/operator/guides?lang=es`,  { withCredentials: true })
+    operatorGuidesSource = new EventSource(`${apiSSEUrl}${operatorGuidesUri()}`,  { withCredentials: true })
     operatorGuidesSource.onmessage = (event) => {
       try {
         const content = JSON.parse(event.data)
         const updatedGuides = content.data?.operatorGuides || []
         operatorGuides.value = updatedGuides
+        totalGuides.value = content.data?.total || 0
+        nextCursor.value = content.data?.nextCursor || ''
         if (activeGuide.value) {
           const stillExists = updatedGuides.find(g => g.guideId === activeGuide.value.guideId)
           if (stillExists && stillExists.operator.id != 0) {
@@ -64,7 +98,13 @@ export default function useOperator({
     }
 
     operatorGuidesSource.onerror = async (err) => {
-      let state = await verifyUnauthorizedOnSSE('/operator/guides?lang=es')
+      let state = await verifyUnauthorizedOnSSE(operatorGuidesUri())
+      // the SSE api renews an expired session by itself, the feed reconnects unless it is over
+      if (state !== 401) {
+        operatorGuidesSource.close()
+        setTimeout(fetchOperatorGuides, 3000)
+        return
+      }
       handleAuthRedirect(state)
       //console.error('SSE connection error:', err)
       //error.value = 'Error al conectarse al servidor'
@@ -73,6 +113,37 @@ export default function useOperator({
     }
   }
 
+  function restartOperatorGuides() {
+    operatorGuidesSource.close()
+    fetchOperatorGuides()
+  }
+
+  function toggleOnlyMine() {
+    onlyMine.value = !onlyMine.value
+    pageCursors.value = []
+    restartOperatorGuides()
+  }
+
+  function changeFilter(name, value) {
+    filters.value = { ...filters.value, [name]: value }
+    pageCursors.value = []
+    restartOperatorGuides()
+  }
+
+  function nextPage() {
+    if (!nextCursor.value) return
+    pageCursors.value = [...pageCursors.value, nextCursor.value]
+    restartOperatorGuides()
+  }
+
+  function previousPage() {
+    if (pageCursors.value.length === 0) return
+    pageCursors.value = pageCursors.value.slice(0, -1)
+    restartOperatorGuides()
+  }
+
+  const currentPage = computed(() => pageCursors.value.length + 1)
+
   async function loadStatusOptions(guideId) {
     loadingStatusOptions.value = true
     statusOptions.value = []
@@ -114,6 +185,20 @@ export default function useOperator({
         await loadStatusOptions(updated.guideId)
         scrollToActivity()
       }
+    } else {
+      error.value = response.content?.message
+      requestId.value = response.content?.requestId
+    }
+  }
+
+  async function releaseActiveGuide() {
+    if (!activeGuide.value) return
+    const response = await releaseGuide(activeGuide.value.guideId)
+    if (response.status === 200) {
+      activeGuide.value = null
+    } else {
+      error.value = response.content?.message
+      requestId.value = response.content?.requestId
     }
   }
 
@@ -144,6 +229,29 @@ export default function useOperator({
     statusChanging.value = false
   }
 
+  async function syncActiveGuide() {
+    if (!activeGuide.value) return
+    syncingGuide.value = true
+    const response = await syncGuide(activeGuide.value.guideId)
+    if (response.status === 200) {
+      activeGuide.value = { ...activeGuide.value, ...response.content.data }
+    } else {
+      error.value = response.content?.message
+      requestId.value = response.content?.requestId
+    }
+    syncingGuide.value = false
+  }
+
+  async function changeAvailability(newAvailability) {
+    const response = await setAvailability(newAvailability)
+    if (response.status === 200) {
+      availability.value = response.content.data.availability
+    } else {
+      error.value = response.content?.message
+      requestId.value = response.content?.requestId
+    }
+  }
+
   async function closeSuccessModal() {
     showSuccessModal.value = false
   }
@@ -174,6 +282,18 @@ export default function useOperator({
     loggingOut.value = false
   }
 
+  async function logoutEverywhere() {
+    loggingOut.value = true
+    const res = await doLogoutEverywhere()
+    if (res.status === 200) {
+      window.location.reload()
+    } else {
+      error.value = res.content.message
+      requestId.value = res.content.requestId
+    }
+    loggingOut.value = false
+  }
+
   onMounted(() => {
     fetchOperatorGuides()
   })
@@ -188,6 +308,16 @@ export default function useOperator({
 
   return {
     operatorGuides,
+    totalGuides,
+    onlyMine,
+    toggleOnlyMine,
+    filters,
+    changeFilter,
+    operatorStatusOptions,
+    nextCursor,
+    currentPage,
+    nextPage,
+    previousPage,
     activeGuide,
     selectGuide,
     openConfirmModal,
@@ -198,6 +328,12 @@ export default function useOperator({
     loadingStatusOptions,
     pendingStatusChange,
     elapsedTime,
-    logout
+    syncingGuide,
+    syncActiveGuide,
+    releaseActiveGuide,
+    availability,
+    changeAvailability,
+    logout,
+    logoutEverywhere
   }
 }
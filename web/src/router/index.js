@@ -2,12 +2,14 @@ import { createRouter, createWebHistory } from 'vue-router'
 import ClienteView from '../views/ClientView.vue'
 import OperadorView from '../views/OperatorView.vue'
 import MonitorView from '../views/MonitorView.vue'
+import DevicePairView from '../views/DevicePairView.vue'
 
 const routes = [
   { path: '/', redirect: '/client' },
   { path: '/client', component: ClienteView },
   { path: '/operator', component: OperadorView },
   { path: '/monitor', component: MonitorView },
+  { path: '/device/pair', component: DevicePairView },
 ]
 
 const router = createRouter({
@@ -6,6 +6,13 @@ export function handleAuthRedirect(status: number) {
   }
 }
 
+// a kiosk or monitor without a valid device token has to be paired again
+export function handleDeviceRedirect(content: { error?: { code: string } } | null) {
+  if (content?.error?.code === 'device_unauthorized') {
+    window.location.href = `${webUrl}/device/pair`
+  }
+}
+
 export function handleError(message: string) {
   return {
     status: 500,
@@ -54,6 +61,17 @@ export async function assignGuideToOperator(guideId) {
   }
 }
 
+export async function releaseGuide(guideId) {
+  try {
+    const res = await api.post(`/guide/${guideId}/release`, {}, axiosOptions)
+    handleAuthRedirect(res.status)
+    return { status: res.status, content: res.data }
+  } catch (err) {
+    console.error(err)
+    return handleError('Error al liberar la guía')
+  }
+}
+
 export async function getGuideStatusOptions(guideId) {
   try {
     const res = await api.get(`/guide/${guideId}/status-options`, axiosOptions)
@@ -64,6 +82,28 @@ export async function getGuideStatusOptions(guideId) {
   }
 }
 
+export async function syncGuide(guideId) {
+  try {
+    const res = await api.post(`/guide/${guideId}/sync`, {}, axiosOptions)
+    handleAuthRedirect(res.status)
+    return { status: res.status, content: res.data }
+  } catch (err) {
+    console.error(err)
+    return handleError('Error al actualizar los datos de la guía')
+  }
+}
+
+export async function setAvailability(availability) {
+  try {
+    const res = await api.put(`/operator/availability`, { availability }, axiosOptions)
+    handleAuthRedirect(res.status)
+    return { status: res.status, content: res.data }
+  } catch (err) {
+    console.error(err)
+    return handleError('Error al cambiar la disponibilidad')
+  }
+}
+
 export async function changeGuideStatus(guideId, newStatusId) {
   try {
     const res = await api.put(
@@ -87,4 +127,25 @@ export async function doLogout() {
       console.error('Logout failed:', err)
       handleError('Error al cerrar session')
     } 
-}
\ No newline at end of file
+}
+
+export async function doLogoutEverywhere() {
+    try {
+      const res = await api.delete('/operator/sessions', axiosOptions)
+      return { status: res.status, content: res.data }
+    } catch (err) {
+      console.error('Logout everywhere failed:', err)
+      return handleError('Error al cerrar las sesiones')
+    }
+}
+
+// pairDevice exchanges the code given by an admin for the device token, kept as a cookie
+export async function pairDevice(code: string) {
+  try {
+    const res = await api.post(`/device/pair`, { code }, axiosOptions)
+    return { status: res.status, content: res.data }
+  } catch (err) {
+    console.error(err)
+    return handleError('Error al conectarse al servidor.')
+  }
+}
@@ -23,4 +23,31 @@ export const api = axios.create({
 export const apiSSE = axios.create({
   baseURL: apiSSEUrl,
   withCredentials: true,
-})
\ No newline at end of file
+})
+let refreshing: Promise<boolean> | null = null
+
+// refreshSession renews the session once for every concurrent caller, a refresh token is only usable once
+export function refreshSession(): Promise<boolean> {
+  if (!refreshing) {
+    refreshing = api
+      .post('/auth/refresh', {}, axiosOptions)
+      .then((res) => res.status === 200)
+      .catch(() => false)
+      .finally(() => {
+        refreshing = null
+      })
+  }
+  return refreshing
+}
+
+// an expired access token is renewed with the refresh token cookie and the request retried once
+api.interceptors.response.use(async (res) => {
+  const config = res.config as typeof res.config & { retried?: boolean }
+  if (res.status !== 401 || config.retried || config.url === '/auth/refresh') {
+    return res
+  }
+  if (!(await refreshSession())) {
+    return res
+  }
+  return api.request({ ...config, retried: true } as typeof config)
+})